@@ -35,6 +35,7 @@ var (
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&CoschedulingArgs{},
+		&CapacitySchedulingArgs{},
 		&NodeResourcesAllocatableArgs{},
 		&TargetLoadPackingArgs{},
 		&LoadVariationRiskBalancingArgs{},
@@ -47,6 +48,15 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NetworkCostArgs{},//Amira
 		&SySchedArgs{},
 		&PeaksArgs{},
+		&ServiceMeshLatencyArgs{},
+		&CacheIsolationArgs{},
+		&CriticalReserveArgs{},
+		&DominantResourceFairnessArgs{},
+		&PodStateArgs{},
+		&DeadlineAwareArgs{},
+		&QueueLengthArgs{},
+		&ThermalAwareArgs{},
+		&NodeResourcesFragmentationArgs{},
 	)
 	return nil
 }
@@ -113,9 +113,12 @@ profiles:
 						{
 							Name: networkoverhead.Name,
 							Args: &config.NetworkOverheadArgs{
-								Namespaces:          []string{"networkAware"},
-								WeightsName:         "netCosts",
-								NetworkTopologyName: "net-topology-v1",
+								Namespaces:            []string{"networkAware"},
+								WeightsName:           "netCosts",
+								NetworkTopologyName:   "net-topology-v1",
+								ZoneViolationWeight:   1,
+								RegionViolationWeight: 1,
+								ScoringMode:           config.ScoringModeAccumulatedCost,
 							},
 						},
 						{//Amira
@@ -297,7 +300,8 @@ func TestCodecsEncodePluginConfig(t *testing.T) {
 											Address: "http://prometheus-k8s.monitoring.svc.cluster.local:9090",
 										},
 										WatcherAddress: "http://deadbeef:2020"},
-									TargetUtilization: 60,
+									TargetUtilization:    60,
+									GPUTargetUtilization: 70,
 									DefaultRequests: corev1.ResourceList{
 										corev1.ResourceCPU: testCPUQuantity,
 									},
@@ -316,6 +320,8 @@ func TestCodecsEncodePluginConfig(t *testing.T) {
 										WatcherAddress: "http://deadbeef:2020"},
 									SafeVarianceMargin:      v1.DefaultSafeVarianceMargin,
 									SafeVarianceSensitivity: v1.DefaultSafeVarianceSensitivity,
+									WarmUpSeconds:           v1.DefaultWarmUpSeconds,
+									WarmUpDiscount:          v1.DefaultWarmUpDiscount,
 								},
 							},
 							{
@@ -378,9 +384,15 @@ profiles:
 - pluginConfig:
   - args:
       apiVersion: kubescheduler.config.k8s.io/v1
+      checkpointTimeoutSeconds: 0
+      gangAdmissionWindowSeconds: 0
       kind: CoschedulingArgs
+      maxWaitingPodGroupsPerNamespace: 0
       permitWaitingTimeSeconds: 10
       podGroupBackoffSeconds: 0
+      retryPenaltySeconds: 0
+      scaleUpPermitExtensionSeconds: 0
+      waitingMembersStatusIntervalSeconds: 0
     name: Coscheduling
   - args:
       apiVersion: kubescheduler.config.k8s.io/v1
@@ -394,9 +406,12 @@ profiles:
     name: NodeResourcesAllocatable
   - args:
       apiVersion: kubescheduler.config.k8s.io/v1
+      clusterPressureHysteresis: 0
+      clusterPressureThreshold: 0
       defaultRequests:
         cpu: "1"
       defaultRequestsMultiplier: "1.8"
+      gpuTargetUtilization: 70
       kind: TargetLoadPackingArgs
       metricProvider:
         address: http://prometheus-k8s.monitoring.svc.cluster.local:9090
@@ -416,6 +431,8 @@ profiles:
         type: Prometheus
       safeVarianceMargin: 1
       safeVarianceSensitivity: 1
+      warmUpDiscount: 1
+      warmUpSeconds: 0
       watcherAddress: http://deadbeef:2020
     name: LoadVariationRiskBalancing
   - args:
@@ -430,6 +447,7 @@ profiles:
         cpu: 0.5
         memory: 0.5
       smoothingWindowSize: 5
+      useVPARecommendations: false
       watcherAddress: http://deadbeef:2020
     name: LowRiskOverCommitment
   - args:
@@ -440,11 +458,23 @@ profiles:
     name: TopologicalcnSort
   - args:
       apiVersion: kubescheduler.config.k8s.io/v1
+      clusterCostMultiplier: 0
+      clusterName: ""
+      costCap: 0
       kind: NetworkCostArgs
+      maxCost: 0
+      measuredCostWeight: 0
+      metricsProviderAddress: ""
+      metricsQuery: ""
+      metricsRefreshIntervalSeconds: 0
       namespaces:
       - default
       networkTopologyName: net-topology-v1
+      regionCostMultiplier: 0
+      sameHostnameCost: 0
+      sameZoneCost: 0
       weightsName: netCosts
+      zoneCostMultiplier: 0
     name: NetworkCostAware
   schedulerName: scheduler-plugins
 `,
@@ -32,6 +32,112 @@ type CoschedulingArgs struct {
 	PermitWaitingTimeSeconds int64
 	// PodGroupBackoffSeconds is the backoff time in seconds before a pod group can be scheduled again.
 	PodGroupBackoffSeconds int64
+	// GangAdmissionWindowSeconds is the time in seconds during which the capacity freed in the cluster is
+	// reserved to the highest-priority, then oldest, waiting pod group. Zero disables the reservation.
+	GangAdmissionWindowSeconds int64
+	// MaxWaitingPodGroupsPerNamespace is the maximum number of pod groups of a namespace having members
+	// waiting in Permit at the same time. Zero means no limit.
+	MaxWaitingPodGroupsPerNamespace int64
+	// FlushDeletedPodGroups watches the PodGroups to flush the state cached for them as soon as they are being
+	// deleted, instead of letting it expire. It relies on the finalizer added by the PodGroup controller.
+	FlushDeletedPodGroups bool
+	// WaitingMembersStatusIntervalSeconds is the interval in seconds at which the members waiting in Permit
+	// are reported in the status of their pod group. Zero disables the report.
+	WaitingMembersStatusIntervalSeconds int64
+	// GangPreemption makes a pod group that can't fit preempt lower-priority pod groups as a whole,
+	// respecting the PodDisruptionBudgets of their members.
+	GangPreemption bool
+	// CheckpointTimeoutSeconds is the time in seconds given to a checkpoint controller to acknowledge the checkpoint
+	// of the members of a pod group before they are preempted or their pod group is released. Zero disables the hooks.
+	CheckpointTimeoutSeconds int64
+	// CheckpointWebhookURL is the URL of the webhook called before a checkpoint, in addition to the annotation of the
+	// members. A successful response acknowledges the checkpoint. Empty doesn't call any webhook.
+	CheckpointWebhookURL string
+	// RetryPenaltySeconds is the time in seconds a pod group is delayed by in the scheduling queue, behind the pod
+	// groups of the same priority, for each of its recent failed attempts. Zero disables the penalty.
+	RetryPenaltySeconds int64
+	// ScaleUpPermitExtensionSeconds is the time in seconds the Permit wait of a pod group is extended by when it
+	// times out while the cluster autoscaler scales up for its members. Zero disables the extension.
+	ScaleUpPermitExtensionSeconds int64
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CapacitySchedulingArgs defines the parameters for CapacityScheduling plugin.
+type CapacitySchedulingArgs struct {
+	metav1.TypeMeta
+
+	// GPUSlicing accounts GPUs in GPU slices in quota usage, so that the min and max of
+	// ElasticQuotas can be expressed in GPU slices. Disabled if nil.
+	GPUSlicing *GPUSlicingSpec
+
+	// BorrowingQueue admits the pods borrowing capacity beyond the min of their ElasticQuota
+	// in FIFO order per ElasticQuota, with aging across ElasticQuotas. Disabled if nil.
+	BorrowingQueue *BorrowingQueueSpec
+
+	// PreemptionProtectionSeconds is the time during which the pods of an ElasticQuota which scaled up
+	// within its min are not preempted by the pods of other ElasticQuotas. Zero disables the protection.
+	PreemptionProtectionSeconds int64
+
+	// PreReclaim reclaims the capacity borrowed beyond the min of ElasticQuotas ahead of the demand
+	// forecast from the pending pods of the ElasticQuotas within their min. Disabled if nil.
+	PreReclaim *PreReclaimSpec
+
+	// BorrowingPolicy arbitrates between the ElasticQuotas competing to borrow the capacity beyond their min.
+	BorrowingPolicy BorrowingPolicyType
+
+	// PreemptionFreezeConfigMap is the "<namespace>/<name>" of the ConfigMap whose preemption-frozen annotation,
+	// set to "true", pauses the preemptions and the reclaims of the plugin, e.g. during maintenance or incident
+	// response. Disabled if empty.
+	PreemptionFreezeConfigMap string
+
+	// AnnotateQuotaUsage annotates the pods bound by the plugin with the accounting of their request in their
+	// ElasticQuota: within its min or borrowed, and the amounts accounted, e.g. for cost attribution.
+	AnnotateQuotaUsage bool
+}
+
+// BorrowingPolicyType is a "string" type.
+type BorrowingPolicyType string
+
+const (
+	// BorrowingFirstComeFirstServed lets the pods borrow in the order they are scheduled.
+	BorrowingFirstComeFirstServed BorrowingPolicyType = "FirstComeFirstServed"
+	// BorrowingDominantResourceFairness lets the ElasticQuota with the smallest dominant share of the borrowed
+	// capacity borrow first, following Dominant Resource Fairness.
+	BorrowingDominantResourceFairness BorrowingPolicyType = "DominantResourceFairness"
+)
+
+// PreReclaimSpec defines how the borrowed capacity is reclaimed ahead of the forecast demand.
+type PreReclaimSpec struct {
+	// LeadTimeSeconds is how far ahead the demand of ElasticQuotas is forecast from the growth of their pending pods.
+	LeadTimeSeconds int64
+	// IntervalSeconds is the period at which the demand is forecast and the borrowed capacity reclaimed.
+	IntervalSeconds int64
+	// MaxEvictionsPerInterval is the number of borrowing pods evicted at most per interval, so that the
+	// capacity is reclaimed gradually.
+	MaxEvictionsPerInterval int64
+}
+
+// BorrowingQueueSpec defines the order in which the pods of ElasticQuotas borrow capacity.
+type BorrowingQueueSpec struct {
+	// AgingSeconds is the time after which the oldest borrower of an ElasticQuota takes precedence
+	// over the borrowers of the other ElasticQuotas. Zero admits borrowers in global FIFO order.
+	AgingSeconds int64
+	// ExpirationSeconds is the time after which a borrower no longer attempted is dropped from the queue,
+	// so that a pod no longer schedulable doesn't block the borrowers queued after it.
+	ExpirationSeconds int64
+}
+
+// GPUSlicingSpec defines how whole GPUs, MIG profiles and time-sliced GPU replicas
+// are accounted in GPU slices.
+type GPUSlicingSpec struct {
+	// SlicesPerGPU is the number of slices of a whole GPU, e.g. the 7 MIG compute slices of an A100 or H100.
+	// A MIG profile <g>g.<memory> accounts for <g> slices.
+	SlicesPerGPU int64
+	// TimeSlicedResourceName is the resource name advertised for time-sliced GPU replicas.
+	TimeSlicedResourceName string
+	// SlicesPerTimeSlicedReplica is the number of slices of a time-sliced GPU replica.
+	SlicesPerTimeSlicedReplica int64
 }
 
 // ModeType is a "string" type.
@@ -42,6 +148,22 @@ const (
 	Least ModeType = "Least"
 	// Most is the string "Most".
 	Most ModeType = "Most"
+	// PodSlots is the string "PodSlots".
+	PodSlots ModeType = "PodSlots"
+	// Ratio is the string "Ratio".
+	Ratio ModeType = "Ratio"
+)
+
+// AggregationType is a "string" type.
+type AggregationType string
+
+const (
+	// WeightedSum is the string "WeightedSum".
+	WeightedSum AggregationType = "WeightedSum"
+	// MinRatio is the string "MinRatio".
+	MinRatio AggregationType = "MinRatio"
+	// Product is the string "Product".
+	Product AggregationType = "Product"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -57,8 +179,15 @@ type NodeResourcesAllocatableArgs struct {
 	// weight as 1 millicore.
 	Resources []schedconfig.ResourceSpec `json:"resources,omitempty"`
 
-	// Whether to prioritize nodes with least or most allocatable resources.
+	// Whether to prioritize nodes with least or most allocatable resources,
+	// nodes with the most pod slots remaining, regardless of Resources,
+	// or nodes with the largest ratios of Resources left once the pod is placed.
 	Mode ModeType `json:"mode,omitempty"`
+
+	// Aggregation of the ratios of Resources left in the Ratio mode: their
+	// weighted sum, their minimum regardless of the weights, or their weighted
+	// product. Ignored in the other modes.
+	Aggregation AggregationType `json:"aggregation,omitempty"`
 }
 
 // MetricProviderType is a "string" type.
@@ -68,6 +197,8 @@ const (
 	KubernetesMetricsServer MetricProviderType = "KubernetesMetricsServer"
 	Prometheus              MetricProviderType = "Prometheus"
 	SignalFx                MetricProviderType = "SignalFx"
+	// GRPC calls an external gRPC metrics service, at the address of the metric provider
+	GRPC MetricProviderType = "GRPC"
 )
 
 // Denote the spec of the metric provider
@@ -82,12 +213,42 @@ type MetricProviderSpec struct {
 	InsecureSkipVerify bool
 }
 
+// AutoTuneSpec holds parameters of the feedback loop comparing predicted and observed node
+// utilization to tune the coefficient of a trimaran plugin
+type AutoTuneSpec struct {
+	// Namespace of the ConfigMap persisting learned coefficients
+	ConfigMapNamespace string
+	// Name of the ConfigMap persisting learned coefficients
+	ConfigMapName string
+	// Interval in seconds between two coefficient adjustments
+	IntervalSeconds int64
+	// Fraction of the mean relative prediction error applied at each adjustment
+	LearningRate float64
+	// Maximum deviation in percent of a learned coefficient from its configured value
+	MaxAdjustmentPercent int64
+}
+
 // TrimaranSpec holds common parameters for trimaran plugins
 type TrimaranSpec struct {
 	// Metric Provider to use when using load watcher as a library
 	MetricProvider MetricProviderSpec
 	// Address of load watcher service
 	WatcherAddress string
+	// Addresses of replicas of the load watcher service, failed over in order after WatcherAddress
+	WatcherAddresses []string
+	// Auto-tuning of the plugin coefficient (TargetLoadPacking and LoadVariationRiskBalancing), disabled when nil
+	AutoTune *AutoTuneSpec
+	// Nodes given a neutral score, e.g. dedicated node pools managed by other policies, none when nil
+	ExcludedNodes *NodeExclusionSpec
+}
+
+// NodeExclusionSpec selects the nodes trimaran plugins don't score by utilization
+type NodeExclusionSpec struct {
+	// Label selector of the excluded nodes
+	NodeSelector *metav1.LabelSelector
+	// Taints of the excluded nodes: a node is excluded if it has a taint matching one of them by key,
+	// and by value and effect when set
+	Taints []v1.Taint
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -104,6 +265,14 @@ type TargetLoadPackingArgs struct {
 	DefaultRequestsMultiplier string
 	// Node target CPU Utilization for bin packing
 	TargetUtilization int64
+	// Node target GPU Utilization for bin packing the pods requesting GPUs
+	GPUTargetUtilization int64
+	// Cluster-wide average CPU Utilization from which the nodes are packed, the pods being spread below it,
+	// always packed when 0
+	ClusterPressureThreshold int64
+	// Points below ClusterPressureThreshold the cluster-wide average CPU Utilization must drop to before
+	// spreading the pods again
+	ClusterPressureHysteresis int64
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -118,6 +287,16 @@ type LoadVariationRiskBalancingArgs struct {
 	SafeVarianceMargin float64
 	// Root power of standard deviation in risk value
 	SafeVarianceSensitivity float64
+	// Duration in seconds of the warm-up phase of pods after they start
+	WarmUpSeconds int64
+	// Fraction [0,1] of the load of pods in warm-up discounted from the node statistics
+	WarmUpDiscount float64
+	// Multipliers of SafeVarianceMargin for the pods of namespaces, overridden by the risk multiplier annotation
+	// of the namespace
+	NamespaceRiskMultipliers map[string]float64
+	// Multipliers of SafeVarianceMargin for the pods of priority classes, for the pods of the namespaces
+	// without multiplier
+	PriorityClassRiskMultipliers map[string]float64
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -132,6 +311,9 @@ type LowRiskOverCommitmentArgs struct {
 	SmoothingWindowSize int64
 	// Resources fractional weight of risk due to limits specification [0,1]
 	RiskLimitWeights map[v1.ResourceName]float64
+	// Use the recommendations of the VerticalPodAutoscaler of the controller of a pod, when any,
+	// as the expected usage of the pod instead of its requests and limits
+	UseVPARecommendations bool
 }
 
 // ScoringStrategyType is a "string" type.
@@ -146,6 +328,9 @@ const (
 	LeastAllocated ScoringStrategyType = "LeastAllocated"
 	// LeastNUMANodes strategy favors nodes which requires least amount of NUMA nodes to satisfy resource requests for given pod
 	LeastNUMANodes ScoringStrategyType = "LeastNUMANodes"
+	// LeastNUMADistance strategy favors nodes which requires least amount of NUMA nodes to satisfy resource requests for given pod,
+	// then the NUMA nodes with the smallest distance between them, as reported in the costs of the topology zones
+	LeastNUMADistance ScoringStrategyType = "LeastNUMADistance"
 )
 
 // ScoringStrategy define ScoringStrategyType for node resource topology plugin
@@ -237,12 +422,31 @@ type NodeResourceTopologyMatchArgs struct {
 	DiscardReservedNodes bool
 	// Cache enables to fine tune the caching behavior
 	Cache *NodeResourceTopologyCache
+	// if set to true, when the pod doesn't fit the NUMA zones of any node, evaluate whether evicting
+	// lower-priority pods would free a single NUMA zone for it, and nominate the pod on that node
+	NUMAPreemption bool
+	// if set to true, prefer for the members of a gang the nodes where they get the same NUMA alignment, i.e. the
+	// same number of NUMA zones and the same resources from each, as the members with identical requests already placed
+	GangAlignment bool
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
-// PreemptionTolerationArgs reuses DefaultPluginArgs.
-type PreemptionTolerationArgs schedconfig.DefaultPreemptionArgs
+// PreemptionTolerationArgs holds arguments used to configure the PreemptionToleration plugin.
+type PreemptionTolerationArgs struct {
+	metav1.TypeMeta
+
+	// MinCandidateNodesPercentage is the minimum number of candidates to
+	// shortlist when dry running preemption as a percentage of number of nodes,
+	// as for DefaultPreemption.
+	MinCandidateNodesPercentage int32
+	// MinCandidateNodesAbsolute is the absolute minimum number of candidates to
+	// shortlist, as for DefaultPreemption.
+	MinCandidateNodesAbsolute int32
+	// PartialPreemption: if set to true, the pods opting in via annotation are shrunk with an in-place
+	// resize to make room for the preemptor, instead of being evicted, when this is enough
+	PartialPreemption bool
+}
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -266,8 +470,27 @@ type NetworkOverheadArgs struct {
 
 	// The NetworkTopology CRD name
 	NetworkTopologyName string
+
+	// Weight of a violated dependency between pods in different zones of the same region
+	ZoneViolationWeight int64
+
+	// Weight of a violated dependency between pods in different regions
+	RegionViolationWeight int64
+
+	// ScoringMode selects how the nodes are scored from the costs to the dependencies
+	ScoringMode NetworkOverheadScoringMode
 }
 
+// NetworkOverheadScoringMode is a "string" type.
+type NetworkOverheadScoringMode string
+
+const (
+	// ScoringModeAccumulatedCost scores the nodes by the costs accumulated to the scheduled replicas of the dependencies
+	ScoringModeAccumulatedCost NetworkOverheadScoringMode = "AccumulatedCost"
+	// ScoringModeCenterOfMass also prefers the nodes in the zone holding most of the weight of the scheduled dependencies
+	ScoringModeCenterOfMass NetworkOverheadScoringMode = "CenterOfMass"
+)
+
 //Amira
 type TopologicalcnSortArgs struct {
 	metav1.TypeMeta
@@ -286,8 +509,113 @@ type NetworkCostArgs struct {
 
 	// The NetworkTopology CRD name
 	NetworkTopologyName string
+
+	// Name of the local cluster in the cluster costs of the NetworkTopology,
+	// used for nodes without the cluster label
+	ClusterName string
+
+	// How the costs to the replicas of a dependency are accumulated
+	DependencyCostMode DependencyCostMode
+
+	// Accumulated cost above which the nodes score the same, 0 to disable capping
+	CostCap int64
+
+	// How the accumulated costs are scaled before normalization
+	CostScaling CostScalingMode
+
+	// Address of the Prometheus server measuring the links between the regions and between the zones,
+	// empty to only use the costs of the NetworkTopology CR
+	MetricsProviderAddress string
+
+	// Template of the PromQL query of the measured costs between the domains of {{.TopologyKey}},
+	// returning a vector of samples labeled with their origin and destination
+	MetricsQuery string
+
+	// Interval between two refreshes of the measured costs, in seconds
+	MetricsRefreshIntervalSeconds int64
+
+	// Weight in percent of the measured costs blended with the costs of the NetworkTopology CR
+	MeasuredCostWeight int64
+
+	// How the nodes are scored from the replicas of the dependencies: by network hops, by network costs,
+	// or by network costs weighted by the traffic expected with each dependency
+	ScoringMode NetworkCostScoringMode
+
+	// Cost to a replica of a dependency whose cost is not defined in the NetworkTopology
+	MaxCost int64
+
+	// Cost to a replica of a dependency in the same zone
+	SameZoneCost int64
+
+	// Cost to a replica of a dependency on the same host
+	SameHostnameCost int64
+
+	// Multipliers of the costs to the replicas of the dependencies in other zones of the same region,
+	// in other regions, and in other clusters
+	ZoneCostMultiplier    int64
+	RegionCostMultiplier  int64
+	ClusterCostMultiplier int64
+
+	// Namespaces of the pods the plugin applies to, all the namespaces if empty
+	AllowedNamespaces []string
+
+	// Namespaces of the pods the plugin doesn't apply to, scored equally on all the nodes
+	DeniedNamespaces []string
+
+	// How the nodes without zone and region labels are handled
+	UnlabeledNodePolicy UnlabeledNodePolicy
 }
 
+// DependencyCostMode is a "string" type.
+type DependencyCostMode string
+
+const (
+	// DependencyCostSum sums the costs to all the replicas of a dependency
+	DependencyCostSum DependencyCostMode = "Sum"
+	// DependencyCostNearestReplica only accounts the cost to the nearest replica of a dependency,
+	// matching how client-side load balancing routes traffic
+	DependencyCostNearestReplica DependencyCostMode = "NearestReplica"
+)
+
+// CostScalingMode is a "string" type.
+type CostScalingMode string
+
+const (
+	// CostScalingLinear normalizes the accumulated costs as they are
+	CostScalingLinear CostScalingMode = "Linear"
+	// CostScalingLogarithmic normalizes the logarithm of the accumulated costs, so that a few
+	// very high costs don't squash the differences between the other nodes
+	CostScalingLogarithmic CostScalingMode = "Logarithmic"
+)
+
+// NetworkCostScoringMode is a "string" type.
+type NetworkCostScoringMode string
+
+const (
+	// NetworkCostScoringHopCount accumulates the network hops to the replicas of the dependencies
+	NetworkCostScoringHopCount NetworkCostScoringMode = "HopCount"
+	// NetworkCostScoringLatency accumulates the network costs to the replicas of the dependencies
+	NetworkCostScoringLatency NetworkCostScoringMode = "Latency"
+	// NetworkCostScoringTrafficWeighted accumulates the network costs to the replicas of the dependencies
+	// multiplied by the traffic expected with each dependency, i.e. its minBandwidth in the AppGroup
+	NetworkCostScoringTrafficWeighted NetworkCostScoringMode = "TrafficWeighted"
+)
+
+// UnlabeledNodePolicy is a "string" type.
+type UnlabeledNodePolicy string
+
+const (
+	// UnlabeledNodeIgnore leaves the nodes without zone and region labels out of the network costs: they pass
+	// the filter and score the average cost of the labeled nodes, and their replicas are not accounted
+	UnlabeledNodeIgnore UnlabeledNodePolicy = "Ignore"
+	// UnlabeledNodePenalize accounts the nodes without zone and region labels with the maximum cost, and their
+	// replicas as violated dependencies
+	UnlabeledNodePenalize UnlabeledNodePolicy = "Penalize"
+	// UnlabeledNodeFilter filters out the nodes without zone and region labels, and accounts their replicas as
+	// with UnlabeledNodePenalize
+	UnlabeledNodeFilter UnlabeledNodePolicy = "Filter"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type SySchedArgs struct {
@@ -298,8 +626,35 @@ type SySchedArgs struct {
 
 	// CR name of the default profile for all system calls
 	DefaultProfileName string
+
+	// SimilarityMetric selects how the exposure of the pods to the system calls of the other pods of their node is computed
+	SimilarityMetric SySchedSimilarityMetric
+
+	// System calls weighted by CriticalSyscallWeight with the CriticalityWeighted similarity metric
+	CriticalSyscalls []string
+
+	// Weight of the critical system calls with the CriticalityWeighted similarity metric
+	CriticalSyscallWeight int64
+
+	// RuntimeProfiles also reads the seccomp profiles recorded at runtime for the pods by a SPO ProfileRecording
+	RuntimeProfiles bool
+
+	// Period in seconds of the refresh of the system calls of the pods with RuntimeProfiles
+	ProfileRefreshSeconds int64
 }
 
+// SySchedSimilarityMetric is a "string" type.
+type SySchedSimilarityMetric string
+
+const (
+	// SySchedExtraneousSyscalls counts the system calls of the node a pod doesn't use
+	SySchedExtraneousSyscalls SySchedSimilarityMetric = "ExtraneousSyscalls"
+	// SySchedJaccard measures the Jaccard distance between the system calls of a pod and of its node
+	SySchedJaccard SySchedSimilarityMetric = "Jaccard"
+	// SySchedCriticalityWeighted counts the system calls of the node a pod doesn't use, weighting the critical ones
+	SySchedCriticalityWeighted SySchedSimilarityMetric = "CriticalityWeighted"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // PeaksArgs holds arguments used to configure the Peaks plugin
@@ -309,6 +664,9 @@ type PeaksArgs struct {
 	// Address of load watcher service
 	WatcherAddress string
 	NodePowerModel map[string]PowerModel // Node power model where key is node name and value is power model
+	// PowerProfileConfigMap is the "<namespace>/<name>" ConfigMap of the power profiles learned per workload
+	// by the power profile controller, used instead of the power models when available. Disabled if empty.
+	PowerProfileConfigMap string
 }
 
 type PowerModel struct {
@@ -318,3 +676,149 @@ type PowerModel struct {
 	// Power = K0 + K1 * e ^(K2 * x) : where x is utilisation
 	// Idle power of node will be K0 + K1
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CriticalReserveArgs holds arguments used to configure the CriticalReserve plugin.
+type CriticalReserveArgs struct {
+	metav1.TypeMeta
+
+	// Capacity kept free on each node for pods of critical priority
+	ReservedResources v1.ResourceList
+
+	// Minimum priority of the pods allowed to consume the reserved capacity
+	MinCriticalPriority int32
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DominantResourceFairnessArgs holds arguments used to configure the DominantResourceFairness plugin.
+type DominantResourceFairnessArgs struct {
+	metav1.TypeMeta
+
+	// Label of the pods naming their tenant, the namespace of the pods when empty or missing
+	TenantLabel string
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PodStateArgs holds arguments used to configure the PodState plugin.
+type PodStateArgs struct {
+	metav1.TypeMeta
+
+	// Weight of the terminating pods of a node, favoring the node
+	TerminatingWeight int64
+
+	// Weight of the pods nominated to a node, penalizing the node
+	NominatedWeight int64
+
+	// Weight of the not-yet-ready pods of a node, penalizing the node during startup storms
+	NotReadyWeight int64
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeadlineAwareArgs holds arguments used to configure the DeadlineAware plugin.
+type DeadlineAwareArgs struct {
+	metav1.TypeMeta
+
+	// Time to deadline below which the pods of a Job are urgent, in seconds
+	UrgencyThresholdSeconds int64
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CacheIsolationArgs holds arguments used to configure the CacheIsolation plugin.
+type CacheIsolationArgs struct {
+	metav1.TypeMeta
+
+	// Label selector of the latency-sensitive pods, placed away from the cache-hungry pods
+	LatencySensitiveSelector *metav1.LabelSelector
+	// Label selector of the cache-hungry pods
+	CacheHungrySelector *metav1.LabelSelector
+	// Weight of the LLC occupancy of the nodes in their cache pressure
+	LLCOccupancyWeight int64
+	// Weight of the memory bandwidth utilization of the nodes in their cache pressure
+	MemoryBandwidthWeight int64
+	// Score penalty of each cache-hungry pod running on a node
+	CacheHungryPodPenalty int64
+	// Age above which the occupancy metrics of a node are ignored, in seconds
+	MetricsMaxAgeSeconds int64
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceMeshLatencyArgs holds arguments used to configure the ServiceMeshLatency plugin.
+type ServiceMeshLatencyArgs struct {
+	metav1.TypeMeta
+
+	// Namespaces of the AppGroup and NetworkTopology CRs
+	Namespaces []string
+	// Weights of the NetworkTopology CR taking precedence over the mesh telemetry when present
+	WeightsName string
+	// The NetworkTopology CR name
+	NetworkTopologyName string
+	// Service mesh reporting the latencies between workloads: Istio or Linkerd
+	MeshType ServiceMeshType
+	// Address of the Prometheus server scraping the mesh telemetry
+	PrometheusAddress string
+	// PromQL query of the p99 latency between workloads in milliseconds, the query of the mesh type when empty
+	LatencyQuery string
+	// Interval between two refreshes of the latencies, in seconds
+	MetricsUpdateIntervalSeconds int64
+}
+
+// ServiceMeshType is a "string" type.
+type ServiceMeshType string
+
+const (
+	// ServiceMeshIstio reads the istio_request_duration_milliseconds histogram reported by the client proxies
+	ServiceMeshIstio ServiceMeshType = "Istio"
+	// ServiceMeshLinkerd reads the response_latency_ms histogram of the outbound proxies
+	ServiceMeshLinkerd ServiceMeshType = "Linkerd"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QueueLengthArgs holds arguments used to configure the QueueLength plugin.
+type QueueLengthArgs struct {
+	metav1.TypeMeta
+
+	// Address of the Prometheus server scraping the backlog of the consumer groups, e.g. the KEDA metrics
+	PrometheusAddress string
+	// PromQL query of the backlog of the consumer groups, labeled with their namespace and consumer_group
+	BacklogQuery string
+	// Label of the pods holding the name of their consumer group, e.g. of their ScaledObject
+	ConsumerGroupLabel string
+	// Interval between two refreshes of the backlogs, in seconds
+	MetricsUpdateIntervalSeconds int64
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ThermalAwareArgs holds arguments used to configure the ThermalAware plugin.
+type ThermalAwareArgs struct {
+	metav1.TypeMeta
+
+	// Common parameters for trimaran plugins
+	TrimaranSpec
+	// Temperature in Celsius up to which a node is not penalized
+	SafeTemperature float64
+	// Temperature in Celsius from which a node gets the lowest score
+	MaxTemperature float64
+	// CPU requests in millicores from which a pod is CPU-heavy, the lighter pods are not steered away from hot nodes
+	CPUHeavyMilliCores int64
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeResourcesFragmentationArgs holds arguments used to configure the NodeResourcesFragmentation plugin.
+type NodeResourcesFragmentationArgs struct {
+	metav1.TypeMeta
+
+	// Resources whose fragmentation is scored, and their weights. Allowed weights start from 1.
+	Resources []schedconfig.ResourceSpec
+	// Shape of the fragmentation, from 0 to 10, of the largest slot left on a node, in percent of its allocatable
+	// resources. The resources left beside a slot of fragmentation 10 are all stranded.
+	Shape []schedconfig.UtilizationShapePoint
+}
@@ -30,8 +30,55 @@ var (
 	defaultPermitWaitingTimeSeconds int64 = 60
 	defaultPodGroupBackoffSeconds   int64 = 0
 
+	// defaultGangAdmissionWindowSeconds doesn't reserve the freed capacity to any pod group
+	defaultGangAdmissionWindowSeconds int64 = 0
+
+	// defaultMaxWaitingPodGroupsPerNamespace doesn't limit the pod groups waiting in Permit
+	defaultMaxWaitingPodGroupsPerNamespace int64 = 0
+
+	// defaultWaitingMembersStatusIntervalSeconds doesn't report the members waiting in Permit
+	defaultWaitingMembersStatusIntervalSeconds int64 = 0
+	// defaultCheckpointTimeoutSeconds disables the checkpoint hooks
+	defaultCheckpointTimeoutSeconds int64 = 0
+	// defaultRetryPenaltySeconds doesn't delay the pod groups failing to be scheduled
+	defaultRetryPenaltySeconds int64 = 0
+	// defaultScaleUpPermitExtensionSeconds doesn't extend the Permit wait of the pod groups
+	defaultScaleUpPermitExtensionSeconds int64 = 0
+
+	// Defaults for the GPU slicing of CapacityScheduling plugin
+
+	// DefaultGPUSlicesPerGPU is the number of MIG compute slices of an A100 or H100 GPU
+	DefaultGPUSlicesPerGPU int64 = 7
+	// DefaultGPUTimeSlicedResourceName is the resource renamed by the NVIDIA device plugin for time-sliced replicas
+	DefaultGPUTimeSlicedResourceName = "nvidia.com/gpu.shared"
+	// DefaultGPUSlicesPerTimeSlicedReplica accounts a time-sliced replica for a single slice
+	DefaultGPUSlicesPerTimeSlicedReplica int64 = 1
+
+	// defaultPreemptionProtectionSeconds doesn't protect the ElasticQuotas which scaled up from preemption
+	defaultPreemptionProtectionSeconds int64 = 0
+
+	// Defaults for the borrowing queue of CapacityScheduling plugin
+
+	// DefaultBorrowingAgingSeconds lets the oldest borrower of an ElasticQuota take precedence after a minute
+	DefaultBorrowingAgingSeconds int64 = 60
+	// DefaultBorrowingExpirationSeconds matches the maximum time a pod stays in the unschedulable queue
+	DefaultBorrowingExpirationSeconds int64 = 300
+	// DefaultBorrowingPolicy lets the pods borrow in the order they are scheduled
+	DefaultBorrowingPolicy = BorrowingFirstComeFirstServed
+
+	// Defaults for the pre-reclaim of CapacityScheduling plugin
+
+	// DefaultPreReclaimLeadTimeSeconds forecasts the demand of ElasticQuotas a minute ahead
+	DefaultPreReclaimLeadTimeSeconds int64 = 60
+	// DefaultPreReclaimIntervalSeconds forecasts the demand and reclaims the borrowed capacity every 10 seconds
+	DefaultPreReclaimIntervalSeconds int64 = 10
+	// DefaultPreReclaimMaxEvictionsPerInterval evicts a single borrowing pod per interval
+	DefaultPreReclaimMaxEvictionsPerInterval int64 = 1
+
 	defaultNodeResourcesAllocatableMode = Least
 
+	defaultNodeResourcesAllocatableAggregation = WeightedSum
+
 	// defaultResourcesToWeightMap is used to set the default resourceToWeight map for CPU and memory
 	// used by the NodeResourcesAllocatable scoring plugin.
 	// The base unit for CPU is millicore, while the base using for memory is a byte.
@@ -49,6 +96,12 @@ var (
 	DefaultRequestsMultiplier = "1.5"
 	// DefaultTargetUtilizationPercent Recommended to keep -10 than desired limit.
 	DefaultTargetUtilizationPercent int64 = 40
+	// DefaultGPUTargetUtilizationPercent packs GPUs higher than CPUs, their utilization being less bursty.
+	DefaultGPUTargetUtilizationPercent int64 = 60
+	// DefaultClusterPressureThresholdPercent always packs the nodes.
+	DefaultClusterPressureThresholdPercent int64 = 0
+	// DefaultClusterPressureHysteresisPercent keeps packing until the cluster utilization drops 10 points below the threshold.
+	DefaultClusterPressureHysteresisPercent int64 = 10
 
 	// Defaults for LoadVariationRiskBalancing plugin
 
@@ -61,6 +114,12 @@ var (
 	DefaultSafeVarianceMargin = 1.0
 	// DefaultSafeVarianceSensitivity is one
 	DefaultSafeVarianceSensitivity = 1.0
+	// The load of pods in warm-up, either started for less than WarmUpSeconds or annotated as warming up,
+	// is discounted from the node statistics by the fraction WarmUpDiscount.
+	// DefaultWarmUpSeconds is zero, only the annotated pods are in warm-up
+	DefaultWarmUpSeconds int64 = 0
+	// DefaultWarmUpDiscount is one, the load of pods in warm-up is fully discounted
+	DefaultWarmUpDiscount = 1.0
 
 	// Defaults for LowRiskOverCommitment plugin
 
@@ -75,6 +134,19 @@ var (
 		v1.ResourceMemory: DefaultRiskLimitWeight,
 	}
 
+	// Defaults for the auto-tuning of trimaran plugin coefficients
+
+	// DefaultAutoTuneConfigMapNamespace is the namespace of the ConfigMap persisting learned coefficients
+	DefaultAutoTuneConfigMapNamespace = "kube-system"
+	// DefaultAutoTuneConfigMapName is the name of the ConfigMap persisting learned coefficients
+	DefaultAutoTuneConfigMapName = "trimaran-autotune"
+	// DefaultAutoTuneIntervalSeconds is the interval between two coefficient adjustments
+	DefaultAutoTuneIntervalSeconds int64 = 300
+	// DefaultAutoTuneLearningRate keeps adjustments slow: 5% of the mean relative prediction error
+	DefaultAutoTuneLearningRate = 0.05
+	// DefaultAutoTuneMaxAdjustmentPercent bounds learned coefficients to +/-25% of their configured value
+	DefaultAutoTuneMaxAdjustmentPercent int64 = 25
+
 	// DefaultMetricProviderType is the Kubernetes metrics server
 	DefaultMetricProviderType = KubernetesMetricsServer
 	// DefaultInsecureSkipVerify is whether to skip the certificate verification
@@ -96,12 +168,116 @@ var (
 	DefaultWeightsName = "UserDefined"
 	// DefaultNetworkTopologyName contains the networkTopology CR name to be used by networkAware plugins
 	DefaultNetworkTopologyName = "nt-default"
+	// DefaultZoneViolationWeight weights a violated dependency across zones as a single violation
+	DefaultZoneViolationWeight int64 = 1
+	// DefaultRegionViolationWeight weights a violated dependency across regions as a single violation
+	DefaultRegionViolationWeight int64 = 1
+	// DefaultNetworkOverheadScoringMode scores the nodes by the accumulated costs
+	DefaultNetworkOverheadScoringMode = ScoringModeAccumulatedCost
+	// DefaultDependencyCostMode sums the costs to all the replicas of a dependency
+	DefaultDependencyCostMode = DependencyCostSum
+	// DefaultCostScaling normalizes the accumulated costs as they are
+	DefaultCostScaling = CostScalingLinear
+	// DefaultNetworkCostScoringMode scores the nodes by the network costs to the dependencies
+	DefaultNetworkCostScoringMode = NetworkCostScoringLatency
+	// DefaultUnlabeledNodePolicy accounts the nodes without zone and region labels with the maximum cost
+	DefaultUnlabeledNodePolicy = UnlabeledNodePenalize
+	// DefaultNetworkCostMetricsQuery reads the latencies measured between the regions and between the zones
+	DefaultNetworkCostMetricsQuery = `avg by (origin, destination) (network_link_latency_milliseconds{topology_key="{{.TopologyKey}}"})`
+	// DefaultNetworkCostMetricsRefreshIntervalSeconds refreshes the measured costs every 30 seconds
+	DefaultNetworkCostMetricsRefreshIntervalSeconds int64 = 30
+	// DefaultMeasuredCostWeight weighs the measured costs as much as the NetworkTopology ones
+	DefaultMeasuredCostWeight int64 = 50
+	// DefaultNetworkCostMaxCost is the cost to the replicas without cost in the NetworkTopology
+	DefaultNetworkCostMaxCost int64 = 100
+	// DefaultNetworkCostSameZoneCost is the cost to the replicas in the same zone
+	DefaultNetworkCostSameZoneCost int64 = 1
+	// DefaultNetworkCostSameHostnameCost is the cost to the replicas on the same host
+	DefaultNetworkCostSameHostnameCost int64 = 0
+	// DefaultNetworkCostMultiplier keeps the costs across zones, regions and clusters as they are
+	DefaultNetworkCostMultiplier int64 = 1
 
 	// Defaults for SySched
 	// DefaultSySchedProfileNamespace is the namesapce of the default syscall profile CR for SySched plugin
 	DefaultSySchedProfileNamespace = "default"
 	// DefaultSySchedProfileName is the name of the default syscall profile CR for SySched plugin
 	DefaultSySchedProfileName = "all-syscalls"
+	// DefaultSySchedSimilarityMetric counts the extraneous system calls
+	DefaultSySchedSimilarityMetric = SySchedExtraneousSyscalls
+	// DefaultSySchedCriticalSyscallWeight is the weight of the critical system calls
+	DefaultSySchedCriticalSyscallWeight int64 = 10
+	// DefaultSySchedProfileRefreshSeconds refreshes the system calls of the pods every minute
+	DefaultSySchedProfileRefreshSeconds int64 = 60
+
+	// Defaults for CriticalReserve
+	// DefaultCriticalReserveResources is the capacity kept free on each node for critical pods
+	DefaultCriticalReserveResources = v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("200m"),
+		v1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	// DefaultMinCriticalPriority is the priority of the system-cluster-critical priority class
+	DefaultMinCriticalPriority int32 = 2000000000
+
+	// Defaults for PodState
+	// DefaultTerminatingWeight counts each terminating pod once
+	DefaultTerminatingWeight int64 = 1
+	// DefaultNominatedWeight counts each nominated pod once
+	DefaultNominatedWeight int64 = 1
+	// DefaultNotReadyWeight is zero, the not-yet-ready pods are ignored
+	DefaultNotReadyWeight int64 = 0
+
+	// Defaults for DeadlineAware
+	// DefaultUrgencyThresholdSeconds makes the pods of a Job urgent 10 minutes before their deadline
+	DefaultUrgencyThresholdSeconds int64 = 600
+
+	// Defaults for CacheIsolation
+	// DefaultCacheProfileLabel is the label of the pods selecting their cache profile
+	DefaultCacheProfileLabel = "scheduling.x-k8s.io/cache-profile"
+	// DefaultLLCOccupancyWeight weighs the LLC occupancy as much as the memory bandwidth utilization
+	DefaultLLCOccupancyWeight int64 = 1
+	// DefaultMemoryBandwidthWeight weighs the memory bandwidth utilization as much as the LLC occupancy
+	DefaultMemoryBandwidthWeight int64 = 1
+	// DefaultCacheHungryPodPenalty lowers the score of a node by 20 per cache-hungry pod
+	DefaultCacheHungryPodPenalty int64 = 20
+	// DefaultMetricsMaxAgeSeconds ignores the occupancy metrics of a node not updated for 5 minutes
+	DefaultMetricsMaxAgeSeconds int64 = 300
+
+	// Defaults for ServiceMeshLatency
+	// DefaultServiceMeshType reads the latencies reported by Istio
+	DefaultServiceMeshType = ServiceMeshIstio
+	// DefaultMeshMetricsUpdateIntervalSeconds refreshes the latencies between workloads every 30 seconds
+	DefaultMeshMetricsUpdateIntervalSeconds int64 = 30
+
+	// Defaults for QueueLength
+	// DefaultBacklogQuery reads the metrics value of the KEDA scalers, e.g. the lag of a Kafka consumer group,
+	// by ScaledObject
+	DefaultBacklogQuery = `sum by (namespace, consumer_group) (label_replace(keda_scaler_metrics_value, "consumer_group", "$1", "scaledObject", "(.*)"))`
+	// DefaultConsumerGroupLabel is the label of the pods naming their consumer group
+	DefaultConsumerGroupLabel = "scheduling.x-k8s.io/consumer-group"
+	// DefaultBacklogUpdateIntervalSeconds refreshes the backlogs every 15 seconds
+	DefaultBacklogUpdateIntervalSeconds int64 = 15
+
+	// Defaults for ThermalAware
+	// DefaultSafeTemperature doesn't penalize the nodes up to 60 Celsius
+	DefaultSafeTemperature = 60.0
+	// DefaultMaxTemperature gives the lowest score to the nodes from 90 Celsius, close to the throttling of most CPUs
+	DefaultMaxTemperature = 90.0
+	// DefaultCPUHeavyMilliCores steers the pods requesting at least a core away from hot nodes
+	DefaultCPUHeavyMilliCores int64 = 1000
+
+	// Defaults for NodeResourcesFragmentation
+	// defaultFragmentationResources weighs the fragmentation of CPU and memory equally
+	defaultFragmentationResources = []schedulerconfigv1.ResourceSpec{
+		{Name: string(v1.ResourceCPU), Weight: 1},
+		{Name: string(v1.ResourceMemory), Weight: 1},
+	}
+	// defaultFragmentationShape strands the resources left beside a slot of up to 10% of the node, and none beside
+	// a slot from 30% of the node
+	defaultFragmentationShape = []schedulerconfigv1.UtilizationShapePoint{
+		{Utilization: 0, Score: 10},
+		{Utilization: 10, Score: 10},
+		{Utilization: 30, Score: 0},
+	}
 )
 
 // SetDefaults_CoschedulingArgs sets the default parameters for Coscheduling plugin.
@@ -112,6 +288,79 @@ func SetDefaults_CoschedulingArgs(obj *CoschedulingArgs) {
 	if obj.PodGroupBackoffSeconds == nil {
 		obj.PodGroupBackoffSeconds = &defaultPodGroupBackoffSeconds
 	}
+	if obj.GangAdmissionWindowSeconds == nil {
+		obj.GangAdmissionWindowSeconds = &defaultGangAdmissionWindowSeconds
+	}
+	if obj.MaxWaitingPodGroupsPerNamespace == nil {
+		obj.MaxWaitingPodGroupsPerNamespace = &defaultMaxWaitingPodGroupsPerNamespace
+	}
+	if obj.WaitingMembersStatusIntervalSeconds == nil {
+		obj.WaitingMembersStatusIntervalSeconds = &defaultWaitingMembersStatusIntervalSeconds
+	}
+	if obj.CheckpointTimeoutSeconds == nil {
+		obj.CheckpointTimeoutSeconds = &defaultCheckpointTimeoutSeconds
+	}
+	if obj.RetryPenaltySeconds == nil {
+		obj.RetryPenaltySeconds = &defaultRetryPenaltySeconds
+	}
+	if obj.ScaleUpPermitExtensionSeconds == nil {
+		obj.ScaleUpPermitExtensionSeconds = &defaultScaleUpPermitExtensionSeconds
+	}
+}
+
+// SetDefaults_CapacitySchedulingArgs sets the default parameters for CapacityScheduling plugin.
+func SetDefaults_CapacitySchedulingArgs(obj *CapacitySchedulingArgs) {
+	if obj.GPUSlicing != nil {
+		SetDefaultGPUSlicingSpec(obj.GPUSlicing)
+	}
+	if obj.BorrowingQueue != nil {
+		SetDefaultBorrowingQueueSpec(obj.BorrowingQueue)
+	}
+	if obj.PreemptionProtectionSeconds == nil {
+		obj.PreemptionProtectionSeconds = &defaultPreemptionProtectionSeconds
+	}
+	if obj.PreReclaim != nil {
+		SetDefaultPreReclaimSpec(obj.PreReclaim)
+	}
+	if obj.BorrowingPolicy == "" {
+		obj.BorrowingPolicy = DefaultBorrowingPolicy
+	}
+}
+
+// SetDefaultPreReclaimSpec sets the default parameters for the pre-reclaim of CapacityScheduling plugin.
+func SetDefaultPreReclaimSpec(spec *PreReclaimSpec) {
+	if spec.LeadTimeSeconds == nil || *spec.LeadTimeSeconds < 0 {
+		spec.LeadTimeSeconds = &DefaultPreReclaimLeadTimeSeconds
+	}
+	if spec.IntervalSeconds == nil || *spec.IntervalSeconds <= 0 {
+		spec.IntervalSeconds = &DefaultPreReclaimIntervalSeconds
+	}
+	if spec.MaxEvictionsPerInterval == nil || *spec.MaxEvictionsPerInterval <= 0 {
+		spec.MaxEvictionsPerInterval = &DefaultPreReclaimMaxEvictionsPerInterval
+	}
+}
+
+// SetDefaultBorrowingQueueSpec sets the default parameters for the borrowing queue of CapacityScheduling plugin.
+func SetDefaultBorrowingQueueSpec(spec *BorrowingQueueSpec) {
+	if spec.AgingSeconds == nil || *spec.AgingSeconds < 0 {
+		spec.AgingSeconds = &DefaultBorrowingAgingSeconds
+	}
+	if spec.ExpirationSeconds == nil || *spec.ExpirationSeconds <= 0 {
+		spec.ExpirationSeconds = &DefaultBorrowingExpirationSeconds
+	}
+}
+
+// SetDefaultGPUSlicingSpec sets the default parameters for the GPU slicing of CapacityScheduling plugin.
+func SetDefaultGPUSlicingSpec(spec *GPUSlicingSpec) {
+	if spec.SlicesPerGPU == nil {
+		spec.SlicesPerGPU = &DefaultGPUSlicesPerGPU
+	}
+	if spec.TimeSlicedResourceName == nil {
+		spec.TimeSlicedResourceName = &DefaultGPUTimeSlicedResourceName
+	}
+	if spec.SlicesPerTimeSlicedReplica == nil {
+		spec.SlicesPerTimeSlicedReplica = &DefaultGPUSlicesPerTimeSlicedReplica
+	}
 }
 
 // SetDefaults_NodeResourcesAllocatableArgs sets the defaults parameters for NodeResourceAllocatable.
@@ -123,16 +372,42 @@ func SetDefaults_NodeResourcesAllocatableArgs(obj *NodeResourcesAllocatableArgs)
 	if obj.Mode == "" {
 		obj.Mode = defaultNodeResourcesAllocatableMode
 	}
+
+	if obj.Mode == Ratio && obj.Aggregation == "" {
+		obj.Aggregation = defaultNodeResourcesAllocatableAggregation
+	}
 }
 
 // SetDefaultTrimaranSpec sets the default parameters for common Trimaran plugins
 func SetDefaultTrimaranSpec(args *TrimaranSpec) {
-	if args.WatcherAddress == nil && args.MetricProvider.Type == "" {
+	if args.WatcherAddress == nil && len(args.WatcherAddresses) == 0 && args.MetricProvider.Type == "" {
 		args.MetricProvider.Type = DefaultMetricProviderType
 	}
 	if args.MetricProvider.Type == Prometheus && args.MetricProvider.InsecureSkipVerify == nil {
 		args.MetricProvider.InsecureSkipVerify = &DefaultInsecureSkipVerify
 	}
+	if args.AutoTune != nil {
+		SetDefaultAutoTuneSpec(args.AutoTune)
+	}
+}
+
+// SetDefaultAutoTuneSpec sets the default parameters of an enabled auto-tuning feedback loop
+func SetDefaultAutoTuneSpec(args *AutoTuneSpec) {
+	if args.ConfigMapNamespace == nil {
+		args.ConfigMapNamespace = &DefaultAutoTuneConfigMapNamespace
+	}
+	if args.ConfigMapName == nil {
+		args.ConfigMapName = &DefaultAutoTuneConfigMapName
+	}
+	if args.IntervalSeconds == nil || *args.IntervalSeconds <= 0 {
+		args.IntervalSeconds = &DefaultAutoTuneIntervalSeconds
+	}
+	if args.LearningRate == nil || *args.LearningRate <= 0 {
+		args.LearningRate = &DefaultAutoTuneLearningRate
+	}
+	if args.MaxAdjustmentPercent == nil || *args.MaxAdjustmentPercent < 0 {
+		args.MaxAdjustmentPercent = &DefaultAutoTuneMaxAdjustmentPercent
+	}
 }
 
 // SetDefaults_TargetLoadPackingArgs sets the default parameters for TargetLoadPacking plugin
@@ -148,6 +423,15 @@ func SetDefaults_TargetLoadPackingArgs(args *TargetLoadPackingArgs) {
 	if args.TargetUtilization == nil || *args.TargetUtilization <= 0 {
 		args.TargetUtilization = &DefaultTargetUtilizationPercent
 	}
+	if args.GPUTargetUtilization == nil || *args.GPUTargetUtilization <= 0 {
+		args.GPUTargetUtilization = &DefaultGPUTargetUtilizationPercent
+	}
+	if args.ClusterPressureThreshold == nil {
+		args.ClusterPressureThreshold = &DefaultClusterPressureThresholdPercent
+	}
+	if args.ClusterPressureHysteresis == nil {
+		args.ClusterPressureHysteresis = &DefaultClusterPressureHysteresisPercent
+	}
 }
 
 // SetDefaults_LoadVariationRiskBalancingArgs sets the default parameters for LoadVariationRiskBalancing plugin
@@ -159,6 +443,12 @@ func SetDefaults_LoadVariationRiskBalancingArgs(args *LoadVariationRiskBalancing
 	if args.SafeVarianceSensitivity == nil || *args.SafeVarianceSensitivity < 0 {
 		args.SafeVarianceSensitivity = &DefaultSafeVarianceSensitivity
 	}
+	if args.WarmUpSeconds == nil || *args.WarmUpSeconds < 0 {
+		args.WarmUpSeconds = &DefaultWarmUpSeconds
+	}
+	if args.WarmUpDiscount == nil || *args.WarmUpDiscount < 0 || *args.WarmUpDiscount > 1 {
+		args.WarmUpDiscount = &DefaultWarmUpDiscount
+	}
 }
 
 // SetDefaults_LowRiskOverCommitmentArgs sets the default parameters for LowRiskOverCommitment plugin
@@ -215,7 +505,13 @@ func SetDefaults_NodeResourceTopologyMatchArgs(obj *NodeResourceTopologyMatchArg
 
 // SetDefaults_PreemptionTolerationArgs reuses SetDefaults_DefaultPreemptionArgs
 func SetDefaults_PreemptionTolerationArgs(obj *PreemptionTolerationArgs) {
-	k8sschedulerconfigv1.SetDefaults_DefaultPreemptionArgs((*schedulerconfigv1.DefaultPreemptionArgs)(obj))
+	args := schedulerconfigv1.DefaultPreemptionArgs{
+		MinCandidateNodesPercentage: obj.MinCandidateNodesPercentage,
+		MinCandidateNodesAbsolute:   obj.MinCandidateNodesAbsolute,
+	}
+	k8sschedulerconfigv1.SetDefaults_DefaultPreemptionArgs(&args)
+	obj.MinCandidateNodesPercentage = args.MinCandidateNodesPercentage
+	obj.MinCandidateNodesAbsolute = args.MinCandidateNodesAbsolute
 }
 
 // SetDefaults_TopologicalSortArgs sets the default parameters for TopologicalSortArgs plugin.
@@ -239,6 +535,18 @@ func SetDefaults_NetworkOverheadArgs(obj *NetworkOverheadArgs) {
 	if obj.NetworkTopologyName == nil {
 		obj.NetworkTopologyName = &DefaultNetworkTopologyName
 	}
+
+	if obj.ZoneViolationWeight == nil || *obj.ZoneViolationWeight < 1 {
+		obj.ZoneViolationWeight = &DefaultZoneViolationWeight
+	}
+
+	if obj.RegionViolationWeight == nil || *obj.RegionViolationWeight < 1 {
+		obj.RegionViolationWeight = &DefaultRegionViolationWeight
+	}
+
+	if obj.ScoringMode == "" {
+		obj.ScoringMode = DefaultNetworkOverheadScoringMode
+	}
 }
 //Amira
 // SetDefaults_TopologicalSortArgs sets the default parameters for TopologicalSortArgs plugin.
@@ -260,6 +568,59 @@ func SetDefaults_NetworkCostArgs(obj *NetworkCostArgs) {
 	if obj.NetworkTopologyName == nil {
 		obj.NetworkTopologyName = &DefaultNetworkTopologyName
 	}
+
+	if obj.DependencyCostMode == "" {
+		obj.DependencyCostMode = DefaultDependencyCostMode
+	}
+
+	if obj.CostScaling == "" {
+		obj.CostScaling = DefaultCostScaling
+	}
+
+	if obj.ScoringMode == "" {
+		obj.ScoringMode = DefaultNetworkCostScoringMode
+	}
+
+	if obj.UnlabeledNodePolicy == "" {
+		obj.UnlabeledNodePolicy = DefaultUnlabeledNodePolicy
+	}
+
+	if obj.MaxCost == nil || *obj.MaxCost < 1 {
+		obj.MaxCost = &DefaultNetworkCostMaxCost
+	}
+
+	if obj.SameZoneCost == nil || *obj.SameZoneCost < 0 {
+		obj.SameZoneCost = &DefaultNetworkCostSameZoneCost
+	}
+
+	if obj.SameHostnameCost == nil || *obj.SameHostnameCost < 0 {
+		obj.SameHostnameCost = &DefaultNetworkCostSameHostnameCost
+	}
+
+	if obj.ZoneCostMultiplier == nil || *obj.ZoneCostMultiplier < 1 {
+		obj.ZoneCostMultiplier = &DefaultNetworkCostMultiplier
+	}
+
+	if obj.RegionCostMultiplier == nil || *obj.RegionCostMultiplier < 1 {
+		obj.RegionCostMultiplier = &DefaultNetworkCostMultiplier
+	}
+
+	if obj.ClusterCostMultiplier == nil || *obj.ClusterCostMultiplier < 1 {
+		obj.ClusterCostMultiplier = &DefaultNetworkCostMultiplier
+	}
+
+	// The measured costs are only used with a metrics provider
+	if obj.MetricsProviderAddress != nil && *obj.MetricsProviderAddress != "" {
+		if obj.MetricsQuery == nil {
+			obj.MetricsQuery = &DefaultNetworkCostMetricsQuery
+		}
+		if obj.MetricsRefreshIntervalSeconds == nil {
+			obj.MetricsRefreshIntervalSeconds = &DefaultNetworkCostMetricsRefreshIntervalSeconds
+		}
+		if obj.MeasuredCostWeight == nil {
+			obj.MeasuredCostWeight = &DefaultMeasuredCostWeight
+		}
+	}
 }
 
 
@@ -273,4 +634,133 @@ func SetDefaults_SySchedArgs(obj *SySchedArgs) {
 	if obj.DefaultProfileName == nil {
 		obj.DefaultProfileName = &DefaultSySchedProfileName
 	}
+
+	if obj.SimilarityMetric == "" {
+		obj.SimilarityMetric = DefaultSySchedSimilarityMetric
+	}
+
+	if obj.CriticalSyscallWeight == nil {
+		obj.CriticalSyscallWeight = &DefaultSySchedCriticalSyscallWeight
+	}
+
+	if obj.ProfileRefreshSeconds == nil || *obj.ProfileRefreshSeconds <= 0 {
+		obj.ProfileRefreshSeconds = &DefaultSySchedProfileRefreshSeconds
+	}
+}
+
+// SetDefaults_CriticalReserveArgs sets the default parameters for CriticalReserve plugin.
+func SetDefaults_CriticalReserveArgs(obj *CriticalReserveArgs) {
+	if len(obj.ReservedResources) == 0 {
+		obj.ReservedResources = DefaultCriticalReserveResources.DeepCopy()
+	}
+
+	if obj.MinCriticalPriority == nil {
+		obj.MinCriticalPriority = &DefaultMinCriticalPriority
+	}
+}
+
+// SetDefaults_PodStateArgs sets the default parameters for PodState plugin.
+func SetDefaults_PodStateArgs(obj *PodStateArgs) {
+	if obj.TerminatingWeight == nil || *obj.TerminatingWeight < 0 {
+		obj.TerminatingWeight = &DefaultTerminatingWeight
+	}
+
+	if obj.NominatedWeight == nil || *obj.NominatedWeight < 0 {
+		obj.NominatedWeight = &DefaultNominatedWeight
+	}
+
+	if obj.NotReadyWeight == nil || *obj.NotReadyWeight < 0 {
+		obj.NotReadyWeight = &DefaultNotReadyWeight
+	}
+}
+
+// SetDefaults_DeadlineAwareArgs sets the default parameters for DeadlineAware plugin.
+func SetDefaults_DeadlineAwareArgs(obj *DeadlineAwareArgs) {
+	if obj.UrgencyThresholdSeconds == nil || *obj.UrgencyThresholdSeconds <= 0 {
+		obj.UrgencyThresholdSeconds = &DefaultUrgencyThresholdSeconds
+	}
+}
+
+// SetDefaults_CacheIsolationArgs sets the default parameters for CacheIsolation plugin.
+func SetDefaults_CacheIsolationArgs(obj *CacheIsolationArgs) {
+	if obj.LatencySensitiveSelector == nil {
+		obj.LatencySensitiveSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{DefaultCacheProfileLabel: "latency-sensitive"},
+		}
+	}
+	if obj.CacheHungrySelector == nil {
+		obj.CacheHungrySelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{DefaultCacheProfileLabel: "cache-hungry"},
+		}
+	}
+	if obj.LLCOccupancyWeight == nil || *obj.LLCOccupancyWeight < 0 {
+		obj.LLCOccupancyWeight = &DefaultLLCOccupancyWeight
+	}
+	if obj.MemoryBandwidthWeight == nil || *obj.MemoryBandwidthWeight < 0 {
+		obj.MemoryBandwidthWeight = &DefaultMemoryBandwidthWeight
+	}
+	if obj.CacheHungryPodPenalty == nil || *obj.CacheHungryPodPenalty < 0 {
+		obj.CacheHungryPodPenalty = &DefaultCacheHungryPodPenalty
+	}
+	if obj.MetricsMaxAgeSeconds == nil || *obj.MetricsMaxAgeSeconds <= 0 {
+		obj.MetricsMaxAgeSeconds = &DefaultMetricsMaxAgeSeconds
+	}
+}
+
+// SetDefaults_ServiceMeshLatencyArgs sets the default parameters for ServiceMeshLatency plugin.
+func SetDefaults_ServiceMeshLatencyArgs(obj *ServiceMeshLatencyArgs) {
+	if len(obj.Namespaces) == 0 {
+		obj.Namespaces = []string{metav1.NamespaceDefault}
+	}
+	if obj.WeightsName == nil {
+		obj.WeightsName = &DefaultWeightsName
+	}
+	if obj.NetworkTopologyName == nil {
+		obj.NetworkTopologyName = &DefaultNetworkTopologyName
+	}
+	if obj.MeshType == "" {
+		obj.MeshType = DefaultServiceMeshType
+	}
+	if obj.MetricsUpdateIntervalSeconds == nil || *obj.MetricsUpdateIntervalSeconds <= 0 {
+		obj.MetricsUpdateIntervalSeconds = &DefaultMeshMetricsUpdateIntervalSeconds
+	}
+}
+
+// SetDefaults_QueueLengthArgs sets the default parameters for QueueLength plugin.
+func SetDefaults_QueueLengthArgs(obj *QueueLengthArgs) {
+	if obj.BacklogQuery == nil || *obj.BacklogQuery == "" {
+		obj.BacklogQuery = &DefaultBacklogQuery
+	}
+	if obj.ConsumerGroupLabel == nil || *obj.ConsumerGroupLabel == "" {
+		obj.ConsumerGroupLabel = &DefaultConsumerGroupLabel
+	}
+	if obj.MetricsUpdateIntervalSeconds == nil || *obj.MetricsUpdateIntervalSeconds <= 0 {
+		obj.MetricsUpdateIntervalSeconds = &DefaultBacklogUpdateIntervalSeconds
+	}
+}
+
+// SetDefaults_ThermalAwareArgs sets the default parameters for ThermalAware plugin.
+func SetDefaults_ThermalAwareArgs(args *ThermalAwareArgs) {
+	SetDefaultTrimaranSpec(&args.TrimaranSpec)
+	if args.SafeTemperature == nil {
+		args.SafeTemperature = &DefaultSafeTemperature
+	}
+	if args.MaxTemperature == nil || *args.MaxTemperature <= *args.SafeTemperature {
+		// keep the default range above the safe temperature
+		maxTemperature := *args.SafeTemperature + DefaultMaxTemperature - DefaultSafeTemperature
+		args.MaxTemperature = &maxTemperature
+	}
+	if args.CPUHeavyMilliCores == nil || *args.CPUHeavyMilliCores < 0 {
+		args.CPUHeavyMilliCores = &DefaultCPUHeavyMilliCores
+	}
+}
+
+// SetDefaults_NodeResourcesFragmentationArgs sets the default parameters for NodeResourcesFragmentation plugin.
+func SetDefaults_NodeResourcesFragmentationArgs(obj *NodeResourcesFragmentationArgs) {
+	if len(obj.Resources) == 0 {
+		obj.Resources = defaultFragmentationResources
+	}
+	if len(obj.Shape) == 0 {
+		obj.Shape = defaultFragmentationShape
+	}
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	schedulerconfigv1 "k8s.io/kube-scheduler/config/v1"
@@ -39,19 +40,105 @@ func TestSchedulingDefaults(t *testing.T) {
 			name:   "empty config CoschedulingArgs",
 			config: &CoschedulingArgs{},
 			expect: &CoschedulingArgs{
-				PermitWaitingTimeSeconds: pointer.Int64Ptr(60),
-				PodGroupBackoffSeconds:   pointer.Int64Ptr(0),
+				PermitWaitingTimeSeconds:            pointer.Int64Ptr(60),
+				PodGroupBackoffSeconds:              pointer.Int64Ptr(0),
+				GangAdmissionWindowSeconds:          pointer.Int64Ptr(0),
+				MaxWaitingPodGroupsPerNamespace:     pointer.Int64Ptr(0),
+				WaitingMembersStatusIntervalSeconds: pointer.Int64Ptr(0),
+				CheckpointTimeoutSeconds:            pointer.Int64Ptr(0),
+				RetryPenaltySeconds:                 pointer.Int64Ptr(0),
+				ScaleUpPermitExtensionSeconds:       pointer.Int64Ptr(0),
 			},
 		},
 		{
 			name: "set non default CoschedulingArgs",
 			config: &CoschedulingArgs{
-				PermitWaitingTimeSeconds: pointer.Int64Ptr(60),
-				PodGroupBackoffSeconds:   pointer.Int64Ptr(20),
+				PermitWaitingTimeSeconds:            pointer.Int64Ptr(60),
+				PodGroupBackoffSeconds:              pointer.Int64Ptr(20),
+				GangAdmissionWindowSeconds:          pointer.Int64Ptr(30),
+				MaxWaitingPodGroupsPerNamespace:     pointer.Int64Ptr(4),
+				WaitingMembersStatusIntervalSeconds: pointer.Int64Ptr(10),
+				CheckpointTimeoutSeconds:            pointer.Int64Ptr(300),
+				RetryPenaltySeconds:                 pointer.Int64Ptr(30),
+				ScaleUpPermitExtensionSeconds:       pointer.Int64Ptr(120),
 			},
 			expect: &CoschedulingArgs{
-				PermitWaitingTimeSeconds: pointer.Int64Ptr(60),
-				PodGroupBackoffSeconds:   pointer.Int64Ptr(20),
+				PermitWaitingTimeSeconds:            pointer.Int64Ptr(60),
+				PodGroupBackoffSeconds:              pointer.Int64Ptr(20),
+				GangAdmissionWindowSeconds:          pointer.Int64Ptr(30),
+				MaxWaitingPodGroupsPerNamespace:     pointer.Int64Ptr(4),
+				WaitingMembersStatusIntervalSeconds: pointer.Int64Ptr(10),
+				CheckpointTimeoutSeconds:            pointer.Int64Ptr(300),
+				RetryPenaltySeconds:                 pointer.Int64Ptr(30),
+				ScaleUpPermitExtensionSeconds:       pointer.Int64Ptr(120),
+			},
+		},
+		{
+			name:   "empty config CapacitySchedulingArgs",
+			config: &CapacitySchedulingArgs{},
+			expect: &CapacitySchedulingArgs{
+				PreemptionProtectionSeconds: pointer.Int64Ptr(0),
+				BorrowingPolicy:             BorrowingFirstComeFirstServed,
+			},
+		},
+		{
+			name: "GPU slicing CapacitySchedulingArgs",
+			config: &CapacitySchedulingArgs{
+				GPUSlicing: &GPUSlicingSpec{
+					SlicesPerGPU: pointer.Int64Ptr(8),
+				},
+			},
+			expect: &CapacitySchedulingArgs{
+				GPUSlicing: &GPUSlicingSpec{
+					SlicesPerGPU:               pointer.Int64Ptr(8),
+					TimeSlicedResourceName:     pointer.String("nvidia.com/gpu.shared"),
+					SlicesPerTimeSlicedReplica: pointer.Int64Ptr(1),
+				},
+				PreemptionProtectionSeconds: pointer.Int64Ptr(0),
+				BorrowingPolicy:             BorrowingFirstComeFirstServed,
+			},
+		},
+		{
+			name: "borrowing queue CapacitySchedulingArgs",
+			config: &CapacitySchedulingArgs{
+				BorrowingQueue: &BorrowingQueueSpec{
+					AgingSeconds: pointer.Int64Ptr(0),
+				},
+			},
+			expect: &CapacitySchedulingArgs{
+				BorrowingQueue: &BorrowingQueueSpec{
+					AgingSeconds:      pointer.Int64Ptr(0),
+					ExpirationSeconds: pointer.Int64Ptr(300),
+				},
+				PreemptionProtectionSeconds: pointer.Int64Ptr(0),
+				BorrowingPolicy:             BorrowingFirstComeFirstServed,
+			},
+		},
+		{
+			name: "preemption protection CapacitySchedulingArgs",
+			config: &CapacitySchedulingArgs{
+				PreemptionProtectionSeconds: pointer.Int64Ptr(120),
+			},
+			expect: &CapacitySchedulingArgs{
+				PreemptionProtectionSeconds: pointer.Int64Ptr(120),
+				BorrowingPolicy:             BorrowingFirstComeFirstServed,
+			},
+		},
+		{
+			name: "pre-reclaim CapacitySchedulingArgs",
+			config: &CapacitySchedulingArgs{
+				PreReclaim: &PreReclaimSpec{
+					LeadTimeSeconds: pointer.Int64Ptr(300),
+				},
+			},
+			expect: &CapacitySchedulingArgs{
+				PreemptionProtectionSeconds: pointer.Int64Ptr(0),
+				PreReclaim: &PreReclaimSpec{
+					LeadTimeSeconds:         pointer.Int64Ptr(300),
+					IntervalSeconds:         pointer.Int64Ptr(10),
+					MaxEvictionsPerInterval: pointer.Int64Ptr(1),
+				},
+				BorrowingPolicy: BorrowingFirstComeFirstServed,
 			},
 		},
 		{
@@ -79,6 +166,53 @@ func TestSchedulingDefaults(t *testing.T) {
 				Mode: Most,
 			},
 		},
+		{
+			name: "NodeResourcesAllocatableArgs in Ratio mode",
+			config: &NodeResourcesAllocatableArgs{
+				Resources: []schedulerconfigv1.ResourceSpec{
+					{Name: "cpu", Weight: 1}, {Name: "nvidia.com/gpu", Weight: 2},
+				},
+				Mode: Ratio,
+			},
+			expect: &NodeResourcesAllocatableArgs{
+				Resources: []schedulerconfigv1.ResourceSpec{
+					{Name: "cpu", Weight: 1}, {Name: "nvidia.com/gpu", Weight: 2},
+				},
+				Mode:        Ratio,
+				Aggregation: WeightedSum,
+			},
+		},
+		{
+			name:   "empty config NodeResourcesFragmentationArgs",
+			config: &NodeResourcesFragmentationArgs{},
+			expect: &NodeResourcesFragmentationArgs{
+				Resources: []schedulerconfigv1.ResourceSpec{
+					{Name: "cpu", Weight: 1}, {Name: "memory", Weight: 1},
+				},
+				Shape: []schedulerconfigv1.UtilizationShapePoint{
+					{Utilization: 0, Score: 10}, {Utilization: 10, Score: 10}, {Utilization: 30, Score: 0},
+				},
+			},
+		},
+		{
+			name: "set non default NodeResourcesFragmentationArgs",
+			config: &NodeResourcesFragmentationArgs{
+				Resources: []schedulerconfigv1.ResourceSpec{
+					{Name: "cpu", Weight: 2}, {Name: "nvidia.com/gpu", Weight: 1},
+				},
+				Shape: []schedulerconfigv1.UtilizationShapePoint{
+					{Utilization: 0, Score: 10}, {Utilization: 50, Score: 0},
+				},
+			},
+			expect: &NodeResourcesFragmentationArgs{
+				Resources: []schedulerconfigv1.ResourceSpec{
+					{Name: "cpu", Weight: 2}, {Name: "nvidia.com/gpu", Weight: 1},
+				},
+				Shape: []schedulerconfigv1.UtilizationShapePoint{
+					{Utilization: 0, Score: 10}, {Utilization: 50, Score: 0},
+				},
+			},
+		},
 		{
 			name:   "empty config TargetLoadPackingArgs",
 			config: &TargetLoadPackingArgs{},
@@ -91,6 +225,9 @@ func TestSchedulingDefaults(t *testing.T) {
 					strconv.FormatInt(DefaultRequestsMilliCores, 10) + "m")},
 				DefaultRequestsMultiplier: pointer.StringPtr("1.5"),
 				TargetUtilization:         pointer.Int64Ptr(40),
+				GPUTargetUtilization:      pointer.Int64Ptr(60),
+				ClusterPressureThreshold:  pointer.Int64Ptr(0),
+				ClusterPressureHysteresis: pointer.Int64Ptr(10),
 			},
 		},
 		{
@@ -101,6 +238,9 @@ func TestSchedulingDefaults(t *testing.T) {
 				DefaultRequests:           v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
 				DefaultRequestsMultiplier: pointer.StringPtr("2.5"),
 				TargetUtilization:         pointer.Int64Ptr(50),
+				GPUTargetUtilization:      pointer.Int64Ptr(70),
+				ClusterPressureThreshold:  pointer.Int64Ptr(60),
+				ClusterPressureHysteresis: pointer.Int64Ptr(5),
 			},
 			expect: &TargetLoadPackingArgs{
 				TrimaranSpec: TrimaranSpec{
@@ -108,6 +248,86 @@ func TestSchedulingDefaults(t *testing.T) {
 				DefaultRequests:           v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
 				DefaultRequestsMultiplier: pointer.StringPtr("2.5"),
 				TargetUtilization:         pointer.Int64Ptr(50),
+				GPUTargetUtilization:      pointer.Int64Ptr(70),
+				ClusterPressureThreshold:  pointer.Int64Ptr(60),
+				ClusterPressureHysteresis: pointer.Int64Ptr(5),
+			},
+		},
+		{
+			name: "replicated load watcher TargetLoadPackingArgs",
+			config: &TargetLoadPackingArgs{
+				TrimaranSpec: TrimaranSpec{
+					WatcherAddresses: []string{"http://load-watcher-0:2020", "http://load-watcher-1:2020"}},
+			},
+			expect: &TargetLoadPackingArgs{
+				TrimaranSpec: TrimaranSpec{
+					WatcherAddresses: []string{"http://load-watcher-0:2020", "http://load-watcher-1:2020"}},
+				DefaultRequests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(
+					strconv.FormatInt(DefaultRequestsMilliCores, 10) + "m")},
+				DefaultRequestsMultiplier: pointer.StringPtr("1.5"),
+				TargetUtilization:         pointer.Int64Ptr(40),
+				GPUTargetUtilization:      pointer.Int64Ptr(60),
+				ClusterPressureThreshold:  pointer.Int64Ptr(0),
+				ClusterPressureHysteresis: pointer.Int64Ptr(10),
+			},
+		},
+		{
+			name: "enabled auto-tuning TargetLoadPackingArgs",
+			config: &TargetLoadPackingArgs{
+				TrimaranSpec: TrimaranSpec{
+					WatcherAddress: pointer.StringPtr("http://localhost:2020"),
+					AutoTune:       &AutoTuneSpec{},
+				},
+			},
+			expect: &TargetLoadPackingArgs{
+				TrimaranSpec: TrimaranSpec{
+					WatcherAddress: pointer.StringPtr("http://localhost:2020"),
+					AutoTune: &AutoTuneSpec{
+						ConfigMapNamespace:   pointer.StringPtr("kube-system"),
+						ConfigMapName:        pointer.StringPtr("trimaran-autotune"),
+						IntervalSeconds:      pointer.Int64Ptr(300),
+						LearningRate:         pointer.Float64Ptr(0.05),
+						MaxAdjustmentPercent: pointer.Int64Ptr(25),
+					},
+				},
+				DefaultRequests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(
+					strconv.FormatInt(DefaultRequestsMilliCores, 10) + "m")},
+				DefaultRequestsMultiplier: pointer.StringPtr("1.5"),
+				TargetUtilization:         pointer.Int64Ptr(40),
+				GPUTargetUtilization:      pointer.Int64Ptr(60),
+				ClusterPressureThreshold:  pointer.Int64Ptr(0),
+				ClusterPressureHysteresis: pointer.Int64Ptr(10),
+			},
+		},
+		{
+			name: "set non default auto-tuning LoadVariationRiskBalancingArgs",
+			config: &LoadVariationRiskBalancingArgs{
+				TrimaranSpec: TrimaranSpec{
+					WatcherAddress: pointer.StringPtr("http://localhost:2020"),
+					AutoTune: &AutoTuneSpec{
+						ConfigMapNamespace:   pointer.StringPtr("scheduler-plugins"),
+						ConfigMapName:        pointer.StringPtr("lvrb-coefficients"),
+						IntervalSeconds:      pointer.Int64Ptr(60),
+						LearningRate:         pointer.Float64Ptr(0.2),
+						MaxAdjustmentPercent: pointer.Int64Ptr(50),
+					},
+				},
+			},
+			expect: &LoadVariationRiskBalancingArgs{
+				TrimaranSpec: TrimaranSpec{
+					WatcherAddress: pointer.StringPtr("http://localhost:2020"),
+					AutoTune: &AutoTuneSpec{
+						ConfigMapNamespace:   pointer.StringPtr("scheduler-plugins"),
+						ConfigMapName:        pointer.StringPtr("lvrb-coefficients"),
+						IntervalSeconds:      pointer.Int64Ptr(60),
+						LearningRate:         pointer.Float64Ptr(0.2),
+						MaxAdjustmentPercent: pointer.Int64Ptr(50),
+					},
+				},
+				SafeVarianceMargin:      pointer.Float64Ptr(1.0),
+				SafeVarianceSensitivity: pointer.Float64Ptr(1.0),
+				WarmUpSeconds:           pointer.Int64Ptr(0),
+				WarmUpDiscount:          pointer.Float64Ptr(1.0),
 			},
 		},
 		{
@@ -120,21 +340,31 @@ func TestSchedulingDefaults(t *testing.T) {
 					}},
 				SafeVarianceMargin:      pointer.Float64Ptr(1.0),
 				SafeVarianceSensitivity: pointer.Float64Ptr(1.0),
+				WarmUpSeconds:           pointer.Int64Ptr(0),
+				WarmUpDiscount:          pointer.Float64Ptr(1.0),
 			},
 		},
 		{
 			name: "set non default LoadVariationRiskBalancingArgs",
 			config: &LoadVariationRiskBalancingArgs{
-				SafeVarianceMargin:      pointer.Float64Ptr(2.0),
-				SafeVarianceSensitivity: pointer.Float64Ptr(2.0),
+				SafeVarianceMargin:           pointer.Float64Ptr(2.0),
+				SafeVarianceSensitivity:      pointer.Float64Ptr(2.0),
+				WarmUpSeconds:                pointer.Int64Ptr(300),
+				WarmUpDiscount:               pointer.Float64Ptr(0.5),
+				NamespaceRiskMultipliers:     map[string]float64{"payments": 2},
+				PriorityClassRiskMultipliers: map[string]float64{"batch": 0.5},
 			},
 			expect: &LoadVariationRiskBalancingArgs{
 				TrimaranSpec: TrimaranSpec{
 					MetricProvider: MetricProviderSpec{
 						Type: "KubernetesMetricsServer",
 					}},
-				SafeVarianceMargin:      pointer.Float64Ptr(2.0),
-				SafeVarianceSensitivity: pointer.Float64Ptr(2.0),
+				SafeVarianceMargin:           pointer.Float64Ptr(2.0),
+				SafeVarianceSensitivity:      pointer.Float64Ptr(2.0),
+				WarmUpSeconds:                pointer.Int64Ptr(300),
+				WarmUpDiscount:               pointer.Float64Ptr(0.5),
+				NamespaceRiskMultipliers:     map[string]float64{"payments": 2},
+				PriorityClassRiskMultipliers: map[string]float64{"batch": 0.5},
 			},
 		},
 		{
@@ -238,43 +468,125 @@ func TestSchedulingDefaults(t *testing.T) {
 			config: &NetworkOverheadArgs{},
 			expect: &NetworkOverheadArgs{
 				Namespaces:          []string{"default"},
-				WeightsName:         pointer.StringPtr("UserDefined"),
-				NetworkTopologyName: pointer.StringPtr("nt-default"),
+				WeightsName:           pointer.StringPtr("UserDefined"),
+				NetworkTopologyName:   pointer.StringPtr("nt-default"),
+				ZoneViolationWeight:   pointer.Int64Ptr(1),
+				RegionViolationWeight: pointer.Int64Ptr(1),
+				ScoringMode:           ScoringModeAccumulatedCost,
 			},
 		},
 		{
 			name: "set non default TopologySortArgs",
 			config: &NetworkOverheadArgs{
-				Namespaces:          []string{"n2"},
-				WeightsName:         pointer.StringPtr("latency"),
-				NetworkTopologyName: pointer.StringPtr("nt-latency-costs"),
+				Namespaces:            []string{"n2"},
+				WeightsName:           pointer.StringPtr("latency"),
+				NetworkTopologyName:   pointer.StringPtr("nt-latency-costs"),
+				ZoneViolationWeight:   pointer.Int64Ptr(2),
+				RegionViolationWeight: pointer.Int64Ptr(5),
+				ScoringMode:           ScoringModeCenterOfMass,
 			},
 			expect: &NetworkOverheadArgs{
-				Namespaces:          []string{"n2"},
-				WeightsName:         pointer.StringPtr("latency"),
-				NetworkTopologyName: pointer.StringPtr("nt-latency-costs"),
+				Namespaces:            []string{"n2"},
+				WeightsName:           pointer.StringPtr("latency"),
+				NetworkTopologyName:   pointer.StringPtr("nt-latency-costs"),
+				ZoneViolationWeight:   pointer.Int64Ptr(2),
+				RegionViolationWeight: pointer.Int64Ptr(5),
+				ScoringMode:           ScoringModeCenterOfMass,
+			},
+		},
+		{
+			name: "invalid violation weights NetworkOverheadArgs",
+			config: &NetworkOverheadArgs{
+				ZoneViolationWeight:   pointer.Int64Ptr(0),
+				RegionViolationWeight: pointer.Int64Ptr(-3),
+			},
+			expect: &NetworkOverheadArgs{
+				Namespaces:            []string{"default"},
+				WeightsName:           pointer.StringPtr("UserDefined"),
+				NetworkTopologyName:   pointer.StringPtr("nt-default"),
+				ZoneViolationWeight:   pointer.Int64Ptr(1),
+				RegionViolationWeight: pointer.Int64Ptr(1),
+				ScoringMode:           ScoringModeAccumulatedCost,
 			},
 		},//Amira
 		{
 			name:   "empty config Network Cost Args",
 			config: &NetworkCostArgs{},
 			expect: &NetworkCostArgs{
-				Namespaces:          []string{"default"},
-				WeightsName:         pointer.StringPtr("UserDefined"),
-				NetworkTopologyName: pointer.StringPtr("nt-default"),
+				Namespaces:            []string{"default"},
+				WeightsName:           pointer.StringPtr("UserDefined"),
+				NetworkTopologyName:   pointer.StringPtr("nt-default"),
+				DependencyCostMode:    DependencyCostSum,
+				CostScaling:           CostScalingLinear,
+				ScoringMode:           NetworkCostScoringLatency,
+				UnlabeledNodePolicy:   UnlabeledNodePenalize,
+				MaxCost:               pointer.Int64(100),
+				SameZoneCost:          pointer.Int64(1),
+				SameHostnameCost:      pointer.Int64(0),
+				ZoneCostMultiplier:    pointer.Int64(1),
+				RegionCostMultiplier:  pointer.Int64(1),
+				ClusterCostMultiplier: pointer.Int64(1),
 			},
 		},
 		{
 			name: "set non default TopologySortArgs",
 			config: &NetworkCostArgs{
-				Namespaces:          []string{"nc2"},
-				WeightsName:         pointer.StringPtr("latency"),
-				NetworkTopologyName: pointer.StringPtr("ntc-latency-costs"),
+				Namespaces:            []string{"nc2"},
+				WeightsName:           pointer.StringPtr("latency"),
+				NetworkTopologyName:   pointer.StringPtr("ntc-latency-costs"),
+				DependencyCostMode:    DependencyCostNearestReplica,
+				CostCap:               pointer.Int64(1000),
+				CostScaling:           CostScalingLogarithmic,
+				ScoringMode:           NetworkCostScoringTrafficWeighted,
+				MaxCost:               pointer.Int64(500),
+				SameZoneCost:          pointer.Int64(2),
+				SameHostnameCost:      pointer.Int64(1),
+				ZoneCostMultiplier:    pointer.Int64(2),
+				RegionCostMultiplier:  pointer.Int64(10),
+				ClusterCostMultiplier: pointer.Int64(20),
+				UnlabeledNodePolicy:   UnlabeledNodeIgnore,
+			},
+			expect: &NetworkCostArgs{
+				Namespaces:            []string{"nc2"},
+				WeightsName:           pointer.StringPtr("latency"),
+				NetworkTopologyName:   pointer.StringPtr("ntc-latency-costs"),
+				DependencyCostMode:    DependencyCostNearestReplica,
+				CostCap:               pointer.Int64(1000),
+				CostScaling:           CostScalingLogarithmic,
+				ScoringMode:           NetworkCostScoringTrafficWeighted,
+				MaxCost:               pointer.Int64(500),
+				SameZoneCost:          pointer.Int64(2),
+				SameHostnameCost:      pointer.Int64(1),
+				ZoneCostMultiplier:    pointer.Int64(2),
+				RegionCostMultiplier:  pointer.Int64(10),
+				ClusterCostMultiplier: pointer.Int64(20),
+				UnlabeledNodePolicy:   UnlabeledNodeIgnore,
+			},
+		},
+		{
+			name: "Network Cost Args with a metrics provider",
+			config: &NetworkCostArgs{
+				MetricsProviderAddress: pointer.String("http://prometheus:9090"),
+				MeasuredCostWeight:     pointer.Int64(100),
 			},
 			expect: &NetworkCostArgs{
-				Namespaces:          []string{"nc2"},
-				WeightsName:         pointer.StringPtr("latency"),
-				NetworkTopologyName: pointer.StringPtr("ntc-latency-costs"),
+				Namespaces:                    []string{"default"},
+				WeightsName:                   pointer.StringPtr("UserDefined"),
+				NetworkTopologyName:           pointer.StringPtr("nt-default"),
+				DependencyCostMode:            DependencyCostSum,
+				CostScaling:                   CostScalingLinear,
+				ScoringMode:                   NetworkCostScoringLatency,
+				UnlabeledNodePolicy:           UnlabeledNodePenalize,
+				MaxCost:                       pointer.Int64(100),
+				SameZoneCost:                  pointer.Int64(1),
+				SameHostnameCost:              pointer.Int64(0),
+				ZoneCostMultiplier:            pointer.Int64(1),
+				RegionCostMultiplier:          pointer.Int64(1),
+				ClusterCostMultiplier:         pointer.Int64(1),
+				MetricsProviderAddress:        pointer.String("http://prometheus:9090"),
+				MetricsQuery:                  pointer.String(DefaultNetworkCostMetricsQuery),
+				MetricsRefreshIntervalSeconds: pointer.Int64(30),
+				MeasuredCostWeight:            pointer.Int64(100),
 			},
 		},//------
 		{
@@ -283,6 +595,9 @@ func TestSchedulingDefaults(t *testing.T) {
 			expect: &SySchedArgs{
 				DefaultProfileNamespace: pointer.StringPtr("default"),
 				DefaultProfileName:      pointer.StringPtr("all-syscalls"),
+				SimilarityMetric:        SySchedExtraneousSyscalls,
+				CriticalSyscallWeight:   pointer.Int64Ptr(10),
+				ProfileRefreshSeconds:   pointer.Int64Ptr(60),
 			},
 		},
 		{
@@ -290,10 +605,224 @@ func TestSchedulingDefaults(t *testing.T) {
 			config: &SySchedArgs{
 				DefaultProfileNamespace: pointer.StringPtr("default"),
 				DefaultProfileName:      pointer.StringPtr("all-syscalls"),
+				SimilarityMetric:        SySchedCriticalityWeighted,
+				CriticalSyscalls:        []string{"ptrace", "mount"},
+				CriticalSyscallWeight:   pointer.Int64Ptr(5),
+				RuntimeProfiles:         true,
+				ProfileRefreshSeconds:   pointer.Int64Ptr(30),
 			},
 			expect: &SySchedArgs{
 				DefaultProfileNamespace: pointer.StringPtr("default"),
 				DefaultProfileName:      pointer.StringPtr("all-syscalls"),
+				SimilarityMetric:        SySchedCriticalityWeighted,
+				CriticalSyscalls:        []string{"ptrace", "mount"},
+				CriticalSyscallWeight:   pointer.Int64Ptr(5),
+				RuntimeProfiles:         true,
+				ProfileRefreshSeconds:   pointer.Int64Ptr(30),
+			},
+		},
+		{
+			name:   "empty config CriticalReserveArgs",
+			config: &CriticalReserveArgs{},
+			expect: &CriticalReserveArgs{
+				ReservedResources: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("200m"),
+					v1.ResourceMemory: resource.MustParse("256Mi"),
+				},
+				MinCriticalPriority: pointer.Int32(2000000000),
+			},
+		},
+		{
+			name: "set non default CriticalReserveArgs",
+			config: &CriticalReserveArgs{
+				ReservedResources:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+				MinCriticalPriority: pointer.Int32(1000),
+			},
+			expect: &CriticalReserveArgs{
+				ReservedResources:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+				MinCriticalPriority: pointer.Int32(1000),
+			},
+		},
+		{
+			name:   "empty config PodStateArgs",
+			config: &PodStateArgs{},
+			expect: &PodStateArgs{
+				TerminatingWeight: pointer.Int64(1),
+				NominatedWeight:   pointer.Int64(1),
+				NotReadyWeight:    pointer.Int64(0),
+			},
+		},
+		{
+			name: "set non default PodStateArgs",
+			config: &PodStateArgs{
+				TerminatingWeight: pointer.Int64(2),
+				NominatedWeight:   pointer.Int64(-1),
+				NotReadyWeight:    pointer.Int64(3),
+			},
+			expect: &PodStateArgs{
+				TerminatingWeight: pointer.Int64(2),
+				NominatedWeight:   pointer.Int64(1),
+				NotReadyWeight:    pointer.Int64(3),
+			},
+		},
+		{
+			name:   "empty config DeadlineAwareArgs",
+			config: &DeadlineAwareArgs{},
+			expect: &DeadlineAwareArgs{
+				UrgencyThresholdSeconds: pointer.Int64(600),
+			},
+		},
+		{
+			name: "set non default DeadlineAwareArgs",
+			config: &DeadlineAwareArgs{
+				UrgencyThresholdSeconds: pointer.Int64(60),
+			},
+			expect: &DeadlineAwareArgs{
+				UrgencyThresholdSeconds: pointer.Int64(60),
+			},
+		},
+		{
+			name:   "empty config QueueLengthArgs",
+			config: &QueueLengthArgs{},
+			expect: &QueueLengthArgs{
+				BacklogQuery:                 pointer.String(DefaultBacklogQuery),
+				ConsumerGroupLabel:           pointer.String("scheduling.x-k8s.io/consumer-group"),
+				MetricsUpdateIntervalSeconds: pointer.Int64(15),
+			},
+		},
+		{
+			name: "set non default QueueLengthArgs",
+			config: &QueueLengthArgs{
+				PrometheusAddress:            pointer.String("http://prometheus:9090"),
+				BacklogQuery:                 pointer.String(`sum by (namespace, consumer_group) (kafka_consumergroup_lag)`),
+				ConsumerGroupLabel:           pointer.String("app"),
+				MetricsUpdateIntervalSeconds: pointer.Int64(5),
+			},
+			expect: &QueueLengthArgs{
+				PrometheusAddress:            pointer.String("http://prometheus:9090"),
+				BacklogQuery:                 pointer.String(`sum by (namespace, consumer_group) (kafka_consumergroup_lag)`),
+				ConsumerGroupLabel:           pointer.String("app"),
+				MetricsUpdateIntervalSeconds: pointer.Int64(5),
+			},
+		},
+		{
+			name:   "empty config ThermalAwareArgs",
+			config: &ThermalAwareArgs{},
+			expect: &ThermalAwareArgs{
+				TrimaranSpec: TrimaranSpec{
+					MetricProvider: MetricProviderSpec{
+						Type: "KubernetesMetricsServer",
+					}},
+				SafeTemperature:    pointer.Float64(60),
+				MaxTemperature:     pointer.Float64(90),
+				CPUHeavyMilliCores: pointer.Int64(1000),
+			},
+		},
+		{
+			name: "set non default ThermalAwareArgs",
+			config: &ThermalAwareArgs{
+				SafeTemperature:    pointer.Float64(50),
+				MaxTemperature:     pointer.Float64(85),
+				CPUHeavyMilliCores: pointer.Int64(0),
+			},
+			expect: &ThermalAwareArgs{
+				TrimaranSpec: TrimaranSpec{
+					MetricProvider: MetricProviderSpec{
+						Type: "KubernetesMetricsServer",
+					}},
+				SafeTemperature:    pointer.Float64(50),
+				MaxTemperature:     pointer.Float64(85),
+				CPUHeavyMilliCores: pointer.Int64(0),
+			},
+		},
+		{
+			name: "set max temperature below safe temperature ThermalAwareArgs",
+			config: &ThermalAwareArgs{
+				SafeTemperature:    pointer.Float64(70),
+				MaxTemperature:     pointer.Float64(65),
+				CPUHeavyMilliCores: pointer.Int64(-1),
+			},
+			expect: &ThermalAwareArgs{
+				TrimaranSpec: TrimaranSpec{
+					MetricProvider: MetricProviderSpec{
+						Type: "KubernetesMetricsServer",
+					}},
+				SafeTemperature:    pointer.Float64(70),
+				MaxTemperature:     pointer.Float64(100),
+				CPUHeavyMilliCores: pointer.Int64(1000),
+			},
+		},
+		{
+			name:   "empty config CacheIsolationArgs",
+			config: &CacheIsolationArgs{},
+			expect: &CacheIsolationArgs{
+				LatencySensitiveSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"scheduling.x-k8s.io/cache-profile": "latency-sensitive"},
+				},
+				CacheHungrySelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"scheduling.x-k8s.io/cache-profile": "cache-hungry"},
+				},
+				LLCOccupancyWeight:    pointer.Int64(1),
+				MemoryBandwidthWeight: pointer.Int64(1),
+				CacheHungryPodPenalty: pointer.Int64(20),
+				MetricsMaxAgeSeconds:  pointer.Int64(300),
+			},
+		},
+		{
+			name: "set non default CacheIsolationArgs",
+			config: &CacheIsolationArgs{
+				LatencySensitiveSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "frontend"},
+				},
+				CacheHungrySelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "batch"},
+				},
+				LLCOccupancyWeight:    pointer.Int64(2),
+				MemoryBandwidthWeight: pointer.Int64(0),
+				CacheHungryPodPenalty: pointer.Int64(10),
+				MetricsMaxAgeSeconds:  pointer.Int64(60),
+			},
+			expect: &CacheIsolationArgs{
+				LatencySensitiveSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "frontend"},
+				},
+				CacheHungrySelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "batch"},
+				},
+				LLCOccupancyWeight:    pointer.Int64(2),
+				MemoryBandwidthWeight: pointer.Int64(0),
+				CacheHungryPodPenalty: pointer.Int64(10),
+				MetricsMaxAgeSeconds:  pointer.Int64(60),
+			},
+		},
+		{
+			name:   "empty config ServiceMeshLatencyArgs",
+			config: &ServiceMeshLatencyArgs{},
+			expect: &ServiceMeshLatencyArgs{
+				Namespaces:                   []string{"default"},
+				WeightsName:                  pointer.String("UserDefined"),
+				NetworkTopologyName:          pointer.String("nt-default"),
+				MeshType:                     ServiceMeshIstio,
+				MetricsUpdateIntervalSeconds: pointer.Int64(30),
+			},
+		},
+		{
+			name: "set non default ServiceMeshLatencyArgs",
+			config: &ServiceMeshLatencyArgs{
+				Namespaces:                   []string{"mesh"},
+				WeightsName:                  pointer.String("netperf"),
+				NetworkTopologyName:          pointer.String("nt-mesh"),
+				MeshType:                     ServiceMeshLinkerd,
+				PrometheusAddress:            pointer.String("http://prometheus.linkerd-viz:9090"),
+				MetricsUpdateIntervalSeconds: pointer.Int64(60),
+			},
+			expect: &ServiceMeshLatencyArgs{
+				Namespaces:                   []string{"mesh"},
+				WeightsName:                  pointer.String("netperf"),
+				NetworkTopologyName:          pointer.String("nt-mesh"),
+				MeshType:                     ServiceMeshLinkerd,
+				PrometheusAddress:            pointer.String("http://prometheus.linkerd-viz:9090"),
+				MetricsUpdateIntervalSeconds: pointer.Int64(60),
 			},
 		},
 	}
@@ -54,6 +54,7 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 
 	types := []runtime.Object{
         &CoschedulingArgs{},
+        &CapacitySchedulingArgs{},
         &NodeResourcesAllocatableArgs{},
         &TargetLoadPackingArgs{},
         &LoadVariationRiskBalancingArgs{},
@@ -66,6 +67,15 @@ func addKnownTypes(scheme *runtime.Scheme) error {
         &TopologicalcnSortArgs{}, // Amira
         &SySchedArgs{},
         &PeaksArgs{},
+        &ServiceMeshLatencyArgs{},
+        &CacheIsolationArgs{},
+        &CriticalReserveArgs{},
+        &DominantResourceFairnessArgs{},
+        &PodStateArgs{},
+        &DeadlineAwareArgs{},
+        &QueueLengthArgs{},
+        &ThermalAwareArgs{},
+        &NodeResourcesFragmentationArgs{},
     }
 
     for _, t := range types {
@@ -41,6 +41,46 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*AutoTuneSpec)(nil), (*config.AutoTuneSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_AutoTuneSpec_To_config_AutoTuneSpec(a.(*AutoTuneSpec), b.(*config.AutoTuneSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.AutoTuneSpec)(nil), (*AutoTuneSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_AutoTuneSpec_To_v1_AutoTuneSpec(a.(*config.AutoTuneSpec), b.(*AutoTuneSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BorrowingQueueSpec)(nil), (*config.BorrowingQueueSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BorrowingQueueSpec_To_config_BorrowingQueueSpec(a.(*BorrowingQueueSpec), b.(*config.BorrowingQueueSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.BorrowingQueueSpec)(nil), (*BorrowingQueueSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_BorrowingQueueSpec_To_v1_BorrowingQueueSpec(a.(*config.BorrowingQueueSpec), b.(*BorrowingQueueSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CacheIsolationArgs)(nil), (*config.CacheIsolationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CacheIsolationArgs_To_config_CacheIsolationArgs(a.(*CacheIsolationArgs), b.(*config.CacheIsolationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CacheIsolationArgs)(nil), (*CacheIsolationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CacheIsolationArgs_To_v1_CacheIsolationArgs(a.(*config.CacheIsolationArgs), b.(*CacheIsolationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CapacitySchedulingArgs)(nil), (*config.CapacitySchedulingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CapacitySchedulingArgs_To_config_CapacitySchedulingArgs(a.(*CapacitySchedulingArgs), b.(*config.CapacitySchedulingArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CapacitySchedulingArgs)(nil), (*CapacitySchedulingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CapacitySchedulingArgs_To_v1_CapacitySchedulingArgs(a.(*config.CapacitySchedulingArgs), b.(*CapacitySchedulingArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CoschedulingArgs)(nil), (*config.CoschedulingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_CoschedulingArgs_To_config_CoschedulingArgs(a.(*CoschedulingArgs), b.(*config.CoschedulingArgs), scope)
 	}); err != nil {
@@ -51,6 +91,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CriticalReserveArgs)(nil), (*config.CriticalReserveArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CriticalReserveArgs_To_config_CriticalReserveArgs(a.(*CriticalReserveArgs), b.(*config.CriticalReserveArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CriticalReserveArgs)(nil), (*CriticalReserveArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CriticalReserveArgs_To_v1_CriticalReserveArgs(a.(*config.CriticalReserveArgs), b.(*CriticalReserveArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DeadlineAwareArgs)(nil), (*config.DeadlineAwareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeadlineAwareArgs_To_config_DeadlineAwareArgs(a.(*DeadlineAwareArgs), b.(*config.DeadlineAwareArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DeadlineAwareArgs)(nil), (*DeadlineAwareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DeadlineAwareArgs_To_v1_DeadlineAwareArgs(a.(*config.DeadlineAwareArgs), b.(*DeadlineAwareArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DominantResourceFairnessArgs)(nil), (*config.DominantResourceFairnessArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DominantResourceFairnessArgs_To_config_DominantResourceFairnessArgs(a.(*DominantResourceFairnessArgs), b.(*config.DominantResourceFairnessArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DominantResourceFairnessArgs)(nil), (*DominantResourceFairnessArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DominantResourceFairnessArgs_To_v1_DominantResourceFairnessArgs(a.(*config.DominantResourceFairnessArgs), b.(*DominantResourceFairnessArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GPUSlicingSpec)(nil), (*config.GPUSlicingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_GPUSlicingSpec_To_config_GPUSlicingSpec(a.(*GPUSlicingSpec), b.(*config.GPUSlicingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.GPUSlicingSpec)(nil), (*GPUSlicingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_GPUSlicingSpec_To_v1_GPUSlicingSpec(a.(*config.GPUSlicingSpec), b.(*GPUSlicingSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LoadVariationRiskBalancingArgs)(nil), (*config.LoadVariationRiskBalancingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_LoadVariationRiskBalancingArgs_To_config_LoadVariationRiskBalancingArgs(a.(*LoadVariationRiskBalancingArgs), b.(*config.LoadVariationRiskBalancingArgs), scope)
 	}); err != nil {
@@ -104,6 +184,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}
 
 
+	if err := s.AddGeneratedConversionFunc((*NodeExclusionSpec)(nil), (*config.NodeExclusionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_NodeExclusionSpec_To_config_NodeExclusionSpec(a.(*NodeExclusionSpec), b.(*config.NodeExclusionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NodeExclusionSpec)(nil), (*NodeExclusionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NodeExclusionSpec_To_v1_NodeExclusionSpec(a.(*config.NodeExclusionSpec), b.(*NodeExclusionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeResourceTopologyCache)(nil), (*config.NodeResourceTopologyCache)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_NodeResourceTopologyCache_To_config_NodeResourceTopologyCache(a.(*NodeResourceTopologyCache), b.(*config.NodeResourceTopologyCache), scope)
 	}); err != nil {
@@ -124,6 +214,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeResourcesFragmentationArgs)(nil), (*config.NodeResourcesFragmentationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_NodeResourcesFragmentationArgs_To_config_NodeResourcesFragmentationArgs(a.(*NodeResourcesFragmentationArgs), b.(*config.NodeResourcesFragmentationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NodeResourcesFragmentationArgs)(nil), (*NodeResourcesFragmentationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NodeResourcesFragmentationArgs_To_v1_NodeResourcesFragmentationArgs(a.(*config.NodeResourcesFragmentationArgs), b.(*NodeResourcesFragmentationArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PeaksArgs)(nil), (*config.PeaksArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_PeaksArgs_To_config_PeaksArgs(a.(*PeaksArgs), b.(*config.PeaksArgs), scope)
 	}); err != nil {
@@ -134,6 +234,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PodStateArgs)(nil), (*config.PodStateArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_PodStateArgs_To_config_PodStateArgs(a.(*PodStateArgs), b.(*config.PodStateArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PodStateArgs)(nil), (*PodStateArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PodStateArgs_To_v1_PodStateArgs(a.(*config.PodStateArgs), b.(*PodStateArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PowerModel)(nil), (*config.PowerModel)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_PowerModel_To_config_PowerModel(a.(*PowerModel), b.(*config.PowerModel), scope)
 	}); err != nil {
@@ -144,6 +254,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PreReclaimSpec)(nil), (*config.PreReclaimSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_PreReclaimSpec_To_config_PreReclaimSpec(a.(*PreReclaimSpec), b.(*config.PreReclaimSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PreReclaimSpec)(nil), (*PreReclaimSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PreReclaimSpec_To_v1_PreReclaimSpec(a.(*config.PreReclaimSpec), b.(*PreReclaimSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PreemptionTolerationArgs)(nil), (*config.PreemptionTolerationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_PreemptionTolerationArgs_To_config_PreemptionTolerationArgs(a.(*PreemptionTolerationArgs), b.(*config.PreemptionTolerationArgs), scope)
 	}); err != nil {
@@ -154,6 +274,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*QueueLengthArgs)(nil), (*config.QueueLengthArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_QueueLengthArgs_To_config_QueueLengthArgs(a.(*QueueLengthArgs), b.(*config.QueueLengthArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.QueueLengthArgs)(nil), (*QueueLengthArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_QueueLengthArgs_To_v1_QueueLengthArgs(a.(*config.QueueLengthArgs), b.(*QueueLengthArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ScoringStrategy)(nil), (*config.ScoringStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_ScoringStrategy_To_config_ScoringStrategy(a.(*ScoringStrategy), b.(*config.ScoringStrategy), scope)
 	}); err != nil {
@@ -164,6 +294,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ServiceMeshLatencyArgs)(nil), (*config.ServiceMeshLatencyArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ServiceMeshLatencyArgs_To_config_ServiceMeshLatencyArgs(a.(*ServiceMeshLatencyArgs), b.(*config.ServiceMeshLatencyArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ServiceMeshLatencyArgs)(nil), (*ServiceMeshLatencyArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ServiceMeshLatencyArgs_To_v1_ServiceMeshLatencyArgs(a.(*config.ServiceMeshLatencyArgs), b.(*ServiceMeshLatencyArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SySchedArgs)(nil), (*config.SySchedArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_SySchedArgs_To_config_SySchedArgs(a.(*SySchedArgs), b.(*config.SySchedArgs), scope)
 	}); err != nil {
@@ -184,6 +324,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ThermalAwareArgs)(nil), (*config.ThermalAwareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ThermalAwareArgs_To_config_ThermalAwareArgs(a.(*ThermalAwareArgs), b.(*config.ThermalAwareArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ThermalAwareArgs)(nil), (*ThermalAwareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ThermalAwareArgs_To_v1_ThermalAwareArgs(a.(*config.ThermalAwareArgs), b.(*ThermalAwareArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TopologicalSortArgs)(nil), (*config.TopologicalSortArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_TopologicalSortArgs_To_config_TopologicalSortArgs(a.(*TopologicalSortArgs), b.(*config.TopologicalSortArgs), scope)
 	}); err != nil {
@@ -229,6 +379,214 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1_AutoTuneSpec_To_config_AutoTuneSpec(in *AutoTuneSpec, out *config.AutoTuneSpec, s conversion.Scope) error {
+	if err := metav1.Convert_Pointer_string_To_string(&in.ConfigMapNamespace, &out.ConfigMapNamespace, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.ConfigMapName, &out.ConfigMapName, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.IntervalSeconds, &out.IntervalSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_float64_To_float64(&in.LearningRate, &out.LearningRate, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MaxAdjustmentPercent, &out.MaxAdjustmentPercent, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_AutoTuneSpec_To_config_AutoTuneSpec is an autogenerated conversion function.
+func Convert_v1_AutoTuneSpec_To_config_AutoTuneSpec(in *AutoTuneSpec, out *config.AutoTuneSpec, s conversion.Scope) error {
+	return autoConvert_v1_AutoTuneSpec_To_config_AutoTuneSpec(in, out, s)
+}
+
+func autoConvert_config_AutoTuneSpec_To_v1_AutoTuneSpec(in *config.AutoTuneSpec, out *AutoTuneSpec, s conversion.Scope) error {
+	if err := metav1.Convert_string_To_Pointer_string(&in.ConfigMapNamespace, &out.ConfigMapNamespace, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.ConfigMapName, &out.ConfigMapName, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.IntervalSeconds, &out.IntervalSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_float64_To_Pointer_float64(&in.LearningRate, &out.LearningRate, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MaxAdjustmentPercent, &out.MaxAdjustmentPercent, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_AutoTuneSpec_To_v1_AutoTuneSpec is an autogenerated conversion function.
+func Convert_config_AutoTuneSpec_To_v1_AutoTuneSpec(in *config.AutoTuneSpec, out *AutoTuneSpec, s conversion.Scope) error {
+	return autoConvert_config_AutoTuneSpec_To_v1_AutoTuneSpec(in, out, s)
+}
+
+func autoConvert_v1_BorrowingQueueSpec_To_config_BorrowingQueueSpec(in *BorrowingQueueSpec, out *config.BorrowingQueueSpec, s conversion.Scope) error {
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.AgingSeconds, &out.AgingSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.ExpirationSeconds, &out.ExpirationSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_BorrowingQueueSpec_To_config_BorrowingQueueSpec is an autogenerated conversion function.
+func Convert_v1_BorrowingQueueSpec_To_config_BorrowingQueueSpec(in *BorrowingQueueSpec, out *config.BorrowingQueueSpec, s conversion.Scope) error {
+	return autoConvert_v1_BorrowingQueueSpec_To_config_BorrowingQueueSpec(in, out, s)
+}
+
+func autoConvert_config_BorrowingQueueSpec_To_v1_BorrowingQueueSpec(in *config.BorrowingQueueSpec, out *BorrowingQueueSpec, s conversion.Scope) error {
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.AgingSeconds, &out.AgingSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.ExpirationSeconds, &out.ExpirationSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_BorrowingQueueSpec_To_v1_BorrowingQueueSpec is an autogenerated conversion function.
+func Convert_config_BorrowingQueueSpec_To_v1_BorrowingQueueSpec(in *config.BorrowingQueueSpec, out *BorrowingQueueSpec, s conversion.Scope) error {
+	return autoConvert_config_BorrowingQueueSpec_To_v1_BorrowingQueueSpec(in, out, s)
+}
+
+func autoConvert_v1_CacheIsolationArgs_To_config_CacheIsolationArgs(in *CacheIsolationArgs, out *config.CacheIsolationArgs, s conversion.Scope) error {
+	out.LatencySensitiveSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.LatencySensitiveSelector))
+	out.CacheHungrySelector = (*metav1.LabelSelector)(unsafe.Pointer(in.CacheHungrySelector))
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.LLCOccupancyWeight, &out.LLCOccupancyWeight, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MemoryBandwidthWeight, &out.MemoryBandwidthWeight, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.CacheHungryPodPenalty, &out.CacheHungryPodPenalty, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MetricsMaxAgeSeconds, &out.MetricsMaxAgeSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_CacheIsolationArgs_To_config_CacheIsolationArgs is an autogenerated conversion function.
+func Convert_v1_CacheIsolationArgs_To_config_CacheIsolationArgs(in *CacheIsolationArgs, out *config.CacheIsolationArgs, s conversion.Scope) error {
+	return autoConvert_v1_CacheIsolationArgs_To_config_CacheIsolationArgs(in, out, s)
+}
+
+func autoConvert_config_CacheIsolationArgs_To_v1_CacheIsolationArgs(in *config.CacheIsolationArgs, out *CacheIsolationArgs, s conversion.Scope) error {
+	out.LatencySensitiveSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.LatencySensitiveSelector))
+	out.CacheHungrySelector = (*metav1.LabelSelector)(unsafe.Pointer(in.CacheHungrySelector))
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.LLCOccupancyWeight, &out.LLCOccupancyWeight, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MemoryBandwidthWeight, &out.MemoryBandwidthWeight, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.CacheHungryPodPenalty, &out.CacheHungryPodPenalty, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MetricsMaxAgeSeconds, &out.MetricsMaxAgeSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_CacheIsolationArgs_To_v1_CacheIsolationArgs is an autogenerated conversion function.
+func Convert_config_CacheIsolationArgs_To_v1_CacheIsolationArgs(in *config.CacheIsolationArgs, out *CacheIsolationArgs, s conversion.Scope) error {
+	return autoConvert_config_CacheIsolationArgs_To_v1_CacheIsolationArgs(in, out, s)
+}
+
+func autoConvert_v1_CapacitySchedulingArgs_To_config_CapacitySchedulingArgs(in *CapacitySchedulingArgs, out *config.CapacitySchedulingArgs, s conversion.Scope) error {
+	if in.GPUSlicing != nil {
+		in, out := &in.GPUSlicing, &out.GPUSlicing
+		*out = new(config.GPUSlicingSpec)
+		if err := Convert_v1_GPUSlicingSpec_To_config_GPUSlicingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GPUSlicing = nil
+	}
+	if in.BorrowingQueue != nil {
+		in, out := &in.BorrowingQueue, &out.BorrowingQueue
+		*out = new(config.BorrowingQueueSpec)
+		if err := Convert_v1_BorrowingQueueSpec_To_config_BorrowingQueueSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.BorrowingQueue = nil
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.PreemptionProtectionSeconds, &out.PreemptionProtectionSeconds, s); err != nil {
+		return err
+	}
+	if in.PreReclaim != nil {
+		in, out := &in.PreReclaim, &out.PreReclaim
+		*out = new(config.PreReclaimSpec)
+		if err := Convert_v1_PreReclaimSpec_To_config_PreReclaimSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreReclaim = nil
+	}
+	out.BorrowingPolicy = config.BorrowingPolicyType(in.BorrowingPolicy)
+	out.PreemptionFreezeConfigMap = in.PreemptionFreezeConfigMap
+	out.AnnotateQuotaUsage = in.AnnotateQuotaUsage
+	return nil
+}
+
+// Convert_v1_CapacitySchedulingArgs_To_config_CapacitySchedulingArgs is an autogenerated conversion function.
+func Convert_v1_CapacitySchedulingArgs_To_config_CapacitySchedulingArgs(in *CapacitySchedulingArgs, out *config.CapacitySchedulingArgs, s conversion.Scope) error {
+	return autoConvert_v1_CapacitySchedulingArgs_To_config_CapacitySchedulingArgs(in, out, s)
+}
+
+func autoConvert_config_CapacitySchedulingArgs_To_v1_CapacitySchedulingArgs(in *config.CapacitySchedulingArgs, out *CapacitySchedulingArgs, s conversion.Scope) error {
+	if in.GPUSlicing != nil {
+		in, out := &in.GPUSlicing, &out.GPUSlicing
+		*out = new(GPUSlicingSpec)
+		if err := Convert_config_GPUSlicingSpec_To_v1_GPUSlicingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GPUSlicing = nil
+	}
+	if in.BorrowingQueue != nil {
+		in, out := &in.BorrowingQueue, &out.BorrowingQueue
+		*out = new(BorrowingQueueSpec)
+		if err := Convert_config_BorrowingQueueSpec_To_v1_BorrowingQueueSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.BorrowingQueue = nil
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.PreemptionProtectionSeconds, &out.PreemptionProtectionSeconds, s); err != nil {
+		return err
+	}
+	if in.PreReclaim != nil {
+		in, out := &in.PreReclaim, &out.PreReclaim
+		*out = new(PreReclaimSpec)
+		if err := Convert_config_PreReclaimSpec_To_v1_PreReclaimSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreReclaim = nil
+	}
+	out.BorrowingPolicy = BorrowingPolicyType(in.BorrowingPolicy)
+	out.PreemptionFreezeConfigMap = in.PreemptionFreezeConfigMap
+	out.AnnotateQuotaUsage = in.AnnotateQuotaUsage
+	return nil
+}
+
+// Convert_config_CapacitySchedulingArgs_To_v1_CapacitySchedulingArgs is an autogenerated conversion function.
+func Convert_config_CapacitySchedulingArgs_To_v1_CapacitySchedulingArgs(in *config.CapacitySchedulingArgs, out *CapacitySchedulingArgs, s conversion.Scope) error {
+	return autoConvert_config_CapacitySchedulingArgs_To_v1_CapacitySchedulingArgs(in, out, s)
+}
+
 func autoConvert_v1_CoschedulingArgs_To_config_CoschedulingArgs(in *CoschedulingArgs, out *config.CoschedulingArgs, s conversion.Scope) error {
 	if err := metav1.Convert_Pointer_int64_To_int64(&in.PermitWaitingTimeSeconds, &out.PermitWaitingTimeSeconds, s); err != nil {
 		return err
@@ -236,6 +594,27 @@ func autoConvert_v1_CoschedulingArgs_To_config_CoschedulingArgs(in *Coscheduling
 	if err := metav1.Convert_Pointer_int64_To_int64(&in.PodGroupBackoffSeconds, &out.PodGroupBackoffSeconds, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.GangAdmissionWindowSeconds, &out.GangAdmissionWindowSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MaxWaitingPodGroupsPerNamespace, &out.MaxWaitingPodGroupsPerNamespace, s); err != nil {
+		return err
+	}
+	out.FlushDeletedPodGroups = in.FlushDeletedPodGroups
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.WaitingMembersStatusIntervalSeconds, &out.WaitingMembersStatusIntervalSeconds, s); err != nil {
+		return err
+	}
+	out.GangPreemption = in.GangPreemption
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.CheckpointTimeoutSeconds, &out.CheckpointTimeoutSeconds, s); err != nil {
+		return err
+	}
+	out.CheckpointWebhookURL = in.CheckpointWebhookURL
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.RetryPenaltySeconds, &out.RetryPenaltySeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.ScaleUpPermitExtensionSeconds, &out.ScaleUpPermitExtensionSeconds, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -251,6 +630,27 @@ func autoConvert_config_CoschedulingArgs_To_v1_CoschedulingArgs(in *config.Cosch
 	if err := metav1.Convert_int64_To_Pointer_int64(&in.PodGroupBackoffSeconds, &out.PodGroupBackoffSeconds, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.GangAdmissionWindowSeconds, &out.GangAdmissionWindowSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MaxWaitingPodGroupsPerNamespace, &out.MaxWaitingPodGroupsPerNamespace, s); err != nil {
+		return err
+	}
+	out.FlushDeletedPodGroups = in.FlushDeletedPodGroups
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.WaitingMembersStatusIntervalSeconds, &out.WaitingMembersStatusIntervalSeconds, s); err != nil {
+		return err
+	}
+	out.GangPreemption = in.GangPreemption
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.CheckpointTimeoutSeconds, &out.CheckpointTimeoutSeconds, s); err != nil {
+		return err
+	}
+	out.CheckpointWebhookURL = in.CheckpointWebhookURL
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.RetryPenaltySeconds, &out.RetryPenaltySeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.ScaleUpPermitExtensionSeconds, &out.ScaleUpPermitExtensionSeconds, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -259,6 +659,112 @@ func Convert_config_CoschedulingArgs_To_v1_CoschedulingArgs(in *config.Coschedul
 	return autoConvert_config_CoschedulingArgs_To_v1_CoschedulingArgs(in, out, s)
 }
 
+func autoConvert_v1_CriticalReserveArgs_To_config_CriticalReserveArgs(in *CriticalReserveArgs, out *config.CriticalReserveArgs, s conversion.Scope) error {
+	out.ReservedResources = *(*corev1.ResourceList)(unsafe.Pointer(&in.ReservedResources))
+	if err := metav1.Convert_Pointer_int32_To_int32(&in.MinCriticalPriority, &out.MinCriticalPriority, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_CriticalReserveArgs_To_config_CriticalReserveArgs is an autogenerated conversion function.
+func Convert_v1_CriticalReserveArgs_To_config_CriticalReserveArgs(in *CriticalReserveArgs, out *config.CriticalReserveArgs, s conversion.Scope) error {
+	return autoConvert_v1_CriticalReserveArgs_To_config_CriticalReserveArgs(in, out, s)
+}
+
+func autoConvert_config_CriticalReserveArgs_To_v1_CriticalReserveArgs(in *config.CriticalReserveArgs, out *CriticalReserveArgs, s conversion.Scope) error {
+	out.ReservedResources = *(*corev1.ResourceList)(unsafe.Pointer(&in.ReservedResources))
+	if err := metav1.Convert_int32_To_Pointer_int32(&in.MinCriticalPriority, &out.MinCriticalPriority, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_CriticalReserveArgs_To_v1_CriticalReserveArgs is an autogenerated conversion function.
+func Convert_config_CriticalReserveArgs_To_v1_CriticalReserveArgs(in *config.CriticalReserveArgs, out *CriticalReserveArgs, s conversion.Scope) error {
+	return autoConvert_config_CriticalReserveArgs_To_v1_CriticalReserveArgs(in, out, s)
+}
+
+func autoConvert_v1_DeadlineAwareArgs_To_config_DeadlineAwareArgs(in *DeadlineAwareArgs, out *config.DeadlineAwareArgs, s conversion.Scope) error {
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.UrgencyThresholdSeconds, &out.UrgencyThresholdSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_DeadlineAwareArgs_To_config_DeadlineAwareArgs is an autogenerated conversion function.
+func Convert_v1_DeadlineAwareArgs_To_config_DeadlineAwareArgs(in *DeadlineAwareArgs, out *config.DeadlineAwareArgs, s conversion.Scope) error {
+	return autoConvert_v1_DeadlineAwareArgs_To_config_DeadlineAwareArgs(in, out, s)
+}
+
+func autoConvert_config_DeadlineAwareArgs_To_v1_DeadlineAwareArgs(in *config.DeadlineAwareArgs, out *DeadlineAwareArgs, s conversion.Scope) error {
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.UrgencyThresholdSeconds, &out.UrgencyThresholdSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_DeadlineAwareArgs_To_v1_DeadlineAwareArgs is an autogenerated conversion function.
+func Convert_config_DeadlineAwareArgs_To_v1_DeadlineAwareArgs(in *config.DeadlineAwareArgs, out *DeadlineAwareArgs, s conversion.Scope) error {
+	return autoConvert_config_DeadlineAwareArgs_To_v1_DeadlineAwareArgs(in, out, s)
+}
+
+func autoConvert_v1_DominantResourceFairnessArgs_To_config_DominantResourceFairnessArgs(in *DominantResourceFairnessArgs, out *config.DominantResourceFairnessArgs, s conversion.Scope) error {
+	out.TenantLabel = in.TenantLabel
+	return nil
+}
+
+// Convert_v1_DominantResourceFairnessArgs_To_config_DominantResourceFairnessArgs is an autogenerated conversion function.
+func Convert_v1_DominantResourceFairnessArgs_To_config_DominantResourceFairnessArgs(in *DominantResourceFairnessArgs, out *config.DominantResourceFairnessArgs, s conversion.Scope) error {
+	return autoConvert_v1_DominantResourceFairnessArgs_To_config_DominantResourceFairnessArgs(in, out, s)
+}
+
+func autoConvert_config_DominantResourceFairnessArgs_To_v1_DominantResourceFairnessArgs(in *config.DominantResourceFairnessArgs, out *DominantResourceFairnessArgs, s conversion.Scope) error {
+	out.TenantLabel = in.TenantLabel
+	return nil
+}
+
+// Convert_config_DominantResourceFairnessArgs_To_v1_DominantResourceFairnessArgs is an autogenerated conversion function.
+func Convert_config_DominantResourceFairnessArgs_To_v1_DominantResourceFairnessArgs(in *config.DominantResourceFairnessArgs, out *DominantResourceFairnessArgs, s conversion.Scope) error {
+	return autoConvert_config_DominantResourceFairnessArgs_To_v1_DominantResourceFairnessArgs(in, out, s)
+}
+
+func autoConvert_v1_GPUSlicingSpec_To_config_GPUSlicingSpec(in *GPUSlicingSpec, out *config.GPUSlicingSpec, s conversion.Scope) error {
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.SlicesPerGPU, &out.SlicesPerGPU, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.TimeSlicedResourceName, &out.TimeSlicedResourceName, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.SlicesPerTimeSlicedReplica, &out.SlicesPerTimeSlicedReplica, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_GPUSlicingSpec_To_config_GPUSlicingSpec is an autogenerated conversion function.
+func Convert_v1_GPUSlicingSpec_To_config_GPUSlicingSpec(in *GPUSlicingSpec, out *config.GPUSlicingSpec, s conversion.Scope) error {
+	return autoConvert_v1_GPUSlicingSpec_To_config_GPUSlicingSpec(in, out, s)
+}
+
+func autoConvert_config_GPUSlicingSpec_To_v1_GPUSlicingSpec(in *config.GPUSlicingSpec, out *GPUSlicingSpec, s conversion.Scope) error {
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.SlicesPerGPU, &out.SlicesPerGPU, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.TimeSlicedResourceName, &out.TimeSlicedResourceName, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.SlicesPerTimeSlicedReplica, &out.SlicesPerTimeSlicedReplica, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_GPUSlicingSpec_To_v1_GPUSlicingSpec is an autogenerated conversion function.
+func Convert_config_GPUSlicingSpec_To_v1_GPUSlicingSpec(in *config.GPUSlicingSpec, out *GPUSlicingSpec, s conversion.Scope) error {
+	return autoConvert_config_GPUSlicingSpec_To_v1_GPUSlicingSpec(in, out, s)
+}
+
 func autoConvert_v1_LoadVariationRiskBalancingArgs_To_config_LoadVariationRiskBalancingArgs(in *LoadVariationRiskBalancingArgs, out *config.LoadVariationRiskBalancingArgs, s conversion.Scope) error {
 	if err := Convert_v1_TrimaranSpec_To_config_TrimaranSpec(&in.TrimaranSpec, &out.TrimaranSpec, s); err != nil {
 		return err
@@ -269,6 +775,14 @@ func autoConvert_v1_LoadVariationRiskBalancingArgs_To_config_LoadVariationRiskBa
 	if err := metav1.Convert_Pointer_float64_To_float64(&in.SafeVarianceSensitivity, &out.SafeVarianceSensitivity, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.WarmUpSeconds, &out.WarmUpSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_float64_To_float64(&in.WarmUpDiscount, &out.WarmUpDiscount, s); err != nil {
+		return err
+	}
+	out.NamespaceRiskMultipliers = *(*map[string]float64)(unsafe.Pointer(&in.NamespaceRiskMultipliers))
+	out.PriorityClassRiskMultipliers = *(*map[string]float64)(unsafe.Pointer(&in.PriorityClassRiskMultipliers))
 	return nil
 }
 
@@ -287,6 +801,14 @@ func autoConvert_config_LoadVariationRiskBalancingArgs_To_v1_LoadVariationRiskBa
 	if err := metav1.Convert_float64_To_Pointer_float64(&in.SafeVarianceSensitivity, &out.SafeVarianceSensitivity, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.WarmUpSeconds, &out.WarmUpSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_float64_To_Pointer_float64(&in.WarmUpDiscount, &out.WarmUpDiscount, s); err != nil {
+		return err
+	}
+	out.NamespaceRiskMultipliers = *(*map[string]float64)(unsafe.Pointer(&in.NamespaceRiskMultipliers))
+	out.PriorityClassRiskMultipliers = *(*map[string]float64)(unsafe.Pointer(&in.PriorityClassRiskMultipliers))
 	return nil
 }
 
@@ -303,6 +825,9 @@ func autoConvert_v1_LowRiskOverCommitmentArgs_To_config_LowRiskOverCommitmentArg
 		return err
 	}
 	out.RiskLimitWeights = *(*map[corev1.ResourceName]float64)(unsafe.Pointer(&in.RiskLimitWeights))
+	if err := metav1.Convert_Pointer_bool_To_bool(&in.UseVPARecommendations, &out.UseVPARecommendations, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -319,6 +844,9 @@ func autoConvert_config_LowRiskOverCommitmentArgs_To_v1_LowRiskOverCommitmentArg
 		return err
 	}
 	out.RiskLimitWeights = *(*map[corev1.ResourceName]float64)(unsafe.Pointer(&in.RiskLimitWeights))
+	if err := metav1.Convert_bool_To_Pointer_bool(&in.UseVPARecommendations, &out.UseVPARecommendations, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -373,6 +901,13 @@ func autoConvert_v1_NetworkOverheadArgs_To_config_NetworkOverheadArgs(in *Networ
 	if err := metav1.Convert_Pointer_string_To_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.ZoneViolationWeight, &out.ZoneViolationWeight, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.RegionViolationWeight, &out.RegionViolationWeight, s); err != nil {
+		return err
+	}
+	out.ScoringMode = config.NetworkOverheadScoringMode(in.ScoringMode)
 	return nil
 }
 
@@ -389,6 +924,13 @@ func autoConvert_config_NetworkOverheadArgs_To_v1_NetworkOverheadArgs(in *config
 	if err := metav1.Convert_string_To_Pointer_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.ZoneViolationWeight, &out.ZoneViolationWeight, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.RegionViolationWeight, &out.RegionViolationWeight, s); err != nil {
+		return err
+	}
+	out.ScoringMode = NetworkOverheadScoringMode(in.ScoringMode)
 	return nil
 }
 
@@ -397,6 +939,28 @@ func Convert_config_NetworkOverheadArgs_To_v1_NetworkOverheadArgs(in *config.Net
 	return autoConvert_config_NetworkOverheadArgs_To_v1_NetworkOverheadArgs(in, out, s)
 }
 
+func autoConvert_v1_NodeExclusionSpec_To_config_NodeExclusionSpec(in *NodeExclusionSpec, out *config.NodeExclusionSpec, s conversion.Scope) error {
+	out.NodeSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.NodeSelector))
+	out.Taints = *(*[]corev1.Taint)(unsafe.Pointer(&in.Taints))
+	return nil
+}
+
+// Convert_v1_NodeExclusionSpec_To_config_NodeExclusionSpec is an autogenerated conversion function.
+func Convert_v1_NodeExclusionSpec_To_config_NodeExclusionSpec(in *NodeExclusionSpec, out *config.NodeExclusionSpec, s conversion.Scope) error {
+	return autoConvert_v1_NodeExclusionSpec_To_config_NodeExclusionSpec(in, out, s)
+}
+
+func autoConvert_config_NodeExclusionSpec_To_v1_NodeExclusionSpec(in *config.NodeExclusionSpec, out *NodeExclusionSpec, s conversion.Scope) error {
+	out.NodeSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.NodeSelector))
+	out.Taints = *(*[]corev1.Taint)(unsafe.Pointer(&in.Taints))
+	return nil
+}
+
+// Convert_config_NodeExclusionSpec_To_v1_NodeExclusionSpec is an autogenerated conversion function.
+func Convert_config_NodeExclusionSpec_To_v1_NodeExclusionSpec(in *config.NodeExclusionSpec, out *NodeExclusionSpec, s conversion.Scope) error {
+	return autoConvert_config_NodeExclusionSpec_To_v1_NodeExclusionSpec(in, out, s)
+}
+
 func autoConvert_v1_NodeResourceTopologyCache_To_config_NodeResourceTopologyCache(in *NodeResourceTopologyCache, out *config.NodeResourceTopologyCache, s conversion.Scope) error {
 	out.ForeignPodsDetect = (*config.ForeignPodsDetectMode)(unsafe.Pointer(in.ForeignPodsDetect))
 	out.ResyncMethod = (*config.CacheResyncMethod)(unsafe.Pointer(in.ResyncMethod))
@@ -430,6 +994,8 @@ func autoConvert_v1_NodeResourceTopologyMatchArgs_To_config_NodeResourceTopology
 	}
 	out.DiscardReservedNodes = in.DiscardReservedNodes
 	out.Cache = (*config.NodeResourceTopologyCache)(unsafe.Pointer(in.Cache))
+	out.NUMAPreemption = in.NUMAPreemption
+	out.GangAlignment = in.GangAlignment
 	return nil
 }
 
@@ -440,12 +1006,15 @@ func autoConvert_config_NodeResourceTopologyMatchArgs_To_v1_NodeResourceTopology
 	}
 	out.DiscardReservedNodes = in.DiscardReservedNodes
 	out.Cache = (*NodeResourceTopologyCache)(unsafe.Pointer(in.Cache))
+	out.NUMAPreemption = in.NUMAPreemption
+	out.GangAlignment = in.GangAlignment
 	return nil
 }
 
 func autoConvert_v1_NodeResourcesAllocatableArgs_To_config_NodeResourcesAllocatableArgs(in *NodeResourcesAllocatableArgs, out *config.NodeResourcesAllocatableArgs, s conversion.Scope) error {
 	out.Resources = *(*[]apisconfig.ResourceSpec)(unsafe.Pointer(&in.Resources))
 	out.Mode = config.ModeType(in.Mode)
+	out.Aggregation = config.AggregationType(in.Aggregation)
 	return nil
 }
 
@@ -457,6 +1026,7 @@ func Convert_v1_NodeResourcesAllocatableArgs_To_config_NodeResourcesAllocatableA
 func autoConvert_config_NodeResourcesAllocatableArgs_To_v1_NodeResourcesAllocatableArgs(in *config.NodeResourcesAllocatableArgs, out *NodeResourcesAllocatableArgs, s conversion.Scope) error {
 	out.Resources = *(*[]configv1.ResourceSpec)(unsafe.Pointer(&in.Resources))
 	out.Mode = ModeType(in.Mode)
+	out.Aggregation = AggregationType(in.Aggregation)
 	return nil
 }
 
@@ -465,9 +1035,32 @@ func Convert_config_NodeResourcesAllocatableArgs_To_v1_NodeResourcesAllocatableA
 	return autoConvert_config_NodeResourcesAllocatableArgs_To_v1_NodeResourcesAllocatableArgs(in, out, s)
 }
 
+func autoConvert_v1_NodeResourcesFragmentationArgs_To_config_NodeResourcesFragmentationArgs(in *NodeResourcesFragmentationArgs, out *config.NodeResourcesFragmentationArgs, s conversion.Scope) error {
+	out.Resources = *(*[]apisconfig.ResourceSpec)(unsafe.Pointer(&in.Resources))
+	out.Shape = *(*[]apisconfig.UtilizationShapePoint)(unsafe.Pointer(&in.Shape))
+	return nil
+}
+
+// Convert_v1_NodeResourcesFragmentationArgs_To_config_NodeResourcesFragmentationArgs is an autogenerated conversion function.
+func Convert_v1_NodeResourcesFragmentationArgs_To_config_NodeResourcesFragmentationArgs(in *NodeResourcesFragmentationArgs, out *config.NodeResourcesFragmentationArgs, s conversion.Scope) error {
+	return autoConvert_v1_NodeResourcesFragmentationArgs_To_config_NodeResourcesFragmentationArgs(in, out, s)
+}
+
+func autoConvert_config_NodeResourcesFragmentationArgs_To_v1_NodeResourcesFragmentationArgs(in *config.NodeResourcesFragmentationArgs, out *NodeResourcesFragmentationArgs, s conversion.Scope) error {
+	out.Resources = *(*[]configv1.ResourceSpec)(unsafe.Pointer(&in.Resources))
+	out.Shape = *(*[]configv1.UtilizationShapePoint)(unsafe.Pointer(&in.Shape))
+	return nil
+}
+
+// Convert_config_NodeResourcesFragmentationArgs_To_v1_NodeResourcesFragmentationArgs is an autogenerated conversion function.
+func Convert_config_NodeResourcesFragmentationArgs_To_v1_NodeResourcesFragmentationArgs(in *config.NodeResourcesFragmentationArgs, out *NodeResourcesFragmentationArgs, s conversion.Scope) error {
+	return autoConvert_config_NodeResourcesFragmentationArgs_To_v1_NodeResourcesFragmentationArgs(in, out, s)
+}
+
 func autoConvert_v1_PeaksArgs_To_config_PeaksArgs(in *PeaksArgs, out *config.PeaksArgs, s conversion.Scope) error {
 	out.WatcherAddress = in.WatcherAddress
 	out.NodePowerModel = *(*map[string]config.PowerModel)(unsafe.Pointer(&in.NodePowerModel))
+	out.PowerProfileConfigMap = in.PowerProfileConfigMap
 	return nil
 }
 
@@ -479,6 +1072,7 @@ func Convert_v1_PeaksArgs_To_config_PeaksArgs(in *PeaksArgs, out *config.PeaksAr
 func autoConvert_config_PeaksArgs_To_v1_PeaksArgs(in *config.PeaksArgs, out *PeaksArgs, s conversion.Scope) error {
 	out.WatcherAddress = in.WatcherAddress
 	out.NodePowerModel = *(*map[string]PowerModel)(unsafe.Pointer(&in.NodePowerModel))
+	out.PowerProfileConfigMap = in.PowerProfileConfigMap
 	return nil
 }
 
@@ -487,6 +1081,42 @@ func Convert_config_PeaksArgs_To_v1_PeaksArgs(in *config.PeaksArgs, out *PeaksAr
 	return autoConvert_config_PeaksArgs_To_v1_PeaksArgs(in, out, s)
 }
 
+func autoConvert_v1_PodStateArgs_To_config_PodStateArgs(in *PodStateArgs, out *config.PodStateArgs, s conversion.Scope) error {
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.TerminatingWeight, &out.TerminatingWeight, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.NominatedWeight, &out.NominatedWeight, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.NotReadyWeight, &out.NotReadyWeight, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_PodStateArgs_To_config_PodStateArgs is an autogenerated conversion function.
+func Convert_v1_PodStateArgs_To_config_PodStateArgs(in *PodStateArgs, out *config.PodStateArgs, s conversion.Scope) error {
+	return autoConvert_v1_PodStateArgs_To_config_PodStateArgs(in, out, s)
+}
+
+func autoConvert_config_PodStateArgs_To_v1_PodStateArgs(in *config.PodStateArgs, out *PodStateArgs, s conversion.Scope) error {
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.TerminatingWeight, &out.TerminatingWeight, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.NominatedWeight, &out.NominatedWeight, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.NotReadyWeight, &out.NotReadyWeight, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_PodStateArgs_To_v1_PodStateArgs is an autogenerated conversion function.
+func Convert_config_PodStateArgs_To_v1_PodStateArgs(in *config.PodStateArgs, out *PodStateArgs, s conversion.Scope) error {
+	return autoConvert_config_PodStateArgs_To_v1_PodStateArgs(in, out, s)
+}
+
 func autoConvert_v1_PowerModel_To_config_PowerModel(in *PowerModel, out *config.PowerModel, s conversion.Scope) error {
 	out.K0 = in.K0
 	out.K1 = in.K1
@@ -511,6 +1141,42 @@ func Convert_config_PowerModel_To_v1_PowerModel(in *config.PowerModel, out *Powe
 	return autoConvert_config_PowerModel_To_v1_PowerModel(in, out, s)
 }
 
+func autoConvert_v1_PreReclaimSpec_To_config_PreReclaimSpec(in *PreReclaimSpec, out *config.PreReclaimSpec, s conversion.Scope) error {
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.LeadTimeSeconds, &out.LeadTimeSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.IntervalSeconds, &out.IntervalSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MaxEvictionsPerInterval, &out.MaxEvictionsPerInterval, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_PreReclaimSpec_To_config_PreReclaimSpec is an autogenerated conversion function.
+func Convert_v1_PreReclaimSpec_To_config_PreReclaimSpec(in *PreReclaimSpec, out *config.PreReclaimSpec, s conversion.Scope) error {
+	return autoConvert_v1_PreReclaimSpec_To_config_PreReclaimSpec(in, out, s)
+}
+
+func autoConvert_config_PreReclaimSpec_To_v1_PreReclaimSpec(in *config.PreReclaimSpec, out *PreReclaimSpec, s conversion.Scope) error {
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.LeadTimeSeconds, &out.LeadTimeSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.IntervalSeconds, &out.IntervalSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MaxEvictionsPerInterval, &out.MaxEvictionsPerInterval, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_PreReclaimSpec_To_v1_PreReclaimSpec is an autogenerated conversion function.
+func Convert_config_PreReclaimSpec_To_v1_PreReclaimSpec(in *config.PreReclaimSpec, out *PreReclaimSpec, s conversion.Scope) error {
+	return autoConvert_config_PreReclaimSpec_To_v1_PreReclaimSpec(in, out, s)
+}
+
 func autoConvert_v1_PreemptionTolerationArgs_To_config_PreemptionTolerationArgs(in *PreemptionTolerationArgs, out *config.PreemptionTolerationArgs, s conversion.Scope) error {
 	if err := metav1.Convert_Pointer_int32_To_int32(&in.MinCandidateNodesPercentage, &out.MinCandidateNodesPercentage, s); err != nil {
 		return err
@@ -518,6 +1184,7 @@ func autoConvert_v1_PreemptionTolerationArgs_To_config_PreemptionTolerationArgs(
 	if err := metav1.Convert_Pointer_int32_To_int32(&in.MinCandidateNodesAbsolute, &out.MinCandidateNodesAbsolute, s); err != nil {
 		return err
 	}
+	out.PartialPreemption = in.PartialPreemption
 	return nil
 }
 
@@ -533,6 +1200,7 @@ func autoConvert_config_PreemptionTolerationArgs_To_v1_PreemptionTolerationArgs(
 	if err := metav1.Convert_int32_To_Pointer_int32(&in.MinCandidateNodesAbsolute, &out.MinCandidateNodesAbsolute, s); err != nil {
 		return err
 	}
+	out.PartialPreemption = in.PartialPreemption
 	return nil
 }
 
@@ -541,6 +1209,48 @@ func Convert_config_PreemptionTolerationArgs_To_v1_PreemptionTolerationArgs(in *
 	return autoConvert_config_PreemptionTolerationArgs_To_v1_PreemptionTolerationArgs(in, out, s)
 }
 
+func autoConvert_v1_QueueLengthArgs_To_config_QueueLengthArgs(in *QueueLengthArgs, out *config.QueueLengthArgs, s conversion.Scope) error {
+	if err := metav1.Convert_Pointer_string_To_string(&in.PrometheusAddress, &out.PrometheusAddress, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.BacklogQuery, &out.BacklogQuery, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.ConsumerGroupLabel, &out.ConsumerGroupLabel, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MetricsUpdateIntervalSeconds, &out.MetricsUpdateIntervalSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_QueueLengthArgs_To_config_QueueLengthArgs is an autogenerated conversion function.
+func Convert_v1_QueueLengthArgs_To_config_QueueLengthArgs(in *QueueLengthArgs, out *config.QueueLengthArgs, s conversion.Scope) error {
+	return autoConvert_v1_QueueLengthArgs_To_config_QueueLengthArgs(in, out, s)
+}
+
+func autoConvert_config_QueueLengthArgs_To_v1_QueueLengthArgs(in *config.QueueLengthArgs, out *QueueLengthArgs, s conversion.Scope) error {
+	if err := metav1.Convert_string_To_Pointer_string(&in.PrometheusAddress, &out.PrometheusAddress, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.BacklogQuery, &out.BacklogQuery, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.ConsumerGroupLabel, &out.ConsumerGroupLabel, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MetricsUpdateIntervalSeconds, &out.MetricsUpdateIntervalSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_QueueLengthArgs_To_v1_QueueLengthArgs is an autogenerated conversion function.
+func Convert_config_QueueLengthArgs_To_v1_QueueLengthArgs(in *config.QueueLengthArgs, out *QueueLengthArgs, s conversion.Scope) error {
+	return autoConvert_config_QueueLengthArgs_To_v1_QueueLengthArgs(in, out, s)
+}
+
 func autoConvert_v1_ScoringStrategy_To_config_ScoringStrategy(in *ScoringStrategy, out *config.ScoringStrategy, s conversion.Scope) error {
 	out.Type = config.ScoringStrategyType(in.Type)
 	out.Resources = *(*[]apisconfig.ResourceSpec)(unsafe.Pointer(&in.Resources))
@@ -563,6 +1273,58 @@ func Convert_config_ScoringStrategy_To_v1_ScoringStrategy(in *config.ScoringStra
 	return autoConvert_config_ScoringStrategy_To_v1_ScoringStrategy(in, out, s)
 }
 
+func autoConvert_v1_ServiceMeshLatencyArgs_To_config_ServiceMeshLatencyArgs(in *ServiceMeshLatencyArgs, out *config.ServiceMeshLatencyArgs, s conversion.Scope) error {
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	if err := metav1.Convert_Pointer_string_To_string(&in.WeightsName, &out.WeightsName, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
+		return err
+	}
+	out.MeshType = config.ServiceMeshType(in.MeshType)
+	if err := metav1.Convert_Pointer_string_To_string(&in.PrometheusAddress, &out.PrometheusAddress, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.LatencyQuery, &out.LatencyQuery, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MetricsUpdateIntervalSeconds, &out.MetricsUpdateIntervalSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_ServiceMeshLatencyArgs_To_config_ServiceMeshLatencyArgs is an autogenerated conversion function.
+func Convert_v1_ServiceMeshLatencyArgs_To_config_ServiceMeshLatencyArgs(in *ServiceMeshLatencyArgs, out *config.ServiceMeshLatencyArgs, s conversion.Scope) error {
+	return autoConvert_v1_ServiceMeshLatencyArgs_To_config_ServiceMeshLatencyArgs(in, out, s)
+}
+
+func autoConvert_config_ServiceMeshLatencyArgs_To_v1_ServiceMeshLatencyArgs(in *config.ServiceMeshLatencyArgs, out *ServiceMeshLatencyArgs, s conversion.Scope) error {
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	if err := metav1.Convert_string_To_Pointer_string(&in.WeightsName, &out.WeightsName, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
+		return err
+	}
+	out.MeshType = ServiceMeshType(in.MeshType)
+	if err := metav1.Convert_string_To_Pointer_string(&in.PrometheusAddress, &out.PrometheusAddress, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.LatencyQuery, &out.LatencyQuery, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MetricsUpdateIntervalSeconds, &out.MetricsUpdateIntervalSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_ServiceMeshLatencyArgs_To_v1_ServiceMeshLatencyArgs is an autogenerated conversion function.
+func Convert_config_ServiceMeshLatencyArgs_To_v1_ServiceMeshLatencyArgs(in *config.ServiceMeshLatencyArgs, out *ServiceMeshLatencyArgs, s conversion.Scope) error {
+	return autoConvert_config_ServiceMeshLatencyArgs_To_v1_ServiceMeshLatencyArgs(in, out, s)
+}
+
 func autoConvert_v1_SySchedArgs_To_config_SySchedArgs(in *SySchedArgs, out *config.SySchedArgs, s conversion.Scope) error {
 	if err := metav1.Convert_Pointer_string_To_string(&in.DefaultProfileNamespace, &out.DefaultProfileNamespace, s); err != nil {
 		return err
@@ -570,6 +1332,15 @@ func autoConvert_v1_SySchedArgs_To_config_SySchedArgs(in *SySchedArgs, out *conf
 	if err := metav1.Convert_Pointer_string_To_string(&in.DefaultProfileName, &out.DefaultProfileName, s); err != nil {
 		return err
 	}
+	out.SimilarityMetric = config.SySchedSimilarityMetric(in.SimilarityMetric)
+	out.CriticalSyscalls = *(*[]string)(unsafe.Pointer(&in.CriticalSyscalls))
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.CriticalSyscallWeight, &out.CriticalSyscallWeight, s); err != nil {
+		return err
+	}
+	out.RuntimeProfiles = in.RuntimeProfiles
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.ProfileRefreshSeconds, &out.ProfileRefreshSeconds, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -585,6 +1356,15 @@ func autoConvert_config_SySchedArgs_To_v1_SySchedArgs(in *config.SySchedArgs, ou
 	if err := metav1.Convert_string_To_Pointer_string(&in.DefaultProfileName, &out.DefaultProfileName, s); err != nil {
 		return err
 	}
+	out.SimilarityMetric = SySchedSimilarityMetric(in.SimilarityMetric)
+	out.CriticalSyscalls = *(*[]string)(unsafe.Pointer(&in.CriticalSyscalls))
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.CriticalSyscallWeight, &out.CriticalSyscallWeight, s); err != nil {
+		return err
+	}
+	out.RuntimeProfiles = in.RuntimeProfiles
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.ProfileRefreshSeconds, &out.ProfileRefreshSeconds, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -604,6 +1384,15 @@ func autoConvert_v1_TargetLoadPackingArgs_To_config_TargetLoadPackingArgs(in *Ta
 	if err := metav1.Convert_Pointer_int64_To_int64(&in.TargetUtilization, &out.TargetUtilization, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.GPUTargetUtilization, &out.GPUTargetUtilization, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.ClusterPressureThreshold, &out.ClusterPressureThreshold, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.ClusterPressureHysteresis, &out.ClusterPressureHysteresis, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -623,6 +1412,15 @@ func autoConvert_config_TargetLoadPackingArgs_To_v1_TargetLoadPackingArgs(in *co
 	if err := metav1.Convert_int64_To_Pointer_int64(&in.TargetUtilization, &out.TargetUtilization, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.GPUTargetUtilization, &out.GPUTargetUtilization, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.ClusterPressureThreshold, &out.ClusterPressureThreshold, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.ClusterPressureHysteresis, &out.ClusterPressureHysteresis, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -631,6 +1429,48 @@ func Convert_config_TargetLoadPackingArgs_To_v1_TargetLoadPackingArgs(in *config
 	return autoConvert_config_TargetLoadPackingArgs_To_v1_TargetLoadPackingArgs(in, out, s)
 }
 
+func autoConvert_v1_ThermalAwareArgs_To_config_ThermalAwareArgs(in *ThermalAwareArgs, out *config.ThermalAwareArgs, s conversion.Scope) error {
+	if err := Convert_v1_TrimaranSpec_To_config_TrimaranSpec(&in.TrimaranSpec, &out.TrimaranSpec, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_float64_To_float64(&in.SafeTemperature, &out.SafeTemperature, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_float64_To_float64(&in.MaxTemperature, &out.MaxTemperature, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.CPUHeavyMilliCores, &out.CPUHeavyMilliCores, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_ThermalAwareArgs_To_config_ThermalAwareArgs is an autogenerated conversion function.
+func Convert_v1_ThermalAwareArgs_To_config_ThermalAwareArgs(in *ThermalAwareArgs, out *config.ThermalAwareArgs, s conversion.Scope) error {
+	return autoConvert_v1_ThermalAwareArgs_To_config_ThermalAwareArgs(in, out, s)
+}
+
+func autoConvert_config_ThermalAwareArgs_To_v1_ThermalAwareArgs(in *config.ThermalAwareArgs, out *ThermalAwareArgs, s conversion.Scope) error {
+	if err := Convert_config_TrimaranSpec_To_v1_TrimaranSpec(&in.TrimaranSpec, &out.TrimaranSpec, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_float64_To_Pointer_float64(&in.SafeTemperature, &out.SafeTemperature, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_float64_To_Pointer_float64(&in.MaxTemperature, &out.MaxTemperature, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.CPUHeavyMilliCores, &out.CPUHeavyMilliCores, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_ThermalAwareArgs_To_v1_ThermalAwareArgs is an autogenerated conversion function.
+func Convert_config_ThermalAwareArgs_To_v1_ThermalAwareArgs(in *config.ThermalAwareArgs, out *ThermalAwareArgs, s conversion.Scope) error {
+	return autoConvert_config_ThermalAwareArgs_To_v1_ThermalAwareArgs(in, out, s)
+}
+
 func autoConvert_v1_TopologicalSortArgs_To_config_TopologicalSortArgs(in *TopologicalSortArgs, out *config.TopologicalSortArgs, s conversion.Scope) error {
 	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
 	return nil
@@ -658,6 +1498,17 @@ func autoConvert_v1_TrimaranSpec_To_config_TrimaranSpec(in *TrimaranSpec, out *c
 	if err := metav1.Convert_Pointer_string_To_string(&in.WatcherAddress, &out.WatcherAddress, s); err != nil {
 		return err
 	}
+	out.WatcherAddresses = *(*[]string)(unsafe.Pointer(&in.WatcherAddresses))
+	if in.AutoTune != nil {
+		in, out := &in.AutoTune, &out.AutoTune
+		*out = new(config.AutoTuneSpec)
+		if err := Convert_v1_AutoTuneSpec_To_config_AutoTuneSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AutoTune = nil
+	}
+	out.ExcludedNodes = (*config.NodeExclusionSpec)(unsafe.Pointer(in.ExcludedNodes))
 	return nil
 }
 
@@ -673,6 +1524,17 @@ func autoConvert_config_TrimaranSpec_To_v1_TrimaranSpec(in *config.TrimaranSpec,
 	if err := metav1.Convert_string_To_Pointer_string(&in.WatcherAddress, &out.WatcherAddress, s); err != nil {
 		return err
 	}
+	out.WatcherAddresses = *(*[]string)(unsafe.Pointer(&in.WatcherAddresses))
+	if in.AutoTune != nil {
+		in, out := &in.AutoTune, &out.AutoTune
+		*out = new(AutoTuneSpec)
+		if err := Convert_config_AutoTuneSpec_To_v1_AutoTuneSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AutoTune = nil
+	}
+	out.ExcludedNodes = (*NodeExclusionSpec)(unsafe.Pointer(in.ExcludedNodes))
 	return nil
 }
 
@@ -694,6 +1556,48 @@ func autoConvert_v1_NetworkCostArgs_To_config_NetworkCostArgs(in *NetworkCostArg
 	if err := metav1.Convert_Pointer_string_To_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.ClusterName, &out.ClusterName, s); err != nil {
+		return err
+	}
+	out.DependencyCostMode = config.DependencyCostMode(in.DependencyCostMode)
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.CostCap, &out.CostCap, s); err != nil {
+		return err
+	}
+	out.CostScaling = config.CostScalingMode(in.CostScaling)
+	if err := metav1.Convert_Pointer_string_To_string(&in.MetricsProviderAddress, &out.MetricsProviderAddress, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.MetricsQuery, &out.MetricsQuery, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MetricsRefreshIntervalSeconds, &out.MetricsRefreshIntervalSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MeasuredCostWeight, &out.MeasuredCostWeight, s); err != nil {
+		return err
+	}
+	out.ScoringMode = config.NetworkCostScoringMode(in.ScoringMode)
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MaxCost, &out.MaxCost, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.SameZoneCost, &out.SameZoneCost, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.SameHostnameCost, &out.SameHostnameCost, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.ZoneCostMultiplier, &out.ZoneCostMultiplier, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.RegionCostMultiplier, &out.RegionCostMultiplier, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.ClusterCostMultiplier, &out.ClusterCostMultiplier, s); err != nil {
+		return err
+	}
+	out.AllowedNamespaces = *(*[]string)(unsafe.Pointer(&in.AllowedNamespaces))
+	out.DeniedNamespaces = *(*[]string)(unsafe.Pointer(&in.DeniedNamespaces))
+	out.UnlabeledNodePolicy = config.UnlabeledNodePolicy(in.UnlabeledNodePolicy)
 	return nil
 }
 
@@ -710,6 +1614,48 @@ func autoConvert_config_NetworkCostArgs_To_v1_NetworkCostArgs(in *config.Network
 	if err := metav1.Convert_string_To_Pointer_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.ClusterName, &out.ClusterName, s); err != nil {
+		return err
+	}
+	out.DependencyCostMode = DependencyCostMode(in.DependencyCostMode)
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.CostCap, &out.CostCap, s); err != nil {
+		return err
+	}
+	out.CostScaling = CostScalingMode(in.CostScaling)
+	if err := metav1.Convert_string_To_Pointer_string(&in.MetricsProviderAddress, &out.MetricsProviderAddress, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.MetricsQuery, &out.MetricsQuery, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MetricsRefreshIntervalSeconds, &out.MetricsRefreshIntervalSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MeasuredCostWeight, &out.MeasuredCostWeight, s); err != nil {
+		return err
+	}
+	out.ScoringMode = NetworkCostScoringMode(in.ScoringMode)
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MaxCost, &out.MaxCost, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.SameZoneCost, &out.SameZoneCost, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.SameHostnameCost, &out.SameHostnameCost, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.ZoneCostMultiplier, &out.ZoneCostMultiplier, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.RegionCostMultiplier, &out.RegionCostMultiplier, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.ClusterCostMultiplier, &out.ClusterCostMultiplier, s); err != nil {
+		return err
+	}
+	out.AllowedNamespaces = *(*[]string)(unsafe.Pointer(&in.AllowedNamespaces))
+	out.DeniedNamespaces = *(*[]string)(unsafe.Pointer(&in.DeniedNamespaces))
+	out.UnlabeledNodePolicy = UnlabeledNodePolicy(in.UnlabeledNodePolicy)
 	return nil
 }
 
@@ -23,10 +23,178 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	configv1 "k8s.io/kube-scheduler/config/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoTuneSpec) DeepCopyInto(out *AutoTuneSpec) {
+	*out = *in
+	if in.ConfigMapNamespace != nil {
+		in, out := &in.ConfigMapNamespace, &out.ConfigMapNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigMapName != nil {
+		in, out := &in.ConfigMapName, &out.ConfigMapName
+		*out = new(string)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LearningRate != nil {
+		in, out := &in.LearningRate, &out.LearningRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxAdjustmentPercent != nil {
+		in, out := &in.MaxAdjustmentPercent, &out.MaxAdjustmentPercent
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoTuneSpec.
+func (in *AutoTuneSpec) DeepCopy() *AutoTuneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoTuneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BorrowingQueueSpec) DeepCopyInto(out *BorrowingQueueSpec) {
+	*out = *in
+	if in.AgingSeconds != nil {
+		in, out := &in.AgingSeconds, &out.AgingSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BorrowingQueueSpec.
+func (in *BorrowingQueueSpec) DeepCopy() *BorrowingQueueSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BorrowingQueueSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheIsolationArgs) DeepCopyInto(out *CacheIsolationArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.LatencySensitiveSelector != nil {
+		in, out := &in.LatencySensitiveSelector, &out.LatencySensitiveSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CacheHungrySelector != nil {
+		in, out := &in.CacheHungrySelector, &out.CacheHungrySelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LLCOccupancyWeight != nil {
+		in, out := &in.LLCOccupancyWeight, &out.LLCOccupancyWeight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemoryBandwidthWeight != nil {
+		in, out := &in.MemoryBandwidthWeight, &out.MemoryBandwidthWeight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CacheHungryPodPenalty != nil {
+		in, out := &in.CacheHungryPodPenalty, &out.CacheHungryPodPenalty
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MetricsMaxAgeSeconds != nil {
+		in, out := &in.MetricsMaxAgeSeconds, &out.MetricsMaxAgeSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheIsolationArgs.
+func (in *CacheIsolationArgs) DeepCopy() *CacheIsolationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheIsolationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CacheIsolationArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacitySchedulingArgs) DeepCopyInto(out *CapacitySchedulingArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.GPUSlicing != nil {
+		in, out := &in.GPUSlicing, &out.GPUSlicing
+		*out = new(GPUSlicingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BorrowingQueue != nil {
+		in, out := &in.BorrowingQueue, &out.BorrowingQueue
+		*out = new(BorrowingQueueSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreemptionProtectionSeconds != nil {
+		in, out := &in.PreemptionProtectionSeconds, &out.PreemptionProtectionSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PreReclaim != nil {
+		in, out := &in.PreReclaim, &out.PreReclaim
+		*out = new(PreReclaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacitySchedulingArgs.
+func (in *CapacitySchedulingArgs) DeepCopy() *CapacitySchedulingArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacitySchedulingArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CapacitySchedulingArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CoschedulingArgs) DeepCopyInto(out *CoschedulingArgs) {
 	*out = *in
@@ -41,6 +209,36 @@ func (in *CoschedulingArgs) DeepCopyInto(out *CoschedulingArgs) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.GangAdmissionWindowSeconds != nil {
+		in, out := &in.GangAdmissionWindowSeconds, &out.GangAdmissionWindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxWaitingPodGroupsPerNamespace != nil {
+		in, out := &in.MaxWaitingPodGroupsPerNamespace, &out.MaxWaitingPodGroupsPerNamespace
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WaitingMembersStatusIntervalSeconds != nil {
+		in, out := &in.WaitingMembersStatusIntervalSeconds, &out.WaitingMembersStatusIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CheckpointTimeoutSeconds != nil {
+		in, out := &in.CheckpointTimeoutSeconds, &out.CheckpointTimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RetryPenaltySeconds != nil {
+		in, out := &in.RetryPenaltySeconds, &out.RetryPenaltySeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ScaleUpPermitExtensionSeconds != nil {
+		in, out := &in.ScaleUpPermitExtensionSeconds, &out.ScaleUpPermitExtensionSeconds
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -62,6 +260,128 @@ func (in *CoschedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CriticalReserveArgs) DeepCopyInto(out *CriticalReserveArgs) {
+	*out = *in
+	if in.ReservedResources != nil {
+		in, out := &in.ReservedResources, &out.ReservedResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MinCriticalPriority != nil {
+		in, out := &in.MinCriticalPriority, &out.MinCriticalPriority
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CriticalReserveArgs.
+func (in *CriticalReserveArgs) DeepCopy() *CriticalReserveArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(CriticalReserveArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CriticalReserveArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeadlineAwareArgs) DeepCopyInto(out *DeadlineAwareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.UrgencyThresholdSeconds != nil {
+		in, out := &in.UrgencyThresholdSeconds, &out.UrgencyThresholdSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeadlineAwareArgs.
+func (in *DeadlineAwareArgs) DeepCopy() *DeadlineAwareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(DeadlineAwareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeadlineAwareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DominantResourceFairnessArgs) DeepCopyInto(out *DominantResourceFairnessArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DominantResourceFairnessArgs.
+func (in *DominantResourceFairnessArgs) DeepCopy() *DominantResourceFairnessArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(DominantResourceFairnessArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DominantResourceFairnessArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUSlicingSpec) DeepCopyInto(out *GPUSlicingSpec) {
+	*out = *in
+	if in.SlicesPerGPU != nil {
+		in, out := &in.SlicesPerGPU, &out.SlicesPerGPU
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TimeSlicedResourceName != nil {
+		in, out := &in.TimeSlicedResourceName, &out.TimeSlicedResourceName
+		*out = new(string)
+		**out = **in
+	}
+	if in.SlicesPerTimeSlicedReplica != nil {
+		in, out := &in.SlicesPerTimeSlicedReplica, &out.SlicesPerTimeSlicedReplica
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUSlicingSpec.
+func (in *GPUSlicingSpec) DeepCopy() *GPUSlicingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUSlicingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadVariationRiskBalancingArgs) DeepCopyInto(out *LoadVariationRiskBalancingArgs) {
 	*out = *in
@@ -77,6 +397,30 @@ func (in *LoadVariationRiskBalancingArgs) DeepCopyInto(out *LoadVariationRiskBal
 		*out = new(float64)
 		**out = **in
 	}
+	if in.WarmUpSeconds != nil {
+		in, out := &in.WarmUpSeconds, &out.WarmUpSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WarmUpDiscount != nil {
+		in, out := &in.WarmUpDiscount, &out.WarmUpDiscount
+		*out = new(float64)
+		**out = **in
+	}
+	if in.NamespaceRiskMultipliers != nil {
+		in, out := &in.NamespaceRiskMultipliers, &out.NamespaceRiskMultipliers
+		*out = make(map[string]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PriorityClassRiskMultipliers != nil {
+		in, out := &in.PriorityClassRiskMultipliers, &out.PriorityClassRiskMultipliers
+		*out = make(map[string]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -115,6 +459,11 @@ func (in *LowRiskOverCommitmentArgs) DeepCopyInto(out *LowRiskOverCommitmentArgs
 			(*out)[key] = val
 		}
 	}
+	if in.UseVPARecommendations != nil {
+		in, out := &in.UseVPARecommendations, &out.UseVPARecommendations
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -186,6 +535,16 @@ func (in *NetworkOverheadArgs) DeepCopyInto(out *NetworkOverheadArgs) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ZoneViolationWeight != nil {
+		in, out := &in.ZoneViolationWeight, &out.ZoneViolationWeight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RegionViolationWeight != nil {
+		in, out := &in.RegionViolationWeight, &out.RegionViolationWeight
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -231,6 +590,76 @@ func (in *NetworkCostArgs) DeepCopyInto(out *NetworkCostArgs) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ClusterName != nil {
+		in, out := &in.ClusterName, &out.ClusterName
+		*out = new(string)
+		**out = **in
+	}
+	if in.CostCap != nil {
+		in, out := &in.CostCap, &out.CostCap
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MetricsProviderAddress != nil {
+		in, out := &in.MetricsProviderAddress, &out.MetricsProviderAddress
+		*out = new(string)
+		**out = **in
+	}
+	if in.MetricsQuery != nil {
+		in, out := &in.MetricsQuery, &out.MetricsQuery
+		*out = new(string)
+		**out = **in
+	}
+	if in.MetricsRefreshIntervalSeconds != nil {
+		in, out := &in.MetricsRefreshIntervalSeconds, &out.MetricsRefreshIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MeasuredCostWeight != nil {
+		in, out := &in.MeasuredCostWeight, &out.MeasuredCostWeight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxCost != nil {
+		in, out := &in.MaxCost, &out.MaxCost
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SameZoneCost != nil {
+		in, out := &in.SameZoneCost, &out.SameZoneCost
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SameHostnameCost != nil {
+		in, out := &in.SameHostnameCost, &out.SameHostnameCost
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ZoneCostMultiplier != nil {
+		in, out := &in.ZoneCostMultiplier, &out.ZoneCostMultiplier
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RegionCostMultiplier != nil {
+		in, out := &in.RegionCostMultiplier, &out.RegionCostMultiplier
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ClusterCostMultiplier != nil {
+		in, out := &in.ClusterCostMultiplier, &out.ClusterCostMultiplier
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedNamespaces != nil {
+		in, out := &in.DeniedNamespaces, &out.DeniedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -252,6 +681,34 @@ func (in *NetworkCostArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeExclusionSpec) DeepCopyInto(out *NodeExclusionSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeExclusionSpec.
+func (in *NodeExclusionSpec) DeepCopy() *NodeExclusionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeExclusionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeResourceTopologyCache) DeepCopyInto(out *NodeResourceTopologyCache) {
 	*out = *in
@@ -358,6 +815,41 @@ func (in *NodeResourcesAllocatableArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeResourcesFragmentationArgs) DeepCopyInto(out *NodeResourcesFragmentationArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]configv1.ResourceSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Shape != nil {
+		in, out := &in.Shape, &out.Shape
+		*out = make([]configv1.UtilizationShapePoint, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourcesFragmentationArgs.
+func (in *NodeResourcesFragmentationArgs) DeepCopy() *NodeResourcesFragmentationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeResourcesFragmentationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeResourcesFragmentationArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PeaksArgs) DeepCopyInto(out *PeaksArgs) {
 	*out = *in
@@ -390,6 +882,46 @@ func (in *PeaksArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodStateArgs) DeepCopyInto(out *PodStateArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.TerminatingWeight != nil {
+		in, out := &in.TerminatingWeight, &out.TerminatingWeight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NominatedWeight != nil {
+		in, out := &in.NominatedWeight, &out.NominatedWeight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NotReadyWeight != nil {
+		in, out := &in.NotReadyWeight, &out.NotReadyWeight
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodStateArgs.
+func (in *PodStateArgs) DeepCopy() *PodStateArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(PodStateArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PodStateArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PowerModel) DeepCopyInto(out *PowerModel) {
 	*out = *in
@@ -406,6 +938,37 @@ func (in *PowerModel) DeepCopy() *PowerModel {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreReclaimSpec) DeepCopyInto(out *PreReclaimSpec) {
+	*out = *in
+	if in.LeadTimeSeconds != nil {
+		in, out := &in.LeadTimeSeconds, &out.LeadTimeSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxEvictionsPerInterval != nil {
+		in, out := &in.MaxEvictionsPerInterval, &out.MaxEvictionsPerInterval
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreReclaimSpec.
+func (in *PreReclaimSpec) DeepCopy() *PreReclaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PreReclaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreemptionTolerationArgs) DeepCopyInto(out *PreemptionTolerationArgs) {
 	*out = *in
@@ -441,6 +1004,51 @@ func (in *PreemptionTolerationArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueLengthArgs) DeepCopyInto(out *QueueLengthArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.PrometheusAddress != nil {
+		in, out := &in.PrometheusAddress, &out.PrometheusAddress
+		*out = new(string)
+		**out = **in
+	}
+	if in.BacklogQuery != nil {
+		in, out := &in.BacklogQuery, &out.BacklogQuery
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConsumerGroupLabel != nil {
+		in, out := &in.ConsumerGroupLabel, &out.ConsumerGroupLabel
+		*out = new(string)
+		**out = **in
+	}
+	if in.MetricsUpdateIntervalSeconds != nil {
+		in, out := &in.MetricsUpdateIntervalSeconds, &out.MetricsUpdateIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueLengthArgs.
+func (in *QueueLengthArgs) DeepCopy() *QueueLengthArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueLengthArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QueueLengthArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScoringStrategy) DeepCopyInto(out *ScoringStrategy) {
 	*out = *in
@@ -462,6 +1070,61 @@ func (in *ScoringStrategy) DeepCopy() *ScoringStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMeshLatencyArgs) DeepCopyInto(out *ServiceMeshLatencyArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WeightsName != nil {
+		in, out := &in.WeightsName, &out.WeightsName
+		*out = new(string)
+		**out = **in
+	}
+	if in.NetworkTopologyName != nil {
+		in, out := &in.NetworkTopologyName, &out.NetworkTopologyName
+		*out = new(string)
+		**out = **in
+	}
+	if in.PrometheusAddress != nil {
+		in, out := &in.PrometheusAddress, &out.PrometheusAddress
+		*out = new(string)
+		**out = **in
+	}
+	if in.LatencyQuery != nil {
+		in, out := &in.LatencyQuery, &out.LatencyQuery
+		*out = new(string)
+		**out = **in
+	}
+	if in.MetricsUpdateIntervalSeconds != nil {
+		in, out := &in.MetricsUpdateIntervalSeconds, &out.MetricsUpdateIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshLatencyArgs.
+func (in *ServiceMeshLatencyArgs) DeepCopy() *ServiceMeshLatencyArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMeshLatencyArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceMeshLatencyArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SySchedArgs) DeepCopyInto(out *SySchedArgs) {
 	*out = *in
@@ -476,6 +1139,21 @@ func (in *SySchedArgs) DeepCopyInto(out *SySchedArgs) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CriticalSyscalls != nil {
+		in, out := &in.CriticalSyscalls, &out.CriticalSyscalls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CriticalSyscallWeight != nil {
+		in, out := &in.CriticalSyscallWeight, &out.CriticalSyscallWeight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ProfileRefreshSeconds != nil {
+		in, out := &in.ProfileRefreshSeconds, &out.ProfileRefreshSeconds
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -519,6 +1197,21 @@ func (in *TargetLoadPackingArgs) DeepCopyInto(out *TargetLoadPackingArgs) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.GPUTargetUtilization != nil {
+		in, out := &in.GPUTargetUtilization, &out.GPUTargetUtilization
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ClusterPressureThreshold != nil {
+		in, out := &in.ClusterPressureThreshold, &out.ClusterPressureThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ClusterPressureHysteresis != nil {
+		in, out := &in.ClusterPressureHysteresis, &out.ClusterPressureHysteresis
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -540,6 +1233,47 @@ func (in *TargetLoadPackingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThermalAwareArgs) DeepCopyInto(out *ThermalAwareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
+	if in.SafeTemperature != nil {
+		in, out := &in.SafeTemperature, &out.SafeTemperature
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxTemperature != nil {
+		in, out := &in.MaxTemperature, &out.MaxTemperature
+		*out = new(float64)
+		**out = **in
+	}
+	if in.CPUHeavyMilliCores != nil {
+		in, out := &in.CPUHeavyMilliCores, &out.CPUHeavyMilliCores
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThermalAwareArgs.
+func (in *ThermalAwareArgs) DeepCopy() *ThermalAwareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(ThermalAwareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThermalAwareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopologicalSortArgs) DeepCopyInto(out *TopologicalSortArgs) {
 	*out = *in
@@ -611,6 +1345,21 @@ func (in *TrimaranSpec) DeepCopyInto(out *TrimaranSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.WatcherAddresses != nil {
+		in, out := &in.WatcherAddresses, &out.WatcherAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoTune != nil {
+		in, out := &in.AutoTune, &out.AutoTune
+		*out = new(AutoTuneSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludedNodes != nil {
+		in, out := &in.ExcludedNodes, &out.ExcludedNodes
+		*out = new(NodeExclusionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
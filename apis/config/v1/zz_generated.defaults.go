@@ -29,7 +29,11 @@ import (
 // Public to allow building arbitrary schemes.
 // All generated defaulters are covering - they call all nested defaulters.
 func RegisterDefaults(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&CacheIsolationArgs{}, func(obj interface{}) { SetObjectDefaults_CacheIsolationArgs(obj.(*CacheIsolationArgs)) })
+	scheme.AddTypeDefaultingFunc(&CapacitySchedulingArgs{}, func(obj interface{}) { SetObjectDefaults_CapacitySchedulingArgs(obj.(*CapacitySchedulingArgs)) })
 	scheme.AddTypeDefaultingFunc(&CoschedulingArgs{}, func(obj interface{}) { SetObjectDefaults_CoschedulingArgs(obj.(*CoschedulingArgs)) })
+	scheme.AddTypeDefaultingFunc(&CriticalReserveArgs{}, func(obj interface{}) { SetObjectDefaults_CriticalReserveArgs(obj.(*CriticalReserveArgs)) })
+	scheme.AddTypeDefaultingFunc(&DeadlineAwareArgs{}, func(obj interface{}) { SetObjectDefaults_DeadlineAwareArgs(obj.(*DeadlineAwareArgs)) })
 	scheme.AddTypeDefaultingFunc(&LoadVariationRiskBalancingArgs{}, func(obj interface{}) {
 		SetObjectDefaults_LoadVariationRiskBalancingArgs(obj.(*LoadVariationRiskBalancingArgs))
 	})
@@ -42,18 +46,41 @@ func RegisterDefaults(scheme *runtime.Scheme) error {
 	scheme.AddTypeDefaultingFunc(&NodeResourcesAllocatableArgs{}, func(obj interface{}) {
 		SetObjectDefaults_NodeResourcesAllocatableArgs(obj.(*NodeResourcesAllocatableArgs))
 	})
+	scheme.AddTypeDefaultingFunc(&NodeResourcesFragmentationArgs{}, func(obj interface{}) {
+		SetObjectDefaults_NodeResourcesFragmentationArgs(obj.(*NodeResourcesFragmentationArgs))
+	})
+	scheme.AddTypeDefaultingFunc(&PodStateArgs{}, func(obj interface{}) { SetObjectDefaults_PodStateArgs(obj.(*PodStateArgs)) })
 	scheme.AddTypeDefaultingFunc(&PreemptionTolerationArgs{}, func(obj interface{}) { SetObjectDefaults_PreemptionTolerationArgs(obj.(*PreemptionTolerationArgs)) })
+	scheme.AddTypeDefaultingFunc(&QueueLengthArgs{}, func(obj interface{}) { SetObjectDefaults_QueueLengthArgs(obj.(*QueueLengthArgs)) })
+	scheme.AddTypeDefaultingFunc(&ServiceMeshLatencyArgs{}, func(obj interface{}) { SetObjectDefaults_ServiceMeshLatencyArgs(obj.(*ServiceMeshLatencyArgs)) })
 	scheme.AddTypeDefaultingFunc(&SySchedArgs{}, func(obj interface{}) { SetObjectDefaults_SySchedArgs(obj.(*SySchedArgs)) })
 	scheme.AddTypeDefaultingFunc(&TargetLoadPackingArgs{}, func(obj interface{}) { SetObjectDefaults_TargetLoadPackingArgs(obj.(*TargetLoadPackingArgs)) })
+	scheme.AddTypeDefaultingFunc(&ThermalAwareArgs{}, func(obj interface{}) { SetObjectDefaults_ThermalAwareArgs(obj.(*ThermalAwareArgs)) })
 	scheme.AddTypeDefaultingFunc(&TopologicalSortArgs{}, func(obj interface{}) { SetObjectDefaults_TopologicalSortArgs(obj.(*TopologicalSortArgs)) })
 	scheme.AddTypeDefaultingFunc(&TopologicalcnSortArgs{}, func(obj interface{}) { SetObjectDefaults_TopologicalcnSortArgs(obj.(*TopologicalcnSortArgs)) })//Amira
 	return nil
 }
 
+func SetObjectDefaults_CacheIsolationArgs(in *CacheIsolationArgs) {
+	SetDefaults_CacheIsolationArgs(in)
+}
+
+func SetObjectDefaults_CapacitySchedulingArgs(in *CapacitySchedulingArgs) {
+	SetDefaults_CapacitySchedulingArgs(in)
+}
+
 func SetObjectDefaults_CoschedulingArgs(in *CoschedulingArgs) {
 	SetDefaults_CoschedulingArgs(in)
 }
 
+func SetObjectDefaults_CriticalReserveArgs(in *CriticalReserveArgs) {
+	SetDefaults_CriticalReserveArgs(in)
+}
+
+func SetObjectDefaults_DeadlineAwareArgs(in *DeadlineAwareArgs) {
+	SetDefaults_DeadlineAwareArgs(in)
+}
+
 func SetObjectDefaults_LoadVariationRiskBalancingArgs(in *LoadVariationRiskBalancingArgs) {
 	SetDefaults_LoadVariationRiskBalancingArgs(in)
 }
@@ -77,10 +104,26 @@ func SetObjectDefaults_NodeResourcesAllocatableArgs(in *NodeResourcesAllocatable
 	SetDefaults_NodeResourcesAllocatableArgs(in)
 }
 
+func SetObjectDefaults_NodeResourcesFragmentationArgs(in *NodeResourcesFragmentationArgs) {
+	SetDefaults_NodeResourcesFragmentationArgs(in)
+}
+
+func SetObjectDefaults_PodStateArgs(in *PodStateArgs) {
+	SetDefaults_PodStateArgs(in)
+}
+
 func SetObjectDefaults_PreemptionTolerationArgs(in *PreemptionTolerationArgs) {
 	SetDefaults_PreemptionTolerationArgs(in)
 }
 
+func SetObjectDefaults_QueueLengthArgs(in *QueueLengthArgs) {
+	SetDefaults_QueueLengthArgs(in)
+}
+
+func SetObjectDefaults_ServiceMeshLatencyArgs(in *ServiceMeshLatencyArgs) {
+	SetDefaults_ServiceMeshLatencyArgs(in)
+}
+
 func SetObjectDefaults_SySchedArgs(in *SySchedArgs) {
 	SetDefaults_SySchedArgs(in)
 }
@@ -89,6 +132,10 @@ func SetObjectDefaults_TargetLoadPackingArgs(in *TargetLoadPackingArgs) {
 	SetDefaults_TargetLoadPackingArgs(in)
 }
 
+func SetObjectDefaults_ThermalAwareArgs(in *ThermalAwareArgs) {
+	SetDefaults_ThermalAwareArgs(in)
+}
+
 func SetObjectDefaults_TopologicalSortArgs(in *TopologicalSortArgs) {
 	SetDefaults_TopologicalSortArgs(in)
 }
@@ -30,6 +30,7 @@ var validScoringStrategy = sets.NewString(
 	string(config.BalancedAllocation),
 	string(config.LeastAllocated),
 	string(config.LeastNUMANodes),
+	string(config.LeastNUMADistance),
 )
 
 func ValidateNodeResourceTopologyMatchArgs(path *field.Path, args *config.NodeResourceTopologyMatchArgs) error {
@@ -39,6 +39,14 @@ func TestValidateNodeResourceTopologyMatchArgs(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "correct config, LeastNUMADistance ScoringStrategy type",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastNUMADistance,
+				},
+			},
+		},
 		{
 			description: "incorrect config, wrong ScoringStrategy type",
 			args: &config.NodeResourceTopologyMatchArgs{
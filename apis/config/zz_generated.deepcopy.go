@@ -23,10 +23,118 @@ package config
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	apisconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoTuneSpec) DeepCopyInto(out *AutoTuneSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoTuneSpec.
+func (in *AutoTuneSpec) DeepCopy() *AutoTuneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoTuneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BorrowingQueueSpec) DeepCopyInto(out *BorrowingQueueSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BorrowingQueueSpec.
+func (in *BorrowingQueueSpec) DeepCopy() *BorrowingQueueSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BorrowingQueueSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheIsolationArgs) DeepCopyInto(out *CacheIsolationArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.LatencySensitiveSelector != nil {
+		in, out := &in.LatencySensitiveSelector, &out.LatencySensitiveSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CacheHungrySelector != nil {
+		in, out := &in.CacheHungrySelector, &out.CacheHungrySelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheIsolationArgs.
+func (in *CacheIsolationArgs) DeepCopy() *CacheIsolationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheIsolationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CacheIsolationArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacitySchedulingArgs) DeepCopyInto(out *CapacitySchedulingArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.GPUSlicing != nil {
+		in, out := &in.GPUSlicing, &out.GPUSlicing
+		*out = new(GPUSlicingSpec)
+		**out = **in
+	}
+	if in.BorrowingQueue != nil {
+		in, out := &in.BorrowingQueue, &out.BorrowingQueue
+		*out = new(BorrowingQueueSpec)
+		**out = **in
+	}
+	if in.PreReclaim != nil {
+		in, out := &in.PreReclaim, &out.PreReclaim
+		*out = new(PreReclaimSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacitySchedulingArgs.
+func (in *CapacitySchedulingArgs) DeepCopy() *CapacitySchedulingArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacitySchedulingArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CapacitySchedulingArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CoschedulingArgs) DeepCopyInto(out *CoschedulingArgs) {
 	*out = *in
@@ -52,11 +160,122 @@ func (in *CoschedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CriticalReserveArgs) DeepCopyInto(out *CriticalReserveArgs) {
+	*out = *in
+	if in.ReservedResources != nil {
+		in, out := &in.ReservedResources, &out.ReservedResources
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CriticalReserveArgs.
+func (in *CriticalReserveArgs) DeepCopy() *CriticalReserveArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(CriticalReserveArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CriticalReserveArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeadlineAwareArgs) DeepCopyInto(out *DeadlineAwareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeadlineAwareArgs.
+func (in *DeadlineAwareArgs) DeepCopy() *DeadlineAwareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(DeadlineAwareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeadlineAwareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DominantResourceFairnessArgs) DeepCopyInto(out *DominantResourceFairnessArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DominantResourceFairnessArgs.
+func (in *DominantResourceFairnessArgs) DeepCopy() *DominantResourceFairnessArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(DominantResourceFairnessArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DominantResourceFairnessArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUSlicingSpec) DeepCopyInto(out *GPUSlicingSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUSlicingSpec.
+func (in *GPUSlicingSpec) DeepCopy() *GPUSlicingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUSlicingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadVariationRiskBalancingArgs) DeepCopyInto(out *LoadVariationRiskBalancingArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.TrimaranSpec = in.TrimaranSpec
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
+	if in.NamespaceRiskMultipliers != nil {
+		in, out := &in.NamespaceRiskMultipliers, &out.NamespaceRiskMultipliers
+		*out = make(map[string]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PriorityClassRiskMultipliers != nil {
+		in, out := &in.PriorityClassRiskMultipliers, &out.PriorityClassRiskMultipliers
+		*out = make(map[string]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -82,7 +301,7 @@ func (in *LoadVariationRiskBalancingArgs) DeepCopyObject() runtime.Object {
 func (in *LowRiskOverCommitmentArgs) DeepCopyInto(out *LowRiskOverCommitmentArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.TrimaranSpec = in.TrimaranSpec
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
 	if in.RiskLimitWeights != nil {
 		in, out := &in.RiskLimitWeights, &out.RiskLimitWeights
 		*out = make(map[v1.ResourceName]float64, len(*in))
@@ -169,6 +388,16 @@ func (in *NetworkCostArgs) DeepCopyInto(out *NetworkCostArgs) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedNamespaces != nil {
+		in, out := &in.DeniedNamespaces, &out.DeniedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -190,6 +419,34 @@ func (in *NetworkCostArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeExclusionSpec) DeepCopyInto(out *NodeExclusionSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeExclusionSpec.
+func (in *NodeExclusionSpec) DeepCopy() *NodeExclusionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeExclusionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeResourceTopologyCache) DeepCopyInto(out *NodeResourceTopologyCache) {
 	*out = *in
@@ -287,6 +544,41 @@ func (in *NodeResourcesAllocatableArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeResourcesFragmentationArgs) DeepCopyInto(out *NodeResourcesFragmentationArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]apisconfig.ResourceSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Shape != nil {
+		in, out := &in.Shape, &out.Shape
+		*out = make([]apisconfig.UtilizationShapePoint, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourcesFragmentationArgs.
+func (in *NodeResourcesFragmentationArgs) DeepCopy() *NodeResourcesFragmentationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeResourcesFragmentationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeResourcesFragmentationArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PeaksArgs) DeepCopyInto(out *PeaksArgs) {
 	*out = *in
@@ -319,6 +611,31 @@ func (in *PeaksArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodStateArgs) DeepCopyInto(out *PodStateArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodStateArgs.
+func (in *PodStateArgs) DeepCopy() *PodStateArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(PodStateArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PodStateArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PowerModel) DeepCopyInto(out *PowerModel) {
 	*out = *in
@@ -335,6 +652,22 @@ func (in *PowerModel) DeepCopy() *PowerModel {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreReclaimSpec) DeepCopyInto(out *PreReclaimSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreReclaimSpec.
+func (in *PreReclaimSpec) DeepCopy() *PreReclaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PreReclaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreemptionTolerationArgs) DeepCopyInto(out *PreemptionTolerationArgs) {
 	*out = *in
@@ -360,6 +693,31 @@ func (in *PreemptionTolerationArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueLengthArgs) DeepCopyInto(out *QueueLengthArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueLengthArgs.
+func (in *QueueLengthArgs) DeepCopy() *QueueLengthArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueLengthArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QueueLengthArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScoringStrategy) DeepCopyInto(out *ScoringStrategy) {
 	*out = *in
@@ -381,10 +739,45 @@ func (in *ScoringStrategy) DeepCopy() *ScoringStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMeshLatencyArgs) DeepCopyInto(out *ServiceMeshLatencyArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshLatencyArgs.
+func (in *ServiceMeshLatencyArgs) DeepCopy() *ServiceMeshLatencyArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMeshLatencyArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceMeshLatencyArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SySchedArgs) DeepCopyInto(out *SySchedArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
+	if in.CriticalSyscalls != nil {
+		in, out := &in.CriticalSyscalls, &out.CriticalSyscalls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -410,7 +803,7 @@ func (in *SySchedArgs) DeepCopyObject() runtime.Object {
 func (in *TargetLoadPackingArgs) DeepCopyInto(out *TargetLoadPackingArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.TrimaranSpec = in.TrimaranSpec
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
 	if in.DefaultRequests != nil {
 		in, out := &in.DefaultRequests, &out.DefaultRequests
 		*out = make(v1.ResourceList, len(*in))
@@ -439,6 +832,32 @@ func (in *TargetLoadPackingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThermalAwareArgs) DeepCopyInto(out *ThermalAwareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThermalAwareArgs.
+func (in *ThermalAwareArgs) DeepCopy() *ThermalAwareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(ThermalAwareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThermalAwareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopologicalSortArgs) DeepCopyInto(out *TopologicalSortArgs) {
 	*out = *in
@@ -508,6 +927,21 @@ func (in *TopologicalcnSortArgs ) DeepCopyObject() runtime.Object {
 func (in *TrimaranSpec) DeepCopyInto(out *TrimaranSpec) {
 	*out = *in
 	out.MetricProvider = in.MetricProvider
+	if in.WatcherAddresses != nil {
+		in, out := &in.WatcherAddresses, &out.WatcherAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoTune != nil {
+		in, out := &in.AutoTune, &out.AutoTune
+		*out = new(AutoTuneSpec)
+		**out = **in
+	}
+	if in.ExcludedNodes != nil {
+		in, out := &in.ExcludedNodes, &out.ExcludedNodes
+		*out = new(NodeExclusionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
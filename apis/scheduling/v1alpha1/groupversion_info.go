@@ -54,6 +54,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ElasticQuotaList{},
 		&PodGroup{},
 		&PodGroupList{},
+		&DataResidencyPolicy{},
+		&DataResidencyPolicyList{},
+		&HostTopology{},
+		&HostTopologyList{},
+		&NodeNFVCapability{},
+		&NodeNFVCapabilityList{},
 	)
 	// AddToGroupVersion allows the serialization of client types like ListOptions.
 	v1.AddToGroupVersion(scheme, SchemeGroupVersion)
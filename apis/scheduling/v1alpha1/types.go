@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	// "sigs.k8s.io/scheduler-plugins/apis/scheduling"
 	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling"
 )
@@ -28,6 +29,7 @@ import (
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:shortName={eq,eqs}
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:metadata:annotations="api-approved.kubernetes.io=https://github.com/kubernetes-sigs/scheduler-plugins/pull/52"
 // +kubebuilder:printcolumn:name="Used",JSONPath=".status.used",type=string,description="Used is the current observed total usage of the resource in the namespace."
@@ -59,6 +61,41 @@ type ElasticQuotaSpec struct {
 	// successfully scheduled pods.
 	// +optional
 	Max v1.ResourceList `json:"max,omitempty" protobuf:"bytes,2,rep,name=max, casttype=ResourceList,castkey=ResourceName"`
+
+	// NodeSelector selects the nodes of a pool dedicated to the quota. When set, Min is only
+	// guaranteed on the selected nodes, other quotas can't use them, and the usage over Min is
+	// borrowed on the shared nodes, i.e. the nodes not dedicated to any quota.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" protobuf:"bytes,3,rep,name=nodeSelector"`
+
+	// Lending are the lending agreements of the quota, each allowing the ElasticQuota of another namespace
+	// to use up to a given amount of the unused min of the quota, ahead of the borrowers sharing the unused min
+	// of all the quotas. The lent resources are given back by preemption once the quota needs them.
+	// +optional
+	Lending []ElasticQuotaLoan `json:"lending,omitempty" protobuf:"bytes,4,rep,name=lending"`
+
+	// BorrowingDecaySeconds decays the pods of the quota using resources beyond its min: every BorrowingDecaySeconds
+	// a pod keeps borrowing, it moves one step ahead of the pods borrowing for a shorter time, regardless of their
+	// priority, in the order the borrowed resources are reclaimed by preemption once other quotas need their min.
+	// The borrowed resources are reclaimed by priority only when not set.
+	// +optional
+	BorrowingDecaySeconds *int32 `json:"borrowingDecaySeconds,omitempty" protobuf:"varint,5,opt,name=borrowingDecaySeconds"`
+
+	// Parent is the namespace of the parent ElasticQuota of the quota in a quota tree. The quota can use the unused
+	// min of its ancestors ahead of the borrowers sharing the unused min of all the quotas, and the usage of the
+	// quota and all its descendants can't exceed the max of each of its ancestors. The borrowed resources are
+	// reclaimed by preemption from the subtrees of the siblings first. The quota is a root when not set.
+	// +optional
+	Parent string `json:"parent,omitempty" protobuf:"bytes,6,opt,name=parent"`
+}
+
+// ElasticQuotaLoan is a lending agreement from an ElasticQuota to the ElasticQuota of another namespace.
+type ElasticQuotaLoan struct {
+	// Borrower is the namespace of the borrowing ElasticQuota.
+	Borrower string `json:"borrower" protobuf:"bytes,1,opt,name=borrower"`
+
+	// Max is the most of the unused min of the lender the borrower can use, for each named resource.
+	Max v1.ResourceList `json:"max" protobuf:"bytes,2,rep,name=max,casttype=ResourceList,castkey=ResourceName"`
 }
 
 // ElasticQuotaStatus defines the observed use.
@@ -111,6 +148,36 @@ const (
 
 	// PodGroupLabel is the default label of coscheduling
 	PodGroupLabel = scheduling.GroupName + "/pod-group"
+
+	// PodGroupNamespaceLabel is the label of the members of a pod group living in another namespace than the
+	// pod group, giving the namespace of the pod group. The namespace of such members must be listed in the
+	// `spec.memberNamespaces` of the pod group.
+	PodGroupNamespaceLabel = scheduling.GroupName + "/pod-group-namespace"
+
+	// PodGroupDisruptionBudgetAnnotation is the annotation of the members of a pod group naming the
+	// PodDisruptionBudget protecting the pod group, set by coscheduling when `spec.maxUnavailable` is set.
+	PodGroupDisruptionBudgetAnnotation = scheduling.GroupName + "/pod-group-disruption-budget"
+
+	// PodGroupLeaderLabel is the label marking the leader of a pod group, set to "true", for the
+	// LeaderSucceeded success policy.
+	PodGroupLeaderLabel = scheduling.GroupName + "/pod-group-leader"
+
+	// PodGroupMemberIndexLabel is the label of the members of a pod group giving their index in the pod group,
+	// for the controllers that neither set the Job completion index nor the StatefulSet pod index. A member
+	// recreated by its controller with the same index keeps the identity of the member it replaces.
+	PodGroupMemberIndexLabel = scheduling.GroupName + "/pod-group-member-index"
+
+	// CheckpointRequestedAnnotation is the annotation coscheduling sets on the members of a pod group, when checkpoint
+	// hooks are enabled, before they are preempted or their pod group is released, with the reason of the checkpoint.
+	CheckpointRequestedAnnotation = scheduling.GroupName + "/checkpoint-requested"
+
+	// CheckpointAcknowledgedAnnotation is the annotation a checkpoint controller sets to "true" on the members of a pod
+	// group once their state is saved, letting coscheduling preempt or release them before the checkpoint timeout.
+	CheckpointAcknowledgedAnnotation = scheduling.GroupName + "/checkpoint-acknowledged"
+
+	// SchedulerCacheFinalizer is the finalizer the controllers add to the PodGroups and ElasticQuotas, so that
+	// the scheduler plugins observe their deletion and flush the state cached for them before they are removed.
+	SchedulerCacheFinalizer = scheduling.GroupName + "/scheduler-cache"
 )
 
 // PodGroup is a collection of Pod; used for batch workload.
@@ -118,6 +185,7 @@ const (
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:shortName={pg,pgs}
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:metadata:annotations="api-approved.kubernetes.io=https://github.com/kubernetes-sigs/scheduler-plugins/pull/50"
 // +kubebuilder:printcolumn:name="Phase",JSONPath=".status.phase",type=string,description="Current phase of PodGroup."
@@ -142,6 +210,26 @@ type PodGroup struct {
 	Status PodGroupStatus `json:"status,omitempty"`
 }
 
+// PodGroupScheduled is the type of the condition of a pod group telling whether minMember members are scheduled
+// on nodes, and if not, why.
+const PodGroupScheduled = "Scheduled"
+
+// These are the reasons of the Scheduled condition of a pod group.
+const (
+	// PodGroupReasonScheduled means minMember members are scheduled on nodes.
+	PodGroupReasonScheduled = "Scheduled"
+
+	// PodGroupReasonInsufficientMembers means fewer than minMember members are created.
+	PodGroupReasonInsufficientMembers = "InsufficientMembers"
+
+	// PodGroupReasonUnschedulable means some members are rejected by the scheduler.
+	PodGroupReasonUnschedulable = "Unschedulable"
+
+	// PodGroupReasonWaiting means some members are not scheduled yet, without being rejected by the scheduler,
+	// e.g. while their siblings are waiting at Permit.
+	PodGroupReasonWaiting = "WaitingForScheduling"
+)
+
 // PodGroupSpec represents the template of a pod group.
 type PodGroupSpec struct {
 	// MinMember defines the minimal number of members/tasks to run the pod group;
@@ -158,6 +246,96 @@ type PodGroupSpec struct {
 
 	// ScheduleTimeoutSeconds defines the maximal time of members/tasks to wait before run the pod group;
 	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty"`
+
+	// Backoff defines how long the scheduler backs off the pod group after its scheduling fails, growing
+	// with its consecutive failures. Defaults to the podGroupBackoffSeconds of the Coscheduling plugin.
+	// +optional
+	Backoff *PodGroupBackoffPolicy `json:"backoff,omitempty"`
+
+	// ProgressDeadlineSeconds defines the maximal time for the pod group to make progress once its
+	// first member waits for the quorum; if fewer than progressMinPercentage of minMember members have
+	// been assigned by then, the scheduler releases the whole pod group and backs it off.
+	// It is distinct from scheduleTimeoutSeconds, which bounds the wait of each member.
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// ProgressMinPercentage defines the percentage of minMember members to be assigned within
+	// progressDeadlineSeconds. Defaults to 100.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ProgressMinPercentage *int32 `json:"progressMinPercentage,omitempty"`
+
+	// MaxUnavailable, if set, makes the PodGroup controller maintain a PodDisruptionBudget named after
+	// the pod group and covering its members, allowing at most maxUnavailable members to be evicted at
+	// once. It can be an absolute number or a percentage of the members.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// SuccessPolicy defines when the pod group is Finished or Failed, from the phases of its members.
+	// Defaults to the MinMember mode.
+	// +optional
+	SuccessPolicy *PodGroupSuccessPolicy `json:"successPolicy,omitempty"`
+
+	// MemberNamespaces lists the namespaces, besides the one of the pod group, whose pods may be members
+	// of the pod group, e.g. for pipelines whose producer and consumer components live in different
+	// namespaces but must start together. Such members are labeled with PodGroupNamespaceLabel, and the
+	// service account of each of them must be allowed to `use` the pod group.
+	// +optional
+	MemberNamespaces []string `json:"memberNamespaces,omitempty"`
+}
+
+// PodGroupSuccessPolicyMode is the mode of a success policy.
+// +kubebuilder:validation:Enum=MinMember;AllSucceeded;LeaderSucceeded;MinSucceeded
+type PodGroupSuccessPolicyMode string
+
+const (
+	// SuccessPolicyMinMember finishes the pod group once minMember members succeeded, and fails it once
+	// a member failed while minMember members are running or completed.
+	SuccessPolicyMinMember PodGroupSuccessPolicyMode = "MinMember"
+
+	// SuccessPolicyAllSucceeded finishes the pod group once all its members succeeded, and fails it once
+	// any member failed.
+	SuccessPolicyAllSucceeded PodGroupSuccessPolicyMode = "AllSucceeded"
+
+	// SuccessPolicyLeaderSucceeded finishes the pod group once its leader, the member labeled with
+	// PodGroupLeaderLabel, succeeded, and fails it once its leader failed.
+	SuccessPolicyLeaderSucceeded PodGroupSuccessPolicyMode = "LeaderSucceeded"
+
+	// SuccessPolicyMinSucceeded finishes the pod group once minSucceeded members succeeded, and fails it
+	// once too many members failed for minSucceeded members to succeed.
+	SuccessPolicyMinSucceeded PodGroupSuccessPolicyMode = "MinSucceeded"
+)
+
+// PodGroupSuccessPolicy defines when a pod group is Finished or Failed.
+type PodGroupSuccessPolicy struct {
+	// Mode of the policy: MinMember, AllSucceeded, LeaderSucceeded or MinSucceeded.
+	Mode PodGroupSuccessPolicyMode `json:"mode"`
+
+	// MinSucceeded defines the number of members to succeed with the MinSucceeded mode.
+	// Defaults to minMember.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinSucceeded *int32 `json:"minSucceeded,omitempty"`
+}
+
+// PodGroupBackoffPolicy defines the backoff of a pod group after failed scheduling attempts: the n-th
+// consecutive failure backs it off for initialSeconds * multiplier^(n-1), up to maxSeconds.
+type PodGroupBackoffPolicy struct {
+	// InitialSeconds defines the backoff after the first failure. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	InitialSeconds *int32 `json:"initialSeconds,omitempty"`
+
+	// MaxSeconds defines the maximal backoff. Defaults to 300.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSeconds *int32 `json:"maxSeconds,omitempty"`
+
+	// Multiplier defines the growth of the backoff after each consecutive failure. Defaults to 2.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Multiplier *int32 `json:"multiplier,omitempty"`
 }
 
 // PodGroupStatus represents the current state of a pod group.
@@ -169,6 +347,10 @@ type PodGroupStatus struct {
 	// It is empty if not initialized.
 	OccupiedBy string `json:"occupiedBy,omitempty"`
 
+	// The number of pods in phase Pending, scheduled or not.
+	// +optional
+	Pending int32 `json:"pending,omitempty"`
+
 	// The number of actively running pods.
 	// +optional
 	Running int32 `json:"running,omitempty"`
@@ -181,8 +363,89 @@ type PodGroupStatus struct {
 	// +optional
 	Failed int32 `json:"failed,omitempty"`
 
-	// ScheduleStartTime of the group
+	// ScheduleStartTime of the group, when minMember members were created.
 	ScheduleStartTime metav1.Time `json:"scheduleStartTime,omitempty"`
+
+	// FullyScheduledTime is the time at which minMember members were first scheduled on nodes.
+	// +optional
+	FullyScheduledTime *metav1.Time `json:"fullyScheduledTime,omitempty"`
+
+	// TimeToFullSchedule is the time the group took to get minMember members scheduled on nodes,
+	// from ScheduleStartTime.
+	// +optional
+	TimeToFullSchedule *metav1.Duration `json:"timeToFullSchedule,omitempty"`
+
+	// Conditions of the group. The Scheduled condition explains why the group is not fully scheduled yet.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// WaitingMembers are the members of the group reserved on a node and waiting at Permit for the
+	// rest of the group, as periodically reported by the scheduler.
+	// +optional
+	WaitingMembers []WaitingMember `json:"waitingMembers,omitempty"`
+
+	// BlockingMembers are the members of the group not scheduled yet while some of its members wait
+	// at Permit, with their last rejection, as periodically reported by the scheduler.
+	// +optional
+	BlockingMembers []BlockingMember `json:"blockingMembers,omitempty"`
+
+	// Blockers aggregates the blocking members by the type of their last rejection.
+	// +optional
+	Blockers []GangBlocker `json:"blockers,omitempty"`
+}
+
+// WaitingMember is a member of a PodGroup waiting at Permit.
+type WaitingMember struct {
+	// Name of the pod.
+	Name string `json:"name"`
+
+	// Deadline after which the pod is rejected if the group is still not admitted.
+	Deadline metav1.Time `json:"deadline"`
+}
+
+// GangBlockerType is the type of the rejection of a member blocking its PodGroup.
+// +kubebuilder:validation:Enum=Quota;Affinity;Resources;Other
+type GangBlockerType string
+
+// These are the types of the rejections of the members blocking their PodGroup.
+const (
+	// GangBlockerQuota means the member is rejected by a quota, e.g. its ElasticQuota.
+	GangBlockerQuota GangBlockerType = "Quota"
+
+	// GangBlockerAffinity means the member is rejected by its node affinity, inter-pod affinity,
+	// anti-affinity or topology spread constraints.
+	GangBlockerAffinity GangBlockerType = "Affinity"
+
+	// GangBlockerResources means the member doesn't fit the resources left on the nodes.
+	GangBlockerResources GangBlockerType = "Resources"
+
+	// GangBlockerOther means the member is rejected for another reason.
+	GangBlockerOther GangBlockerType = "Other"
+)
+
+// BlockingMember is a member of a PodGroup not scheduled yet while some of its members wait at Permit.
+type BlockingMember struct {
+	// Name of the pod.
+	Name string `json:"name"`
+
+	// Type of the last rejection of the pod, empty if it was not rejected yet.
+	// +optional
+	Type GangBlockerType `json:"type,omitempty"`
+
+	// Message of the last rejection of the pod, with the reasons of the nodes rejecting it.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GangBlocker is the number of members blocking a PodGroup for a type of rejection.
+type GangBlocker struct {
+	// Type of the rejection.
+	Type GangBlockerType `json:"type"`
+
+	// Members is the number of blocking members last rejected for this type.
+	Members int32 `json:"members"`
 }
 
 // +kubebuilder:object:root=true
@@ -197,3 +460,173 @@ type PodGroupList struct {
 	// Items is the list of PodGroup
 	Items []PodGroup `json:"items"`
 }
+
+// DataResidencyPolicy restricts the regions the pods of the selected workloads can run in,
+// e.g. to keep the processing of personal data in the jurisdiction it was collected in.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName={drp,drps}
+// +kubebuilder:metadata:annotations="api-approved.kubernetes.io=unapproved, experimental-only"
+// +kubebuilder:printcolumn:name="Regions",type=string,JSONPath=`.spec.allowedRegions`,description="AllowedRegions are the regions the selected pods can run in."
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age is the time DataResidencyPolicy was created."
+type DataResidencyPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the data residency policy.
+	// +optional
+	Spec DataResidencyPolicySpec `json:"spec,omitempty"`
+}
+
+// DataResidencyPolicySpec defines the workloads a data residency policy applies to and their allowed regions.
+type DataResidencyPolicySpec struct {
+	// Selector selects the pods the policy applies to, in all the namespaces.
+	// An empty selector selects all the pods.
+	Selector *metav1.LabelSelector `json:"selector"`
+
+	// AllowedRegions are the values of the topology.kubernetes.io/region node label the selected
+	// pods can run in. When several policies select a pod, it can only run in the regions allowed by all of them.
+	// +kubebuilder:validation:MinItems=1
+	AllowedRegions []string `json:"allowedRegions"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DataResidencyPolicyList is a list of DataResidencyPolicy items.
+type DataResidencyPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of DataResidencyPolicy objects.
+	Items []DataResidencyPolicy `json:"items"`
+}
+
+// HostTopology maps the virtualized nodes, e.g. the VMs of a KubeVirt or OpenStack infrastructure running
+// Kubernetes nodes, to the physical hosts running them, and the physical hosts to their failure domains.
+// It is maintained by the infrastructure controller placing the nodes on the hosts.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName={ht,hts}
+// +kubebuilder:metadata:annotations="api-approved.kubernetes.io=unapproved, experimental-only"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age is the time HostTopology was created."
+type HostTopology struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the physical hosts running the nodes.
+	// +optional
+	Spec HostTopologySpec `json:"spec,omitempty"`
+}
+
+// HostTopologySpec defines the physical hosts running the nodes.
+type HostTopologySpec struct {
+	// Hosts are the physical hosts running the nodes.
+	// +optional
+	Hosts []PhysicalHost `json:"hosts,omitempty"`
+}
+
+// PhysicalHost is a hypervisor running nodes.
+type PhysicalHost struct {
+	// Name is the name of the physical host.
+	Name string `json:"name"`
+
+	// FailureDomain is the failure domain of the physical host, e.g. its rack or chassis.
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+
+	// Nodes are the names of the nodes running on the physical host.
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HostTopologyList is a list of HostTopology items.
+type HostTopologyList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of HostTopology objects.
+	Items []HostTopology `json:"items"`
+}
+
+// NodeNFVCapability describes the NFV capabilities of a node: its 1Gi hugepages, its isolated cores and
+// its SR-IOV NIC pools. It is named after the node, and maintained by the node agent or operator
+// configuring them.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName={nnc,nncs}
+// +kubebuilder:metadata:annotations="api-approved.kubernetes.io=unapproved, experimental-only"
+// +kubebuilder:printcolumn:name="HugePages1Gi",type=integer,JSONPath=`.spec.hugePages1Gi`,description="HugePages1Gi is the number of 1Gi hugepages of the node."
+// +kubebuilder:printcolumn:name="IsolatedCores",type=integer,JSONPath=`.spec.isolatedCores`,description="IsolatedCores is the number of isolated cores of the node."
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age is the time NodeNFVCapability was created."
+type NodeNFVCapability struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the NFV capabilities of the node.
+	// +optional
+	Spec NodeNFVCapabilitySpec `json:"spec,omitempty"`
+}
+
+// NodeNFVCapabilitySpec defines the NFV capabilities of a node.
+type NodeNFVCapabilitySpec struct {
+	// HugePages1Gi is the number of 1Gi hugepages of the node available to the pods.
+	// +optional
+	HugePages1Gi int64 `json:"hugePages1Gi,omitempty"`
+
+	// IsolatedCores is the number of cores of the node isolated from the kernel scheduler,
+	// e.g. by the isolcpus kernel argument, available to the DPDK poll-mode threads of the pods.
+	// +optional
+	IsolatedCores int64 `json:"isolatedCores,omitempty"`
+
+	// SRIOVPools are the SR-IOV NIC pools of the node.
+	// +optional
+	SRIOVPools []SRIOVPool `json:"sriovPools,omitempty"`
+}
+
+// SRIOVPool is a pool of SR-IOV virtual functions of a NIC, advertised by the SR-IOV device plugin.
+type SRIOVPool struct {
+	// Name is the name of the pool, e.g. the physical function of the NIC.
+	Name string `json:"name"`
+
+	// ResourceName is the extended resource the pods request the virtual functions of the pool with,
+	// e.g. intel.com/sriov_dpdk_a.
+	ResourceName string `json:"resourceName"`
+
+	// VFs is the number of virtual functions of the pool.
+	// +kubebuilder:validation:Minimum=0
+	VFs int64 `json:"vfs"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeNFVCapabilityList is a list of NodeNFVCapability items.
+type NodeNFVCapabilityList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of NodeNFVCapability objects.
+	Items []NodeNFVCapability `json:"items"`
+}
@@ -22,9 +22,109 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlockingMember) DeepCopyInto(out *BlockingMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockingMember.
+func (in *BlockingMember) DeepCopy() *BlockingMember {
+	if in == nil {
+		return nil
+	}
+	out := new(BlockingMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataResidencyPolicy) DeepCopyInto(out *DataResidencyPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataResidencyPolicy.
+func (in *DataResidencyPolicy) DeepCopy() *DataResidencyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DataResidencyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataResidencyPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataResidencyPolicyList) DeepCopyInto(out *DataResidencyPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataResidencyPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataResidencyPolicyList.
+func (in *DataResidencyPolicyList) DeepCopy() *DataResidencyPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(DataResidencyPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataResidencyPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataResidencyPolicySpec) DeepCopyInto(out *DataResidencyPolicySpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedRegions != nil {
+		in, out := &in.AllowedRegions, &out.AllowedRegions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataResidencyPolicySpec.
+func (in *DataResidencyPolicySpec) DeepCopy() *DataResidencyPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DataResidencyPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticQuota) DeepCopyInto(out *ElasticQuota) {
 	*out = *in
@@ -84,6 +184,28 @@ func (in *ElasticQuotaList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticQuotaLoan) DeepCopyInto(out *ElasticQuotaLoan) {
+	*out = *in
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticQuotaLoan.
+func (in *ElasticQuotaLoan) DeepCopy() *ElasticQuotaLoan {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticQuotaLoan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticQuotaSpec) DeepCopyInto(out *ElasticQuotaSpec) {
 	*out = *in
@@ -101,6 +223,25 @@ func (in *ElasticQuotaSpec) DeepCopyInto(out *ElasticQuotaSpec) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Lending != nil {
+		in, out := &in.Lending, &out.Lending
+		*out = make([]ElasticQuotaLoan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BorrowingDecaySeconds != nil {
+		in, out := &in.BorrowingDecaySeconds, &out.BorrowingDecaySeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticQuotaSpec.
@@ -135,6 +276,199 @@ func (in *ElasticQuotaStatus) DeepCopy() *ElasticQuotaStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GangBlocker) DeepCopyInto(out *GangBlocker) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GangBlocker.
+func (in *GangBlocker) DeepCopy() *GangBlocker {
+	if in == nil {
+		return nil
+	}
+	out := new(GangBlocker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostTopology) DeepCopyInto(out *HostTopology) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostTopology.
+func (in *HostTopology) DeepCopy() *HostTopology {
+	if in == nil {
+		return nil
+	}
+	out := new(HostTopology)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostTopology) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostTopologyList) DeepCopyInto(out *HostTopologyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HostTopology, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostTopologyList.
+func (in *HostTopologyList) DeepCopy() *HostTopologyList {
+	if in == nil {
+		return nil
+	}
+	out := new(HostTopologyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostTopologyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostTopologySpec) DeepCopyInto(out *HostTopologySpec) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]PhysicalHost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostTopologySpec.
+func (in *HostTopologySpec) DeepCopy() *HostTopologySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostTopologySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNFVCapability) DeepCopyInto(out *NodeNFVCapability) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNFVCapability.
+func (in *NodeNFVCapability) DeepCopy() *NodeNFVCapability {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNFVCapability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeNFVCapability) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNFVCapabilityList) DeepCopyInto(out *NodeNFVCapabilityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeNFVCapability, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNFVCapabilityList.
+func (in *NodeNFVCapabilityList) DeepCopy() *NodeNFVCapabilityList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNFVCapabilityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeNFVCapabilityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNFVCapabilitySpec) DeepCopyInto(out *NodeNFVCapabilitySpec) {
+	*out = *in
+	if in.SRIOVPools != nil {
+		in, out := &in.SRIOVPools, &out.SRIOVPools
+		*out = make([]SRIOVPool, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNFVCapabilitySpec.
+func (in *NodeNFVCapabilitySpec) DeepCopy() *NodeNFVCapabilitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNFVCapabilitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhysicalHost) DeepCopyInto(out *PhysicalHost) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhysicalHost.
+func (in *PhysicalHost) DeepCopy() *PhysicalHost {
+	if in == nil {
+		return nil
+	}
+	out := new(PhysicalHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodGroup) DeepCopyInto(out *PodGroup) {
 	*out = *in
@@ -162,6 +496,36 @@ func (in *PodGroup) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodGroupBackoffPolicy) DeepCopyInto(out *PodGroupBackoffPolicy) {
+	*out = *in
+	if in.InitialSeconds != nil {
+		in, out := &in.InitialSeconds, &out.InitialSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSeconds != nil {
+		in, out := &in.MaxSeconds, &out.MaxSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Multiplier != nil {
+		in, out := &in.Multiplier, &out.Multiplier
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodGroupBackoffPolicy.
+func (in *PodGroupBackoffPolicy) DeepCopy() *PodGroupBackoffPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PodGroupBackoffPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodGroupList) DeepCopyInto(out *PodGroupList) {
 	*out = *in
@@ -209,6 +573,36 @@ func (in *PodGroupSpec) DeepCopyInto(out *PodGroupSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(PodGroupBackoffPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProgressMinPercentage != nil {
+		in, out := &in.ProgressMinPercentage, &out.ProgressMinPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.SuccessPolicy != nil {
+		in, out := &in.SuccessPolicy, &out.SuccessPolicy
+		*out = new(PodGroupSuccessPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemberNamespaces != nil {
+		in, out := &in.MemberNamespaces, &out.MemberNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodGroupSpec.
@@ -225,6 +619,39 @@ func (in *PodGroupSpec) DeepCopy() *PodGroupSpec {
 func (in *PodGroupStatus) DeepCopyInto(out *PodGroupStatus) {
 	*out = *in
 	in.ScheduleStartTime.DeepCopyInto(&out.ScheduleStartTime)
+	if in.FullyScheduledTime != nil {
+		in, out := &in.FullyScheduledTime, &out.FullyScheduledTime
+		*out = (*in).DeepCopy()
+	}
+	if in.TimeToFullSchedule != nil {
+		in, out := &in.TimeToFullSchedule, &out.TimeToFullSchedule
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WaitingMembers != nil {
+		in, out := &in.WaitingMembers, &out.WaitingMembers
+		*out = make([]WaitingMember, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BlockingMembers != nil {
+		in, out := &in.BlockingMembers, &out.BlockingMembers
+		*out = make([]BlockingMember, len(*in))
+		copy(*out, *in)
+	}
+	if in.Blockers != nil {
+		in, out := &in.Blockers, &out.Blockers
+		*out = make([]GangBlocker, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodGroupStatus.
@@ -236,3 +663,54 @@ func (in *PodGroupStatus) DeepCopy() *PodGroupStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodGroupSuccessPolicy) DeepCopyInto(out *PodGroupSuccessPolicy) {
+	*out = *in
+	if in.MinSucceeded != nil {
+		in, out := &in.MinSucceeded, &out.MinSucceeded
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodGroupSuccessPolicy.
+func (in *PodGroupSuccessPolicy) DeepCopy() *PodGroupSuccessPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PodGroupSuccessPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SRIOVPool) DeepCopyInto(out *SRIOVPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SRIOVPool.
+func (in *SRIOVPool) DeepCopy() *SRIOVPool {
+	if in == nil {
+		return nil
+	}
+	out := new(SRIOVPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaitingMember) DeepCopyInto(out *WaitingMember) {
+	*out = *in
+	in.Deadline.DeepCopyInto(&out.Deadline)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitingMember.
+func (in *WaitingMember) DeepCopy() *WaitingMember {
+	if in == nil {
+		return nil
+	}
+	out := new(WaitingMember)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// The v1beta1 PodGroup and ElasticQuota convert from and to the v1alpha1 hub. The fields added by v1beta1 without
+// v1alpha1 counterpart must be preserved in annotations of the hub, so that a v1beta1 object read back is unchanged.
+
+var _ conversion.Convertible = &PodGroup{}
+var _ conversion.Convertible = &ElasticQuota{}
+
+// ConvertTo converts the PodGroup to the v1alpha1 hub.
+func (src *PodGroup) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.PodGroup)
+	if !ok {
+		return fmt.Errorf("want a v1alpha1 PodGroup, got %T", dstRaw)
+	}
+	spec, status := src.Spec.DeepCopy(), src.Status.DeepCopy()
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = v1alpha1.PodGroupSpec{
+		MinMember:               spec.MinMember,
+		MinResources:            spec.MinResources,
+		ScheduleTimeoutSeconds:  spec.ScheduleTimeoutSeconds,
+		ProgressDeadlineSeconds: spec.ProgressDeadlineSeconds,
+		ProgressMinPercentage:   spec.ProgressMinPercentage,
+		MaxUnavailable:          spec.MaxUnavailable,
+		MemberNamespaces:        spec.MemberNamespaces,
+	}
+	if backoff := spec.Backoff; backoff != nil {
+		dst.Spec.Backoff = &v1alpha1.PodGroupBackoffPolicy{
+			InitialSeconds: backoff.InitialSeconds,
+			MaxSeconds:     backoff.MaxSeconds,
+			Multiplier:     backoff.Multiplier,
+		}
+	}
+	if policy := spec.SuccessPolicy; policy != nil {
+		dst.Spec.SuccessPolicy = &v1alpha1.PodGroupSuccessPolicy{
+			Mode:         v1alpha1.PodGroupSuccessPolicyMode(policy.Mode),
+			MinSucceeded: policy.MinSucceeded,
+		}
+	}
+	dst.Status = v1alpha1.PodGroupStatus{
+		Phase:              v1alpha1.PodGroupPhase(status.Phase),
+		OccupiedBy:         status.OccupiedBy,
+		Pending:            status.Pending,
+		Running:            status.Running,
+		Succeeded:          status.Succeeded,
+		Failed:             status.Failed,
+		ScheduleStartTime:  status.ScheduleStartTime,
+		FullyScheduledTime: status.FullyScheduledTime,
+		TimeToFullSchedule: status.TimeToFullSchedule,
+		Conditions:         status.Conditions,
+	}
+	for _, member := range status.WaitingMembers {
+		dst.Status.WaitingMembers = append(dst.Status.WaitingMembers, v1alpha1.WaitingMember{Name: member.Name, Deadline: member.Deadline})
+	}
+	for _, member := range status.BlockingMembers {
+		dst.Status.BlockingMembers = append(dst.Status.BlockingMembers,
+			v1alpha1.BlockingMember{Name: member.Name, Type: v1alpha1.GangBlockerType(member.Type), Message: member.Message})
+	}
+	for _, blocker := range status.Blockers {
+		dst.Status.Blockers = append(dst.Status.Blockers, v1alpha1.GangBlocker{Type: v1alpha1.GangBlockerType(blocker.Type), Members: blocker.Members})
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub to the PodGroup.
+func (dst *PodGroup) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.PodGroup)
+	if !ok {
+		return fmt.Errorf("want a v1alpha1 PodGroup, got %T", srcRaw)
+	}
+	spec, status := src.Spec.DeepCopy(), src.Status.DeepCopy()
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = PodGroupSpec{
+		MinMember:               spec.MinMember,
+		MinResources:            spec.MinResources,
+		ScheduleTimeoutSeconds:  spec.ScheduleTimeoutSeconds,
+		ProgressDeadlineSeconds: spec.ProgressDeadlineSeconds,
+		ProgressMinPercentage:   spec.ProgressMinPercentage,
+		MaxUnavailable:          spec.MaxUnavailable,
+		MemberNamespaces:        spec.MemberNamespaces,
+	}
+	if backoff := spec.Backoff; backoff != nil {
+		dst.Spec.Backoff = &PodGroupBackoffPolicy{
+			InitialSeconds: backoff.InitialSeconds,
+			MaxSeconds:     backoff.MaxSeconds,
+			Multiplier:     backoff.Multiplier,
+		}
+	}
+	if policy := spec.SuccessPolicy; policy != nil {
+		dst.Spec.SuccessPolicy = &PodGroupSuccessPolicy{
+			Mode:         PodGroupSuccessPolicyMode(policy.Mode),
+			MinSucceeded: policy.MinSucceeded,
+		}
+	}
+	dst.Status = PodGroupStatus{
+		Phase:              PodGroupPhase(status.Phase),
+		OccupiedBy:         status.OccupiedBy,
+		Pending:            status.Pending,
+		Running:            status.Running,
+		Succeeded:          status.Succeeded,
+		Failed:             status.Failed,
+		ScheduleStartTime:  status.ScheduleStartTime,
+		FullyScheduledTime: status.FullyScheduledTime,
+		TimeToFullSchedule: status.TimeToFullSchedule,
+		Conditions:         status.Conditions,
+	}
+	for _, member := range status.WaitingMembers {
+		dst.Status.WaitingMembers = append(dst.Status.WaitingMembers, WaitingMember{Name: member.Name, Deadline: member.Deadline})
+	}
+	for _, member := range status.BlockingMembers {
+		dst.Status.BlockingMembers = append(dst.Status.BlockingMembers,
+			BlockingMember{Name: member.Name, Type: GangBlockerType(member.Type), Message: member.Message})
+	}
+	for _, blocker := range status.Blockers {
+		dst.Status.Blockers = append(dst.Status.Blockers, GangBlocker{Type: GangBlockerType(blocker.Type), Members: blocker.Members})
+	}
+	return nil
+}
+
+// ConvertTo converts the ElasticQuota to the v1alpha1 hub.
+func (src *ElasticQuota) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.ElasticQuota)
+	if !ok {
+		return fmt.Errorf("want a v1alpha1 ElasticQuota, got %T", dstRaw)
+	}
+	spec := src.Spec.DeepCopy()
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = v1alpha1.ElasticQuotaSpec{
+		Min:                   spec.Min,
+		Max:                   spec.Max,
+		NodeSelector:          spec.NodeSelector,
+		BorrowingDecaySeconds: spec.BorrowingDecaySeconds,
+		Parent:                spec.Parent,
+	}
+	for _, loan := range spec.Lending {
+		dst.Spec.Lending = append(dst.Spec.Lending, v1alpha1.ElasticQuotaLoan{Borrower: loan.Borrower, Max: loan.Max})
+	}
+	dst.Status = v1alpha1.ElasticQuotaStatus{Used: src.Status.Used.DeepCopy()}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub to the ElasticQuota.
+func (dst *ElasticQuota) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.ElasticQuota)
+	if !ok {
+		return fmt.Errorf("want a v1alpha1 ElasticQuota, got %T", srcRaw)
+	}
+	spec := src.Spec.DeepCopy()
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = ElasticQuotaSpec{
+		Min:                   spec.Min,
+		Max:                   spec.Max,
+		NodeSelector:          spec.NodeSelector,
+		BorrowingDecaySeconds: spec.BorrowingDecaySeconds,
+		Parent:                spec.Parent,
+	}
+	for _, loan := range spec.Lending {
+		dst.Spec.Lending = append(dst.Spec.Lending, ElasticQuotaLoan{Borrower: loan.Borrower, Max: loan.Max})
+	}
+	dst.Status = ElasticQuotaStatus{Used: src.Status.Used.DeepCopy()}
+	return nil
+}
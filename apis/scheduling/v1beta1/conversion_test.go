@@ -0,0 +1,240 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	fuzz "github.com/google/gofuzz"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestPodGroupConversion(t *testing.T) {
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+	tests := []struct {
+		name string
+		pg   *v1alpha1.PodGroup
+	}{
+		{
+			name: "empty PodGroup",
+			pg:   &v1alpha1.PodGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg"}},
+		},
+		{
+			name: "PodGroup with all the fields set",
+			pg: &v1alpha1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        "pg",
+					Labels:      map[string]string{"app": "training"},
+					Annotations: map[string]string{"note": "kept"},
+					Finalizers:  []string{v1alpha1.SchedulerCacheFinalizer},
+				},
+				Spec: v1alpha1.PodGroupSpec{
+					MinMember:              4,
+					MinResources:           v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+					ScheduleTimeoutSeconds: ptr.To[int32](60),
+					Backoff: &v1alpha1.PodGroupBackoffPolicy{
+						InitialSeconds: ptr.To[int32](30),
+						MaxSeconds:     ptr.To[int32](600),
+						Multiplier:     ptr.To[int32](3),
+					},
+					ProgressDeadlineSeconds: ptr.To[int32](120),
+					ProgressMinPercentage:   ptr.To[int32](50),
+					MaxUnavailable:          ptr.To(intstr.FromString("25%")),
+					SuccessPolicy: &v1alpha1.PodGroupSuccessPolicy{
+						Mode:         v1alpha1.SuccessPolicyMinSucceeded,
+						MinSucceeded: ptr.To[int32](3),
+					},
+					MemberNamespaces: []string{"producer", "consumer"},
+				},
+				Status: v1alpha1.PodGroupStatus{
+					Phase:              v1alpha1.PodGroupRunning,
+					OccupiedBy:         "default/job",
+					Pending:            1,
+					Running:            4,
+					Succeeded:          1,
+					Failed:             2,
+					ScheduleStartTime:  now,
+					FullyScheduledTime: &now,
+					TimeToFullSchedule: &metav1.Duration{Duration: time.Minute},
+					Conditions: []metav1.Condition{{
+						Type:               v1alpha1.PodGroupScheduled,
+						Status:             metav1.ConditionTrue,
+						Reason:             v1alpha1.PodGroupReasonScheduled,
+						LastTransitionTime: now,
+					}},
+					WaitingMembers: []v1alpha1.WaitingMember{{Name: "pod-1", Deadline: now}},
+					BlockingMembers: []v1alpha1.BlockingMember{
+						{Name: "pod-2", Type: v1alpha1.GangBlockerQuota, Message: "3 elasticquota exceeded"},
+					},
+					Blockers: []v1alpha1.GangBlocker{{Type: v1alpha1.GangBlockerQuota, Members: 1}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beta := &PodGroup{}
+			if err := beta.ConvertFrom(tt.pg.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+			got := &v1alpha1.PodGroup{}
+			if err := beta.ConvertTo(got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.pg, got); diff != "" {
+				t.Errorf("unexpected PodGroup after a round trip (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestElasticQuotaConversion(t *testing.T) {
+	tests := []struct {
+		name string
+		eq   *v1alpha1.ElasticQuota
+	}{
+		{
+			name: "empty ElasticQuota",
+			eq:   &v1alpha1.ElasticQuota{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "eq"}},
+		},
+		{
+			name: "ElasticQuota with all the fields set",
+			eq: &v1alpha1.ElasticQuota{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "eq", Labels: map[string]string{"team": "a"}},
+				Spec: v1alpha1.ElasticQuotaSpec{
+					Min:          v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+					Max:          v1.ResourceList{v1.ResourceCPU: resource.MustParse("8")},
+					NodeSelector: map[string]string{"pool": "a"},
+					Lending: []v1alpha1.ElasticQuotaLoan{
+						{Borrower: "team-b", Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}},
+					},
+					BorrowingDecaySeconds: ptr.To[int32](300),
+					Parent:                "org",
+				},
+				Status: v1alpha1.ElasticQuotaStatus{Used: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3")}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beta := &ElasticQuota{}
+			if err := beta.ConvertFrom(tt.eq.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+			got := &v1alpha1.ElasticQuota{}
+			if err := beta.ConvertTo(got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.eq, got); diff != "" {
+				t.Errorf("unexpected ElasticQuota after a round trip (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// newRoundTripFuzzer returns a fuzzer setting every field, so that a field missed by the conversion fails the round trip.
+// The TypeMeta is left empty, it is set by the serializer of each version.
+func newRoundTripFuzzer(seed int64) *fuzz.Fuzzer {
+	return fuzz.NewWithSeed(seed).NilChance(0).NumElements(1, 2).Funcs(
+		func(*metav1.TypeMeta, fuzz.Continue) {},
+		func(q *resource.Quantity, c fuzz.Continue) {
+			*q = *resource.NewQuantity(c.Int63n(1000), resource.DecimalSI)
+		},
+		func(t *metav1.Time, c fuzz.Continue) {
+			*t = metav1.Unix(c.Int63n(1<<32), 0)
+		},
+		func(d *metav1.Duration, c fuzz.Continue) {
+			d.Duration = time.Duration(c.Int63n(1<<32)) * time.Second
+		},
+		func(fields *metav1.FieldsV1, c fuzz.Continue) {
+			fields.Raw = []byte(`{"f:spec":{}}`)
+		},
+	)
+}
+
+func TestPodGroupConversionFuzz(t *testing.T) {
+	for seed := int64(0); seed < 100; seed++ {
+		f := newRoundTripFuzzer(seed)
+
+		hub := &v1alpha1.PodGroup{}
+		f.Fuzz(hub)
+		beta := &PodGroup{}
+		if err := beta.ConvertFrom(hub.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+		gotHub := &v1alpha1.PodGroup{}
+		if err := beta.ConvertTo(gotHub); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(hub, gotHub); diff != "" {
+			t.Fatalf("unexpected v1alpha1 PodGroup after a round trip with seed %d (-want,+got):\n%s", seed, diff)
+		}
+
+		f.Fuzz(beta)
+		if err := beta.DeepCopy().ConvertTo(hub); err != nil {
+			t.Fatal(err)
+		}
+		gotBeta := &PodGroup{}
+		if err := gotBeta.ConvertFrom(hub); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(beta, gotBeta); diff != "" {
+			t.Fatalf("unexpected v1beta1 PodGroup after a round trip with seed %d (-want,+got):\n%s", seed, diff)
+		}
+	}
+}
+
+func TestElasticQuotaConversionFuzz(t *testing.T) {
+	for seed := int64(0); seed < 100; seed++ {
+		f := newRoundTripFuzzer(seed)
+
+		hub := &v1alpha1.ElasticQuota{}
+		f.Fuzz(hub)
+		beta := &ElasticQuota{}
+		if err := beta.ConvertFrom(hub.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+		gotHub := &v1alpha1.ElasticQuota{}
+		if err := beta.ConvertTo(gotHub); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(hub, gotHub); diff != "" {
+			t.Fatalf("unexpected v1alpha1 ElasticQuota after a round trip with seed %d (-want,+got):\n%s", seed, diff)
+		}
+
+		f.Fuzz(beta)
+		if err := beta.DeepCopy().ConvertTo(hub); err != nil {
+			t.Fatal(err)
+		}
+		gotBeta := &ElasticQuota{}
+		if err := gotBeta.ConvertFrom(hub); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(beta, gotBeta); diff != "" {
+			t.Fatalf("unexpected v1beta1 ElasticQuota after a round trip with seed %d (-want,+got):\n%s", seed, diff)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 is the next version of the PodGroup and ElasticQuota APIs. It is served along v1alpha1,
+// which stays the storage version, and converted from and to it by the conversion webhook of the controller
+// manager. The scheduler plugins and the controllers keep using the v1alpha1 clients.
+// +kubebuilder:object:generate=true
+// +groupName=scheduling.x-k8s.io
+package v1beta1
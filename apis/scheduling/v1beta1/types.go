@@ -0,0 +1,403 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ElasticQuota sets elastic quota restrictions per namespace
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName={eq,eqs}
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:annotations="api-approved.kubernetes.io=https://github.com/kubernetes-sigs/scheduler-plugins/pull/52"
+// +kubebuilder:printcolumn:name="Used",JSONPath=".status.used",type=string,description="Used is the current observed total usage of the resource in the namespace."
+// +kubebuilder:printcolumn:name="Max",JSONPath=".spec.max",type=string,description="Max is the set of desired max limits for each named resource."
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type=date,description="Age is the time ElasticQuota was created."
+type ElasticQuota struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// ElasticQuotaSpec defines the Min and Max for Quota.
+	// +optional
+	Spec ElasticQuotaSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+
+	// ElasticQuotaStatus defines the observed use.
+	// +optional
+	Status ElasticQuotaStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// ElasticQuotaSpec defines the Min and Max for Quota.
+type ElasticQuotaSpec struct {
+	// Min is the set of desired guaranteed limits for each named resource.
+	// +optional
+	Min v1.ResourceList `json:"min,omitempty" protobuf:"bytes,1,rep,name=min, casttype=ResourceList,castkey=ResourceName"`
+
+	// Max is the set of desired max limits for each named resource. The usage of max is based on the resource configurations of
+	// successfully scheduled pods.
+	// +optional
+	Max v1.ResourceList `json:"max,omitempty" protobuf:"bytes,2,rep,name=max, casttype=ResourceList,castkey=ResourceName"`
+
+	// NodeSelector selects the nodes of a pool dedicated to the quota. When set, Min is only
+	// guaranteed on the selected nodes, other quotas can't use them, and the usage over Min is
+	// borrowed on the shared nodes, i.e. the nodes not dedicated to any quota.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" protobuf:"bytes,3,rep,name=nodeSelector"`
+
+	// Lending are the lending agreements of the quota, each allowing the ElasticQuota of another namespace
+	// to use up to a given amount of the unused min of the quota, ahead of the borrowers sharing the unused min
+	// of all the quotas. The lent resources are given back by preemption once the quota needs them.
+	// +optional
+	Lending []ElasticQuotaLoan `json:"lending,omitempty" protobuf:"bytes,4,rep,name=lending"`
+
+	// BorrowingDecaySeconds decays the pods of the quota using resources beyond its min: every BorrowingDecaySeconds
+	// a pod keeps borrowing, it moves one step ahead of the pods borrowing for a shorter time, regardless of their
+	// priority, in the order the borrowed resources are reclaimed by preemption once other quotas need their min.
+	// The borrowed resources are reclaimed by priority only when not set.
+	// +optional
+	BorrowingDecaySeconds *int32 `json:"borrowingDecaySeconds,omitempty" protobuf:"varint,5,opt,name=borrowingDecaySeconds"`
+
+	// Parent is the namespace of the parent ElasticQuota of the quota in a quota tree. The quota can use the unused
+	// min of its ancestors ahead of the borrowers sharing the unused min of all the quotas, and the usage of the
+	// quota and all its descendants can't exceed the max of each of its ancestors. The borrowed resources are
+	// reclaimed by preemption from the subtrees of the siblings first. The quota is a root when not set.
+	// +optional
+	Parent string `json:"parent,omitempty" protobuf:"bytes,6,opt,name=parent"`
+}
+
+// ElasticQuotaLoan is a lending agreement from an ElasticQuota to the ElasticQuota of another namespace.
+type ElasticQuotaLoan struct {
+	// Borrower is the namespace of the borrowing ElasticQuota.
+	Borrower string `json:"borrower" protobuf:"bytes,1,opt,name=borrower"`
+
+	// Max is the most of the unused min of the lender the borrower can use, for each named resource.
+	Max v1.ResourceList `json:"max" protobuf:"bytes,2,rep,name=max,casttype=ResourceList,castkey=ResourceName"`
+}
+
+// ElasticQuotaStatus defines the observed use.
+type ElasticQuotaStatus struct {
+	// Used is the current observed total usage of the resource in the namespace.
+	// +optional
+	Used v1.ResourceList `json:"used,omitempty" protobuf:"bytes,1,rep,name=used,casttype=ResourceList,castkey=ResourceName"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ElasticQuotaList is a list of ElasticQuota items.
+type ElasticQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Items is a list of ElasticQuota objects.
+	Items []ElasticQuota `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// PodGroupPhase is the phase of a pod group at the current time.
+type PodGroupPhase string
+
+// These are the valid phase of podGroups.
+const (
+	// PodGroupPending means the pod group has been accepted by the system, but scheduler can not allocate
+	// enough resources to it.
+	PodGroupPending PodGroupPhase = "Pending"
+
+	// PodGroupRunning means the `spec.minMember` pods of the pod group are in running phase.
+	PodGroupRunning PodGroupPhase = "Running"
+
+	// PodGroupScheduling means the number of pods scheduled is bigger than `spec.minMember`
+	// but the number of running pods has not reached the `spec.minMember` pods of PodGroups.
+	PodGroupScheduling PodGroupPhase = "Scheduling"
+
+	// PodGroupUnknown means a part of `spec.minMember` pods of the pod group have been scheduled but the others can not
+	// be scheduled due to, e.g. not enough resource; scheduler will wait for related controllers to recover them.
+	PodGroupUnknown PodGroupPhase = "Unknown"
+
+	// PodGroupFinished means the `spec.minMember` pods of the pod group are successfully finished.
+	PodGroupFinished PodGroupPhase = "Finished"
+
+	// PodGroupFailed means at least one of `spec.minMember` pods have failed.
+	PodGroupFailed PodGroupPhase = "Failed"
+)
+
+// PodGroup is a collection of Pod; used for batch workload.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName={pg,pgs}
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:annotations="api-approved.kubernetes.io=https://github.com/kubernetes-sigs/scheduler-plugins/pull/50"
+// +kubebuilder:printcolumn:name="Phase",JSONPath=".status.phase",type=string,description="Current phase of PodGroup."
+// +kubebuilder:printcolumn:name="MinMember",JSONPath=".spec.minMember",type=integer,description="MinMember defines the minimal number of members/tasks to run the pod group."
+// +kubebuilder:printcolumn:name="Running",JSONPath=".status.running",type=integer,description="The number of actively running pods."
+// +kubebuilder:printcolumn:name="Succeeded",JSONPath=".status.succeeded",type=integer,description="The number of pods which reached phase Succeeded."
+// +kubebuilder:printcolumn:name="Failed",JSONPath=".status.failed",type=integer,description="The number of pods which reached phase Failed."
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type=date,description="Age is the time PodGroup was created."
+type PodGroup struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the pod group.
+	// +optional
+	Spec PodGroupSpec `json:"spec,omitempty"`
+
+	// Status represents the current information about a pod group.
+	// This data may not be up to date.
+	// +optional
+	Status PodGroupStatus `json:"status,omitempty"`
+}
+
+// PodGroupSpec represents the template of a pod group.
+type PodGroupSpec struct {
+	// MinMember defines the minimal number of members/tasks to run the pod group;
+	// if there's not enough resources to start all tasks, the scheduler
+	// will not start any.
+	// The minimum is 1
+	// +kubebuilder:validation:Minimum=1
+	MinMember int32 `json:"minMember,omitempty"`
+
+	// MinResources defines the minimal resource of members/tasks to run the pod group;
+	// if there's not enough resources to start all tasks, the scheduler
+	// will not start any.
+	MinResources v1.ResourceList `json:"minResources,omitempty"`
+
+	// ScheduleTimeoutSeconds defines the maximal time of members/tasks to wait before run the pod group;
+	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty"`
+
+	// Backoff defines how long the scheduler backs off the pod group after its scheduling fails, growing
+	// with its consecutive failures. Defaults to the podGroupBackoffSeconds of the Coscheduling plugin.
+	// +optional
+	Backoff *PodGroupBackoffPolicy `json:"backoff,omitempty"`
+
+	// ProgressDeadlineSeconds defines the maximal time for the pod group to make progress once its
+	// first member waits for the quorum; if fewer than progressMinPercentage of minMember members have
+	// been assigned by then, the scheduler releases the whole pod group and backs it off.
+	// It is distinct from scheduleTimeoutSeconds, which bounds the wait of each member.
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// ProgressMinPercentage defines the percentage of minMember members to be assigned within
+	// progressDeadlineSeconds. Defaults to 100.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ProgressMinPercentage *int32 `json:"progressMinPercentage,omitempty"`
+
+	// MaxUnavailable, if set, makes the PodGroup controller maintain a PodDisruptionBudget named after
+	// the pod group and covering its members, allowing at most maxUnavailable members to be evicted at
+	// once. It can be an absolute number or a percentage of the members.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// SuccessPolicy defines when the pod group is Finished or Failed, from the phases of its members.
+	// Defaults to the MinMember mode.
+	// +optional
+	SuccessPolicy *PodGroupSuccessPolicy `json:"successPolicy,omitempty"`
+
+	// MemberNamespaces lists the namespaces, besides the one of the pod group, whose pods may be members
+	// of the pod group, e.g. for pipelines whose producer and consumer components live in different
+	// namespaces but must start together. Such members are labeled with PodGroupNamespaceLabel, and the
+	// service account of each of them must be allowed to `use` the pod group.
+	// +optional
+	MemberNamespaces []string `json:"memberNamespaces,omitempty"`
+}
+
+// PodGroupSuccessPolicyMode is the mode of a success policy.
+// +kubebuilder:validation:Enum=MinMember;AllSucceeded;LeaderSucceeded;MinSucceeded
+type PodGroupSuccessPolicyMode string
+
+const (
+	// SuccessPolicyMinMember finishes the pod group once minMember members succeeded, and fails it once
+	// a member failed while minMember members are running or completed.
+	SuccessPolicyMinMember PodGroupSuccessPolicyMode = "MinMember"
+
+	// SuccessPolicyAllSucceeded finishes the pod group once all its members succeeded, and fails it once
+	// any member failed.
+	SuccessPolicyAllSucceeded PodGroupSuccessPolicyMode = "AllSucceeded"
+
+	// SuccessPolicyLeaderSucceeded finishes the pod group once its leader, the member labeled with
+	// PodGroupLeaderLabel, succeeded, and fails it once its leader failed.
+	SuccessPolicyLeaderSucceeded PodGroupSuccessPolicyMode = "LeaderSucceeded"
+
+	// SuccessPolicyMinSucceeded finishes the pod group once minSucceeded members succeeded, and fails it
+	// once too many members failed for minSucceeded members to succeed.
+	SuccessPolicyMinSucceeded PodGroupSuccessPolicyMode = "MinSucceeded"
+)
+
+// PodGroupSuccessPolicy defines when a pod group is Finished or Failed.
+type PodGroupSuccessPolicy struct {
+	// Mode of the policy: MinMember, AllSucceeded, LeaderSucceeded or MinSucceeded.
+	Mode PodGroupSuccessPolicyMode `json:"mode"`
+
+	// MinSucceeded defines the number of members to succeed with the MinSucceeded mode.
+	// Defaults to minMember.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinSucceeded *int32 `json:"minSucceeded,omitempty"`
+}
+
+// PodGroupBackoffPolicy defines the backoff of a pod group after failed scheduling attempts: the n-th
+// consecutive failure backs it off for initialSeconds * multiplier^(n-1), up to maxSeconds.
+type PodGroupBackoffPolicy struct {
+	// InitialSeconds defines the backoff after the first failure. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	InitialSeconds *int32 `json:"initialSeconds,omitempty"`
+
+	// MaxSeconds defines the maximal backoff. Defaults to 300.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSeconds *int32 `json:"maxSeconds,omitempty"`
+
+	// Multiplier defines the growth of the backoff after each consecutive failure. Defaults to 2.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Multiplier *int32 `json:"multiplier,omitempty"`
+}
+
+// PodGroupStatus represents the current state of a pod group.
+type PodGroupStatus struct {
+	// Current phase of PodGroup.
+	Phase PodGroupPhase `json:"phase,omitempty"`
+
+	// OccupiedBy marks the workload (e.g., deployment, statefulset) UID that occupy the podgroup.
+	// It is empty if not initialized.
+	OccupiedBy string `json:"occupiedBy,omitempty"`
+
+	// The number of pods in phase Pending, scheduled or not.
+	// +optional
+	Pending int32 `json:"pending,omitempty"`
+
+	// The number of actively running pods.
+	// +optional
+	Running int32 `json:"running,omitempty"`
+
+	// The number of pods which reached phase Succeeded.
+	// +optional
+	Succeeded int32 `json:"succeeded,omitempty"`
+
+	// The number of pods which reached phase Failed.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+
+	// ScheduleStartTime of the group, when minMember members were created.
+	ScheduleStartTime metav1.Time `json:"scheduleStartTime,omitempty"`
+
+	// FullyScheduledTime is the time at which minMember members were first scheduled on nodes.
+	// +optional
+	FullyScheduledTime *metav1.Time `json:"fullyScheduledTime,omitempty"`
+
+	// TimeToFullSchedule is the time the group took to get minMember members scheduled on nodes,
+	// from ScheduleStartTime.
+	// +optional
+	TimeToFullSchedule *metav1.Duration `json:"timeToFullSchedule,omitempty"`
+
+	// Conditions of the group. The Scheduled condition explains why the group is not fully scheduled yet.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// WaitingMembers are the members of the group reserved on a node and waiting at Permit for the
+	// rest of the group, as periodically reported by the scheduler.
+	// +optional
+	WaitingMembers []WaitingMember `json:"waitingMembers,omitempty"`
+
+	// BlockingMembers are the members of the group not scheduled yet while some of its members wait
+	// at Permit, with their last rejection, as periodically reported by the scheduler.
+	// +optional
+	BlockingMembers []BlockingMember `json:"blockingMembers,omitempty"`
+
+	// Blockers aggregates the blocking members by the type of their last rejection.
+	// +optional
+	Blockers []GangBlocker `json:"blockers,omitempty"`
+}
+
+// WaitingMember is a member of a PodGroup waiting at Permit.
+type WaitingMember struct {
+	// Name of the pod.
+	Name string `json:"name"`
+
+	// Deadline after which the pod is rejected if the group is still not admitted.
+	Deadline metav1.Time `json:"deadline"`
+}
+
+// GangBlockerType is the type of the rejection of a member blocking its PodGroup.
+// +kubebuilder:validation:Enum=Quota;Affinity;Resources;Other
+type GangBlockerType string
+
+// These are the types of the rejections of the members blocking their PodGroup.
+const (
+	// GangBlockerQuota means the member is rejected by a quota, e.g. its ElasticQuota.
+	GangBlockerQuota GangBlockerType = "Quota"
+
+	// GangBlockerAffinity means the member is rejected by its node affinity, inter-pod affinity,
+	// anti-affinity or topology spread constraints.
+	GangBlockerAffinity GangBlockerType = "Affinity"
+
+	// GangBlockerResources means the member doesn't fit the resources left on the nodes.
+	GangBlockerResources GangBlockerType = "Resources"
+
+	// GangBlockerOther means the member is rejected for another reason.
+	GangBlockerOther GangBlockerType = "Other"
+)
+
+// BlockingMember is a member of a PodGroup not scheduled yet while some of its members wait at Permit.
+type BlockingMember struct {
+	// Name of the pod.
+	Name string `json:"name"`
+
+	// Type of the last rejection of the pod, empty if it was not rejected yet.
+	// +optional
+	Type GangBlockerType `json:"type,omitempty"`
+
+	// Message of the last rejection of the pod, with the reasons of the nodes rejecting it.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GangBlocker is the number of members blocking a PodGroup for a type of rejection.
+type GangBlocker struct {
+	// Type of the rejection.
+	Type GangBlockerType `json:"type"`
+
+	// Members is the number of blocking members last rejected for this type.
+	Members int32 `json:"members"`
+}
+
+// +kubebuilder:object:root=true
+
+// PodGroupList is a collection of pod groups.
+type PodGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of PodGroup
+	Items []PodGroup `json:"items"`
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/amiraBenamer20/scheduler-plugins/manifests"
+)
+
+// crdFieldManager is the field manager owning the CRDs applied by the controller manager.
+const crdFieldManager = "scheduler-plugins-controller"
+
+// embeddedCRDs returns the CRD manifests to install, the diktyo ones only if enabled.
+func embeddedCRDs(diktyo bool) [][]byte {
+	crds := [][]byte{manifests.PodGroupCRD, manifests.ElasticQuotaCRD, manifests.DataResidencyPolicyCRD, manifests.HostTopologyCRD, manifests.NodeNFVCapabilityCRD}
+	if diktyo {
+		crds = append(crds, manifests.AppGroupCRD, manifests.NetworkTopologyCRD)
+	}
+	return crds
+}
+
+// decodeCRDs decodes the objects of a multi-document CRD manifest.
+func decodeCRDs(manifest []byte) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		// The status is owned by the API server.
+		unstructured.RemoveNestedField(obj.Object, "status")
+		objs = append(objs, obj)
+	}
+}
+
+// installCRDs applies the embedded CRD manifests with server-side apply,
+// creating the missing CRDs and upgrading the existing ones to the version of the binary.
+func installCRDs(ctx context.Context, c client.Client, diktyo bool) error {
+	for _, manifest := range embeddedCRDs(diktyo) {
+		objs, err := decodeCRDs(manifest)
+		if err != nil {
+			return fmt.Errorf("decoding embedded CRD manifest: %w", err)
+		}
+		for _, obj := range objs {
+			if err := c.Patch(ctx, obj, client.Apply, client.FieldOwner(crdFieldManager), client.ForceOwnership); err != nil {
+				return fmt.Errorf("applying CRD %q: %w", obj.GetName(), err)
+			}
+			setupLog.Info("applied CRD", "crd", obj.GetName())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestEmbeddedCRDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		diktyo bool
+		want   []string
+	}{
+		{
+			name: "scheduling CRDs",
+			want: []string{
+				"podgroups.scheduling.x-k8s.io",
+				"elasticquotas.scheduling.x-k8s.io",
+				"dataresidencypolicies.scheduling.x-k8s.io",
+				"hosttopologies.scheduling.x-k8s.io",
+				"nodenfvcapabilities.scheduling.x-k8s.io",
+			},
+		},
+		{
+			name:   "scheduling and diktyo CRDs",
+			diktyo: true,
+			want: []string{
+				"podgroups.scheduling.x-k8s.io",
+				"elasticquotas.scheduling.x-k8s.io",
+				"dataresidencypolicies.scheduling.x-k8s.io",
+				"hosttopologies.scheduling.x-k8s.io",
+				"nodenfvcapabilities.scheduling.x-k8s.io",
+				"appgroups.appgroup.diktyo.x-k8s.io",
+				"networktopologies.networktopology.diktyo.x-k8s.io",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, manifest := range embeddedCRDs(tt.diktyo) {
+				objs, err := decodeCRDs(manifest)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				for _, obj := range objs {
+					if obj.GetKind() != "CustomResourceDefinition" {
+						t.Errorf("unexpected kind %q for %q", obj.GetKind(), obj.GetName())
+					}
+					got = append(got, obj.GetName())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected CRDs %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEmbeddedCRDVersions(t *testing.T) {
+	want := map[string][]string{
+		"podgroups.scheduling.x-k8s.io":     {"v1alpha1", "v1beta1"},
+		"elasticquotas.scheduling.x-k8s.io": {"v1alpha1", "v1beta1"},
+	}
+	for _, manifest := range embeddedCRDs(false) {
+		objs, err := decodeCRDs(manifest)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, obj := range objs {
+			wantVersions, ok := want[obj.GetName()]
+			if !ok {
+				continue
+			}
+			versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+			var served, stored []string
+			for _, v := range versions {
+				version := v.(map[string]interface{})
+				if version["served"] == true {
+					served = append(served, version["name"].(string))
+				}
+				if version["storage"] == true {
+					stored = append(stored, version["name"].(string))
+				}
+			}
+			if !reflect.DeepEqual(served, wantVersions) {
+				t.Errorf("expected %q to serve %v, got %v", obj.GetName(), wantVersions, served)
+			}
+			// v1alpha1 stays the storage version, so that the existing objects don't need to be migrated
+			if !reflect.DeepEqual(stored, []string{"v1alpha1"}) {
+				t.Errorf("expected %q to store v1alpha1, got %v", obj.GetName(), stored)
+			}
+		}
+	}
+}
@@ -17,7 +17,12 @@ limitations under the License.
 package app
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/pflag"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/controllers"
 )
 
 type ServerRunOptions struct {
@@ -27,6 +32,66 @@ type ServerRunOptions struct {
 	ApiServerBurst       int
 	Workers              int
 	EnableLeaderElection bool
+	// NetworkCostScoringAddr : address of the NetworkCostAware multi-cluster scoring endpoint
+	NetworkCostScoringAddr string
+	// NetworkCostScoringCertDir : directory of the serving certificate of the scoring endpoint, tls.crt and tls.key
+	NetworkCostScoringCertDir string
+	// InstallCRDs : apply the embedded CRDs at startup with server-side apply
+	InstallCRDs bool
+	// InstallDiktyoCRDs : also apply the diktyo AppGroup and NetworkTopology CRDs
+	InstallDiktyoCRDs bool
+	// EnableConversionWebhook : serve the conversion webhook of the PodGroup and ElasticQuota CRDs
+	EnableConversionWebhook bool
+	// WebhookPort : port of the webhook server
+	WebhookPort int
+	// WebhookCertDir : directory of the serving certificate of the webhook server, tls.crt and tls.key
+	WebhookCertDir string
+	// MigrateStorageVersion : migrate the PodGroups and ElasticQuotas to the storage version of their CRD at startup
+	MigrateStorageVersion bool
+	// NetworkTopologyName : name of the NetworkTopology CR maintained from the latencies measured by the network probes, disabled if empty
+	NetworkTopologyName string
+	// NetworkTopologyNamespace : namespace of the NetworkTopology CR and of the network probe DaemonSet
+	NetworkTopologyNamespace string
+	// NetworkProbeImage : image of the network probes, i.e. of the controller
+	NetworkProbeImage string
+	// NetworkProbeServiceAccount : service account of the network probes
+	NetworkProbeServiceAccount string
+	// NetworkProbePort : host port of the network probes
+	NetworkProbePort int
+	// NetworkProbeInterval : period of the latency measurements of the network probes
+	NetworkProbeInterval time.Duration
+	// NetworkProbe : run as a network probe of the DaemonSet deployed by the NetworkTopology controller
+	NetworkProbe bool
+	// PodGroupRateLimiter : backoff and rate limits of the reconciliation queue of the PodGroup controller
+	PodGroupRateLimiter controllers.RateLimiterOptions
+	// PodGroupPartialScheduleTimeout : how long a PodGroup may stay partially scheduled before its scheduled members are evicted
+	PodGroupPartialScheduleTimeout time.Duration
+	// ElasticQuotaRateLimiter : backoff and rate limits of the reconciliation queue of the ElasticQuota controller
+	ElasticQuotaRateLimiter controllers.RateLimiterOptions
+	// ElasticQuotaMaxUsageWarningPercent : percentage of its max from which the usage of an ElasticQuota is warned about
+	ElasticQuotaMaxUsageWarningPercent int
+	// NetworkTopologyRateLimiter : backoff and rate limits of the reconciliation queue of the NetworkTopology controller
+	NetworkTopologyRateLimiter controllers.RateLimiterOptions
+	// AppGroupCostReportInterval : period of the reports of the network costs of the AppGroups, disabled if 0
+	AppGroupCostReportInterval time.Duration
+	// AppGroupCostNetworkTopologyName : name of the NetworkTopology CR giving the costs of the reports, in the NetworkTopologyNamespace
+	AppGroupCostNetworkTopologyName string
+	// AppGroupCostWeightsName : weights of the NetworkTopology CR giving the costs of the reports
+	AppGroupCostWeightsName string
+	// AppGroupCostRateLimiter : backoff and rate limits of the reconciliation queue of the AppGroup cost controller
+	AppGroupCostRateLimiter controllers.RateLimiterOptions
+	// PowerProfileWatcherAddress : address of the load watcher service giving the power of the nodes to learn the power profiles from, disabled if empty
+	PowerProfileWatcherAddress string
+	// PowerProfileMetric : energy metric of load watcher giving the power of the nodes
+	PowerProfileMetric string
+	// PowerProfileMetricScale : watts per unit of the PowerProfileMetric
+	PowerProfileMetricScale float64
+	// PowerProfileConfigMap : "<namespace>/<name>" ConfigMap the power profiles of the workloads are stored in, for the Peaks plugin
+	PowerProfileConfigMap string
+	// PowerProfileSettleTime : how long after the placement of a pod the power of its node is measured again
+	PowerProfileSettleTime time.Duration
+	// PowerProfileRateLimiter : backoff and rate limits of the reconciliation queue of the power profile controller
+	PowerProfileRateLimiter controllers.RateLimiterOptions
 }
 
 func NewServerRunOptions() *ServerRunOptions {
@@ -42,4 +107,42 @@ func (s *ServerRunOptions) addAllFlags() {
 	pflag.IntVar(&s.ApiServerBurst, "burst", 10, "burst of query apiserver.")
 	pflag.IntVar(&s.Workers, "workers", 1, "workers of scheduler-plugin-controllers.")
 	pflag.BoolVar(&s.EnableLeaderElection, "enableLeaderElection", s.EnableLeaderElection, "If EnableLeaderElection for controller.")
+	pflag.StringVar(&s.NetworkCostScoringAddr, "networkCostScoringAddr", "", "gRPC bind address of the NetworkCostAware multi-cluster scoring endpoint, disabled if empty.")
+	pflag.StringVar(&s.NetworkCostScoringCertDir, "networkCostScoringCertDir", "", "Directory of the tls.crt and tls.key serving certificate of the multi-cluster scoring endpoint, served in plaintext if empty.")
+	pflag.BoolVar(&s.InstallCRDs, "installCRDs", false, "Install or upgrade the PodGroup, ElasticQuota, DataResidencyPolicy, HostTopology and NodeNFVCapability CRDs at startup with server-side apply.")
+	pflag.BoolVar(&s.InstallDiktyoCRDs, "installDiktyoCRDs", false, "With installCRDs, also install or upgrade the diktyo AppGroup and NetworkTopology CRDs.")
+	pflag.BoolVar(&s.EnableConversionWebhook, "enableConversionWebhook", false, "Serve the conversion webhook of the PodGroup and ElasticQuota CRDs between their v1alpha1 and v1beta1 versions.")
+	pflag.IntVar(&s.WebhookPort, "webhookPort", 9443, "Port of the webhook server.")
+	pflag.StringVar(&s.WebhookCertDir, "webhookCertDir", "", "Directory of the tls.crt and tls.key serving certificate of the webhook server, a temporary directory if empty.")
+	pflag.BoolVar(&s.MigrateStorageVersion, "migrateStorageVersion", false, "Migrate the stored PodGroups and ElasticQuotas to the storage version of their CRD at startup.")
+	pflag.StringVar(&s.NetworkTopologyName, "networkTopologyName", "", "Name of the NetworkTopology CR whose NetperfCosts weights are maintained from the latencies measured by network probes, disabled if empty.")
+	pflag.StringVar(&s.NetworkTopologyNamespace, "networkTopologyNamespace", "default", "Namespace of the NetworkTopology CR and of the network probe DaemonSet.")
+	pflag.StringVar(&s.NetworkProbeImage, "networkProbeImage", "", "Image of the network probes, i.e. the image of the controller, required with networkTopologyName.")
+	pflag.StringVar(&s.NetworkProbeServiceAccount, "networkProbeServiceAccount", "network-probe", "Service account of the network probes, allowed to list and patch the pods of the probe DaemonSet.")
+	pflag.IntVar(&s.NetworkProbePort, "networkProbePort", 8091, "Host port the network probes listen on and connect to.")
+	pflag.DurationVar(&s.NetworkProbeInterval, "networkProbeInterval", 30*time.Second, "Period of the latency measurements of the network probes.")
+	pflag.BoolVar(&s.NetworkProbe, "networkProbe", false, "Run as a network probe of the DaemonSet deployed by the NetworkTopology controller, instead of the controllers.")
+	pflag.DurationVar(&s.PodGroupPartialScheduleTimeout, "podGroupPartialScheduleTimeout", 0, "How long a PodGroup may keep fewer than minMember members scheduled before they are evicted for the whole group to retry, disabled if 0.")
+	pflag.IntVar(&s.ElasticQuotaMaxUsageWarningPercent, "elasticQuotaMaxUsageWarningPercent", 90, "Percentage of its max from which a NearMax event warns about the usage of an ElasticQuota, disabled if 0.")
+	pflag.DurationVar(&s.AppGroupCostReportInterval, "appGroupCostReportInterval", 0, "Period of the reports of the network costs of the AppGroups per zone pair in the metrics of the controller, disabled if 0.")
+	pflag.StringVar(&s.AppGroupCostNetworkTopologyName, "appGroupCostNetworkTopologyName", "nt-default", "Name of the NetworkTopology CR giving the costs of the AppGroup cost reports, in networkTopologyNamespace.")
+	pflag.StringVar(&s.AppGroupCostWeightsName, "appGroupCostWeightsName", "UserDefined", "Weights of the NetworkTopology CR giving the costs of the AppGroup cost reports.")
+	pflag.StringVar(&s.PowerProfileWatcherAddress, "powerProfileWatcherAddress", "", "Address of the load watcher service giving the power of the nodes the power profiles of the workloads are learned from, disabled if empty.")
+	pflag.StringVar(&s.PowerProfileMetric, "powerProfileMetric", "scaph_host_power_microwatts", "Energy metric of load watcher giving the power of the nodes.")
+	pflag.Float64Var(&s.PowerProfileMetricScale, "powerProfileMetricScale", 1e-6, "Watts per unit of the powerProfileMetric.")
+	pflag.StringVar(&s.PowerProfileConfigMap, "powerProfileConfigMap", "kube-system/peaks-power-profiles", "<namespace>/<name> ConfigMap the power profiles of the workloads are stored in, for the Peaks plugin.")
+	pflag.DurationVar(&s.PowerProfileSettleTime, "powerProfileSettleTime", 15*time.Minute, "How long after the placement of a pod the power of its node is measured again, at least the window of load watcher.")
+	addRateLimiterFlags(&s.PodGroupRateLimiter, "podGroup", "PodGroup")
+	addRateLimiterFlags(&s.ElasticQuotaRateLimiter, "elasticQuota", "ElasticQuota")
+	addRateLimiterFlags(&s.NetworkTopologyRateLimiter, "networkTopology", "NetworkTopology")
+	addRateLimiterFlags(&s.AppGroupCostRateLimiter, "appGroupCost", "AppGroup cost")
+	addRateLimiterFlags(&s.PowerProfileRateLimiter, "powerProfile", "power profile")
+}
+
+// addRateLimiterFlags adds the flags of the rate limiter of a controller, defaulting to the rate limiter of controller-runtime.
+func addRateLimiterFlags(o *controllers.RateLimiterOptions, prefix, controller string) {
+	pflag.DurationVar(&o.BaseDelay, prefix+"BaseDelay", 5*time.Millisecond, fmt.Sprintf("Initial delay of the exponential backoff of the failed reconciliations of the %s controller.", controller))
+	pflag.DurationVar(&o.MaxDelay, prefix+"MaxDelay", 1000*time.Second, fmt.Sprintf("Maximum delay of the exponential backoff of the failed reconciliations of the %s controller.", controller))
+	pflag.Float64Var(&o.QPS, prefix+"QPS", 10, fmt.Sprintf("Overall rate of the reconciliations of the %s controller.", controller))
+	pflag.IntVar(&o.Burst, prefix+"Burst", 100, fmt.Sprintf("Bucket size of the overall rate limit of the reconciliations of the %s controller.", controller))
 }
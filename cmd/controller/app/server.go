@@ -17,13 +17,25 @@ limitations under the License.
 package app
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	// schedulingv1a1 "sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 	// "sigs.k8s.io/scheduler-plugins/pkg/controllers"
@@ -32,8 +44,16 @@ import (
 	// "github.com/amiraBenamer20/controller-runtime/pkg/healthz"
 	// metricsserver "github.com/amiraBenamer20/controller-runtime/pkg/metrics/server"
 
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
 	schedulingv1a1 "github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	schedulingv1b1 "github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1beta1"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/controllers"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/multicluster"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/networkprobe"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran"
 )
 
 var (
@@ -43,8 +63,12 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 
 	utilruntime.Must(schedulingv1a1.AddToScheme(scheme))
+	utilruntime.Must(schedulingv1b1.AddToScheme(scheme))
+	utilruntime.Must(agv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(ntv1alpha1.AddToScheme(scheme))
 }
 
 func Run(s *ServerRunOptions) error {
@@ -52,6 +76,10 @@ func Run(s *ServerRunOptions) error {
 	config.QPS = float32(s.ApiServerQPS)
 	config.Burst = s.ApiServerBurst
 
+	if s.NetworkProbe {
+		return runNetworkProbe(s, kubernetes.NewForConfigOrDie(config))
+	}
+
 	// Controller Runtime Controllers
 	ctrl.SetLogger(klogr.New())
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
@@ -59,6 +87,10 @@ func Run(s *ServerRunOptions) error {
 		Metrics: metricsserver.Options{
 			BindAddress: s.MetricsAddr,
 		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    s.WebhookPort,
+			CertDir: s.WebhookCertDir,
+		}),
 		HealthProbeBindAddress:  s.ProbeAddr,
 		LeaderElection:          s.EnableLeaderElection,
 		LeaderElectionID:        "sched-plugins-controllers",
@@ -69,24 +101,145 @@ func Run(s *ServerRunOptions) error {
 		return err
 	}
 
+	if s.InstallCRDs || s.MigrateStorageVersion {
+		c, err := client.New(config, client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create client")
+			return err
+		}
+		if s.InstallCRDs {
+			if err := installCRDs(context.Background(), c, s.InstallDiktyoCRDs); err != nil {
+				setupLog.Error(err, "unable to install CRDs")
+				return err
+			}
+		}
+		if s.MigrateStorageVersion {
+			// run by the leader only, once the conversion webhook is served
+			if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+				if err := migrateStorageVersions(ctx, c); err != nil {
+					setupLog.Error(err, "unable to migrate the storage version")
+				}
+				return nil
+			})); err != nil {
+				setupLog.Error(err, "unable to add the storage version migration")
+				return err
+			}
+		}
+	}
+
+	if err := s.PodGroupRateLimiter.Validate(); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PodGroup")
+		return err
+	}
 	if err = (&controllers.PodGroupReconciler{
-		Client:  mgr.GetClient(),
-		Scheme:  mgr.GetScheme(),
-		Workers: s.Workers,
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Workers:     s.Workers,
+		RateLimiter: s.PodGroupRateLimiter,
+
+		PartialScheduleTimeout: s.PodGroupPartialScheduleTimeout,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PodGroup")
 		return err
 	}
 
+	if err := s.ElasticQuotaRateLimiter.Validate(); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticQuota")
+		return err
+	}
 	if err = (&controllers.ElasticQuotaReconciler{
-		Client:  mgr.GetClient(),
-		Scheme:  mgr.GetScheme(),
-		Workers: s.Workers,
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Workers:     s.Workers,
+		RateLimiter: s.ElasticQuotaRateLimiter,
+
+		MaxUsageWarningPercent: s.ElasticQuotaMaxUsageWarningPercent,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ElasticQuota")
 		return err
 	}
 
+	if s.NetworkTopologyName != "" {
+		if s.NetworkProbeImage == "" {
+			err := fmt.Errorf("networkProbeImage is required with networkTopologyName")
+			setupLog.Error(err, "unable to create controller", "controller", "NetworkTopology")
+			return err
+		}
+		if err := s.NetworkTopologyRateLimiter.Validate(); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NetworkTopology")
+			return err
+		}
+		if err = (&controllers.NetworkTopologyReconciler{
+			Client:              mgr.GetClient(),
+			Scheme:              mgr.GetScheme(),
+			Namespace:           s.NetworkTopologyNamespace,
+			Name:                s.NetworkTopologyName,
+			ProbeImage:          s.NetworkProbeImage,
+			ProbeServiceAccount: s.NetworkProbeServiceAccount,
+			ProbePort:           s.NetworkProbePort,
+			ProbeInterval:       s.NetworkProbeInterval,
+			RateLimiter:         s.NetworkTopologyRateLimiter,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NetworkTopology")
+			return err
+		}
+	}
+
+	if s.AppGroupCostReportInterval > 0 {
+		if err := s.AppGroupCostRateLimiter.Validate(); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AppGroupCost")
+			return err
+		}
+		if err = (&controllers.AppGroupCostReconciler{
+			Client:                   mgr.GetClient(),
+			Scheme:                   mgr.GetScheme(),
+			NetworkTopologyNamespace: s.NetworkTopologyNamespace,
+			NetworkTopologyName:      s.AppGroupCostNetworkTopologyName,
+			WeightsName:              s.AppGroupCostWeightsName,
+			Interval:                 s.AppGroupCostReportInterval,
+			RateLimiter:              s.AppGroupCostRateLimiter,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AppGroupCost")
+			return err
+		}
+	}
+
+	if s.PowerProfileWatcherAddress != "" {
+		if err := setupPowerProfileController(mgr, s); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "PowerProfile")
+			return err
+		}
+	}
+
+	if s.EnableConversionWebhook {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(&schedulingv1a1.PodGroup{}).Complete(); err != nil {
+			setupLog.Error(err, "unable to create conversion webhook", "webhook", "PodGroup")
+			return err
+		}
+		if err := ctrl.NewWebhookManagedBy(mgr).For(&schedulingv1a1.ElasticQuota{}).Complete(); err != nil {
+			setupLog.Error(err, "unable to create conversion webhook", "webhook", "ElasticQuota")
+			return err
+		}
+	}
+
+	if s.NetworkCostScoringAddr != "" {
+		var opts []grpc.ServerOption
+		if s.NetworkCostScoringCertDir != "" {
+			creds, err := multicluster.TLSCredentials(s.NetworkCostScoringCertDir)
+			if err != nil {
+				setupLog.Error(err, "unable to load the serving certificate of the NetworkCostAware multi-cluster scoring endpoint")
+				return err
+			}
+			opts = append(opts, grpc.Creds(creds))
+		} else {
+			setupLog.Info("Serving the NetworkCostAware multi-cluster scoring endpoint in plaintext, set networkCostScoringCertDir to serve it over TLS")
+		}
+		if err := mgr.Add(multicluster.NewEndpoint(s.NetworkCostScoringAddr, multicluster.NewServer(mgr.GetClient()), opts...)); err != nil {
+			setupLog.Error(err, "unable to add NetworkCostAware multi-cluster scoring endpoint")
+			return err
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		return err
@@ -102,3 +255,48 @@ func Run(s *ServerRunOptions) error {
 	}
 	return nil
 }
+
+// setupPowerProfileController sets up the controller learning the power profiles of the workloads, from the
+// power of the nodes given by load watcher.
+func setupPowerProfileController(mgr ctrl.Manager, s *ServerRunOptions) error {
+	namespace, name, ok := strings.Cut(s.PowerProfileConfigMap, "/")
+	if !ok || namespace == "" || name == "" {
+		return fmt.Errorf("invalid powerProfileConfigMap %q, want <namespace>/<name>", s.PowerProfileConfigMap)
+	}
+	if err := s.PowerProfileRateLimiter.Validate(); err != nil {
+		return err
+	}
+	collector, err := trimaran.NewCollector(klog.Background(), &pluginconfig.TrimaranSpec{WatcherAddress: s.PowerProfileWatcherAddress})
+	if err != nil {
+		return err
+	}
+	source := &trimaran.NodePowerSource{Collector: collector, Metric: s.PowerProfileMetric, Scale: s.PowerProfileMetricScale}
+	return (&controllers.PowerProfileReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Namespace:   namespace,
+		Name:        name,
+		NodePower:   source.NodePower,
+		SettleTime:  s.PowerProfileSettleTime,
+		RateLimiter: s.PowerProfileRateLimiter,
+	}).SetupWithManager(mgr)
+}
+
+// runNetworkProbe runs a network probe of the DaemonSet deployed by the NetworkTopology controller,
+// identified by the environment variables set by the DaemonSet.
+func runNetworkProbe(s *ServerRunOptions, client kubernetes.Interface) error {
+	prober := &networkprobe.Prober{
+		Client:    client,
+		Namespace: os.Getenv("POD_NAMESPACE"),
+		Name:      os.Getenv("POD_NAME"),
+		NodeName:  os.Getenv("NODE_NAME"),
+		Port:      s.NetworkProbePort,
+		Interval:  s.NetworkProbeInterval,
+		Samples:   5,
+	}
+	if err := prober.Run(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "unable to run the network probe")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// migratedCRDs are the CRDs served in several versions, whose objects are migrated to the storage version.
+var migratedCRDs = []string{"podgroups.scheduling.x-k8s.io", "elasticquotas.scheduling.x-k8s.io"}
+
+// migrateStorageVersions migrates the objects of the migrated CRDs to their storage version.
+func migrateStorageVersions(ctx context.Context, c client.Client) error {
+	for _, name := range migratedCRDs {
+		if err := migrateStorageVersion(ctx, c, name); err != nil {
+			return fmt.Errorf("migrating the storage version of %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// migrateStorageVersion rewrites the objects of the CRD, so that the API server stores them in the storage
+// version of the CRD, then records the storage version as the only stored version. The versions no longer
+// stored can then be removed from the CRD.
+func migrateStorageVersion(ctx context.Context, c client.Client, name string) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+		if apierrors.IsNotFound(err) {
+			setupLog.Info("CRD not installed, skipping the storage version migration", "crd", name)
+			return nil
+		}
+		return err
+	}
+	storageVersion := ""
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			storageVersion = version.Name
+		}
+	}
+	if storageVersion == "" {
+		return fmt.Errorf("no storage version")
+	}
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		return nil
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{Group: crd.Spec.Group, Version: storageVersion, Kind: crd.Spec.Names.ListKind})
+	if err := c.List(ctx, list); err != nil {
+		return err
+	}
+	for i := range list.Items {
+		if err := rewrite(ctx, c, &list.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			return err
+		}
+		crd.Status.StoredVersions = []string{storageVersion}
+		return c.Status().Update(ctx, crd)
+	})
+}
+
+// rewrite updates the object without changing it, which makes the API server store it in the storage version.
+func rewrite(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	err := c.Update(ctx, obj)
+	// the objects updated meanwhile are stored in the storage version already, and the deleted ones are gone
+	if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1a1 "github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestMigrateStorageVersion(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "podgroups.scheduling.x-k8s.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "scheduling.x-k8s.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "PodGroup", ListKind: "PodGroupList", Plural: "podgroups"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Storage: true},
+				{Name: "v1beta1", Served: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1alpha1", "v1beta1"}},
+	}
+	pg := &schedulingv1a1.PodGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg"}, Spec: schedulingv1a1.PodGroupSpec{MinMember: 2}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd, pg).WithStatusSubresource(crd).Build()
+
+	ctx := context.Background()
+	before := &schedulingv1a1.PodGroup{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pg"}, before); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrateStorageVersions(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &apiextensionsv1.CustomResourceDefinition{}
+	if err := c.Get(ctx, types.NamespacedName{Name: crd.Name}, got); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"v1alpha1"}; !reflect.DeepEqual(got.Status.StoredVersions, want) {
+		t.Errorf("expected the stored versions %v, got %v", want, got.Status.StoredVersions)
+	}
+	gotPG := &schedulingv1a1.PodGroup{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pg"}, gotPG); err != nil {
+		t.Fatal(err)
+	}
+	if gotPG.ResourceVersion == before.ResourceVersion || gotPG.Spec.MinMember != 2 {
+		t.Errorf("expected the PodGroup to be rewritten unchanged, got %+v", gotPG)
+	}
+}
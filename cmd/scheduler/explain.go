@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology"
+)
+
+// addFitExplainServer adds the --noderesourcetopology-explain-address flag to the scheduler command, which serves
+// the NodeResourceTopology fit explanation debug endpoint on its own listener while the scheduler runs. The endpoints
+// of the scheduler are served by a mux built by kube-scheduler, which doesn't let plugins mount their handlers.
+func addFitExplainServer(command *cobra.Command) {
+	var address string
+	command.Flags().StringVar(&address, "noderesourcetopology-explain-address", "", "The address to serve the NodeResourceTopology "+
+		"fit explanation debug endpoint on, at the "+noderesourcetopology.FitExplainPath+" path, e.g. 127.0.0.1:10271. Empty doesn't serve it.")
+
+	run := command.RunE
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		if address != "" {
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return err
+			}
+			mux := http.NewServeMux()
+			mux.Handle(noderesourcetopology.FitExplainPath, noderesourcetopology.NewFitExplainHandler(klog.Background()))
+			server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+			go func() {
+				if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					klog.Background().Error(err, "Failed to serve the NodeResourceTopology fit explanation", "address", address)
+				}
+			}()
+			defer server.Close()
+		}
+		return run(cmd, args)
+	}
+}
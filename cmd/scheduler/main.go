@@ -23,52 +23,111 @@ import (
 	_ "k8s.io/component-base/metrics/prometheus/clientgo" // for rest client metric registration
 	_ "k8s.io/component-base/metrics/prometheus/version"  // for version metric registration
 	"k8s.io/kubernetes/cmd/kube-scheduler/app"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/cacheisolation"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/capacityscheduling"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/coscheduling"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/criticalreserve"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/dataresidency"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/deadlineaware"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/drf"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/hostantiaffinity"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/networkoverhead"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/servicemeshlatency"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/topologicalsort"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/networkcost"//Amira
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/topologicalcnsort"//Amira
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/nfvaware"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesources"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/podstate"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/preemptiontoleration"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/qos"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/queuelength"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/shadow"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/sysched"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran/loadvariationriskbalancing"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran/lowriskovercommitment"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran/peaks"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran/targetloadpacking"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran/thermalaware"
 
 	// Ensure scheme package is initialized.
 	_ "github.com/amiraBenamer20/scheduler-plugins/apis/config/scheme"
 )
 
+// shadowed are the Filter and Score plugins which may also run in shadow mode, observing their decisions without
+// enforcing them, under the name given by shadow.Name.
+var shadowed = []struct {
+	name    string
+	factory frameworkruntime.PluginFactory
+}{
+	{cacheisolation.Name, cacheisolation.New},
+	{capacityscheduling.Name, capacityscheduling.New},
+	{criticalreserve.Name, criticalreserve.New},
+	{dataresidency.Name, dataresidency.New},
+	{deadlineaware.Name, deadlineaware.New},
+	{drf.Name, drf.New},
+	{hostantiaffinity.Name, hostantiaffinity.New},
+	{loadvariationriskbalancing.Name, loadvariationriskbalancing.New},
+	{networkoverhead.Name, networkoverhead.New},
+	{servicemeshlatency.Name, servicemeshlatency.New},
+	{networkcost.Name, networkcost.New},
+	{nfvaware.Name, nfvaware.New},
+	{noderesources.AllocatableName, noderesources.NewAllocatable},
+	{noderesources.FragmentationName, noderesources.NewFragmentation},
+	{noderesourcetopology.Name, noderesourcetopology.New},
+	{queuelength.Name, queuelength.New},
+	{targetloadpacking.Name, targetloadpacking.New},
+	{lowriskovercommitment.Name, lowriskovercommitment.New},
+	{sysched.Name, sysched.New},
+	{peaks.Name, peaks.New},
+	{thermalaware.Name, thermalaware.New},
+	{podstate.Name, podstate.New},
+}
+
 func main() {
 	// Register custom plugins to the scheduler framework.
 	// Later they can consist of scheduler profile(s) and hence
 	// used by various kinds of workloads.
-	command := app.NewSchedulerCommand(
+	options := []app.Option{
+		app.WithPlugin(cacheisolation.Name, cacheisolation.New),
 		app.WithPlugin(capacityscheduling.Name, capacityscheduling.New),
 		app.WithPlugin(coscheduling.Name, coscheduling.New),
+		app.WithPlugin(criticalreserve.Name, criticalreserve.New),
+		app.WithPlugin(dataresidency.Name, dataresidency.New),
+		app.WithPlugin(deadlineaware.Name, deadlineaware.New),
+		app.WithPlugin(drf.Name, drf.New),
+		app.WithPlugin(hostantiaffinity.Name, hostantiaffinity.New),
 		app.WithPlugin(loadvariationriskbalancing.Name, loadvariationriskbalancing.New),
 		app.WithPlugin(networkoverhead.Name, networkoverhead.New),
+		app.WithPlugin(servicemeshlatency.Name, servicemeshlatency.New),
 		app.WithPlugin(topologicalsort.Name, topologicalsort.New),
 		app.WithPlugin(networkcost.Name, networkcost.New),//Amira
 		app.WithPlugin(topologicalcnsort.Name, topologicalcnsort.New),//Amira
+		app.WithPlugin(nfvaware.Name, nfvaware.New),
 		app.WithPlugin(noderesources.AllocatableName, noderesources.NewAllocatable),
+		app.WithPlugin(noderesources.FragmentationName, noderesources.NewFragmentation),
 		app.WithPlugin(noderesourcetopology.Name, noderesourcetopology.New),
 		app.WithPlugin(preemptiontoleration.Name, preemptiontoleration.New),
+		app.WithPlugin(queuelength.Name, queuelength.New),
 		app.WithPlugin(targetloadpacking.Name, targetloadpacking.New),
 		app.WithPlugin(lowriskovercommitment.Name, lowriskovercommitment.New),
 		app.WithPlugin(sysched.Name, sysched.New),
 		app.WithPlugin(peaks.Name, peaks.New),
+		app.WithPlugin(thermalaware.Name, thermalaware.New),
 		// Sample plugins below.
 		// app.WithPlugin(crossnodepreemption.Name, crossnodepreemption.New),
 		app.WithPlugin(podstate.Name, podstate.New),
 		app.WithPlugin(qos.Name, qos.New),
-	)
+	}
+	for _, p := range shadowed {
+		options = append(options, app.WithPlugin(shadow.Name(p.name), shadow.New(p.name, p.factory)))
+	}
+	command := app.NewSchedulerCommand(options...)
+	addFitExplainServer(command)
+	command.AddCommand(newSimulateCommand())
 
 	code := cli.Run(command)
 	os.Exit(code)
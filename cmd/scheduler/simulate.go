@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	schedconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/simulator"
+)
+
+// simulateOptions are the flags of the simulate subcommand.
+type simulateOptions struct {
+	config        string
+	schedulerName string
+	snapshot      string
+	pods          string
+	kubeconfig    string
+	output        string
+}
+
+// newSimulateCommand returns the simulate subcommand, a dry-run of the plugins of a scheduler profile on a
+// snapshot of a cluster, reporting where the pods would be placed and why.
+func newSimulateCommand() *cobra.Command {
+	o := &simulateOptions{}
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Replay the pending pods of a cluster snapshot through the scheduler plugins, offline",
+		Long: `Replay the pending pods of a cluster snapshot through the plugins of a scheduler profile, offline,
+and report the outcome of each pod: its node, the scores of the feasible nodes per plugin,
+and the reasons of the nodes filtered out.
+
+The snapshot is read from YAML documents, e.g. recorded with
+  kubectl get nodes,pods,podgroups,appgroups,networktopologies -A -o yaml
+or from the live cluster of --kubeconfig if no --snapshot is given. Nothing is bound in the cluster.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.run(cmd)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&o.config, "config", o.config, "The KubeSchedulerConfiguration of the profile to simulate. Defaults to the default profile.")
+	flags.StringVar(&o.schedulerName, "scheduler-name", o.schedulerName, "The scheduler name of the profile to simulate. Defaults to the first profile.")
+	flags.StringVar(&o.snapshot, "snapshot", o.snapshot, "The YAML or JSON snapshot of the cluster. Its pods not bound to a node are replayed.")
+	flags.StringVar(&o.pods, "pods", o.pods, "The YAML or JSON pods to replay, in order, instead of the pending pods of the snapshot.")
+	flags.StringVar(&o.kubeconfig, "kubeconfig", o.kubeconfig, "The cluster to snapshot if no --snapshot is given. Otherwise the API server "+
+		"the custom resources of the snapshot are created on, for the plugins reading them, e.g. an envtest API server.")
+	flags.StringVarP(&o.output, "output", "o", "text", "The format of the report: text or json.")
+	return cmd
+}
+
+func (o *simulateOptions) run(cmd *cobra.Command) error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("unsupported output format %q", o.output)
+	}
+	if o.snapshot == "" && o.kubeconfig == "" {
+		return fmt.Errorf("either --snapshot or --kubeconfig is required")
+	}
+	ctx := cmd.Context()
+
+	profile, err := o.loadProfile()
+	if err != nil {
+		return err
+	}
+	cfg := simulator.Config{Profile: profile}
+	if o.kubeconfig != "" {
+		if cfg.KubeConfig, err = clientcmd.BuildConfigFromFlags("", o.kubeconfig); err != nil {
+			return err
+		}
+	}
+
+	var snapshot *simulator.Snapshot
+	var pods []*v1.Pod
+	if o.snapshot != "" {
+		f, err := os.Open(o.snapshot)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if snapshot, pods, err = simulator.LoadSnapshot(f); err != nil {
+			return err
+		}
+	} else {
+		if snapshot, pods, err = simulator.LoadClusterSnapshot(ctx, cfg.KubeConfig); err != nil {
+			return err
+		}
+		cfg.ObjectsInCluster = true
+	}
+	if o.pods != "" {
+		f, err := os.Open(o.pods)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if pods, err = simulator.LoadPods(f); err != nil {
+			return err
+		}
+	}
+
+	report, err := simulator.Run(ctx, cfg, snapshot, pods)
+	if err != nil {
+		return err
+	}
+	if o.output == "json" {
+		_, err = report.WriteTo(cmd.OutOrStdout())
+		return err
+	}
+	return report.WriteText(cmd.OutOrStdout())
+}
+
+// loadProfile returns the profile of the configuration, or the default profile.
+func (o *simulateOptions) loadProfile() (*schedconfig.KubeSchedulerProfile, error) {
+	if o.config == "" {
+		return simulator.DefaultProfile()
+	}
+	data, err := os.ReadFile(o.config)
+	if err != nil {
+		return nil, err
+	}
+	return simulator.LoadProfile(data, o.schedulerName)
+}
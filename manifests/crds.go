@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package manifests embeds the CRD manifests shipped with the scheduler plugins,
+// so that the binaries can install the CRDs matching their version.
+package manifests
+
+import (
+	_ "embed"
+)
+
+var (
+	// PodGroupCRD is the CRD manifest of PodGroup.
+	//go:embed coscheduling/crd.yaml
+	PodGroupCRD []byte
+
+	// ElasticQuotaCRD is the CRD manifest of ElasticQuota.
+	//go:embed capacityscheduling/crd.yaml
+	ElasticQuotaCRD []byte
+
+	// DataResidencyPolicyCRD is the CRD manifest of DataResidencyPolicy.
+	//go:embed dataresidency/crd.yaml
+	DataResidencyPolicyCRD []byte
+
+	// HostTopologyCRD is the CRD manifest of HostTopology.
+	//go:embed hostantiaffinity/crd.yaml
+	HostTopologyCRD []byte
+
+	// NodeNFVCapabilityCRD is the CRD manifest of NodeNFVCapability.
+	//go:embed nfvaware/crd.yaml
+	NodeNFVCapabilityCRD []byte
+
+	// AppGroupCRD is the CRD manifest of the diktyo AppGroup.
+	//go:embed appgroup/crd.yaml
+	AppGroupCRD []byte
+
+	// NetworkTopologyCRD is the CRD manifest of the diktyo NetworkTopology.
+	//go:embed networktopology/crd.yaml
+	NetworkTopologyCRD []byte
+)
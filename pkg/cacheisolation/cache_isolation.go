@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cacheisolation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/clock"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "CacheIsolation"
+
+	// LLCOccupancyAnnotation is the node annotation holding the share of the last level cache occupied
+	// on the node, in percent, e.g. the RDT llc_occupancy of all the resctrl groups over the cache size.
+	LLCOccupancyAnnotation = "cacheisolation.scheduling.x-k8s.io/llc-occupancy"
+	// MemoryBandwidthAnnotation is the node annotation holding the share of the memory bandwidth used
+	// on the node, in percent, e.g. the RDT mbm_total_bytes rate over the peak bandwidth.
+	MemoryBandwidthAnnotation = "cacheisolation.scheduling.x-k8s.io/memory-bandwidth"
+	// MetricsTimestampAnnotation is the node annotation holding the time the occupancy metrics were
+	// measured, in RFC 3339 format.
+	MetricsTimestampAnnotation = "cacheisolation.scheduling.x-k8s.io/timestamp"
+)
+
+// CacheIsolation is a plugin keeping the latency-sensitive pods away from the nodes where the last
+// level cache and the memory bandwidth are contended, e.g. by cache-hungry pods. The occupancy of the
+// nodes is published as node annotations by a node agent reading Intel RDT.
+type CacheIsolation struct {
+	handle                framework.Handle
+	latencySensitive      labels.Selector
+	cacheHungry           labels.Selector
+	llcOccupancyWeight    int64
+	memoryBandwidthWeight int64
+	cacheHungryPodPenalty int64
+	metricsMaxAge         time.Duration
+	clock                 clock.Clock
+}
+
+var _ framework.ScorePlugin = &CacheIsolation{}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (c *CacheIsolation) Name() string {
+	return Name
+}
+
+// New initializes a new plugin and returns it.
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.CacheIsolationArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type CacheIsolationArgs, got %T", obj)
+	}
+	latencySensitive, err := metav1.LabelSelectorAsSelector(args.LatencySensitiveSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latencySensitiveSelector: %w", err)
+	}
+	cacheHungry, err := metav1.LabelSelectorAsSelector(args.CacheHungrySelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cacheHungrySelector: %w", err)
+	}
+	if args.LLCOccupancyWeight < 0 || args.MemoryBandwidthWeight < 0 || args.CacheHungryPodPenalty < 0 {
+		return nil, fmt.Errorf("llcOccupancyWeight, memoryBandwidthWeight and cacheHungryPodPenalty should not be negative")
+	}
+	if args.MetricsMaxAgeSeconds <= 0 {
+		return nil, fmt.Errorf("metricsMaxAgeSeconds should be positive, got %d", args.MetricsMaxAgeSeconds)
+	}
+
+	klog.FromContext(ctx).V(4).Info("CacheIsolation start", "latencySensitive", latencySensitive, "cacheHungry", cacheHungry)
+	return &CacheIsolation{
+		handle:                handle,
+		latencySensitive:      latencySensitive,
+		cacheHungry:           cacheHungry,
+		llcOccupancyWeight:    args.LLCOccupancyWeight,
+		memoryBandwidthWeight: args.MemoryBandwidthWeight,
+		cacheHungryPodPenalty: args.CacheHungryPodPenalty,
+		metricsMaxAge:         time.Duration(args.MetricsMaxAgeSeconds) * time.Second,
+		clock:                 clock.RealClock{},
+	}, nil
+}
+
+// Score favors the nodes with the least cache pressure for the latency-sensitive pods. All the nodes
+// score equally for the other pods.
+func (c *CacheIsolation) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	if !c.latencySensitive.Matches(labels.Set(pod.Labels)) {
+		return 0, nil
+	}
+	nodeInfo, err := c.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+	}
+	pressure := c.cachePressure(nodeInfo)
+	klog.FromContext(ctx).V(6).Info("Cache pressure of the node for the latency-sensitive pod", "pod", klog.KObj(pod), "node", nodeName, "pressure", pressure)
+	return max(framework.MaxNodeScore-pressure, framework.MinNodeScore), nil
+}
+
+// ScoreExtensions returns nil as the scores are already within the node score range.
+func (c *CacheIsolation) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// cachePressure returns the weighted mean of the LLC occupancy and the memory bandwidth utilization of
+// the node, in percent, plus the penalty of the cache-hungry pods running on the node. The metrics are
+// ignored when missing or older than the maximum age, leaving only the penalty of the cache-hungry pods.
+func (c *CacheIsolation) cachePressure(nodeInfo *framework.NodeInfo) int64 {
+	var pressure int64
+	if llc, mbw, ok := c.occupancy(nodeInfo.Node()); ok && c.llcOccupancyWeight+c.memoryBandwidthWeight > 0 {
+		mean := (float64(c.llcOccupancyWeight)*llc + float64(c.memoryBandwidthWeight)*mbw) /
+			float64(c.llcOccupancyWeight+c.memoryBandwidthWeight)
+		pressure = int64(math.Round(mean))
+	}
+	for _, podInfo := range nodeInfo.Pods {
+		if podInfo.Pod.DeletionTimestamp == nil && c.cacheHungry.Matches(labels.Set(podInfo.Pod.Labels)) {
+			pressure += c.cacheHungryPodPenalty
+		}
+	}
+	return pressure
+}
+
+// occupancy returns the LLC occupancy and the memory bandwidth utilization of the node, in percent,
+// and whether they are available and fresh.
+func (c *CacheIsolation) occupancy(node *v1.Node) (float64, float64, bool) {
+	if node == nil {
+		return 0, 0, false
+	}
+	timestamp, err := time.Parse(time.RFC3339, node.Annotations[MetricsTimestampAnnotation])
+	if err != nil || c.clock.Since(timestamp) > c.metricsMaxAge {
+		return 0, 0, false
+	}
+	llc, llcOK := percentAnnotation(node, LLCOccupancyAnnotation)
+	mbw, mbwOK := percentAnnotation(node, MemoryBandwidthAnnotation)
+	if !llcOK || !mbwOK {
+		return 0, 0, false
+	}
+	return llc, mbw, true
+}
+
+// percentAnnotation returns the value of a percent annotation of the node, capped to 0-100.
+func percentAnnotation(node *v1.Node, key string) (float64, bool) {
+	value, ok := node.Annotations[key]
+	if !ok {
+		return 0, false
+	}
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(percent) {
+		klog.V(5).InfoS("Invalid cache occupancy annotation", "node", klog.KObj(node), "annotation", key, "value", value)
+		return 0, false
+	}
+	return min(max(percent, 0), 100), true
+}
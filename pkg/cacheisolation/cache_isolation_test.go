@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cacheisolation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+const profileLabel = "scheduling.x-k8s.io/cache-profile"
+
+func newTestCacheIsolation(now time.Time) *CacheIsolation {
+	return &CacheIsolation{
+		latencySensitive:      labels.SelectorFromSet(labels.Set{profileLabel: "latency-sensitive"}),
+		cacheHungry:           labels.SelectorFromSet(labels.Set{profileLabel: "cache-hungry"}),
+		llcOccupancyWeight:    1,
+		memoryBandwidthWeight: 1,
+		cacheHungryPodPenalty: 20,
+		metricsMaxAge:         5 * time.Minute,
+		clock:                 testingclock.NewFakeClock(now),
+	}
+}
+
+// makeNodeInfo returns the node info of a node with the given annotations, running the given pods.
+func makeNodeInfo(annotations map[string]string, pods ...*v1.Pod) *framework.NodeInfo {
+	node := st.MakeNode().Name("node1").Obj()
+	node.Annotations = annotations
+	nodeInfo := framework.NewNodeInfo(pods...)
+	nodeInfo.SetNode(node)
+	return nodeInfo
+}
+
+func makeProfilePod(name string, profile string) *v1.Pod {
+	return st.MakePod().Namespace("default").Name(name).Node("node1").Label(profileLabel, profile).Obj()
+}
+
+func TestNew(t *testing.T) {
+	validArgs := func() *config.CacheIsolationArgs {
+		return &config.CacheIsolationArgs{
+			LatencySensitiveSelector: &metav1.LabelSelector{MatchLabels: map[string]string{profileLabel: "latency-sensitive"}},
+			CacheHungrySelector:      &metav1.LabelSelector{MatchLabels: map[string]string{profileLabel: "cache-hungry"}},
+			LLCOccupancyWeight:       1,
+			MemoryBandwidthWeight:    1,
+			CacheHungryPodPenalty:    20,
+			MetricsMaxAgeSeconds:     300,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(args *config.CacheIsolationArgs)
+		wantErr bool
+	}{
+		{
+			name:   "valid args",
+			mutate: func(args *config.CacheIsolationArgs) {},
+		},
+		{
+			name: "invalid selector",
+			mutate: func(args *config.CacheIsolationArgs) {
+				args.CacheHungrySelector.MatchExpressions = []metav1.LabelSelectorRequirement{{Key: profileLabel, Operator: "Unknown"}}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "negative weight",
+			mutate:  func(args *config.CacheIsolationArgs) { args.LLCOccupancyWeight = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "no metrics max age",
+			mutate:  func(args *config.CacheIsolationArgs) { args.MetricsMaxAgeSeconds = 0 },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := validArgs()
+			tt.mutate(args)
+			_, err := New(context.TODO(), args, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCachePressure(t *testing.T) {
+	now := time.Now()
+	fresh := now.Add(-time.Minute).Format(time.RFC3339)
+	stale := now.Add(-time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name     string
+		nodeInfo *framework.NodeInfo
+		expected int64
+	}{
+		{
+			name:     "no metrics nor cache-hungry pods",
+			nodeInfo: makeNodeInfo(nil, makeProfilePod("p1", "latency-sensitive")),
+			expected: 0,
+		},
+		{
+			name: "mean of the fresh metrics",
+			nodeInfo: makeNodeInfo(map[string]string{
+				LLCOccupancyAnnotation:     "60",
+				MemoryBandwidthAnnotation:  "30",
+				MetricsTimestampAnnotation: fresh,
+			}),
+			expected: 45,
+		},
+		{
+			name: "stale metrics are ignored",
+			nodeInfo: makeNodeInfo(map[string]string{
+				LLCOccupancyAnnotation:     "60",
+				MemoryBandwidthAnnotation:  "30",
+				MetricsTimestampAnnotation: stale,
+			}),
+			expected: 0,
+		},
+		{
+			name: "invalid metrics are ignored",
+			nodeInfo: makeNodeInfo(map[string]string{
+				LLCOccupancyAnnotation:     "high",
+				MemoryBandwidthAnnotation:  "30",
+				MetricsTimestampAnnotation: fresh,
+			}),
+			expected: 0,
+		},
+		{
+			name: "metrics are capped",
+			nodeInfo: makeNodeInfo(map[string]string{
+				LLCOccupancyAnnotation:     "150",
+				MemoryBandwidthAnnotation:  "-10",
+				MetricsTimestampAnnotation: fresh,
+			}),
+			expected: 50,
+		},
+		{
+			name: "cache-hungry pods add their penalty",
+			nodeInfo: makeNodeInfo(map[string]string{
+				LLCOccupancyAnnotation:     "20",
+				MemoryBandwidthAnnotation:  "10",
+				MetricsTimestampAnnotation: fresh,
+			}, makeProfilePod("p1", "cache-hungry"), makeProfilePod("p2", "cache-hungry"), makeProfilePod("p3", "latency-sensitive")),
+			expected: 55,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCacheIsolation(now)
+			if got := c.cachePressure(tt.nodeInfo); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	c := newTestCacheIsolation(time.Now())
+
+	// the pods which are not latency-sensitive score all the nodes equally, without reading the snapshot
+	score, status := c.Score(context.TODO(), nil, makeProfilePod("p", "cache-hungry"), "node1")
+	if !status.IsSuccess() || score != 0 {
+		t.Errorf("expected score 0, got %v with status %v", score, status)
+	}
+}
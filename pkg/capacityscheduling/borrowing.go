@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// ErrReasonBorrowingTurn is the reason for a pod waiting for older borrowers to borrow resources first.
+const ErrReasonBorrowingTurn = "older pods are queued to borrow resources beyond the min of ElasticQuotas"
+
+// borrower is a pod queued to borrow resources beyond the min of its ElasticQuota.
+type borrower struct {
+	uid     types.UID
+	created time.Time
+	// queued is when the pod was first queued, from which it ages.
+	queued time.Time
+	// seen is when the pod was last attempted, from which it expires.
+	seen time.Time
+}
+
+// borrowingQueue admits the pods borrowing resources beyond the min of their ElasticQuota in FIFO
+// order per ElasticQuota, rather than in the order the scheduler happens to pop them. Across
+// ElasticQuotas, the oldest borrower of an ElasticQuota queued for longer than aging takes
+// precedence over the borrowers queued after it.
+type borrowingQueue struct {
+	sync.Mutex
+	aging      time.Duration
+	expiration time.Duration
+	// perQuota orders the borrowers within each ElasticQuota only, when the borrowing policy arbitrates across them.
+	perQuota bool
+	// borrowers of each ElasticQuota(namespace), ordered by creation.
+	borrowers map[string][]*borrower
+}
+
+func newBorrowingQueue(spec *config.BorrowingQueueSpec) (*borrowingQueue, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	if spec.AgingSeconds < 0 {
+		return nil, fmt.Errorf("agingSeconds should not be negative, got %d", spec.AgingSeconds)
+	}
+	if spec.ExpirationSeconds <= 0 {
+		return nil, fmt.Errorf("expirationSeconds should be positive, got %d", spec.ExpirationSeconds)
+	}
+	return &borrowingQueue{
+		aging:      time.Duration(spec.AgingSeconds) * time.Second,
+		expiration: time.Duration(spec.ExpirationSeconds) * time.Second,
+		borrowers:  make(map[string][]*borrower),
+	}, nil
+}
+
+// initBorrowingQueue queues the pods borrowing beyond the min of their ElasticQuota, if the borrowing queue is configured.
+func (c *CapacityScheduling) initBorrowingQueue(spec *config.BorrowingQueueSpec) error {
+	borrowingQueue, err := newBorrowingQueue(spec)
+	if err != nil {
+		return fmt.Errorf("invalid BorrowingQueue: %w", err)
+	}
+	c.borrowingQueue = borrowingQueue
+	return nil
+}
+
+// admit queues the pod as a borrower of its ElasticQuota, and returns true if it is its turn to borrow:
+// the pod is the oldest borrower of its ElasticQuota, and, unless the queue is per ElasticQuota, no borrower
+// of another ElasticQuota queued before it has aged. The queue always admits when it is disabled.
+func (q *borrowingQueue) admit(pod *v1.Pod, now time.Time) bool {
+	if q == nil {
+		return true
+	}
+	q.Lock()
+	defer q.Unlock()
+
+	q.expire(now)
+	b := q.find(pod)
+	if b == nil {
+		b = &borrower{uid: pod.UID, created: pod.CreationTimestamp.Time, queued: now}
+		q.insert(pod.Namespace, b)
+	}
+	b.seen = now
+
+	if q.borrowers[pod.Namespace][0] != b {
+		return false
+	}
+	if q.perQuota {
+		return true
+	}
+	for namespace, borrowers := range q.borrowers {
+		if namespace == pod.Namespace {
+			continue
+		}
+		head := borrowers[0]
+		if now.Sub(head.queued) >= q.aging && head.queued.Before(b.queued) {
+			return false
+		}
+	}
+	return true
+}
+
+// remove drops the pod from the borrowers, once it got resources or no longer borrows.
+func (q *borrowingQueue) remove(pod *v1.Pod) {
+	if q == nil {
+		return
+	}
+	q.Lock()
+	defer q.Unlock()
+
+	borrowers := q.borrowers[pod.Namespace]
+	for i, b := range borrowers {
+		if b.uid == pod.UID {
+			q.set(pod.Namespace, append(borrowers[:i:i], borrowers[i+1:]...))
+			return
+		}
+	}
+}
+
+func (q *borrowingQueue) find(pod *v1.Pod) *borrower {
+	for _, b := range q.borrowers[pod.Namespace] {
+		if b.uid == pod.UID {
+			return b
+		}
+	}
+	return nil
+}
+
+func (q *borrowingQueue) insert(namespace string, b *borrower) {
+	borrowers := append(q.borrowers[namespace], b)
+	sort.SliceStable(borrowers, func(i, j int) bool {
+		if !borrowers[i].created.Equal(borrowers[j].created) {
+			return borrowers[i].created.Before(borrowers[j].created)
+		}
+		return borrowers[i].uid < borrowers[j].uid
+	})
+	q.borrowers[namespace] = borrowers
+}
+
+// expire drops the borrowers not attempted for longer than expiration, e.g. deleted pods,
+// so that they don't block the borrowers queued after them.
+func (q *borrowingQueue) expire(now time.Time) {
+	for namespace, borrowers := range q.borrowers {
+		kept := borrowers[:0]
+		for _, b := range borrowers {
+			if now.Sub(b.seen) <= q.expiration {
+				kept = append(kept, b)
+			}
+		}
+		q.set(namespace, kept)
+	}
+}
+
+// forget drops the borrowers queued in the namespace, once its ElasticQuota is deleted.
+func (q *borrowingQueue) forget(namespace string) {
+	if q == nil {
+		return
+	}
+	q.Lock()
+	defer q.Unlock()
+	delete(q.borrowers, namespace)
+}
+
+func (q *borrowingQueue) set(namespace string, borrowers []*borrower) {
+	if len(borrowers) == 0 {
+		delete(q.borrowers, namespace)
+		return
+	}
+	q.borrowers[namespace] = borrowers
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+func makeBorrower(namespace, name string, created time.Time) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			UID:               types.UID(namespace + "/" + name),
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+}
+
+func TestNewBorrowingQueue(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    *config.BorrowingQueueSpec
+		enabled bool
+		wantErr bool
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:    "enabled",
+			spec:    &config.BorrowingQueueSpec{AgingSeconds: 60, ExpirationSeconds: 300},
+			enabled: true,
+		},
+		{
+			name:    "negative aging",
+			spec:    &config.BorrowingQueueSpec{AgingSeconds: -1, ExpirationSeconds: 300},
+			wantErr: true,
+		},
+		{
+			name:    "zero expiration",
+			spec:    &config.BorrowingQueueSpec{AgingSeconds: 60},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := newBorrowingQueue(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if (q != nil) != tt.enabled {
+				t.Errorf("expected enabled %v, got %v", tt.enabled, q != nil)
+			}
+		})
+	}
+}
+
+func TestBorrowingQueueAdmit(t *testing.T) {
+	now := time.Now()
+	spec := &config.BorrowingQueueSpec{AgingSeconds: 60, ExpirationSeconds: 300}
+
+	t.Run("disabled", func(t *testing.T) {
+		var q *borrowingQueue
+		if !q.admit(makeBorrower("ns1", "p1", now), now) {
+			t.Error("expected a disabled queue to admit")
+		}
+		q.remove(makeBorrower("ns1", "p1", now))
+	})
+
+	t.Run("FIFO within an ElasticQuota", func(t *testing.T) {
+		q, _ := newBorrowingQueue(spec)
+		older := makeBorrower("ns1", "older", now.Add(-time.Minute))
+		newer := makeBorrower("ns1", "newer", now)
+
+		if q.admit(newer, now) != true {
+			t.Fatal("expected the only borrower to be admitted")
+		}
+		if q.admit(older, now) != true {
+			t.Fatal("expected the older borrower to be admitted")
+		}
+		if q.admit(newer, now) != false {
+			t.Fatal("expected the newer borrower to wait for the older one")
+		}
+		q.remove(older)
+		if q.admit(newer, now) != true {
+			t.Fatal("expected the newer borrower to be admitted once the older one is removed")
+		}
+	})
+
+	t.Run("aging across ElasticQuotas", func(t *testing.T) {
+		q, _ := newBorrowingQueue(spec)
+		p1 := makeBorrower("ns1", "p1", now)
+		p2 := makeBorrower("ns2", "p2", now)
+
+		if !q.admit(p1, now) {
+			t.Fatal("expected p1 to be admitted")
+		}
+		if !q.admit(p2, now.Add(time.Second)) {
+			t.Fatal("expected p2 to be admitted before p1 aged")
+		}
+		if q.admit(p2, now.Add(time.Minute)) {
+			t.Fatal("expected p2 to wait for the aged p1")
+		}
+		if !q.admit(p1, now.Add(time.Minute)) {
+			t.Fatal("expected the aged p1 to be admitted")
+		}
+		q.remove(p1)
+		if !q.admit(p2, now.Add(time.Minute)) {
+			t.Fatal("expected p2 to be admitted once p1 is removed")
+		}
+	})
+
+	t.Run("per ElasticQuota", func(t *testing.T) {
+		q, _ := newBorrowingQueue(spec)
+		q.perQuota = true
+		p1 := makeBorrower("ns1", "p1", now)
+		p2 := makeBorrower("ns2", "p2", now)
+
+		q.admit(p1, now)
+		if !q.admit(p2, now.Add(time.Minute)) {
+			t.Fatal("expected p2 not to wait for the aged p1 of another ElasticQuota")
+		}
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		q, _ := newBorrowingQueue(spec)
+		older := makeBorrower("ns1", "older", now.Add(-time.Minute))
+		newer := makeBorrower("ns1", "newer", now)
+
+		q.admit(older, now)
+		if q.admit(newer, now.Add(time.Minute)) {
+			t.Fatal("expected the newer borrower to wait for the older one")
+		}
+		if !q.admit(newer, now.Add(301*time.Second)) {
+			t.Fatal("expected the newer borrower to be admitted once the older one expired")
+		}
+		if len(q.borrowers["ns1"]) != 1 {
+			t.Errorf("expected 1 borrower left, got %d", len(q.borrowers["ns1"]))
+		}
+	})
+
+	t.Run("ElasticQuota deleted", func(t *testing.T) {
+		q, _ := newBorrowingQueue(spec)
+		older := makeBorrower("ns1", "older", now.Add(-time.Minute))
+		newer := makeBorrower("ns1", "newer", now)
+
+		q.admit(older, now)
+		if q.admit(newer, now) {
+			t.Fatal("expected the newer borrower to wait for the older one")
+		}
+		q.forget("ns1")
+		if len(q.borrowers["ns1"]) != 0 {
+			t.Errorf("expected no borrower left, got %d", len(q.borrowers["ns1"]))
+		}
+	})
+}
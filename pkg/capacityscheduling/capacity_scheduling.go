@@ -21,12 +21,14 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	policy "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/informers"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -48,6 +50,7 @@ import (
 	// "sigs.k8s.io/scheduler-plugins/pkg/util"
 
 
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
 	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling"
 	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
@@ -65,9 +68,25 @@ type CapacityScheduling struct {
 	sync.RWMutex
 	fh                framework.Handle
 	podLister         corelisters.PodLister
+	nodeLister        corelisters.NodeLister
 	pdbLister         policylisters.PodDisruptionBudgetLister
 	client            client.Client
 	elasticQuotaInfos ElasticQuotaInfos
+	// gpuSlicing accounts the GPUs of the requests and quotas in slices, nil when disabled.
+	gpuSlicing *gpuSlicing
+	// borrowingQueue admits the borrowers in FIFO order per ElasticQuota, nil when disabled.
+	borrowingQueue *borrowingQueue
+	// borrowingFairness arbitrates the borrowing between ElasticQuotas, nil when first-come-first-served.
+	borrowingFairness *borrowingFairness
+	// preemptionProtection is the time during which the pods of an ElasticQuota which scaled up within
+	// its min are not preempted by the pods of other ElasticQuotas, zero when disabled.
+	preemptionProtection time.Duration
+	// preReclaim reclaims the borrowed resources ahead of the forecast demand of ElasticQuotas, nil when disabled.
+	preReclaim *preReclaim
+	// preemptionFreeze pauses the preemptions and the reclaims while set, nil when disabled.
+	preemptionFreeze *preemptionFreeze
+	// annotateQuotaUsage annotates the pods bound with their QuotaUsage.
+	annotateQuotaUsage bool
 }
 
 // PreFilterState computed at PreFilter and used at PostFilter or Reserve.
@@ -83,6 +102,11 @@ type PreFilterState struct {
 	// 1. the pods subject to the same quota(namespace) and is more important than the preemptor.
 	// 2. the pods subject to the different quota(namespace) and the usage of quota(namespace) does not exceed min.
 	nominatedPodsReqWithPodReq framework.Resource
+
+	// fitsInPool is true if the pod fits the min of the node pool of its ElasticQuota.
+	fitsInPool bool
+	// overSharedMin is true if the pod of an ElasticQuota with a node pool can't borrow on the shared nodes.
+	overSharedMin bool
 }
 
 // Clone the preFilter state.
@@ -102,9 +126,12 @@ func (s *ElasticQuotaSnapshotState) Clone() framework.StateData {
 	}
 }
 
+var _ framework.PreEnqueuePlugin = &CapacityScheduling{}
 var _ framework.PreFilterPlugin = &CapacityScheduling{}
+var _ framework.FilterPlugin = &CapacityScheduling{}
 var _ framework.PostFilterPlugin = &CapacityScheduling{}
 var _ framework.ReservePlugin = &CapacityScheduling{}
+var _ framework.PostBindPlugin = &CapacityScheduling{}
 var _ framework.EnqueueExtensions = &CapacityScheduling{}
 var _ preemption.Interface = &preemptor{}
 
@@ -128,10 +155,37 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		fh:                handle,
 		elasticQuotaInfos: NewElasticQuotaInfos(),
 		podLister:         handle.SharedInformerFactory().Core().V1().Pods().Lister(),
+		nodeLister:        handle.SharedInformerFactory().Core().V1().Nodes().Lister(),
 		pdbLister:         getPDBLister(handle.SharedInformerFactory()),
 	}
 	logger := klog.FromContext(ctx)
 
+	if obj != nil {
+		args, ok := obj.(*config.CapacitySchedulingArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type CapacitySchedulingArgs, got %T", obj)
+		}
+		if err := c.initGPUSlicing(args.GPUSlicing); err != nil {
+			return nil, err
+		}
+		if err := c.initBorrowingQueue(args.BorrowingQueue); err != nil {
+			return nil, err
+		}
+		if err := c.initBorrowingFairness(args.BorrowingPolicy); err != nil {
+			return nil, err
+		}
+		if err := c.initPreemptionProtection(args.PreemptionProtectionSeconds); err != nil {
+			return nil, err
+		}
+		if err := c.initPreReclaim(args.PreReclaim); err != nil {
+			return nil, err
+		}
+		if err := c.initPreemptionFreeze(args.PreemptionFreezeConfigMap); err != nil {
+			return nil, err
+		}
+		c.annotateQuotaUsage = args.AnnotateQuotaUsage
+	}
+
 	client, err := client.New(handle.KubeConfig(), client.Options{Scheme: scheme})
 	if err != nil {
 		return nil, err
@@ -193,6 +247,10 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 			},
 		},
 	)
+	c.startPreReclaim(ctx)
+	if err := c.preemptionFreeze.run(ctx, handle.ClientSet()); err != nil {
+		return nil, err
+	}
 	logger.Info("CapacityScheduling start")
 	return c, nil
 }
@@ -202,20 +260,66 @@ func (c *CapacityScheduling) EventsToRegister(_ context.Context) ([]framework.Cl
 	// https://github.com/kubernetes/kubernetes/pull/101394
 	// Please follow: eventhandlers.go#L403-L410
 	eqGVK := fmt.Sprintf("elasticquotas.v1alpha1.%v", scheduling.GroupName)
+	podActionType := framework.Delete
+	if c.borrowingQueue != nil || c.borrowingFairness != nil {
+		// The borrowers queued after a pod, or of ElasticQuotas borrowing less, get their turn once it is assigned.
+		podActionType |= framework.Add
+	}
 	return []framework.ClusterEventWithHint{
-		{Event: framework.ClusterEvent{Resource: framework.Pod, ActionType: framework.Delete}},
+		// Terminated pods give their resources back to their ElasticQuota too.
+		{Event: framework.ClusterEvent{Resource: framework.Pod, ActionType: podActionType | framework.Update}, QueueingHintFn: c.isSchedulableAfterPodChange},
 		{Event: framework.ClusterEvent{Resource: framework.GVK(eqGVK), ActionType: framework.All}},
+		{Event: framework.ClusterEvent{Resource: framework.Node, ActionType: framework.Add | framework.UpdateNodeLabel}},
 	}, nil
 }
 
+// isSchedulableAfterPodChange requeues a pod when a pod is added or deleted, or when an assigned pod terminates,
+// i.e. when the usage of an ElasticQuota changes. Other pod updates leave the usage unchanged.
+func (c *CapacityScheduling) isSchedulableAfterPodChange(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	oldPod, newPod, err := schedutil.As[*v1.Pod](oldObj, newObj)
+	if err != nil {
+		return framework.Queue, err
+	}
+	if oldPod == nil || newPod == nil {
+		return framework.Queue, nil
+	}
+	if assignedPod(newPod) && !isTerminated(oldPod) && isTerminated(newPod) {
+		logger.V(5).Info("pod terminated, the usage of its ElasticQuota dropped", "pod", klog.KObj(pod), "terminatedPod", klog.KObj(newPod))
+		return framework.Queue, nil
+	}
+	return framework.QueueSkip, nil
+}
+
+// PreEnqueue keeps a pod out of the active queue while its ElasticQuota is used over its max even before the pod,
+// i.e. while the pod is certain to be rejected in PreFilter. The pod is requeued by the events freeing usage or
+// changing the ElasticQuota, rather than going through scheduling cycles and preemption evaluations meanwhile.
+func (c *CapacityScheduling) PreEnqueue(ctx context.Context, pod *v1.Pod) *framework.Status {
+	c.RLock()
+	defer c.RUnlock()
+
+	eq := c.elasticQuotaInfos[pod.Namespace]
+	if eq == nil || !eq.usedOverMaxWith(eq.computePodResourceRequest(pod)) {
+		return nil
+	}
+	return framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("Pod %v/%v is gated because ElasticQuota %v is more than Max", pod.Namespace, pod.Name, eq.Namespace))
+}
+
 // PreFilter performs the following validations.
 // 1. Check if the (pod.request + eq.allocated) is less than eq.max.
 // 2. Check if the sum(eq's usage) > sum(eq's min).
+// A pod of an ElasticQuota with a node pool passes the second validation if it fits the min of the pool instead.
+// With the borrowing queue, a pod borrowing beyond the min of its ElasticQuota also waits for its turn to borrow.
+// With the DominantResourceFairness borrowing policy, it also waits for the ElasticQuotas borrowing less to borrow first.
+// A pod within the min of its ElasticQuota and the resources lent to it under lending agreements passes the second
+// validation, while the lent resources not used yet are taken out of the sum of min for the pods of other ElasticQuotas.
+// In a quota tree, the usage of the subtree of each ancestor of the ElasticQuota must not exceed the max of the ancestor
+// with the pod, and a pod within the unused min of the ancestors of its ElasticQuota passes the second validation.
 func (c *CapacityScheduling) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
 	// TODO improve the efficiency of taking snapshot
 	// e.g. use a two-pointer data structure to only copy the updated EQs when necessary.
 	snapshotElasticQuota := c.snapshotElasticQuota()
 	podReq := computePodResourceRequest(pod)
+	c.gpuSlicing.toSlices(podReq)
 
 	state.Write(ElasticQuotaSnapshotKey, snapshotElasticQuota)
 
@@ -252,7 +356,7 @@ func (c *CapacityScheduling) PreFilter(ctx context.Context, state *framework.Cyc
 			ns := p.Pod.Namespace
 			info := c.elasticQuotaInfos[ns]
 			if info != nil {
-				pResourceRequest := util.ResourceList(computePodResourceRequest(p.Pod))
+				pResourceRequest := util.ResourceList(info.computePodResourceRequest(p.Pod))
 				// If they are subject to the same quota(namespace) and p is more important than pod,
 				// p will be added to the nominatedResource and totalNominatedResource.
 				// If they aren't subject to the same quota(namespace) and the usage of quota(p's namespace) does not exceed min,
@@ -279,8 +383,43 @@ func (c *CapacityScheduling) PreFilter(ctx context.Context, state *framework.Cyc
 	if eq.usedOverMaxWith(nominatedPodsReqInEQWithPodReq) {
 		return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Pod %v/%v is rejected in PreFilter because ElasticQuota %v is more than Max", pod.Namespace, pod.Name, eq.Namespace))
 	}
+	if ancestor := elasticQuotaInfos.ancestorUsedOverMaxWith(pod.Namespace, nominatedPodsReqInEQWithPodReq); ancestor != nil {
+		return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Pod %v/%v is rejected in PreFilter because the subtree of ElasticQuota %v is more than Max", pod.Namespace, pod.Name, ancestor.Namespace))
+	}
 
-	if elasticQuotaInfos.aggregatedUsedOverMinWith(*nominatedPodsReqWithPodReq) {
+	// the resources granted to other borrowers under lending agreements can't be borrowed by the pod.
+	grants := elasticQuotaInfos.loanGrants()
+	overSharedMin := elasticQuotaInfos.aggregatedUsedOverMinWithout(*nominatedPodsReqWithPodReq, grants.reservedExcept(pod.Namespace))
+	if eq.pool != nil {
+		preFilterState.fitsInPool = !eq.poolUsedOverMinWith(nominatedPodsReqInEQWithPodReq)
+		preFilterState.overSharedMin = overSharedMin
+		if preFilterState.fitsInPool {
+			c.borrowingQueue.remove(pod)
+			c.borrowingFairness.remove(pod)
+			return nil, framework.NewStatus(framework.Success, "")
+		}
+	}
+
+	// the unused min of the ancestors of the ElasticQuota is granted to it like the resources lent to it.
+	granted := elasticQuotaInfos.ancestorsGrant(pod.Namespace)
+	if lent := grants.granted[pod.Namespace]; lent != nil {
+		addResource(granted, lent, 1)
+	}
+	if !eq.usedOverMinWith(nominatedPodsReqInEQWithPodReq) {
+		c.borrowingQueue.remove(pod)
+		c.borrowingFairness.remove(pod)
+	} else if !eq.usedOverGrantWith(nominatedPodsReqInEQWithPodReq, granted) {
+		// the pod borrows the resources granted to its ElasticQuota, reserved to it ahead of the other borrowers.
+		c.borrowingQueue.remove(pod)
+		c.borrowingFairness.remove(pod)
+		return nil, framework.NewStatus(framework.Success, "")
+	} else if !c.borrowingQueue.admit(pod, time.Now()) {
+		return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Pod %v/%v is rejected in PreFilter because %v", pod.Namespace, pod.Name, ErrReasonBorrowingTurn))
+	} else if !c.borrowingFairness.admit(pod, elasticQuotaInfos, time.Now()) {
+		return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Pod %v/%v is rejected in PreFilter because %v", pod.Namespace, pod.Name, ErrReasonBorrowingFairness))
+	}
+
+	if overSharedMin {
 		return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Pod %v/%v is rejected in PreFilter because total ElasticQuota used is more than min", pod.Namespace, pod.Name))
 	}
 
@@ -304,7 +443,7 @@ func (c *CapacityScheduling) AddPod(ctx context.Context, cycleState *framework.C
 
 	elasticQuotaInfo := elasticQuotaSnapshotState.elasticQuotaInfos[podToAdd.Pod.Namespace]
 	if elasticQuotaInfo != nil {
-		err := elasticQuotaInfo.addPodIfNotPresent(podToAdd.Pod)
+		err := elasticQuotaInfo.addPodOnNodeIfNotPresent(podToAdd.Pod, nodeInfo.Node().Name)
 		if err != nil {
 			logger.Error(err, "Failed to add Pod to its associated elasticQuota", "pod", klog.KObj(podToAdd.Pod))
 		}
@@ -335,6 +474,9 @@ func (c *CapacityScheduling) RemovePod(ctx context.Context, cycleState *framewor
 }
 
 func (c *CapacityScheduling) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, m framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	if c.suppressPreemption(pod, "Not preempting pods to make room for the pod") {
+		return nil, framework.NewStatus(framework.Unschedulable, "preemption is frozen")
+	}
 	defer func() {
 		metrics.PreemptionAttempts.Inc()
 	}()
@@ -346,8 +488,9 @@ func (c *CapacityScheduling) PostFilter(ctx context.Context, state *framework.Cy
 		PdbLister:  c.pdbLister,
 		State:      state,
 		Interface: &preemptor{
-			fh:    c.fh,
-			state: state,
+			fh:                   c.fh,
+			state:                state,
+			preemptionProtection: c.preemptionProtection,
 		},
 	}
 
@@ -360,6 +503,8 @@ func (c *CapacityScheduling) Reserve(ctx context.Context, state *framework.Cycle
 
 	logger := klog.FromContext(ctx)
 
+	c.borrowingQueue.remove(pod)
+	c.borrowingFairness.remove(pod)
 	elasticQuotaInfo := c.elasticQuotaInfos[pod.Namespace]
 	if elasticQuotaInfo != nil {
 		err := elasticQuotaInfo.addPodIfNotPresent(pod)
@@ -367,6 +512,11 @@ func (c *CapacityScheduling) Reserve(ctx context.Context, state *framework.Cycle
 			logger.Error(err, "Failed to add Pod to its associated elasticQuota", "pod", klog.KObj(pod))
 			return framework.NewStatus(framework.Error, err.Error())
 		}
+		// The quota scaled up within its min: protect its pods from preemption for a while.
+		if c.preemptionProtection > 0 && !elasticQuotaInfo.usedOverMin() {
+			elasticQuotaInfo.scaledUp = time.Now()
+		}
+		c.recordQuotaUsage(state, elasticQuotaInfo, pod)
 	}
 	return framework.NewStatus(framework.Success, "")
 }
@@ -389,6 +539,8 @@ func (c *CapacityScheduling) Unreserve(ctx context.Context, state *framework.Cyc
 type preemptor struct {
 	fh    framework.Handle
 	state *framework.CycleState
+	// preemptionProtection is the time during which the ElasticQuotas which scaled up are protected.
+	preemptionProtection time.Duration
 }
 
 func (p *preemptor) OrderedScoreFuncs(ctx context.Context, nodesToVictims map[string]*extenderv1.Victims) []func(node string) int64 {
@@ -450,7 +602,7 @@ func (p *preemptor) PodEligibleToPreemptOthers(pod *v1.Pod, nominatedNodeStatus
 		podPriority := corev1helpers.PodPriority(pod)
 		preemptorEQInfo, preemptorWithEQ := elasticQuotaSnapshotState.elasticQuotaInfos[pod.Namespace]
 		if preemptorWithEQ {
-			moreThanMinWithPreemptor := preemptorEQInfo.usedOverMinWith(&preFilterState.nominatedPodsReqInEQWithPodReq)
+			moreThanMinWithPreemptor := preemptorEQInfo.reclaimableWith(&preFilterState.podReq, &preFilterState.nominatedPodsReqInEQWithPodReq) == nil
 			for _, p := range nodeInfo.Pods {
 				// Checking terminating pods
 				if p.Pod.DeletionTimestamp != nil {
@@ -540,14 +692,42 @@ func (p *preemptor) SelectVictimsOnNode(
 	podPriority := corev1helpers.PodPriority(pod)
 	preemptorElasticQuotaInfo, preemptorWithElasticQuota := elasticQuotaInfos[pod.Namespace]
 
+	// decayLevels are the borrowing decay levels of the pods of the other quotas,
+	// computed before the victims are removed from their quotas.
+	decayLevels := make(map[types.UID]int64)
+	if preemptorWithElasticQuota {
+		now := time.Now()
+		for _, p := range nodeInfo.Pods {
+			if eqInfo, withEQ := elasticQuotaInfos[p.Pod.Namespace]; withEQ && p.Pod.Namespace != pod.Namespace {
+				decayLevels[p.Pod.UID] = eqInfo.borrowingDecayLevel(p.Pod, now)
+			}
+		}
+	}
+	// siblings are the namespaces of the subtrees of the siblings of the preemptor's quota, reclaimed from first.
+	siblings := elasticQuotaInfos.siblingSubtrees(pod.Namespace)
+	// The pods outside the sibling subtrees are more important, then the pods borrowing for fewer decay periods,
+	// and then the pods with a higher priority.
+	moreImportantPod := func(pod1, pod2 *v1.Pod) bool {
+		if sibling1, sibling2 := siblings.Has(pod1.Namespace), siblings.Has(pod2.Namespace); sibling1 != sibling2 {
+			return sibling2
+		}
+		if level1, level2 := decayLevels[pod1.UID], decayLevels[pod2.UID]; level1 != level2 {
+			return level1 < level2
+		}
+		return schedutil.MoreImportantPod(pod1, pod2)
+	}
+
 	// sort the pods in node by the priority class
-	sort.Slice(nodeInfo.Pods, func(i, j int) bool { return !schedutil.MoreImportantPod(nodeInfo.Pods[i].Pod, nodeInfo.Pods[j].Pod) })
+	sort.Slice(nodeInfo.Pods, func(i, j int) bool { return !moreImportantPod(nodeInfo.Pods[i].Pod, nodeInfo.Pods[j].Pod) })
 
 	var potentialVictims []*framework.PodInfo
 	if preemptorWithElasticQuota {
 		nominatedPodsReqInEQWithPodReq = preFilterState.nominatedPodsReqInEQWithPodReq
 		nominatedPodsReqWithPodReq = preFilterState.nominatedPodsReqWithPodReq
-		moreThanMinWithPreemptor := preemptorElasticQuotaInfo.usedOverMinWith(&nominatedPodsReqInEQWithPodReq)
+		// reclaimable are the resources requested by the preemptor within the min of its quota.
+		reclaimable := preemptorElasticQuotaInfo.reclaimableWith(&podReq, &nominatedPodsReqInEQWithPodReq)
+		moreThanMinWithPreemptor := reclaimable == nil
+		now, protection := time.Now(), p.preemptionProtection
 		for _, p := range nodeInfo.Pods {
 			eqInfo, withEQ := elasticQuotaInfos[p.Pod.Namespace]
 			if !withEQ {
@@ -555,7 +735,8 @@ func (p *preemptor) SelectVictimsOnNode(
 			}
 
 			if moreThanMinWithPreemptor {
-				// If Preemptor.Request + Quota.Used > Quota.Min:
+				// If Preemptor.Request + Quota.Used > Quota.Min for all the
+				// resources the preemptor requests:
 				// It means that its guaranteed isn't borrowed by other
 				// quotas. So that we will select the pods which subject to the
 				// same quota(namespace) with the lower priority than the
@@ -568,13 +749,22 @@ func (p *preemptor) SelectVictimsOnNode(
 				}
 
 			} else {
-				// If Preemptor.Request + Quota.allocated <= Quota.min: It
-				// means that its min(guaranteed) resource is used or
-				// `borrowed` by other Quota. Potential victims in a node
-				// will be chosen from Quotas that allocates more resources
-				// than its min, i.e., borrowing resources from other
-				// Quotas.
-				if p.Pod.Namespace != pod.Namespace && eqInfo.usedOverMin() {
+				// If Preemptor.Request + Quota.allocated <= Quota.min for
+				// some resources, e.g. GPUs: It means that its min(guaranteed)
+				// of these resources is used or `borrowed` by other Quota.
+				// Potential victims in a node will be chosen from Quotas that
+				// allocates more resources than its min, i.e., borrowing
+				// resources from other Quotas. Only the pods giving back
+				// borrowed resources the preemptor can reclaim are chosen,
+				// and the quotas stop contributing victims once back to
+				// their min. The quotas which recently scaled up within
+				// their min are protected, to damp rapid scale-up and
+				// reclaim cycles.
+				if p.Pod.Namespace != pod.Namespace && eqInfo.usedOverMinFor(reclaimable, eqInfo.computePodResourceRequest(p.Pod)) {
+					if eqInfo.protectedFromPreemption(now, protection) {
+						logger.V(5).Info("Pod protected from preemption after its elasticQuota scaled up", "pod", klog.KObj(p.Pod))
+						continue
+					}
 					potentialVictims = append(potentialVictims, p)
 					if err := removePod(p); err != nil {
 						return nil, 0, framework.AsStatus(err)
@@ -618,6 +808,7 @@ func (p *preemptor) SelectVictimsOnNode(
 	// we are almost done and this node is not suitable for preemption.
 	if preemptorWithElasticQuota {
 		if preemptorElasticQuotaInfo.usedOverMaxWith(&podReq) ||
+			elasticQuotaInfos.ancestorUsedOverMaxWith(pod.Namespace, &podReq) != nil ||
 			elasticQuotaInfos.aggregatedUsedOverMinWith(podReq) {
 			return nil, 0, framework.NewStatus(framework.Unschedulable, "global quota max exceeded")
 		}
@@ -628,7 +819,7 @@ func (p *preemptor) SelectVictimsOnNode(
 	// Sort potentialVictims by pod priority from high to low, which ensures to
 	// reprieve higher priority pods first.
 	sort.Slice(potentialVictims, func(i, j int) bool {
-		return schedutil.MoreImportantPod(potentialVictims[i].Pod, potentialVictims[j].Pod)
+		return moreImportantPod(potentialVictims[i].Pod, potentialVictims[j].Pod)
 	})
 	// Try to reprieve as many pods as possible. We first try to reprieve the PDB
 	// violating victims and then other non-violating ones. In both cases, we start
@@ -640,20 +831,23 @@ func (p *preemptor) SelectVictimsOnNode(
 		}
 		s := p.fh.RunFilterPluginsWithNominatedPods(ctx, state, pod, nodeInfo)
 		fits := s.IsSuccess()
-		if !fits {
-			if err := removePod(pi); err != nil {
-				return false, err
+		if fits && preemptorWithElasticQuota {
+			// Reprieving the pod can only push the quotas over on the resources it requests,
+			// so that the pods not competing for the resources over the quotas are reprieved.
+			piReq := computePodResourceRequest(pi.Pod)
+			if eqInfo, withEQ := elasticQuotaInfos[pi.Pod.Namespace]; withEQ {
+				piReq = eqInfo.computePodResourceRequest(pi.Pod)
 			}
-			victims = append(victims, pi.Pod)
-			logger.V(5).Info("Found a potential preemption victim on node", "pod", klog.KObj(pi.Pod), "node", klog.KObj(nodeInfo.Node()))
+			fits = !preemptorElasticQuotaInfo.usedOverMaxWithOn(&nominatedPodsReqInEQWithPodReq, piReq) &&
+				!elasticQuotaInfos.ancestorsUsedOverMaxWithOn(pod.Namespace, &nominatedPodsReqInEQWithPodReq, piReq) &&
+				!elasticQuotaInfos.aggregatedUsedOverMinWithOn(nominatedPodsReqWithPodReq, piReq)
 		}
-
-		if preemptorWithElasticQuota && (preemptorElasticQuotaInfo.usedOverMaxWith(&nominatedPodsReqInEQWithPodReq) || elasticQuotaInfos.aggregatedUsedOverMinWith(nominatedPodsReqWithPodReq)) {
+		if !fits {
 			if err := removePod(pi); err != nil {
 				return false, err
 			}
 			victims = append(victims, pi.Pod)
-			logger.V(5).Info("Found a potential preemption victim on node", "pod", klog.KObj(pi.Pod), " node", klog.KObj(nodeInfo.Node()))
+			logger.V(5).Info("Found a potential preemption victim on node", "pod", klog.KObj(pi.Pod), "node", klog.KObj(nodeInfo.Node()))
 		}
 
 		return fits, nil
@@ -676,19 +870,22 @@ func (p *preemptor) SelectVictimsOnNode(
 
 	// Sort victims after reprieving pods to keep the pods in the victims sorted in order of priority from high to low.
 	if len(violatingVictims) != 0 && len(nonViolatingVictims) != 0 {
-		sort.Slice(victims, func(i, j int) bool { return schedutil.MoreImportantPod(victims[i], victims[j]) })
+		sort.Slice(victims, func(i, j int) bool { return moreImportantPod(victims[i], victims[j]) })
 	}
 	return victims, numViolatingVictim, framework.NewStatus(framework.Success)
 }
 
 func (c *CapacityScheduling) addElasticQuota(obj interface{}) {
 	eq := obj.(*v1alpha1.ElasticQuota)
+	if eq.DeletionTimestamp != nil {
+		return
+	}
 	oldElasticQuotaInfo := c.elasticQuotaInfos[eq.Namespace]
 	if oldElasticQuotaInfo != nil {
 		return
 	}
 
-	elasticQuotaInfo := newElasticQuotaInfo(eq.Namespace, eq.Spec.Min, eq.Spec.Max, nil)
+	elasticQuotaInfo := c.newElasticQuotaInfo(eq)
 
 	c.Lock()
 	defer c.Unlock()
@@ -698,7 +895,13 @@ func (c *CapacityScheduling) addElasticQuota(obj interface{}) {
 func (c *CapacityScheduling) updateElasticQuota(oldObj, newObj interface{}) {
 	oldEQ := oldObj.(*v1alpha1.ElasticQuota)
 	newEQ := newObj.(*v1alpha1.ElasticQuota)
-	newEQInfo := newElasticQuotaInfo(newEQ.Namespace, newEQ.Spec.Min, newEQ.Spec.Max, nil)
+	// The ElasticQuota is held by the controller's SchedulerCacheFinalizer until
+	// its state is flushed: it no longer admits pods.
+	if newEQ.DeletionTimestamp != nil {
+		c.deleteElasticQuota(newEQ)
+		return
+	}
+	newEQInfo := c.newElasticQuotaInfo(newEQ)
 
 	c.Lock()
 	defer c.Unlock()
@@ -707,15 +910,28 @@ func (c *CapacityScheduling) updateElasticQuota(oldObj, newObj interface{}) {
 	if oldEQInfo != nil {
 		newEQInfo.pods = oldEQInfo.pods
 		newEQInfo.Used = oldEQInfo.Used
+		newEQInfo.scaledUp = oldEQInfo.scaledUp
+		newEQInfo.borrowingSince = oldEQInfo.borrowingSince
+		newEQInfo.updateBorrowing(time.Now())
+		if oldEQInfo.pool != nil && newEQInfo.pool != nil && oldEQInfo.pool.selector.String() == newEQInfo.pool.selector.String() {
+			newEQInfo.pool = oldEQInfo.pool
+		} else if err := c.recomputePool(newEQInfo); err != nil {
+			klog.FromContext(context.TODO()).Error(err, "Failed to account the pods in the node pool of elasticQuota", "elasticQuota", klog.KObj(newEQ))
+		}
 	}
 	c.elasticQuotaInfos[newEQ.Namespace] = newEQInfo
 }
 
 func (c *CapacityScheduling) deleteElasticQuota(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
 	elasticQuota := obj.(*v1alpha1.ElasticQuota)
 	c.Lock()
 	defer c.Unlock()
 	delete(c.elasticQuotaInfos, elasticQuota.Namespace)
+	c.borrowingQueue.forget(elasticQuota.Namespace)
+	c.borrowingFairness.forget(elasticQuota.Namespace)
 }
 
 func (c *CapacityScheduling) addPod(obj interface{}) {
@@ -736,7 +952,12 @@ func (c *CapacityScheduling) addPod(obj interface{}) {
 			return
 		}
 
-		eqs := eqList.Items
+		var eqs []v1alpha1.ElasticQuota
+		for _, eq := range eqList.Items {
+			if eq.DeletionTimestamp == nil {
+				eqs = append(eqs, eq)
+			}
+		}
 		// If the length of elasticQuotas is 0, return.
 		if len(eqs) == 0 {
 			return
@@ -745,7 +966,7 @@ func (c *CapacityScheduling) addPod(obj interface{}) {
 		if len(eqs) > 0 {
 			// only one elasticquota is supported in each namespace
 			eq := eqs[0]
-			elasticQuotaInfo = newElasticQuotaInfo(eq.Namespace, eq.Spec.Min, eq.Spec.Max, nil)
+			elasticQuotaInfo = c.newElasticQuotaInfo(&eq)
 			c.elasticQuotaInfos[eq.Namespace] = elasticQuotaInfo
 		}
 	}
@@ -787,6 +1008,8 @@ func (c *CapacityScheduling) deletePod(obj interface{}) {
 	c.Lock()
 	defer c.Unlock()
 
+	c.borrowingQueue.remove(pod)
+	c.borrowingFairness.remove(pod)
 	elasticQuotaInfo := c.elasticQuotaInfos[pod.Namespace]
 	if elasticQuotaInfo != nil {
 		err := elasticQuotaInfo.deletePodIfPresent(pod)
@@ -796,6 +1019,27 @@ func (c *CapacityScheduling) deletePod(obj interface{}) {
 	}
 }
 
+// newElasticQuotaInfo returns the ElasticQuotaInfo of an ElasticQuota, with its GPU min, max
+// and loans converted to GPU slices when GPU slicing is enabled, its node pool, loans and parent if any.
+func (c *CapacityScheduling) newElasticQuotaInfo(eq *v1alpha1.ElasticQuota) *ElasticQuotaInfo {
+	elasticQuotaInfo := newElasticQuotaInfo(eq.Namespace, eq.Spec.Min, eq.Spec.Max, nil)
+	elasticQuotaInfo.pool = newNodePool(eq.Spec.NodeSelector, c.nodeLabels)
+	elasticQuotaInfo.loans = newLoans(eq.Spec.Lending)
+	elasticQuotaInfo.parent = eq.Spec.Parent
+	if eq.Spec.BorrowingDecaySeconds != nil && *eq.Spec.BorrowingDecaySeconds > 0 {
+		elasticQuotaInfo.borrowingDecay = time.Duration(*eq.Spec.BorrowingDecaySeconds) * time.Second
+	}
+	if c.gpuSlicing != nil {
+		elasticQuotaInfo.gpuSlicing = c.gpuSlicing
+		c.gpuSlicing.toSlices(elasticQuotaInfo.Min)
+		c.gpuSlicing.toSlices(elasticQuotaInfo.Max)
+		for _, l := range elasticQuotaInfo.loans {
+			c.gpuSlicing.toSlices(l.max)
+		}
+	}
+	return elasticQuotaInfo
+}
+
 // getElasticQuotasSnapshot will return the snapshot of elasticQuotas.
 func (c *CapacityScheduling) snapshotElasticQuota() *ElasticQuotaSnapshotState {
 	c.RLock()
@@ -942,3 +1186,8 @@ func filterPodsWithPDBViolation(podInfos []*framework.PodInfo, pdbs []*policy.Po
 func assignedPod(pod *v1.Pod) bool {
 	return len(pod.Spec.NodeName) != 0
 }
+
+// isTerminated returns whether the pod succeeded or failed.
+func isTerminated(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 
@@ -31,6 +32,7 @@ import (
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
 	extenderv1 "k8s.io/kube-scheduler/extender/v1"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
@@ -186,6 +188,103 @@ func TestPreFilter(t *testing.T) {
 	}
 }
 
+func TestPreEnqueue(t *testing.T) {
+	elasticQuotas := map[string]*ElasticQuotaInfo{
+		"ns1": {
+			Namespace: "ns1",
+			Min:       &framework.Resource{Memory: 1000},
+			Max:       &framework.Resource{Memory: 2000},
+			Used:      &framework.Resource{Memory: 1800},
+		},
+		"ns2": {
+			Namespace: "ns2",
+			Min:       &framework.Resource{Memory: 1000},
+			Used:      &framework.Resource{Memory: 5000},
+		},
+	}
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected framework.Code
+	}{
+		{
+			name:     "pod fits in the max of its ElasticQuota",
+			pod:      makePod("ns1-p1", "ns1", 200, 0, 0, 0, "ns1-p1", ""),
+			expected: framework.Success,
+		},
+		{
+			name:     "pod of an ElasticQuota used over its max",
+			pod:      makePod("ns1-p2", "ns1", 300, 0, 0, 0, "ns1-p2", ""),
+			expected: framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:     "pod of an ElasticQuota without max",
+			pod:      makePod("ns2-p1", "ns2", 300, 0, 0, 0, "ns2-p1", ""),
+			expected: framework.Success,
+		},
+		{
+			name:     "pod without ElasticQuota",
+			pod:      makePod("ns3-p1", "ns3", 300, 0, 0, 0, "ns3-p1", ""),
+			expected: framework.Success,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &CapacityScheduling{elasticQuotaInfos: elasticQuotas}
+			if got := cs.PreEnqueue(context.TODO(), tt.pod); got.Code() != tt.expected {
+				t.Errorf("expected %v, got %v : %v", tt.expected, got.Code(), got.Message())
+			}
+		})
+	}
+}
+
+func TestIsSchedulableAfterPodChange(t *testing.T) {
+	pending := makePod("ns1-p1", "ns1", 200, 0, 0, 0, "ns1-p1", "")
+	running := makePodWithStatus(makePod("ns1-p2", "ns1", 200, 0, 0, 0, "ns1-p2", "node-a"), v1.PodRunning)
+	succeeded := makePodWithStatus(running.DeepCopy(), v1.PodSucceeded)
+	tests := []struct {
+		name     string
+		oldObj   interface{}
+		newObj   interface{}
+		expected framework.QueueingHint
+	}{
+		{
+			name:     "pod added",
+			newObj:   running,
+			expected: framework.Queue,
+		},
+		{
+			name:     "pod deleted",
+			oldObj:   running,
+			expected: framework.Queue,
+		},
+		{
+			name:     "assigned pod terminated",
+			oldObj:   running,
+			newObj:   succeeded,
+			expected: framework.Queue,
+		},
+		{
+			name:     "assigned pod updated",
+			oldObj:   running,
+			newObj:   running.DeepCopy(),
+			expected: framework.QueueSkip,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &CapacityScheduling{}
+			got, err := cs.isSchedulableAfterPodChange(klog.Background(), pending, tt.oldObj, tt.newObj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestPostFilter(t *testing.T) {
 	res := map[v1.ResourceName]string{v1.ResourceMemory: "150"}
 	tests := []struct {
@@ -486,6 +585,30 @@ func TestReserve(t *testing.T) {
 	}
 }
 
+func TestReserveScaleUp(t *testing.T) {
+	cs := &CapacityScheduling{
+		elasticQuotaInfos: ElasticQuotaInfos{
+			"ns1": newElasticQuotaInfo("ns1", makeResourceList(1000, 100), makeResourceList(1000, 200), nil),
+		},
+		preemptionProtection: time.Minute,
+	}
+	state := framework.NewCycleState()
+
+	if got := cs.Reserve(context.TODO(), state, makePod("t1-p1", "ns1", 50, 0, 0, midPriority, "t1-p1", "node-a"), "node-a"); !got.IsSuccess() {
+		t.Fatalf("unexpected Reserve status: %v", got)
+	}
+	scaledUp := cs.elasticQuotaInfos["ns1"].scaledUp
+	if scaledUp.IsZero() {
+		t.Fatal("expected the quota to scale up within its min")
+	}
+	if got := cs.Reserve(context.TODO(), state, makePod("t1-p2", "ns1", 100, 0, 0, midPriority, "t1-p2", "node-a"), "node-a"); !got.IsSuccess() {
+		t.Fatalf("unexpected Reserve status: %v", got)
+	}
+	if got := cs.elasticQuotaInfos["ns1"].scaledUp; !got.Equal(scaledUp) {
+		t.Errorf("expected the scale up beyond the min not to be recorded, got %v", got)
+	}
+}
+
 func TestUnreserve(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -807,6 +930,235 @@ func TestDryRunPreemption(t *testing.T) {
 	}
 }
 
+func TestSelectVictimsOnNode(t *testing.T) {
+	res := map[v1.ResourceName]string{v1.ResourceMemory: "200", v1.ResourceCPU: "10", ResourceGPU: "4"}
+	makeEQInfo := func(namespace string, min, max v1.ResourceList, pods ...*v1.Pod) *ElasticQuotaInfo {
+		eqInfo := newElasticQuotaInfo(namespace, min, max, nil)
+		for _, p := range pods {
+			_ = eqInfo.addPodIfNotPresent(p)
+		}
+		return eqInfo
+	}
+	fourPods := []*v1.Pod{
+		makePod("t-p1", "ns2", 50, 0, 0, 10, "t-p1", "node-a"),
+		makePod("t-p2", "ns2", 50, 0, 0, 20, "t-p2", "node-a"),
+		makePod("t-p3", "ns2", 50, 0, 0, 30, "t-p3", "node-a"),
+		makePod("t-p4", "ns2", 50, 0, 0, 40, "t-p4", "node-a"),
+	}
+	fourCPUPods := []*v1.Pod{
+		makePod("t-p1", "ns2", 50, 100, 0, 10, "t-p1", "node-a"),
+		makePod("t-p2", "ns2", 50, 100, 0, 20, "t-p2", "node-a"),
+		makePod("t-p3", "ns2", 50, 100, 0, 30, "t-p3", "node-a"),
+		makePod("t-p4", "ns2", 50, 100, 0, 40, "t-p4", "node-a"),
+	}
+	scaledUpAgo := func(eqInfo *ElasticQuotaInfo, ago time.Duration) *ElasticQuotaInfo {
+		eqInfo.scaledUp = time.Now().Add(-ago)
+		return eqInfo
+	}
+	borrowingFor := func(eqInfo *ElasticQuotaInfo, decay, ago time.Duration) *ElasticQuotaInfo {
+		eqInfo.borrowingDecay = decay
+		eqInfo.borrowingSince = time.Now().Add(-ago)
+		return eqInfo
+	}
+	startedAgo := func(pod *v1.Pod, ago time.Duration) *v1.Pod {
+		pod.Status.StartTime = &metav1.Time{Time: time.Now().Add(-ago)}
+		return pod
+	}
+	// t-p3 and t-p4 have been borrowing for 35 minutes, t-p1 and t-p2 just started.
+	fourLongBorrowingPods := []*v1.Pod{
+		startedAgo(makePod("t-p1", "ns2", 50, 0, 0, 10, "t-p1", "node-a"), 0),
+		startedAgo(makePod("t-p2", "ns2", 50, 0, 0, 20, "t-p2", "node-a"), 0),
+		startedAgo(makePod("t-p3", "ns2", 50, 0, 0, 30, "t-p3", "node-a"), 35*time.Minute),
+		startedAgo(makePod("t-p4", "ns2", 50, 0, 0, 40, "t-p4", "node-a"), 35*time.Minute),
+	}
+	twoPods := []*v1.Pod{
+		makePod("t-p1", "ns2", 100, 100, 0, 10, "t-p1", "node-a"),
+		makePod("t-p2", "ns2", 100, 0, 0, 20, "t-p2", "node-a"),
+	}
+	twoGPUPods := []*v1.Pod{
+		makePod("t-p1", "ns2", 50, 0, 2, 10, "t-p1", "node-a"),
+		makePod("t-p2", "ns2", 50, 0, 2, 20, "t-p2", "node-a"),
+	}
+	withGPU := func(rl v1.ResourceList, gpu int64) v1.ResourceList {
+		rl[ResourceGPU] = *resource.NewQuantity(gpu, resource.DecimalSI)
+		return rl
+	}
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		pods          []*v1.Pod
+		elasticQuotas map[string]*ElasticQuotaInfo
+		// nominatedReq is added to the request of the preemptor in the quotas of all the namespaces
+		nominatedReq v1.ResourceList
+		// protection is the time the quotas which scaled up are protected from preemption
+		protection  time.Duration
+		wantVictims []string
+		wantStatus  framework.Code
+	}{
+		{
+			name: "victims stop at the min of the borrowing quota",
+			pod:  makePod("t-p", "ns1", 100, 0, 0, highPriority, "t-p", ""),
+			pods: fourPods,
+			elasticQuotas: map[string]*ElasticQuotaInfo{
+				"ns1": makeEQInfo("ns1", makeResourceList(1000, 200), makeResourceList(1000, 200)),
+				"ns2": makeEQInfo("ns2", makeResourceList(1000, 100), makeResourceList(1000, 200), fourPods...),
+			},
+			wantVictims: []string{"t-p1", "t-p2"},
+			wantStatus:  framework.Success,
+		},
+		{
+			name: "no victims in a quota borrowing only resources the preemptor doesn't request",
+			pod:  makePod("t-p", "ns1", 100, 0, 0, highPriority, "t-p", ""),
+			pods: fourCPUPods,
+			elasticQuotas: map[string]*ElasticQuotaInfo{
+				"ns1": makeEQInfo("ns1", makeResourceList(1000, 200), makeResourceList(1000, 200)),
+				"ns2": makeEQInfo("ns2", makeResourceList(0, 200), makeResourceList(1000, 200), fourCPUPods...),
+			},
+			wantStatus: framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name: "pods not requesting the resources over the min are reprieved",
+			pod:  makePod("t-p", "ns1", 100, 0, 0, highPriority, "t-p", ""),
+			pods: twoPods,
+			elasticQuotas: map[string]*ElasticQuotaInfo{
+				"ns1": makeEQInfo("ns1", makeResourceList(500, 200), makeResourceList(1000, 200)),
+				"ns2": makeEQInfo("ns2", makeResourceList(0, 0), makeResourceList(1000, 200), twoPods...),
+			},
+			nominatedReq: makeResourceList(1000, 0),
+			wantVictims:  []string{"t-p1"},
+			wantStatus:   framework.Success,
+		},
+		{
+			name: "guaranteed GPUs reclaimed by a quota borrowing CPU",
+			pod:  makePod("t-p", "ns1", 0, 100, 2, highPriority, "t-p", ""),
+			pods: twoGPUPods,
+			elasticQuotas: map[string]*ElasticQuotaInfo{
+				// ns1 already uses 200m CPU on another node, over its min of CPU but within its min of GPUs.
+				"ns1": makeEQInfo("ns1", withGPU(makeResourceList(100, 200), 4), withGPU(makeResourceList(1000, 200), 4),
+					makePod("t-q", "ns1", 0, 200, 0, midPriority, "t-q", "node-b")),
+				"ns2": makeEQInfo("ns2", withGPU(makeResourceList(1000, 200), 0), withGPU(makeResourceList(1000, 200), 4), twoGPUPods...),
+			},
+			wantVictims: []string{"t-p1"},
+			wantStatus:  framework.Success,
+		},
+		{
+			name: "no victims in a quota which recently scaled up within its min",
+			pod:  makePod("t-p", "ns1", 100, 0, 0, highPriority, "t-p", ""),
+			pods: fourPods,
+			elasticQuotas: map[string]*ElasticQuotaInfo{
+				"ns1": makeEQInfo("ns1", makeResourceList(1000, 200), makeResourceList(1000, 200)),
+				"ns2": scaledUpAgo(makeEQInfo("ns2", makeResourceList(1000, 100), makeResourceList(1000, 200), fourPods...), 10*time.Second),
+			},
+			protection: time.Minute,
+			wantStatus: framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name: "victims in a quota which scaled up within its min before the protection window",
+			pod:  makePod("t-p", "ns1", 100, 0, 0, highPriority, "t-p", ""),
+			pods: fourPods,
+			elasticQuotas: map[string]*ElasticQuotaInfo{
+				"ns1": makeEQInfo("ns1", makeResourceList(1000, 200), makeResourceList(1000, 200)),
+				"ns2": scaledUpAgo(makeEQInfo("ns2", makeResourceList(1000, 100), makeResourceList(1000, 200), fourPods...), 2*time.Minute),
+			},
+			protection:  time.Minute,
+			wantVictims: []string{"t-p1", "t-p2"},
+			wantStatus:  framework.Success,
+		},
+		{
+			name: "victims borrowing for the longest time first with borrowing decay",
+			pod:  makePod("t-p", "ns1", 100, 0, 0, highPriority, "t-p", ""),
+			pods: fourLongBorrowingPods,
+			elasticQuotas: map[string]*ElasticQuotaInfo{
+				"ns1": makeEQInfo("ns1", makeResourceList(1000, 200), makeResourceList(1000, 200)),
+				"ns2": borrowingFor(makeEQInfo("ns2", makeResourceList(1000, 100), makeResourceList(1000, 200), fourLongBorrowingPods...), 10*time.Minute, time.Hour),
+			},
+			wantVictims: []string{"t-p3", "t-p4"},
+			wantStatus:  framework.Success,
+		},
+		{
+			name: "victims by priority with borrowing decay before a decay period",
+			pod:  makePod("t-p", "ns1", 100, 0, 0, highPriority, "t-p", ""),
+			pods: fourLongBorrowingPods,
+			elasticQuotas: map[string]*ElasticQuotaInfo{
+				"ns1": makeEQInfo("ns1", makeResourceList(1000, 200), makeResourceList(1000, 200)),
+				"ns2": borrowingFor(makeEQInfo("ns2", makeResourceList(1000, 100), makeResourceList(1000, 200), fourLongBorrowingPods...), time.Hour, time.Hour),
+			},
+			wantVictims: []string{"t-p1", "t-p2"},
+			wantStatus:  framework.Success,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registeredPlugins := append(makeRegisteredPlugin(),
+				tf.RegisterPluginAsExtensions(Name, func(_ context.Context, _ apiruntime.Object, _ framework.Handle) (framework.Plugin, error) {
+					return &quotaAccounting{}, nil
+				}, "PreFilter"),
+			)
+			nodes := []*v1.Node{st.MakeNode().Name("node-a").Capacity(res).Obj()}
+
+			cs := clientsetfake.NewSimpleClientset()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			fwk, err := tf.NewFramework(
+				ctx,
+				registeredPlugins,
+				"default-scheduler",
+				frameworkruntime.WithClientSet(cs),
+				frameworkruntime.WithPodNominator(testutil.NewPodNominator(nil)),
+				frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(tt.pods, nodes)),
+				frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(cs, 0)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			state := framework.NewCycleState()
+			if _, status, _ := fwk.RunPreFilterPlugins(ctx, state, tt.pod); !status.IsSuccess() {
+				t.Fatalf("Unexpected preFilterStatus: %v", status)
+			}
+
+			podReq := computePodResourceRequest(tt.pod)
+			nominatedPodsReqWithPodReq := podReq.Clone()
+			nominatedPodsReqWithPodReq.Add(tt.nominatedReq)
+			state.Write(preFilterStateKey, &PreFilterState{
+				podReq:                         *podReq,
+				nominatedPodsReqWithPodReq:     *nominatedPodsReqWithPodReq,
+				nominatedPodsReqInEQWithPodReq: *podReq,
+			})
+			state.Write(ElasticQuotaSnapshotKey, &ElasticQuotaSnapshotState{elasticQuotaInfos: tt.elasticQuotas})
+
+			nodeInfo, err := fwk.SnapshotSharedLister().NodeInfos().Get("node-a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &preemptor{fh: fwk, state: state, preemptionProtection: tt.protection}
+			victims, _, status := p.SelectVictimsOnNode(ctx, state, tt.pod, nodeInfo.Snapshot(), nil)
+			if status.Code() != tt.wantStatus {
+				t.Fatalf("Unexpected status: want %v, got %v", tt.wantStatus, status)
+			}
+			var got []string
+			for _, v := range victims {
+				got = append(got, v.Name)
+			}
+			sort.Strings(got)
+			if diff := gocmp.Diff(tt.wantVictims, got); diff != "" {
+				t.Errorf("Unexpected victims (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+// quotaAccounting only runs the PreFilter extensions of CapacityScheduling, accounting the pods
+// removed and added back by the victim selection in the ElasticQuota snapshot as in the scheduler.
+type quotaAccounting struct {
+	CapacityScheduling
+}
+
+func (q *quotaAccounting) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	return nil, nil
+}
+
 func TestPodEligibleToPreemptOthers(t *testing.T) {
 	res := map[v1.ResourceName]string{v1.ResourceMemory: "150"}
 	tests := []struct {
@@ -1202,6 +1554,18 @@ func TestUpdateElasticQuota(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:            "Update ElasticQuota held by the finalizer",
+			oldElasticQuota: makeEQ("ns1", "t1-eq1", makeResourceList(100, 1000), makeResourceList(10, 100)),
+			newElasticQuota: func() *v1alpha1.ElasticQuota {
+				eq := makeEQ("ns1", "t1-eq1", makeResourceList(100, 1000), makeResourceList(10, 100))
+				now := metav1.Now()
+				eq.DeletionTimestamp = &now
+				return eq
+			}(),
+			ns:       []string{"ns1"},
+			expected: map[string]*ElasticQuotaInfo{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
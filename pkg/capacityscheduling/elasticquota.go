@@ -18,6 +18,7 @@ package capacityscheduling
 
 import (
 	"math"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -48,16 +49,33 @@ func (e ElasticQuotaInfos) clone() ElasticQuotaInfos {
 }
 
 func (e ElasticQuotaInfos) aggregatedUsedOverMinWith(podRequest framework.Resource) bool {
+	used, min := e.aggregatedUsedAndMinWith(podRequest)
+	return cmp(used, min, LowerBoundOfMin)
+}
+
+// aggregatedUsedOverMinWithOn is aggregatedUsedOverMinWith restricted to the given resources.
+func (e ElasticQuotaInfos) aggregatedUsedOverMinWithOn(podRequest framework.Resource, resources *framework.Resource) bool {
+	used, min := e.aggregatedUsedAndMinWith(podRequest)
+	return cmp(restrictTo(used, resources), min, LowerBoundOfMin)
+}
+
+func (e ElasticQuotaInfos) aggregatedUsedAndMinWith(podRequest framework.Resource) (*framework.Resource, *framework.Resource) {
 	used := framework.NewResource(nil)
 	min := framework.NewResource(nil)
 
 	for _, elasticQuotaInfo := range e {
+		if elasticQuotaInfo.pool != nil {
+			// the min of a quota with a node pool is guaranteed by its pool,
+			// only its usage on the shared nodes is borrowed.
+			used.Add(util.ResourceList(elasticQuotaInfo.sharedUsed()))
+			continue
+		}
 		used.Add(util.ResourceList(elasticQuotaInfo.Used))
 		min.Add(util.ResourceList(elasticQuotaInfo.Min))
 	}
 
 	used.Add(util.ResourceList(&podRequest))
-	return cmp(used, min, LowerBoundOfMin)
+	return used, min
 }
 
 // ElasticQuotaInfo is a wrapper to a ElasticQuota with information.
@@ -68,6 +86,26 @@ type ElasticQuotaInfo struct {
 	Min       *framework.Resource
 	Max       *framework.Resource
 	Used      *framework.Resource
+
+	// gpuSlicing accounts the GPU resources of pods in GPU slices, nil when disabled.
+	gpuSlicing *gpuSlicing
+
+	// pool tracks the usage of the node pool dedicated to the ElasticQuota, nil without a pool.
+	pool *nodePool
+
+	// loans are the lending agreements of the ElasticQuota to the ElasticQuotas of other namespaces.
+	loans []loan
+
+	// parent is the namespace of the parent ElasticQuota in the quota tree, empty for a root.
+	parent string
+
+	// scaledUp is when a pod was last reserved within the min of the ElasticQuota.
+	scaledUp time.Time
+
+	// borrowingDecay is the period after which the borrowing pods move a step ahead in the reclaim order, 0 without decay.
+	borrowingDecay time.Duration
+	// borrowingSince is when the ElasticQuota started using resources beyond its min, zero when within its min.
+	borrowingSince time.Time
 }
 
 func newElasticQuotaInfo(namespace string, min, max, used v1.ResourceList) *ElasticQuotaInfo {
@@ -124,6 +162,65 @@ func (e *ElasticQuotaInfo) usedOverMaxWith(podRequest *framework.Resource) bool
 	return cmp2(podRequest, e.Used, e.Max, UpperBoundOfMax)
 }
 
+// usedOverMaxWithOn is usedOverMaxWith restricted to the given resources.
+func (e *ElasticQuotaInfo) usedOverMaxWithOn(podRequest, resources *framework.Resource) bool {
+	if e.Max == nil {
+		return false
+	}
+	return cmp2(restrictTo(podRequest, resources), restrictTo(e.Used, resources), e.Max, UpperBoundOfMax)
+}
+
+// reclaimableWith returns the resources requested by the pod on which the quota stays within its min with the
+// given request, i.e. the resources the pod can reclaim from the quotas borrowing them, nil if the quota is over its
+// min on all of them. It is decided per resource, so that a quota borrowing CPU can still reclaim its guaranteed GPUs
+// or other extended resources.
+func (e *ElasticQuotaInfo) reclaimableWith(podRequest, request *framework.Resource) *framework.Resource {
+	if e.Min == nil {
+		return nil
+	}
+	reclaimable := &framework.Resource{}
+	requested, found := false, false
+	withinMin := func(quantity, used, req, min int64) bool {
+		if quantity <= 0 {
+			return false
+		}
+		requested = true
+		if used+req > min {
+			return false
+		}
+		found = true
+		return true
+	}
+	if withinMin(podRequest.MilliCPU, e.Used.MilliCPU, request.MilliCPU, e.Min.MilliCPU) {
+		reclaimable.MilliCPU = podRequest.MilliCPU
+	}
+	if withinMin(podRequest.Memory, e.Used.Memory, request.Memory, e.Min.Memory) {
+		reclaimable.Memory = podRequest.Memory
+	}
+	if withinMin(podRequest.EphemeralStorage, e.Used.EphemeralStorage, request.EphemeralStorage, e.Min.EphemeralStorage) {
+		reclaimable.EphemeralStorage = podRequest.EphemeralStorage
+	}
+	for name, quantity := range podRequest.ScalarResources {
+		if withinMin(quantity, e.Used.ScalarResources[name], request.ScalarResources[name], e.Min.ScalarResources[name]) {
+			reclaimable.SetScalar(name, quantity)
+		}
+	}
+	if requested && !found {
+		return nil
+	}
+	return reclaimable
+}
+
+// usedOverMinFor returns whether the quota uses more than its min of a resource requested by both the
+// preemptor and the pod, i.e. whether preempting the pod gives back borrowed resources the preemptor needs.
+func (e *ElasticQuotaInfo) usedOverMinFor(preemptorRequest, podRequest *framework.Resource) bool {
+	min := e.Min
+	if min == nil {
+		min = &framework.Resource{}
+	}
+	return cmp(restrictTo(restrictTo(e.Used, preemptorRequest), podRequest), min, LowerBoundOfMin)
+}
+
 func (e *ElasticQuotaInfo) usedOverMin() bool {
 	// "ElasticQuotaInfo doesn't have Min" means used values exceeded min(0)
 	if e.Min == nil {
@@ -134,8 +231,15 @@ func (e *ElasticQuotaInfo) usedOverMin() bool {
 
 func (e *ElasticQuotaInfo) clone() *ElasticQuotaInfo {
 	newEQInfo := &ElasticQuotaInfo{
-		Namespace: e.Namespace,
-		pods:      sets.New[string](),
+		Namespace:      e.Namespace,
+		pods:           sets.New[string](),
+		gpuSlicing:     e.gpuSlicing,
+		pool:           e.pool.clone(),
+		loans:          e.loans,
+		parent:         e.parent,
+		scaledUp:       e.scaledUp,
+		borrowingDecay: e.borrowingDecay,
+		borrowingSince: e.borrowingSince,
 	}
 
 	if e.Min != nil {
@@ -155,6 +259,11 @@ func (e *ElasticQuotaInfo) clone() *ElasticQuotaInfo {
 }
 
 func (e *ElasticQuotaInfo) addPodIfNotPresent(pod *v1.Pod) error {
+	return e.addPodOnNodeIfNotPresent(pod, pod.Spec.NodeName)
+}
+
+// addPodOnNodeIfNotPresent adds the pod running, or nominated to run, on the given node.
+func (e *ElasticQuotaInfo) addPodOnNodeIfNotPresent(pod *v1.Pod, nodeName string) error {
 	key, err := framework.GetPodKey(pod)
 	if err != nil {
 		return err
@@ -165,8 +274,10 @@ func (e *ElasticQuotaInfo) addPodIfNotPresent(pod *v1.Pod) error {
 	}
 
 	e.pods.Insert(key)
-	podRequest := computePodResourceRequest(pod)
+	podRequest := e.computePodResourceRequest(pod)
 	e.reserveResource(*podRequest)
+	e.pool.addPod(key, nodeName, podRequest)
+	e.updateBorrowing(time.Now())
 
 	return nil
 }
@@ -182,12 +293,47 @@ func (e *ElasticQuotaInfo) deletePodIfPresent(pod *v1.Pod) error {
 	}
 
 	e.pods.Delete(key)
-	podRequest := computePodResourceRequest(pod)
+	podRequest := e.computePodResourceRequest(pod)
 	e.unreserveResource(*podRequest)
+	e.pool.deletePod(key, podRequest)
+	e.updateBorrowing(time.Now())
 
 	return nil
 }
 
+// updateBorrowing records when the ElasticQuota with borrowing decay started using resources beyond its min, if it does.
+func (e *ElasticQuotaInfo) updateBorrowing(now time.Time) {
+	if e.borrowingDecay <= 0 || !e.usedOverMin() {
+		e.borrowingSince = time.Time{}
+	} else if e.borrowingSince.IsZero() {
+		e.borrowingSince = now
+	}
+}
+
+// borrowingDecayLevel returns the number of borrowing decay periods the pod has been using resources beyond the min
+// of the ElasticQuota for: since the ElasticQuota started borrowing, or since the pod started if later. The higher the
+// level, the earlier the pod is reclaimed. It is 0 without decay.
+func (e *ElasticQuotaInfo) borrowingDecayLevel(pod *v1.Pod, now time.Time) int64 {
+	if e.borrowingDecay <= 0 || e.borrowingSince.IsZero() {
+		return 0
+	}
+	since := e.borrowingSince
+	if pod.Status.StartTime != nil && pod.Status.StartTime.After(since) {
+		since = pod.Status.StartTime.Time
+	}
+	if since.After(now) {
+		return 0
+	}
+	return int64(now.Sub(since) / e.borrowingDecay)
+}
+
+// computePodResourceRequest returns the request of the pod accounted in the ElasticQuota.
+func (e *ElasticQuotaInfo) computePodResourceRequest(pod *v1.Pod) *framework.Resource {
+	podRequest := computePodResourceRequest(pod)
+	e.gpuSlicing.toSlices(podRequest)
+	return podRequest
+}
+
 func cmp(x, y *framework.Resource, bound int64) bool {
 	return cmp2(x, &framework.Resource{}, y, bound)
 }
@@ -222,6 +368,29 @@ func cmp2(x1, x2, y *framework.Resource, bound int64) bool {
 	return false
 }
 
+// restrictTo returns the quantities of x for the resources with a positive quantity in resources only.
+func restrictTo(x, resources *framework.Resource) *framework.Resource {
+	restricted := &framework.Resource{}
+	if resources.MilliCPU > 0 {
+		restricted.MilliCPU = x.MilliCPU
+	}
+	if resources.Memory > 0 {
+		restricted.Memory = x.Memory
+	}
+	if resources.EphemeralStorage > 0 {
+		restricted.EphemeralStorage = x.EphemeralStorage
+	}
+	if resources.AllowedPodNumber > 0 {
+		restricted.AllowedPodNumber = x.AllowedPodNumber
+	}
+	for rName, rQuant := range resources.ScalarResources {
+		if rQuant > 0 {
+			restricted.SetScalar(rName, x.ScalarResources[rName])
+		}
+	}
+	return restricted
+}
+
 func makeResourceListForBound(bound int64) v1.ResourceList {
 	return v1.ResourceList{
 		v1.ResourceCPU:              *resource.NewMilliQuantity(bound, resource.DecimalSI),
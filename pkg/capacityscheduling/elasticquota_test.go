@@ -19,7 +19,9 @@ package capacityscheduling
 import (
 	"reflect"
 	"testing"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	v1 "k8s.io/api/core/v1"
@@ -503,6 +505,124 @@ func TestUsedOverMin(t *testing.T) {
 	}
 }
 
+func TestUsedOverMinFor(t *testing.T) {
+	eqInfo := &ElasticQuotaInfo{
+		Namespace: "ns1",
+		Used: &framework.Resource{
+			MilliCPU: 4000,
+			Memory:   200,
+			ScalarResources: map[v1.ResourceName]int64{
+				ResourceGPU: 5,
+			},
+		},
+		Min: &framework.Resource{
+			MilliCPU: 3000,
+			Memory:   300,
+			ScalarResources: map[v1.ResourceName]int64{
+				ResourceGPU: 5,
+			},
+		},
+	}
+	tests := []struct {
+		name             string
+		preemptorRequest *framework.Resource
+		podRequest       *framework.Resource
+		expected         bool
+	}{
+		{
+			name:             "Preemptor And Pod Request The Resource Over Min",
+			preemptorRequest: &framework.Resource{MilliCPU: 100, Memory: 100},
+			podRequest:       &framework.Resource{MilliCPU: 100},
+			expected:         true,
+		},
+		{
+			name:             "Preemptor Doesn't Request The Resource Over Min",
+			preemptorRequest: &framework.Resource{Memory: 100},
+			podRequest:       &framework.Resource{MilliCPU: 100, Memory: 100},
+			expected:         false,
+		},
+		{
+			name:             "Pod Doesn't Request The Resource Over Min",
+			preemptorRequest: &framework.Resource{MilliCPU: 100, Memory: 100},
+			podRequest:       &framework.Resource{Memory: 100, ScalarResources: map[v1.ResourceName]int64{ResourceGPU: 1}},
+			expected:         false,
+		},
+		{
+			name:             "Scalar Resource Not Over Min",
+			preemptorRequest: &framework.Resource{ScalarResources: map[v1.ResourceName]int64{ResourceGPU: 1}},
+			podRequest:       &framework.Resource{MilliCPU: 100, ScalarResources: map[v1.ResourceName]int64{ResourceGPU: 1}},
+			expected:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := eqInfo.usedOverMinFor(tt.preemptorRequest, tt.podRequest)
+			if actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestReclaimableWith(t *testing.T) {
+	eqInfo := &ElasticQuotaInfo{
+		Namespace: "ns1",
+		Used: &framework.Resource{
+			MilliCPU: 4000,
+			Memory:   200,
+			ScalarResources: map[v1.ResourceName]int64{
+				ResourceGPU: 2,
+			},
+		},
+		Min: &framework.Resource{
+			MilliCPU: 3000,
+			Memory:   300,
+			ScalarResources: map[v1.ResourceName]int64{
+				ResourceGPU: 4,
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		podRequest *framework.Resource
+		expected   *framework.Resource
+	}{
+		{
+			name:       "All Requested Resources Over Min",
+			podRequest: &framework.Resource{MilliCPU: 100, Memory: 200},
+			expected:   nil,
+		},
+		{
+			name:       "GPU Within Min With CPU Over Min",
+			podRequest: &framework.Resource{MilliCPU: 100, ScalarResources: map[v1.ResourceName]int64{ResourceGPU: 2}},
+			expected:   &framework.Resource{ScalarResources: map[v1.ResourceName]int64{ResourceGPU: 2}},
+		},
+		{
+			name:       "GPU Over Min",
+			podRequest: &framework.Resource{MilliCPU: 100, ScalarResources: map[v1.ResourceName]int64{ResourceGPU: 3}},
+			expected:   nil,
+		},
+		{
+			name:       "Memory And GPU Within Min",
+			podRequest: &framework.Resource{Memory: 100, ScalarResources: map[v1.ResourceName]int64{ResourceGPU: 1}},
+			expected:   &framework.Resource{Memory: 100, ScalarResources: map[v1.ResourceName]int64{ResourceGPU: 1}},
+		},
+		{
+			name:       "Empty Request",
+			podRequest: &framework.Resource{},
+			expected:   &framework.Resource{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := eqInfo.reclaimableWith(tt.podRequest, tt.podRequest)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
 func TestNewElasticQuotaInfo(t *testing.T) {
 	type elasticQuotaParam struct {
 		namespace string
@@ -630,3 +750,40 @@ func TestNewElasticQuotaInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestBorrowingDecayLevel(t *testing.T) {
+	eqInfo := newElasticQuotaInfo("ns1", makeResourceList(1000, 100), makeResourceList(2000, 200), nil)
+	eqInfo.borrowingDecay = 10 * time.Minute
+	p1 := makePod("t-p1", "ns1", 100, 0, 0, 0, "t-p1", "node-a")
+	p2 := makePod("t-p2", "ns1", 50, 0, 0, 0, "t-p2", "node-a")
+
+	if err := eqInfo.addPodIfNotPresent(p1); err != nil {
+		t.Fatal(err)
+	}
+	if !eqInfo.borrowingSince.IsZero() {
+		t.Fatalf("expected the quota within its min not to borrow, since %v", eqInfo.borrowingSince)
+	}
+	if err := eqInfo.addPodIfNotPresent(p2); err != nil {
+		t.Fatal(err)
+	}
+	if eqInfo.borrowingSince.IsZero() {
+		t.Fatal("expected the quota beyond its min to borrow")
+	}
+
+	now := eqInfo.borrowingSince.Add(25 * time.Minute)
+	if got := eqInfo.borrowingDecayLevel(p1, now); got != 2 {
+		t.Errorf("expected the pod to decay for 2 periods, got %v", got)
+	}
+	// The pod started after the quota started borrowing.
+	p2.Status.StartTime = &metav1.Time{Time: eqInfo.borrowingSince.Add(20 * time.Minute)}
+	if got := eqInfo.borrowingDecayLevel(p2, now); got != 0 {
+		t.Errorf("expected the pod started 5 minutes ago not to decay, got %v", got)
+	}
+
+	if err := eqInfo.deletePodIfPresent(p2); err != nil {
+		t.Fatal(err)
+	}
+	if got := eqInfo.borrowingDecayLevel(p1, now); got != 0 {
+		t.Errorf("expected no decay once back within the min, got %v", got)
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// ErrReasonBorrowingFairness is the reason for a pod waiting for the ElasticQuotas borrowing less to borrow first.
+const ErrReasonBorrowingFairness = "ElasticQuotas with a smaller dominant share of the borrowed resources are borrowing first"
+
+// borrowingContenderExpiration is the time after which a borrower no longer attempted stops competing for the
+// borrowable resources, matching the maximum time a pod stays in the unschedulable queue.
+const borrowingContenderExpiration = 5 * time.Minute
+
+// borrowingFairness arbitrates between the ElasticQuotas competing to borrow resources beyond their min
+// following Dominant Resource Fairness: the dominant share of an ElasticQuota is the largest share it borrows
+// of any resource of the aggregated min of the ElasticQuotas, and a pod borrows only if no ElasticQuota with
+// borrowers attempted meanwhile has a smaller dominant share than its ElasticQuota.
+type borrowingFairness struct {
+	sync.Mutex
+	expiration time.Duration
+	// contenders are when the borrowers of each ElasticQuota(namespace) were last attempted, by pod UID.
+	contenders map[string]map[types.UID]time.Time
+}
+
+func newBorrowingFairness(policy config.BorrowingPolicyType) (*borrowingFairness, error) {
+	switch policy {
+	case "", config.BorrowingFirstComeFirstServed:
+		return nil, nil
+	case config.BorrowingDominantResourceFairness:
+		return &borrowingFairness{
+			expiration: borrowingContenderExpiration,
+			contenders: make(map[string]map[types.UID]time.Time),
+		}, nil
+	default:
+		return nil, fmt.Errorf("borrowingPolicy should be %v or %v, got %v",
+			config.BorrowingFirstComeFirstServed, config.BorrowingDominantResourceFairness, policy)
+	}
+}
+
+// initBorrowingFairness arbitrates the borrowing between ElasticQuotas following the policy. It runs after
+// initBorrowingQueue: with Dominant Resource Fairness, the policy arbitrates across ElasticQuotas and the queue
+// only orders the borrowers of each.
+func (c *CapacityScheduling) initBorrowingFairness(policy config.BorrowingPolicyType) error {
+	borrowingFairness, err := newBorrowingFairness(policy)
+	if err != nil {
+		return fmt.Errorf("invalid BorrowingPolicy: %w", err)
+	}
+	c.borrowingFairness = borrowingFairness
+	if c.borrowingQueue != nil && borrowingFairness != nil {
+		c.borrowingQueue.perQuota = true
+	}
+	return nil
+}
+
+// admit records the pod as a borrower of its ElasticQuota, and returns true if its ElasticQuota has the
+// smallest dominant share among the ElasticQuotas with borrowers. It always admits when the policy is
+// first-come-first-served.
+func (f *borrowingFairness) admit(pod *v1.Pod, elasticQuotaInfos ElasticQuotaInfos, now time.Time) bool {
+	if f == nil {
+		return true
+	}
+	f.Lock()
+	defer f.Unlock()
+
+	f.expire(now)
+	if f.contenders[pod.Namespace] == nil {
+		f.contenders[pod.Namespace] = make(map[types.UID]time.Time)
+	}
+	f.contenders[pod.Namespace][pod.UID] = now
+
+	eq := elasticQuotaInfos[pod.Namespace]
+	if eq == nil {
+		return true
+	}
+	capacity := elasticQuotaInfos.borrowableCapacity()
+	share := eq.dominantBorrowedShare(capacity)
+	for namespace := range f.contenders {
+		if namespace == pod.Namespace {
+			continue
+		}
+		if other := elasticQuotaInfos[namespace]; other != nil && other.dominantBorrowedShare(capacity) < share {
+			return false
+		}
+	}
+	return true
+}
+
+// remove drops the pod from the borrowers, once it got resources or no longer borrows.
+func (f *borrowingFairness) remove(pod *v1.Pod) {
+	if f == nil {
+		return
+	}
+	f.Lock()
+	defer f.Unlock()
+
+	delete(f.contenders[pod.Namespace], pod.UID)
+	if len(f.contenders[pod.Namespace]) == 0 {
+		delete(f.contenders, pod.Namespace)
+	}
+}
+
+// forget drops the borrowers of the namespace, once its ElasticQuota is deleted.
+func (f *borrowingFairness) forget(namespace string) {
+	if f == nil {
+		return
+	}
+	f.Lock()
+	defer f.Unlock()
+	delete(f.contenders, namespace)
+}
+
+// expire drops the borrowers not attempted for longer than expiration, e.g. deleted pods,
+// so that their ElasticQuotas no longer hold back the others.
+func (f *borrowingFairness) expire(now time.Time) {
+	for namespace, borrowers := range f.contenders {
+		for uid, seen := range borrowers {
+			if now.Sub(seen) > f.expiration {
+				delete(borrowers, uid)
+			}
+		}
+		if len(borrowers) == 0 {
+			delete(f.contenders, namespace)
+		}
+	}
+}
+
+// borrowableCapacity returns the resources the ElasticQuotas borrow from, i.e. the aggregated min of the
+// ElasticQuotas without a node pool.
+func (e ElasticQuotaInfos) borrowableCapacity() *framework.Resource {
+	_, min := e.aggregatedUsedAndMinWith(framework.Resource{})
+	return min
+}
+
+// borrowed returns the resources the ElasticQuota uses beyond its min, or out of its node pool if it has one.
+func (e *ElasticQuotaInfo) borrowed() *framework.Resource {
+	if e.pool != nil {
+		return e.sharedUsed()
+	}
+	return subtractResource(e.Used, e.Min)
+}
+
+// dominantBorrowedShare returns the largest share of a resource of the capacity the ElasticQuota borrows.
+func (e *ElasticQuotaInfo) dominantBorrowedShare(capacity *framework.Resource) float64 {
+	borrowed := e.borrowed()
+	dominant := 0.0
+	share := func(quantity, capacity int64) {
+		if capacity > 0 {
+			dominant = max(dominant, float64(quantity)/float64(capacity))
+		}
+	}
+	share(borrowed.MilliCPU, capacity.MilliCPU)
+	share(borrowed.Memory, capacity.Memory)
+	share(borrowed.EphemeralStorage, capacity.EphemeralStorage)
+	for name, quantity := range borrowed.ScalarResources {
+		share(quantity, capacity.ScalarResources[name])
+	}
+	return dominant
+}
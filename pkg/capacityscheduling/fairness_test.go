@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+func TestNewBorrowingFairness(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  config.BorrowingPolicyType
+		enabled bool
+		wantErr bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:   "first come first served",
+			policy: config.BorrowingFirstComeFirstServed,
+		},
+		{
+			name:    "dominant resource fairness",
+			policy:  config.BorrowingDominantResourceFairness,
+			enabled: true,
+		},
+		{
+			name:    "unknown policy",
+			policy:  "RoundRobin",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newBorrowingFairness(tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if (f != nil) != tt.enabled {
+				t.Errorf("expected enabled %v, got %v", tt.enabled, f != nil)
+			}
+		})
+	}
+}
+
+func TestInitBorrowingFairness(t *testing.T) {
+	tests := []struct {
+		name         string
+		queue        *config.BorrowingQueueSpec
+		policy       config.BorrowingPolicyType
+		wantPerQuota bool
+	}{
+		{
+			name:   "queue with first come first served",
+			queue:  &config.BorrowingQueueSpec{ExpirationSeconds: 300},
+			policy: config.BorrowingFirstComeFirstServed,
+		},
+		{
+			name:         "queue with dominant resource fairness",
+			queue:        &config.BorrowingQueueSpec{ExpirationSeconds: 300},
+			policy:       config.BorrowingDominantResourceFairness,
+			wantPerQuota: true,
+		},
+		{
+			name:   "dominant resource fairness without queue",
+			policy: config.BorrowingDominantResourceFairness,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &CapacityScheduling{}
+			if err := c.initBorrowingQueue(tt.queue); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.initBorrowingFairness(tt.policy); err != nil {
+				t.Fatal(err)
+			}
+			if c.borrowingQueue != nil && c.borrowingQueue.perQuota != tt.wantPerQuota {
+				t.Errorf("expected the queue per ElasticQuota %v, got %v", tt.wantPerQuota, c.borrowingQueue.perQuota)
+			}
+		})
+	}
+}
+
+func TestBorrowingFairnessAdmit(t *testing.T) {
+	now := time.Now()
+	min := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+	// ns1 borrows 2 of the 8 CPUs of the aggregated min, ns2 1Gi of its 16Gi of memory.
+	elasticQuotaInfos := ElasticQuotaInfos{
+		"ns1": newElasticQuotaInfo("ns1", min, nil, v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("6"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		}),
+		"ns2": newElasticQuotaInfo("ns2", min, nil, v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1"),
+			v1.ResourceMemory: resource.MustParse("9Gi"),
+		}),
+	}
+
+	t.Run("first come first served", func(t *testing.T) {
+		var f *borrowingFairness
+		if !f.admit(makeBorrower("ns1", "p1", now), elasticQuotaInfos, now) {
+			t.Error("expected first-come-first-served to admit")
+		}
+		f.remove(makeBorrower("ns1", "p1", now))
+	})
+
+	t.Run("smallest dominant share first", func(t *testing.T) {
+		f, _ := newBorrowingFairness(config.BorrowingDominantResourceFairness)
+		p1 := makeBorrower("ns1", "p1", now)
+		p2 := makeBorrower("ns2", "p2", now)
+
+		if !f.admit(p1, elasticQuotaInfos, now) {
+			t.Fatal("expected the only borrower to be admitted")
+		}
+		if !f.admit(p2, elasticQuotaInfos, now) {
+			t.Fatal("expected the borrower of the smallest dominant share to be admitted")
+		}
+		if f.admit(p1, elasticQuotaInfos, now) {
+			t.Fatal("expected p1 to wait for the ElasticQuota borrowing less")
+		}
+		f.remove(p2)
+		if !f.admit(p1, elasticQuotaInfos, now) {
+			t.Fatal("expected p1 to be admitted once p2 is removed")
+		}
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		f, _ := newBorrowingFairness(config.BorrowingDominantResourceFairness)
+		p1 := makeBorrower("ns1", "p1", now)
+		p2 := makeBorrower("ns2", "p2", now)
+
+		f.admit(p2, elasticQuotaInfos, now)
+		if f.admit(p1, elasticQuotaInfos, now.Add(time.Minute)) {
+			t.Fatal("expected p1 to wait for the ElasticQuota borrowing less")
+		}
+		if !f.admit(p1, elasticQuotaInfos, now.Add(301*time.Second)) {
+			t.Fatal("expected p1 to be admitted once p2 expired")
+		}
+	})
+
+	t.Run("ElasticQuota deleted", func(t *testing.T) {
+		f, _ := newBorrowingFairness(config.BorrowingDominantResourceFairness)
+		p1 := makeBorrower("ns1", "p1", now)
+		p2 := makeBorrower("ns2", "p2", now)
+
+		f.admit(p2, elasticQuotaInfos, now)
+		f.forget("ns2")
+		if !f.admit(p1, elasticQuotaInfos, now) {
+			t.Fatal("expected p1 to be admitted once the ElasticQuota of p2 is deleted")
+		}
+	})
+}
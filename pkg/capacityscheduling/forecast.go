@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// preReclaim forecasts the demand of the ElasticQuotas within their min from their pending pods, and evicts the
+// pods borrowing beyond the min of other ElasticQuotas ahead of it, so that a surge of guaranteed pods doesn't
+// wait for preemptions to get its min back. It is only used by the reclaim loop, so it isn't locked.
+type preReclaim struct {
+	leadTime     time.Duration
+	interval     time.Duration
+	maxEvictions int
+	// pending is the request of the pending pods of each ElasticQuota(namespace) at the last sample.
+	pending map[string]*framework.Resource
+	// sampled is when the last sample was taken, zero before the first one.
+	sampled time.Time
+}
+
+func newPreReclaim(spec *config.PreReclaimSpec) (*preReclaim, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	if spec.LeadTimeSeconds < 0 {
+		return nil, fmt.Errorf("leadTimeSeconds should not be negative, got %d", spec.LeadTimeSeconds)
+	}
+	if spec.IntervalSeconds <= 0 {
+		return nil, fmt.Errorf("intervalSeconds should be positive, got %d", spec.IntervalSeconds)
+	}
+	if spec.MaxEvictionsPerInterval <= 0 {
+		return nil, fmt.Errorf("maxEvictionsPerInterval should be positive, got %d", spec.MaxEvictionsPerInterval)
+	}
+	return &preReclaim{
+		leadTime:     time.Duration(spec.LeadTimeSeconds) * time.Second,
+		interval:     time.Duration(spec.IntervalSeconds) * time.Second,
+		maxEvictions: int(spec.MaxEvictionsPerInterval),
+	}, nil
+}
+
+// initPreReclaim reclaims the borrowed resources ahead of the forecast demand of the ElasticQuotas, if pre-reclaim
+// is configured.
+func (c *CapacityScheduling) initPreReclaim(spec *config.PreReclaimSpec) error {
+	preReclaim, err := newPreReclaim(spec)
+	if err != nil {
+		return fmt.Errorf("invalid PreReclaim: %w", err)
+	}
+	c.preReclaim = preReclaim
+	return nil
+}
+
+// startPreReclaim runs the reclaim loop until the context is done, if pre-reclaim is enabled.
+func (c *CapacityScheduling) startPreReclaim(ctx context.Context) {
+	if c.preReclaim != nil {
+		go wait.UntilWithContext(ctx, c.reclaimAhead, c.preReclaim.interval)
+	}
+}
+
+// forecast returns the request of the pending pods of each ElasticQuota(namespace) expected lead time ahead: the
+// pending request, plus its growth since the last sample extrapolated over the lead time. The pending request is
+// kept as the next sample.
+func (p *preReclaim) forecast(pending map[string]*framework.Resource, now time.Time) map[string]*framework.Resource {
+	forecast := make(map[string]*framework.Resource, len(pending))
+	elapsed := now.Sub(p.sampled)
+	for namespace, request := range pending {
+		forecast[namespace] = request.Clone()
+		previous, ok := p.pending[namespace]
+		if !ok || p.sampled.IsZero() || elapsed <= 0 {
+			continue
+		}
+		ratio := float64(p.leadTime) / float64(elapsed)
+		growth := combineResource(subtractResource(request, previous), nil, func(a, _ int64) int64 {
+			return int64(float64(a) * ratio)
+		})
+		addResource(forecast[namespace], growth, 1)
+	}
+	p.pending = pending
+	p.sampled = now
+	return forecast
+}
+
+// victims returns the pods to evict so that the ElasticQuotas get their forecast demand within their min, at most
+// maxEvictions of them: the lowest priority and most recently started pods first, among the pods of the ElasticQuotas
+// borrowing the resources to reclaim. The ElasticQuotaInfos are updated as if the victims were evicted.
+func (p *preReclaim) victims(elasticQuotaInfos ElasticQuotaInfos, pods []*v1.Pod, now time.Time) []*v1.Pod {
+	pending := make(map[string]*framework.Resource)
+	var borrowers []*v1.Pod
+	for _, pod := range pods {
+		eq := elasticQuotaInfos[pod.Namespace]
+		if eq == nil || eq.pool != nil || isTerminated(pod) || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if !assignedPod(pod) {
+			if pending[pod.Namespace] == nil {
+				pending[pod.Namespace] = &framework.Resource{}
+			}
+			addResource(pending[pod.Namespace], eq.computePodResourceRequest(pod), 1)
+		} else if eq.usedOverMin() {
+			borrowers = append(borrowers, pod)
+		}
+	}
+
+	shortfall := elasticQuotaInfos.reclaimShortfall(p.forecast(pending, now))
+	sort.SliceStable(borrowers, func(i, j int) bool {
+		pi, pj := corev1helpers.PodPriority(borrowers[i]), corev1helpers.PodPriority(borrowers[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return schedutil.GetPodStartTime(borrowers[j]).Before(schedutil.GetPodStartTime(borrowers[i]))
+	})
+
+	var victims []*v1.Pod
+	for _, pod := range borrowers {
+		if len(victims) >= p.maxEvictions || !cmp(shortfall, &framework.Resource{}, LowerBoundOfMin) {
+			break
+		}
+		eq := elasticQuotaInfos[pod.Namespace]
+		podRequest := eq.computePodResourceRequest(pod)
+		if !eq.usedOverMinFor(shortfall, podRequest) {
+			continue
+		}
+		eq.unreserveResource(*podRequest)
+		shortfall = subtractResource(shortfall, podRequest)
+		victims = append(victims, pod)
+	}
+	return victims
+}
+
+// reclaimShortfall returns the resources borrowed beyond the aggregated min that the ElasticQuotas need back
+// to run their forecast demand within their min, restricted to the resources they demand.
+func (e ElasticQuotaInfos) reclaimShortfall(demand map[string]*framework.Resource) *framework.Resource {
+	needed := &framework.Resource{}
+	for namespace, request := range demand {
+		eq := e[namespace]
+		if eq == nil || eq.pool != nil || eq.Min == nil {
+			continue
+		}
+		addResource(needed, minResource(request, subtractResource(eq.Min, eq.Used)), 1)
+	}
+	used, min := e.aggregatedUsedAndMinWith(*needed)
+	return restrictTo(subtractResource(used, min), needed)
+}
+
+// reclaimAhead evicts the pods borrowing the resources the ElasticQuotas are forecast to need within their min.
+// The pods are evicted through the Eviction API, so that their PodDisruptionBudgets are respected.
+func (c *CapacityScheduling) reclaimAhead(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	pods, err := c.podLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "Failed to list the pods to forecast the demand of ElasticQuotas")
+		return
+	}
+
+	c.RLock()
+	elasticQuotaInfos := c.elasticQuotaInfos.clone()
+	c.RUnlock()
+
+	for _, victim := range c.preReclaim.victims(elasticQuotaInfos, pods, time.Now()) {
+		if c.suppressPreemption(victim, "Not evicting the borrowing pod ahead of the forecast demand") {
+			continue
+		}
+		eviction := &policy.Eviction{ObjectMeta: metav1.ObjectMeta{Namespace: victim.Namespace, Name: victim.Name}}
+		if err := c.fh.ClientSet().PolicyV1().Evictions(victim.Namespace).Evict(ctx, eviction); err != nil {
+			logger.Error(err, "Failed to evict the borrowing pod ahead of the forecast demand", "pod", klog.KObj(victim))
+			continue
+		}
+		logger.V(2).Info("Evicted the borrowing pod ahead of the forecast demand", "pod", klog.KObj(victim))
+	}
+}
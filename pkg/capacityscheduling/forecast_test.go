@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func makeForecastPod(namespace, name, cpu string, priority int32, nodeName string, started time.Time) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1.PodSpec{
+			NodeName: nodeName,
+			Priority: &priority,
+			Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}},
+			}},
+		},
+		Status: v1.PodStatus{StartTime: &metav1.Time{Time: started}},
+	}
+}
+
+func TestPreReclaimForecast(t *testing.T) {
+	now := time.Now()
+	p := &preReclaim{leadTime: time.Minute}
+
+	forecast := p.forecast(map[string]*framework.Resource{"ns1": {MilliCPU: 2000}}, now)
+	if got := forecast["ns1"].MilliCPU; got != 2000 {
+		t.Errorf("expected the pending request of the first sample, 2000m cpu, got %vm", got)
+	}
+
+	// the pending request grew by 2 cpus in 10 seconds, i.e. by 12 cpus in a minute.
+	forecast = p.forecast(map[string]*framework.Resource{"ns1": {MilliCPU: 4000}, "ns2": {MilliCPU: 1000}}, now.Add(10*time.Second))
+	if got := forecast["ns1"].MilliCPU; got != 16000 {
+		t.Errorf("expected the pending request extrapolated over the lead time, 16000m cpu, got %vm", got)
+	}
+	if got := forecast["ns2"].MilliCPU; got != 1000 {
+		t.Errorf("expected the pending request of a new ElasticQuota, 1000m cpu, got %vm", got)
+	}
+
+	// a shrinking pending request is not extrapolated.
+	forecast = p.forecast(map[string]*framework.Resource{"ns1": {MilliCPU: 3000}}, now.Add(20*time.Second))
+	if got := forecast["ns1"].MilliCPU; got != 3000 {
+		t.Errorf("expected the pending request, 3000m cpu, got %vm", got)
+	}
+}
+
+func TestPreReclaimVictims(t *testing.T) {
+	now := time.Now()
+	borrowers := []*v1.Pod{
+		makeForecastPod("borrower", "low", "1", 0, "node", now.Add(-2*time.Minute)),
+		makeForecastPod("borrower", "new", "1", 0, "node", now.Add(-time.Minute)),
+		makeForecastPod("borrower", "high", "2", 10, "node", now.Add(-time.Minute)),
+	}
+	tests := []struct {
+		name         string
+		pending      []*v1.Pod
+		maxEvictions int
+		want         []string
+	}{
+		{
+			name:         "demand within the unused aggregated min",
+			pending:      []*v1.Pod{makeForecastPod("owner", "p1", "2", 0, "", now)},
+			maxEvictions: 3,
+		},
+		{
+			name:         "most recently started pod of the lowest priority first",
+			pending:      []*v1.Pod{makeForecastPod("owner", "p1", "4", 0, "", now)},
+			maxEvictions: 1,
+			want:         []string{"new"},
+		},
+		{
+			name:         "pods evicted until the shortfall is reclaimed",
+			pending:      []*v1.Pod{makeForecastPod("owner", "p1", "4", 0, "", now)},
+			maxEvictions: 3,
+			want:         []string{"new", "low"},
+		},
+		{
+			name:         "demand of the borrowing ElasticQuota beyond its min",
+			pending:      []*v1.Pod{makeForecastPod("borrower", "p1", "4", 0, "", now)},
+			maxEvictions: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// owner uses 2 out of its 10 cpus of min, borrower 6 cpus beyond its min: 2 cpus of the aggregated min are unused.
+			infos := ElasticQuotaInfos{
+				"owner":    makeTreeElasticQuotaInfo("owner", "", "10", "20", 2000),
+				"borrower": makeTreeElasticQuotaInfo("borrower", "", "2", "20", 8000),
+			}
+			p := &preReclaim{leadTime: time.Minute, maxEvictions: tt.maxEvictions}
+			var got []string
+			for _, victim := range p.victims(infos, append(tt.pending, borrowers...), now) {
+				got = append(got, victim.Name)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("expected victims %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
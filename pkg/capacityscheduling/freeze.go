@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling"
+)
+
+// PreemptionFrozenAnnotation is the annotation of the PreemptionFreezeConfigMap which, set to "true", pauses
+// the preemptions and the reclaims of CapacityScheduling until it is removed or set to another value.
+const PreemptionFrozenAnnotation = scheduling.GroupName + "/preemption-frozen"
+
+// preemptionFreeze follows the PreemptionFrozenAnnotation of the PreemptionFreezeConfigMap.
+type preemptionFreeze struct {
+	namespace string
+	name      string
+	frozen    atomic.Bool
+}
+
+// newPreemptionFreeze returns the freeze switch of the "<namespace>/<name>" ConfigMap, nil if empty.
+func newPreemptionFreeze(configMap string) (*preemptionFreeze, error) {
+	if configMap == "" {
+		return nil, nil
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(configMap)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == "" || name == "" {
+		return nil, fmt.Errorf("want <namespace>/<name>, got %q", configMap)
+	}
+	return &preemptionFreeze{namespace: namespace, name: name}, nil
+}
+
+// initPreemptionFreeze follows the freeze switch of the PreemptionFreezeConfigMap, if configured.
+func (c *CapacityScheduling) initPreemptionFreeze(configMap string) error {
+	preemptionFreeze, err := newPreemptionFreeze(configMap)
+	if err != nil {
+		return fmt.Errorf("invalid PreemptionFreezeConfigMap: %w", err)
+	}
+	c.preemptionFreeze = preemptionFreeze
+	return nil
+}
+
+// isFrozen returns whether the preemptions are paused.
+func (f *preemptionFreeze) isFrozen() bool {
+	return f != nil && f.frozen.Load()
+}
+
+// run watches the ConfigMap, only, until the context is done. It does nothing when the freeze is disabled.
+func (f *preemptionFreeze) run(ctx context.Context, cs kubernetes.Interface) error {
+	if f == nil {
+		return nil
+	}
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(cs, 0,
+		informers.WithNamespace(f.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", f.name).String()
+		}))
+	if _, err := informerFactory.Core().V1().ConfigMaps().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { f.update(ctx, obj) },
+		UpdateFunc: func(_, newObj interface{}) { f.update(ctx, newObj) },
+		DeleteFunc: func(interface{}) { f.update(ctx, nil) },
+	}); err != nil {
+		return err
+	}
+	informerFactory.Start(ctx.Done())
+	return nil
+}
+
+func (f *preemptionFreeze) update(ctx context.Context, obj interface{}) {
+	configMap, _ := obj.(*v1.ConfigMap)
+	frozen := configMap != nil && configMap.Annotations[PreemptionFrozenAnnotation] == "true"
+	if f.frozen.Swap(frozen) != frozen {
+		klog.FromContext(ctx).Info("Preemptions of CapacityScheduling", "frozen", frozen, "configMap", klog.KRef(f.namespace, f.name))
+	}
+}
+
+// suppressPreemption returns whether the preemption by or of the pod is suppressed by the freeze, recording an event
+// on the pod if so.
+func (c *CapacityScheduling) suppressPreemption(pod *v1.Pod, note string) bool {
+	if !c.preemptionFreeze.isFrozen() {
+		return false
+	}
+	if recorder := c.fh.EventRecorder(); recorder != nil {
+		recorder.Eventf(pod, nil, v1.EventTypeNormal, "PreemptionFrozen", "Preempting", "%s: preemptions are frozen by %s/%s",
+			note, c.preemptionFreeze.namespace, c.preemptionFreeze.name)
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+)
+
+func TestNewPreemptionFreeze(t *testing.T) {
+	tests := []struct {
+		configMap string
+		want      *preemptionFreeze
+		wantErr   bool
+	}{
+		{configMap: ""},
+		{configMap: "kube-system/capacity-scheduling", want: &preemptionFreeze{namespace: "kube-system", name: "capacity-scheduling"}},
+		{configMap: "capacity-scheduling", wantErr: true},
+		{configMap: "a/b/c", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := newPreemptionFreeze(tt.configMap)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%q: want error %v, got %v", tt.configMap, tt.wantErr, err)
+		}
+		if tt.want == nil {
+			if got != nil {
+				t.Errorf("%q: want no freeze, got %v/%v", tt.configMap, got.namespace, got.name)
+			}
+			continue
+		}
+		if got == nil || got.namespace != tt.want.namespace || got.name != tt.want.name {
+			t.Errorf("%q: want %v/%v, got %v", tt.configMap, tt.want.namespace, tt.want.name, got)
+		}
+	}
+}
+
+func TestPreemptionFreeze(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "capacity-scheduling"}}
+	cs := clientsetfake.NewSimpleClientset(configMap)
+	freeze := &preemptionFreeze{namespace: "kube-system", name: "capacity-scheduling"}
+	if err := freeze.run(ctx, cs); err != nil {
+		t.Fatal(err)
+	}
+	waitFrozen := func(want bool) {
+		if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+			return freeze.isFrozen() == want, nil
+		}); err != nil {
+			t.Fatalf("want frozen %v, got %v", want, freeze.isFrozen())
+		}
+	}
+	update := func(value string) {
+		configMap.Annotations = map[string]string{PreemptionFrozenAnnotation: value}
+		if _, err := cs.CoreV1().ConfigMaps("kube-system").Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitFrozen(false)
+	update("true")
+	waitFrozen(true)
+	update("false")
+	waitFrozen(false)
+	update("true")
+	waitFrozen(true)
+	if err := cs.CoreV1().ConfigMaps("kube-system").Delete(ctx, configMap.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitFrozen(false)
+}
+
+func TestPostFilterFrozen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recorder := events.NewFakeRecorder(1)
+	fwk, err := tf.NewFramework(
+		ctx,
+		[]tf.RegisterPluginFunc{
+			tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		},
+		"default-scheduler",
+		frameworkruntime.WithEventRecorder(recorder),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	freeze := &preemptionFreeze{namespace: "kube-system", name: "capacity-scheduling"}
+	freeze.frozen.Store(true)
+	c := &CapacityScheduling{fh: fwk, preemptionFreeze: freeze}
+
+	pod := st.MakePod().Name("p").Namespace("ns1").Obj()
+	result, status := c.PostFilter(ctx, framework.NewCycleState(), pod, nil)
+	if result != nil || status.Code() != framework.Unschedulable {
+		t.Errorf("want unschedulable without result, got %v, %v", result, status)
+	}
+	select {
+	case event := <-recorder.Events:
+		t.Logf("event: %v", event)
+	default:
+		t.Error("want an event noting the suppressed preemption")
+	}
+}
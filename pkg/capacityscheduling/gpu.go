@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+const (
+	// ResourceGPUSlices is the resource accounting GPUs of ElasticQuotas in slices,
+	// when GPU slicing is enabled in the plugin args.
+	ResourceGPUSlices v1.ResourceName = "scheduling.x-k8s.io/gpu-slices"
+
+	// resourceGPU is the resource of whole NVIDIA GPUs.
+	resourceGPU v1.ResourceName = "nvidia.com/gpu"
+
+	// migResourcePrefix is the prefix of NVIDIA MIG profile resources, e.g. nvidia.com/mig-3g.20gb.
+	migResourcePrefix = "nvidia.com/mig-"
+)
+
+// gpuSlicing converts the GPU resources (whole GPUs, MIG profiles, time-sliced replicas)
+// of requests and quotas to GPU slices.
+type gpuSlicing struct {
+	slicesPerGPU               int64
+	timeSlicedResourceName     v1.ResourceName
+	slicesPerTimeSlicedReplica int64
+}
+
+func newGPUSlicing(spec *config.GPUSlicingSpec) (*gpuSlicing, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	if spec.SlicesPerGPU <= 0 {
+		return nil, fmt.Errorf("slicesPerGPU should be positive, got %d", spec.SlicesPerGPU)
+	}
+	if spec.SlicesPerTimeSlicedReplica <= 0 {
+		return nil, fmt.Errorf("slicesPerTimeSlicedReplica should be positive, got %d", spec.SlicesPerTimeSlicedReplica)
+	}
+	return &gpuSlicing{
+		slicesPerGPU:               spec.SlicesPerGPU,
+		timeSlicedResourceName:     v1.ResourceName(spec.TimeSlicedResourceName),
+		slicesPerTimeSlicedReplica: spec.SlicesPerTimeSlicedReplica,
+	}, nil
+}
+
+// initGPUSlicing accounts the GPUs of the requests and quotas in slices, if GPU slicing is configured.
+func (c *CapacityScheduling) initGPUSlicing(spec *config.GPUSlicingSpec) error {
+	gpuSlicing, err := newGPUSlicing(spec)
+	if err != nil {
+		return fmt.Errorf("invalid GPUSlicing: %w", err)
+	}
+	c.gpuSlicing = gpuSlicing
+	return nil
+}
+
+// slicesOf returns the number of slices of one unit of the resource,
+// and false if the resource is not a GPU resource.
+func (g *gpuSlicing) slicesOf(name v1.ResourceName) (int64, bool) {
+	switch {
+	case name == resourceGPU:
+		return g.slicesPerGPU, true
+	case g.timeSlicedResourceName != "" && name == g.timeSlicedResourceName:
+		return g.slicesPerTimeSlicedReplica, true
+	case strings.HasPrefix(string(name), migResourcePrefix):
+		// MIG profiles are named <compute slices>g.<memory>gb, e.g. 3g.20gb
+		profile := strings.TrimPrefix(string(name), migResourcePrefix)
+		computeSlices, _, found := strings.Cut(profile, "g.")
+		if !found {
+			return 0, false
+		}
+		slices, err := strconv.ParseInt(computeSlices, 10, 64)
+		if err != nil || slices <= 0 {
+			return 0, false
+		}
+		return slices, true
+	}
+	return 0, false
+}
+
+// toSlices replaces the GPU resources of the given resource by the GPU slices they account for.
+// It is a no-op when GPU slicing is not enabled.
+func (g *gpuSlicing) toSlices(r *framework.Resource) {
+	if g == nil || r == nil {
+		return
+	}
+	var slices int64
+	for name, quantity := range r.ScalarResources {
+		perUnit, ok := g.slicesOf(name)
+		if !ok {
+			continue
+		}
+		// saturate for unbounded quotas
+		if quantity > (UpperBoundOfMax-slices)/perUnit {
+			slices = UpperBoundOfMax
+		} else {
+			slices += quantity * perUnit
+		}
+		delete(r.ScalarResources, name)
+	}
+	if slices > 0 {
+		r.SetScalar(ResourceGPUSlices, min(r.ScalarResources[ResourceGPUSlices], UpperBoundOfMax-slices)+slices)
+	}
+}
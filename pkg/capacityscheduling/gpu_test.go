@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+var testGPUSlicingSpec = &config.GPUSlicingSpec{
+	SlicesPerGPU:               7,
+	TimeSlicedResourceName:     "nvidia.com/gpu.shared",
+	SlicesPerTimeSlicedReplica: 1,
+}
+
+func TestNewGPUSlicing(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    *config.GPUSlicingSpec
+		wantErr bool
+	}{
+		{name: "disabled", spec: nil},
+		{name: "valid", spec: testGPUSlicingSpec},
+		{name: "invalid slicesPerGPU", spec: &config.GPUSlicingSpec{SlicesPerGPU: 0, SlicesPerTimeSlicedReplica: 1}, wantErr: true},
+		{name: "invalid slicesPerTimeSlicedReplica", spec: &config.GPUSlicingSpec{SlicesPerGPU: 7, SlicesPerTimeSlicedReplica: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := newGPUSlicing(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.spec == nil && g != nil {
+				t.Errorf("expected GPU slicing to be disabled, got %+v", g)
+			}
+		})
+	}
+}
+
+func TestGPUSlicingToSlices(t *testing.T) {
+	g, err := newGPUSlicing(testGPUSlicingSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		resource *framework.Resource
+		expected *framework.Resource
+	}{
+		{
+			name:     "whole GPUs",
+			resource: &framework.Resource{MilliCPU: 1000, ScalarResources: map[v1.ResourceName]int64{"nvidia.com/gpu": 2}},
+			expected: &framework.Resource{MilliCPU: 1000, ScalarResources: map[v1.ResourceName]int64{ResourceGPUSlices: 14}},
+		},
+		{
+			name: "MIG profiles and time-sliced replicas",
+			resource: &framework.Resource{ScalarResources: map[v1.ResourceName]int64{
+				"nvidia.com/mig-1g.5gb":  2,
+				"nvidia.com/mig-3g.20gb": 1,
+				"nvidia.com/gpu.shared":  3,
+			}},
+			expected: &framework.Resource{ScalarResources: map[v1.ResourceName]int64{ResourceGPUSlices: 8}},
+		},
+		{
+			name:     "slices are added to the GPU slices already accounted",
+			resource: &framework.Resource{ScalarResources: map[v1.ResourceName]int64{ResourceGPUSlices: 4, "nvidia.com/gpu": 1}},
+			expected: &framework.Resource{ScalarResources: map[v1.ResourceName]int64{ResourceGPUSlices: 11}},
+		},
+		{
+			name:     "other resources are kept",
+			resource: &framework.Resource{ScalarResources: map[v1.ResourceName]int64{"example.com/foo": 1, "nvidia.com/mig-invalid": 1}},
+			expected: &framework.Resource{ScalarResources: map[v1.ResourceName]int64{"example.com/foo": 1, "nvidia.com/mig-invalid": 1}},
+		},
+		{
+			name:     "unbounded quotas saturate",
+			resource: &framework.Resource{ScalarResources: map[v1.ResourceName]int64{"nvidia.com/gpu": UpperBoundOfMax}},
+			expected: &framework.Resource{ScalarResources: map[v1.ResourceName]int64{ResourceGPUSlices: UpperBoundOfMax}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.toSlices(tt.resource)
+			if !reflect.DeepEqual(tt.resource, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, tt.resource)
+			}
+		})
+	}
+}
+
+func TestElasticQuotaInfoGPUSlices(t *testing.T) {
+	g, err := newGPUSlicing(testGPUSlicingSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &CapacityScheduling{gpuSlicing: g}
+	eq := &v1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "eq", Namespace: "ns1"},
+		Spec: v1alpha1.ElasticQuotaSpec{
+			Min: v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+			Max: v1.ResourceList{ResourceGPUSlices: resource.MustParse("10")},
+		},
+	}
+	elasticQuotaInfo := c.newElasticQuotaInfo(eq)
+	if got := elasticQuotaInfo.Min.ScalarResources; !reflect.DeepEqual(got, map[v1.ResourceName]int64{ResourceGPUSlices: 7}) {
+		t.Errorf("expected min of 7 GPU slices, got %v", got)
+	}
+
+	gpuPod := func(name string, requests v1.ResourceList) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1", UID: types.UID(name)},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: requests}}}},
+		}
+	}
+	if err := elasticQuotaInfo.addPodIfNotPresent(gpuPod("p1", v1.ResourceList{"nvidia.com/mig-3g.20gb": resource.MustParse("2")})); err != nil {
+		t.Fatal(err)
+	}
+	if got := elasticQuotaInfo.Used.ScalarResources[ResourceGPUSlices]; got != 6 {
+		t.Errorf("expected 6 GPU slices used, got %v", got)
+	}
+
+	wholeGPU := elasticQuotaInfo.computePodResourceRequest(gpuPod("p2", v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}))
+	if !elasticQuotaInfo.usedOverMaxWith(wholeGPU) {
+		t.Errorf("expected a whole GPU to exceed the max of the ElasticQuota")
+	}
+	timeSliced := elasticQuotaInfo.computePodResourceRequest(gpuPod("p3", v1.ResourceList{"nvidia.com/gpu.shared": resource.MustParse("1")}))
+	if elasticQuotaInfo.usedOverMaxWith(timeSliced) {
+		t.Errorf("expected a time-sliced replica to fit the max of the ElasticQuota")
+	}
+
+	if err := elasticQuotaInfo.deletePodIfPresent(gpuPod("p1", v1.ResourceList{"nvidia.com/mig-3g.20gb": resource.MustParse("2")})); err != nil {
+		t.Fatal(err)
+	}
+	if got := elasticQuotaInfo.Used.ScalarResources[ResourceGPUSlices]; got != 0 {
+		t.Errorf("expected no GPU slices used, got %v", got)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// ancestors returns the ElasticQuotas of the ancestors of the namespace in its quota tree, the parent first.
+// The tree is cut at a parent without ElasticQuota, and before the first ancestor seen twice if the parents
+// form a cycle.
+func (e ElasticQuotaInfos) ancestors(namespace string) []*ElasticQuotaInfo {
+	var ancestors []*ElasticQuotaInfo
+	seen := sets.New(namespace)
+	for eq := e[namespace]; eq != nil && eq.parent != "" && !seen.Has(eq.parent); eq = e[eq.parent] {
+		parent := e[eq.parent]
+		if parent == nil {
+			break
+		}
+		seen.Insert(eq.parent)
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+// isDescendant returns whether the ElasticQuota of the namespace is in the subtree of the ancestor, other than the ancestor.
+func (e ElasticQuotaInfos) isDescendant(namespace, ancestor string) bool {
+	for _, eq := range e.ancestors(namespace) {
+		if eq.Namespace == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// subtreeUsed returns the usage of the ElasticQuota of the namespace and all its descendants.
+func (e ElasticQuotaInfos) subtreeUsed(namespace string) *framework.Resource {
+	used := &framework.Resource{}
+	for ns, eq := range e {
+		if ns == namespace || e.isDescendant(ns, namespace) {
+			addResource(used, eq.Used, 1)
+		}
+	}
+	return used
+}
+
+// siblingSubtrees returns the namespaces of the ElasticQuotas in the subtrees of the siblings of the ElasticQuota
+// of the namespace, i.e. of the other children of its parent. It is empty for a root.
+func (e ElasticQuotaInfos) siblingSubtrees(namespace string) sets.Set[string] {
+	siblings := sets.New[string]()
+	ancestors := e.ancestors(namespace)
+	if len(ancestors) == 0 {
+		return siblings
+	}
+	parent := ancestors[0].Namespace
+	for ns := range e {
+		if ns != namespace && e.isDescendant(ns, parent) && !e.isDescendant(ns, namespace) {
+			siblings.Insert(ns)
+		}
+	}
+	return siblings
+}
+
+// ancestorsGrant returns the unused min of the ancestors of the namespace its ElasticQuota can use beyond its own min:
+// the min of each ancestor used neither by the ancestor itself nor by its other descendants beyond their min. The
+// ancestors with a node pool grant nothing, as their min is only guaranteed on their pool.
+func (e ElasticQuotaInfos) ancestorsGrant(namespace string) *framework.Resource {
+	granted := &framework.Resource{}
+	for _, ancestor := range e.ancestors(namespace) {
+		if ancestor.pool != nil {
+			continue
+		}
+		unused := subtractResource(ancestor.Min, ancestor.Used)
+		for ns, eq := range e {
+			if ns != namespace && e.isDescendant(ns, ancestor.Namespace) {
+				unused = subtractResource(unused, subtractResource(eq.Used, eq.Min))
+			}
+		}
+		addResource(granted, unused, 1)
+	}
+	return granted
+}
+
+// ancestorUsedOverMaxWith returns the first ancestor of the namespace whose subtree uses more than
+// its max with the pod request, nil if none.
+func (e ElasticQuotaInfos) ancestorUsedOverMaxWith(namespace string, podRequest *framework.Resource) *ElasticQuotaInfo {
+	for _, ancestor := range e.ancestors(namespace) {
+		if ancestor.Max != nil && cmp2(podRequest, e.subtreeUsed(ancestor.Namespace), ancestor.Max, UpperBoundOfMax) {
+			return ancestor
+		}
+	}
+	return nil
+}
+
+// ancestorsUsedOverMaxWithOn is ancestorUsedOverMaxWith restricted to the given resources.
+func (e ElasticQuotaInfos) ancestorsUsedOverMaxWithOn(namespace string, podRequest, resources *framework.Resource) bool {
+	for _, ancestor := range e.ancestors(namespace) {
+		if ancestor.Max != nil && cmp2(restrictTo(podRequest, resources), restrictTo(e.subtreeUsed(ancestor.Namespace), resources), ancestor.Max, UpperBoundOfMax) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func makeTreeElasticQuotaInfo(namespace, parent, min, max string, usedMilliCPU int64) *ElasticQuotaInfo {
+	eq := newElasticQuotaInfo(namespace,
+		v1.ResourceList{v1.ResourceCPU: resource.MustParse(min)},
+		v1.ResourceList{v1.ResourceCPU: resource.MustParse(max)}, nil)
+	eq.parent = parent
+	eq.Used = &framework.Resource{MilliCPU: usedMilliCPU}
+	return eq
+}
+
+// newTreeElasticQuotaInfos returns the quota tree of the hierarchy tests: org has the children team-a and team-b,
+// and team-a the child team-a1. x and y are parents of each other, and orphan has a parent without ElasticQuota.
+func newTreeElasticQuotaInfos() ElasticQuotaInfos {
+	return ElasticQuotaInfos{
+		"org":     makeTreeElasticQuotaInfo("org", "", "10", "20", 2000),
+		"team-a":  makeTreeElasticQuotaInfo("team-a", "org", "2", "10", 3000),
+		"team-a1": makeTreeElasticQuotaInfo("team-a1", "team-a", "1", "4", 1000),
+		"team-b":  makeTreeElasticQuotaInfo("team-b", "org", "2", "10", 4000),
+		"x":       makeTreeElasticQuotaInfo("x", "y", "1", "2", 0),
+		"y":       makeTreeElasticQuotaInfo("y", "x", "1", "2", 0),
+		"orphan":  makeTreeElasticQuotaInfo("orphan", "missing", "1", "2", 0),
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	infos := newTreeElasticQuotaInfos()
+	for namespace, want := range map[string][]string{
+		"org":     nil,
+		"team-a1": {"team-a", "org"},
+		"team-b":  {"org"},
+		"x":       {"y"},
+		"orphan":  nil,
+	} {
+		var got []string
+		for _, eq := range infos.ancestors(namespace) {
+			got = append(got, eq.Namespace)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("expected ancestors %v of %v, got %v", want, namespace, got)
+		}
+	}
+}
+
+func TestSubtreeUsed(t *testing.T) {
+	infos := newTreeElasticQuotaInfos()
+	for namespace, want := range map[string]int64{"org": 10000, "team-a": 4000, "team-b": 4000, "x": 0} {
+		if got := infos.subtreeUsed(namespace).MilliCPU; got != want {
+			t.Errorf("expected %vm cpu used by the subtree of %v, got %vm", want, namespace, got)
+		}
+	}
+}
+
+func TestSiblingSubtrees(t *testing.T) {
+	infos := newTreeElasticQuotaInfos()
+	for namespace, want := range map[string][]string{
+		"team-a":  {"team-b"},
+		"team-b":  {"team-a", "team-a1"},
+		"team-a1": nil,
+		"org":     nil,
+	} {
+		if got := infos.siblingSubtrees(namespace); !got.Equal(sets.New(want...)) {
+			t.Errorf("expected sibling subtrees %v of %v, got %v", want, namespace, sets.List(got))
+		}
+	}
+}
+
+func TestAncestorsGrant(t *testing.T) {
+	infos := newTreeElasticQuotaInfos()
+	// org has 8 unused cpus, team-a borrows 1 cpu and team-b 2 cpus beyond their min, team-a has no unused min.
+	for namespace, want := range map[string]int64{"team-a": 6000, "team-b": 7000, "team-a1": 5000, "org": 0} {
+		if got := infos.ancestorsGrant(namespace).MilliCPU; got != want {
+			t.Errorf("expected %vm cpu granted to %v by its ancestors, got %vm", want, namespace, got)
+		}
+	}
+}
+
+func TestAncestorUsedOverMaxWith(t *testing.T) {
+	infos := newTreeElasticQuotaInfos()
+	tests := []struct {
+		name      string
+		namespace string
+		cpuReq    int64
+		want      string
+	}{
+		{
+			name:      "within the max of all the ancestors",
+			namespace: "team-a1",
+			cpuReq:    6000,
+		},
+		{
+			name:      "over the max of the parent",
+			namespace: "team-a1",
+			cpuReq:    7000,
+			want:      "team-a",
+		},
+		{
+			name:      "over the max of the root",
+			namespace: "team-b",
+			cpuReq:    11000,
+			want:      "org",
+		},
+		{
+			name:      "root without ancestors",
+			namespace: "org",
+			cpuReq:    100000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if ancestor := infos.ancestorUsedOverMaxWith(tt.namespace, &framework.Resource{MilliCPU: tt.cpuReq}); ancestor != nil {
+				got = ancestor.Namespace
+			}
+			if got != tt.want {
+				t.Errorf("expected the ancestor over its max %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
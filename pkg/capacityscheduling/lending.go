@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"sort"
+
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// loan is a lending agreement of an ElasticQuota to the ElasticQuota of the borrower namespace.
+type loan struct {
+	borrower string
+	max      *framework.Resource
+}
+
+func newLoans(lending []v1alpha1.ElasticQuotaLoan) []loan {
+	var loans []loan
+	for _, l := range lending {
+		loans = append(loans, loan{borrower: l.Borrower, max: framework.NewResource(l.Max)})
+	}
+	return loans
+}
+
+// loanGrant is the part of the unused min of a lender granted to a borrower under a lending agreement.
+type loanGrant struct {
+	lender   string
+	borrower string
+	granted  *framework.Resource
+	// reserved is the part of granted not used by the borrower yet, which other borrowers can't borrow.
+	reserved *framework.Resource
+}
+
+// loanGrants are the resources lent under the lending agreements of all the ElasticQuotas.
+type loanGrants struct {
+	grants []*loanGrant
+	// granted are the resources each borrower can use beyond its own min.
+	granted map[string]*framework.Resource
+}
+
+// loanGrants grants the unused min of each ElasticQuota to its borrowers, in the order of its lending
+// agreements and up to the max of each agreement. The usage of a borrower over its min uses up its grants
+// in the order of the lenders. The ElasticQuotas with a node pool neither lend nor borrow, as their min
+// is only guaranteed on their pool.
+func (e ElasticQuotaInfos) loanGrants() *loanGrants {
+	g := &loanGrants{granted: make(map[string]*framework.Resource)}
+	lenders := make([]string, 0, len(e))
+	for namespace, lender := range e {
+		if lender.pool == nil && len(lender.loans) != 0 {
+			lenders = append(lenders, namespace)
+		}
+	}
+	sort.Strings(lenders)
+
+	borrowed := make(map[string]*framework.Resource)
+	for _, namespace := range lenders {
+		lender := e[namespace]
+		unused := subtractResource(lender.Min, lender.Used)
+		for _, l := range lender.loans {
+			borrower := e[l.borrower]
+			if borrower == nil || borrower.pool != nil || borrower == lender {
+				continue
+			}
+			granted := minResource(l.max, unused)
+			unused = subtractResource(unused, granted)
+			if borrowed[l.borrower] == nil {
+				borrowed[l.borrower] = subtractResource(borrower.Used, borrower.Min)
+			}
+			reserved := subtractResource(granted, borrowed[l.borrower])
+			borrowed[l.borrower] = subtractResource(borrowed[l.borrower], granted)
+			g.grants = append(g.grants, &loanGrant{lender: namespace, borrower: l.borrower, granted: granted, reserved: reserved})
+			if total := g.granted[l.borrower]; total != nil {
+				addResource(total, granted, 1)
+			} else {
+				g.granted[l.borrower] = granted.Clone()
+			}
+		}
+	}
+	return g
+}
+
+// reservedExcept returns the resources reserved to the borrowers other than the namespace,
+// out of the unused min of the lenders other than the namespace.
+func (g *loanGrants) reservedExcept(namespace string) *framework.Resource {
+	reserved := &framework.Resource{}
+	for _, grant := range g.grants {
+		if grant.lender != namespace && grant.borrower != namespace {
+			addResource(reserved, grant.reserved, 1)
+		}
+	}
+	return reserved
+}
+
+// usedOverGrantWith returns whether the quota uses more than its min and the resources granted
+// to it under lending agreements with the pod request.
+func (e *ElasticQuotaInfo) usedOverGrantWith(podRequest, granted *framework.Resource) bool {
+	limit := granted.Clone()
+	if e.Min != nil {
+		addResource(limit, e.Min, 1)
+	}
+	return cmp2(podRequest, e.Used, limit, LowerBoundOfMin)
+}
+
+// aggregatedUsedOverMinWithout is aggregatedUsedOverMinWith with the reserved resources taken out of the min.
+func (e ElasticQuotaInfos) aggregatedUsedOverMinWithout(podRequest framework.Resource, reserved *framework.Resource) bool {
+	used, min := e.aggregatedUsedAndMinWith(podRequest)
+	return cmp(used, subtractResource(min, reserved), LowerBoundOfMin)
+}
+
+// combineResource applies op to the quantities of each resource of x and y, nil standing for no resources.
+func combineResource(x, y *framework.Resource, op func(a, b int64) int64) *framework.Resource {
+	if x == nil {
+		x = &framework.Resource{}
+	}
+	if y == nil {
+		y = &framework.Resource{}
+	}
+	r := &framework.Resource{
+		MilliCPU:         op(x.MilliCPU, y.MilliCPU),
+		Memory:           op(x.Memory, y.Memory),
+		EphemeralStorage: op(x.EphemeralStorage, y.EphemeralStorage),
+		AllowedPodNumber: int(op(int64(x.AllowedPodNumber), int64(y.AllowedPodNumber))),
+	}
+	for name, quantity := range x.ScalarResources {
+		r.SetScalar(name, op(quantity, y.ScalarResources[name]))
+	}
+	for name, quantity := range y.ScalarResources {
+		if _, ok := x.ScalarResources[name]; !ok {
+			r.SetScalar(name, op(0, quantity))
+		}
+	}
+	return r
+}
+
+// subtractResource returns x - y, without negative quantities.
+func subtractResource(x, y *framework.Resource) *framework.Resource {
+	return combineResource(x, y, func(a, b int64) int64 { return max(a-b, 0) })
+}
+
+func minResource(x, y *framework.Resource) *framework.Resource {
+	return combineResource(x, y, func(a, b int64) int64 { return min(a, b) })
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	testutil "github.com/amiraBenamer20/scheduler-plugins/test/util"
+)
+
+func makeLendingElasticQuota(namespace, min string, lending ...v1alpha1.ElasticQuotaLoan) *v1alpha1.ElasticQuota {
+	return &v1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "eq", Namespace: namespace},
+		Spec: v1alpha1.ElasticQuotaSpec{
+			Min:     v1.ResourceList{v1.ResourceCPU: resource.MustParse(min)},
+			Lending: lending,
+		},
+	}
+}
+
+func makeLoan(borrower, cpu string) v1alpha1.ElasticQuotaLoan {
+	return v1alpha1.ElasticQuotaLoan{Borrower: borrower, Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}}
+}
+
+// newLendingElasticQuotaInfos returns the ElasticQuotas of the lending tests: ns1 lends 4 cpus to ns2
+// and 2 cpus to ns3 out of its 6 unused cpus, ns2 borrows 3 of them, ns4 has a node pool and ns5 no loan.
+func newLendingElasticQuotaInfos(t *testing.T) ElasticQuotaInfos {
+	c := newPoolTestPlugin(t)
+	infos := ElasticQuotaInfos{
+		"ns1": c.newElasticQuotaInfo(makeLendingElasticQuota("ns1", "10", makeLoan("ns2", "4"), makeLoan("ns3", "4"), makeLoan("ns4", "4"))),
+		"ns2": c.newElasticQuotaInfo(makeLendingElasticQuota("ns2", "2")),
+		"ns3": c.newElasticQuotaInfo(makeLendingElasticQuota("ns3", "2")),
+		"ns4": c.newElasticQuotaInfo(makePoolElasticQuota("ns4", v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")}, nil, map[string]string{"pool": "ns4"})),
+		"ns5": c.newElasticQuotaInfo(makeLendingElasticQuota("ns5", "2")),
+	}
+	infos["ns1"].Used = &framework.Resource{MilliCPU: 4000}
+	infos["ns2"].Used = &framework.Resource{MilliCPU: 5000}
+	infos["ns3"].Used = &framework.Resource{MilliCPU: 1000}
+	return infos
+}
+
+func TestLoanGrants(t *testing.T) {
+	grants := newLendingElasticQuotaInfos(t).loanGrants()
+
+	wantGrants := []loanGrant{
+		{lender: "ns1", borrower: "ns2", granted: &framework.Resource{MilliCPU: 4000}, reserved: &framework.Resource{MilliCPU: 1000}},
+		{lender: "ns1", borrower: "ns3", granted: &framework.Resource{MilliCPU: 2000}, reserved: &framework.Resource{MilliCPU: 2000}},
+	}
+	if len(grants.grants) != len(wantGrants) {
+		t.Fatalf("expected %v grants, got %v", len(wantGrants), len(grants.grants))
+	}
+	for i, want := range wantGrants {
+		got := grants.grants[i]
+		if got.lender != want.lender || got.borrower != want.borrower ||
+			got.granted.MilliCPU != want.granted.MilliCPU || got.reserved.MilliCPU != want.reserved.MilliCPU {
+			t.Errorf("expected grant %+v, got %+v", want, got)
+		}
+		if total := grants.granted[want.borrower]; total == nil || total.MilliCPU != want.granted.MilliCPU {
+			t.Errorf("expected %vm cpu granted to %v, got %v", want.granted.MilliCPU, want.borrower, total)
+		}
+	}
+
+	for namespace, want := range map[string]int64{"ns1": 0, "ns2": 2000, "ns3": 1000, "ns5": 3000} {
+		if got := grants.reservedExcept(namespace).MilliCPU; got != want {
+			t.Errorf("expected %vm cpu reserved out of the reach of %v, got %vm", want, namespace, got)
+		}
+	}
+}
+
+func TestPreFilterLending(t *testing.T) {
+	// the sum of min is 16 cpus and the sum of usage 10 cpus, 3 of the 6 unused cpus are reserved to ns2 and ns3.
+	tests := []struct {
+		name      string
+		namespace string
+		cpuReq    int64
+		expected  framework.Code
+	}{
+		{
+			name:      "lender within its min, including the resources it lends",
+			namespace: "ns1",
+			cpuReq:    6000,
+			expected:  framework.Success,
+		},
+		{
+			name:      "borrower within the resources lent to it",
+			namespace: "ns3",
+			cpuReq:    3000,
+			expected:  framework.Success,
+		},
+		{
+			name:      "borrower over the resources lent to it borrows the unused min of all the quotas",
+			namespace: "ns2",
+			cpuReq:    2000,
+			expected:  framework.Success,
+		},
+		{
+			name:      "borrower over the resources lent to it and the unused min of all the quotas",
+			namespace: "ns2",
+			cpuReq:    5000,
+			expected:  framework.Unschedulable,
+		},
+		{
+			name:      "borrower without loan within the unused min not lent",
+			namespace: "ns5",
+			cpuReq:    3000,
+			expected:  framework.Success,
+		},
+		{
+			name:      "the resources lent to other quotas can't be borrowed",
+			namespace: "ns5",
+			cpuReq:    4000,
+			expected:  framework.Unschedulable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			fwk, err := tf.NewFramework(
+				ctx, registeredPlugins, "",
+				frameworkruntime.WithPodNominator(testutil.NewPodNominator(nil)),
+				frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(make([]*v1.Pod, 0), make([]*v1.Node, 0))),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cs := &CapacityScheduling{
+				elasticQuotaInfos: newLendingElasticQuotaInfos(t),
+				fh:                fwk,
+			}
+
+			pod := makePod("p", tt.namespace, 0, tt.cpuReq, 0, 0, "p", "")
+			if _, got := cs.PreFilter(ctx, framework.NewCycleState(), pod); got.Code() != tt.expected {
+				t.Errorf("expected %v, got %v : %v", tt.expected, got.Code(), got.Message())
+			}
+		})
+	}
+}
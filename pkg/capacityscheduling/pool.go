@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	// ErrReasonPoolMin is the reason for a pod not fitting the min of the node pool of its ElasticQuota.
+	ErrReasonPoolMin = "node(s) were in the pool of the ElasticQuota whose min is used up"
+	// ErrReasonSharedPool is the reason for a pod not able to borrow on the shared nodes.
+	ErrReasonSharedPool = "node(s) were shared and the ElasticQuota can't borrow resources"
+	// ErrReasonDedicatedPool is the reason for a pod not allowed on the node pool of another ElasticQuota.
+	ErrReasonDedicatedPool = "node(s) were dedicated to the ElasticQuota of another namespace"
+)
+
+// nodePool tracks the usage of an ElasticQuota on the nodes dedicated to it.
+// The min of the ElasticQuota is only guaranteed on those nodes, while its
+// usage over min is borrowed on the shared nodes, not dedicated to any ElasticQuota.
+type nodePool struct {
+	selector labels.Selector
+	// nodeLabels returns the labels of a node, and false if the node is unknown.
+	nodeLabels func(nodeName string) (labels.Set, bool)
+	// pods are the keys of the pods running in the pool, used their usage.
+	pods sets.Set[string]
+	used *framework.Resource
+}
+
+func newNodePool(selector map[string]string, nodeLabels func(nodeName string) (labels.Set, bool)) *nodePool {
+	if len(selector) == 0 {
+		return nil
+	}
+	return &nodePool{
+		selector:   labels.SelectorFromSet(selector),
+		nodeLabels: nodeLabels,
+		pods:       sets.New[string](),
+		used:       framework.NewResource(nil),
+	}
+}
+
+func (p *nodePool) clone() *nodePool {
+	if p == nil {
+		return nil
+	}
+	return &nodePool{
+		selector:   p.selector,
+		nodeLabels: p.nodeLabels,
+		pods:       p.pods.Clone(),
+		used:       p.used.Clone(),
+	}
+}
+
+// matches returns true if the node belongs to the pool.
+func (p *nodePool) matches(node *v1.Node) bool {
+	return p != nil && node != nil && p.selector.Matches(labels.Set(node.Labels))
+}
+
+// contains returns true if the named node belongs to the pool.
+func (p *nodePool) contains(nodeName string) bool {
+	if p == nil || nodeName == "" || p.nodeLabels == nil {
+		return false
+	}
+	nodeLabels, ok := p.nodeLabels(nodeName)
+	return ok && p.selector.Matches(nodeLabels)
+}
+
+func (p *nodePool) addPod(key, nodeName string, podRequest *framework.Resource) {
+	if !p.contains(nodeName) || p.pods.Has(key) {
+		return
+	}
+	p.pods.Insert(key)
+	addResource(p.used, podRequest, 1)
+}
+
+func (p *nodePool) deletePod(key string, podRequest *framework.Resource) {
+	if p == nil || !p.pods.Has(key) {
+		return
+	}
+	p.pods.Delete(key)
+	addResource(p.used, podRequest, -1)
+}
+
+// sharedUsed returns the usage of the ElasticQuota out of its node pool.
+func (e *ElasticQuotaInfo) sharedUsed() *framework.Resource {
+	used := e.Used.Clone()
+	if e.pool != nil {
+		addResource(used, e.pool.used, -1)
+	}
+	return used
+}
+
+// poolUsedOverMinWith returns true if the usage of the node pool with the pod request exceeds the min.
+func (e *ElasticQuotaInfo) poolUsedOverMinWith(podRequest *framework.Resource) bool {
+	if e.pool == nil || e.Min == nil {
+		return true
+	}
+	return cmp2(podRequest, e.pool.used, e.Min, LowerBoundOfMin)
+}
+
+// poolOwner returns the ElasticQuota the node is dedicated to, preferring the one of the
+// given namespace, and nil if the node is shared.
+func (e ElasticQuotaInfos) poolOwner(namespace string, node *v1.Node) *ElasticQuotaInfo {
+	if info := e[namespace]; info != nil && info.pool.matches(node) {
+		return info
+	}
+	var owner *ElasticQuotaInfo
+	for _, info := range e {
+		// pick the first namespace in order for stable messages
+		if info.pool.matches(node) && (owner == nil || info.Namespace < owner.Namespace) {
+			owner = info
+		}
+	}
+	return owner
+}
+
+func addResource(dst, r *framework.Resource, sign int64) {
+	dst.MilliCPU += sign * r.MilliCPU
+	dst.Memory += sign * r.Memory
+	dst.EphemeralStorage += sign * r.EphemeralStorage
+	dst.AllowedPodNumber += int(sign) * r.AllowedPodNumber
+	for name, value := range r.ScalarResources {
+		dst.SetScalar(name, dst.ScalarResources[name]+sign*value)
+	}
+}
+
+// nodeLabels returns the labels of a node from the node lister.
+func (c *CapacityScheduling) nodeLabels(nodeName string) (labels.Set, bool) {
+	if c.nodeLister == nil {
+		return nil, false
+	}
+	node, err := c.nodeLister.Get(nodeName)
+	if err != nil {
+		return nil, false
+	}
+	return labels.Set(node.Labels), true
+}
+
+// Filter keeps the node pools of the ElasticQuotas to their namespace:
+// 1. a pod may only run in the pool of its ElasticQuota within its min,
+// 2. a pod of an ElasticQuota with a pool may only borrow on the shared nodes,
+// 3. a pod may never run in the pool of the ElasticQuota of another namespace.
+func (c *CapacityScheduling) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	snapshotElasticQuota, err := getElasticQuotaSnapshotState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	preFilterState, err := getPreFilterState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+
+	owner := snapshotElasticQuota.elasticQuotaInfos.poolOwner(pod.Namespace, nodeInfo.Node())
+	switch {
+	case owner == nil:
+		if preFilterState.overSharedMin {
+			return framework.NewStatus(framework.Unschedulable, ErrReasonSharedPool)
+		}
+	case owner.Namespace == pod.Namespace:
+		if !preFilterState.fitsInPool {
+			return framework.NewStatus(framework.Unschedulable, ErrReasonPoolMin)
+		}
+	default:
+		klog.FromContext(ctx).V(5).Info("Node is dedicated to another ElasticQuota", "pod", klog.KObj(pod), "node", klog.KObj(nodeInfo.Node()), "elasticQuota", owner.Namespace)
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonDedicatedPool)
+	}
+	return nil
+}
+
+// recomputePool accounts the pods of the ElasticQuota found by the pod lister in its new node pool.
+func (c *CapacityScheduling) recomputePool(elasticQuotaInfo *ElasticQuotaInfo) error {
+	if elasticQuotaInfo.pool == nil || c.podLister == nil {
+		return nil
+	}
+	pods, err := c.podLister.Pods(elasticQuotaInfo.Namespace).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("listing pods of ElasticQuota %v: %w", elasticQuotaInfo.Namespace, err)
+	}
+	for _, pod := range pods {
+		key, err := framework.GetPodKey(pod)
+		if err != nil || !elasticQuotaInfo.pods.Has(key) {
+			continue
+		}
+		elasticQuotaInfo.pool.addPod(key, pod.Spec.NodeName, elasticQuotaInfo.computePodResourceRequest(pod))
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func newPoolTestPlugin(t *testing.T, nodes ...*v1.Node) *CapacityScheduling {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range nodes {
+		if err := indexer.Add(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &CapacityScheduling{
+		nodeLister:        corelisters.NewNodeLister(indexer),
+		elasticQuotaInfos: NewElasticQuotaInfos(),
+	}
+}
+
+func makePoolElasticQuota(namespace string, min, max v1.ResourceList, nodeSelector map[string]string) *v1alpha1.ElasticQuota {
+	return &v1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "eq", Namespace: namespace},
+		Spec:       v1alpha1.ElasticQuotaSpec{Min: min, Max: max, NodeSelector: nodeSelector},
+	}
+}
+
+func TestNodePoolAccounting(t *testing.T) {
+	poolNode := st.MakeNode().Name("pool-node").Label("pool", "ns1").Obj()
+	sharedNode := st.MakeNode().Name("shared-node").Obj()
+	c := newPoolTestPlugin(t, poolNode, sharedNode)
+
+	ns1 := c.newElasticQuotaInfo(makePoolElasticQuota("ns1",
+		v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+		v1.ResourceList{v1.ResourceCPU: resource.MustParse("10")},
+		map[string]string{"pool": "ns1"}))
+	ns2 := c.newElasticQuotaInfo(makePoolElasticQuota("ns2",
+		v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+		v1.ResourceList{v1.ResourceCPU: resource.MustParse("10")},
+		nil))
+	if ns2.pool != nil {
+		t.Fatalf("expected no node pool without a node selector")
+	}
+	elasticQuotaInfos := ElasticQuotaInfos{"ns1": ns1, "ns2": ns2}
+
+	inPool := st.MakePod().Namespace("ns1").Name("p1").UID("p1").Node("pool-node").Req(map[v1.ResourceName]string{v1.ResourceCPU: "3"}).Obj()
+	borrowing := st.MakePod().Namespace("ns1").Name("p2").UID("p2").Node("shared-node").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj()
+	for _, pod := range []*v1.Pod{inPool, borrowing} {
+		if err := ns1.addPodIfNotPresent(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := ns1.pool.used.MilliCPU; got != 3000 {
+		t.Errorf("expected 3 cpu used in the pool, got %vm", got)
+	}
+	if got := ns1.sharedUsed().MilliCPU; got != 1000 {
+		t.Errorf("expected 1 cpu used out of the pool, got %vm", got)
+	}
+
+	request := &framework.Resource{MilliCPU: 1000}
+	if ns1.poolUsedOverMinWith(request) {
+		t.Errorf("expected 1 more cpu to fit the min of the pool")
+	}
+	if !ns1.poolUsedOverMinWith(&framework.Resource{MilliCPU: 2000}) {
+		t.Errorf("expected 2 more cpus to exceed the min of the pool")
+	}
+	// only the usage out of the pool borrows the min of the shared nodes: 1 + 1 <= 2
+	if elasticQuotaInfos.aggregatedUsedOverMinWith(*request) {
+		t.Errorf("expected 1 more cpu to fit the min of the shared nodes")
+	}
+	if !elasticQuotaInfos.aggregatedUsedOverMinWith(framework.Resource{MilliCPU: 2000}) {
+		t.Errorf("expected 2 more cpus to exceed the min of the shared nodes")
+	}
+
+	clone := ns1.clone()
+	if err := clone.deletePodIfPresent(inPool); err != nil {
+		t.Fatal(err)
+	}
+	if got := clone.pool.used.MilliCPU; got != 0 {
+		t.Errorf("expected no cpu used in the pool of the clone, got %vm", got)
+	}
+	if got := ns1.pool.used.MilliCPU; got != 3000 {
+		t.Errorf("expected the pool to be left untouched by its clone, got %vm", got)
+	}
+
+	// nominated pods are accounted on the node they are nominated to
+	nominated := st.MakePod().Namespace("ns1").Name("p3").UID("p3").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj()
+	if err := ns1.addPodOnNodeIfNotPresent(nominated, "pool-node"); err != nil {
+		t.Fatal(err)
+	}
+	if got := ns1.pool.used.MilliCPU; got != 4000 {
+		t.Errorf("expected 4 cpus used in the pool, got %vm", got)
+	}
+}
+
+func TestFilterNodePool(t *testing.T) {
+	poolNode := st.MakeNode().Name("pool-node").Label("pool", "ns1").Obj()
+	sharedNode := st.MakeNode().Name("shared-node").Obj()
+	c := newPoolTestPlugin(t, poolNode, sharedNode)
+
+	elasticQuotaInfos := ElasticQuotaInfos{
+		"ns1": c.newElasticQuotaInfo(makePoolElasticQuota("ns1", nil, nil, map[string]string{"pool": "ns1"})),
+		"ns2": c.newElasticQuotaInfo(makePoolElasticQuota("ns2", nil, nil, nil)),
+	}
+
+	tests := []struct {
+		name           string
+		pod            *v1.Pod
+		node           *v1.Node
+		preFilterState *PreFilterState
+		wantStatus     *framework.Status
+	}{
+		{
+			name:           "pod within min in its pool",
+			pod:            st.MakePod().Namespace("ns1").Name("p").Obj(),
+			node:           poolNode,
+			preFilterState: &PreFilterState{fitsInPool: true},
+		},
+		{
+			name:           "pod over min in its pool",
+			pod:            st.MakePod().Namespace("ns1").Name("p").Obj(),
+			node:           poolNode,
+			preFilterState: &PreFilterState{},
+			wantStatus:     framework.NewStatus(framework.Unschedulable, ErrReasonPoolMin),
+		},
+		{
+			name:           "pod of a pool borrowing on a shared node",
+			pod:            st.MakePod().Namespace("ns1").Name("p").Obj(),
+			node:           sharedNode,
+			preFilterState: &PreFilterState{},
+		},
+		{
+			name:           "pod of a pool can't borrow on a shared node",
+			pod:            st.MakePod().Namespace("ns1").Name("p").Obj(),
+			node:           sharedNode,
+			preFilterState: &PreFilterState{fitsInPool: true, overSharedMin: true},
+			wantStatus:     framework.NewStatus(framework.Unschedulable, ErrReasonSharedPool),
+		},
+		{
+			name:           "pod of another quota in a pool",
+			pod:            st.MakePod().Namespace("ns2").Name("p").Obj(),
+			node:           poolNode,
+			preFilterState: &PreFilterState{},
+			wantStatus:     framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonDedicatedPool),
+		},
+		{
+			name:           "pod without quota in a pool",
+			pod:            st.MakePod().Namespace("ns3").Name("p").Obj(),
+			node:           poolNode,
+			preFilterState: &PreFilterState{},
+			wantStatus:     framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonDedicatedPool),
+		},
+		{
+			name:           "pod without quota on a shared node",
+			pod:            st.MakePod().Namespace("ns3").Name("p").Obj(),
+			node:           sharedNode,
+			preFilterState: &PreFilterState{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := framework.NewCycleState()
+			state.Write(ElasticQuotaSnapshotKey, &ElasticQuotaSnapshotState{elasticQuotaInfos: elasticQuotaInfos.clone()})
+			state.Write(preFilterStateKey, tt.preFilterState)
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(tt.node)
+
+			status := c.Filter(context.TODO(), state, tt.pod, nodeInfo)
+			if tt.wantStatus.Code() != status.Code() || tt.wantStatus.Message() != status.Message() {
+				t.Errorf("expected status %v, got %v", tt.wantStatus, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"fmt"
+	"time"
+)
+
+// initPreemptionProtection protects the pods of the ElasticQuotas which scaled up within their min from the
+// preemptions by the pods of other ElasticQuotas for the given number of seconds, zero disabling the protection.
+func (c *CapacityScheduling) initPreemptionProtection(seconds int64) error {
+	if seconds < 0 {
+		return fmt.Errorf("preemptionProtectionSeconds should not be negative, got %d", seconds)
+	}
+	c.preemptionProtection = time.Duration(seconds) * time.Second
+	return nil
+}
+
+// protectedFromPreemption returns whether the quota scaled up within its min less than protection ago,
+// so that its pods are not preempted by the pods of other quotas reclaiming their min.
+func (e *ElasticQuotaInfo) protectedFromPreemption(now time.Time, protection time.Duration) bool {
+	return protection > 0 && !e.scaledUp.IsZero() && now.Sub(e.scaledUp) < protection
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInitPreemptionProtection(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds int64
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "disabled", seconds: 0},
+		{name: "valid", seconds: 30, want: 30 * time.Second},
+		{name: "negative", seconds: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &CapacityScheduling{}
+			err := c.initPreemptionProtection(tt.seconds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if c.preemptionProtection != tt.want {
+				t.Errorf("expected protection %v, got %v", tt.want, c.preemptionProtection)
+			}
+		})
+	}
+}
+
+func TestProtectedFromPreemption(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		scaledUp   time.Time
+		protection time.Duration
+		want       bool
+	}{
+		{name: "protection disabled", scaledUp: now.Add(-time.Second), protection: 0, want: false},
+		{name: "never scaled up", protection: time.Minute, want: false},
+		{name: "scaled up within the protection", scaledUp: now.Add(-time.Second), protection: time.Minute, want: true},
+		{name: "scaled up before the protection", scaledUp: now.Add(-2 * time.Minute), protection: time.Minute, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &ElasticQuotaInfo{scaledUp: tt.scaledUp}
+			if got := e.protectedFromPreemption(now, tt.protection); got != tt.want {
+				t.Errorf("expected protected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
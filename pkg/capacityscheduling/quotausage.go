@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"encoding/json"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+// QuotaUsageAnnotation is the annotation CapacityScheduling sets on the pods it binds, when annotateQuotaUsage is
+// enabled, with the QuotaUsage of the pod in JSON, so that cost attribution and chargeback tools can consume the
+// quota decisions made at placement time.
+const QuotaUsageAnnotation = scheduling.GroupName + "/elastic-quota-usage"
+
+// QuotaUsageOutcome is the outcome of the accounting of a pod in its ElasticQuota.
+type QuotaUsageOutcome string
+
+const (
+	// QuotaUsageWithinMin means the request of the pod fit within the min of its ElasticQuota.
+	QuotaUsageWithinMin QuotaUsageOutcome = "WithinMin"
+	// QuotaUsageBorrowed means the pod borrowed some of its request beyond the min of its ElasticQuota.
+	QuotaUsageBorrowed QuotaUsageOutcome = "Borrowed"
+)
+
+// QuotaUsage is the accounting of a pod in the ElasticQuota of its namespace when it was reserved.
+type QuotaUsage struct {
+	// Outcome tells whether the pod fit within the min of the ElasticQuota or borrowed beyond it.
+	Outcome QuotaUsageOutcome `json:"outcome"`
+	// Requested is the request of the pod accounted in the ElasticQuota, in GPU slices with GPU slicing.
+	Requested v1.ResourceList `json:"requested"`
+	// Borrowed is the part of Requested beyond the min of the ElasticQuota, empty within the min.
+	Borrowed v1.ResourceList `json:"borrowed,omitempty"`
+}
+
+// quotaUsageStateKey is the key in CycleState to the QuotaUsage of the pod computed at Reserve.
+const quotaUsageStateKey = "QuotaUsage" + Name
+
+// Clone the QuotaUsage state.
+func (u *QuotaUsage) Clone() framework.StateData {
+	return u
+}
+
+// newQuotaUsage returns the accounting of the pod request in the ElasticQuota, once reserved in it.
+func newQuotaUsage(e *ElasticQuotaInfo, podRequest *framework.Resource) *QuotaUsage {
+	borrowed := e.borrowedBy(podRequest)
+	usage := &QuotaUsage{
+		Outcome:   QuotaUsageWithinMin,
+		Requested: nonZero(util.ResourceList(podRequest)),
+		Borrowed:  nonZero(util.ResourceList(borrowed)),
+	}
+	if len(usage.Borrowed) > 0 {
+		usage.Outcome = QuotaUsageBorrowed
+	}
+	return usage
+}
+
+// borrowedBy returns the part of the request of a pod reserved in the quota which is beyond the min of the quota,
+// per resource.
+func (e *ElasticQuotaInfo) borrowedBy(podRequest *framework.Resource) *framework.Resource {
+	quotaMin := e.Min
+	if quotaMin == nil {
+		quotaMin = &framework.Resource{}
+	}
+	beyondMin := func(request, used, bound int64) int64 {
+		return max(0, min(request, used-bound))
+	}
+	borrowed := &framework.Resource{
+		MilliCPU:         beyondMin(podRequest.MilliCPU, e.Used.MilliCPU, quotaMin.MilliCPU),
+		Memory:           beyondMin(podRequest.Memory, e.Used.Memory, quotaMin.Memory),
+		EphemeralStorage: beyondMin(podRequest.EphemeralStorage, e.Used.EphemeralStorage, quotaMin.EphemeralStorage),
+	}
+	for name, quantity := range podRequest.ScalarResources {
+		bound := int64(LowerBoundOfMin)
+		if q, ok := quotaMin.ScalarResources[name]; ok {
+			bound = q
+		}
+		if b := beyondMin(quantity, e.Used.ScalarResources[name], bound); b > 0 {
+			borrowed.SetScalar(name, b)
+		}
+	}
+	return borrowed
+}
+
+// nonZero returns the resources of the list with a non-zero quantity, nil if none.
+func nonZero(list v1.ResourceList) v1.ResourceList {
+	var result v1.ResourceList
+	for name, quantity := range list {
+		if quantity.IsZero() {
+			continue
+		}
+		if result == nil {
+			result = make(v1.ResourceList)
+		}
+		result[name] = quantity
+	}
+	return result
+}
+
+// recordQuotaUsage keeps the QuotaUsage of the pod reserved in the ElasticQuota in CycleState, for PostBind to
+// annotate the pod with, when annotateQuotaUsage is enabled.
+func (c *CapacityScheduling) recordQuotaUsage(state *framework.CycleState, e *ElasticQuotaInfo, pod *v1.Pod) {
+	if c.annotateQuotaUsage {
+		state.Write(quotaUsageStateKey, newQuotaUsage(e, e.computePodResourceRequest(pod)))
+	}
+}
+
+// PostBind annotates the pod with the QuotaUsageAnnotation when annotateQuotaUsage is enabled. The annotation is
+// informative: failing to annotate the pod is only logged.
+func (c *CapacityScheduling) PostBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	data, err := state.Read(quotaUsageStateKey)
+	if err != nil {
+		// the pod is not accounted in an ElasticQuota, or the annotation is disabled.
+		return
+	}
+	usage, ok := data.(*QuotaUsage)
+	if !ok {
+		return
+	}
+	logger := klog.FromContext(ctx)
+	value, err := json.Marshal(usage)
+	if err != nil {
+		logger.Error(err, "Failed to encode the quota usage", "pod", klog.KObj(pod))
+		return
+	}
+	podCopy := pod.DeepCopy()
+	if podCopy.Annotations == nil {
+		podCopy.Annotations = make(map[string]string)
+	}
+	podCopy.Annotations[QuotaUsageAnnotation] = string(value)
+	patch, err := util.CreateMergePatch(pod, podCopy)
+	if err != nil {
+		logger.Error(err, "Failed to create the quota usage annotation patch", "pod", klog.KObj(pod))
+		return
+	}
+	if _, err := c.fh.ClientSet().CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		logger.Error(err, "Failed to annotate the pod with its quota usage", "pod", klog.KObj(pod))
+	}
+}
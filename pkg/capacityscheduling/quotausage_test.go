@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+)
+
+func TestQuotaUsage(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotate     bool
+		namespace    string
+		cpu          int64
+		gpu          int64
+		wantOutcome  QuotaUsageOutcome
+		wantRequest  map[v1.ResourceName]int64
+		wantBorrowed map[v1.ResourceName]int64
+	}{
+		{
+			name:        "request within the min",
+			annotate:    true,
+			namespace:   "ns1",
+			cpu:         500,
+			wantOutcome: QuotaUsageWithinMin,
+			wantRequest: map[v1.ResourceName]int64{v1.ResourceCPU: 500, v1.ResourceMemory: 100000},
+		},
+		{
+			name:         "request partly borrowed beyond the min",
+			annotate:     true,
+			namespace:    "ns1",
+			cpu:          1000,
+			wantOutcome:  QuotaUsageBorrowed,
+			wantRequest:  map[v1.ResourceName]int64{v1.ResourceCPU: 1000, v1.ResourceMemory: 100000},
+			wantBorrowed: map[v1.ResourceName]int64{v1.ResourceCPU: 500},
+		},
+		{
+			name:         "extended resource without min borrowed",
+			annotate:     true,
+			namespace:    "ns1",
+			cpu:          500,
+			gpu:          1,
+			wantOutcome:  QuotaUsageBorrowed,
+			wantRequest:  map[v1.ResourceName]int64{v1.ResourceCPU: 500, v1.ResourceMemory: 100000, ResourceGPU: 1000},
+			wantBorrowed: map[v1.ResourceName]int64{ResourceGPU: 1000},
+		},
+		{
+			name:      "pod without ElasticQuota",
+			annotate:  true,
+			namespace: "ns2",
+			cpu:       500,
+		},
+		{
+			name:      "annotation disabled",
+			namespace: "ns1",
+			cpu:       500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			pod := makePod("p", tt.namespace, 100, tt.cpu, tt.gpu, 0, "p", "")
+			cs := clientsetfake.NewSimpleClientset(pod)
+			fwk, err := tf.NewFramework(ctx, []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}, "default-scheduler", frameworkruntime.WithClientSet(cs))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// ns1 already uses 1.5 of its 2 CPUs.
+			eq := newElasticQuotaInfo("ns1", makeResourceList(2000, 1000000), nil, makeResourceList(1500, 0))
+			c := &CapacityScheduling{
+				fh:                 fwk,
+				elasticQuotaInfos:  ElasticQuotaInfos{"ns1": eq},
+				annotateQuotaUsage: tt.annotate,
+			}
+
+			state := framework.NewCycleState()
+			if status := c.Reserve(ctx, state, pod, "node-a"); !status.IsSuccess() {
+				t.Fatalf("unexpected Reserve status: %v", status)
+			}
+			c.PostBind(ctx, state, pod, "node-a")
+
+			got, err := cs.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, ok := got.Annotations[QuotaUsageAnnotation]
+			if tt.wantOutcome == "" {
+				if ok {
+					t.Errorf("want no quota usage annotation, got %v", value)
+				}
+				return
+			}
+			var usage QuotaUsage
+			if err := json.Unmarshal([]byte(value), &usage); err != nil {
+				t.Fatalf("invalid quota usage annotation %q: %v", value, err)
+			}
+			if usage.Outcome != tt.wantOutcome {
+				t.Errorf("want outcome %v, got %v", tt.wantOutcome, usage.Outcome)
+			}
+			if got := milliValues(usage.Requested); !reflect.DeepEqual(got, tt.wantRequest) {
+				t.Errorf("want requested %v, got %v", tt.wantRequest, got)
+			}
+			if got := milliValues(usage.Borrowed); !reflect.DeepEqual(got, tt.wantBorrowed) {
+				t.Errorf("want borrowed %v, got %v", tt.wantBorrowed, got)
+			}
+		})
+	}
+}
+
+// milliValues returns the quantities of the list in milli units, nil if empty.
+func milliValues(list v1.ResourceList) map[v1.ResourceName]int64 {
+	if len(list) == 0 {
+		return nil
+	}
+	values := make(map[v1.ResourceName]int64)
+	for name, quantity := range list {
+		values[name] = quantity.MilliValue()
+	}
+	return values
+}
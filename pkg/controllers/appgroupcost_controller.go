@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/api"
+	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
+)
+
+var (
+	appGroupCostLabels = []string{"namespace", "appgroup", "origin_zone", "destination_zone"}
+
+	// appGroupZonePairDependencies is the number of pairs of replicas of dependent workloads of an AppGroup per zone pair.
+	appGroupZonePairDependencies = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "networkcostaware",
+		Name:      "appgroup_zone_pair_dependencies",
+		Help:      "Number of pairs of replicas of dependent workloads of an AppGroup, from the zone of the replica to the zone of its dependency.",
+	}, appGroupCostLabels)
+
+	// appGroupZonePairCost is the network cost of the dependencies of an AppGroup per zone pair.
+	appGroupZonePairCost = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "networkcostaware",
+		Name:      "appgroup_zone_pair_cost",
+		Help:      "Network cost of the pairs of replicas of dependent workloads of an AppGroup, from the zone of the replica to the zone of its dependency.",
+	}, appGroupCostLabels)
+
+	// appGroupZonePairMinBandwidth is the bandwidth required by the dependencies of an AppGroup per zone pair.
+	appGroupZonePairMinBandwidth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "networkcostaware",
+		Name:      "appgroup_zone_pair_min_bandwidth",
+		Help:      "MinBandwidth of the pairs of replicas of dependent workloads of an AppGroup, from the zone of the replica to the zone of its dependency.",
+	}, appGroupCostLabels)
+
+	registerAppGroupCostMetrics sync.Once
+)
+
+// AppGroupCostReconciler reports the network costs of the placements of the AppGroups, aggregated per pair of
+// zones, as metrics of the controller. They give the cross-zone traffic the NetworkCostAware plugin created.
+type AppGroupCostReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// NetworkTopologyNamespace and NetworkTopologyName of the NetworkTopology giving the costs between the zones.
+	NetworkTopologyNamespace string
+	NetworkTopologyName      string
+	// WeightsName of the costs of the NetworkTopology, as in the NetworkCostArgs of the plugin.
+	WeightsName string
+	// Interval is the period of the reports of each AppGroup.
+	Interval time.Duration
+	// RateLimiter are the settings of the rate limiter of the reconciliation queue.
+	RateLimiter RateLimiterOptions
+}
+
+// zonePair : origin and destination zones of the dependencies of an AppGroup
+type zonePair struct {
+	origin      string
+	destination string
+}
+
+// zonePairCost : dependencies of an AppGroup from one zone to another
+type zonePairCost struct {
+	dependencies int64
+	cost         int64
+	minBandwidth int64
+}
+
+// +kubebuilder:rbac:groups=appgroup.diktyo.x-k8s.io,resources=appgroups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networktopology.diktyo.x-k8s.io,resources=networktopologies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods;nodes,verbs=get;list;watch
+func (r *AppGroupCostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	ag := &agv1alpha1.AppGroup{}
+	if err := r.Get(ctx, req.NamespacedName, ag); err != nil {
+		if apierrs.IsNotFound(err) {
+			deleteAppGroupCosts(req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	pods := &v1.PodList{}
+	if err := r.List(ctx, pods, client.MatchingLabels{agv1alpha1.AppGroupLabel: ag.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	nodes := &v1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return ctrl.Result{}, err
+	}
+	nt := &ntv1alpha1.NetworkTopology{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: r.NetworkTopologyNamespace, Name: r.NetworkTopologyName}, nt); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		log.V(4).Info("no network topology, reporting the maximum cost between zones", "namespace", r.NetworkTopologyNamespace, "name", r.NetworkTopologyName)
+		nt = nil
+	}
+
+	costs := appGroupZonePairCosts(ag, pods.Items, nodes.Items, api.NewModel(ag, nt, api.Options{WeightsName: r.WeightsName}))
+	deleteAppGroupCosts(ag.Namespace, ag.Name)
+	for pair, c := range costs {
+		labels := prometheus.Labels{"namespace": ag.Namespace, "appgroup": ag.Name, "origin_zone": pair.origin, "destination_zone": pair.destination}
+		appGroupZonePairDependencies.With(labels).Set(float64(c.dependencies))
+		appGroupZonePairCost.With(labels).Set(float64(c.cost))
+		appGroupZonePairMinBandwidth.With(labels).Set(float64(c.minBandwidth))
+	}
+	return ctrl.Result{RequeueAfter: r.Interval}, nil
+}
+
+// appGroupZonePairCosts aggregates the costs of the dependencies between the scheduled replicas of the workloads of
+// the AppGroup per pair of zones. The cost between zones without cost in the model is the maximum cost, as in the plugin.
+func appGroupZonePairCosts(ag *agv1alpha1.AppGroup, pods []v1.Pod, nodes []v1.Node, model *api.Model) map[zonePair]*zonePairCost {
+	locations := make(map[string]api.Location, len(nodes))
+	for i := range nodes {
+		locations[nodes[i].Name] = api.NodeLocation(&nodes[i], "")
+	}
+	replicas := make(map[string][]api.Location)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if location, ok := locations[pod.Spec.NodeName]; ok {
+			selector := networkcostawareutil.GetPodAppGroupSelector(pod)
+			replicas[selector] = append(replicas[selector], location)
+		}
+	}
+
+	costs := make(map[zonePair]*zonePairCost)
+	for _, w := range ag.Spec.Workloads {
+		for _, d := range w.Dependencies {
+			for _, origin := range replicas[w.Workload.Selector] {
+				for _, destination := range replicas[d.Workload.Selector] {
+					cost, ok := model.Cost(origin, destination)
+					if !ok {
+						cost = api.MaxCost
+					}
+					pair := zonePair{origin: origin.Zone, destination: destination.Zone}
+					c, ok := costs[pair]
+					if !ok {
+						c = &zonePairCost{}
+						costs[pair] = c
+					}
+					c.dependencies++
+					c.cost += cost
+					c.minBandwidth += d.MinBandwidth.Value()
+				}
+			}
+		}
+	}
+	return costs
+}
+
+// deleteAppGroupCosts deletes the metrics of the AppGroup, e.g. of the zone pairs it no longer uses.
+func deleteAppGroupCosts(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "appgroup": name}
+	appGroupZonePairDependencies.DeletePartialMatch(labels)
+	appGroupZonePairCost.DeletePartialMatch(labels)
+	appGroupZonePairMinBandwidth.DeletePartialMatch(labels)
+}
+
+// SetupWithManager sets up the controller with the Manager, and registers its metrics in the metrics registry
+// of controller-runtime, served by the Manager.
+func (r *AppGroupCostReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	registerAppGroupCostMetrics.Do(func() {
+		metrics.Registry.MustRegister(appGroupZonePairDependencies, appGroupZonePairCost, appGroupZonePairMinBandwidth)
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("appgroupcost").
+		For(&agv1alpha1.AppGroup{}).
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter.rateLimiter()}).
+		Complete(r)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+)
+
+func makeAppGroupPod(name, selector, nodeName string, phase v1.PodPhase) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				agv1alpha1.AppGroupLabel:         "shop",
+				agv1alpha1.AppGroupSelectorLabel: selector,
+			},
+		},
+		Spec:   v1.PodSpec{NodeName: nodeName},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func TestAppGroupCostController_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(s))
+	utilruntime.Must(agv1alpha1.AddToScheme(s))
+	utilruntime.Must(ntv1alpha1.AddToScheme(s))
+
+	ag := &agv1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+		Spec: agv1alpha1.AppGroupSpec{
+			NumMembers: 3,
+			Workloads: agv1alpha1.AppGroupWorkloadList{
+				{
+					Workload: agv1alpha1.AppGroupWorkloadInfo{Selector: "frontend"},
+					Dependencies: agv1alpha1.DependenciesList{
+						{Workload: agv1alpha1.AppGroupWorkloadInfo{Selector: "backend"}, MinBandwidth: resource.MustParse("100")},
+					},
+				},
+				{Workload: agv1alpha1.AppGroupWorkloadInfo{Selector: "backend"}},
+			},
+		},
+	}
+	nt := &ntv1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt-default", Namespace: "default"},
+		Spec: ntv1alpha1.NetworkTopologySpec{Weights: ntv1alpha1.WeightList{{
+			Name: "UserDefined",
+			TopologyList: ntv1alpha1.TopologyList{{
+				TopologyKey: ntv1alpha1.NetworkTopologyZone,
+				OriginList: ntv1alpha1.OriginList{
+					makeCosts("z1", ntv1alpha1.CostInfo{Destination: "z2", NetworkCost: 10}),
+				},
+			}},
+		}}},
+	}
+	client := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		ag, nt,
+		makeTopologyNode("n1", "us-east", "z1"),
+		makeTopologyNode("n2", "us-east", "z2"),
+		makeTopologyNode("n3", "us-east", "z3"),
+		makeAppGroupPod("frontend-1", "frontend", "n1", v1.PodRunning),
+		makeAppGroupPod("frontend-2", "frontend", "n3", v1.PodRunning),
+		makeAppGroupPod("frontend-3", "frontend", "", v1.PodPending),
+		makeAppGroupPod("backend-1", "backend", "n1", v1.PodRunning),
+		makeAppGroupPod("backend-2", "backend", "n2", v1.PodRunning),
+		makeAppGroupPod("backend-3", "backend", "n2", v1.PodSucceeded),
+	).Build()
+	r := &AppGroupCostReconciler{
+		Client:                   client,
+		Scheme:                   s,
+		NetworkTopologyNamespace: "default",
+		NetworkTopologyName:      "nt-default",
+		WeightsName:              "UserDefined",
+		Interval:                 time.Minute,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "shop"}}
+
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if result.RequeueAfter != time.Minute {
+		t.Errorf("expected to requeue after %v, got %v", time.Minute, result.RequeueAfter)
+	}
+	for _, tt := range []struct {
+		origin, destination              string
+		dependencies, cost, minBandwidth float64
+	}{
+		{origin: "z1", destination: "z1", dependencies: 1, cost: 1, minBandwidth: 100},
+		{origin: "z1", destination: "z2", dependencies: 1, cost: 10, minBandwidth: 100},
+		// no cost between z3 and z1 or z2 in the NetworkTopology
+		{origin: "z3", destination: "z1", dependencies: 1, cost: 100, minBandwidth: 100},
+		{origin: "z3", destination: "z2", dependencies: 1, cost: 100, minBandwidth: 100},
+	} {
+		labels := prometheus.Labels{"namespace": "default", "appgroup": "shop", "origin_zone": tt.origin, "destination_zone": tt.destination}
+		if got := testutil.ToFloat64(appGroupZonePairDependencies.With(labels)); got != tt.dependencies {
+			t.Errorf("%s to %s: expected %v dependencies, got %v", tt.origin, tt.destination, tt.dependencies, got)
+		}
+		if got := testutil.ToFloat64(appGroupZonePairCost.With(labels)); got != tt.cost {
+			t.Errorf("%s to %s: expected cost %v, got %v", tt.origin, tt.destination, tt.cost, got)
+		}
+		if got := testutil.ToFloat64(appGroupZonePairMinBandwidth.With(labels)); got != tt.minBandwidth {
+			t.Errorf("%s to %s: expected min bandwidth %v, got %v", tt.origin, tt.destination, tt.minBandwidth, got)
+		}
+	}
+	if got := testutil.CollectAndCount(appGroupZonePairCost); got != 4 {
+		t.Errorf("expected 4 zone pairs, got %v", got)
+	}
+
+	if err := client.Delete(ctx, ag); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if got := testutil.CollectAndCount(appGroupZonePairCost); got != 0 {
+		t.Errorf("expected the zone pairs of the deleted AppGroup to be deleted, got %v", got)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	quota "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/client-go/tools/record"
 
@@ -48,8 +49,12 @@ type ElasticQuotaReconciler struct {
 	recorder record.EventRecorder
 
 	client.Client
-	Scheme  *runtime.Scheme
-	Workers int
+	Scheme      *runtime.Scheme
+	Workers     int
+	RateLimiter RateLimiterOptions
+	// MaxUsageWarningPercent is the percentage of its max from which the usage of an ElasticQuota is warned about,
+	// disabled if 0.
+	MaxUsageWarningPercent int
 }
 
 // +kubebuilder:rbac:groups=scheduling.x-k8s.io,resources=elasticquota,verbs=get;list;watch;create;update;patch;delete
@@ -69,12 +74,28 @@ func (r *ElasticQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	// TODO: When elastic quota supports multiple instances in a namespace, modify this
-	if len(eqList.Items) == 0 {
+	var eq *schedv1alpha1.ElasticQuota
+	for i := range eqList.Items {
+		deleting, err := reconcileFinalizer(ctx, r.Client, r.recorder, &eqList.Items[i])
+		if err != nil {
+			log.Error(err, "Reconcile finalizer for elasticquota failed")
+			return ctrl.Result{}, err
+		}
+		if !deleting && eq == nil {
+			eq = &eqList.Items[i]
+		}
+	}
+	if eq == nil {
 		log.V(5).Info("no elasticquota found")
 		return ctrl.Result{}, nil
 	}
 
-	eq := &eqList.Items[0]
+	if msg, err := r.validateParent(ctx, eq); err != nil {
+		return ctrl.Result{}, err
+	} else if msg != "" {
+		r.recorder.Event(eq, v1.EventTypeWarning, "InvalidParent", msg)
+	}
+
 	used, err := r.computeElasticQuotaUsed(ctx, req.Namespace, eq)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -92,6 +113,7 @@ func (r *ElasticQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if err = r.patchElasticQuota(ctx, eq, newEQ); err != nil {
 		return ctrl.Result{}, err
 	}
+	r.recordUsageEvents(eq, used)
 	r.recorder.Event(eq, v1.EventTypeNormal, "Synced", fmt.Sprintf("Elastic Quota %s synced successfully", req.NamespacedName))
 	return ctrl.Result{}, nil
 }
@@ -101,6 +123,34 @@ func (r *ElasticQuotaReconciler) patchElasticQuota(ctx context.Context, old, new
 	return r.Status().Patch(ctx, new, patch)
 }
 
+// validateParent returns why the parent of the ElasticQuota is invalid: its namespace has no ElasticQuota, or the parents
+// form a cycle. The scheduler then cuts the quota tree at the invalid parent. It returns an empty message for a valid parent.
+func (r *ElasticQuotaReconciler) validateParent(ctx context.Context, eq *schedv1alpha1.ElasticQuota) (string, error) {
+	seen := sets.New(eq.Namespace)
+	for parent := eq.Spec.Parent; parent != ""; {
+		if seen.Has(parent) {
+			return fmt.Sprintf("Parents of elastic quota %v/%v form a cycle at namespace %v", eq.Namespace, eq.Name, parent), nil
+		}
+		seen.Insert(parent)
+		eqList := &schedv1alpha1.ElasticQuotaList{}
+		if err := r.List(ctx, eqList, client.InNamespace(parent)); err != nil {
+			return "", err
+		}
+		var next *schedv1alpha1.ElasticQuota
+		for i := range eqList.Items {
+			if eqList.Items[i].DeletionTimestamp == nil {
+				next = &eqList.Items[i]
+				break
+			}
+		}
+		if next == nil {
+			return fmt.Sprintf("Parent %v of elastic quota %v/%v has no elastic quota", parent, eq.Namespace, eq.Name), nil
+		}
+		parent = next.Spec.Parent
+	}
+	return "", nil
+}
+
 func (r *ElasticQuotaReconciler) computeElasticQuotaUsed(ctx context.Context, namespace string, eq *schedv1alpha1.ElasticQuota) (v1.ResourceList, error) {
 	used := newZeroUsed(eq)
 	podList := &v1.PodList{}
@@ -156,7 +206,7 @@ func computePodResourceRequest(pod *v1.Pod) v1.ResourceList {
 	}
 	// If Overhead is being utilized, add to the total requests for the pod
 	if pod.Spec.Overhead != nil {
-		quota.Add(result, pod.Spec.Overhead)
+		result = quota.Add(result, pod.Spec.Overhead)
 	}
 	// take max_resource for init_containers and containers
 	return quota.Max(result, initRes)
@@ -181,6 +231,6 @@ func (r *ElasticQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Watches(&v1.Pod{}, &handler.EnqueueRequestForObject{}).
 		For(&schedv1alpha1.ElasticQuota{}).
-		WithOptions(controller.Options{MaxConcurrentReconciles: r.Workers}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.Workers, RateLimiter: r.RateLimiter.rateLimiter()}).
 		Complete(r)
 }
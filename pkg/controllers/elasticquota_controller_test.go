@@ -268,8 +268,43 @@ func setUpEQ(ctx context.Context,
 	controller := &ElasticQuotaReconciler{
 		Client:   client,
 		Scheme:   s,
-		recorder: record.NewFakeRecorder(3),
+		recorder: record.NewFakeRecorder(10),
 	}
 
 	return controller, client
 }
+
+func TestElasticQuotaController_ValidateParent(t *testing.T) {
+	ctx := context.TODO()
+	makeEQ := func(namespace, parent string) *v1alpha1.ElasticQuota {
+		eq := testutil.MakeEQ(namespace, namespace+"-eq").Obj()
+		eq.Spec.Parent = parent
+		return eq
+	}
+	eqs := []*v1alpha1.ElasticQuota{
+		makeEQ("org", ""),
+		makeEQ("team-a", "org"),
+		makeEQ("orphan", "missing"),
+		makeEQ("x", "y"),
+		makeEQ("y", "x"),
+	}
+	controller, _ := setUpEQ(ctx, t, eqs, nil)
+
+	for _, tt := range []struct {
+		eq      *v1alpha1.ElasticQuota
+		invalid bool
+	}{
+		{eq: eqs[0]},
+		{eq: eqs[1]},
+		{eq: eqs[2], invalid: true},
+		{eq: eqs[3], invalid: true},
+	} {
+		msg, err := controller.validateParent(ctx, tt.eq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if invalid := msg != ""; invalid != tt.invalid {
+			t.Errorf("expected the parent of %v to be invalid: %v, got %q", tt.eq.Namespace, tt.invalid, msg)
+		}
+	}
+}
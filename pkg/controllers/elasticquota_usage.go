@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	schedv1alpha1 "github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// recordUsageEvents records a warning on the ElasticQuota when its usage crosses its min, from which its pods may
+// be preempted, or reaches the MaxUsageWarningPercent of its max, from which its pods may no longer be scheduled.
+// The usage is compared with the usage last reported in the status, so that each crossing is recorded once.
+func (r *ElasticQuotaReconciler) recordUsageEvents(eq *schedv1alpha1.ElasticQuota, used v1.ResourceList) {
+	overMin := crossedResources(eq.Status.Used, used, eq.Spec.Min, func(used, min resource.Quantity) bool {
+		return used.Cmp(min) > 0
+	})
+	if len(overMin) > 0 {
+		r.recorder.Eventf(eq, v1.EventTypeWarning, "OverMin",
+			"Usage exceeds min for %s; the pods above min may be preempted", describeUsage(overMin, used, eq.Spec.Min))
+	}
+
+	if r.MaxUsageWarningPercent <= 0 {
+		return
+	}
+	percent := float64(r.MaxUsageWarningPercent)
+	nearMax := crossedResources(eq.Status.Used, used, eq.Spec.Max, func(used, max resource.Quantity) bool {
+		return used.AsApproximateFloat64()*100 >= max.AsApproximateFloat64()*percent
+	})
+	if len(nearMax) > 0 {
+		r.recorder.Eventf(eq, v1.EventTypeWarning, "NearMax",
+			"Usage reached %d%% of max for %s; the pods beyond max won't be scheduled", r.MaxUsageWarningPercent,
+			describeUsage(nearMax, used, eq.Spec.Max))
+	}
+}
+
+// crossedResources returns the sorted resources of the limits whose usage reached its limit, as given by reached,
+// with the new usage but not with the old one. A resource missing from a usage isn't used.
+func crossedResources(oldUsed, used, limits v1.ResourceList, reached func(used, limit resource.Quantity) bool) []v1.ResourceName {
+	var crossed []v1.ResourceName
+	for name, limit := range limits {
+		if reached(used[name], limit) && !reached(oldUsed[name], limit) {
+			crossed = append(crossed, name)
+		}
+	}
+	slices.Sort(crossed)
+	return crossed
+}
+
+// describeUsage describes the usage and the limit of the resources, e.g. "cpu (5/4), nvidia.com/gpu (2/2)"
+func describeUsage(names []v1.ResourceName, used, limits v1.ResourceList) string {
+	descriptions := make([]string, 0, len(names))
+	for _, name := range names {
+		u, l := used[name], limits[name]
+		descriptions = append(descriptions, fmt.Sprintf("%s (%s/%s)", name, u.String(), l.String()))
+	}
+	return strings.Join(descriptions, ", ")
+}
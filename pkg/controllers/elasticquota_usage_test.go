@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestRecordUsageEvents(t *testing.T) {
+	resources := func(cpu, gpu string) v1.ResourceList {
+		return v1.ResourceList{
+			v1.ResourceCPU:                    resource.MustParse(cpu),
+			v1.ResourceName("nvidia.com/gpu"): resource.MustParse(gpu),
+		}
+	}
+	cases := []struct {
+		name       string
+		percent    int
+		oldUsed    v1.ResourceList
+		used       v1.ResourceList
+		wantEvents []string
+	}{
+		{
+			name:    "within min",
+			percent: 90,
+			oldUsed: resources("1", "0"),
+			used:    resources("4", "1"),
+		},
+		{
+			name:       "crossing min",
+			percent:    90,
+			oldUsed:    resources("4", "1"),
+			used:       resources("5", "3"),
+			wantEvents: []string{"Warning OverMin Usage exceeds min for cpu (5/4), nvidia.com/gpu (3/2); the pods above min may be preempted"},
+		},
+		{
+			name:       "crossing min without usage reported",
+			percent:    90,
+			used:       resources("5", "0"),
+			wantEvents: []string{"Warning OverMin Usage exceeds min for cpu (5/4); the pods above min may be preempted"},
+		},
+		{
+			name:    "already over min",
+			percent: 90,
+			oldUsed: resources("5", "0"),
+			used:    resources("6", "0"),
+		},
+		{
+			name:    "back within min",
+			percent: 90,
+			oldUsed: resources("6", "0"),
+			used:    resources("3", "0"),
+		},
+		{
+			name:    "approaching max",
+			percent: 90,
+			oldUsed: resources("8", "0"),
+			used:    resources("9", "4"),
+			wantEvents: []string{
+				"Warning OverMin Usage exceeds min for nvidia.com/gpu (4/2); the pods above min may be preempted",
+				"Warning NearMax Usage reached 90% of max for cpu (9/10), nvidia.com/gpu (4/4); the pods beyond max won't be scheduled",
+			},
+		},
+		{
+			name:    "already approaching max",
+			percent: 90,
+			oldUsed: resources("9", "0"),
+			used:    resources("10", "0"),
+		},
+		{
+			name:    "max warning disabled",
+			oldUsed: resources("8", "0"),
+			used:    resources("10", "0"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			eq := &v1alpha1.ElasticQuota{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "eq1"},
+				Spec: v1alpha1.ElasticQuotaSpec{
+					Min: resources("4", "2"),
+					Max: resources("10", "4"),
+				},
+				Status: v1alpha1.ElasticQuotaStatus{Used: c.oldUsed},
+			}
+			recorder := record.NewFakeRecorder(3)
+			controller := &ElasticQuotaReconciler{recorder: recorder, MaxUsageWarningPercent: c.percent}
+			controller.recordUsageEvents(eq, c.used)
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			if !reflect.DeepEqual(events, c.wantEvents) {
+				t.Errorf("want events %q, got %q", c.wantEvents, events)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	schedv1alpha1 "github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// SchedulerCacheFlushed is the reason of the event recorded when the SchedulerCacheFinalizer
+// of a PodGroup or an ElasticQuota being deleted is removed.
+const SchedulerCacheFlushed = "SchedulerCacheFlushed"
+
+// reconcileFinalizer adds the SchedulerCacheFinalizer to the object if it is not being deleted.
+// Otherwise, the update setting its deletion timestamp already notified the scheduler plugins,
+// which flush the state cached for the object, so the finalizer is removed to let the deletion complete.
+// It returns whether the object is being deleted.
+func reconcileFinalizer(ctx context.Context, c client.Client, recorder record.EventRecorder, obj client.Object) (bool, error) {
+	if obj.GetDeletionTimestamp().IsZero() {
+		if controllerutil.AddFinalizer(obj, schedv1alpha1.SchedulerCacheFinalizer) {
+			return false, c.Update(ctx, obj)
+		}
+		return false, nil
+	}
+	if controllerutil.RemoveFinalizer(obj, schedv1alpha1.SchedulerCacheFinalizer) {
+		if err := c.Update(ctx, obj); err != nil {
+			return true, err
+		}
+		recorder.Event(obj, v1.EventTypeNormal, SchedulerCacheFlushed, "Scheduler plugins notified of the deletion")
+	}
+	return true, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	testutil "github.com/amiraBenamer20/scheduler-plugins/test/integration"
+)
+
+func TestReconcileFinalizer(t *testing.T) {
+	ctx := context.TODO()
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = v1alpha1.AddToScheme(s)
+
+	cases := []struct {
+		name string
+		obj  client.Object
+	}{
+		{
+			name: "pod group",
+			obj:  makePG("pg1", 2, "", nil),
+		},
+		{
+			name: "elastic quota",
+			obj:  testutil.MakeEQ("ns1", "eq1").Obj(),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(s).WithObjects(c.obj).Build()
+			recorder := record.NewFakeRecorder(1)
+			key := types.NamespacedName{Namespace: c.obj.GetNamespace(), Name: c.obj.GetName()}
+
+			obj := c.obj.DeepCopyObject().(client.Object)
+			if deleting, err := reconcileFinalizer(ctx, cl, recorder, obj); err != nil || deleting {
+				t.Fatalf("expected the finalizer to be added, got deleting %v, err %v", deleting, err)
+			}
+			if err := cl.Get(ctx, key, obj); err != nil {
+				t.Fatal(err)
+			}
+			if !controllerutil.ContainsFinalizer(obj, v1alpha1.SchedulerCacheFinalizer) {
+				t.Fatalf("expected finalizer %v, got %v", v1alpha1.SchedulerCacheFinalizer, obj.GetFinalizers())
+			}
+
+			// The finalizer holds the object being deleted until the next reconciliation.
+			if err := cl.Delete(ctx, obj); err != nil {
+				t.Fatal(err)
+			}
+			if err := cl.Get(ctx, key, obj); err != nil {
+				t.Fatalf("expected the object held by the finalizer, got %v", err)
+			}
+			if deleting, err := reconcileFinalizer(ctx, cl, recorder, obj); err != nil || !deleting {
+				t.Fatalf("expected the finalizer to be removed, got deleting %v, err %v", deleting, err)
+			}
+			if err := cl.Get(ctx, key, obj); !errors.IsNotFound(err) {
+				t.Errorf("expected the object to be deleted, got %v", err)
+			}
+			select {
+			case event := <-recorder.Events:
+				if !strings.Contains(event, SchedulerCacheFlushed) {
+					t.Errorf("unexpected event %q", event)
+				}
+			default:
+				t.Errorf("expected a %v event", SchedulerCacheFlushed)
+			}
+		})
+	}
+}
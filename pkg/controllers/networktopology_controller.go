@@ -0,0 +1,264 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/networkprobe"
+)
+
+// networkTopologyKeys are the topology keys whose costs are derived from the node-to-node latencies.
+var networkTopologyKeys = []ntv1alpha1.TopologyKey{ntv1alpha1.NetworkTopologyRegion, ntv1alpha1.NetworkTopologyZone}
+
+// NetworkTopologyReconciler maintains the NetperfCosts weights of a NetworkTopology from the node-to-node latencies
+// measured by the probes of a DaemonSet it deploys, so that the network-aware plugins get fresh costs without
+// hand-written weights. The other weights of the NetworkTopology are left untouched.
+type NetworkTopologyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Namespace and Name of the NetworkTopology, the probe DaemonSet is deployed in the same namespace.
+	Namespace string
+	Name      string
+	// ProbeImage is the image of the probes, running the controller in probe mode.
+	ProbeImage string
+	// ProbeServiceAccount is the service account of the probes, allowed to list and patch the probe pods.
+	ProbeServiceAccount string
+	// ProbePort is the host port the probes listen on and connect to.
+	ProbePort int
+	// ProbeInterval is the period of the measurements of the probes, and of the reconciliation of the weights.
+	ProbeInterval time.Duration
+	// RateLimiter are the settings of the rate limiter of the reconciliation queue.
+	RateLimiter RateLimiterOptions
+}
+
+// +kubebuilder:rbac:groups=networktopology.diktyo.x-k8s.io,resources=networktopologies,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+func (r *NetworkTopologyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	if err := r.ensureProbes(ctx); err != nil {
+		log.Error(err, "Unable to deploy the network probes")
+		return ctrl.Result{}, err
+	}
+
+	nodes := &v1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return ctrl.Result{}, err
+	}
+	pods := &v1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(r.Namespace), client.MatchingLabels{networkprobe.AppLabel: networkprobe.AppName}); err != nil {
+		return ctrl.Result{}, err
+	}
+	latencies := make(map[string]networkprobe.Latencies)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		nodeLatencies, err := networkprobe.ParseLatencies(pod)
+		if err != nil {
+			log.Error(err, "Ignoring the latencies of the network probe", "pod", pod.Name)
+			continue
+		}
+		if pod.Spec.NodeName != "" && nodeLatencies != nil {
+			latencies[pod.Spec.NodeName] = nodeLatencies
+		}
+	}
+	weights := netperfWeights(nodes.Items, latencies)
+
+	nt := &ntv1alpha1.NetworkTopology{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: r.Name}, nt); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		nt = &ntv1alpha1.NetworkTopology{
+			ObjectMeta: metav1.ObjectMeta{Namespace: r.Namespace, Name: r.Name},
+			Spec:       ntv1alpha1.NetworkTopologySpec{Weights: ntv1alpha1.WeightList{weights}},
+			Status:     ntv1alpha1.NetworkTopologyStatus{NodeCount: int64(len(nodes.Items)), WeightCalculationTime: metav1.Now()},
+		}
+		log.V(3).Info("creating the network topology", "namespace", r.Namespace, "name", r.Name)
+		return ctrl.Result{RequeueAfter: r.ProbeInterval}, r.Create(ctx, nt)
+	}
+
+	newNT := nt.DeepCopy()
+	setWeights(newNT, weights)
+	newNT.Status.NodeCount = int64(len(nodes.Items))
+	if apiequality.Semantic.DeepEqual(nt.Spec, newNT.Spec) && nt.Status.NodeCount == newNT.Status.NodeCount {
+		return ctrl.Result{RequeueAfter: r.ProbeInterval}, nil
+	}
+	newNT.Status.WeightCalculationTime = metav1.Now()
+	log.V(3).Info("updating the network topology", "namespace", r.Namespace, "name", r.Name)
+	return ctrl.Result{RequeueAfter: r.ProbeInterval}, r.Update(ctx, newNT)
+}
+
+// ensureProbes creates the probe DaemonSet, or updates it once its image or arguments changed.
+func (r *NetworkTopologyReconciler) ensureProbes(ctx context.Context) error {
+	want := r.probeDaemonSet()
+	ds := &appsv1.DaemonSet{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(want), ds); err != nil {
+		if apierrs.IsNotFound(err) {
+			return r.Create(ctx, want)
+		}
+		return err
+	}
+	containers := ds.Spec.Template.Spec.Containers
+	wantContainer := want.Spec.Template.Spec.Containers[0]
+	if len(containers) == 1 && containers[0].Image == wantContainer.Image && reflect.DeepEqual(containers[0].Args, wantContainer.Args) {
+		return nil
+	}
+	ds.Spec.Template = want.Spec.Template
+	return r.Update(ctx, ds)
+}
+
+// probeDaemonSet returns the DaemonSet of the probes, on the host network of every node so that they measure the
+// latency between the nodes.
+func (r *NetworkTopologyReconciler) probeDaemonSet() *appsv1.DaemonSet {
+	labels := map[string]string{networkprobe.AppLabel: networkprobe.AppName}
+	fieldEnv := func(name, fieldPath string) v1.EnvVar {
+		return v1.EnvVar{Name: name, ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: fieldPath}}}
+	}
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: r.Namespace, Name: networkprobe.AppName, Labels: labels},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					ServiceAccountName: r.ProbeServiceAccount,
+					HostNetwork:        true,
+					Tolerations:        []v1.Toleration{{Operator: v1.TolerationOpExists}},
+					Containers: []v1.Container{{
+						Name:  "probe",
+						Image: r.ProbeImage,
+						Args: []string{
+							"--networkProbe",
+							fmt.Sprintf("--networkProbePort=%d", r.ProbePort),
+							fmt.Sprintf("--networkProbeInterval=%s", r.ProbeInterval),
+						},
+						Env: []v1.EnvVar{
+							fieldEnv("NODE_NAME", "spec.nodeName"),
+							fieldEnv("POD_NAME", "metadata.name"),
+							fieldEnv("POD_NAMESPACE", "metadata.namespace"),
+						},
+						Ports: []v1.ContainerPort{{ContainerPort: int32(r.ProbePort), Protocol: v1.ProtocolTCP}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// netperfWeights returns the NetperfCosts weights of the latencies between the nodes: the cost from a region or zone
+// to another is their mean latency in milliseconds, at least 1. The lists are sorted as the plugins expect them.
+func netperfWeights(nodes []v1.Node, latencies map[string]networkprobe.Latencies) ntv1alpha1.WeightInfo {
+	weights := ntv1alpha1.WeightInfo{Name: ntv1alpha1.NetworkTopologyNetperfCosts}
+	for _, key := range networkTopologyKeys {
+		domains := make(map[string]string, len(nodes))
+		for _, node := range nodes {
+			domains[node.Name] = node.Labels[string(key)]
+		}
+		samples := make(map[string]map[string][]float64)
+		for origin, nodeLatencies := range latencies {
+			originDomain := domains[origin]
+			if originDomain == "" {
+				continue
+			}
+			for destination, latency := range nodeLatencies {
+				destinationDomain := domains[destination]
+				if destinationDomain == "" || destinationDomain == originDomain {
+					continue
+				}
+				if samples[originDomain] == nil {
+					samples[originDomain] = make(map[string][]float64)
+				}
+				samples[originDomain][destinationDomain] = append(samples[originDomain][destinationDomain], latency)
+			}
+		}
+
+		var origins ntv1alpha1.OriginList
+		for origin, destinations := range samples {
+			var costs ntv1alpha1.CostList
+			for destination, latencies := range destinations {
+				var sum float64
+				for _, latency := range latencies {
+					sum += latency
+				}
+				cost := max(int64(math.Round(sum/float64(len(latencies)))), 1)
+				costs = append(costs, ntv1alpha1.CostInfo{Destination: destination, NetworkCost: cost})
+			}
+			sort.Sort(networkcostawareutil.ByDestination(costs))
+			origins = append(origins, ntv1alpha1.OriginInfo{Origin: origin, CostList: costs})
+		}
+		sort.Sort(networkcostawareutil.ByOrigin(origins))
+		weights.TopologyList = append(weights.TopologyList, ntv1alpha1.TopologyInfo{TopologyKey: key, OriginList: origins})
+	}
+	sort.Sort(networkcostawareutil.ByTopologyKey(weights.TopologyList))
+	return weights
+}
+
+// setWeights replaces the weights of the NetworkTopology with the same name, or adds them.
+func setWeights(nt *ntv1alpha1.NetworkTopology, weights ntv1alpha1.WeightInfo) {
+	for i := range nt.Spec.Weights {
+		if nt.Spec.Weights[i].Name == weights.Name {
+			nt.Spec.Weights[i] = weights
+			return
+		}
+	}
+	nt.Spec.Weights = append(nt.Spec.Weights, weights)
+}
+
+// SetupWithManager sets up the controller with the Manager. The probe pods and the nodes, whose topology labels
+// may change, requeue the NetworkTopology.
+func (r *NetworkTopologyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toNetworkTopology := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []ctrl.Request {
+		return []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: r.Namespace, Name: r.Name}}}
+	})
+	isProbe := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == r.Namespace && obj.GetLabels()[networkprobe.AppLabel] == networkprobe.AppName
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("networktopology").
+		For(&ntv1alpha1.NetworkTopology{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetNamespace() == r.Namespace && obj.GetName() == r.Name
+		}))).
+		Watches(&v1.Pod{}, toNetworkTopology, builder.WithPredicates(isProbe)).
+		Watches(&v1.Node{}, toNetworkTopology).
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter.rateLimiter()}).
+		Complete(r)
+}
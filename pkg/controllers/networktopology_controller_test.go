@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/networkprobe"
+)
+
+func makeTopologyNode(name, region, zone string) *v1.Node {
+	return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+		v1.LabelTopologyRegion: region,
+		v1.LabelTopologyZone:   zone,
+	}}}
+}
+
+func makeNetworkProbePod(nodeName, latencies string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "probe-" + nodeName,
+			Namespace:   "kube-system",
+			Labels:      map[string]string{networkprobe.AppLabel: networkprobe.AppName},
+			Annotations: map[string]string{networkprobe.LatenciesAnnotation: latencies},
+		},
+		Spec: v1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func makeCosts(origin string, destinationCosts ...ntv1alpha1.CostInfo) ntv1alpha1.OriginInfo {
+	return ntv1alpha1.OriginInfo{Origin: origin, CostList: destinationCosts}
+}
+
+func TestNetperfWeights(t *testing.T) {
+	nodes := []v1.Node{
+		*makeTopologyNode("n1", "us-east", "z1"),
+		*makeTopologyNode("n2", "us-east", "z1"),
+		*makeTopologyNode("n3", "us-east", "z2"),
+		*makeTopologyNode("n4", "us-west", "z3"),
+		*makeTopologyNode("n5", "", ""),
+	}
+	latencies := map[string]networkprobe.Latencies{
+		"n1": {"n2": 0.1, "n3": 0.4, "n4": 30, "n5": 5},
+		"n2": {"n1": 0.1, "n3": 1.6, "n4": 34},
+		"n4": {"n1": 31},
+	}
+	want := ntv1alpha1.WeightInfo{
+		Name: ntv1alpha1.NetworkTopologyNetperfCosts,
+		TopologyList: ntv1alpha1.TopologyList{
+			{
+				TopologyKey: ntv1alpha1.NetworkTopologyRegion,
+				OriginList: ntv1alpha1.OriginList{
+					makeCosts("us-east", ntv1alpha1.CostInfo{Destination: "us-west", NetworkCost: 32}),
+					makeCosts("us-west", ntv1alpha1.CostInfo{Destination: "us-east", NetworkCost: 31}),
+				},
+			},
+			{
+				TopologyKey: ntv1alpha1.NetworkTopologyZone,
+				OriginList: ntv1alpha1.OriginList{
+					makeCosts("z1",
+						ntv1alpha1.CostInfo{Destination: "z2", NetworkCost: 1},
+						ntv1alpha1.CostInfo{Destination: "z3", NetworkCost: 32}),
+					makeCosts("z3", ntv1alpha1.CostInfo{Destination: "z1", NetworkCost: 31}),
+				},
+			},
+		},
+	}
+	if got := netperfWeights(nodes, latencies); !apiequality.Semantic.DeepEqual(got, want) {
+		t.Errorf("expected weights %+v, got %+v", want, got)
+	}
+}
+
+func TestNetworkTopologyController_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(s))
+	utilruntime.Must(ntv1alpha1.AddToScheme(s))
+
+	userDefined := ntv1alpha1.WeightInfo{Name: "UserDefined"}
+	client := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		makeTopologyNode("n1", "us-east", "z1"),
+		makeTopologyNode("n2", "us-west", "z2"),
+		makeNetworkProbePod("n1", `{"n2":40}`),
+		makeNetworkProbePod("n2", `{"n1":42}`),
+		&ntv1alpha1.NetworkTopology{
+			ObjectMeta: metav1.ObjectMeta{Name: "net-topology", Namespace: "kube-system"},
+			Spec:       ntv1alpha1.NetworkTopologySpec{Weights: ntv1alpha1.WeightList{userDefined}},
+		},
+	).Build()
+	r := &NetworkTopologyReconciler{
+		Client:              client,
+		Scheme:              s,
+		Namespace:           "kube-system",
+		Name:                "net-topology",
+		ProbeImage:          "controller:latest",
+		ProbeServiceAccount: "network-probe",
+		ProbePort:           8091,
+		ProbeInterval:       30 * time.Second,
+	}
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "kube-system", Name: "net-topology"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter != r.ProbeInterval {
+		t.Errorf("expected a requeue after %v, got %v", r.ProbeInterval, result.RequeueAfter)
+	}
+
+	ds := &appsv1.DaemonSet{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: networkprobe.AppName}, ds); err != nil {
+		t.Fatalf("expected the probe DaemonSet to be deployed: %v", err)
+	}
+	if got := ds.Spec.Template.Spec.Containers[0].Image; got != r.ProbeImage {
+		t.Errorf("expected the probe image %v, got %v", r.ProbeImage, got)
+	}
+
+	nt := &ntv1alpha1.NetworkTopology{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: "net-topology"}, nt); err != nil {
+		t.Fatal(err)
+	}
+	if len(nt.Spec.Weights) != 2 || nt.Spec.Weights[0].Name != userDefined.Name || nt.Spec.Weights[1].Name != ntv1alpha1.NetworkTopologyNetperfCosts {
+		t.Fatalf("expected the user-defined and NetperfCosts weights, got %+v", nt.Spec.Weights)
+	}
+	regions := nt.Spec.Weights[1].TopologyList[0]
+	if regions.TopologyKey != ntv1alpha1.NetworkTopologyRegion || len(regions.OriginList) != 2 || regions.OriginList[0].CostList[0].NetworkCost != 40 {
+		t.Errorf("expected the region costs measured by the probes, got %+v", regions)
+	}
+	if nt.Status.NodeCount != 2 || nt.Status.WeightCalculationTime.IsZero() {
+		t.Errorf("expected the status of 2 nodes with a weight calculation time, got %+v", nt.Status)
+	}
+}
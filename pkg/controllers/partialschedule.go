@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	schedv1alpha1 "github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// reconcilePartialSchedule evicts the scheduled members of a group stuck with fewer than minMember members
+// scheduled for longer than the PartialScheduleTimeout, so that the whole group retries at once rather than
+// holding the resources of its scheduled members. It returns when to check the group again, if at all.
+func (r *PodGroupReconciler) reconcilePartialSchedule(ctx context.Context, pg *schedv1alpha1.PodGroup, pods []v1.Pod, now time.Time) (ctrl.Result, error) {
+	if r.PartialScheduleTimeout <= 0 || pg.Status.FullyScheduledTime != nil {
+		return ctrl.Result{}, nil
+	}
+
+	var scheduled []*v1.Pod
+	for i := range pods {
+		if pods[i].Spec.NodeName != "" && pods[i].Status.Phase != v1.PodFailed {
+			scheduled = append(scheduled, &pods[i])
+		}
+	}
+	if len(scheduled) == 0 || len(scheduled) >= int(pg.Spec.MinMember) {
+		return ctrl.Result{}, nil
+	}
+
+	if remaining := partiallyScheduledSince(scheduled).Add(r.PartialScheduleTimeout).Sub(now); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	log.FromContext(ctx).Info("Evicting the members of the partially scheduled group",
+		"scheduled", len(scheduled), "minMember", pg.Spec.MinMember, "timeout", r.PartialScheduleTimeout)
+	r.recorder.Eventf(pg, v1.EventTypeWarning, "PartialScheduleTimeout",
+		"%d members scheduled for longer than %v, minMember is %d; evicting them", len(scheduled), r.PartialScheduleTimeout, pg.Spec.MinMember)
+	for _, pod := range scheduled {
+		if pod.DeletionTimestamp != nil || (pod.Status.Phase != v1.PodPending && pod.Status.Phase != v1.PodRunning) {
+			continue
+		}
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name},
+		}
+		// An eviction refused, e.g. by a PodDisruptionBudget, fails the reconciliation to retry it later.
+		if err := r.SubResource("eviction").Create(ctx, pod, eviction); err != nil && !apierrs.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// partiallyScheduledSince returns the earliest time one of the pods was scheduled, falling back on their
+// creation time when they have no PodScheduled condition.
+func partiallyScheduledSince(pods []*v1.Pod) time.Time {
+	var since time.Time
+	for _, pod := range pods {
+		t := pod.CreationTimestamp.Time
+		for _, c := range pod.Status.Conditions {
+			if c.Type == v1.PodScheduled && c.Status == v1.ConditionTrue && !c.LastTransitionTime.IsZero() {
+				t = c.LastTransitionTime.Time
+			}
+		}
+		if since.IsZero() || t.Before(since) {
+			since = t
+		}
+	}
+	return since
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestReconcilePartialSchedule(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now().Truncate(time.Second)
+	scheduledAt := metav1.NewTime(now.Add(-time.Minute))
+	scheduled := func(name string) *v1.Pod {
+		p := st.MakePod().Namespace("default").Name(name).Node("node-1").Phase(v1.PodRunning).Obj()
+		p.Status.Conditions = []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: scheduledAt}}
+		return p
+	}
+	pending := st.MakePod().Namespace("default").Name("pod-3").Phase(v1.PodPending).Obj()
+
+	cases := []struct {
+		name           string
+		timeout        time.Duration
+		pods           []*v1.Pod
+		fullyScheduled bool
+		wantEvicted    []string
+		wantRequeue    time.Duration
+	}{
+		{
+			name:    "disabled",
+			pods:    []*v1.Pod{scheduled("pod-1"), scheduled("pod-2"), pending},
+			timeout: 0,
+		},
+		{
+			name:        "partially scheduled within the timeout",
+			pods:        []*v1.Pod{scheduled("pod-1"), scheduled("pod-2"), pending},
+			timeout:     3 * time.Minute,
+			wantRequeue: 2 * time.Minute,
+		},
+		{
+			name:        "partially scheduled past the timeout",
+			pods:        []*v1.Pod{scheduled("pod-1"), scheduled("pod-2"), pending},
+			timeout:     30 * time.Second,
+			wantEvicted: []string{"pod-1", "pod-2"},
+		},
+		{
+			name:    "no member scheduled",
+			pods:    []*v1.Pod{pending},
+			timeout: 30 * time.Second,
+		},
+		{
+			name:    "fully scheduled",
+			pods:    []*v1.Pod{scheduled("pod-1"), scheduled("pod-2"), scheduled("pod-3")},
+			timeout: 30 * time.Second,
+		},
+		{
+			name:           "fully scheduled once",
+			pods:           []*v1.Pod{scheduled("pod-1"), scheduled("pod-2"), pending},
+			fullyScheduled: true,
+			timeout:        30 * time.Second,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(s)
+			_ = v1alpha1.AddToScheme(s)
+			pg := makePG("pg", 3, v1alpha1.PodGroupScheduling, nil)
+			if c.fullyScheduled {
+				pg.Status.FullyScheduledTime = &scheduledAt
+			}
+			objs := []client.Object{pg}
+			pods := make([]v1.Pod, 0, len(c.pods))
+			for _, p := range c.pods {
+				objs = append(objs, p)
+				pods = append(pods, *p)
+			}
+			kClient := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+			controller := &PodGroupReconciler{
+				Client:                 kClient,
+				Scheme:                 s,
+				recorder:               record.NewFakeRecorder(3),
+				PartialScheduleTimeout: c.timeout,
+			}
+
+			result, err := controller.reconcilePartialSchedule(ctx, pg, pods, now)
+			if err != nil {
+				t.Fatalf("reconcile: (%v)", err)
+			}
+			if result.RequeueAfter != c.wantRequeue {
+				t.Errorf("want requeue after %v, got %v", c.wantRequeue, result.RequeueAfter)
+			}
+			evicted := map[string]bool{}
+			for _, name := range c.wantEvicted {
+				evicted[name] = true
+			}
+			for _, p := range c.pods {
+				err := kClient.Get(ctx, client.ObjectKeyFromObject(p), &v1.Pod{})
+				if gone := apierrs.IsNotFound(err); gone != evicted[p.Name] {
+					t.Errorf("pod %s: want evicted %v, got %v (%v)", p.Name, evicted[p.Name], gone, err)
+				}
+			}
+		})
+	}
+}
@@ -25,7 +25,11 @@ import (
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -56,13 +60,19 @@ type PodGroupReconciler struct {
 	recorder record.EventRecorder
 
 	client.Client
-	Scheme  *runtime.Scheme
-	Workers int
+	Scheme      *runtime.Scheme
+	Workers     int
+	RateLimiter RateLimiterOptions
+	// PartialScheduleTimeout is how long a group may keep fewer than minMember members scheduled before
+	// its scheduled members are evicted for the whole group to retry. Zero disables the evictions.
+	PartialScheduleTimeout time.Duration
 }
 
 // +kubebuilder:rbac:groups=scheduling.x-k8s.io,resources=podgroups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=scheduling.x-k8s.io,resources=podgroups/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=scheduling.x-k8s.io,resources=podgroups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -86,6 +96,18 @@ func (r *PodGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 
+	if deleting, err := reconcileFinalizer(ctx, r.Client, r.recorder, pg); err != nil || deleting {
+		if err != nil {
+			log.Error(err, "Reconcile finalizer for group failed")
+		}
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileDisruptionBudget(ctx, pg); err != nil {
+		log.Error(err, "Reconcile disruption budget for group failed")
+		return ctrl.Result{}, err
+	}
+
 	if pg.Status.Phase == schedv1alpha1.PodGroupFinished ||
 		pg.Status.Phase == schedv1alpha1.PodGroupFailed {
 		return ctrl.Result{}, nil
@@ -108,7 +130,13 @@ func (r *PodGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		log.Error(err, "List pods for group failed")
 		return ctrl.Result{}, err
 	}
-	pods := podList.Items
+	// Only the pods of the namespace of the group and of its member namespaces are its members.
+	pods := make([]v1.Pod, 0, len(podList.Items))
+	for i := range podList.Items {
+		if util.IsPodGroupMember(&podList.Items[i], pg) {
+			pods = append(pods, podList.Items[i])
+		}
+	}
 
 	pgCopy := pg.DeepCopy()
 	switch pgCopy.Status.Phase {
@@ -134,16 +162,17 @@ func (r *PodGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupRunning
 		}
 		// Final state of pod group
-		if pgCopy.Status.Failed != 0 &&
-			pgCopy.Status.Failed+pgCopy.Status.Running+pgCopy.Status.Succeeded >= pg.Spec.MinMember {
-			pgCopy.Status.Phase = schedv1alpha1.PodGroupFailed
-		}
-		if pgCopy.Status.Succeeded >= pg.Spec.MinMember {
-			pgCopy.Status.Phase = schedv1alpha1.PodGroupFinished
+		if phase, final := getFinalPhase(pg, pods, pgCopy.Status.Running, pgCopy.Status.Succeeded, pgCopy.Status.Failed); final {
+			pgCopy.Status.Phase = phase
 		}
 	}
 
-	return r.patchPodGroup(ctx, pg, pgCopy)
+	updateSchedulingStatus(pgCopy, pods, metav1.Now())
+
+	if result, err := r.patchPodGroup(ctx, pg, pgCopy); err != nil || pgCopy.Status.Phase != schedv1alpha1.PodGroupScheduling {
+		return result, err
+	}
+	return r.reconcilePartialSchedule(ctx, pgCopy, pods, time.Now())
 }
 
 func (r *PodGroupReconciler) patchPodGroup(ctx context.Context, old, new *schedv1alpha1.PodGroup) (ctrl.Result, error) {
@@ -155,6 +184,58 @@ func (r *PodGroupReconciler) patchPodGroup(ctx context.Context, old, new *schedv
 	return ctrl.Result{}, err
 }
 
+// reconcileDisruptionBudget creates or updates the PodDisruptionBudget covering the pods of the group
+// when the group sets a maxUnavailable, and deletes it otherwise. A PodDisruptionBudget of the same
+// name which is not controlled by the group is left untouched.
+func (r *PodGroupReconciler) reconcileDisruptionBudget(ctx context.Context, pg *schedv1alpha1.PodGroup) error {
+	pdb := &policyv1.PodDisruptionBudget{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: pg.Namespace, Name: pg.Name}, pdb)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(pdb, pg) {
+		if pg.Spec.MaxUnavailable != nil {
+			r.recorder.Eventf(pg, v1.EventTypeWarning, "DisruptionBudgetConflict",
+				"PodDisruptionBudget %s/%s exists and is not controlled by the pod group", pdb.Namespace, pdb.Name)
+		}
+		return nil
+	}
+
+	if pg.Spec.MaxUnavailable == nil {
+		if !exists {
+			return nil
+		}
+		return client.IgnoreNotFound(r.Delete(ctx, pdb))
+	}
+
+	spec := policyv1.PodDisruptionBudgetSpec{
+		MaxUnavailable: pg.Spec.MaxUnavailable,
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{schedv1alpha1.PodGroupLabel: pg.Name},
+		},
+	}
+	if !exists {
+		pdb = &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: pg.Namespace,
+				Name:      pg.Name,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(pg, schedv1alpha1.SchemeGroupVersion.WithKind("PodGroup")),
+				},
+			},
+			Spec: spec,
+		}
+		return r.Create(ctx, pdb)
+	}
+	if apiequality.Semantic.DeepEqual(pdb.Spec, spec) {
+		return nil
+	}
+	pdbCopy := pdb.DeepCopy()
+	pdbCopy.Spec = spec
+	return r.Patch(ctx, pdbCopy, client.MergeFrom(pdb))
+}
+
 func getCurrentPodStats(pods []v1.Pod) (int32, int32, int32) {
 	if len(pods) == 0 {
 		return 0, 0, 0
@@ -178,6 +259,115 @@ func getCurrentPodStats(pods []v1.Pod) (int32, int32, int32) {
 	return running, succeeded, failed
 }
 
+// updateSchedulingStatus fills the number of pending members, the times the group started and completed its
+// scheduling, and the Scheduled condition explaining why fewer than minMember members are scheduled, if so.
+func updateSchedulingStatus(pg *schedv1alpha1.PodGroup, pods []v1.Pod, now metav1.Time) {
+	var scheduled int32
+	var unschedulable []string
+	pg.Status.Pending = 0
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == v1.PodPending {
+			pg.Status.Pending++
+		}
+		if pod.Spec.NodeName != "" {
+			if pod.Status.Phase != v1.PodFailed {
+				scheduled++
+			}
+			continue
+		}
+		for _, c := range pod.Status.Conditions {
+			if c.Type == v1.PodScheduled && c.Status == v1.ConditionFalse && c.Reason == v1.PodReasonUnschedulable {
+				unschedulable = append(unschedulable, fmt.Sprintf("%s: %s", pod.Name, c.Message))
+			}
+		}
+	}
+
+	if len(pods) >= int(pg.Spec.MinMember) && pg.Status.ScheduleStartTime.IsZero() {
+		pg.Status.ScheduleStartTime = now
+	}
+
+	condition := metav1.Condition{
+		Type:               schedv1alpha1.PodGroupScheduled,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: pg.Generation,
+	}
+	switch {
+	case scheduled >= pg.Spec.MinMember:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = schedv1alpha1.PodGroupReasonScheduled
+		condition.Message = fmt.Sprintf("%d members scheduled, minMember is %d", scheduled, pg.Spec.MinMember)
+		if pg.Status.FullyScheduledTime == nil {
+			pg.Status.FullyScheduledTime = now.DeepCopy()
+			if !pg.Status.ScheduleStartTime.IsZero() {
+				pg.Status.TimeToFullSchedule = &metav1.Duration{Duration: now.Sub(pg.Status.ScheduleStartTime.Time)}
+			}
+		}
+	case len(pods) < int(pg.Spec.MinMember):
+		condition.Reason = schedv1alpha1.PodGroupReasonInsufficientMembers
+		condition.Message = fmt.Sprintf("%d members created, minMember is %d", len(pods), pg.Spec.MinMember)
+	case len(unschedulable) > 0:
+		sort.Strings(unschedulable)
+		condition.Reason = schedv1alpha1.PodGroupReasonUnschedulable
+		condition.Message = fmt.Sprintf("%d members scheduled, minMember is %d; %d members unschedulable, e.g. %s",
+			scheduled, pg.Spec.MinMember, len(unschedulable), unschedulable[0])
+	default:
+		condition.Reason = schedv1alpha1.PodGroupReasonWaiting
+		condition.Message = fmt.Sprintf("%d members scheduled, minMember is %d", scheduled, pg.Spec.MinMember)
+	}
+	meta.SetStatusCondition(&pg.Status.Conditions, condition)
+}
+
+// getFinalPhase returns the final phase of the pod group, Finished or Failed, from the phases of its
+// members per its success policy, and false if the pod group is not completed yet.
+func getFinalPhase(pg *schedv1alpha1.PodGroup, pods []v1.Pod, running, succeeded, failed int32) (schedv1alpha1.PodGroupPhase, bool) {
+	mode := schedv1alpha1.SuccessPolicyMinMember
+	if pg.Spec.SuccessPolicy != nil {
+		mode = pg.Spec.SuccessPolicy.Mode
+	}
+	switch mode {
+	case schedv1alpha1.SuccessPolicyAllSucceeded:
+		if failed != 0 {
+			return schedv1alpha1.PodGroupFailed, true
+		}
+		if succeeded == int32(len(pods)) {
+			return schedv1alpha1.PodGroupFinished, true
+		}
+	case schedv1alpha1.SuccessPolicyLeaderSucceeded:
+		for i := range pods {
+			if pods[i].Labels[schedv1alpha1.PodGroupLeaderLabel] != "true" {
+				continue
+			}
+			switch pods[i].Status.Phase {
+			case v1.PodSucceeded:
+				return schedv1alpha1.PodGroupFinished, true
+			case v1.PodFailed:
+				return schedv1alpha1.PodGroupFailed, true
+			}
+		}
+	case schedv1alpha1.SuccessPolicyMinSucceeded:
+		minSucceeded := pg.Spec.MinMember
+		if pg.Spec.SuccessPolicy.MinSucceeded != nil {
+			minSucceeded = *pg.Spec.SuccessPolicy.MinSucceeded
+		}
+		if succeeded >= minSucceeded {
+			return schedv1alpha1.PodGroupFinished, true
+		}
+		// Members may not be created yet: only failures make the pod group fail.
+		if failed > 0 && int32(len(pods))-failed < minSucceeded {
+			return schedv1alpha1.PodGroupFailed, true
+		}
+	default:
+		if succeeded >= pg.Spec.MinMember {
+			return schedv1alpha1.PodGroupFinished, true
+		}
+		if failed != 0 && failed+running+succeeded >= pg.Spec.MinMember {
+			return schedv1alpha1.PodGroupFailed, true
+		}
+	}
+	return "", false
+}
+
 func fillOccupiedObj(pg *schedv1alpha1.PodGroup, pod *v1.Pod) {
 	if len(pod.OwnerReferences) == 0 {
 		return
@@ -201,7 +391,8 @@ func (r *PodGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Watches(&v1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.podToPodGroup)).
 		For(&schedv1alpha1.PodGroup{}).
-		WithOptions(controller.Options{MaxConcurrentReconciles: r.Workers}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.Workers, RateLimiter: r.RateLimiter.rateLimiter()}).
 		Complete(r)
 }
 
@@ -219,7 +410,7 @@ func (r *PodGroupReconciler) podToPodGroup(ctx context.Context, obj client.Objec
 
 	return []ctrl.Request{{
 		NamespacedName: types.NamespacedName{
-			Namespace: pod.Namespace,
+			Namespace: util.GetPodGroupNamespace(pod),
 			Name:      pgName,
 		}}}
 }
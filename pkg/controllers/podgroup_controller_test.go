@@ -18,19 +18,25 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2/klogr"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	"k8s.io/utils/ptr"
 
 	// ctrl "sigs.k8s.io/controller-runtime"
 	// "sigs.k8s.io/controller-runtime/pkg/client"
@@ -203,6 +209,115 @@ func Test_Run(t *testing.T) {
 	}
 }
 
+func TestGetFinalPhase(t *testing.T) {
+	pods := func(phases ...v1.PodPhase) []v1.Pod {
+		var ps []v1.Pod
+		for i, phase := range phases {
+			pod := st.MakePod().Namespace("default").Name(fmt.Sprintf("pod%d", i)).Obj()
+			pod.Status.Phase = phase
+			ps = append(ps, *pod)
+		}
+		return ps
+	}
+	withLeader := func(ps []v1.Pod, i int) []v1.Pod {
+		ps[i].Labels = map[string]string{v1alpha1.PodGroupLeaderLabel: "true"}
+		return ps
+	}
+	cases := []struct {
+		name        string
+		policy      *v1alpha1.PodGroupSuccessPolicy
+		pods        []v1.Pod
+		wantPhase   v1alpha1.PodGroupPhase
+		wantIsFinal bool
+	}{
+		{
+			name:        "default policy, minMember succeeded",
+			pods:        pods(v1.PodSucceeded, v1.PodSucceeded, v1.PodRunning),
+			wantPhase:   v1alpha1.PodGroupFinished,
+			wantIsFinal: true,
+		},
+		{
+			name:        "default policy, a member failed",
+			pods:        pods(v1.PodFailed, v1.PodRunning, v1.PodRunning),
+			wantPhase:   v1alpha1.PodGroupFailed,
+			wantIsFinal: true,
+		},
+		{
+			name:   "all succeeded policy, a member still running",
+			policy: &v1alpha1.PodGroupSuccessPolicy{Mode: v1alpha1.SuccessPolicyAllSucceeded},
+			pods:   pods(v1.PodSucceeded, v1.PodSucceeded, v1.PodRunning),
+		},
+		{
+			name:        "all succeeded policy, all members succeeded",
+			policy:      &v1alpha1.PodGroupSuccessPolicy{Mode: v1alpha1.SuccessPolicyAllSucceeded},
+			pods:        pods(v1.PodSucceeded, v1.PodSucceeded, v1.PodSucceeded),
+			wantPhase:   v1alpha1.PodGroupFinished,
+			wantIsFinal: true,
+		},
+		{
+			name:        "all succeeded policy, a member failed",
+			policy:      &v1alpha1.PodGroupSuccessPolicy{Mode: v1alpha1.SuccessPolicyAllSucceeded},
+			pods:        pods(v1.PodSucceeded, v1.PodSucceeded, v1.PodFailed),
+			wantPhase:   v1alpha1.PodGroupFailed,
+			wantIsFinal: true,
+		},
+		{
+			name:        "leader succeeded policy, leader succeeded while workers run",
+			policy:      &v1alpha1.PodGroupSuccessPolicy{Mode: v1alpha1.SuccessPolicyLeaderSucceeded},
+			pods:        withLeader(pods(v1.PodSucceeded, v1.PodRunning, v1.PodRunning), 0),
+			wantPhase:   v1alpha1.PodGroupFinished,
+			wantIsFinal: true,
+		},
+		{
+			name:   "leader succeeded policy, a worker succeeded while the leader runs",
+			policy: &v1alpha1.PodGroupSuccessPolicy{Mode: v1alpha1.SuccessPolicyLeaderSucceeded},
+			pods:   withLeader(pods(v1.PodRunning, v1.PodSucceeded, v1.PodSucceeded), 0),
+		},
+		{
+			name:        "leader succeeded policy, leader failed",
+			policy:      &v1alpha1.PodGroupSuccessPolicy{Mode: v1alpha1.SuccessPolicyLeaderSucceeded},
+			pods:        withLeader(pods(v1.PodRunning, v1.PodFailed, v1.PodRunning), 1),
+			wantPhase:   v1alpha1.PodGroupFailed,
+			wantIsFinal: true,
+		},
+		{
+			name:        "min succeeded policy, N of M succeeded",
+			policy:      &v1alpha1.PodGroupSuccessPolicy{Mode: v1alpha1.SuccessPolicyMinSucceeded, MinSucceeded: ptr.To[int32](2)},
+			pods:        pods(v1.PodSucceeded, v1.PodFailed, v1.PodSucceeded, v1.PodRunning),
+			wantPhase:   v1alpha1.PodGroupFinished,
+			wantIsFinal: true,
+		},
+		{
+			name:   "min succeeded policy, N of M can still succeed",
+			policy: &v1alpha1.PodGroupSuccessPolicy{Mode: v1alpha1.SuccessPolicyMinSucceeded, MinSucceeded: ptr.To[int32](2)},
+			pods:   pods(v1.PodFailed, v1.PodFailed, v1.PodRunning, v1.PodRunning),
+		},
+		{
+			name:        "min succeeded policy, N of M can no longer succeed",
+			policy:      &v1alpha1.PodGroupSuccessPolicy{Mode: v1alpha1.SuccessPolicyMinSucceeded, MinSucceeded: ptr.To[int32](2)},
+			pods:        pods(v1.PodFailed, v1.PodFailed, v1.PodFailed, v1.PodRunning),
+			wantPhase:   v1alpha1.PodGroupFailed,
+			wantIsFinal: true,
+		},
+		{
+			name:   "min succeeded policy, members not all created yet",
+			policy: &v1alpha1.PodGroupSuccessPolicy{Mode: v1alpha1.SuccessPolicyMinSucceeded, MinSucceeded: ptr.To[int32](5)},
+			pods:   pods(v1.PodRunning, v1.PodRunning, v1.PodRunning),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pg := makePG("pg", 2, v1alpha1.PodGroupRunning, nil)
+			pg.Spec.SuccessPolicy = c.policy
+			running, succeeded, failed := getCurrentPodStats(c.pods)
+			phase, final := getFinalPhase(pg, c.pods, running, succeeded, failed)
+			if phase != c.wantPhase || final != c.wantIsFinal {
+				t.Errorf("want %q (final: %v), got %q (final: %v)", c.wantPhase, c.wantIsFinal, phase, final)
+			}
+		})
+	}
+}
+
 func TestFillGroupStatusOccupied(t *testing.T) {
 	ctx := context.TODO()
 	cases := []struct {
@@ -276,6 +391,248 @@ func TestFillGroupStatusOccupied(t *testing.T) {
 	}
 }
 
+func TestReconcileDisruptionBudget(t *testing.T) {
+	ctx := context.TODO()
+	one, two := intstr.FromInt32(1), intstr.FromString("50%")
+	cases := []struct {
+		name               string
+		maxUnavailable     []*intstr.IntOrString
+		foreign            bool
+		wantPDB            bool
+		wantMaxUnavailable *intstr.IntOrString
+	}{
+		{
+			name:           "no maxUnavailable, no PDB",
+			maxUnavailable: []*intstr.IntOrString{nil},
+		},
+		{
+			name:               "maxUnavailable set, PDB created",
+			maxUnavailable:     []*intstr.IntOrString{&one},
+			wantPDB:            true,
+			wantMaxUnavailable: &one,
+		},
+		{
+			name:               "maxUnavailable changed, PDB updated",
+			maxUnavailable:     []*intstr.IntOrString{&one, &two},
+			wantPDB:            true,
+			wantMaxUnavailable: &two,
+		},
+		{
+			name:           "maxUnavailable unset, PDB deleted",
+			maxUnavailable: []*intstr.IntOrString{&one, nil},
+		},
+		{
+			name:               "PDB not controlled by the group left untouched",
+			maxUnavailable:     []*intstr.IntOrString{&two},
+			foreign:            true,
+			wantPDB:            true,
+			wantMaxUnavailable: &one,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller, kClient := setUp(ctx, []string{"pod1", "pod2"}, "pg", v1.PodRunning, 2, v1alpha1.PodGroupRunning, nil, nil)
+			key := types.NamespacedName{Name: "pg", Namespace: metav1.NamespaceDefault}
+			if c.foreign {
+				foreign := &policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+					Spec:       policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &one},
+				}
+				if err := kClient.Create(ctx, foreign); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, maxUnavailable := range c.maxUnavailable {
+				pg := &v1alpha1.PodGroup{}
+				if err := kClient.Get(ctx, key, pg); err != nil {
+					t.Fatal(err)
+				}
+				pg.Spec.MaxUnavailable = maxUnavailable
+				if err := kClient.Update(ctx, pg); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := controller.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+					t.Fatalf("reconcile: (%v)", err)
+				}
+			}
+
+			pdb := &policyv1.PodDisruptionBudget{}
+			err := kClient.Get(ctx, key, pdb)
+			if !c.wantPDB {
+				if !apierrs.IsNotFound(err) {
+					t.Fatalf("want no PodDisruptionBudget, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pdb.Spec.MaxUnavailable.String() != c.wantMaxUnavailable.String() {
+				t.Fatalf("want maxUnavailable %v, got %v", c.wantMaxUnavailable, pdb.Spec.MaxUnavailable)
+			}
+			if c.foreign {
+				return
+			}
+			if got := pdb.Spec.Selector.MatchLabels[v1alpha1.PodGroupLabel]; got != key.Name {
+				t.Fatalf("want selector on pod group %v, got %v", key.Name, got)
+			}
+			if len(pdb.OwnerReferences) != 1 || pdb.OwnerReferences[0].Kind != "PodGroup" || pdb.OwnerReferences[0].Name != key.Name {
+				t.Fatalf("want PodDisruptionBudget controlled by the pod group, got %v", pdb.OwnerReferences)
+			}
+		})
+	}
+}
+
+func TestReconcileMemberNamespaces(t *testing.T) {
+	ctx := context.TODO()
+	cases := []struct {
+		name              string
+		minMember         int32
+		desiredGroupPhase v1alpha1.PodGroupPhase
+	}{
+		{
+			name:              "members of the member namespaces count",
+			minMember:         3,
+			desiredGroupPhase: v1alpha1.PodGroupScheduling,
+		},
+		{
+			name:              "pods of other namespaces do not count",
+			minMember:         4,
+			desiredGroupPhase: v1alpha1.PodGroupPending,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller, kClient := setUp(ctx, []string{"producer-0", "producer-1"}, "pipeline", v1.PodPending, c.minMember, v1alpha1.PodGroupPending, nil, nil)
+			key := types.NamespacedName{Name: "pipeline", Namespace: metav1.NamespaceDefault}
+			pg := &v1alpha1.PodGroup{}
+			if err := kClient.Get(ctx, key, pg); err != nil {
+				t.Fatal(err)
+			}
+			pg.Spec.MemberNamespaces = []string{"consumer"}
+			if err := kClient.Update(ctx, pg); err != nil {
+				t.Fatal(err)
+			}
+
+			consumer := st.MakePod().Namespace("consumer").Name("consumer-0").
+				Label(v1alpha1.PodGroupLabel, "pipeline").Label(v1alpha1.PodGroupNamespaceLabel, metav1.NamespaceDefault).Obj()
+			// Neither a member of a pod group of another namespace nor a pod of a member namespace.
+			unlabeled := st.MakePod().Namespace("consumer").Name("consumer-1").Label(v1alpha1.PodGroupLabel, "pipeline").Obj()
+			foreign := st.MakePod().Namespace("other").Name("other-0").
+				Label(v1alpha1.PodGroupLabel, "pipeline").Label(v1alpha1.PodGroupNamespaceLabel, metav1.NamespaceDefault).Obj()
+			for _, p := range []*v1.Pod{consumer, unlabeled, foreign} {
+				if err := kClient.Create(ctx, p); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			reqs := controller.podToPodGroup(ctx, consumer)
+			if len(reqs) != 1 || reqs[0].NamespacedName != key {
+				t.Fatalf("want pod mapped to pod group %v, got %v", key, reqs)
+			}
+			if _, err := controller.Reconcile(ctx, reqs[0]); err != nil {
+				t.Fatalf("reconcile: (%v)", err)
+			}
+			if err := kClient.Get(ctx, key, pg); err != nil {
+				t.Fatal(err)
+			}
+			if pg.Status.Phase != c.desiredGroupPhase {
+				t.Fatalf("want %v, got %v", c.desiredGroupPhase, pg.Status.Phase)
+			}
+		})
+	}
+}
+
+func TestUpdateSchedulingStatus(t *testing.T) {
+	start := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	now := metav1.NewTime(start.Add(time.Minute))
+	pod := func(name, nodeName string, phase v1.PodPhase, conditions ...v1.PodCondition) v1.Pod {
+		p := st.MakePod().Namespace("default").Name(name).Node(nodeName).Obj()
+		p.Status.Phase = phase
+		p.Status.Conditions = conditions
+		return *p
+	}
+	unschedulable := v1.PodCondition{
+		Type:    v1.PodScheduled,
+		Status:  v1.ConditionFalse,
+		Reason:  v1.PodReasonUnschedulable,
+		Message: "0/3 nodes are available: 3 Insufficient cpu.",
+	}
+
+	tests := []struct {
+		name                   string
+		startTime              metav1.Time
+		pods                   []v1.Pod
+		wantPending            int32
+		wantStartTime          metav1.Time
+		wantReason             string
+		wantMessage            string
+		wantTimeToFullSchedule *metav1.Duration
+	}{
+		{
+			name:        "members missing",
+			pods:        []v1.Pod{pod("pod1", "", v1.PodPending)},
+			wantPending: 1,
+			wantReason:  v1alpha1.PodGroupReasonInsufficientMembers,
+			wantMessage: "1 members created, minMember is 2",
+		},
+		{
+			name:          "members unschedulable",
+			pods:          []v1.Pod{pod("pod1", "node1", v1.PodRunning), pod("pod2", "", v1.PodPending, unschedulable)},
+			wantPending:   1,
+			wantStartTime: now,
+			wantReason:    v1alpha1.PodGroupReasonUnschedulable,
+			wantMessage:   "1 members scheduled, minMember is 2; 1 members unschedulable, e.g. pod2: 0/3 nodes are available: 3 Insufficient cpu.",
+		},
+		{
+			name:          "members waiting",
+			startTime:     start,
+			pods:          []v1.Pod{pod("pod1", "", v1.PodPending), pod("pod2", "", v1.PodPending)},
+			wantPending:   2,
+			wantStartTime: start,
+			wantReason:    v1alpha1.PodGroupReasonWaiting,
+			wantMessage:   "0 members scheduled, minMember is 2",
+		},
+		{
+			name:                   "members scheduled",
+			startTime:              start,
+			pods:                   []v1.Pod{pod("pod1", "node1", v1.PodRunning), pod("pod2", "node1", v1.PodPending)},
+			wantPending:            1,
+			wantStartTime:          start,
+			wantReason:             v1alpha1.PodGroupReasonScheduled,
+			wantMessage:            "2 members scheduled, minMember is 2",
+			wantTimeToFullSchedule: &metav1.Duration{Duration: time.Minute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pg := makePG("pg", 2, v1alpha1.PodGroupScheduling, nil)
+			pg.Status.ScheduleStartTime = tt.startTime
+			updateSchedulingStatus(pg, tt.pods, now)
+
+			if pg.Status.Pending != tt.wantPending {
+				t.Errorf("Want %v pending members, got %v", tt.wantPending, pg.Status.Pending)
+			}
+			if !pg.Status.ScheduleStartTime.Equal(&tt.wantStartTime) {
+				t.Errorf("Want schedule start time %v, got %v", tt.wantStartTime, pg.Status.ScheduleStartTime)
+			}
+			if len(pg.Status.Conditions) != 1 {
+				t.Fatalf("Want a single condition, got %v", pg.Status.Conditions)
+			}
+			condition := pg.Status.Conditions[0]
+			if condition.Type != v1alpha1.PodGroupScheduled || condition.Reason != tt.wantReason || condition.Message != tt.wantMessage {
+				t.Errorf("Want condition %v with reason %q and message %q, got %+v", v1alpha1.PodGroupScheduled, tt.wantReason, tt.wantMessage, condition)
+			}
+			if (tt.wantReason == v1alpha1.PodGroupReasonScheduled) != (pg.Status.FullyScheduledTime != nil) {
+				t.Errorf("Unexpected fully scheduled time %v", pg.Status.FullyScheduledTime)
+			}
+			if !reflect.DeepEqual(pg.Status.TimeToFullSchedule, tt.wantTimeToFullSchedule) {
+				t.Errorf("Want time to full schedule %v, got %v", tt.wantTimeToFullSchedule, pg.Status.TimeToFullSchedule)
+			}
+		})
+	}
+}
+
 func setUp(ctx context.Context,
 	podNames []string,
 	pgName string,
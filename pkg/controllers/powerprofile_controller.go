@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran"
+)
+
+// powerProfileBaselineAge is how long after its placement a pod is deemed not running yet, the power of its
+// node then being measured as the baseline of the placement.
+const powerProfileBaselineAge = time.Minute
+
+// PowerProfileReconciler learns the power drawn per CPU core by the pods of the Deployments and Jobs, per node
+// class, from the variation of the power of their node after their placement. The power profiles are stored in
+// a ConfigMap, the Peaks plugin estimating the power of the pods from them rather than from the power models.
+type PowerProfileReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Namespace and Name of the power profile ConfigMap.
+	Namespace string
+	Name      string
+	// NodePower gives the average power drawn by a node, in watts.
+	NodePower func(logger klog.Logger, nodeName string) (float64, bool)
+	// SettleTime is how long after the placement of a pod the power of its node is measured again, at least
+	// the window the power of the nodes is averaged over. Placements on the node in between discard the measure.
+	SettleTime time.Duration
+	// RateLimiter are the settings of the rate limiter of the reconciliation queue.
+	RateLimiter RateLimiterOptions
+
+	mu sync.Mutex
+	// placements are the placements being measured, or measured already, per pod.
+	placements map[types.NamespacedName]*powerPlacement
+	// lastPlacements are the times of the last placements per node.
+	lastPlacements map[string]time.Time
+}
+
+// powerPlacement : placement of a pod whose power is learned
+type powerPlacement struct {
+	nodeName  string
+	scheduled time.Time
+	// baseline is the power of the node at the placement, in watts, measured at baselineTime.
+	baseline     float64
+	baselineTime time.Time
+	measured     bool
+}
+
+// +kubebuilder:rbac:groups="",resources=pods;nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;patch
+func (r *PowerProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	pod := &v1.Pod{}
+	if err := r.Get(ctx, req.NamespacedName, pod); err != nil {
+		if apierrs.IsNotFound(err) {
+			r.forgetPlacement(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	scheduled := podScheduledTime(pod)
+	if pod.Spec.NodeName == "" || scheduled.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	r.recordPlacement(pod.Spec.NodeName, scheduled)
+
+	key, ok := trimaran.PowerProfileKey(pod)
+	cpu := computePodResourceRequest(pod)[v1.ResourceCPU]
+	if !ok || cpu.IsZero() || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		r.forgetPlacement(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	r.mu.Lock()
+	p, ok := r.placements[req.NamespacedName]
+	r.mu.Unlock()
+	if !ok {
+		// Only a pod seen right after its placement gives the power of its node before it ran.
+		if now.Sub(scheduled) > powerProfileBaselineAge {
+			return ctrl.Result{}, nil
+		}
+		baseline, ok := r.NodePower(log, pod.Spec.NodeName)
+		if !ok {
+			return ctrl.Result{}, nil
+		}
+		r.mu.Lock()
+		if r.placements == nil {
+			r.placements = make(map[types.NamespacedName]*powerPlacement)
+		}
+		r.placements[req.NamespacedName] = &powerPlacement{nodeName: pod.Spec.NodeName, scheduled: scheduled, baseline: baseline, baselineTime: now}
+		r.mu.Unlock()
+		return ctrl.Result{RequeueAfter: r.SettleTime}, nil
+	}
+	if p.measured {
+		return ctrl.Result{}, nil
+	}
+	if remaining := p.baselineTime.Add(r.SettleTime).Sub(now); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	r.mu.Lock()
+	p.measured = true
+	disturbed := r.lastPlacements[p.nodeName].After(p.scheduled)
+	r.mu.Unlock()
+	if disturbed {
+		log.V(4).Info("Other pods placed on the node since the pod, not learning its power", "node", p.nodeName)
+		return ctrl.Result{}, nil
+	}
+	power, ok := r.NodePower(log, p.nodeName)
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+	node := &v1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: p.nodeName}, node); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	wattsPerCore := max(power-p.baseline, 0) * 1000 / float64(cpu.MilliValue())
+	if err := r.observe(ctx, key, trimaran.PowerProfileNodeClass(node), wattsPerCore); err != nil {
+		r.mu.Lock()
+		p.measured = false
+		r.mu.Unlock()
+		return ctrl.Result{}, err
+	}
+	log.V(4).Info("Learned the power of the pod", "workload", key, "node", p.nodeName, "wattsPerCore", wattsPerCore)
+	return ctrl.Result{}, nil
+}
+
+// recordPlacement remembers the last time a pod was placed on the node.
+func (r *PowerProfileReconciler) recordPlacement(nodeName string, scheduled time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lastPlacements == nil {
+		r.lastPlacements = make(map[string]time.Time)
+	}
+	if scheduled.After(r.lastPlacements[nodeName]) {
+		r.lastPlacements[nodeName] = scheduled
+	}
+}
+
+// forgetPlacement forgets the placement of the pod, if any.
+func (r *PowerProfileReconciler) forgetPlacement(name types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.placements, name)
+}
+
+// observe learns the power drawn per CPU core by the pods of the workload on the node class in the power
+// profile ConfigMap, creating it if needed.
+func (r *PowerProfileReconciler) observe(ctx context.Context, key, nodeClass string, wattsPerCore float64) error {
+	cm := &v1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: r.Name}, cm); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		profile := trimaran.PowerProfile{}
+		profile.Observe(nodeClass, wattsPerCore)
+		data, err := profile.Encode()
+		if err != nil {
+			return err
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: r.Namespace, Name: r.Name},
+			Data:       map[string]string{key: data},
+		}
+		return r.Create(ctx, cm)
+	}
+
+	profile := trimaran.PowerProfile{}
+	if data, ok := cm.Data[key]; ok {
+		var err error
+		if profile, err = trimaran.DecodePowerProfile(data); err != nil {
+			// Start over from an invalid power profile.
+			profile = trimaran.PowerProfile{}
+		}
+	}
+	profile.Observe(nodeClass, wattsPerCore)
+	data, err := profile.Encode()
+	if err != nil {
+		return err
+	}
+	cmCopy := cm.DeepCopy()
+	if cmCopy.Data == nil {
+		cmCopy.Data = make(map[string]string)
+	}
+	cmCopy.Data[key] = data
+	return r.Patch(ctx, cmCopy, client.MergeFromWithOptions(cm, client.MergeFromWithOptimisticLock{}))
+}
+
+// podScheduledTime returns when the pod was placed on its node, zero if not known.
+func podScheduledTime(pod *v1.Pod) time.Time {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodScheduled && c.Status == v1.ConditionTrue {
+			return c.LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
+
+// SetupWithManager sets up the controller with the Manager, watching the pods placed on a node.
+func (r *PowerProfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("powerprofile").
+		For(&v1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			pod, ok := obj.(*v1.Pod)
+			return ok && pod.Spec.NodeName != ""
+		}))).
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter.rateLimiter()}).
+		Complete(r)
+}
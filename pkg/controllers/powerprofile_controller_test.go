@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran"
+)
+
+func makePowerProfilePod(name, nodeName string, scheduled time.Time) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			Labels:          map[string]string{"pod-template-hash": "5b8c"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-5b8c", Controller: ptr.To(true)}},
+		},
+		Spec: v1.PodSpec{
+			NodeName: nodeName,
+			Containers: []v1.Container{{Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+			}}},
+		},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(scheduled)}},
+		},
+	}
+}
+
+func TestPowerProfileController_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(s))
+	now := time.Now()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: map[string]string{v1.LabelInstanceTypeStable: "m5.large"}}}
+
+	tests := []struct {
+		name      string
+		pods      []*v1.Pod
+		disturbed bool
+		want      trimaran.PowerProfile
+	}{
+		{
+			name: "power learned after the placement",
+			pods: []*v1.Pod{makePowerProfilePod("web-1", "n1", now)},
+			want: trimaran.PowerProfile{"m5.large": {WattsPerCore: 20, Samples: 1}},
+		},
+		{
+			name:      "other pod placed on the node since",
+			pods:      []*v1.Pod{makePowerProfilePod("web-1", "n1", now)},
+			disturbed: true,
+		},
+		{
+			name: "pod placed before the controller saw it",
+			pods: []*v1.Pod{makePowerProfilePod("web-1", "n1", now.Add(-time.Hour))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []runtime.Object{node}
+			for _, p := range tt.pods {
+				objs = append(objs, p)
+			}
+			power := map[string]float64{"n1": 100}
+			r := &PowerProfileReconciler{
+				Client:     fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).Build(),
+				Scheme:     s,
+				Namespace:  "kube-system",
+				Name:       "peaks-power-profiles",
+				SettleTime: 100 * time.Millisecond,
+				NodePower: func(_ klog.Logger, nodeName string) (float64, bool) {
+					w, ok := power[nodeName]
+					return w, ok
+				},
+			}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web-1"}}
+			result, err := r.Reconcile(ctx, req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := r.placements[req.NamespacedName]; !ok {
+				if tt.want != nil {
+					t.Fatal("expected the placement to be measured")
+				}
+				return
+			}
+			if result.RequeueAfter != r.SettleTime {
+				t.Errorf("expected a requeue after %v, got %v", r.SettleTime, result.RequeueAfter)
+			}
+
+			// The node draws 40W more once the pod runs.
+			power["n1"] = 140
+			if tt.disturbed {
+				r.recordPlacement("n1", time.Now())
+			}
+			time.Sleep(r.SettleTime)
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatal(err)
+			}
+			cm := &v1.ConfigMap{}
+			err = r.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: "peaks-power-profiles"}, cm)
+			if tt.want == nil {
+				if err == nil {
+					t.Errorf("expected no power profile, got %v", cm.Data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := trimaran.DecodePowerProfile(cm.Data["default_deployment_web"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) || got["m5.large"] != tt.want["m5.large"] {
+				t.Errorf("expected the power profile %v, got %v", tt.want, got)
+			}
+
+			// The placement is learned once.
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: "peaks-power-profiles"}, cm); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := trimaran.DecodePowerProfile(cm.Data["default_deployment_web"]); got["m5.large"].Samples != 1 {
+				t.Errorf("expected a single sample, got %v", got)
+			}
+		})
+	}
+}
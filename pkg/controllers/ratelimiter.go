@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RateLimiterOptions are the settings of the rate limiter of the queue of a reconciler: the requests failing
+// are retried with an exponential backoff from BaseDelay to MaxDelay, and all the requests are limited to QPS
+// with bursts of Burst. The zero value keeps the default rate limiter of controller-runtime.
+type RateLimiterOptions struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	QPS       float64
+	Burst     int
+}
+
+// Validate returns an error if the settings are not a valid rate limiter.
+func (o RateLimiterOptions) Validate() error {
+	if o == (RateLimiterOptions{}) {
+		return nil
+	}
+	if o.BaseDelay <= 0 || o.MaxDelay < o.BaseDelay {
+		return fmt.Errorf("invalid backoff from %v to %v, want 0 < base delay <= max delay", o.BaseDelay, o.MaxDelay)
+	}
+	if o.QPS <= 0 || o.Burst <= 0 {
+		return fmt.Errorf("invalid qps %v and burst %v, want positive values", o.QPS, o.Burst)
+	}
+	return nil
+}
+
+// rateLimiter returns the rate limiter with the settings, nil for the default rate limiter of controller-runtime.
+func (o RateLimiterOptions) rateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
+	if o == (RateLimiterOptions{}) {
+		return nil
+	}
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](o.BaseDelay, o.MaxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(o.QPS), o.Burst)},
+	)
+}
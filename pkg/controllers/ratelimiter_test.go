@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRateLimiterOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options RateLimiterOptions
+		wantErr bool
+		// wantDelays are the delays of the successive failures of a request, none for the default rate limiter
+		wantDelays []time.Duration
+	}{
+		{
+			name: "default rate limiter",
+		},
+		{
+			name:       "exponential backoff up to the max delay",
+			options:    RateLimiterOptions{BaseDelay: time.Second, MaxDelay: 3 * time.Second, QPS: 1000, Burst: 1000},
+			wantDelays: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+		{
+			name:    "base delay greater than the max delay",
+			options: RateLimiterOptions{BaseDelay: time.Minute, MaxDelay: time.Second, QPS: 10, Burst: 100},
+			wantErr: true,
+		},
+		{
+			name:    "no burst",
+			options: RateLimiterOptions{BaseDelay: time.Millisecond, MaxDelay: time.Second, QPS: 10},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			limiter := tt.options.rateLimiter()
+			if (limiter == nil) != (tt.wantDelays == nil) {
+				t.Fatalf("want default rate limiter %v, got %v", tt.wantDelays == nil, limiter)
+			}
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "pg"}}
+			for i, want := range tt.wantDelays {
+				if got := limiter.When(req); got != want {
+					t.Errorf("failure %d: want delay %v, got %v", i, want, got)
+				}
+			}
+		})
+	}
+}
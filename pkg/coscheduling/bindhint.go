@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/coscheduling/metrics"
+)
+
+// GangBindHintKey is the key in CycleState of the GangBindHint of a pod belonging to a PodGroup.
+const GangBindHintKey framework.StateKey = "PermitGangBindHint" + Name
+
+// GangBindHint is left in CycleState by the Permit of Coscheduling for the binding goroutines.
+// The hint is shared by all the members of a gang waiting in Permit. Once the gang is permitted,
+// its members are released at once and should be bound with high parallelism and priority,
+// to shorten the window during which a half-bound gang holds reserved capacity.
+type GangBindHint struct {
+	// PodGroup is the full name of the PodGroup of the gang.
+	PodGroup string
+
+	lock        sync.RWMutex
+	members     int
+	permittedAt time.Time
+}
+
+// Clone shares the hint, which is updated for all the members of the gang at once.
+func (h *GangBindHint) Clone() framework.StateData {
+	return h
+}
+
+// Permitted returns whether the gang passed Permit, with the number of members released together and when.
+func (h *GangBindHint) Permitted() (bool, int, time.Time) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.members > 0, h.members, h.permittedAt
+}
+
+func (h *GangBindHint) permit(members int, now time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.members = members
+	h.permittedAt = now
+}
+
+// GetGangBindHint returns the GangBindHint of the pod being scheduled or bound,
+// or nil if the pod doesn't belong to a PodGroup.
+func GetGangBindHint(state *framework.CycleState) *GangBindHint {
+	if state == nil {
+		return nil
+	}
+	c, err := state.Read(GangBindHintKey)
+	if err != nil {
+		return nil
+	}
+	hint, _ := c.(*GangBindHint)
+	return hint
+}
+
+// gangBindHint returns the hint shared by the members of the PodGroup waiting in Permit.
+func (cs *Coscheduling) gangBindHint(pgFullName string) *GangBindHint {
+	cs.bindHintLock.Lock()
+	defer cs.bindHintLock.Unlock()
+	if cs.bindHints == nil {
+		cs.bindHints = make(map[string]*GangBindHint)
+	}
+	hint, ok := cs.bindHints[pgFullName]
+	if !ok {
+		hint = &GangBindHint{PodGroup: pgFullName}
+		cs.bindHints[pgFullName] = hint
+		metrics.PodGroupsWaiting.Inc()
+	}
+	return hint
+}
+
+// dropGangBindHint forgets the hint of the PodGroup once its waiting members are released.
+func (cs *Coscheduling) dropGangBindHint(pgFullName string) {
+	cs.bindHintLock.Lock()
+	defer cs.bindHintLock.Unlock()
+	if _, ok := cs.bindHints[pgFullName]; ok {
+		delete(cs.bindHints, pgFullName)
+		metrics.PodGroupsWaiting.Dec()
+	}
+}
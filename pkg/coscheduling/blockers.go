@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/coscheduling/metrics"
+)
+
+// blockerTypes are the types of the rejections by the plugins rejecting the members of PodGroups,
+// the rejections by the other plugins are of type Other.
+var blockerTypes = map[string]v1alpha1.GangBlockerType{
+	"CapacityScheduling":        v1alpha1.GangBlockerQuota,
+	names.NodeAffinity:          v1alpha1.GangBlockerAffinity,
+	names.InterPodAffinity:      v1alpha1.GangBlockerAffinity,
+	names.PodTopologySpread:     v1alpha1.GangBlockerAffinity,
+	"HostAntiAffinity":          v1alpha1.GangBlockerAffinity,
+	names.NodeResourcesFit:      v1alpha1.GangBlockerResources,
+	names.NodePorts:             v1alpha1.GangBlockerResources,
+	"NodeResourceTopologyMatch": v1alpha1.GangBlockerResources,
+	"NFVAware":                  v1alpha1.GangBlockerResources,
+}
+
+// blockerTypeOrder is the order the types of the rejections are reported in.
+var blockerTypeOrder = []v1alpha1.GangBlockerType{
+	v1alpha1.GangBlockerQuota,
+	v1alpha1.GangBlockerAffinity,
+	v1alpha1.GangBlockerResources,
+	v1alpha1.GangBlockerOther,
+}
+
+// memberRejection is the last rejection of a member of a PodGroup.
+type memberRejection struct {
+	namespace string
+	name      string
+	blocker   v1alpha1.GangBlockerType
+	message   string
+}
+
+// rejectionOf returns the type and the message of the rejection of a pod by the nodes: the type rejecting the most
+// nodes, and the reasons of the nodes counted as in the FitError of the scheduler.
+func rejectionOf(statuses framework.NodeToStatusMap) (v1alpha1.GangBlockerType, string) {
+	if len(statuses) == 0 {
+		return v1alpha1.GangBlockerOther, ""
+	}
+	nodes := make(map[v1alpha1.GangBlockerType]int)
+	reasons := make(map[string]int)
+	for _, status := range statuses {
+		if status == nil {
+			continue
+		}
+		blocker, ok := blockerTypes[status.Plugin()]
+		if !ok {
+			blocker = v1alpha1.GangBlockerOther
+		}
+		nodes[blocker]++
+		for _, reason := range status.Reasons() {
+			reasons[reason]++
+		}
+	}
+	blocker, most := v1alpha1.GangBlockerOther, 0
+	for _, t := range blockerTypeOrder {
+		if nodes[t] > most {
+			blocker, most = t, nodes[t]
+		}
+	}
+	histogram := make([]string, 0, len(reasons))
+	for reason, count := range reasons {
+		histogram = append(histogram, fmt.Sprintf("%d %v", count, reason))
+	}
+	sort.Strings(histogram)
+	return blocker, fmt.Sprintf("0/%d nodes are available: %v.", len(statuses), strings.Join(histogram, ", "))
+}
+
+// recordRejection counts the rejection of a member of a PodGroup by its type, and remembers it if the members blocking
+// their PodGroup are reported.
+func (cs *Coscheduling) recordRejection(pod *v1.Pod, statuses framework.NodeToStatusMap) {
+	blocker, message := rejectionOf(statuses)
+	metrics.MemberRejections.WithLabelValues(string(blocker)).Inc()
+	if cs.waitingStatusInterval == 0 {
+		return
+	}
+	cs.rejectionLock.Lock()
+	defer cs.rejectionLock.Unlock()
+	if cs.rejections == nil {
+		cs.rejections = make(map[types.UID]memberRejection)
+	}
+	cs.rejections[pod.UID] = memberRejection{namespace: pod.Namespace, name: pod.Name, blocker: blocker, message: message}
+}
+
+// blockingMembers returns the members of the PodGroup neither bound nor waiting in Permit, sorted by name, with their
+// last rejection, and their number per type of rejection.
+func (cs *Coscheduling) blockingMembers(pgFullName string, waiting []v1alpha1.WaitingMember) ([]v1alpha1.BlockingMember, []v1alpha1.GangBlocker, error) {
+	namespace, name, _ := strings.Cut(pgFullName, "/")
+	pods, err := cs.listPodGroupPods(namespace, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	waitingNames := sets.New[string]()
+	for _, member := range waiting {
+		waitingNames.Insert(member.Name)
+	}
+
+	cs.rejectionLock.Lock()
+	defer cs.rejectionLock.Unlock()
+	var blocking []v1alpha1.BlockingMember
+	counts := make(map[v1alpha1.GangBlockerType]int32)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" || pod.DeletionTimestamp != nil || waitingNames.Has(pod.Name) ||
+			pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		rejection := cs.rejections[pod.UID]
+		blocking = append(blocking, v1alpha1.BlockingMember{Name: pod.Name, Type: rejection.blocker, Message: rejection.message})
+		if rejection.blocker != "" {
+			counts[rejection.blocker]++
+		}
+	}
+	sort.Slice(blocking, func(i, j int) bool { return blocking[i].Name < blocking[j].Name })
+	var blockers []v1alpha1.GangBlocker
+	for _, t := range blockerTypeOrder {
+		if counts[t] > 0 {
+			blockers = append(blockers, v1alpha1.GangBlocker{Type: t, Members: counts[t]})
+		}
+	}
+	return blocking, blockers, nil
+}
+
+// forgetRejections forgets the rejections of the pods deleted or bound since.
+func (cs *Coscheduling) forgetRejections() {
+	lister := cs.frameworkHandler.SharedInformerFactory().Core().V1().Pods().Lister()
+	cs.rejectionLock.Lock()
+	defer cs.rejectionLock.Unlock()
+	for uid, rejection := range cs.rejections {
+		pod, err := lister.Pods(rejection.namespace).Get(rejection.name)
+		if err != nil || pod.UID != uid || pod.Spec.NodeName != "" {
+			delete(cs.rejections, uid)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/coscheduling/core"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+// heldBackKey is the key in CycleState marking a pod rejected in PreFilter by the waiting budget of its namespace.
+const heldBackKey framework.StateKey = "PreFilterHeldBack" + Name
+
+// heldBackState marks a pod whose PodGroup is held back by the waiting budget of its namespace.
+type heldBackState struct{}
+
+// Clone shares the mark, which holds no data.
+func (s *heldBackState) Clone() framework.StateData {
+	return s
+}
+
+// isHeldBack returns whether the pod of the cycle was rejected by the waiting budget of its namespace.
+func isHeldBack(state *framework.CycleState) bool {
+	if state == nil {
+		return false
+	}
+	_, err := state.Read(heldBackKey)
+	return err == nil
+}
+
+// checkWaitingBudget rejects the pod if its PodGroup has no member waiting in Permit yet and its namespace
+// already has maxWaitingPodGroups PodGroups waiting in Permit, so that a single namespace can't fill the
+// waiting pods with large gangs. The PodGroups held back are activated again once a waiting one leaves Permit,
+// the rejection being marked in the state so that it doesn't release them itself.
+func (cs *Coscheduling) checkWaitingBudget(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+	if cs.maxWaitingPodGroups == 0 {
+		return nil
+	}
+	pgFullName := util.GetPodGroupFullName(pod)
+	if pgFullName == "" {
+		return nil
+	}
+	namespace := util.GetPodGroupNamespace(pod)
+
+	waiting := sets.New[string]()
+	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		if util.GetPodGroupNamespace(waitingPod.GetPod()) != namespace {
+			return
+		}
+		if name := util.GetPodGroupFullName(waitingPod.GetPod()); name != "" {
+			waiting.Insert(name)
+		}
+	})
+	if waiting.Has(pgFullName) || int64(waiting.Len()) < cs.maxWaitingPodGroups {
+		return nil
+	}
+
+	cs.budgetLock.Lock()
+	if cs.heldBack == nil {
+		cs.heldBack = make(map[string]sets.Set[string])
+	}
+	if cs.heldBack[namespace] == nil {
+		cs.heldBack[namespace] = sets.New[string]()
+	}
+	cs.heldBack[namespace].Insert(util.GetPodGroupLabel(pod))
+	cs.budgetLock.Unlock()
+	if state != nil {
+		state.Write(heldBackKey, &heldBackState{})
+	}
+
+	klog.FromContext(ctx).V(3).Info("PodGroup held back by the waiting budget of its namespace",
+		"podGroup", pgFullName, "pod", klog.KObj(pod), "waitingPodGroups", waiting.Len())
+	return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+		fmt.Sprintf("namespace %v already has %v PodGroups waiting in Permit, the maximum allowed: podGroup %v waits for one of them to be admitted or rejected",
+			namespace, waiting.Len(), pgFullName))
+}
+
+// releaseWaitingBudget moves the pods of the PodGroups held back by the waiting budget of the namespace
+// back to activeQ through the given state, once a PodGroup of the namespace left Permit. Nothing is
+// released without a state, the PodGroups held back are then activated by the next release.
+func (cs *Coscheduling) releaseWaitingBudget(ctx context.Context, namespace string, state *framework.CycleState) {
+	if cs.maxWaitingPodGroups == 0 || state == nil {
+		return
+	}
+	c, err := state.Read(framework.PodsToActivateKey)
+	if err != nil {
+		return
+	}
+	s, ok := c.(*framework.PodsToActivate)
+	if !ok {
+		return
+	}
+
+	cs.budgetLock.Lock()
+	heldBack := cs.heldBack[namespace]
+	delete(cs.heldBack, namespace)
+	cs.budgetLock.Unlock()
+
+	lh := klog.FromContext(ctx)
+	for pgName := range heldBack {
+		pods, err := cs.listPodGroupPods(namespace, pgName)
+		if err != nil {
+			lh.Error(err, "Failed to obtain pods belong to a PodGroup", "podGroup", klog.KRef(namespace, pgName))
+			continue
+		}
+		s.Lock()
+		for _, pod := range pods {
+			if pod.Spec.NodeName == "" {
+				s.Map[core.GetNamespacedName(pod)] = pod
+			}
+		}
+		s.Unlock()
+	}
+}
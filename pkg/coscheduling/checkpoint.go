@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+const (
+	// CheckpointReasonPreemption is the reason of the checkpoint requested before the members are preempted.
+	CheckpointReasonPreemption = "Preemption"
+	// CheckpointReasonTimeout is the reason of the checkpoint requested before the PodGroup is released
+	// because of its progress deadline.
+	CheckpointReasonTimeout = "Timeout"
+)
+
+// checkpointPollInterval is the interval at which the acknowledgment annotations of the members are checked.
+var checkpointPollInterval = time.Second
+
+// CheckpointRequest is the body of the request posted to the checkpoint webhook.
+type CheckpointRequest struct {
+	// PodGroup is the full name of the PodGroup, as <namespace>/<name>.
+	PodGroup string `json:"podGroup"`
+	// Reason is the reason of the checkpoint, Preemption or Timeout.
+	Reason string `json:"reason"`
+	// Pods are the names of the members to checkpoint.
+	Pods []string `json:"pods"`
+	// Deadline is the time after which the members are preempted or released without acknowledgment.
+	Deadline metav1.Time `json:"deadline"`
+}
+
+// requestCheckpoint asks for a checkpoint of the members of the PodGroup, annotating them and calling the webhook if
+// configured, then waits until it is acknowledged: by a successful response of the webhook, or by the acknowledgment
+// annotation of the members still running. It returns false if the checkpoint timeout expired first.
+func (cs *Coscheduling) requestCheckpoint(ctx context.Context, pgFullName string, pods []*v1.Pod, reason string) bool {
+	lh := klog.FromContext(ctx)
+	deadline := time.Now().Add(cs.checkpointTimeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	for _, pod := range pods {
+		if err := cs.annotateCheckpoint(ctx, pod, reason); err != nil {
+			lh.Error(err, "Failed to request the checkpoint of the pod", "pod", klog.KObj(pod), "podGroup", pgFullName)
+		}
+	}
+	if cs.checkpointWebhookURL != "" {
+		err := cs.callCheckpointWebhook(ctx, pgFullName, pods, reason, deadline)
+		if err == nil {
+			lh.V(4).Info("Checkpoint acknowledged by the webhook", "podGroup", pgFullName, "reason", reason)
+			return true
+		}
+		lh.Error(err, "Checkpoint webhook failed, waiting for the acknowledgment annotations", "podGroup", pgFullName)
+	}
+
+	err := wait.PollUntilContextCancel(ctx, checkpointPollInterval, true, func(context.Context) (bool, error) {
+		return cs.checkpointAcknowledged(pods), nil
+	})
+	if err != nil {
+		lh.V(3).Info("Checkpoint not acknowledged in time", "podGroup", pgFullName, "reason", reason, "timeout", cs.checkpointTimeout)
+		return false
+	}
+	lh.V(4).Info("Checkpoint acknowledged", "podGroup", pgFullName, "reason", reason)
+	return true
+}
+
+// annotateCheckpoint sets the CheckpointRequestedAnnotation on the pod, and clears a previous acknowledgment.
+func (cs *Coscheduling) annotateCheckpoint(ctx context.Context, pod *v1.Pod, reason string) error {
+	podCopy := pod.DeepCopy()
+	if podCopy.Annotations == nil {
+		podCopy.Annotations = make(map[string]string)
+	}
+	podCopy.Annotations[v1alpha1.CheckpointRequestedAnnotation] = reason
+	delete(podCopy.Annotations, v1alpha1.CheckpointAcknowledgedAnnotation)
+	patch, err := util.CreateMergePatch(pod, podCopy)
+	if err != nil {
+		return err
+	}
+	_, err = cs.frameworkHandler.ClientSet().CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// checkpointAcknowledged returns whether all the pods acknowledged their checkpoint, or are already gone.
+func (cs *Coscheduling) checkpointAcknowledged(pods []*v1.Pod) bool {
+	lister := cs.frameworkHandler.SharedInformerFactory().Core().V1().Pods().Lister()
+	for _, pod := range pods {
+		current, err := lister.Pods(pod.Namespace).Get(pod.Name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false
+		}
+		if current.UID != pod.UID || current.DeletionTimestamp != nil ||
+			current.Status.Phase == v1.PodSucceeded || current.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if current.Annotations[v1alpha1.CheckpointAcknowledgedAnnotation] != "true" {
+			return false
+		}
+	}
+	return true
+}
+
+// callCheckpointWebhook posts the CheckpointRequest to the webhook. A 2xx response acknowledges the checkpoint.
+func (cs *Coscheduling) callCheckpointWebhook(ctx context.Context, pgFullName string, pods []*v1.Pod, reason string, deadline time.Time) error {
+	request := CheckpointRequest{
+		PodGroup: pgFullName,
+		Reason:   reason,
+		Deadline: metav1.NewTime(deadline),
+	}
+	for _, pod := range pods {
+		request.Pods = append(request.Pods, pod.Name)
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cs.checkpointWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("checkpoint webhook returned status %v", resp.Status)
+	}
+	return nil
+}
+
+// startCheckpointedPreemption evicts the victims once their checkpoint is acknowledged or timed out, in the background.
+// Until then, the victims are not preempted again and the preemptor stays nominated on the node.
+func (cs *Coscheduling) startCheckpointedPreemption(ctx context.Context, preemptor *v1.Pod, pgName string, victims []*victimGroup, nodeName string) {
+	cs.checkpointLock.Lock()
+	cs.pendingPreemptions[pgName] = nodeName
+	for _, group := range victims {
+		cs.checkpointing.Insert(group.name)
+	}
+	cs.checkpointLock.Unlock()
+
+	ctx = klog.NewContext(context.Background(), klog.FromContext(ctx))
+	go func() {
+		defer func() {
+			cs.checkpointLock.Lock()
+			delete(cs.pendingPreemptions, pgName)
+			for _, group := range victims {
+				cs.checkpointing.Delete(group.name)
+			}
+			cs.checkpointLock.Unlock()
+		}()
+		for _, group := range victims {
+			pods := make([]*v1.Pod, 0, len(group.pods))
+			for _, vp := range group.pods {
+				pods = append(pods, vp.podInfo.Pod)
+			}
+			cs.requestCheckpoint(ctx, group.name, pods, CheckpointReasonPreemption)
+			if err := cs.evictPodGroup(ctx, preemptor, pgName, group); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// pendingPreemption returns the node the PodGroup is nominated on while the checkpoint of its victims is in progress.
+func (cs *Coscheduling) pendingPreemption(pgName string) (string, bool) {
+	cs.checkpointLock.Lock()
+	defer cs.checkpointLock.Unlock()
+	nodeName, ok := cs.pendingPreemptions[pgName]
+	return nodeName, ok
+}
+
+// isCheckpointing returns whether the PodGroup is being checkpointed before its preemption.
+func (cs *Coscheduling) isCheckpointing(pgName string) bool {
+	cs.checkpointLock.Lock()
+	defer cs.checkpointLock.Unlock()
+	return cs.checkpointing.Has(pgName)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCallCheckpointWebhook(t *testing.T) {
+	pods := []*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "p1", Namespace: "ns1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "p2", Namespace: "ns1"}},
+	}
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:   "checkpoint acknowledged",
+			status: http.StatusOK,
+		},
+		{
+			name:   "checkpoint acknowledged without content",
+			status: http.StatusNoContent,
+		},
+		{
+			name:    "checkpoint failed",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got CheckpointRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("Failed to decode the checkpoint request: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			cs := &Coscheduling{checkpointWebhookURL: server.URL}
+			deadline := time.Now().Add(time.Minute).Truncate(time.Second)
+			err := cs.callCheckpointWebhook(context.Background(), "ns1/pg1", pods, CheckpointReasonPreemption, deadline)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("callCheckpointWebhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			want := CheckpointRequest{
+				PodGroup: "ns1/pg1",
+				Reason:   CheckpointReasonPreemption,
+				Pods:     []string{"p1", "p2"},
+				Deadline: metav1.NewTime(deadline),
+			}
+			if !reflect.DeepEqual(got.Pods, want.Pods) || got.PodGroup != want.PodGroup || got.Reason != want.Reason ||
+				!got.Deadline.Equal(&want.Deadline) {
+				t.Errorf("Unexpected checkpoint request %+v, want %+v", got, want)
+			}
+		})
+	}
+}
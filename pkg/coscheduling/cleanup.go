@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	ctrlruntimecache "sigs.k8s.io/controller-runtime/pkg/cache"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// watchPodGroupDeletions starts a PodGroup informer flushing the state cached for the PodGroups being deleted,
+// see SchedulerCacheFinalizer.
+func (cs *Coscheduling) watchPodGroupDeletions(ctx context.Context, scheme *runtime.Scheme) error {
+	podGroupCache, err := ctrlruntimecache.New(cs.frameworkHandler.KubeConfig(), ctrlruntimecache.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	podGroupInformer, err := podGroupCache.GetInformer(ctx, &v1alpha1.PodGroup{})
+	if err != nil {
+		return err
+	}
+	if _, err := podGroupInformer.AddEventHandler(cs.podGroupCleanupHandler(ctx)); err != nil {
+		return err
+	}
+	go func() {
+		if err := podGroupCache.Start(ctx); err != nil {
+			klog.FromContext(ctx).Error(err, "Failed to start the PodGroup cache")
+		}
+	}()
+	return nil
+}
+
+// podGroupCleanupHandler flushes the state cached for a PodGroup as soon as it is being deleted, i.e. once
+// the controller's SchedulerCacheFinalizer holds it with a deletion timestamp, or at the latest once it is gone,
+// so that no admission decision outlives the PodGroup until the caches expire.
+func (cs *Coscheduling) podGroupCleanupHandler(ctx context.Context) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPG, ok1 := oldObj.(*v1alpha1.PodGroup)
+			newPG, ok2 := newObj.(*v1alpha1.PodGroup)
+			if ok1 && ok2 && oldPG.DeletionTimestamp == nil && newPG.DeletionTimestamp != nil {
+				cs.flushPodGroup(ctx, newPG)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pg, ok := obj.(*v1alpha1.PodGroup); ok {
+				cs.flushPodGroup(ctx, pg)
+			}
+		},
+	}
+}
+
+// flushPodGroup drops the permitted, backed off and admission state of the PodGroup, its binding hint,
+// its last scale-up, and its entry among the PodGroups held back by the waiting budget.
+func (cs *Coscheduling) flushPodGroup(ctx context.Context, pg *v1alpha1.PodGroup) {
+	pgFullName := fmt.Sprintf("%v/%v", pg.Namespace, pg.Name)
+	klog.FromContext(ctx).V(4).Info("Flushing the state cached for a deleted PodGroup", "podGroup", pgFullName)
+
+	cs.pgMgr.FlushPodGroup(ctx, pgFullName)
+	cs.dropGangBindHint(pgFullName)
+	cs.forgetScaleUp(pgFullName)
+
+	cs.budgetLock.Lock()
+	defer cs.budgetLock.Unlock()
+	if heldBack := cs.heldBack[pg.Namespace]; heldBack != nil {
+		heldBack.Delete(pg.Name)
+		if heldBack.Len() == 0 {
+			delete(cs.heldBack, pg.Namespace)
+		}
+	}
+}
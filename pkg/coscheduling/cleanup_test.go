@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	clicache "k8s.io/client-go/tools/cache"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/coscheduling/core"
+	tu "github.com/amiraBenamer20/scheduler-plugins/test/util"
+)
+
+func TestPodGroupCleanupHandler(t *testing.T) {
+	pg := &v1alpha1.PodGroup{ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: "ns1"}, Spec: v1alpha1.PodGroupSpec{MinMember: 2}}
+	deleting := pg.DeepCopy()
+	deleting.DeletionTimestamp = &metav1.Time{}
+
+	tests := []struct {
+		name        string
+		event       func(handler clicache.ResourceEventHandler)
+		wantFlushed bool
+	}{
+		{
+			name:  "PodGroup updated",
+			event: func(handler clicache.ResourceEventHandler) { handler.OnUpdate(pg, pg.DeepCopy()) },
+		},
+		{
+			name:        "PodGroup held by the finalizer",
+			event:       func(handler clicache.ResourceEventHandler) { handler.OnUpdate(pg, deleting) },
+			wantFlushed: true,
+		},
+		{
+			name:        "PodGroup deleted",
+			event:       func(handler clicache.ResourceEventHandler) { handler.OnDelete(pg) },
+			wantFlushed: true,
+		},
+		{
+			name: "PodGroup deleted while disconnected",
+			event: func(handler clicache.ResourceEventHandler) {
+				handler.OnDelete(clicache.DeletedFinalStateUnknown{Key: "ns1/pg1", Obj: pg})
+			},
+			wantFlushed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client, err := tu.NewFakeClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			podInformer := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0).Core().V1().Pods()
+			pl := &Coscheduling{
+				pgMgr:     core.NewPodGroupManager(client, nil, nil, podInformer),
+				heldBack:  map[string]sets.Set[string]{"ns1": sets.New("pg1", "pg2")},
+				bindHints: map[string]*GangBindHint{"ns1/pg1": {PodGroup: "ns1/pg1"}},
+			}
+
+			tt.event(pl.podGroupCleanupHandler(ctx))
+
+			if _, ok := pl.bindHints["ns1/pg1"]; ok == tt.wantFlushed {
+				t.Errorf("expected the binding hint of ns1/pg1 flushed %v", tt.wantFlushed)
+			}
+			if pl.heldBack["ns1"].Has("pg1") == tt.wantFlushed {
+				t.Errorf("expected ns1/pg1 held back by the waiting budget flushed %v", tt.wantFlushed)
+			}
+			if !pl.heldBack["ns1"].Has("pg2") {
+				t.Error("expected ns1/pg2 to stay held back by the waiting budget")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// gangCandidateTTL is the time after which a waiting gang no longer attempted is forgotten. The pods of
+// unschedulable gangs are retried at least every 5 minutes by the scheduling queue.
+const gangCandidateTTL = 5 * time.Minute
+
+// gangCandidate is a gang waiting for capacity.
+type gangCandidate struct {
+	priority int32
+	created  time.Time
+	lastSeen time.Time
+}
+
+// before returns whether the candidate takes precedence over the other one: the highest priority first,
+// then the oldest PodGroup, then by name.
+func (c gangCandidate) before(name string, other gangCandidate, otherName string) bool {
+	if c.priority != other.priority {
+		return c.priority > other.priority
+	}
+	if !c.created.Equal(other.created) {
+		return c.created.Before(other.created)
+	}
+	return name < otherName
+}
+
+// gangArbiter serializes which gang may consume newly freed capacity. Without it, all the waiting gangs
+// race for the freed capacity, each possibly getting some of its members assumed, and none of them
+// completing. When capacity frees up, the arbiter opens a window during which only the highest-priority,
+// then oldest, waiting gang gets past PreFilter. The window closes once that gang is admitted or rejected,
+// or when it expires.
+type gangArbiter struct {
+	window time.Duration
+	clock  clock.Clock
+
+	sync.Mutex
+	// candidates stores the waiting gangs by the full name of their PodGroup.
+	candidates map[string]gangCandidate
+	// holder is the full name of the PodGroup the freed capacity is reserved to until expiry, if any.
+	holder string
+	expiry time.Time
+}
+
+func newGangArbiter(window time.Duration, clock clock.Clock) *gangArbiter {
+	return &gangArbiter{
+		window:     window,
+		clock:      clock,
+		candidates: make(map[string]gangCandidate),
+	}
+}
+
+// observe records the gang as waiting for capacity.
+func (a *gangArbiter) observe(pgFullName string, priority int32, created time.Time) {
+	a.Lock()
+	defer a.Unlock()
+	a.candidates[pgFullName] = gangCandidate{priority: priority, created: created, lastSeen: a.clock.Now()}
+}
+
+// capacityFreed opens a window for the first waiting gang, unless a window is already open.
+func (a *gangArbiter) capacityFreed() {
+	a.Lock()
+	defer a.Unlock()
+	now := a.clock.Now()
+	if a.holder != "" && now.Before(a.expiry) {
+		return
+	}
+	a.holder = ""
+	var first gangCandidate
+	for name, c := range a.candidates {
+		if now.Sub(c.lastSeen) > gangCandidateTTL {
+			delete(a.candidates, name)
+			continue
+		}
+		if a.holder == "" || c.before(name, first, a.holder) {
+			a.holder, first = name, c
+		}
+	}
+	a.expiry = now.Add(a.window)
+}
+
+// admit returns whether the gang may consume the freed capacity, and otherwise the gang holding it.
+func (a *gangArbiter) admit(pgFullName string) (string, bool) {
+	a.Lock()
+	defer a.Unlock()
+	if a.holder == "" || a.holder == pgFullName || !a.clock.Now().Before(a.expiry) {
+		return "", true
+	}
+	return a.holder, false
+}
+
+// release closes the window held by the gang, forgetting the gang if it got admitted. It returns the other
+// waiting gangs, held back during the window, or nil if the gang held no window.
+func (a *gangArbiter) release(pgFullName string, admitted bool) []string {
+	a.Lock()
+	defer a.Unlock()
+	if admitted {
+		delete(a.candidates, pgFullName)
+	}
+	if a.holder != pgFullName {
+		return nil
+	}
+	held := a.clock.Now().Before(a.expiry)
+	a.holder = ""
+	if !held {
+		return nil
+	}
+	var waiting []string
+	for name := range a.candidates {
+		if name != pgFullName {
+			waiting = append(waiting, name)
+		}
+	}
+	return waiting
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestGangArbiter(t *testing.T) {
+	now := time.Now()
+	type candidate struct {
+		name     string
+		priority int32
+		created  time.Time
+	}
+	tests := []struct {
+		name         string
+		candidates   []candidate
+		expectHolder string
+	}{
+		{
+			name: "highest priority gang first",
+			candidates: []candidate{
+				{name: "ns/pg1", priority: 0, created: now.Add(-time.Hour)},
+				{name: "ns/pg2", priority: 10, created: now},
+			},
+			expectHolder: "ns/pg2",
+		},
+		{
+			name: "oldest gang first with the same priority",
+			candidates: []candidate{
+				{name: "ns/pg1", priority: 10, created: now},
+				{name: "ns/pg2", priority: 10, created: now.Add(-time.Hour)},
+			},
+			expectHolder: "ns/pg2",
+		},
+		{
+			name: "by name with the same priority and age",
+			candidates: []candidate{
+				{name: "ns/pg2", priority: 10, created: now},
+				{name: "ns/pg1", priority: 10, created: now},
+			},
+			expectHolder: "ns/pg1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newGangArbiter(time.Minute, testingclock.NewFakeClock(now))
+			for _, c := range tt.candidates {
+				a.observe(c.name, c.priority, c.created)
+			}
+			// No window is open until capacity frees up.
+			for _, c := range tt.candidates {
+				if _, ok := a.admit(c.name); !ok {
+					t.Errorf("expected %v to be admitted before capacity frees up", c.name)
+				}
+			}
+			a.capacityFreed()
+			for _, c := range tt.candidates {
+				holder, ok := a.admit(c.name)
+				if ok != (c.name == tt.expectHolder) {
+					t.Errorf("expected only %v to be admitted, got %v admitted: %v", tt.expectHolder, c.name, ok)
+				}
+				if !ok && holder != tt.expectHolder {
+					t.Errorf("expected the holder to be %v, got %v", tt.expectHolder, holder)
+				}
+			}
+		})
+	}
+}
+
+func TestGangArbiterWindow(t *testing.T) {
+	now := time.Now()
+	clock := testingclock.NewFakeClock(now)
+	a := newGangArbiter(time.Minute, clock)
+	a.observe("ns/pg1", 10, now)
+	a.observe("ns/pg2", 0, now)
+	a.observe("ns/pg3", 0, now)
+
+	a.capacityFreed()
+	if _, ok := a.admit("ns/pg2"); ok {
+		t.Fatalf("expected ns/pg2 to be held back")
+	}
+	// Capacity freed again doesn't move an open window.
+	a.observe("ns/pg4", 100, now)
+	a.capacityFreed()
+	if holder, _ := a.admit("ns/pg4"); holder != "ns/pg1" {
+		t.Errorf("expected the window to stay with ns/pg1, got %v", holder)
+	}
+
+	// Another gang releasing doesn't close the window.
+	if waiting := a.release("ns/pg2", false); waiting != nil {
+		t.Errorf("expected no waiting gangs on release by a gang not holding the window, got %v", waiting)
+	}
+	if _, ok := a.admit("ns/pg2"); ok {
+		t.Errorf("expected ns/pg2 to still be held back")
+	}
+
+	// The holder getting admitted closes the window and is forgotten.
+	waiting := a.release("ns/pg1", true)
+	sort.Strings(waiting)
+	if diff := cmp.Diff([]string{"ns/pg2", "ns/pg3", "ns/pg4"}, waiting); diff != "" {
+		t.Errorf("unexpected waiting gangs (-want, +got):\n%s", diff)
+	}
+	if _, ok := a.admit("ns/pg2"); !ok {
+		t.Errorf("expected ns/pg2 to be admitted once the window is closed")
+	}
+	a.capacityFreed()
+	if holder, _ := a.admit("ns/pg2"); holder != "ns/pg4" {
+		t.Errorf("expected the next window to go to ns/pg4, got %v", holder)
+	}
+
+	// The window expires.
+	clock.Step(2 * time.Minute)
+	if _, ok := a.admit("ns/pg2"); !ok {
+		t.Errorf("expected ns/pg2 to be admitted once the window expired")
+	}
+	if waiting := a.release("ns/pg4", false); waiting != nil {
+		t.Errorf("expected no waiting gangs on release of an expired window, got %v", waiting)
+	}
+
+	// The gangs no longer attempted are forgotten.
+	clock.Step(gangCandidateTTL)
+	a.observe("ns/pg3", 0, now)
+	a.capacityFreed()
+	if holder, ok := a.admit("ns/pg4"); ok || holder != "ns/pg3" {
+		t.Errorf("expected the window to go to ns/pg3, the only gang still attempted, got %v", holder)
+	}
+}
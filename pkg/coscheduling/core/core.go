@@ -26,12 +26,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	informerv1 "k8s.io/client-go/informers/core/v1"
 	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
 	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	// "sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
@@ -73,7 +76,12 @@ type Manager interface {
 	DeletePermittedPodGroup(context.Context, string)
 	CalculateAssignedPods(context.Context, string, string) int
 	ActivateSiblings(ctx context.Context, pod *corev1.Pod, state *framework.CycleState)
-	BackoffPodGroup(string, time.Duration)
+	BackoffPodGroup(string, *v1alpha1.PodGroup, time.Duration)
+	CheckGangFeasibility(ctx context.Context, pod *corev1.Pod, nodeName string, waitingPods []*corev1.Pod) error
+	ReleaseAdmission(ctx context.Context, pgFullName string, admitted bool, state *framework.CycleState)
+	FlushPodGroup(ctx context.Context, pgFullName string)
+	RecordFailedAttempt(pgFullName string)
+	GetRetryPenalty(*corev1.Pod) time.Duration
 }
 
 // PodGroupManager defines the scheduling operation called
@@ -89,8 +97,22 @@ type PodGroupManager struct {
 	permittedPG *gocache.Cache
 	// backedOffPG stores the podgorup name which failed scheudling recently.
 	backedOffPG *gocache.Cache
+	// backoffFailures stores the number of consecutive failures of the podgroups having a backoff policy.
+	backoffFailures *gocache.Cache
+	// activatedMembers stores the UID of the pod which last activated the siblings of its podgroup, per member,
+	// so that a recreated member does not activate them again.
+	activatedMembers *gocache.Cache
+	// memberAccess stores whether the service accounts of the pods of other namespaces than their podgroup
+	// are allowed to use it, per service account and podgroup.
+	memberAccess *gocache.Cache
 	// podLister is pod lister
 	podLister listerv1.PodLister
+	// arbiter reserves the freed capacity to a single waiting gang at a time, if enabled.
+	arbiter *gangArbiter
+	// retryPenalty is the time a podgroup is delayed by in the scheduling queue per recent failed attempt, if enabled.
+	retryPenalty time.Duration
+	// failedAttempts stores the number of recent failed attempts of the podgroups, if the retry penalty is enabled.
+	failedAttempts *gocache.Cache
 	sync.RWMutex
 }
 
@@ -103,11 +125,90 @@ func NewPodGroupManager(client client.Client, snapshotSharedLister framework.Sha
 		podLister:            podInformer.Lister(),
 		permittedPG:          gocache.New(3*time.Second, 3*time.Second),
 		backedOffPG:          gocache.New(10*time.Second, 10*time.Second),
+		backoffFailures:      gocache.New(10*time.Second, 10*time.Second),
+		activatedMembers:     gocache.New(10*time.Second, 10*time.Second),
+		memberAccess:         gocache.New(memberAccessTTL, memberAccessTTL),
 	}
 	return pgMgr
 }
 
-func (pgMgr *PodGroupManager) BackoffPodGroup(pgName string, backoff time.Duration) {
+// EnableGangArbiter reserves the capacity freed by the pods leaving the nodes to the highest-priority, then
+// oldest, waiting gang for the given window, rather than letting all the waiting gangs race for it.
+func (pgMgr *PodGroupManager) EnableGangArbiter(window time.Duration, podInformer informerv1.PodInformer) error {
+	pgMgr.arbiter = newGangArbiter(window, clock.RealClock{})
+	_, err := podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, ok1 := oldObj.(*corev1.Pod)
+			newPod, ok2 := newObj.(*corev1.Pod)
+			if ok1 && ok2 && newPod.Spec.NodeName != "" && !isTerminated(oldPod) && isTerminated(newPod) {
+				pgMgr.arbiter.capacityFreed()
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pod, ok := obj.(*corev1.Pod); ok && pod.Spec.NodeName != "" && !isTerminated(pod) {
+				pgMgr.arbiter.capacityFreed()
+			}
+		},
+	})
+	return err
+}
+
+// ReleaseAdmission ends the window during which the freed capacity is reserved to the PodGroup, if any,
+// once the gang got admitted or rejected. The pods of the gangs held back during the window are moved
+// back to activeQ through the given state, if not nil.
+func (pgMgr *PodGroupManager) ReleaseAdmission(ctx context.Context, pgFullName string, admitted bool, state *framework.CycleState) {
+	if pgMgr.arbiter == nil {
+		return
+	}
+	waiting := pgMgr.arbiter.release(pgFullName, admitted)
+	if len(waiting) == 0 || state == nil {
+		return
+	}
+	c, err := state.Read(framework.PodsToActivateKey)
+	if err != nil {
+		return
+	}
+	s, ok := c.(*framework.PodsToActivate)
+	if !ok {
+		return
+	}
+	lh := klog.FromContext(ctx)
+	for _, name := range waiting {
+		namespace, pgName, err := cache.SplitMetaNamespaceKey(name)
+		if err != nil {
+			continue
+		}
+		pods, err := pgMgr.listPodGroupPods(ctx, namespace, pgName)
+		if err != nil {
+			lh.Error(err, "Failed to obtain pods belong to a PodGroup", "podGroup", name)
+			continue
+		}
+		s.Lock()
+		for _, pod := range pods {
+			if pod.Spec.NodeName == "" {
+				s.Map[GetNamespacedName(pod)] = pod
+			}
+		}
+		s.Unlock()
+	}
+}
+
+// BackoffPodGroup backs off the podgroup for the given duration, or, if the podgroup has a backoff policy,
+// for the backoff of its consecutive failures given by the policy.
+func (pgMgr *PodGroupManager) BackoffPodGroup(pgName string, pg *v1alpha1.PodGroup, backoff time.Duration) {
+	if pg != nil && pg.Spec.Backoff != nil && pgMgr.backoffFailures != nil {
+		failures := 1
+		if n, ok := pgMgr.backoffFailures.Get(pgName); ok {
+			failures = n.(int) + 1
+		}
+		var maxBackoff time.Duration
+		backoff, maxBackoff = util.GetBackoffDuration(pg, failures)
+		// The failures are forgotten once the podgroup gets no failure for twice the maximal backoff.
+		pgMgr.backoffFailures.Set(pgName, failures, 2*maxBackoff)
+	}
 	if backoff == time.Duration(0) {
 		return
 	}
@@ -130,26 +231,26 @@ func (pgMgr *PodGroupManager) ActivateSiblings(ctx context.Context, pod *corev1.
 		return
 	}
 
-	pods, err := pgMgr.podLister.Pods(pod.Namespace).List(
-		labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: pgName}),
-	)
+	pods, err := pgMgr.listPodGroupPods(ctx, util.GetPodGroupNamespace(pod), pgName)
 	if err != nil {
 		lh.Error(err, "Failed to obtain pods belong to a PodGroup", "podGroup", pgName)
 		return
 	}
 
-	for i := range pods {
-		if pods[i].UID == pod.UID {
-			pods = append(pods[:i], pods[i+1:]...)
-			break
+	// Neither the pod nor the previous incarnations of its member, e.g. being deleted, are activated.
+	memberKey := util.GetPodGroupMemberKey(pod)
+	siblings := make([]*corev1.Pod, 0, len(pods))
+	for _, p := range pods {
+		if p.UID != pod.UID && p.DeletionTimestamp == nil && util.GetPodGroupMemberKey(p) != memberKey {
+			siblings = append(siblings, p)
 		}
 	}
 
-	if len(pods) != 0 {
+	if len(siblings) != 0 {
 		if c, err := state.Read(framework.PodsToActivateKey); err == nil {
 			if s, ok := c.(*framework.PodsToActivate); ok {
 				s.Lock()
-				for _, pod := range pods {
+				for _, pod := range siblings {
 					namespacedName := GetNamespacedName(pod)
 					s.Map[namespacedName] = pod
 				}
@@ -171,20 +272,30 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) er
 		return nil
 	}
 
+	if err := pgMgr.checkMembership(ctx, pod, pg); err != nil {
+		return err
+	}
+
 	if _, exist := pgMgr.backedOffPG.Get(pgFullName); exist {
 		return fmt.Errorf("podGroup %v failed recently", pgFullName)
 	}
 
-	pods, err := pgMgr.podLister.Pods(pod.Namespace).List(
-		labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: util.GetPodGroupLabel(pod)}),
-	)
+	pods, err := util.ListPodGroupPods(pgMgr.podLister, pg.Namespace, pg.Name, pg.Spec.MemberNamespaces)
 	if err != nil {
 		return fmt.Errorf("podLister list pods failed: %w", err)
 	}
 
-	if len(pods) < int(pg.Spec.MinMember) {
+	// A member recreated by its controller is counted once with the pod it replaces.
+	if members := util.CountPodGroupMembers(pods); members < int(pg.Spec.MinMember) {
 		return fmt.Errorf("pre-filter pod %v cannot find enough sibling pods, "+
-			"current pods number: %v, minMember of group: %v", pod.Name, len(pods), pg.Spec.MinMember)
+			"current pods number: %v, minMember of group: %v", pod.Name, members, pg.Spec.MinMember)
+	}
+
+	if pgMgr.arbiter != nil {
+		pgMgr.arbiter.observe(pgFullName, corev1helpers.PodPriority(pod), pg.CreationTimestamp.Time)
+		if holder, ok := pgMgr.arbiter.admit(pgFullName); !ok {
+			return fmt.Errorf("podGroup %v waits for the freed capacity reserved to podGroup %v", pgFullName, holder)
+		}
 	}
 
 	if pg.Spec.MinResources == nil {
@@ -211,7 +322,7 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) er
 		lh.Error(err, "Failed to PreFilter", "podGroup", klog.KObj(pg))
 		return err
 	}
-	pgMgr.permittedPG.Add(pgFullName, pgFullName, *pgMgr.scheduleTimeout)
+	pgMgr.permittedPG.Add(pgFullName, pgFullName, util.GetWaitTimeDuration(pg, pgMgr.scheduleTimeout))
 	return nil
 }
 
@@ -230,6 +341,10 @@ func (pgMgr *PodGroupManager) Permit(ctx context.Context, state *framework.Cycle
 	// The number of pods that have been assigned nodes is calculated from the snapshot.
 	// The current pod in not included in the snapshot during the current scheduling cycle.
 	if int32(assigned)+1 >= pg.Spec.MinMember {
+		if pgMgr.backoffFailures != nil {
+			pgMgr.backoffFailures.Delete(pgFullName)
+		}
+		pgMgr.forgetFailedAttempts(pgFullName)
 		return Success
 	}
 
@@ -242,12 +357,31 @@ func (pgMgr *PodGroupManager) Permit(ctx context.Context, state *framework.Cycle
 		// its siblings.
 		// It'd be in-efficient if we trigger activating siblings unconditionally.
 		// See https://github.com/kubernetes-sigs/scheduler-plugins/issues/682
+		// A member recreated since it activated its siblings, e.g. after a transient failure, does not activate
+		// them again within the wait time of the podgroup.
+		if pgMgr.activatedByPreviousIncarnation(pgFullName, pod) {
+			return Wait
+		}
 		state.Write(permitStateKey, &PermitState{Activate: true})
+		if pgMgr.activatedMembers != nil {
+			pgMgr.activatedMembers.Set(pgFullName+"/"+util.GetPodGroupMemberKey(pod), pod.UID,
+				util.GetWaitTimeDuration(pg, pgMgr.scheduleTimeout))
+		}
 	}
 
 	return Wait
 }
 
+// activatedByPreviousIncarnation returns whether another pod of the same member of the podgroup
+// recently activated the siblings.
+func (pgMgr *PodGroupManager) activatedByPreviousIncarnation(pgFullName string, pod *corev1.Pod) bool {
+	if pgMgr.activatedMembers == nil {
+		return false
+	}
+	uid, ok := pgMgr.activatedMembers.Get(pgFullName + "/" + util.GetPodGroupMemberKey(pod))
+	return ok && uid != pod.UID
+}
+
 // GetCreationTimestamp returns the creation time of a podGroup or a pod.
 func (pgMgr *PodGroupManager) GetCreationTimestamp(ctx context.Context, pod *corev1.Pod, ts time.Time) time.Time {
 	pgName := util.GetPodGroupLabel(pod)
@@ -255,12 +389,24 @@ func (pgMgr *PodGroupManager) GetCreationTimestamp(ctx context.Context, pod *cor
 		return ts
 	}
 	var pg v1alpha1.PodGroup
-	if err := pgMgr.client.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: pgName}, &pg); err != nil {
+	if err := pgMgr.client.Get(ctx, types.NamespacedName{Namespace: util.GetPodGroupNamespace(pod), Name: pgName}, &pg); err != nil {
 		return ts
 	}
 	return pg.CreationTimestamp.Time
 }
 
+// FlushPodGroup drops the state cached for a deleted podGroup: its permitted and backed off entries,
+// its failed attempts and its admission window, instead of waiting for them to expire.
+func (pgMgr *PodGroupManager) FlushPodGroup(ctx context.Context, pgFullName string) {
+	pgMgr.permittedPG.Delete(pgFullName)
+	pgMgr.backedOffPG.Delete(pgFullName)
+	if pgMgr.backoffFailures != nil {
+		pgMgr.backoffFailures.Delete(pgFullName)
+	}
+	pgMgr.forgetFailedAttempts(pgFullName)
+	pgMgr.ReleaseAdmission(ctx, pgFullName, true, nil)
+}
+
 // DeletePermittedPodGroup deletes a podGroup that passes Pre-Filter but reaches PostFilter.
 func (pgMgr *PodGroupManager) DeletePermittedPodGroup(_ context.Context, pgFullName string) {
 	pgMgr.permittedPG.Delete(pgFullName)
@@ -272,14 +418,26 @@ func (pgMgr *PodGroupManager) GetPodGroup(ctx context.Context, pod *corev1.Pod)
 	if len(pgName) == 0 {
 		return "", nil
 	}
+	namespace := util.GetPodGroupNamespace(pod)
+	var pg v1alpha1.PodGroup
+	if err := pgMgr.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: pgName}, &pg); err != nil {
+		return fmt.Sprintf("%v/%v", namespace, pgName), nil
+	}
+	return fmt.Sprintf("%v/%v", namespace, pgName), &pg
+}
+
+// listPodGroupPods lists the pods of the podGroup namespace/name, including its members of its member namespaces.
+func (pgMgr *PodGroupManager) listPodGroupPods(ctx context.Context, namespace, pgName string) ([]*corev1.Pod, error) {
+	var memberNamespaces []string
 	var pg v1alpha1.PodGroup
-	if err := pgMgr.client.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: pgName}, &pg); err != nil {
-		return fmt.Sprintf("%v/%v", pod.Namespace, pgName), nil
+	if err := pgMgr.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: pgName}, &pg); err == nil {
+		memberNamespaces = pg.Spec.MemberNamespaces
 	}
-	return fmt.Sprintf("%v/%v", pod.Namespace, pgName), &pg
+	return util.ListPodGroupPods(pgMgr.podLister, namespace, pgName, memberNamespaces)
 }
 
-// CalculateAssignedPods returns the number of pods that has been assigned nodes: assumed or bound.
+// CalculateAssignedPods returns the number of members that has been assigned nodes: assumed or bound.
+// A member recreated by its controller while the pod it replaces is still terminating is counted once.
 func (pgMgr *PodGroupManager) CalculateAssignedPods(ctx context.Context, podGroupName, namespace string) int {
 	lh := klog.FromContext(ctx)
 	nodeInfos, err := pgMgr.snapshotSharedLister.NodeInfos().List()
@@ -287,17 +445,17 @@ func (pgMgr *PodGroupManager) CalculateAssignedPods(ctx context.Context, podGrou
 		lh.Error(err, "Cannot get nodeInfos from frameworkHandle")
 		return 0
 	}
-	var count int
+	var assigned []*corev1.Pod
 	for _, nodeInfo := range nodeInfos {
 		for _, podInfo := range nodeInfo.Pods {
 			pod := podInfo.Pod
-			if util.GetPodGroupLabel(pod) == podGroupName && pod.Namespace == namespace && pod.Spec.NodeName != "" {
-				count++
+			if util.GetPodGroupLabel(pod) == podGroupName && util.GetPodGroupNamespace(pod) == namespace && pod.Spec.NodeName != "" {
+				assigned = append(assigned, pod)
 			}
 		}
 	}
 
-	return count
+	return util.CountPodGroupMembers(assigned)
 }
 
 // CheckClusterResource checks if resource capacity of the cluster can satisfy <resourceRequest>.
@@ -324,6 +482,64 @@ func CheckClusterResource(ctx context.Context, nodeList []*framework.NodeInfo, r
 	return fmt.Errorf("resource gap: %v", resourceRequest)
 }
 
+// CheckGangFeasibility re-validates, against the current snapshot, that the nodes the gang members are assumed on
+// can still host them: the pod being permitted on <nodeName>, and the <waitingPods> assumed in previous cycles.
+// It returns an error detailing the first node that can no longer fit the gang; otherwise returns nil.
+func (pgMgr *PodGroupManager) CheckGangFeasibility(ctx context.Context, pod *corev1.Pod, nodeName string, waitingPods []*corev1.Pod) error {
+	lh := klog.FromContext(ctx)
+	// The pod being permitted is not in the snapshot yet, unlike the waiting pods.
+	podRequest := framework.NewResource(resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{}))
+	nodeNames := []string{nodeName}
+	gangRequests := map[string]*framework.Resource{nodeName: podRequest.Clone()}
+	for _, p := range waitingPods {
+		if p.Spec.NodeName == "" {
+			continue
+		}
+		if _, ok := gangRequests[p.Spec.NodeName]; !ok {
+			nodeNames = append(nodeNames, p.Spec.NodeName)
+			gangRequests[p.Spec.NodeName] = &framework.Resource{}
+		}
+		gangRequests[p.Spec.NodeName].Add(resourcehelper.PodRequests(p, resourcehelper.PodResourcesOptions{}))
+	}
+
+	for _, name := range nodeNames {
+		info, err := pgMgr.snapshotSharedLister.NodeInfos().Get(name)
+		if err != nil || info.Node() == nil {
+			return fmt.Errorf("node %v of the gang not found", name)
+		}
+		requested := info.Requested.Clone()
+		podCount := len(info.Pods)
+		if name == nodeName {
+			requested.Add(util.ResourceList(podRequest))
+			podCount++
+		}
+		if podCount > info.Allocatable.AllowedPodNumber {
+			return fmt.Errorf("node %v has too many pods: %v, allowed: %v", name, podCount, info.Allocatable.AllowedPodNumber)
+		}
+		// Only the resources requested by the gang members are checked.
+		gangRequest := util.ResourceList(gangRequests[name])
+		allocatable := util.ResourceList(info.Allocatable)
+		for resourceName, used := range util.ResourceList(requested) {
+			if q := gangRequest[resourceName]; q.IsZero() {
+				continue
+			}
+			available := allocatable[resourceName]
+			if used.Cmp(available) > 0 {
+				lh.V(4).Info("Node can no longer fit the gang", "node", name, "resource", resourceName,
+					"requested", used.String(), "allocatable", available.String())
+				return fmt.Errorf("node %v has insufficient %v: requested %v, allocatable %v",
+					name, resourceName, used.String(), available.String())
+			}
+		}
+	}
+	return nil
+}
+
+// isTerminated returns whether the pod no longer holds resources on its node.
+func isTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
 // GetNamespacedName returns the namespaced name.
 func GetNamespacedName(obj metav1.Object) string {
 	return fmt.Sprintf("%v/%v", obj.GetNamespace(), obj.GetName())
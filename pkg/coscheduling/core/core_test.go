@@ -22,14 +22,17 @@ import (
 	"time"
 
 	gocache "github.com/patrickmn/go-cache"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
 	clicache "k8s.io/client-go/tools/cache"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 	tu "sigs.k8s.io/scheduler-plugins/test/util"
 )
@@ -49,6 +52,7 @@ func TestPreFilter(t *testing.T) {
 		pod             *corev1.Pod
 		pendingPods     []*corev1.Pod
 		pgs             []*v1alpha1.PodGroup
+		arbiterHolder   string
 		expectedSuccess bool
 	}{
 		{
@@ -138,6 +142,44 @@ func TestPreFilter(t *testing.T) {
 			},
 			expectedSuccess: false,
 		},
+		{
+			name: "recreated member counted once",
+			pod:  makeJobPod("job-1", "1"),
+			pendingPods: []*corev1.Pod{
+				makeJobPod("job-0-old", "0"),
+				makeJobPod("job-0", "0"),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).Obj(),
+			},
+			expectedSuccess: false,
+		},
+		{
+			name: "freed capacity reserved to another pg",
+			pod:  st.MakePod().Name("p1a").Namespace("ns").UID("p1a").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			pendingPods: []*corev1.Pod{
+				st.MakePod().Name("p1b").Namespace("ns").UID("p1b").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+				st.MakePod().Name("p1c").Namespace("ns").UID("p1c").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).Obj(),
+			},
+			arbiterHolder:   "ns/pg2",
+			expectedSuccess: false,
+		},
+		{
+			name: "freed capacity reserved to the pod's pg",
+			pod:  st.MakePod().Name("p1a").Namespace("ns").UID("p1a").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			pendingPods: []*corev1.Pod{
+				st.MakePod().Name("p1b").Namespace("ns").UID("p1b").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+				st.MakePod().Name("p1c").Namespace("ns").UID("p1c").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).Obj(),
+			},
+			arbiterHolder:   "ns/pg1",
+			expectedSuccess: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -171,6 +213,11 @@ func TestPreFilter(t *testing.T) {
 				permittedPG:          newCache(),
 				backedOffPG:          newCache(),
 			}
+			if tt.arbiterHolder != "" {
+				pgMgr.arbiter = newGangArbiter(time.Minute, clock.RealClock{})
+				pgMgr.arbiter.observe(tt.arbiterHolder, 1000, time.Now())
+				pgMgr.arbiter.capacityFreed()
+			}
 
 			informerFactory.Start(ctx.Done())
 			if !clicache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
@@ -239,6 +286,26 @@ func TestPermit(t *testing.T) {
 			},
 			want: Success,
 		},
+		{
+			name: "recreated member counted once in the quorum",
+			pod:  makeJobPod("job-1", "1"),
+			existingPods: []*corev1.Pod{
+				func() *corev1.Pod {
+					pod := makeJobPod("job-0-old", "0")
+					pod.Spec.NodeName = "node"
+					return pod
+				}(),
+				func() *corev1.Pod {
+					pod := makeJobPod("job-0", "0")
+					pod.Spec.NodeName = "node"
+					return pod
+				}(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(3).Obj(),
+			},
+			want: Wait,
+		},
 	}
 
 	for _, tt := range tests {
@@ -286,6 +353,67 @@ func TestPermit(t *testing.T) {
 	}
 }
 
+func TestPermitActivateSiblings(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduleTimeout := 10 * time.Second
+	nodes := []*corev1.Node{
+		st.MakeNode().Name("node").Capacity(map[corev1.ResourceName]string{corev1.ResourceCPU: "4"}).Obj(),
+	}
+	member := makeJobPod("job-0", "0")
+	recreated := makeJobPod("job-0-new", "0")
+	deleting := makeJobPod("job-0-old", "0")
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	sibling := makeJobPod("job-1", "1")
+	pg := tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).Obj()
+
+	client, err := tu.NewFakeClient(pg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := clientsetfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	pgMgr := &PodGroupManager{
+		client:               client,
+		snapshotSharedLister: tu.NewFakeSharedLister(nil, nodes),
+		podLister:            podInformer.Lister(),
+		scheduleTimeout:      &scheduleTimeout,
+		activatedMembers:     newCache(),
+	}
+	informerFactory.Start(ctx.Done())
+	if !clicache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
+		t.Fatal("WaitForCacheSync failed")
+	}
+	for _, p := range []*corev1.Pod{member, deleting, sibling} {
+		podInformer.Informer().GetStore().Add(p)
+	}
+
+	permit := func(pod *corev1.Pod) map[string]*corev1.Pod {
+		state := framework.NewCycleState()
+		state.Write(framework.PodsToActivateKey, framework.NewPodsToActivate())
+		if got := pgMgr.Permit(ctx, state, pod); got != Wait {
+			t.Fatalf("Want %v, but got %v", Wait, got)
+		}
+		pgMgr.ActivateSiblings(ctx, pod, state)
+		c, _ := state.Read(framework.PodsToActivateKey)
+		return c.(*framework.PodsToActivate).Map
+	}
+
+	// Neither the previous incarnation of the member being deleted nor the member itself are activated.
+	if got := permit(member); len(got) != 1 || got["ns/job-1"] == nil {
+		t.Errorf("expected only ns/job-1 to be activated, got %v", got)
+	}
+	// The recreated member does not activate its siblings again.
+	if got := permit(recreated); len(got) != 0 {
+		t.Errorf("expected no pod to be activated by the recreated member, got %v", got)
+	}
+	// The same pod activates its siblings at every attempt.
+	if got := permit(member); len(got) != 1 {
+		t.Errorf("expected ns/job-1 to be activated again, got %v", got)
+	}
+}
+
 func TestCheckClusterResource(t *testing.T) {
 	capacity := map[corev1.ResourceName]string{
 		corev1.ResourceCPU: "3",
@@ -357,6 +485,118 @@ func TestCheckClusterResource(t *testing.T) {
 	}
 }
 
+func TestCheckGangFeasibility(t *testing.T) {
+	capacity := map[corev1.ResourceName]string{
+		corev1.ResourceCPU: "4",
+	}
+	nodes := []*corev1.Node{
+		st.MakeNode().Name("node-a").Capacity(capacity).Obj(),
+		st.MakeNode().Name("node-b").Capacity(capacity).Obj(),
+	}
+	member := func(name, node, cpu string) *corev1.Pod {
+		return st.MakePod().Name(name).Namespace("ns").UID(name).Label(v1alpha1.PodGroupLabel, "pg1").Node(node).
+			Req(map[corev1.ResourceName]string{corev1.ResourceCPU: cpu}).Obj()
+	}
+
+	tests := []struct {
+		name         string
+		existingPods []*corev1.Pod
+		pod          *corev1.Pod
+		nodeName     string
+		waitingPods  []*corev1.Pod
+		want         bool
+	}{
+		{
+			name:         "gang still fits",
+			existingPods: []*corev1.Pod{member("p1", "node-b", "2")},
+			pod:          member("p2", "", "2"),
+			nodeName:     "node-a",
+			waitingPods:  []*corev1.Pod{member("p1", "node-b", "2")},
+			want:         true,
+		},
+		{
+			name: "node of the pod being permitted got filled",
+			existingPods: []*corev1.Pod{
+				st.MakePod().Name("other").Namespace("ns").UID("other").Node("node-a").
+					Req(map[corev1.ResourceName]string{corev1.ResourceCPU: "3"}).Obj(),
+			},
+			pod:      member("p2", "", "2"),
+			nodeName: "node-a",
+			want:     false,
+		},
+		{
+			name: "node of a waiting pod got overcommitted",
+			existingPods: []*corev1.Pod{
+				member("p1", "node-b", "2"),
+				st.MakePod().Name("other").Namespace("ns").UID("other").Node("node-b").
+					Req(map[corev1.ResourceName]string{corev1.ResourceCPU: "3"}).Obj(),
+			},
+			pod:         member("p2", "", "2"),
+			nodeName:    "node-a",
+			waitingPods: []*corev1.Pod{member("p1", "node-b", "2")},
+			want:        false,
+		},
+		{
+			name:        "node of a waiting pod got deleted",
+			pod:         member("p2", "", "2"),
+			nodeName:    "node-a",
+			waitingPods: []*corev1.Pod{member("p1", "node-c", "2")},
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgMgr := &PodGroupManager{snapshotSharedLister: tu.NewFakeSharedLister(tt.existingPods, nodes)}
+			err := pgMgr.CheckGangFeasibility(context.Background(), tt.pod, tt.nodeName, tt.waitingPods)
+			if (err == nil) != tt.want {
+				t.Errorf("Expect the gang to be feasible: %v, but got %v", tt.want, err)
+			}
+		})
+	}
+}
+
 func newCache() *gocache.Cache {
 	return gocache.New(10*time.Second, 10*time.Second)
 }
+
+func TestFlushPodGroup(t *testing.T) {
+	ctx := context.Background()
+	pgMgr := &PodGroupManager{
+		permittedPG: newCache(),
+		backedOffPG: newCache(),
+		arbiter:     newGangArbiter(time.Minute, clock.RealClock{}),
+	}
+	for _, name := range []string{"ns1/pg1", "ns1/pg2"} {
+		pgMgr.permittedPG.Add(name, name, time.Minute)
+		pgMgr.BackoffPodGroup(name, nil, time.Minute)
+		pgMgr.arbiter.observe(name, 0, time.Now())
+	}
+	pgMgr.arbiter.capacityFreed()
+
+	pgMgr.FlushPodGroup(ctx, "ns1/pg1")
+
+	if _, ok := pgMgr.permittedPG.Get("ns1/pg1"); ok {
+		t.Error("expected ns1/pg1 to be flushed from the permitted PodGroups")
+	}
+	if _, ok := pgMgr.backedOffPG.Get("ns1/pg1"); ok {
+		t.Error("expected ns1/pg1 to be flushed from the backed off PodGroups")
+	}
+	if _, ok := pgMgr.arbiter.candidates["ns1/pg1"]; ok {
+		t.Error("expected ns1/pg1 to be flushed from the gangs waiting for capacity")
+	}
+	if _, ok := pgMgr.permittedPG.Get("ns1/pg2"); !ok {
+		t.Error("expected ns1/pg2 to stay permitted")
+	}
+	if _, ok := pgMgr.backedOffPG.Get("ns1/pg2"); !ok {
+		t.Error("expected ns1/pg2 to stay backed off")
+	}
+}
+
+// makeJobPod returns a member of pg1 created by the indexed Job "job" with the completion index.
+func makeJobPod(name, index string) *corev1.Pod {
+	pod := st.MakePod().Name(name).Namespace("ns").UID(name).Label(v1alpha1.PodGroupLabel, "pg1").
+		Label(batchv1.JobCompletionIndexAnnotation, index).OwnerReference("job", batchv1.SchemeGroupVersion.WithKind("Job")).Obj()
+	pod.OwnerReferences[0].UID = "job"
+	return pod
+}
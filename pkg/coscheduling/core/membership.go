@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+const (
+	// MemberAccessVerb is the verb the service account of a pod must be allowed on the PodGroup of another
+	// namespace for the pod to be one of its members.
+	MemberAccessVerb = "use"
+
+	// memberAccessTTL is how long the access of a service account to a PodGroup is cached.
+	memberAccessTTL = time.Minute
+)
+
+// checkMembership checks that a pod of another namespace than its podGroup may be one of its members: the
+// namespace of the pod is one of the member namespaces of the podGroup, and the service account of the pod
+// is allowed to use the podGroup. Otherwise, the pods of any namespace could join the podGroup and hold
+// back or complete its gang.
+func (pgMgr *PodGroupManager) checkMembership(ctx context.Context, pod *corev1.Pod, pg *v1alpha1.PodGroup) error {
+	if pod.Namespace == pg.Namespace {
+		return nil
+	}
+	if !slices.Contains(pg.Spec.MemberNamespaces, pod.Namespace) {
+		return fmt.Errorf("namespace %v of pod %v is not a member namespace of podGroup %v",
+			pod.Namespace, pod.Name, GetNamespacedName(pg))
+	}
+
+	serviceAccount := pod.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	user := fmt.Sprintf("system:serviceaccount:%v:%v", pod.Namespace, serviceAccount)
+	key := user + "/" + GetNamespacedName(pg)
+	if pgMgr.memberAccess != nil {
+		if allowed, ok := pgMgr.memberAccess.Get(key); ok {
+			if !allowed.(bool) {
+				return fmt.Errorf("%v is not allowed to %v podGroup %v", user, MemberAccessVerb, GetNamespacedName(pg))
+			}
+			return nil
+		}
+	}
+
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user,
+			Groups: []string{"system:serviceaccounts", "system:serviceaccounts:" + pod.Namespace},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: pg.Namespace,
+				Verb:      MemberAccessVerb,
+				Group:     v1alpha1.SchemeGroupVersion.Group,
+				Resource:  "podgroups",
+				Name:      pg.Name,
+			},
+		},
+	}
+	if err := pgMgr.client.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to review the access of %v to podGroup %v: %w", user, GetNamespacedName(pg), err)
+	}
+	if pgMgr.memberAccess != nil {
+		pgMgr.memberAccess.Set(key, review.Status.Allowed, memberAccessTTL)
+	}
+	if !review.Status.Allowed {
+		klog.FromContext(ctx).V(4).Info("Pod not allowed to join a PodGroup of another namespace",
+			"pod", klog.KObj(pod), "podGroup", klog.KObj(pg), "user", user, "reason", review.Status.Reason)
+		return fmt.Errorf("%v is not allowed to %v podGroup %v", user, MemberAccessVerb, GetNamespacedName(pg))
+	}
+	return nil
+}
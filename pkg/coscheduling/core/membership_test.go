@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	clientscheme "k8s.io/client-go/kubernetes/scheme"
+	clicache "k8s.io/client-go/tools/cache"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// newReviewingClient returns a fake client answering the SubjectAccessReviews from the allowed users, and
+// counting them.
+func newReviewingClient(t *testing.T, allowed sets.Set[string], reviews *int, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := clientscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			review, ok := obj.(*authorizationv1.SubjectAccessReview)
+			if !ok {
+				return c.Create(ctx, obj, opts...)
+			}
+			*reviews++
+			attrs := review.Spec.ResourceAttributes
+			review.Status.Allowed = allowed.Has(review.Spec.User) && attrs.Verb == MemberAccessVerb &&
+				attrs.Resource == "podgroups" && attrs.Namespace == "producer" && attrs.Name == "pipeline"
+			return nil
+		},
+	}).Build()
+}
+
+func TestCheckMembership(t *testing.T) {
+	pg := &v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pipeline", Namespace: "producer"},
+		Spec:       v1alpha1.PodGroupSpec{MinMember: 2, MemberNamespaces: []string{"consumer"}},
+	}
+	member := func(namespace, serviceAccount string) *corev1.Pod {
+		pod := st.MakePod().Name("p").Namespace(namespace).Label(v1alpha1.PodGroupLabel, "pipeline").
+			Label(v1alpha1.PodGroupNamespaceLabel, "producer").Obj()
+		pod.Spec.ServiceAccountName = serviceAccount
+		return pod
+	}
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		wantErr     bool
+		wantReviews int
+	}{
+		{
+			name: "pod of the namespace of the pod group",
+			pod:  st.MakePod().Name("p").Namespace("producer").Label(v1alpha1.PodGroupLabel, "pipeline").Obj(),
+		},
+		{
+			name:        "service account allowed to use the pod group",
+			pod:         member("consumer", "pipeline"),
+			wantReviews: 1,
+		},
+		{
+			name:        "service account not allowed to use the pod group",
+			pod:         member("consumer", ""),
+			wantErr:     true,
+			wantReviews: 1,
+		},
+		{
+			name:    "namespace not a member namespace",
+			pod:     member("other", "pipeline"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviews := 0
+			pgMgr := &PodGroupManager{
+				client: newReviewingClient(t, sets.New("system:serviceaccount:consumer:pipeline",
+					"system:serviceaccount:other:pipeline"), &reviews),
+				memberAccess: newCache(),
+			}
+			// The access is reviewed once, then cached.
+			for i := 0; i < 2; i++ {
+				if err := pgMgr.checkMembership(context.Background(), tt.pod, pg); (err != nil) != tt.wantErr {
+					t.Fatalf("want error %v, got %v", tt.wantErr, err)
+				}
+			}
+			if reviews != tt.wantReviews {
+				t.Errorf("want %v reviews, got %v", tt.wantReviews, reviews)
+			}
+		})
+	}
+}
+
+func TestPreFilterMemberNamespaces(t *testing.T) {
+	scheduleTimeout := 10 * time.Second
+	pg := &v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pipeline", Namespace: "producer"},
+		Spec:       v1alpha1.PodGroupSpec{MinMember: 3, MemberNamespaces: []string{"consumer"}},
+	}
+	consumer := st.MakePod().Name("consumer-0").Namespace("consumer").UID("consumer-0").
+		Label(v1alpha1.PodGroupLabel, "pipeline").Label(v1alpha1.PodGroupNamespaceLabel, "producer").Obj()
+	pods := []*corev1.Pod{
+		consumer,
+		st.MakePod().Name("producer-0").Namespace("producer").UID("producer-0").Label(v1alpha1.PodGroupLabel, "pipeline").Obj(),
+		// A pod of another namespace than the member namespaces is not counted.
+		st.MakePod().Name("other-0").Namespace("other").UID("other-0").
+			Label(v1alpha1.PodGroupLabel, "pipeline").Label(v1alpha1.PodGroupNamespaceLabel, "producer").Obj(),
+	}
+	tests := []struct {
+		name            string
+		extraPod        *corev1.Pod
+		expectedSuccess bool
+	}{
+		{
+			name:            "not enough members in the namespaces of the pg",
+			expectedSuccess: false,
+		},
+		{
+			name: "enough members in the namespaces of the pg",
+			extraPod: st.MakePod().Name("consumer-1").Namespace("consumer").UID("consumer-1").
+				Label(v1alpha1.PodGroupLabel, "pipeline").Label(v1alpha1.PodGroupNamespaceLabel, "producer").Obj(),
+			expectedSuccess: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			reviews := 0
+			cs := clientsetfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			pgMgr := &PodGroupManager{
+				client:          newReviewingClient(t, sets.New("system:serviceaccount:consumer:default"), &reviews, pg.DeepCopy()),
+				podLister:       podInformer.Lister(),
+				scheduleTimeout: &scheduleTimeout,
+				permittedPG:     newCache(),
+				backedOffPG:     newCache(),
+				memberAccess:    newCache(),
+			}
+
+			informerFactory.Start(ctx.Done())
+			if !clicache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
+				t.Fatal("WaitForCacheSync failed")
+			}
+			for _, p := range pods {
+				podInformer.Informer().GetStore().Add(p)
+			}
+			if tt.extraPod != nil {
+				podInformer.Informer().GetStore().Add(tt.extraPod)
+			}
+
+			err := pgMgr.PreFilter(ctx, consumer)
+			if (err == nil) != tt.expectedSuccess {
+				t.Errorf("Want %v, but got %v", tt.expectedSuccess, err)
+			}
+		})
+	}
+}
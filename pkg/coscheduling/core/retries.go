@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+// EnableRetryPenalty delays the podgroups in the scheduling queue by the given penalty for each of their recent
+// failed attempts, so that a gang failing over and over doesn't stay ahead of the gangs of the same priority.
+func (pgMgr *PodGroupManager) EnableRetryPenalty(penalty time.Duration) {
+	pgMgr.retryPenalty = penalty
+	pgMgr.failedAttempts = gocache.New(penalty, penalty)
+}
+
+// RecordFailedAttempt counts a failed attempt to schedule the podgroup, if the retry penalty is enabled.
+// The failed attempts are forgotten once the podgroup gets no failure for twice its penalty.
+func (pgMgr *PodGroupManager) RecordFailedAttempt(pgFullName string) {
+	if pgMgr.failedAttempts == nil {
+		return
+	}
+	pgMgr.Lock()
+	defer pgMgr.Unlock()
+	attempts := 1
+	if n, ok := pgMgr.failedAttempts.Get(pgFullName); ok {
+		attempts = n.(int) + 1
+	}
+	pgMgr.failedAttempts.Set(pgFullName, attempts, 2*time.Duration(attempts)*pgMgr.retryPenalty)
+}
+
+// GetFailedAttempts returns the number of recent failed attempts to schedule the podgroup.
+func (pgMgr *PodGroupManager) GetFailedAttempts(pgFullName string) int {
+	if pgMgr.failedAttempts == nil {
+		return 0
+	}
+	pgMgr.RLock()
+	defer pgMgr.RUnlock()
+	if n, ok := pgMgr.failedAttempts.Get(pgFullName); ok {
+		return n.(int)
+	}
+	return 0
+}
+
+// GetRetryPenalty returns the time the podgroup of a pod is delayed by in the scheduling queue for its recent
+// failed attempts, zero for the pods without podgroup.
+func (pgMgr *PodGroupManager) GetRetryPenalty(pod *corev1.Pod) time.Duration {
+	pgFullName := util.GetPodGroupFullName(pod)
+	if pgFullName == "" {
+		return 0
+	}
+	return time.Duration(pgMgr.GetFailedAttempts(pgFullName)) * pgMgr.retryPenalty
+}
+
+// forgetFailedAttempts forgets the failed attempts of the podgroup, once admitted or deleted.
+func (pgMgr *PodGroupManager) forgetFailedAttempts(pgFullName string) {
+	if pgMgr.failedAttempts != nil {
+		pgMgr.failedAttempts.Delete(pgFullName)
+	}
+}
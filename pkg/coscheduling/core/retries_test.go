@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestRetryPenalty(t *testing.T) {
+	pod := st.MakePod().Name("p1").Namespace("ns1").Label(v1alpha1.PodGroupLabel, "pg1").Obj()
+	other := st.MakePod().Name("p2").Namespace("ns1").Label(v1alpha1.PodGroupLabel, "pg2").Obj()
+	single := st.MakePod().Name("p3").Namespace("ns1").Obj()
+
+	disabled := &PodGroupManager{}
+	disabled.RecordFailedAttempt("ns1/pg1")
+	if got := disabled.GetRetryPenalty(pod); got != 0 {
+		t.Errorf("expected no penalty when disabled, got %v", got)
+	}
+
+	pgMgr := &PodGroupManager{permittedPG: newCache(), backedOffPG: newCache()}
+	pgMgr.EnableRetryPenalty(10 * time.Second)
+	for i := 0; i < 3; i++ {
+		pgMgr.RecordFailedAttempt("ns1/pg1")
+	}
+	pgMgr.RecordFailedAttempt("ns1/pg2")
+	if got, want := pgMgr.GetRetryPenalty(pod), 30*time.Second; got != want {
+		t.Errorf("expected the penalty %v after 3 failed attempts, got %v", want, got)
+	}
+	if got, want := pgMgr.GetRetryPenalty(other), 10*time.Second; got != want {
+		t.Errorf("expected the penalty %v after a failed attempt, got %v", want, got)
+	}
+	if got := pgMgr.GetRetryPenalty(single); got != 0 {
+		t.Errorf("expected no penalty for a pod without podgroup, got %v", got)
+	}
+
+	pgMgr.FlushPodGroup(context.Background(), "ns1/pg1")
+	if got := pgMgr.GetFailedAttempts("ns1/pg1"); got != 0 {
+		t.Errorf("expected the failed attempts of a flushed podgroup to be forgotten, got %v", got)
+	}
+	if got := pgMgr.GetFailedAttempts("ns1/pg2"); got != 1 {
+		t.Errorf("expected the failed attempts of other podgroups to be kept, got %v", got)
+	}
+}
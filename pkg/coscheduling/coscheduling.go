@@ -19,12 +19,19 @@ package coscheduling
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	clientscheme "k8s.io/client-go/kubernetes/scheme"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1"
 	"k8s.io/client-go/tools/cache"
 	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
 	"k8s.io/klog/v2"
@@ -41,6 +48,7 @@ import (
 	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling"
 	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/coscheduling/core"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/coscheduling/metrics"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
 )
 
@@ -50,6 +58,51 @@ type Coscheduling struct {
 	pgMgr            core.Manager
 	scheduleTimeout  *time.Duration
 	pgBackoff        *time.Duration
+	// progressTimers stores the progress deadline timer of pod groups having members waiting in Permit.
+	progressTimers map[string]*time.Timer
+	progressLock   sync.Mutex
+	// bindHints stores the binding hint shared by the members of pod groups waiting in Permit.
+	bindHints    map[string]*GangBindHint
+	bindHintLock sync.Mutex
+	// maxWaitingPodGroups is the maximum number of pod groups per namespace waiting in Permit, if not zero.
+	maxWaitingPodGroups int64
+	// heldBack stores, per namespace, the pod groups rejected in PreFilter by the waiting budget.
+	heldBack   map[string]sets.Set[string]
+	budgetLock sync.Mutex
+	// waitingStatusInterval is the interval at which the members waiting in Permit are reported
+	// in the status of their pod group, if not zero.
+	waitingStatusInterval time.Duration
+	// waitingDeadlines stores the Permit deadline of the pods waiting in Permit.
+	waitingDeadlines map[types.UID]time.Time
+	waitingLock      sync.Mutex
+	// reportedWaiting stores the waiting and blocking members last reported in the status of each pod group.
+	// It is only accessed by the goroutine reporting them.
+	reportedWaiting map[string]waitingReport
+	// rejections stores the last rejection of the members of pod groups, if the waiting members are reported.
+	rejections    map[types.UID]memberRejection
+	rejectionLock sync.Mutex
+	// gangPreemption makes the PodGroups that can't fit preempt lower-priority PodGroups as a whole.
+	gangPreemption bool
+	pdbLister      policylisters.PodDisruptionBudgetLister
+	// checkpointTimeout is the time given to acknowledge the checkpoint of the members of a pod group
+	// before they are preempted or their pod group is released, if not zero.
+	checkpointTimeout    time.Duration
+	checkpointWebhookURL string
+	// pendingPreemptions stores the node the preemptor pod groups are nominated on while their victims are checkpointed.
+	pendingPreemptions map[string]string
+	// checkpointing stores the victim pod groups being checkpointed.
+	checkpointing  sets.Set[string]
+	checkpointLock sync.Mutex
+	// scaleUpExtension is how long the Permit wait of pod groups is extended by while the cluster scales up
+	// for them, if not zero.
+	scaleUpExtension time.Duration
+	nodeLister       corelisters.NodeLister
+	// scaleUps stores when the cluster autoscaler last triggered a scale-up for the members of pod groups.
+	scaleUps map[string]time.Time
+	// permitTimers stores the Permit deadline timer of pod groups having members waiting in Permit.
+	permitTimers map[string]*time.Timer
+	scaleUpLock  sync.Mutex
+	client       client.Client
 }
 
 var _ framework.QueueSortPlugin = &Coscheduling{}
@@ -57,12 +110,17 @@ var _ framework.PreFilterPlugin = &Coscheduling{}
 var _ framework.PostFilterPlugin = &Coscheduling{}
 var _ framework.PermitPlugin = &Coscheduling{}
 var _ framework.ReservePlugin = &Coscheduling{}
+var _ framework.PreBindPlugin = &Coscheduling{}
 
 var _ framework.EnqueueExtensions = &Coscheduling{}
 
 const (
 	// Name is the name of the plugin used in Registry and configurations.
 	Name = "Coscheduling"
+
+	// ProgressDeadlineExceeded is the reason of the event recorded when a PodGroup is released
+	// because of its progress deadline.
+	ProgressDeadlineExceeded = "ProgressDeadlineExceeded"
 )
 
 // New initializes and returns a new Coscheduling plugin.
@@ -70,6 +128,7 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 
 	lh := klog.FromContext(ctx)
 	lh.V(5).Info("creating new coscheduling plugin")
+	metrics.Register()
 
 	args, ok := obj.(*config.CoschedulingArgs)
 	if !ok {
@@ -100,6 +159,7 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		frameworkHandler: handle,
 		pgMgr:            pgMgr,
 		scheduleTimeout:  &scheduleTimeDuration,
+		client:           client,
 	}
 	if args.PodGroupBackoffSeconds < 0 {
 		err := fmt.Errorf("parse arguments failed")
@@ -109,6 +169,68 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		pgBackoff := time.Duration(args.PodGroupBackoffSeconds) * time.Second
 		plugin.pgBackoff = &pgBackoff
 	}
+	if args.GangAdmissionWindowSeconds < 0 {
+		err := fmt.Errorf("parse arguments failed")
+		lh.Error(err, "GangAdmissionWindowSeconds cannot be negative")
+		return nil, err
+	} else if args.GangAdmissionWindowSeconds > 0 {
+		window := time.Duration(args.GangAdmissionWindowSeconds) * time.Second
+		if err := pgMgr.EnableGangArbiter(window, handle.SharedInformerFactory().Core().V1().Pods()); err != nil {
+			return nil, err
+		}
+	}
+	if args.MaxWaitingPodGroupsPerNamespace < 0 {
+		err := fmt.Errorf("parse arguments failed")
+		lh.Error(err, "MaxWaitingPodGroupsPerNamespace cannot be negative")
+		return nil, err
+	}
+	plugin.maxWaitingPodGroups = args.MaxWaitingPodGroupsPerNamespace
+	if args.GangPreemption {
+		plugin.gangPreemption = true
+		plugin.pdbLister = handle.SharedInformerFactory().Policy().V1().PodDisruptionBudgets().Lister()
+	}
+	if args.WaitingMembersStatusIntervalSeconds < 0 {
+		err := fmt.Errorf("parse arguments failed")
+		lh.Error(err, "WaitingMembersStatusIntervalSeconds cannot be negative")
+		return nil, err
+	} else if args.WaitingMembersStatusIntervalSeconds > 0 {
+		plugin.waitingStatusInterval = time.Duration(args.WaitingMembersStatusIntervalSeconds) * time.Second
+		go wait.UntilWithContext(ctx, plugin.syncWaitingMembers, plugin.waitingStatusInterval)
+	}
+	if args.CheckpointTimeoutSeconds < 0 {
+		err := fmt.Errorf("parse arguments failed")
+		lh.Error(err, "CheckpointTimeoutSeconds cannot be negative")
+		return nil, err
+	} else if args.CheckpointTimeoutSeconds > 0 {
+		plugin.checkpointTimeout = time.Duration(args.CheckpointTimeoutSeconds) * time.Second
+		plugin.checkpointWebhookURL = args.CheckpointWebhookURL
+		plugin.pendingPreemptions = make(map[string]string)
+		plugin.checkpointing = sets.New[string]()
+	}
+	if args.RetryPenaltySeconds < 0 {
+		err := fmt.Errorf("parse arguments failed")
+		lh.Error(err, "RetryPenaltySeconds cannot be negative")
+		return nil, err
+	} else if args.RetryPenaltySeconds > 0 {
+		pgMgr.EnableRetryPenalty(time.Duration(args.RetryPenaltySeconds) * time.Second)
+	}
+	if args.ScaleUpPermitExtensionSeconds < 0 {
+		err := fmt.Errorf("parse arguments failed")
+		lh.Error(err, "ScaleUpPermitExtensionSeconds cannot be negative")
+		return nil, err
+	} else if args.ScaleUpPermitExtensionSeconds > 0 {
+		plugin.scaleUpExtension = time.Duration(args.ScaleUpPermitExtensionSeconds) * time.Second
+		plugin.nodeLister = handle.SharedInformerFactory().Core().V1().Nodes().Lister()
+		if err := plugin.watchScaleUps(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if args.FlushDeletedPodGroups {
+		if err := plugin.watchPodGroupDeletions(ctx, scheme); err != nil {
+			return nil, err
+		}
+	}
 	return plugin, nil
 }
 
@@ -130,7 +252,8 @@ func (cs *Coscheduling) Name() string {
 
 // Less is used to sort pods in the scheduling queue in the following order.
 // 1. Compare the priorities of Pods.
-// 2. Compare the initialization timestamps of PodGroups or Pods.
+// 2. Compare the initialization timestamps of PodGroups or Pods, delayed by the retry penalty of the PodGroups
+// for their recent failed attempts, if enabled.
 // 3. Compare the keys of PodGroups/Pods: <namespace>/<podname>.
 func (cs *Coscheduling) Less(podInfo1, podInfo2 *framework.QueuedPodInfo) bool {
 	prio1 := corev1helpers.PodPriority(podInfo1.Pod)
@@ -138,8 +261,10 @@ func (cs *Coscheduling) Less(podInfo1, podInfo2 *framework.QueuedPodInfo) bool {
 	if prio1 != prio2 {
 		return prio1 > prio2
 	}
-	creationTime1 := cs.pgMgr.GetCreationTimestamp(context.TODO(), podInfo1.Pod, *podInfo1.InitialAttemptTimestamp)
-	creationTime2 := cs.pgMgr.GetCreationTimestamp(context.TODO(), podInfo2.Pod, *podInfo2.InitialAttemptTimestamp)
+	creationTime1 := cs.pgMgr.GetCreationTimestamp(context.TODO(), podInfo1.Pod, *podInfo1.InitialAttemptTimestamp).
+		Add(cs.pgMgr.GetRetryPenalty(podInfo1.Pod))
+	creationTime2 := cs.pgMgr.GetCreationTimestamp(context.TODO(), podInfo2.Pod, *podInfo2.InitialAttemptTimestamp).
+		Add(cs.pgMgr.GetRetryPenalty(podInfo2.Pod))
 	if creationTime1.Equal(creationTime2) {
 		return core.GetNamespacedName(podInfo1.Pod) < core.GetNamespacedName(podInfo2.Pod)
 	}
@@ -147,10 +272,14 @@ func (cs *Coscheduling) Less(podInfo1, podInfo2 *framework.QueuedPodInfo) bool {
 }
 
 // PreFilter performs the following validations.
-// 1. Whether the PodGroup that the Pod belongs to is on the deny list.
-// 2. Whether the total number of pods in a PodGroup is less than its `minMember`.
+// 1. Whether the namespace of the Pod has room for one more PodGroup waiting in Permit.
+// 2. Whether the PodGroup that the Pod belongs to is on the deny list.
+// 3. Whether the total number of pods in a PodGroup is less than its `minMember`.
 func (cs *Coscheduling) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
 	lh := klog.FromContext(ctx)
+	if status := cs.checkWaitingBudget(ctx, state, pod); status != nil {
+		return nil, status
+	}
 	// If PreFilter fails, return framework.UnschedulableAndUnresolvable to avoid
 	// any preemption attempts.
 	if err := cs.pgMgr.PreFilter(ctx, pod); err != nil {
@@ -161,6 +290,7 @@ func (cs *Coscheduling) PreFilter(ctx context.Context, state *framework.CycleSta
 }
 
 // PostFilter is used to reject a group of pods if a pod does not pass PreFilter or Filter.
+// With gang preemption, lower-priority PodGroups are first preempted as a whole to make room for the group.
 func (cs *Coscheduling) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod,
 	filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
 	lh := klog.FromContext(ctx)
@@ -169,10 +299,18 @@ func (cs *Coscheduling) PostFilter(ctx context.Context, state *framework.CycleSt
 		lh.V(4).Info("Pod does not belong to any group", "pod", klog.KObj(pod))
 		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable, "can not find pod group")
 	}
+	cs.recordRejection(pod, filteredNodeStatusMap)
+
+	// The PodGroup is held back by the waiting budget of its namespace: it has no member waiting in Permit,
+	// and it is activated again once a PodGroup of the namespace leaves Permit, not by its own rejection.
+	if isHeldBack(state) {
+		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("PodGroup %v is held back by the waiting budget of its namespace", pgName))
+	}
 
 	// This indicates there are already enough Pods satisfying the PodGroup,
 	// so don't bother to reject the whole PodGroup.
-	assigned := cs.pgMgr.CalculateAssignedPods(ctx, pg.Name, pod.Namespace)
+	assigned := cs.pgMgr.CalculateAssignedPods(ctx, pg.Name, pg.Namespace)
 	if assigned >= int(pg.Spec.MinMember) {
 		lh.V(4).Info("Assigned pods", "podGroup", klog.KObj(pg), "assigned", assigned)
 		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable)
@@ -186,25 +324,45 @@ func (cs *Coscheduling) PostFilter(ctx context.Context, state *framework.CycleSt
 		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable)
 	}
 
+	if cs.gangPreemption {
+		if result, status := cs.preemptPodGroups(ctx, state, pod, pgName, pg, assigned, filteredNodeStatusMap); result != nil || status != nil {
+			return result, status
+		}
+	}
+
 	// It's based on an implicit assumption: if the nth Pod failed,
 	// it's inferrable other Pods belonging to the same PodGroup would be very likely to fail.
+	// The waiting pods are rejected once iterated over, not to take the waiting lock within the iteration.
+	var waitingPods []framework.WaitingPod
 	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
-		if waitingPod.GetPod().Namespace == pod.Namespace && util.GetPodGroupLabel(waitingPod.GetPod()) == pg.Name {
-			lh.V(3).Info("PostFilter rejects the pod", "podGroup", klog.KObj(pg), "pod", klog.KObj(waitingPod.GetPod()))
-			waitingPod.Reject(cs.Name(), "optimistic rejection in PostFilter")
+		if util.GetPodGroupFullName(waitingPod.GetPod()) == pgName {
+			waitingPods = append(waitingPods, waitingPod)
 		}
 	})
+	for _, waitingPod := range waitingPods {
+		lh.V(3).Info("PostFilter rejects the pod", "podGroup", klog.KObj(pg), "pod", klog.KObj(waitingPod.GetPod()))
+		cs.forgetWaitingDeadline(waitingPod.GetPod())
+		waitingPod.Reject(cs.Name(), "optimistic rejection in PostFilter")
+	}
 
-	if cs.pgBackoff != nil {
-		pods, err := cs.frameworkHandler.SharedInformerFactory().Core().V1().Pods().Lister().Pods(pod.Namespace).List(
-			labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: util.GetPodGroupLabel(pod)}),
-		)
-		if err == nil && len(pods) >= int(pg.Spec.MinMember) {
-			cs.pgMgr.BackoffPodGroup(pgName, *cs.pgBackoff)
+	if cs.pgBackoff != nil || pg.Spec.Backoff != nil {
+		pods, err := util.ListPodGroupPods(cs.frameworkHandler.SharedInformerFactory().Core().V1().Pods().Lister(),
+			pg.Namespace, pg.Name, pg.Spec.MemberNamespaces)
+		if err == nil && util.CountPodGroupMembers(pods) >= int(pg.Spec.MinMember) {
+			var backoff time.Duration
+			if cs.pgBackoff != nil {
+				backoff = *cs.pgBackoff
+			}
+			cs.pgMgr.BackoffPodGroup(pgName, pg, backoff)
 		}
 	}
 
+	cs.stopProgressDeadline(pgName)
+	cs.stopPermitDeadline(pgName)
 	cs.pgMgr.DeletePermittedPodGroup(ctx, pgName)
+	cs.pgMgr.ReleaseAdmission(ctx, pgName, false, state)
+	cs.releaseWaitingBudget(ctx, pg.Namespace, state)
+	cs.countGangRejection(pgName, metrics.RejectionUnschedulable)
 	return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable,
 		fmt.Sprintf("PodGroup %v gets rejected due to Pod %v is unschedulable even after PostFilter", pgName, pod.Name))
 }
@@ -232,16 +390,61 @@ func (cs *Coscheduling) Permit(ctx context.Context, state *framework.CycleState,
 			waitTime = wait
 		}
 		retStatus = framework.NewStatus(framework.Wait)
+		cs.recordWaitingDeadline(pod, waitTime)
+		if cs.scaleUpExtension > 0 {
+			// The plugin rejects the members at their deadline unless the cluster scales up for them:
+			// leave the framework waiting for the extension.
+			cs.startPermitDeadline(ctx, util.GetPodGroupFullName(pod), pg, waitTime)
+			waitTime += cs.scaleUpExtension
+		}
+		state.Write(GangBindHintKey, cs.gangBindHint(util.GetPodGroupFullName(pod)))
 		// We will also request to move the sibling pods back to activeQ.
 		cs.pgMgr.ActivateSiblings(ctx, pod, state)
+		cs.startProgressDeadline(ctx, pod, pg)
 	case core.Success:
 		pgFullName := util.GetPodGroupFullName(pod)
+		cs.stopProgressDeadline(pgFullName)
+		cs.stopPermitDeadline(pgFullName)
+		var waitingPods []framework.WaitingPod
+		var siblings []*v1.Pod
 		cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
 			if util.GetPodGroupFullName(waitingPod.GetPod()) == pgFullName {
-				lh.V(3).Info("Permit allows", "pod", klog.KObj(waitingPod.GetPod()))
-				waitingPod.Allow(cs.Name())
+				waitingPods = append(waitingPods, waitingPod)
+				siblings = append(siblings, waitingPod.GetPod())
 			}
 		})
+		// The capacity may have changed while the members were waiting: reject the whole gang
+		// now rather than letting its binds fail one by one.
+		if err := cs.pgMgr.CheckGangFeasibility(ctx, pod, nodeName, siblings); err != nil {
+			msg := fmt.Sprintf("PodGroup %v can no longer fit at Permit: %v", pgFullName, err)
+			lh.V(3).Info("Permit rejects the PodGroup", "podGroup", pgFullName, "pod", klog.KObj(pod), "reason", err.Error())
+			for _, waitingPod := range waitingPods {
+				cs.forgetWaitingDeadline(waitingPod.GetPod())
+				waitingPod.Reject(cs.Name(), msg)
+			}
+			cs.dropGangBindHint(pgFullName)
+			cs.pgMgr.DeletePermittedPodGroup(ctx, pgFullName)
+			cs.pgMgr.ReleaseAdmission(ctx, pgFullName, false, state)
+			cs.releaseWaitingBudget(ctx, util.GetPodGroupNamespace(pod), state)
+			cs.countGangRejection(pgFullName, metrics.RejectionInfeasible)
+			return framework.NewStatus(framework.Unschedulable, msg), 0
+		}
+		// Hint the binding goroutines of the whole gang, released below, to bind it at once.
+		hint := cs.gangBindHint(pgFullName)
+		hint.permit(len(waitingPods)+1, time.Now())
+		state.Write(GangBindHintKey, hint)
+		cs.dropGangBindHint(pgFullName)
+		// The gang got the capacity it waited for: let the other waiting gangs consume the rest.
+		cs.pgMgr.ReleaseAdmission(ctx, pgFullName, true, state)
+		cs.releaseWaitingBudget(ctx, util.GetPodGroupNamespace(pod), state)
+		for _, waitingPod := range waitingPods {
+			lh.V(3).Info("Permit allows", "pod", klog.KObj(waitingPod.GetPod()))
+			cs.forgetWaitingDeadline(waitingPod.GetPod())
+			waitingPod.Allow(cs.Name())
+		}
+		if _, pg := cs.pgMgr.GetPodGroup(ctx, pod); pg != nil {
+			observeScheduleLatency(pg, time.Now())
+		}
 		lh.V(3).Info("Permit allows", "pod", klog.KObj(pod))
 		retStatus = framework.NewStatus(framework.Success)
 		waitTime = 0
@@ -262,11 +465,215 @@ func (cs *Coscheduling) Unreserve(ctx context.Context, state *framework.CycleSta
 	if pg == nil {
 		return
 	}
+	// The deadlines of the members waiting are forgotten as they are released, so that the rejection of
+	// the gang is counted once, by the first of its members unreserved.
+	waited, timedOut := cs.forgetWaitingDeadline(pod)
+	var waitingPods []framework.WaitingPod
 	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
-		if waitingPod.GetPod().Namespace == pod.Namespace && util.GetPodGroupLabel(waitingPod.GetPod()) == pg.Name {
-			lh.V(3).Info("Unreserve rejects", "pod", klog.KObj(waitingPod.GetPod()), "podGroup", klog.KObj(pg))
-			waitingPod.Reject(cs.Name(), "rejection in Unreserve")
+		if util.GetPodGroupFullName(waitingPod.GetPod()) == pgName {
+			waitingPods = append(waitingPods, waitingPod)
 		}
 	})
+	for _, waitingPod := range waitingPods {
+		lh.V(3).Info("Unreserve rejects", "pod", klog.KObj(waitingPod.GetPod()), "podGroup", klog.KObj(pg))
+		if siblingWaited, _ := cs.forgetWaitingDeadline(waitingPod.GetPod()); siblingWaited {
+			waited = true
+		}
+		waitingPod.Reject(cs.Name(), "rejection in Unreserve")
+	}
+	if timedOut {
+		metrics.GangPermitTimeouts.Inc()
+		cs.countGangRejection(pgName, metrics.RejectionTimeout)
+	} else if waited {
+		cs.countGangRejection(pgName, metrics.RejectionUnreserved)
+	}
+	cs.stopProgressDeadline(pgName)
+	cs.stopPermitDeadline(pgName)
+	cs.dropGangBindHint(pgName)
 	cs.pgMgr.DeletePermittedPodGroup(ctx, pgName)
+	cs.pgMgr.ReleaseAdmission(ctx, pgName, false, state)
+	cs.releaseWaitingBudget(ctx, pg.Namespace, state)
+}
+
+// PreBind annotates the members of a PodGroup with a maxUnavailable with the name of the
+// PodDisruptionBudget maintained by the PodGroup controller, so that the gang admitted together
+// is also protected together from voluntary disruptions. The annotation is informative: the
+// PodDisruptionBudget selects the members by their PodGroup label, so failing to annotate a pod
+// doesn't fail its binding. The delay of the members of a gang released together by Permit is
+// observed from their GangBindHint.
+func (cs *Coscheduling) PreBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if hint := GetGangBindHint(state); hint != nil {
+		if permitted, _, permittedAt := hint.Permitted(); permitted {
+			metrics.GangBindDelay.Observe(time.Since(permittedAt).Seconds())
+		}
+	}
+	_, pg := cs.pgMgr.GetPodGroup(ctx, pod)
+	if pg == nil || pg.Spec.MaxUnavailable == nil || pod.Annotations[v1alpha1.PodGroupDisruptionBudgetAnnotation] == pg.Name {
+		return nil
+	}
+	lh := klog.FromContext(ctx)
+	podCopy := pod.DeepCopy()
+	if podCopy.Annotations == nil {
+		podCopy.Annotations = make(map[string]string)
+	}
+	podCopy.Annotations[v1alpha1.PodGroupDisruptionBudgetAnnotation] = pg.Name
+	patch, err := util.CreateMergePatch(pod, podCopy)
+	if err != nil {
+		lh.Error(err, "Failed to create the disruption budget annotation patch", "pod", klog.KObj(pod))
+		return nil
+	}
+	if _, err := cs.frameworkHandler.ClientSet().CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		lh.Error(err, "Failed to annotate the pod with its disruption budget", "pod", klog.KObj(pod), "podGroup", klog.KObj(pg))
+	}
+	return nil
+}
+
+// startProgressDeadline starts the progress deadline timer of the PodGroup, if it has a progress
+// deadline and no timer is running yet, i.e. when its first member waits in Permit.
+func (cs *Coscheduling) startProgressDeadline(ctx context.Context, pod *v1.Pod, pg *v1alpha1.PodGroup) {
+	deadline, minAssigned := util.GetProgressDeadline(pg)
+	if deadline == 0 {
+		return
+	}
+	pgFullName := util.GetPodGroupFullName(pod)
+	cs.progressLock.Lock()
+	defer cs.progressLock.Unlock()
+	if cs.progressTimers == nil {
+		cs.progressTimers = make(map[string]*time.Timer)
+	}
+	if _, ok := cs.progressTimers[pgFullName]; ok {
+		return
+	}
+	lh := klog.FromContext(ctx)
+	ctx = klog.NewContext(context.Background(), lh)
+	cs.progressTimers[pgFullName] = time.AfterFunc(deadline, func() {
+		cs.progressLock.Lock()
+		delete(cs.progressTimers, pgFullName)
+		cs.progressLock.Unlock()
+		cs.checkProgress(ctx, pod, pgFullName, deadline, minAssigned)
+	})
+}
+
+// stopProgressDeadline stops the progress deadline timer of the PodGroup, if any.
+func (cs *Coscheduling) stopProgressDeadline(pgFullName string) {
+	cs.progressLock.Lock()
+	defer cs.progressLock.Unlock()
+	if timer, ok := cs.progressTimers[pgFullName]; ok {
+		timer.Stop()
+		delete(cs.progressTimers, pgFullName)
+	}
+}
+
+// checkProgress releases and backs off the whole PodGroup if fewer than minAssigned members
+// are waiting in Permit or bound once its progress deadline is exceeded.
+func (cs *Coscheduling) checkProgress(ctx context.Context, pod *v1.Pod, pgFullName string, deadline time.Duration, minAssigned int32) {
+	lh := klog.FromContext(ctx)
+	pgName := util.GetPodGroupLabel(pod)
+
+	var waiting []framework.WaitingPod
+	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		if util.GetPodGroupFullName(waitingPod.GetPod()) == pgFullName {
+			waiting = append(waiting, waitingPod)
+		}
+	})
+	if len(waiting) == 0 {
+		// The members already got allowed or rejected.
+		return
+	}
+	assignedPods := make([]*v1.Pod, 0, len(waiting))
+	for _, waitingPod := range waiting {
+		assignedPods = append(assignedPods, waitingPod.GetPod())
+	}
+	pods, err := cs.listPodGroupPods(util.GetPodGroupNamespace(pod), pgName)
+	if err != nil {
+		lh.Error(err, "Failed to obtain pods belong to a PodGroup", "podGroup", pgFullName)
+		return
+	}
+	for _, p := range pods {
+		if p.Spec.NodeName != "" {
+			assignedPods = append(assignedPods, p)
+		}
+	}
+	// A member recreated by its controller is counted once with the pod it replaces.
+	assigned := util.CountPodGroupMembers(assignedPods)
+	if int32(assigned) >= minAssigned {
+		return
+	}
+	if cs.checkpointTimeout > 0 {
+		// Give the members already bound a chance to save their state before the PodGroup is released.
+		var bound []*v1.Pod
+		for _, p := range pods {
+			if p.Spec.NodeName != "" && p.Status.Phase != v1.PodSucceeded && p.Status.Phase != v1.PodFailed {
+				bound = append(bound, p)
+			}
+		}
+		if len(bound) > 0 {
+			cs.requestCheckpoint(ctx, pgFullName, bound, CheckpointReasonTimeout)
+		}
+	}
+
+	msg := fmt.Sprintf("PodGroup %v made no sufficient progress within %v: %v assigned members, %v required",
+		pgFullName, deadline, assigned, minAssigned)
+	lh.V(3).Info("Progress deadline exceeded, releasing the PodGroup", "podGroup", pgFullName, "assigned", assigned, "required", minAssigned)
+	for _, waitingPod := range waiting {
+		cs.forgetWaitingDeadline(waitingPod.GetPod())
+		waitingPod.Reject(cs.Name(), msg)
+	}
+
+	// Back off the PodGroup with its backoff policy or the configured backoff, or otherwise its progress deadline.
+	backoff := deadline
+	if cs.pgBackoff != nil {
+		backoff = *cs.pgBackoff
+	}
+	_, pg := cs.pgMgr.GetPodGroup(ctx, pod)
+	cs.pgMgr.BackoffPodGroup(pgFullName, pg, backoff)
+	cs.dropGangBindHint(pgFullName)
+	cs.pgMgr.DeletePermittedPodGroup(ctx, pgFullName)
+	cs.pgMgr.ReleaseAdmission(ctx, pgFullName, false, nil)
+	cs.countGangRejection(pgFullName, metrics.RejectionProgressDeadline)
+
+	if recorder := cs.frameworkHandler.EventRecorder(); recorder != nil {
+		if pg != nil {
+			recorder.Eventf(pg, nil, v1.EventTypeWarning, ProgressDeadlineExceeded, "Scheduling", "%v", msg)
+		}
+	}
+}
+
+// countGangRejection counts the rejection of the PodGroup by reason, and its failed attempt, delaying it in the
+// scheduling queue if the retry penalty is enabled.
+func (cs *Coscheduling) countGangRejection(pgFullName, reason string) {
+	metrics.GangRejections.WithLabelValues(reason).Inc()
+	cs.pgMgr.RecordFailedAttempt(pgFullName)
+}
+
+// observeScheduleLatency observes the time the PodGroup took to pass Permit, from the start of its scheduling,
+// or from its creation if it never had minMember members created.
+func observeScheduleLatency(pg *v1alpha1.PodGroup, now time.Time) {
+	start := pg.Status.ScheduleStartTime.Time
+	if start.IsZero() {
+		start = pg.CreationTimestamp.Time
+	}
+	if start.IsZero() || now.Before(start) {
+		return
+	}
+	metrics.PodGroupScheduleLatency.Observe(now.Sub(start).Seconds())
+}
+
+// listPodGroupPods lists the pods labeled as members of the PodGroup namespace/pgName, in its namespace or in
+// another one, for the callers not holding the PodGroup and its member namespaces. The pods of the namespaces
+// other than the member namespaces of the PodGroup are rejected in PreFilter.
+func (cs *Coscheduling) listPodGroupPods(namespace, pgName string) ([]*v1.Pod, error) {
+	pods, err := cs.frameworkHandler.SharedInformerFactory().Core().V1().Pods().Lister().List(
+		labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: pgName}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if util.GetPodGroupNamespace(pod) == namespace {
+			members = append(members, pod)
+		}
+	}
+	return members, nil
 }
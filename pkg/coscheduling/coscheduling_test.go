@@ -26,10 +26,14 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
 	clicache "k8s.io/client-go/tools/cache"
+	componentmetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/testutil"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
@@ -38,9 +42,11 @@ import (
 	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
 	"k8s.io/utils/pointer"
 
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/coscheduling/metrics"
 	_ "sigs.k8s.io/scheduler-plugins/apis/config/scheme"
 	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling/core"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
 	tu "sigs.k8s.io/scheduler-plugins/test/util"
 )
 
@@ -192,7 +198,9 @@ func TestLess(t *testing.T) {
 		p1   *framework.QueuedPodInfo
 		p2   *framework.QueuedPodInfo
 		pgs  []*v1alpha1.PodGroup
-		want bool
+		// failedAttempts are the failed attempts of the pod groups, delaying them by 10s each
+		failedAttempts []string
+		want           bool
 	}{
 		{
 			name: "p1.priority less than p2.priority",
@@ -400,6 +408,61 @@ func TestLess(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "equal priority. pg1 is created earlier than pg2 but failed recently",
+			p1: &framework.QueuedPodInfo{
+				PodInfo: tu.MustNewPodInfo(t, st.MakePod().Name("p1").Namespace("ns").Priority(highPriority).
+					Label(v1alpha1.PodGroupLabel, "pg1").Obj()),
+				InitialAttemptTimestamp: ptrTime(now.Add(time.Second * 1)),
+			},
+			p2: &framework.QueuedPodInfo{
+				PodInfo: tu.MustNewPodInfo(t, st.MakePod().Name("p2").Namespace("ns").Priority(highPriority).
+					Label(v1alpha1.PodGroupLabel, "pg2").Obj()),
+				InitialAttemptTimestamp: ptrTime(now.Add(time.Second * 2)),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").Time(now.Add(time.Second * 1)).Obj(),
+				tu.MakePodGroup().Name("pg2").Namespace("ns").Time(now.Add(time.Second * 2)).Obj(),
+			},
+			failedAttempts: []string{"ns/pg1"},
+			want:           false,
+		},
+		{
+			name: "equal priority. pg1 is created long before pg2 and failed less than pg2",
+			p1: &framework.QueuedPodInfo{
+				PodInfo: tu.MustNewPodInfo(t, st.MakePod().Name("p1").Namespace("ns").Priority(highPriority).
+					Label(v1alpha1.PodGroupLabel, "pg1").Obj()),
+				InitialAttemptTimestamp: ptrTime(now.Add(time.Second * 1)),
+			},
+			p2: &framework.QueuedPodInfo{
+				PodInfo: tu.MustNewPodInfo(t, st.MakePod().Name("p2").Namespace("ns").Priority(highPriority).
+					Label(v1alpha1.PodGroupLabel, "pg2").Obj()),
+				InitialAttemptTimestamp: ptrTime(now.Add(time.Second * 15)),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").Time(now.Add(time.Second * 1)).Obj(),
+				tu.MakePodGroup().Name("pg2").Namespace("ns").Time(now.Add(time.Second * 15)).Obj(),
+			},
+			failedAttempts: []string{"ns/pg1", "ns/pg1", "ns/pg2"},
+			want:           true,
+		},
+		{
+			name: "higher priority. p1 belongs to pg1 which failed recently",
+			p1: &framework.QueuedPodInfo{
+				PodInfo: tu.MustNewPodInfo(t, st.MakePod().Name("p1").Namespace("ns").Priority(highPriority).
+					Label(v1alpha1.PodGroupLabel, "pg1").Obj()),
+				InitialAttemptTimestamp: ptrTime(now.Add(time.Second * 1)),
+			},
+			p2: &framework.QueuedPodInfo{
+				PodInfo:                 tu.MustNewPodInfo(t, st.MakePod().Name("p2").Namespace("ns").Priority(lowPriority).Obj()),
+				InitialAttemptTimestamp: ptrTime(now.Add(time.Second * 2)),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").Time(now.Add(time.Second * 1)).Obj(),
+			},
+			failedAttempts: []string{"ns/pg1", "ns/pg1"},
+			want:           true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -421,7 +484,14 @@ func TestLess(t *testing.T) {
 			informerFactory := informers.NewSharedInformerFactory(cs, 0)
 			podInformer := informerFactory.Core().V1().Pods()
 
-			pl := &Coscheduling{pgMgr: core.NewPodGroupManager(client, nil, nil, podInformer)}
+			pgMgr := core.NewPodGroupManager(client, nil, nil, podInformer)
+			if len(tt.failedAttempts) > 0 {
+				pgMgr.EnableRetryPenalty(10 * time.Second)
+			}
+			for _, pgName := range tt.failedAttempts {
+				pgMgr.RecordFailedAttempt(pgName)
+			}
+			pl := &Coscheduling{pgMgr: pgMgr}
 
 			informerFactory.Start(ctx.Done())
 			if !clicache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
@@ -449,6 +519,8 @@ func TestPermit(t *testing.T) {
 		pod  *v1.Pod
 		pgs  []*v1alpha1.PodGroup
 		want framework.Code
+		// wantHint is the number of gang members hinted to be bound together, or -1 without hint
+		wantHint int
 	}{
 		{
 			name: "pods do not belong to any podGroup",
@@ -457,7 +529,8 @@ func TestPermit(t *testing.T) {
 				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(1).Obj(),
 				tu.MakePodGroup().Name("pg2").Namespace("ns").MinMember(2).Obj(),
 			},
-			want: framework.Success,
+			want:     framework.Success,
+			wantHint: -1,
 		},
 		{
 			name: "pods belong to a pg1, but quorum not satisfied",
@@ -466,7 +539,8 @@ func TestPermit(t *testing.T) {
 				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(1).Obj(),
 				tu.MakePodGroup().Name("pg2").Namespace("ns").MinMember(2).Obj(),
 			},
-			want: framework.Wait,
+			want:     framework.Wait,
+			wantHint: 0,
 		},
 		{
 			name: "pods belong to a podGroup, and quorum satisfied",
@@ -475,7 +549,18 @@ func TestPermit(t *testing.T) {
 				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(1).Obj(),
 				tu.MakePodGroup().Name("pg2").Namespace("ns").MinMember(2).Obj(),
 			},
-			want: framework.Success,
+			want:     framework.Success,
+			wantHint: 1,
+		},
+		{
+			name: "pods belong to a podGroup, quorum satisfied, but the node can no longer fit the pod",
+			pod: st.MakePod().Name("p").Namespace("ns").UID("p").Label(v1alpha1.PodGroupLabel, "pg1").
+				Req(map[v1.ResourceName]string{v1.ResourceCPU: "5"}).Obj(),
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(1).Obj(),
+			},
+			want:     framework.Unschedulable,
+			wantHint: -1,
 		},
 	}
 
@@ -525,10 +610,111 @@ func TestPermit(t *testing.T) {
 				t.Fatal("WaitForCacheSync failed")
 			}
 
-			code, _ := pl.Permit(ctx, framework.NewCycleState(), tt.pod, "node")
+			state := framework.NewCycleState()
+			code, _ := pl.Permit(ctx, state, tt.pod, "node")
 			if got := code.Code(); got != tt.want {
 				t.Errorf("Want %v, but got %v", tt.want, got)
 			}
+
+			hint := GetGangBindHint(state)
+			if tt.wantHint < 0 {
+				if hint != nil {
+					t.Errorf("Want no gang bind hint, but got %v", hint.PodGroup)
+				}
+				return
+			}
+			if hint == nil {
+				t.Fatal("Want a gang bind hint, but got none")
+			}
+			if permitted, members, _ := hint.Permitted(); permitted != (tt.wantHint > 0) || members != tt.wantHint {
+				t.Errorf("Want %v members hinted to be bound together, but got %v", tt.wantHint, members)
+			}
+			if status := pl.PreBind(ctx, state, tt.pod, "node"); !status.IsSuccess() {
+				t.Errorf("Want PreBind to succeed with the gang bind hint, but got %v", status.Message())
+			}
+		})
+	}
+}
+
+func TestPreBind(t *testing.T) {
+	maxUnavailable := intstr.FromInt32(1)
+	pgWithBudget := tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).Obj()
+	pgWithBudget.Spec.MaxUnavailable = &maxUnavailable
+
+	tests := []struct {
+		name           string
+		pod            *v1.Pod
+		pgs            []*v1alpha1.PodGroup
+		wantAnnotation string
+	}{
+		{
+			name: "pod does not belong to any podGroup",
+			pod:  st.MakePod().Name("p").Namespace("ns").UID("p").Obj(),
+			pgs:  []*v1alpha1.PodGroup{pgWithBudget},
+		},
+		{
+			name: "pod belongs to a podGroup without maxUnavailable",
+			pod:  st.MakePod().Name("p").Namespace("ns").UID("p").Label(v1alpha1.PodGroupLabel, "pg2").Obj(),
+			pgs: []*v1alpha1.PodGroup{
+				pgWithBudget,
+				tu.MakePodGroup().Name("pg2").Namespace("ns").MinMember(2).Obj(),
+			},
+		},
+		{
+			name:           "pod belongs to a podGroup with maxUnavailable",
+			pod:            st.MakePod().Name("p").Namespace("ns").UID("p").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			pgs:            []*v1alpha1.PodGroup{pgWithBudget},
+			wantAnnotation: "pg1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var objs []runtime.Object
+			objs = append(objs, tt.pod)
+			for _, pg := range tt.pgs {
+				objs = append(objs, pg)
+			}
+			client, err := tu.NewFakeClient(objs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cs := clientsetfake.NewSimpleClientset(tt.pod)
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			f, err := tf.NewFramework(
+				ctx,
+				registeredPlugins,
+				"default-scheduler",
+				fwkruntime.WithClientSet(cs),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods()
+
+			pl := &Coscheduling{
+				frameworkHandler: f,
+				pgMgr:            core.NewPodGroupManager(client, tu.NewFakeSharedLister(nil, nil), nil, podInformer),
+			}
+
+			if status := pl.PreBind(ctx, framework.NewCycleState(), tt.pod, "node"); !status.IsSuccess() {
+				t.Fatalf("Want success, but got %v", status)
+			}
+			pod, err := cs.CoreV1().Pods(tt.pod.Namespace).Get(ctx, tt.pod.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pod.Annotations[v1alpha1.PodGroupDisruptionBudgetAnnotation]; got != tt.wantAnnotation {
+				t.Errorf("Want disruption budget annotation %q, but got %q", tt.wantAnnotation, got)
+			}
 		})
 	}
 }
@@ -649,3 +835,373 @@ func TestPostFilter(t *testing.T) {
 		})
 	}
 }
+
+type fakeWaitingPod struct {
+	pod      *v1.Pod
+	rejected string
+}
+
+func (w *fakeWaitingPod) GetPod() *v1.Pod               { return w.pod }
+func (w *fakeWaitingPod) GetPendingPlugins() []string   { return []string{Name} }
+func (w *fakeWaitingPod) Allow(pluginName string)       {}
+func (w *fakeWaitingPod) Reject(pluginName, msg string) { w.rejected = msg }
+
+// fakeWaitingPodsHandle overrides the waiting pods of a framework handle.
+type fakeWaitingPodsHandle struct {
+	framework.Framework
+	waitingPods []*fakeWaitingPod
+}
+
+func (h *fakeWaitingPodsHandle) IterateOverWaitingPods(callback func(framework.WaitingPod)) {
+	for _, w := range h.waitingPods {
+		callback(w)
+	}
+}
+
+func TestProgressDeadline(t *testing.T) {
+	scheduleTimeout := 10 * time.Second
+	pg := tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(4).ProgressDeadline(1, 50).Obj()
+
+	tests := []struct {
+		name         string
+		waitingPods  []*v1.Pod
+		existingPods []*v1.Pod
+		wantRejected bool
+	}{
+		{
+			name: "not enough members assigned, release the pod group",
+			waitingPods: []*v1.Pod{
+				st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			},
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+				st.MakePod().Name("p2").Namespace("ns").UID("p2").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			},
+			wantRejected: true,
+		},
+		{
+			name: "enough members waiting or bound, keep waiting",
+			waitingPods: []*v1.Pod{
+				st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			},
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+				st.MakePod().Name("p2").Namespace("ns").UID("p2").Node("node").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			},
+			wantRejected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			client, err := tu.NewFakeClient(pg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cs := clientsetfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			f, err := tf.NewFramework(
+				ctx,
+				registeredPlugins,
+				"default-scheduler",
+				fwkruntime.WithInformerFactory(informerFactory),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			handle := &fakeWaitingPodsHandle{Framework: f}
+			for _, p := range tt.waitingPods {
+				handle.waitingPods = append(handle.waitingPods, &fakeWaitingPod{pod: p})
+			}
+
+			pgMgr := core.NewPodGroupManager(client, tu.NewFakeSharedLister(tt.existingPods, nil), &scheduleTimeout, podInformer)
+			pl := &Coscheduling{
+				frameworkHandler: handle,
+				pgMgr:            pgMgr,
+				scheduleTimeout:  &scheduleTimeout,
+			}
+
+			informerFactory.Start(ctx.Done())
+			if !clicache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
+				t.Fatal("WaitForCacheSync failed")
+			}
+			for _, p := range tt.existingPods {
+				podInformer.Informer().GetStore().Add(p)
+			}
+
+			deadline, minAssigned := util.GetProgressDeadline(pg)
+			pl.checkProgress(ctx, tt.waitingPods[0], "ns/pg1", deadline, minAssigned)
+
+			for _, w := range handle.waitingPods {
+				if got := w.rejected != ""; got != tt.wantRejected {
+					t.Errorf("expected rejected %v, got %v", tt.wantRejected, got)
+				}
+			}
+			// A released PodGroup is backed off.
+			err = pgMgr.PreFilter(ctx, tt.waitingPods[0])
+			if got := err != nil && err.Error() == "podGroup ns/pg1 failed recently"; got != tt.wantRejected {
+				t.Errorf("expected backoff %v, got %v", tt.wantRejected, err)
+			}
+		})
+	}
+}
+
+func TestProgressDeadlineTimer(t *testing.T) {
+	pod := st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg1").Obj()
+	pl := &Coscheduling{}
+
+	pl.startProgressDeadline(context.Background(), pod, tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).Obj())
+	if len(pl.progressTimers) != 0 {
+		t.Errorf("expected no timer without progress deadline, got %v", len(pl.progressTimers))
+	}
+
+	pg := tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).ProgressDeadline(60, 100).Obj()
+	pl.startProgressDeadline(context.Background(), pod, pg)
+	timer := pl.progressTimers["ns/pg1"]
+	pl.startProgressDeadline(context.Background(), pod, pg)
+	if len(pl.progressTimers) != 1 || pl.progressTimers["ns/pg1"] != timer {
+		t.Errorf("expected a single timer per PodGroup")
+	}
+
+	pl.stopProgressDeadline("ns/pg1")
+	if len(pl.progressTimers) != 0 {
+		t.Errorf("expected the timer to be stopped")
+	}
+}
+
+func TestWaitingBudget(t *testing.T) {
+	waitingPods := []*v1.Pod{
+		st.MakePod().Name("a1").Namespace("ns").UID("a1").Node("node").Label(v1alpha1.PodGroupLabel, "pg-a").Obj(),
+		st.MakePod().Name("c1").Namespace("other").UID("c1").Node("node").Label(v1alpha1.PodGroupLabel, "pg-c").Obj(),
+	}
+	podA := st.MakePod().Name("a2").Namespace("ns").UID("a2").Label(v1alpha1.PodGroupLabel, "pg-a").Obj()
+	podB := st.MakePod().Name("b1").Namespace("ns").UID("b1").Label(v1alpha1.PodGroupLabel, "pg-b").Obj()
+	podD := st.MakePod().Name("d1").Namespace("other2").UID("d1").Label(v1alpha1.PodGroupLabel, "pg-d").Obj()
+	podNoGroup := st.MakePod().Name("p").Namespace("ns").UID("p").Obj()
+
+	tests := []struct {
+		name                string
+		maxWaitingPodGroups int64
+		pod                 *v1.Pod
+		wantCode            framework.Code
+	}{
+		{
+			name:     "no budget",
+			pod:      podB,
+			wantCode: framework.Success,
+		},
+		{
+			name:                "budget exhausted by another PodGroup of the namespace",
+			maxWaitingPodGroups: 1,
+			pod:                 podB,
+			wantCode:            framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:                "PodGroup already waiting",
+			maxWaitingPodGroups: 1,
+			pod:                 podA,
+			wantCode:            framework.Success,
+		},
+		{
+			name:                "budget left in the namespace",
+			maxWaitingPodGroups: 2,
+			pod:                 podB,
+			wantCode:            framework.Success,
+		},
+		{
+			name:                "PodGroups of other namespaces don't count",
+			maxWaitingPodGroups: 1,
+			pod:                 podD,
+			wantCode:            framework.Success,
+		},
+		{
+			name:                "pod without PodGroup",
+			maxWaitingPodGroups: 1,
+			pod:                 podNoGroup,
+			wantCode:            framework.Success,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cs := clientsetfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			f, err := tf.NewFramework(
+				ctx,
+				registeredPlugins,
+				"default-scheduler",
+				fwkruntime.WithInformerFactory(informerFactory),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			handle := &fakeWaitingPodsHandle{Framework: f}
+			for _, p := range waitingPods {
+				handle.waitingPods = append(handle.waitingPods, &fakeWaitingPod{pod: p})
+			}
+			for _, p := range append(waitingPods, tt.pod) {
+				podInformer.Informer().GetStore().Add(p)
+			}
+
+			pl := &Coscheduling{
+				frameworkHandler:    handle,
+				maxWaitingPodGroups: tt.maxWaitingPodGroups,
+			}
+			preFilterState := framework.NewCycleState()
+			status := pl.checkWaitingBudget(ctx, preFilterState, tt.pod)
+			if got := status.Code(); got != tt.wantCode {
+				t.Fatalf("expected code %v, got %v: %v", tt.wantCode, got, status)
+			}
+			// The rejection is marked so that PostFilter doesn't activate the PodGroup it just held back.
+			if got, want := isHeldBack(preFilterState), tt.wantCode != framework.Success; got != want {
+				t.Errorf("expected held back %v, got %v", want, got)
+			}
+
+			// A PodGroup held back is activated once a PodGroup of its namespace leaves Permit.
+			state := framework.NewCycleState()
+			state.Write(framework.PodsToActivateKey, framework.NewPodsToActivate())
+			pl.releaseWaitingBudget(ctx, "ns", state)
+			c, err := state.Read(framework.PodsToActivateKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			activated := c.(*framework.PodsToActivate).Map
+			_, gotActivated := activated[core.GetNamespacedName(tt.pod)]
+			if wantActivated := tt.wantCode != framework.Success; gotActivated != wantActivated {
+				t.Errorf("expected activated %v, got %v", wantActivated, gotActivated)
+			}
+			if len(pl.heldBack) != 0 {
+				t.Errorf("expected no PodGroup held back after the release, got %v", pl.heldBack)
+			}
+		})
+	}
+}
+
+func TestGangMetrics(t *testing.T) {
+	metrics.Register()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scheduleTimeout := 10 * time.Second
+	pg := tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(3).Obj()
+	pg.Status.ScheduleStartTime = metav1.NewTime(time.Now().Add(-time.Minute))
+	client, err := tu.NewFakeClient(pg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	informerFactory := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	f, err := tf.NewFramework(ctx,
+		[]tf.RegisterPluginFunc{
+			tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		},
+		"default-scheduler",
+		fwkruntime.WithInformerFactory(informerFactory),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p1 := st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg1").Obj()
+	p2 := st.MakePod().Name("p2").Namespace("ns").UID("p2").Label(v1alpha1.PodGroupLabel, "pg1").Obj()
+	handle := &fakeWaitingPodsHandle{Framework: f, waitingPods: []*fakeWaitingPod{{pod: p2}}}
+	pl := &Coscheduling{
+		frameworkHandler: handle,
+		pgMgr:            core.NewPodGroupManager(client, tu.NewFakeSharedLister(nil, nil), &scheduleTimeout, podInformer),
+		scheduleTimeout:  &scheduleTimeout,
+	}
+
+	counter := func(m componentmetrics.CounterMetric) float64 {
+		t.Helper()
+		val, err := testutil.GetCounterMetricValue(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return val
+	}
+	gauge := func() float64 {
+		t.Helper()
+		val, err := testutil.GetGaugeMetricValue(metrics.PodGroupsWaiting)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return val
+	}
+	timeouts := counter(metrics.GangPermitTimeouts)
+	rejectedTimeout := counter(metrics.GangRejections.WithLabelValues(metrics.RejectionTimeout))
+	rejectedUnreserved := counter(metrics.GangRejections.WithLabelValues(metrics.RejectionUnreserved))
+	waiting := gauge()
+
+	// p1 and p2 wait in Permit: the PodGroup is counted once.
+	pl.recordWaitingDeadline(p1, -time.Second)
+	pl.gangBindHint("ns/pg1")
+	pl.recordWaitingDeadline(p2, time.Minute)
+	pl.gangBindHint("ns/pg1")
+	if got := gauge() - waiting; got != 1 {
+		t.Errorf("expected 1 more PodGroup waiting, got %v", got)
+	}
+
+	// p1 times out, and p2 rejected in turn doesn't count the rejection again.
+	state := framework.NewCycleState()
+	pl.Unreserve(ctx, state, p1, "node")
+	if handle.waitingPods[0].rejected == "" {
+		t.Error("expected p2 to be rejected")
+	}
+	handle.waitingPods = nil
+	pl.Unreserve(ctx, state, p2, "node")
+	if got := counter(metrics.GangPermitTimeouts) - timeouts; got != 1 {
+		t.Errorf("expected 1 more Permit timeout, got %v", got)
+	}
+	if got := counter(metrics.GangRejections.WithLabelValues(metrics.RejectionTimeout)) - rejectedTimeout; got != 1 {
+		t.Errorf("expected 1 more rejection on timeout, got %v", got)
+	}
+	if got := counter(metrics.GangRejections.WithLabelValues(metrics.RejectionUnreserved)) - rejectedUnreserved; got != 0 {
+		t.Errorf("expected no more rejection in Unreserve, got %v", got)
+	}
+	if got := gauge() - waiting; got != 0 {
+		t.Errorf("expected no more PodGroup waiting, got %v", got)
+	}
+
+	count, err := testutil.GetHistogramMetricCount(metrics.PodGroupScheduleLatency.ObserverMetric)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observeScheduleLatency(pg, time.Now())
+	if got, err := testutil.GetHistogramMetricCount(metrics.PodGroupScheduleLatency.ObserverMetric); err != nil || got != count+1 {
+		t.Errorf("expected 1 more schedule latency observed, got %v: %v", got-count, err)
+	}
+
+	count, err = testutil.GetHistogramMetricCount(metrics.GangBindDelay.ObserverMetric)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hint := pl.gangBindHint("ns/pg1")
+	bindState := framework.NewCycleState()
+	bindState.Write(GangBindHintKey, hint)
+	pl.PreBind(ctx, bindState, p1, "node")
+	if got, err := testutil.GetHistogramMetricCount(metrics.GangBindDelay.ObserverMetric); err != nil || got != count {
+		t.Errorf("expected no bind delay observed before Permit, got %v: %v", got-count, err)
+	}
+	hint.permit(2, time.Now())
+	pl.PreBind(ctx, bindState, p1, "node")
+	if got, err := testutil.GetHistogramMetricCount(metrics.GangBindDelay.ObserverMetric); err != nil || got != count+1 {
+		t.Errorf("expected 1 more bind delay observed, got %v: %v", got-count, err)
+	}
+	pl.dropGangBindHint("ns/pg1")
+}
@@ -0,0 +1,350 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	apipod "k8s.io/kubernetes/pkg/api/v1/pod"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/noderesources"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+// victimPod is an assigned member of a victim PodGroup, and the node it is assigned to.
+type victimPod struct {
+	podInfo  *framework.PodInfo
+	nodeName string
+}
+
+// victimGroup is a PodGroup whose assigned members are preempted all together, or not at all.
+type victimGroup struct {
+	name string
+	// priority is the highest priority of the members.
+	priority int32
+	pods     []victimPod
+}
+
+// preemptPodGroups evicts, as a whole, the lower-priority PodGroups making room for the members of the
+// PodGroup of the pod missing to reach its minMember, and nominates the pod on the node it fits on.
+// The PodGroups whose eviction would violate a PodDisruptionBudget are not evicted.
+// With checkpoint hooks, the victims are evicted in the background once their checkpoint is acknowledged or timed out.
+// A nil result means that gang preemption is not possible.
+func (cs *Coscheduling) preemptPodGroups(ctx context.Context, state *framework.CycleState, pod *v1.Pod, pgName string,
+	pg *v1alpha1.PodGroup, assigned int, m framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	lh := klog.FromContext(ctx)
+	if pod.Spec.PreemptionPolicy != nil && *pod.Spec.PreemptionPolicy == v1.PreemptNever {
+		return nil, nil
+	}
+	if nodeName, ok := cs.pendingPreemption(pgName); ok {
+		// The victims are being checkpointed, they are evicted afterwards.
+		return framework.NewPostFilterResultWithNominatedNode(nodeName), framework.NewStatus(framework.Success)
+	}
+	members, ok := cs.pendingMembers(pod, pg, int(pg.Spec.MinMember)-assigned)
+	if !ok {
+		lh.V(4).Info("Not enough pending members to reach the quorum with gang preemption", "podGroup", klog.KObj(pg))
+		return nil, nil
+	}
+	nodeInfos, err := cs.frameworkHandler.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		lh.Error(err, "Could not list the nodes for gang preemption", "pod", klog.KObj(pod))
+		return nil, nil
+	}
+	candidates := cs.victimGroups(pod, pgName, nodeInfos)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	pdbs, err := cs.pdbLister.List(labels.Everything())
+	if err != nil {
+		lh.Error(err, "Could not list the PodDisruptionBudgets for gang preemption", "pod", klog.KObj(pod))
+		return nil, nil
+	}
+
+	// Add the candidates, the least important first, until the gang fits.
+	budgets := newDisruptionBudgets(pdbs)
+	var victims []*victimGroup
+	nodeName := ""
+	for _, group := range candidates {
+		if !budgets.allow(group) {
+			lh.V(5).Info("Evicting the PodGroup would violate a PodDisruptionBudget", "podGroup", group.name)
+			continue
+		}
+		victims = append(victims, group)
+		if nodeName, ok = cs.placeGang(ctx, state, pod, members, nodeInfos, victims, m); ok {
+			break
+		}
+	}
+	if nodeName == "" {
+		return nil, nil
+	}
+	// Reprieve the victims that are not needed for the gang to fit, the most important first.
+	for i := len(victims) - 1; i >= 0; i-- {
+		rest := append(append([]*victimGroup{}, victims[:i]...), victims[i+1:]...)
+		if node, ok := cs.placeGang(ctx, state, pod, members, nodeInfos, rest, m); ok {
+			victims, nodeName = rest, node
+		}
+	}
+
+	if cs.checkpointTimeout > 0 {
+		cs.startCheckpointedPreemption(ctx, pod, pgName, victims, nodeName)
+		return framework.NewPostFilterResultWithNominatedNode(nodeName), framework.NewStatus(framework.Success)
+	}
+	for _, group := range victims {
+		if err := cs.evictPodGroup(ctx, pod, pgName, group); err != nil {
+			return nil, framework.AsStatus(err)
+		}
+	}
+	return framework.NewPostFilterResultWithNominatedNode(nodeName), framework.NewStatus(framework.Success)
+}
+
+// pendingMembers returns the other members of the PodGroup of the pod that are neither assigned nor waiting in
+// Permit, needed besides the pod to reach the quorum. It returns false if there are not enough of them.
+func (cs *Coscheduling) pendingMembers(pod *v1.Pod, pg *v1alpha1.PodGroup, needed int) ([]*v1.Pod, bool) {
+	pods, err := util.ListPodGroupPods(cs.frameworkHandler.SharedInformerFactory().Core().V1().Pods().Lister(),
+		pg.Namespace, pg.Name, pg.Spec.MemberNamespaces)
+	if err != nil {
+		return nil, false
+	}
+	var members []*v1.Pod
+	for _, p := range pods {
+		if p.UID == pod.UID || p.Spec.NodeName != "" || p.DeletionTimestamp != nil ||
+			p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed ||
+			cs.frameworkHandler.GetWaitingPod(p.UID) != nil {
+			continue
+		}
+		members = append(members, p)
+	}
+	if len(members) < needed-1 {
+		return nil, false
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+	return members[:max(needed-1, 0)], true
+}
+
+// victimGroups returns the PodGroups with members assigned to the nodes whose priority is lower than the one of the pod,
+// the least important first: the lowest priority, then the fewest members, so that as few pods as possible are evicted.
+// The PodGroups with members waiting in Permit or being deleted, or being checkpointed, are not preempted.
+func (cs *Coscheduling) victimGroups(pod *v1.Pod, pgName string, nodeInfos []*framework.NodeInfo) []*victimGroup {
+	groups := make(map[string]*victimGroup)
+	skipped := make(map[string]bool)
+	for _, nodeInfo := range nodeInfos {
+		for _, podInfo := range nodeInfo.Pods {
+			name := util.GetPodGroupFullName(podInfo.Pod)
+			if name == "" || name == pgName || skipped[name] {
+				continue
+			}
+			if podInfo.Pod.DeletionTimestamp != nil || cs.frameworkHandler.GetWaitingPod(podInfo.Pod.UID) != nil ||
+				(cs.checkpointTimeout > 0 && cs.isCheckpointing(name)) {
+				skipped[name] = true
+				delete(groups, name)
+				continue
+			}
+			group, ok := groups[name]
+			if !ok {
+				group = &victimGroup{name: name, priority: corev1helpers.PodPriority(podInfo.Pod)}
+				groups[name] = group
+			}
+			group.priority = max(group.priority, corev1helpers.PodPriority(podInfo.Pod))
+			group.pods = append(group.pods, victimPod{podInfo: podInfo, nodeName: nodeInfo.Node().Name})
+		}
+	}
+
+	podPriority := corev1helpers.PodPriority(pod)
+	var candidates []*victimGroup
+	for _, group := range groups {
+		if group.priority < podPriority {
+			candidates = append(candidates, group)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority < candidates[j].priority
+		}
+		if len(candidates[i].pods) != len(candidates[j].pods) {
+			return len(candidates[i].pods) < len(candidates[j].pods)
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	return candidates
+}
+
+// placeGang simulates the eviction of the victims, then places the pod on the first node, by name, passing the Filter
+// plugins, and the other members on the first nodes with enough resources for their requests. It returns the node
+// of the pod, and false if the gang doesn't fit.
+// Only the resources requested by the other members are checked: their own scheduling cycles run the Filter plugins.
+func (cs *Coscheduling) placeGang(ctx context.Context, state *framework.CycleState, pod *v1.Pod, members []*v1.Pod,
+	nodeInfos []*framework.NodeInfo, victims []*victimGroup, m framework.NodeToStatusMap) (string, bool) {
+	lh := klog.FromContext(ctx)
+	state = state.Clone()
+	original := make(map[string]*framework.NodeInfo, len(nodeInfos))
+	names := make([]string, 0, len(nodeInfos))
+	for _, nodeInfo := range nodeInfos {
+		original[nodeInfo.Node().Name] = nodeInfo
+		names = append(names, nodeInfo.Node().Name)
+	}
+	sort.Strings(names)
+	// The nodes are copied before being modified by the simulation.
+	simulated := make(map[string]*framework.NodeInfo)
+	simulatedNode := func(name string) *framework.NodeInfo {
+		if _, ok := simulated[name]; !ok {
+			simulated[name] = original[name].Snapshot()
+		}
+		return simulated[name]
+	}
+	nodeInfo := func(name string) *framework.NodeInfo {
+		if nodeInfo, ok := simulated[name]; ok {
+			return nodeInfo
+		}
+		return original[name]
+	}
+
+	for _, group := range victims {
+		for _, victim := range group.pods {
+			if _, ok := original[victim.nodeName]; !ok {
+				continue
+			}
+			nodeInfo := simulatedNode(victim.nodeName)
+			if err := nodeInfo.RemovePod(lh, victim.podInfo.Pod); err != nil {
+				return "", false
+			}
+			if status := cs.frameworkHandler.RunPreFilterExtensionRemovePod(ctx, state, pod, victim.podInfo, nodeInfo); !status.IsSuccess() {
+				return "", false
+			}
+		}
+	}
+
+	nodeName := ""
+	for _, name := range names {
+		// the nodes where the pod is unschedulable and unresolvable can't be helped
+		if m[name].Code() == framework.UnschedulableAndUnresolvable {
+			continue
+		}
+		if status := cs.frameworkHandler.RunFilterPluginsWithNominatedPods(ctx, state, pod, nodeInfo(name)); status.IsSuccess() {
+			nodeName = name
+			break
+		}
+	}
+	if nodeName == "" {
+		return "", false
+	}
+	simulatedNode(nodeName).AddPod(pod)
+
+	for _, member := range members {
+		placed := false
+		for _, name := range names {
+			if len(noderesources.Fits(member, nodeInfo(name))) == 0 {
+				simulatedNode(name).AddPod(member)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			return "", false
+		}
+	}
+	return nodeName, true
+}
+
+// evictPodGroup deletes the assigned members of the victim PodGroup, adding them the DisruptionTarget condition.
+func (cs *Coscheduling) evictPodGroup(ctx context.Context, preemptor *v1.Pod, pgName string, group *victimGroup) error {
+	lh := klog.FromContext(ctx)
+	client := cs.frameworkHandler.ClientSet()
+	for _, vp := range group.pods {
+		victim := vp.podInfo.Pod
+		condition := &v1.PodCondition{
+			Type:    v1.DisruptionTarget,
+			Status:  v1.ConditionTrue,
+			Reason:  v1.PodReasonPreemptionByScheduler,
+			Message: fmt.Sprintf("%s: preempting to accommodate the higher priority PodGroup %s", preemptor.Spec.SchedulerName, pgName),
+		}
+		newStatus := victim.Status.DeepCopy()
+		if apipod.UpdatePodCondition(newStatus, condition) {
+			if err := schedutil.PatchPodStatus(ctx, client, victim, newStatus); err != nil {
+				lh.Error(err, "Could not add DisruptionTarget condition due to gang preemption", "pod", klog.KObj(victim), "preemptor", klog.KObj(preemptor))
+				return err
+			}
+		}
+		if err := schedutil.DeletePod(ctx, client, victim); err != nil {
+			lh.Error(err, "Could not preempt the pod", "pod", klog.KObj(victim), "preemptor", klog.KObj(preemptor))
+			return err
+		}
+		lh.V(2).Info("Preemptor PodGroup preempted victim PodGroup", "preemptor", klog.KObj(preemptor), "podGroup", pgName,
+			"victim", klog.KObj(victim), "victimPodGroup", group.name, "node", vp.nodeName)
+		cs.frameworkHandler.EventRecorder().Eventf(victim, preemptor, v1.EventTypeNormal, "Preempted", "Preempting",
+			"Preempted with PodGroup %v by pod %v of PodGroup %v", group.name, preemptor.UID, pgName)
+	}
+	return nil
+}
+
+// disruptionBudgets tracks the disruptions still allowed by the PodDisruptionBudgets while victims are selected.
+type disruptionBudgets struct {
+	pdbs    []*policy.PodDisruptionBudget
+	allowed []int32
+}
+
+func newDisruptionBudgets(pdbs []*policy.PodDisruptionBudget) *disruptionBudgets {
+	allowed := make([]int32, len(pdbs))
+	for i, pdb := range pdbs {
+		allowed[i] = pdb.Status.DisruptionsAllowed
+	}
+	return &disruptionBudgets{pdbs: pdbs, allowed: allowed}
+}
+
+// allow returns whether evicting all the members of the group violates no PodDisruptionBudget, and if so,
+// consumes the disruptions.
+func (b *disruptionBudgets) allow(group *victimGroup) bool {
+	allowed := append([]int32{}, b.allowed...)
+	for _, vp := range group.pods {
+		pod := vp.podInfo.Pod
+		// A pod with no labels will not match any PDB. So, no need to check.
+		if len(pod.Labels) == 0 {
+			continue
+		}
+		for i, pdb := range b.pdbs {
+			if pdb.Namespace != pod.Namespace {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			// A PDB with a nil or empty selector matches nothing.
+			if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			// Existing in DisruptedPods means it has been processed in API server,
+			// we don't treat it as a violating case.
+			if _, exist := pdb.Status.DisruptedPods[pod.Name]; exist {
+				continue
+			}
+			allowed[i]--
+			if allowed[i] < 0 {
+				return false
+			}
+		}
+	}
+	b.allowed = allowed
+	return true
+}
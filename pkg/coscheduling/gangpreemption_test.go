@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func makeVictimGroup(name string, podLabels map[string]string, podNames ...string) *victimGroup {
+	group := &victimGroup{name: "ns1/" + name}
+	for _, podName := range podNames {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: "ns1", Labels: podLabels}}
+		podInfo, _ := framework.NewPodInfo(pod)
+		group.pods = append(group.pods, victimPod{podInfo: podInfo, nodeName: "node1"})
+	}
+	return group
+}
+
+func TestDisruptionBudgetsAllow(t *testing.T) {
+	pdb := func(allowed int32, disrupted ...string) *policy.PodDisruptionBudget {
+		pdb := &policy.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "ns1"},
+			Spec: policy.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			Status: policy.PodDisruptionBudgetStatus{DisruptionsAllowed: allowed, DisruptedPods: map[string]metav1.Time{}},
+		}
+		for _, name := range disrupted {
+			pdb.Status.DisruptedPods[name] = metav1.Now()
+		}
+		return pdb
+	}
+	web := map[string]string{"app": "web"}
+
+	tests := []struct {
+		name   string
+		pdbs   []*policy.PodDisruptionBudget
+		groups []*victimGroup
+		want   []bool
+	}{
+		{
+			name:   "no PodDisruptionBudget",
+			groups: []*victimGroup{makeVictimGroup("pg1", web, "p1", "p2")},
+			want:   []bool{true},
+		},
+		{
+			name:   "the whole group fits in the budget",
+			pdbs:   []*policy.PodDisruptionBudget{pdb(2)},
+			groups: []*victimGroup{makeVictimGroup("pg1", web, "p1", "p2")},
+			want:   []bool{true},
+		},
+		{
+			name:   "evicting only some members would fit in the budget",
+			pdbs:   []*policy.PodDisruptionBudget{pdb(1)},
+			groups: []*victimGroup{makeVictimGroup("pg1", web, "p1", "p2")},
+			want:   []bool{false},
+		},
+		{
+			name:   "the disruptions are consumed by the previous groups",
+			pdbs:   []*policy.PodDisruptionBudget{pdb(2)},
+			groups: []*victimGroup{makeVictimGroup("pg1", web, "p1"), makeVictimGroup("pg2", web, "p2", "p3"), makeVictimGroup("pg3", web, "p4")},
+			want:   []bool{true, false, true},
+		},
+		{
+			name:   "the pods already disrupted don't count",
+			pdbs:   []*policy.PodDisruptionBudget{pdb(1, "p1")},
+			groups: []*victimGroup{makeVictimGroup("pg1", web, "p1", "p2")},
+			want:   []bool{true},
+		},
+		{
+			name:   "the pods not matching the selector don't count",
+			pdbs:   []*policy.PodDisruptionBudget{pdb(0)},
+			groups: []*victimGroup{makeVictimGroup("pg1", map[string]string{"app": "db"}, "p1"), makeVictimGroup("pg2", nil, "p2")},
+			want:   []bool{true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budgets := newDisruptionBudgets(tt.pdbs)
+			for i, group := range tt.groups {
+				if got := budgets.allow(group); got != tt.want[i] {
+					t.Errorf("allow(%v) = %v, want %v", group.name, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const metricsSubsystem = "coscheduling"
+
+// Reasons for rejecting a gang, i.e. the members of a PodGroup waiting in Permit.
+const (
+	// RejectionUnschedulable means a member of the PodGroup is unschedulable even after PostFilter.
+	RejectionUnschedulable = "unschedulable"
+	// RejectionInfeasible means the PodGroup can no longer fit at Permit.
+	RejectionInfeasible = "infeasible"
+	// RejectionTimeout means a member of the PodGroup timed out in Permit.
+	RejectionTimeout = "timeout"
+	// RejectionUnreserved means a member of the PodGroup got unreserved, e.g. after failing to bind.
+	RejectionUnreserved = "unreserved"
+	// RejectionProgressDeadline means the PodGroup made no sufficient progress within its progress deadline.
+	RejectionProgressDeadline = "progress_deadline"
+)
+
+var (
+	// PodGroupScheduleLatency is the time between the start of the scheduling of a PodGroup, when minMember
+	// members were created, and its members passing Permit together.
+	PodGroupScheduleLatency = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "podgroup_schedule_latency_seconds",
+			Help:           "Time between the start of the scheduling of a PodGroup and its members passing Permit together, in seconds.",
+			Buckets:        metrics.ExponentialBuckets(0.1, 2, 15),
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// GangBindDelay is the time between the members of a PodGroup passing Permit together and each of them
+	// reaching PreBind, i.e. how long the gang stays half-bound.
+	GangBindDelay = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "gang_bind_delay_seconds",
+			Help:           "Time between the members of a PodGroup passing Permit together and each of them reaching PreBind, in seconds.",
+			Buckets:        metrics.ExponentialBuckets(0.001, 2, 15),
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// PodGroupsWaiting is the number of PodGroups with members waiting in Permit.
+	PodGroupsWaiting = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "podgroups_waiting",
+			Help:           "Number of PodGroups with members waiting in Permit.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// GangPermitTimeouts is the number of PodGroups released because a member timed out in Permit.
+	GangPermitTimeouts = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "gang_permit_timeouts_total",
+			Help:           "Number of PodGroups released because a member timed out in Permit.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// GangPermitExtensions is the number of extensions of the Permit wait of PodGroups while the cluster scaled up for them.
+	GangPermitExtensions = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "gang_permit_extensions_total",
+			Help:           "Number of extensions of the Permit wait of PodGroups while the cluster scaled up for them.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// GangRejections is the number of rejections of PodGroups by reason.
+	GangRejections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "gang_rejections_total",
+			Help:           "Number of rejections of PodGroups by reason: unschedulable, infeasible, timeout, unreserved or progress_deadline.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"reason"})
+
+	// MemberRejections is the number of rejections of the members of PodGroups by type.
+	MemberRejections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "member_rejections_total",
+			Help:           "Number of rejections of the members of PodGroups by type: Quota, Affinity, Resources or Other.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"type"})
+
+	metricsList = []metrics.Registerable{
+		PodGroupScheduleLatency,
+		GangBindDelay,
+		PodGroupsWaiting,
+		GangPermitTimeouts,
+		GangPermitExtensions,
+		GangRejections,
+		MemberRejections,
+	}
+)
+
+var registerMetrics sync.Once
+
+// Register registers the metrics of the Coscheduling plugin in the legacy registry, exposed by the scheduler.
+func Register() {
+	registerMetrics.Do(func() {
+		for _, metric := range metricsList {
+			legacyregistry.MustRegister(metric)
+		}
+	})
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/coscheduling/metrics"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+const (
+	// TriggeredScaleUp is the reason of the events the cluster autoscaler records on the pods it scales the cluster up for.
+	TriggeredScaleUp = "TriggeredScaleUp"
+
+	// PermitWaitExtended is the reason of the event recorded when the Permit wait of a PodGroup is extended
+	// because the cluster scales up for it.
+	PermitWaitExtended = "PermitWaitExtended"
+
+	// scaleUpRetention is how long a scale-up is deemed in progress once triggered or once its nodes registered,
+	// the default max-node-provision-time of the cluster autoscaler.
+	scaleUpRetention = 15 * time.Minute
+)
+
+// watchScaleUps records the scale-ups the cluster autoscaler triggers for the members of pod groups, from the
+// TriggeredScaleUp events it records on them.
+func (cs *Coscheduling) watchScaleUps(ctx context.Context) error {
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(cs.frameworkHandler.ClientSet(), 0,
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("reason", TriggeredScaleUp).String()
+		}))
+	if _, err := informerFactory.Core().V1().Events().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    cs.recordScaleUp,
+		UpdateFunc: func(_, newObj interface{}) { cs.recordScaleUp(newObj) },
+	}); err != nil {
+		return err
+	}
+	informerFactory.Start(ctx.Done())
+	return nil
+}
+
+// recordScaleUp remembers when the scale-up of the event was last triggered for the PodGroup of the pod it is about,
+// and forgets the scale-ups no longer deemed in progress.
+func (cs *Coscheduling) recordScaleUp(obj interface{}) {
+	event, ok := obj.(*v1.Event)
+	if !ok || event.Reason != TriggeredScaleUp || event.InvolvedObject.Kind != "Pod" {
+		return
+	}
+	pod, err := cs.frameworkHandler.SharedInformerFactory().Core().V1().Pods().Lister().
+		Pods(event.InvolvedObject.Namespace).Get(event.InvolvedObject.Name)
+	if err != nil {
+		return
+	}
+	pgFullName := util.GetPodGroupFullName(pod)
+	if pgFullName == "" {
+		return
+	}
+
+	triggered := event.LastTimestamp.Time
+	if triggered.IsZero() {
+		triggered = event.EventTime.Time
+	}
+	if triggered.IsZero() {
+		triggered = event.CreationTimestamp.Time
+	}
+	now := time.Now()
+	cs.scaleUpLock.Lock()
+	defer cs.scaleUpLock.Unlock()
+	if cs.scaleUps == nil {
+		cs.scaleUps = make(map[string]time.Time)
+	}
+	if triggered.After(cs.scaleUps[pgFullName]) {
+		cs.scaleUps[pgFullName] = triggered
+	}
+	for name, t := range cs.scaleUps {
+		if now.Sub(t) > scaleUpRetention {
+			delete(cs.scaleUps, name)
+		}
+	}
+}
+
+// forgetScaleUp forgets the scale-up triggered for the PodGroup, if any.
+func (cs *Coscheduling) forgetScaleUp(pgFullName string) {
+	cs.scaleUpLock.Lock()
+	defer cs.scaleUpLock.Unlock()
+	delete(cs.scaleUps, pgFullName)
+}
+
+// startPermitDeadline starts the Permit deadline timer of the PodGroup, if no timer is running yet, i.e. when its
+// first member waits in Permit. The members are then rejected at their deadline by the plugin rather than by the
+// framework, unless the cluster scales up for them.
+func (cs *Coscheduling) startPermitDeadline(ctx context.Context, pgFullName string, pg *v1alpha1.PodGroup, waitTime time.Duration) {
+	cs.scaleUpLock.Lock()
+	defer cs.scaleUpLock.Unlock()
+	if cs.permitTimers == nil {
+		cs.permitTimers = make(map[string]*time.Timer)
+	}
+	if _, ok := cs.permitTimers[pgFullName]; ok {
+		return
+	}
+	lh := klog.FromContext(ctx)
+	ctx = klog.NewContext(context.Background(), lh)
+	cs.permitTimers[pgFullName] = time.AfterFunc(waitTime, func() {
+		cs.scaleUpLock.Lock()
+		delete(cs.permitTimers, pgFullName)
+		cs.scaleUpLock.Unlock()
+		cs.checkPermitDeadline(ctx, pgFullName, pg, waitTime)
+	})
+}
+
+// stopPermitDeadline stops the Permit deadline timer of the PodGroup, if any.
+func (cs *Coscheduling) stopPermitDeadline(pgFullName string) {
+	cs.scaleUpLock.Lock()
+	defer cs.scaleUpLock.Unlock()
+	if timer, ok := cs.permitTimers[pgFullName]; ok {
+		timer.Stop()
+		delete(cs.permitTimers, pgFullName)
+	}
+}
+
+// checkPermitDeadline extends the deadline of the members of the PodGroup waiting in Permit by the scale-up
+// extension if the cluster scales up for them, the framework rejecting them at the extended deadline. Otherwise,
+// it rejects the members whose deadline is exceeded, Unreserve releasing the whole PodGroup as on a timeout.
+func (cs *Coscheduling) checkPermitDeadline(ctx context.Context, pgFullName string, pg *v1alpha1.PodGroup, waitTime time.Duration) {
+	lh := klog.FromContext(ctx)
+	var waiting []framework.WaitingPod
+	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		if util.GetPodGroupFullName(waitingPod.GetPod()) == pgFullName {
+			waiting = append(waiting, waitingPod)
+		}
+	})
+	if len(waiting) == 0 {
+		// The members already got allowed or rejected.
+		return
+	}
+
+	if reason := cs.scaleUpReason(pgFullName, waiting); reason != "" {
+		for _, waitingPod := range waiting {
+			cs.extendWaitingDeadline(waitingPod.GetPod(), cs.scaleUpExtension)
+		}
+		msg := fmt.Sprintf("Permit wait of PodGroup %v extended by %v: %v", pgFullName, cs.scaleUpExtension, reason)
+		lh.V(3).Info("Extending the Permit wait while the cluster scales up", "podGroup", pgFullName,
+			"extension", cs.scaleUpExtension, "reason", reason)
+		metrics.GangPermitExtensions.Inc()
+		if recorder := cs.frameworkHandler.EventRecorder(); recorder != nil && pg != nil {
+			recorder.Eventf(pg, nil, v1.EventTypeNormal, PermitWaitExtended, "Scheduling", "%v", msg)
+		}
+		return
+	}
+
+	msg := fmt.Sprintf("PodGroup %v timed out after waiting %v in Permit, without scale-up of the cluster for it", pgFullName, waitTime)
+	for _, waitingPod := range waiting {
+		if cs.waitingDeadlineExceeded(waitingPod.GetPod()) {
+			lh.V(3).Info("Permit deadline exceeded", "podGroup", pgFullName, "pod", klog.KObj(waitingPod.GetPod()))
+			waitingPod.Reject(cs.Name(), msg)
+		}
+	}
+}
+
+// scaleUpReason returns why the cluster is deemed to scale up for the members of the PodGroup, or an empty string
+// if it is not: the cluster autoscaler recently triggered a scale-up for some of its members, or a node fitting
+// one of its pending members registered recently and is not Ready yet.
+func (cs *Coscheduling) scaleUpReason(pgFullName string, waiting []framework.WaitingPod) string {
+	now := time.Now()
+	cs.scaleUpLock.Lock()
+	triggered, ok := cs.scaleUps[pgFullName]
+	cs.scaleUpLock.Unlock()
+	if ok && now.Sub(triggered) <= scaleUpRetention {
+		return fmt.Sprintf("the cluster autoscaler triggered a scale-up for its members %v ago", now.Sub(triggered).Round(time.Second))
+	}
+
+	pending := make([]*v1.Pod, 0, len(waiting))
+	for _, waitingPod := range waiting {
+		pending = append(pending, waitingPod.GetPod())
+	}
+	namespace, name, _ := strings.Cut(pgFullName, "/")
+	if pods, err := cs.listPodGroupPods(namespace, name); err == nil {
+		for _, p := range pods {
+			if p.Spec.NodeName == "" {
+				pending = append(pending, p)
+			}
+		}
+	}
+	nodes, err := cs.nodeLister.List(labels.Everything())
+	if err != nil {
+		return ""
+	}
+	for _, node := range nodes {
+		if isNodeReady(node) || now.Sub(node.CreationTimestamp.Time) > scaleUpRetention {
+			continue
+		}
+		for _, p := range pending {
+			if fitsAllocatable(p, node) {
+				return fmt.Sprintf("node %v fitting its members is not Ready yet", node.Name)
+			}
+		}
+	}
+	return ""
+}
+
+// isNodeReady returns whether the Ready condition of the node is true.
+func isNodeReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// fitsAllocatable returns whether the allocatable resources of the node fit the requests of the pod.
+func fitsAllocatable(pod *v1.Pod, node *v1.Node) bool {
+	for name, request := range resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{}) {
+		if request.IsZero() {
+			continue
+		}
+		if allocatable, ok := node.Status.Allocatable[name]; !ok || allocatable.Cmp(request) < 0 {
+			return false
+		}
+	}
+	return true
+}
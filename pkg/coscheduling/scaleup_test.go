@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	fwkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// newScaleUpTestPlugin returns a plugin extending the Permit wait by a minute, with the pods in the informer
+// and the waiting pods waiting in Permit.
+func newScaleUpTestPlugin(t *testing.T, ctx context.Context, pods, waitingPods []*v1.Pod, nodes []*v1.Node) (*Coscheduling, *fakeWaitingPodsHandle) {
+	informerFactory := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)
+	f, err := tf.NewFramework(
+		ctx,
+		[]tf.RegisterPluginFunc{
+			tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		},
+		"default-scheduler",
+		fwkruntime.WithInformerFactory(informerFactory),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range pods {
+		informerFactory.Core().V1().Pods().Informer().GetStore().Add(p)
+	}
+	for _, n := range nodes {
+		informerFactory.Core().V1().Nodes().Informer().GetStore().Add(n)
+	}
+	handle := &fakeWaitingPodsHandle{Framework: f}
+	for _, p := range waitingPods {
+		handle.waitingPods = append(handle.waitingPods, &fakeWaitingPod{pod: p})
+	}
+	return &Coscheduling{
+		frameworkHandler: handle,
+		scaleUpExtension: time.Minute,
+		nodeLister:       informerFactory.Core().V1().Nodes().Lister(),
+	}, handle
+}
+
+func TestCheckPermitDeadline(t *testing.T) {
+	now := time.Now()
+	p1 := st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj()
+	p2 := st.MakePod().Name("p2").Namespace("ns").UID("p2").Label(v1alpha1.PodGroupLabel, "pg1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj()
+	p3 := st.MakePod().Name("p3").Namespace("ns").UID("p3").Label(v1alpha1.PodGroupLabel, "pg1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj()
+	makeNode := func(name, cpu string, created time.Time, ready bool) *v1.Node {
+		node := st.MakeNode().Name(name).Capacity(map[v1.ResourceName]string{v1.ResourceCPU: cpu}).Obj()
+		node.CreationTimestamp = metav1.NewTime(created)
+		if ready {
+			node.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}
+		}
+		return node
+	}
+
+	tests := []struct {
+		name         string
+		pods         []*v1.Pod
+		nodes        []*v1.Node
+		scaleUp      time.Time
+		wantRejected []string
+		wantExtended bool
+	}{
+		{
+			name:         "no scale-up, reject the members past their deadline",
+			wantRejected: []string{"p1"},
+		},
+		{
+			name:         "scale-up triggered for the members, extend the wait",
+			scaleUp:      now.Add(-5 * time.Minute),
+			wantExtended: true,
+		},
+		{
+			name:         "scale-up triggered long ago, reject the members past their deadline",
+			scaleUp:      now.Add(-time.Hour),
+			wantRejected: []string{"p1"},
+		},
+		{
+			name:         "upcoming node fitting the members, extend the wait",
+			nodes:        []*v1.Node{makeNode("n1", "2", now.Add(-time.Minute), false)},
+			wantExtended: true,
+		},
+		{
+			name:         "upcoming node fitting a pending member, extend the wait",
+			pods:         []*v1.Pod{p3},
+			nodes:        []*v1.Node{makeNode("n1", "8", now.Add(-time.Minute), false)},
+			wantExtended: true,
+		},
+		{
+			name:         "upcoming node too small for the members, reject the members past their deadline",
+			nodes:        []*v1.Node{makeNode("n1", "1", now.Add(-time.Minute), false)},
+			wantRejected: []string{"p1"},
+		},
+		{
+			name: "nodes ready or not ready for long, reject the members past their deadline",
+			nodes: []*v1.Node{
+				makeNode("n1", "8", now.Add(-time.Minute), true),
+				makeNode("n2", "8", now.Add(-time.Hour), false),
+			},
+			wantRejected: []string{"p1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			pl, handle := newScaleUpTestPlugin(t, ctx, tt.pods, []*v1.Pod{p1, p2}, tt.nodes)
+			deadlines := map[types.UID]time.Time{"p1": now.Add(-time.Second), "p2": now.Add(30 * time.Second)}
+			pl.waitingDeadlines = map[types.UID]time.Time{"p1": deadlines["p1"], "p2": deadlines["p2"]}
+			if !tt.scaleUp.IsZero() {
+				pl.scaleUps = map[string]time.Time{"ns/pg1": tt.scaleUp}
+			}
+
+			pl.checkPermitDeadline(ctx, "ns/pg1", nil, 10*time.Second)
+
+			var rejected []string
+			for _, w := range handle.waitingPods {
+				if w.rejected != "" {
+					rejected = append(rejected, w.pod.Name)
+				}
+			}
+			if len(rejected) != len(tt.wantRejected) || (len(rejected) > 0 && rejected[0] != tt.wantRejected[0]) {
+				t.Errorf("expected rejected %v, got %v", tt.wantRejected, rejected)
+			}
+			for uid, deadline := range deadlines {
+				want := deadline
+				if tt.wantExtended {
+					want = deadline.Add(time.Minute)
+				}
+				if got := pl.waitingDeadlines[uid]; !got.Equal(want) {
+					t.Errorf("expected the deadline of %v at %v, got %v", uid, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordScaleUp(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	member := st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg1").Obj()
+	single := st.MakePod().Name("p2").Namespace("ns").UID("p2").Obj()
+	pl, _ := newScaleUpTestPlugin(t, ctx, []*v1.Pod{member, single}, nil, nil)
+	makeEvent := func(pod, reason string, at time.Time) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: pod + "." + reason, Namespace: "ns"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "ns", Name: pod},
+			Reason:         reason,
+			LastTimestamp:  metav1.NewTime(at),
+		}
+	}
+	now := time.Now().Truncate(time.Second)
+
+	pl.recordScaleUp(makeEvent("p1", "NotTriggerScaleUp", now))
+	pl.recordScaleUp(makeEvent("p2", TriggeredScaleUp, now))
+	pl.recordScaleUp(makeEvent("p3", TriggeredScaleUp, now))
+	if len(pl.scaleUps) != 0 {
+		t.Errorf("expected no scale-up recorded, got %v", pl.scaleUps)
+	}
+
+	pl.recordScaleUp(makeEvent("p1", TriggeredScaleUp, now.Add(-time.Minute)))
+	pl.recordScaleUp(makeEvent("p1", TriggeredScaleUp, now))
+	pl.recordScaleUp(makeEvent("p1", TriggeredScaleUp, now.Add(-2*time.Minute)))
+	if got := pl.scaleUps["ns/pg1"]; !got.Equal(now) {
+		t.Errorf("expected the last scale-up of ns/pg1 at %v, got %v", now, got)
+	}
+
+	pl.scaleUps["ns/pg2"] = now.Add(-time.Hour)
+	pl.recordScaleUp(makeEvent("p1", TriggeredScaleUp, now))
+	if _, ok := pl.scaleUps["ns/pg2"]; ok {
+		t.Errorf("expected the scale-up of ns/pg2 to be forgotten")
+	}
+}
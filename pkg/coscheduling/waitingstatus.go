@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+// waitingDeadlineRetention is how long the deadline of a pod is remembered once exceeded.
+const waitingDeadlineRetention = time.Minute
+
+// recordWaitingDeadline remembers when the pod waiting in Permit times out.
+func (cs *Coscheduling) recordWaitingDeadline(pod *v1.Pod, waitTime time.Duration) {
+	cs.waitingLock.Lock()
+	defer cs.waitingLock.Unlock()
+	if cs.waitingDeadlines == nil {
+		cs.waitingDeadlines = make(map[types.UID]time.Time)
+	}
+	cs.waitingDeadlines[pod.UID] = time.Now().Add(waitTime)
+}
+
+// extendWaitingDeadline extends the deadline of the pod waiting in Permit, if it has one.
+func (cs *Coscheduling) extendWaitingDeadline(pod *v1.Pod, extension time.Duration) {
+	cs.waitingLock.Lock()
+	defer cs.waitingLock.Unlock()
+	if deadline, ok := cs.waitingDeadlines[pod.UID]; ok {
+		cs.waitingDeadlines[pod.UID] = deadline.Add(extension)
+	}
+}
+
+// waitingDeadlineExceeded returns whether the pod waiting in Permit has a deadline, and exceeded it.
+func (cs *Coscheduling) waitingDeadlineExceeded(pod *v1.Pod) bool {
+	cs.waitingLock.Lock()
+	defer cs.waitingLock.Unlock()
+	deadline, ok := cs.waitingDeadlines[pod.UID]
+	return ok && !deadline.After(time.Now())
+}
+
+// forgetWaitingDeadline forgets the deadline of the pod once it is allowed or rejected. It returns whether
+// the pod was still waiting, i.e. it had a deadline, and whether it timed out, i.e. its deadline was exceeded.
+func (cs *Coscheduling) forgetWaitingDeadline(pod *v1.Pod) (bool, bool) {
+	cs.waitingLock.Lock()
+	defer cs.waitingLock.Unlock()
+	deadline, ok := cs.waitingDeadlines[pod.UID]
+	delete(cs.waitingDeadlines, pod.UID)
+	return ok, ok && !deadline.After(time.Now())
+}
+
+// waitingMembers returns the members waiting in Permit per PodGroup, sorted by name,
+// and forgets the deadlines of the pods which timed out a while ago.
+func (cs *Coscheduling) waitingMembers() map[string][]v1alpha1.WaitingMember {
+	// The waiting lock is taken once the waiting pods are iterated over, the framework holding its own lock
+	// during the iteration.
+	var pods []*v1.Pod
+	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		pods = append(pods, waitingPod.GetPod())
+	})
+
+	cs.waitingLock.Lock()
+	defer cs.waitingLock.Unlock()
+	members := make(map[string][]v1alpha1.WaitingMember)
+	for _, pod := range pods {
+		pgFullName := util.GetPodGroupFullName(pod)
+		deadline, ok := cs.waitingDeadlines[pod.UID]
+		if pgFullName == "" || !ok {
+			continue
+		}
+		members[pgFullName] = append(members[pgFullName], v1alpha1.WaitingMember{Name: pod.Name, Deadline: metav1.NewTime(deadline)})
+	}
+	// The deadline of a pod is recorded before it is added to the waiting pods: keep it until it is exceeded,
+	// and a while after for Unreserve to tell the pods which timed out, in case they were not released.
+	now := time.Now()
+	for uid, deadline := range cs.waitingDeadlines {
+		if deadline.Add(waitingDeadlineRetention).Before(now) {
+			delete(cs.waitingDeadlines, uid)
+		}
+	}
+	for _, m := range members {
+		sort.Slice(m, func(i, j int) bool { return m[i].Name < m[j].Name })
+	}
+	return members
+}
+
+// waitingReport is the report of the members of a pod group waiting in Permit, and of the members blocking them.
+type waitingReport struct {
+	waiting  []v1alpha1.WaitingMember
+	blocking []v1alpha1.BlockingMember
+	blockers []v1alpha1.GangBlocker
+}
+
+// syncWaitingMembers reports the members waiting in Permit in the status of their PodGroup, so that
+// external controllers can act on partially admitted gangs, along with the members blocking them and
+// their last rejection. Only the PodGroups whose report changed since the last one are patched, the
+// ones no longer waiting get their report cleared.
+func (cs *Coscheduling) syncWaitingMembers(ctx context.Context) {
+	lh := klog.FromContext(ctx)
+	members := cs.waitingMembers()
+	cs.forgetRejections()
+	if cs.reportedWaiting == nil {
+		cs.reportedWaiting = make(map[string]waitingReport)
+	}
+	reports := make(map[string]waitingReport, len(members))
+	pgFullNames := make([]string, 0, len(members)+len(cs.reportedWaiting))
+	for pgFullName, waiting := range members {
+		report := waitingReport{waiting: waiting}
+		var err error
+		if report.blocking, report.blockers, err = cs.blockingMembers(pgFullName, waiting); err != nil {
+			lh.Error(err, "Failed to obtain the members blocking the PodGroup", "podGroup", pgFullName)
+		}
+		reports[pgFullName] = report
+		pgFullNames = append(pgFullNames, pgFullName)
+	}
+	for pgFullName := range cs.reportedWaiting {
+		if _, ok := members[pgFullName]; !ok {
+			pgFullNames = append(pgFullNames, pgFullName)
+		}
+	}
+
+	for _, pgFullName := range pgFullNames {
+		report := reports[pgFullName]
+		if reflect.DeepEqual(report, cs.reportedWaiting[pgFullName]) {
+			continue
+		}
+		if err := cs.patchWaitingMembers(ctx, pgFullName, report); err != nil {
+			if !apierrors.IsNotFound(err) {
+				// Retry at the next sync.
+				lh.Error(err, "Failed to report the waiting members in the PodGroup status", "podGroup", pgFullName)
+				continue
+			}
+			report = waitingReport{}
+		}
+		if len(report.waiting) == 0 {
+			delete(cs.reportedWaiting, pgFullName)
+		} else {
+			cs.reportedWaiting[pgFullName] = report
+		}
+	}
+}
+
+func (cs *Coscheduling) patchWaitingMembers(ctx context.Context, pgFullName string, report waitingReport) error {
+	namespace, name, _ := strings.Cut(pgFullName, "/")
+	pg := &v1alpha1.PodGroup{}
+	if err := cs.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, pg); err != nil {
+		return err
+	}
+	pgCopy := pg.DeepCopy()
+	pgCopy.Status.WaitingMembers = report.waiting
+	pgCopy.Status.BlockingMembers = report.blocking
+	pgCopy.Status.Blockers = report.blockers
+	return cs.client.Status().Patch(ctx, pgCopy, client.MergeFrom(pg))
+}
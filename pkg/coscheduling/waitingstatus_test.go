@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	fwkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestSyncWaitingMembers(t *testing.T) {
+	ctx := context.Background()
+	pg1 := &v1alpha1.PodGroup{ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: "ns"}, Spec: v1alpha1.PodGroupSpec{MinMember: 3}}
+	pg2 := &v1alpha1.PodGroup{ObjectMeta: metav1.ObjectMeta{Name: "pg2", Namespace: "ns"}, Spec: v1alpha1.PodGroupSpec{MinMember: 2}}
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.PodGroup{}).
+		WithRuntimeObjects(pg1, pg2).
+		Build()
+	p1 := st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg1").Obj()
+	p2 := st.MakePod().Name("p2").Namespace("ns").UID("p2").Label(v1alpha1.PodGroupLabel, "pg1").Obj()
+	p3 := st.MakePod().Name("p3").Namespace("ns").UID("p3").Label(v1alpha1.PodGroupLabel, "pg2").Obj()
+	// p4 is waiting for another plugin.
+	p4 := st.MakePod().Name("p4").Namespace("ns").UID("p4").Obj()
+	// p5 blocks pg1, p6 is bound.
+	p5 := st.MakePod().Name("p5").Namespace("ns").UID("p5").Label(v1alpha1.PodGroupLabel, "pg1").Obj()
+	p6 := st.MakePod().Name("p6").Namespace("ns").UID("p6").Label(v1alpha1.PodGroupLabel, "pg2").Node("node").Obj()
+
+	informerFactory := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	for _, p := range []*v1.Pod{p1, p2, p3, p4, p5, p6} {
+		if err := podInformer.Informer().GetStore().Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := tf.NewFramework(ctx,
+		[]tf.RegisterPluginFunc{
+			tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		},
+		"default-scheduler",
+		fwkruntime.WithInformerFactory(informerFactory),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle := &fakeWaitingPodsHandle{Framework: f}
+	for _, p := range []*v1.Pod{p2, p1, p3, p4} {
+		handle.waitingPods = append(handle.waitingPods, &fakeWaitingPod{pod: p})
+	}
+	pl := &Coscheduling{
+		frameworkHandler:      handle,
+		waitingStatusInterval: time.Second,
+		client:                client,
+	}
+	for _, p := range []*v1.Pod{p1, p2, p3, p4} {
+		pl.recordWaitingDeadline(p, time.Minute)
+	}
+	pl.recordRejection(p5, framework.NodeToStatusMap{
+		"node": framework.NewStatus(framework.Unschedulable, "Insufficient cpu").WithPlugin(names.NodeResourcesFit),
+	})
+	// The deadline of p4 is forgotten once exceeded for a while.
+	pl.waitingDeadlines[p4.UID] = time.Now().Add(-waitingDeadlineRetention - time.Second)
+
+	getStatus := func(name string) v1alpha1.PodGroupStatus {
+		pg := &v1alpha1.PodGroup{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: name}, pg); err != nil {
+			t.Fatal(err)
+		}
+		return pg.Status
+	}
+	getWaitingMembers := func(name string) []v1alpha1.WaitingMember {
+		return getStatus(name).WaitingMembers
+	}
+
+	pl.syncWaitingMembers(ctx)
+	got := getWaitingMembers("pg1")
+	if len(got) != 2 || got[0].Name != "p1" || got[1].Name != "p2" {
+		t.Errorf("expected p1 and p2 waiting in the status of pg1, got %v", got)
+	}
+	// The deadlines are serialized with a precision of a second.
+	if want := pl.waitingDeadlines[p1.UID].Truncate(time.Second); !got[0].Deadline.Time.Equal(want) {
+		t.Errorf("expected the deadline of p1 %v, got %v", want, got[0].Deadline)
+	}
+	if got := getWaitingMembers("pg2"); len(got) != 1 || got[0].Name != "p3" {
+		t.Errorf("expected p3 waiting in the status of pg2, got %v", got)
+	}
+	if _, ok := pl.waitingDeadlines[p4.UID]; ok {
+		t.Error("expected the exceeded deadline of p4 to be forgotten")
+	}
+	status := getStatus("pg1")
+	wantBlocking := []v1alpha1.BlockingMember{
+		{Name: "p5", Type: v1alpha1.GangBlockerResources, Message: "0/1 nodes are available: 1 Insufficient cpu."},
+	}
+	if !reflect.DeepEqual(status.BlockingMembers, wantBlocking) {
+		t.Errorf("expected p5 blocking pg1, got %v", status.BlockingMembers)
+	}
+	if want := []v1alpha1.GangBlocker{{Type: v1alpha1.GangBlockerResources, Members: 1}}; !reflect.DeepEqual(status.Blockers, want) {
+		t.Errorf("expected pg1 blocked by resources, got %v", status.Blockers)
+	}
+	if status := getStatus("pg2"); len(status.BlockingMembers) != 0 || len(status.Blockers) != 0 {
+		t.Errorf("expected no member blocking pg2, got %v", status.BlockingMembers)
+	}
+
+	// pg2 got admitted and p1 rejected: pg2 gets its report cleared.
+	handle.waitingPods = []*fakeWaitingPod{{pod: p2}}
+	pl.syncWaitingMembers(ctx)
+	if got := getWaitingMembers("pg1"); len(got) != 1 || got[0].Name != "p2" {
+		t.Errorf("expected p2 waiting in the status of pg1, got %v", got)
+	}
+	if got := getWaitingMembers("pg2"); len(got) != 0 {
+		t.Errorf("expected no member waiting in the status of pg2, got %v", got)
+	}
+	if _, ok := pl.reportedWaiting["ns/pg2"]; ok {
+		t.Error("expected pg2 to be no longer reported")
+	}
+
+	// p5 got bound: its rejection is forgotten, and p1 rejected at Permit blocks pg1 instead.
+	p5.Spec.NodeName = "node"
+	if err := podInformer.Informer().GetStore().Update(p5); err != nil {
+		t.Fatal(err)
+	}
+	pl.syncWaitingMembers(ctx)
+	status = getStatus("pg1")
+	if want := []v1alpha1.BlockingMember{{Name: "p1"}}; !reflect.DeepEqual(status.BlockingMembers, want) || len(status.Blockers) != 0 {
+		t.Errorf("expected p1 blocking pg1 without a rejection, got %v", status.BlockingMembers)
+	}
+	if _, ok := pl.rejections[p5.UID]; ok {
+		t.Error("expected the rejection of p5 to be forgotten")
+	}
+}
+
+func TestRejectionOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    framework.NodeToStatusMap
+		wantType    v1alpha1.GangBlockerType
+		wantMessage string
+	}{
+		{
+			name: "rejected by its quota in PreFilter",
+			statuses: framework.NodeToStatusMap{
+				"node1": framework.NewStatus(framework.Unschedulable, "Pod is rejected in PreFilter because ElasticQuota ns is more than Max").WithPlugin("CapacityScheduling"),
+				"node2": framework.NewStatus(framework.Unschedulable, "Pod is rejected in PreFilter because ElasticQuota ns is more than Max").WithPlugin("CapacityScheduling"),
+			},
+			wantType:    v1alpha1.GangBlockerQuota,
+			wantMessage: "0/2 nodes are available: 2 Pod is rejected in PreFilter because ElasticQuota ns is more than Max.",
+		},
+		{
+			name: "rejected by the affinity on most nodes",
+			statuses: framework.NodeToStatusMap{
+				"node1": framework.NewStatus(framework.UnschedulableAndUnresolvable, "node(s) didn't match Pod's node affinity/selector").WithPlugin(names.NodeAffinity),
+				"node2": framework.NewStatus(framework.Unschedulable, "node(s) didn't match pod anti-affinity rules").WithPlugin(names.InterPodAffinity),
+				"node3": framework.NewStatus(framework.Unschedulable, "Insufficient cpu", "Insufficient memory").WithPlugin(names.NodeResourcesFit),
+			},
+			wantType: v1alpha1.GangBlockerAffinity,
+			wantMessage: "0/3 nodes are available: 1 Insufficient cpu, 1 Insufficient memory, " +
+				"1 node(s) didn't match Pod's node affinity/selector, 1 node(s) didn't match pod anti-affinity rules.",
+		},
+		{
+			name: "rejected by another plugin",
+			statuses: framework.NodeToStatusMap{
+				"node1": framework.NewStatus(framework.Unschedulable, "node(s) had untolerated taint").WithPlugin(names.TaintToleration),
+			},
+			wantType:    v1alpha1.GangBlockerOther,
+			wantMessage: "0/1 nodes are available: 1 node(s) had untolerated taint.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotMessage := rejectionOf(tt.statuses)
+			if gotType != tt.wantType || gotMessage != tt.wantMessage {
+				t.Errorf("expected %v rejection %q, got %v rejection %q", tt.wantType, tt.wantMessage, gotType, gotMessage)
+			}
+		})
+	}
+}
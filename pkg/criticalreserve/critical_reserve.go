@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package criticalreserve
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// CriticalReserve is a plugin keeping a reserve of capacity on each node for pods of
+// critical priority, so that critical DaemonSet updates never need to preempt other pods.
+type CriticalReserve struct {
+	handle              framework.Handle
+	reserved            *framework.Resource
+	minCriticalPriority int32
+}
+
+var _ framework.PreFilterPlugin = &CriticalReserve{}
+var _ framework.FilterPlugin = &CriticalReserve{}
+var _ framework.EnqueueExtensions = &CriticalReserve{}
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "CriticalReserve"
+
+	// preFilterStateKey is the key in CycleState to the pre-computed pod request.
+	preFilterStateKey = "PreFilter" + Name
+
+	// ErrReasonReserve is the reason for a pod not fitting outside of the critical reserve.
+	ErrReasonReserve = "node(s) didn't have enough capacity outside of the critical reserve"
+)
+
+type preFilterState struct {
+	podRequest *framework.Resource
+}
+
+// Clone the preFilter state.
+func (s *preFilterState) Clone() framework.StateData {
+	return s
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (cr *CriticalReserve) Name() string {
+	return Name
+}
+
+// New initializes a new plugin and returns it.
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.CriticalReserveArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type CriticalReserveArgs, got %T", obj)
+	}
+	for name, quantity := range args.ReservedResources {
+		if quantity.Sign() < 0 {
+			return nil, fmt.Errorf("reserved %v should not be negative, got %v", name, quantity.String())
+		}
+	}
+
+	klog.FromContext(ctx).V(4).Info("CriticalReserve start", "reservedResources", args.ReservedResources, "minCriticalPriority", args.MinCriticalPriority)
+	return &CriticalReserve{
+		handle:              handle,
+		reserved:            framework.NewResource(args.ReservedResources),
+		minCriticalPriority: args.MinCriticalPriority,
+	}, nil
+}
+
+// EventsToRegister returns the possible events that may make a pod
+// failed by this plugin schedulable.
+func (cr *CriticalReserve) EventsToRegister(_ context.Context) ([]framework.ClusterEventWithHint, error) {
+	return []framework.ClusterEventWithHint{
+		{Event: framework.ClusterEvent{Resource: framework.Pod, ActionType: framework.Delete}},
+		{Event: framework.ClusterEvent{Resource: framework.Node, ActionType: framework.Add | framework.UpdateNodeAllocatable}},
+	}, nil
+}
+
+// PreFilter skips the pods allowed to consume the reserve, and computes the request of the others.
+func (cr *CriticalReserve) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	if cr.isCritical(pod) {
+		return nil, framework.NewStatus(framework.Skip)
+	}
+	state.Write(preFilterStateKey, &preFilterState{
+		podRequest: framework.NewResource(resource.PodRequests(pod, resource.PodResourcesOptions{})),
+	})
+	return nil, framework.NewStatus(framework.Success)
+}
+
+// PreFilterExtensions returns nil as the pod request does not depend on other pods.
+func (cr *CriticalReserve) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Filter rejects the nodes on which the pod would consume the critical reserve.
+func (cr *CriticalReserve) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+
+	if insufficient := cr.insufficientResources(s.podRequest, nodeInfo); len(insufficient) > 0 {
+		klog.FromContext(ctx).V(5).Info("Pod would consume the critical reserve", "pod", klog.KObj(pod), "node", klog.KObj(nodeInfo.Node()), "resources", insufficient)
+		return framework.NewStatus(framework.Unschedulable, ErrReasonReserve)
+	}
+	return nil
+}
+
+func (cr *CriticalReserve) isCritical(pod *v1.Pod) bool {
+	return corev1helpers.PodPriority(pod) >= cr.minCriticalPriority
+}
+
+// insufficientResources returns the reserved resources that would not be left free on the node if the pod was bound to it.
+// Only the resources requested by the pod are checked, every pod taking one of the pods of the node, so that a pod
+// doesn't get rejected because of a reserve already consumed by others, or of a resource the node doesn't have.
+func (cr *CriticalReserve) insufficientResources(podRequest *framework.Resource, nodeInfo *framework.NodeInfo) []v1.ResourceName {
+	var insufficient []v1.ResourceName
+	free := func(allocatable, requested, request int64) int64 {
+		return allocatable - requested - request
+	}
+	if cr.reserved.MilliCPU > 0 && podRequest.MilliCPU > 0 &&
+		free(nodeInfo.Allocatable.MilliCPU, nodeInfo.Requested.MilliCPU, podRequest.MilliCPU) < cr.reserved.MilliCPU {
+		insufficient = append(insufficient, v1.ResourceCPU)
+	}
+	if cr.reserved.Memory > 0 && podRequest.Memory > 0 &&
+		free(nodeInfo.Allocatable.Memory, nodeInfo.Requested.Memory, podRequest.Memory) < cr.reserved.Memory {
+		insufficient = append(insufficient, v1.ResourceMemory)
+	}
+	if cr.reserved.EphemeralStorage > 0 && podRequest.EphemeralStorage > 0 &&
+		free(nodeInfo.Allocatable.EphemeralStorage, nodeInfo.Requested.EphemeralStorage, podRequest.EphemeralStorage) < cr.reserved.EphemeralStorage {
+		insufficient = append(insufficient, v1.ResourceEphemeralStorage)
+	}
+	if cr.reserved.AllowedPodNumber > 0 && nodeInfo.Allocatable.AllowedPodNumber-len(nodeInfo.Pods)-1 < cr.reserved.AllowedPodNumber {
+		insufficient = append(insufficient, v1.ResourcePods)
+	}
+	for name, quantity := range cr.reserved.ScalarResources {
+		allocatable, ok := nodeInfo.Allocatable.ScalarResources[name]
+		if quantity <= 0 || !ok || podRequest.ScalarResources[name] == 0 {
+			continue
+		}
+		if free(allocatable, nodeInfo.Requested.ScalarResources[name], podRequest.ScalarResources[name]) < quantity {
+			insufficient = append(insufficient, name)
+		}
+	}
+	return insufficient
+}
+
+func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, error) {
+	c, err := cycleState.Read(preFilterStateKey)
+	if err != nil {
+		// preFilterState doesn't exist, likely PreFilter wasn't invoked.
+		return nil, fmt.Errorf("error reading %q from cycleState: %w", preFilterStateKey, err)
+	}
+
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to CriticalReserve.preFilterState error", c)
+	}
+	return s, nil
+}
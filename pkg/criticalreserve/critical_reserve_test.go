@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package criticalreserve
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    *config.CriticalReserveArgs
+		wantErr bool
+	}{
+		{
+			name: "valid args",
+			args: &config.CriticalReserveArgs{
+				ReservedResources:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m")},
+				MinCriticalPriority: 2000000000,
+			},
+		},
+		{
+			name: "negative reserve",
+			args: &config.CriticalReserveArgs{
+				ReservedResources: v1.ResourceList{v1.ResourceMemory: resource.MustParse("-1Gi")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(context.TODO(), tt.args, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if _, err := New(context.TODO(), &config.CoschedulingArgs{}, nil); err == nil {
+		t.Errorf("expected an error for args of another plugin")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	node := st.MakeNode().Name("node").Capacity(map[v1.ResourceName]string{
+		v1.ResourceCPU:    "4",
+		v1.ResourceMemory: "8Gi",
+		v1.ResourcePods:   "10",
+	}).Obj()
+	node.Status.Allocatable = node.Status.Capacity
+	criticalPriority := int32(2000000000)
+
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		existingPods []*v1.Pod
+		wantSkip     bool
+		wantStatus   *framework.Status
+	}{
+		{
+			name: "pod leaves the reserve free",
+			pod:  st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2", v1.ResourceMemory: "2Gi"}).Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("e1").Node("node").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1", v1.ResourceMemory: "1Gi"}).Obj(),
+			},
+		},
+		{
+			name: "pod would consume the cpu reserve",
+			pod:  st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2600m"}).Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("e1").Node("node").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj(),
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReserve),
+		},
+		{
+			name:       "pod would consume the memory reserve",
+			pod:        st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceMemory: "7500Mi"}).Obj(),
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReserve),
+		},
+		{
+			name: "pod doesn't request the consumed cpu reserve",
+			pod:  st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceMemory: "1Gi"}).Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("e1").Node("node").Req(map[v1.ResourceName]string{v1.ResourceCPU: "3800m"}).Obj(),
+			},
+		},
+		{
+			name:     "critical pod may consume the reserve",
+			pod:      st.MakePod().Name("p").Priority(criticalPriority).Req(map[v1.ResourceName]string{v1.ResourceCPU: "3"}).Obj(),
+			wantSkip: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(context.TODO(), &config.CriticalReserveArgs{
+				ReservedResources: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("500m"),
+					v1.ResourceMemory: resource.MustParse("1Gi"),
+					// The node has no GPU: its reserve doesn't apply.
+					"nvidia.com/gpu": resource.MustParse("1"),
+				},
+				MinCriticalPriority: criticalPriority,
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cr := p.(*CriticalReserve)
+
+			nodeInfo := framework.NewNodeInfo(tt.existingPods...)
+			nodeInfo.SetNode(node)
+
+			state := framework.NewCycleState()
+			_, status := cr.PreFilter(context.TODO(), state, tt.pod)
+			if tt.wantSkip {
+				if !status.IsSkip() {
+					t.Errorf("expected PreFilter to skip the pod, got %v", status)
+				}
+				return
+			}
+			if !status.IsSuccess() {
+				t.Fatalf("unexpected PreFilter status: %v", status)
+			}
+
+			status = cr.Filter(context.TODO(), state, tt.pod, nodeInfo)
+			if tt.wantStatus.Code() != status.Code() || tt.wantStatus.Message() != status.Message() {
+				t.Errorf("expected status %v, got %v", tt.wantStatus, status)
+			}
+		})
+	}
+}
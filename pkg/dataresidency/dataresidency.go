@@ -0,0 +1,244 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dataresidency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling"
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "DataResidency"
+
+	preFilterStateKey = "PreFilter" + Name
+
+	// ResidencyViolationPrevented is the reason of the audit event recorded on a pod
+	// that can't be scheduled on some nodes because of data residency policies.
+	ResidencyViolationPrevented = "DataResidencyViolationPrevented"
+	// ResidencyEnforced is the reason of the audit event recorded on a pod
+	// bound to a node of a region allowed by its data residency policies.
+	ResidencyEnforced = "DataResidencyEnforced"
+)
+
+// DataResidency is a plugin enforcing the DataResidencyPolicies, i.e. filtering out the nodes
+// outside the regions allowed for the pods, and recording audit events of the decisions.
+type DataResidency struct {
+	handle framework.Handle
+	client client.Client
+}
+
+var _ framework.PreFilterPlugin = &DataResidency{}
+var _ framework.FilterPlugin = &DataResidency{}
+var _ framework.PostFilterPlugin = &DataResidency{}
+var _ framework.PreBindPlugin = &DataResidency{}
+var _ framework.EnqueueExtensions = &DataResidency{}
+
+// preFilterState computed at PreFilter and used at Filter, PostFilter and PreBind.
+type preFilterState struct {
+	// policies are the names of the policies selecting the pod.
+	policies []string
+	// allowedRegions are the regions allowed by all the policies selecting the pod.
+	allowedRegions sets.Set[string]
+}
+
+// Clone the preFilter state. The state is not modified after PreFilter.
+func (s *preFilterState) Clone() framework.StateData {
+	return s
+}
+
+// New initializes a new plugin and returns it.
+func New(ctx context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	lh := klog.FromContext(ctx)
+	lh.V(5).Info("creating new data residency plugin")
+
+	scheme := runtime.NewScheme()
+	_ = clientscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	client, err := client.New(handle.KubeConfig(), client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataResidency{
+		handle: handle,
+		client: client,
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (dr *DataResidency) Name() string {
+	return Name
+}
+
+// EventsToRegister returns the possible events that may make a Pod
+// failed by this plugin schedulable.
+func (dr *DataResidency) EventsToRegister(_ context.Context) ([]framework.ClusterEventWithHint, error) {
+	policyGVK := fmt.Sprintf("dataresidencypolicies.v1alpha1.%v", scheduling.GroupName)
+	return []framework.ClusterEventWithHint{
+		{Event: framework.ClusterEvent{Resource: framework.Node, ActionType: framework.Add | framework.UpdateNodeLabel}},
+		{Event: framework.ClusterEvent{Resource: framework.GVK(policyGVK), ActionType: framework.All}},
+	}, nil
+}
+
+// PreFilter looks up the policies selecting the pod and computes the regions allowed by all of them.
+// The pod is rejected if no region is allowed by all of them; the Filter is skipped if no policy selects it.
+func (dr *DataResidency) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	s, err := dr.computePreFilterState(ctx, pod)
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	state.Write(preFilterStateKey, s)
+	if len(s.policies) == 0 {
+		return nil, framework.NewStatus(framework.Skip)
+	}
+	if s.allowedRegions.Len() == 0 {
+		return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable,
+			fmt.Sprintf("data residency policies %v allow no common region", s.policies))
+	}
+	return nil, nil
+}
+
+// PreFilterExtensions returns a PreFilterExtensions interface if the plugin implements one.
+func (dr *DataResidency) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Filter rejects the nodes whose region is not allowed by the policies selecting the pod.
+func (dr *DataResidency) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	node := nodeInfo.Node()
+	region, ok := node.Labels[v1.LabelTopologyRegion]
+	if !ok {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+			fmt.Sprintf("node has no %v label, required by data residency policies %v", v1.LabelTopologyRegion, s.policies))
+	}
+	if !s.allowedRegions.Has(region) {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+			fmt.Sprintf("node region %q is not allowed by data residency policies %v", region, s.policies))
+	}
+	return nil
+}
+
+// PostFilter records an audit event on the pod when nodes were rejected because of its policies.
+// It never makes the pod schedulable, so that the next PostFilter plugins, e.g. preemption, run.
+func (dr *DataResidency) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod,
+	filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	s, err := getPreFilterState(state)
+	if err != nil || len(s.policies) == 0 {
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+	rejected := 0
+	for _, status := range filteredNodeStatusMap {
+		if status.Plugin() == Name {
+			rejected++
+		}
+	}
+	if rejected == 0 {
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+
+	klog.FromContext(ctx).V(4).Info("Nodes rejected by data residency policies", "pod", klog.KObj(pod),
+		"policies", s.policies, "allowedRegions", sets.List(s.allowedRegions), "rejectedNodes", rejected)
+	if recorder := dr.handle.EventRecorder(); recorder != nil {
+		recorder.Eventf(pod, nil, v1.EventTypeWarning, ResidencyViolationPrevented, "Scheduling",
+			"%d nodes outside the regions %v allowed by data residency policies %v were filtered out",
+			rejected, sets.List(s.allowedRegions), s.policies)
+	}
+	return nil, framework.NewStatus(framework.Unschedulable)
+}
+
+// PreBind records an audit event on the pod with the region of its node and the policies enforced.
+func (dr *DataResidency) PreBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	s, err := getPreFilterState(state)
+	if err != nil || len(s.policies) == 0 {
+		return nil
+	}
+	nodeInfo, err := dr.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+	}
+	region := nodeInfo.Node().Labels[v1.LabelTopologyRegion]
+
+	klog.FromContext(ctx).V(4).Info("Data residency policies enforced", "pod", klog.KObj(pod),
+		"node", nodeName, "region", region, "policies", s.policies)
+	if recorder := dr.handle.EventRecorder(); recorder != nil {
+		recorder.Eventf(pod, nil, v1.EventTypeNormal, ResidencyEnforced, "Binding",
+			"Node %v in region %q is allowed by data residency policies %v", nodeName, region, s.policies)
+	}
+	return nil
+}
+
+// computePreFilterState lists the policies selecting the pod and intersects their allowed regions.
+// The policies with an invalid selector are ignored.
+func (dr *DataResidency) computePreFilterState(ctx context.Context, pod *v1.Pod) (*preFilterState, error) {
+	var policyList v1alpha1.DataResidencyPolicyList
+	if err := dr.client.List(ctx, &policyList); err != nil {
+		return nil, fmt.Errorf("listing data residency policies: %w", err)
+	}
+
+	lh := klog.FromContext(ctx)
+	s := &preFilterState{}
+	for i := range policyList.Items {
+		policy := &policyList.Items[i]
+		selector, err := metav1.LabelSelectorAsSelector(policy.Spec.Selector)
+		if err != nil {
+			lh.Error(err, "Invalid selector of data residency policy", "policy", policy.Name)
+			continue
+		}
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		s.policies = append(s.policies, policy.Name)
+		if s.allowedRegions == nil {
+			s.allowedRegions = sets.New[string](policy.Spec.AllowedRegions...)
+		} else {
+			s.allowedRegions = s.allowedRegions.Intersection(sets.New[string](policy.Spec.AllowedRegions...))
+		}
+	}
+	sort.Strings(s.policies)
+	return s, nil
+}
+
+func getPreFilterState(state *framework.CycleState) (*preFilterState, error) {
+	c, err := state.Read(preFilterStateKey)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q from cycleState: %w", preFilterStateKey, err)
+	}
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to dataresidency.preFilterState error", c)
+	}
+	return s, nil
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dataresidency
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	tu "github.com/amiraBenamer20/scheduler-plugins/test/util"
+)
+
+func makePolicy(name string, matchLabels map[string]string, regions ...string) *v1alpha1.DataResidencyPolicy {
+	return &v1alpha1.DataResidencyPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.DataResidencyPolicySpec{
+			Selector:       &metav1.LabelSelector{MatchLabels: matchLabels},
+			AllowedRegions: regions,
+		},
+	}
+}
+
+func makeNode(name, region string) *v1.Node {
+	node := st.MakeNode().Name(name).Obj()
+	if region != "" {
+		node.Labels = map[string]string{v1.LabelTopologyRegion: region}
+	}
+	return node
+}
+
+func TestDataResidencyFilter(t *testing.T) {
+	nodes := []*v1.Node{
+		makeNode("eu-node", "eu-west"),
+		makeNode("us-node", "us-east"),
+		makeNode("unlabeled-node", ""),
+	}
+	gdpr := makePolicy("gdpr", map[string]string{"data": "personal"}, "eu-west", "eu-central")
+	health := makePolicy("health", map[string]string{"team": "health"}, "eu-central", "us-east")
+	everywhere := makePolicy("everywhere", map[string]string{}, "eu-west", "us-east")
+
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		policies      []runtime.Object
+		wantPreFilter framework.Code
+		wantFilter    map[string]framework.Code
+	}{
+		{
+			name:          "pod not selected by any policy",
+			pod:           st.MakePod().Name("p").Namespace("ns").Label("data", "public").Obj(),
+			policies:      []runtime.Object{gdpr},
+			wantPreFilter: framework.Skip,
+		},
+		{
+			name:          "nodes outside the allowed regions are filtered out",
+			pod:           st.MakePod().Name("p").Namespace("ns").Label("data", "personal").Obj(),
+			policies:      []runtime.Object{gdpr, health},
+			wantPreFilter: framework.Success,
+			wantFilter: map[string]framework.Code{
+				"eu-node":        framework.Success,
+				"us-node":        framework.UnschedulableAndUnresolvable,
+				"unlabeled-node": framework.UnschedulableAndUnresolvable,
+			},
+		},
+		{
+			name:          "an empty selector selects all the pods",
+			pod:           st.MakePod().Name("p").Namespace("ns").Obj(),
+			policies:      []runtime.Object{everywhere},
+			wantPreFilter: framework.Success,
+			wantFilter: map[string]framework.Code{
+				"eu-node":        framework.Success,
+				"us-node":        framework.Success,
+				"unlabeled-node": framework.UnschedulableAndUnresolvable,
+			},
+		},
+		{
+			name:          "no region allowed by all the policies selecting the pod",
+			pod:           st.MakePod().Name("p").Namespace("ns").Label("data", "personal").Label("team", "health").Obj(),
+			policies:      []runtime.Object{gdpr, health, everywhere},
+			wantPreFilter: framework.UnschedulableAndUnresolvable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			scheme := runtime.NewScheme()
+			if err := v1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			cs := clientsetfake.NewSimpleClientset()
+			dr := &DataResidency{client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tt.policies...).Build()}
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			fwk, err := tf.NewFramework(
+				ctx,
+				registeredPlugins,
+				"default-scheduler",
+				frameworkruntime.WithClientSet(cs),
+				frameworkruntime.WithEventRecorder(&events.FakeRecorder{}),
+				frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(cs, 0)),
+				frameworkruntime.WithSnapshotSharedLister(tu.NewFakeSharedLister(nil, nodes)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dr.handle = fwk
+			state := framework.NewCycleState()
+			if _, status := dr.PreFilter(ctx, state, tt.pod); status.Code() != tt.wantPreFilter {
+				t.Fatalf("unexpected PreFilter status: %v, want code %v", status, tt.wantPreFilter)
+			}
+			for _, node := range nodes {
+				want, ok := tt.wantFilter[node.Name]
+				if !ok {
+					continue
+				}
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(node)
+				if status := dr.Filter(ctx, state, tt.pod, nodeInfo); status.Code() != want {
+					t.Errorf("unexpected Filter status on %v: %v, want code %v", node.Name, status, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDataResidencyAuditEvents(t *testing.T) {
+	nodes := []*v1.Node{makeNode("eu-node", "eu-west"), makeNode("us-node", "us-east")}
+	gdpr := makePolicy("gdpr", map[string]string{"data": "personal"}, "eu-west")
+
+	rejected := framework.NewStatus(framework.UnschedulableAndUnresolvable, "node region \"us-east\" is not allowed")
+	rejected.SetPlugin(Name)
+	otherRejected := framework.NewStatus(framework.Unschedulable, "Insufficient cpu")
+	otherRejected.SetPlugin("NodeResourcesFit")
+
+	tests := []struct {
+		name                  string
+		pod                   *v1.Pod
+		filteredNodesStatuses framework.NodeToStatusMap
+		wantPostFilterEvent   string
+		wantPreBindEvent      string
+	}{
+		{
+			name:                  "nodes rejected by the policies and pod bound in an allowed region",
+			pod:                   st.MakePod().Name("p").Namespace("ns").Label("data", "personal").Obj(),
+			filteredNodesStatuses: framework.NodeToStatusMap{"us-node": rejected, "eu-node": otherRejected},
+			wantPostFilterEvent:   "Warning " + ResidencyViolationPrevented + " 1 nodes outside the regions [eu-west]",
+			wantPreBindEvent:      "Normal " + ResidencyEnforced + " Node eu-node in region \"eu-west\" is allowed by data residency policies [gdpr]",
+		},
+		{
+			name:                  "nodes rejected by other plugins only",
+			pod:                   st.MakePod().Name("p").Namespace("ns").Label("data", "personal").Obj(),
+			filteredNodesStatuses: framework.NodeToStatusMap{"eu-node": otherRejected},
+			wantPreBindEvent:      "Normal " + ResidencyEnforced,
+		},
+		{
+			name:                  "pod not selected by any policy",
+			pod:                   st.MakePod().Name("p").Namespace("ns").Obj(),
+			filteredNodesStatuses: framework.NodeToStatusMap{"us-node": rejected},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			recorder := events.NewFakeRecorder(10)
+			scheme := runtime.NewScheme()
+			if err := v1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			cs := clientsetfake.NewSimpleClientset()
+			dr := &DataResidency{client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(gdpr).Build()}
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			fwk, err := tf.NewFramework(
+				ctx,
+				registeredPlugins,
+				"default-scheduler",
+				frameworkruntime.WithClientSet(cs),
+				frameworkruntime.WithEventRecorder(recorder),
+				frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(cs, 0)),
+				frameworkruntime.WithSnapshotSharedLister(tu.NewFakeSharedLister(nil, nodes)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dr.handle = fwk
+			state := framework.NewCycleState()
+			dr.PreFilter(ctx, state, tt.pod)
+
+			if _, status := dr.PostFilter(ctx, state, tt.pod, tt.filteredNodesStatuses); status.Code() != framework.Unschedulable {
+				t.Errorf("unexpected PostFilter status: %v, want code %v", status, framework.Unschedulable)
+			}
+			checkEvent(t, recorder, tt.wantPostFilterEvent)
+
+			if status := dr.PreBind(ctx, state, tt.pod, "eu-node"); !status.IsSuccess() {
+				t.Errorf("unexpected PreBind status: %v", status)
+			}
+			checkEvent(t, recorder, tt.wantPreBindEvent)
+		})
+	}
+}
+
+func checkEvent(t *testing.T, recorder *events.FakeRecorder, want string) {
+	t.Helper()
+	select {
+	case got := <-recorder.Events:
+		if want == "" {
+			t.Errorf("unexpected event %q", got)
+		} else if !strings.HasPrefix(got, want) {
+			t.Errorf("unexpected event %q, want prefix %q", got, want)
+		}
+	default:
+		if want != "" {
+			t.Errorf("expected event with prefix %q, got none", want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadlineaware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/clock"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// DeadlineAware is a plugin favoring the pods approaching their deadline, e.g. the pods of the Jobs
+// with an activeDeadlineSeconds: the urgent pods are scheduled first, on the nodes with the most headroom.
+type DeadlineAware struct {
+	handle           framework.Handle
+	urgencyThreshold time.Duration
+	clock            clock.Clock
+}
+
+var _ framework.QueueSortPlugin = &DeadlineAware{}
+var _ framework.ScorePlugin = &DeadlineAware{}
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "DeadlineAware"
+)
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (d *DeadlineAware) Name() string {
+	return Name
+}
+
+// New initializes a new plugin and returns it.
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.DeadlineAwareArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type DeadlineAwareArgs, got %T", obj)
+	}
+	if args.UrgencyThresholdSeconds <= 0 {
+		return nil, fmt.Errorf("urgencyThresholdSeconds should be positive, got %d", args.UrgencyThresholdSeconds)
+	}
+
+	klog.FromContext(ctx).V(4).Info("DeadlineAware start", "urgencyThresholdSeconds", args.UrgencyThresholdSeconds)
+	return &DeadlineAware{
+		handle:           handle,
+		urgencyThreshold: time.Duration(args.UrgencyThresholdSeconds) * time.Second,
+		clock:            clock.RealClock{},
+	}, nil
+}
+
+// Less orders the pods by priority, then the urgent pods first, the closest to their deadline first,
+// then by their timestamp. The urgency is evaluated when the pods are compared, so that the pods
+// move up the queue as they approach their deadline.
+func (d *DeadlineAware) Less(podInfo1, podInfo2 *framework.QueuedPodInfo) bool {
+	prio1 := corev1helpers.PodPriority(podInfo1.Pod)
+	prio2 := corev1helpers.PodPriority(podInfo2.Pod)
+	if prio1 != prio2 {
+		return prio1 > prio2
+	}
+	now := d.clock.Now()
+	deadline1, urgent1 := d.urgentDeadline(podInfo1.Pod, now)
+	deadline2, urgent2 := d.urgentDeadline(podInfo2.Pod, now)
+	if urgent1 != urgent2 {
+		return urgent1
+	}
+	if urgent1 && !deadline1.Equal(deadline2) {
+		return deadline1.Before(deadline2)
+	}
+	return podInfo1.Timestamp.Before(podInfo2.Timestamp)
+}
+
+// Score favors the nodes with the most headroom left after placing an urgent pod, the more the closer
+// the pod is to its deadline. All the nodes score equally for the other pods.
+func (d *DeadlineAware) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	urgency := d.urgency(pod, d.clock.Now())
+	if urgency == 0 {
+		return 0, nil
+	}
+	nodeInfo, err := d.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+	}
+	headroom := headroom(pod, nodeInfo)
+	klog.FromContext(ctx).V(6).Info("Headroom of the node for the urgent pod", "pod", klog.KObj(pod), "node", nodeName, "urgency", urgency, "headroom", headroom)
+	return int64(math.Round(urgency * headroom * float64(framework.MaxNodeScore))), nil
+}
+
+// ScoreExtensions returns nil as the scores are already within the node score range.
+func (d *DeadlineAware) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// urgentDeadline returns the deadline of the pod, and whether the pod is urgent, i.e. its deadline is
+// within the urgency threshold.
+func (d *DeadlineAware) urgentDeadline(pod *v1.Pod, now time.Time) (time.Time, bool) {
+	deadline, ok := podDeadline(pod)
+	if !ok {
+		return time.Time{}, false
+	}
+	return deadline, deadline.Sub(now) < d.urgencyThreshold
+}
+
+// urgency returns 0 for the pods which are not urgent, growing to 1 as the pods reach their deadline.
+func (d *DeadlineAware) urgency(pod *v1.Pod, now time.Time) float64 {
+	deadline, urgent := d.urgentDeadline(pod, now)
+	if !urgent {
+		return 0
+	}
+	remaining := deadline.Sub(now)
+	if remaining <= 0 {
+		return 1
+	}
+	return 1 - float64(remaining)/float64(d.urgencyThreshold)
+}
+
+// podDeadline returns the deadline of the pod, from its activeDeadlineSeconds counted from its creation.
+// The Job controller doesn't propagate the activeDeadlineSeconds of a Job to its pods: the pods of a Job
+// get a deadline when their template sets it.
+func podDeadline(pod *v1.Pod) (time.Time, bool) {
+	if pod.Spec.ActiveDeadlineSeconds == nil {
+		return time.Time{}, false
+	}
+	return pod.CreationTimestamp.Add(time.Duration(*pod.Spec.ActiveDeadlineSeconds) * time.Second), true
+}
+
+// headroom returns the lowest share of the node allocatable cpu and memory left after placing the pod.
+func headroom(pod *v1.Pod, nodeInfo *framework.NodeInfo) float64 {
+	request := framework.NewResource(resource.PodRequests(pod, resource.PodResourcesOptions{}))
+	share := 1.0
+	left := func(requested, allocatable int64) {
+		if allocatable > 0 {
+			share = min(share, float64(allocatable-requested)/float64(allocatable))
+		}
+	}
+	left(nodeInfo.Requested.MilliCPU+request.MilliCPU, nodeInfo.Allocatable.MilliCPU)
+	left(nodeInfo.Requested.Memory+request.Memory, nodeInfo.Allocatable.Memory)
+	return max(share, 0)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadlineaware
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+)
+
+func newTestDeadlineAware(now time.Time) *DeadlineAware {
+	return &DeadlineAware{
+		urgencyThreshold: 10 * time.Minute,
+		clock:            testingclock.NewFakeClock(now),
+	}
+}
+
+// makeDeadlinePod returns a pod created at created, with a deadline after activeDeadline if positive.
+func makeDeadlinePod(name string, priority int32, created time.Time, activeDeadline time.Duration) *v1.Pod {
+	pod := st.MakePod().Namespace("default").Name(name).Priority(priority).Obj()
+	pod.CreationTimestamp = metav1.NewTime(created)
+	if activeDeadline > 0 {
+		pod.Spec.ActiveDeadlineSeconds = ptr.To(int64(activeDeadline.Seconds()))
+	}
+	return pod
+}
+
+func TestLess(t *testing.T) {
+	now := time.Now()
+	d := newTestDeadlineAware(now)
+	queued := func(pod *v1.Pod, timestamp time.Time) *framework.QueuedPodInfo {
+		return &framework.QueuedPodInfo{PodInfo: &framework.PodInfo{Pod: pod}, Timestamp: timestamp}
+	}
+
+	tests := []struct {
+		name     string
+		p1       *framework.QueuedPodInfo
+		p2       *framework.QueuedPodInfo
+		expected bool
+	}{
+		{
+			name:     "higher priority first",
+			p1:       queued(makeDeadlinePod("p1", 10, now, 0), now.Add(time.Second)),
+			p2:       queued(makeDeadlinePod("p2", 0, now, time.Minute), now),
+			expected: true,
+		},
+		{
+			name:     "urgent pod first",
+			p1:       queued(makeDeadlinePod("p1", 0, now, 5*time.Minute), now.Add(time.Second)),
+			p2:       queued(makeDeadlinePod("p2", 0, now, 0), now),
+			expected: true,
+		},
+		{
+			name:     "pod with a distant deadline is not urgent",
+			p1:       queued(makeDeadlinePod("p1", 0, now, time.Hour), now.Add(time.Second)),
+			p2:       queued(makeDeadlinePod("p2", 0, now, 0), now),
+			expected: false,
+		},
+		{
+			name:     "closest deadline first",
+			p1:       queued(makeDeadlinePod("p1", 0, now.Add(-5*time.Minute), 6*time.Minute), now.Add(time.Second)),
+			p2:       queued(makeDeadlinePod("p2", 0, now, 5*time.Minute), now),
+			expected: true,
+		},
+		{
+			name:     "earlier first without urgency",
+			p1:       queued(makeDeadlinePod("p1", 0, now, 0), now),
+			p2:       queued(makeDeadlinePod("p2", 0, now, 0), now.Add(time.Second)),
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.Less(tt.p1, tt.p2); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUrgency(t *testing.T) {
+	now := time.Now()
+	d := newTestDeadlineAware(now)
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected float64
+	}{
+		{
+			name:     "no deadline",
+			pod:      makeDeadlinePod("p", 0, now, 0),
+			expected: 0,
+		},
+		{
+			name:     "deadline beyond the threshold",
+			pod:      makeDeadlinePod("p", 0, now, time.Hour),
+			expected: 0,
+		},
+		{
+			name:     "deadline within the threshold",
+			pod:      makeDeadlinePod("p", 0, now, 4*time.Minute),
+			expected: 0.6,
+		},
+		{
+			name:     "deadline passed",
+			pod:      makeDeadlinePod("p", 0, now.Add(-time.Hour), time.Minute),
+			expected: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.urgency(tt.pod, now); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	now := time.Now()
+	d := newTestDeadlineAware(now)
+
+	// the pods without urgency score all the nodes equally, without reading the snapshot
+	score, status := d.Score(context.TODO(), nil, makeDeadlinePod("p", 0, now, 0), "node1")
+	if !status.IsSuccess() || score != 0 {
+		t.Errorf("expected score 0, got %v with status %v", score, status)
+	}
+}
+
+func TestHeadroom(t *testing.T) {
+	node := st.MakeNode().Name("node1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8", v1.ResourceMemory: "16Gi"}).Obj()
+	nodeInfo := framework.NewNodeInfo(
+		st.MakePod().Namespace("default").Name("running").Node("node1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2", v1.ResourceMemory: "4Gi"}).Obj(),
+	)
+	nodeInfo.SetNode(node)
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected float64
+	}{
+		{
+			name:     "cpu is the lowest headroom",
+			pod:      st.MakePod().Namespace("default").Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+			expected: 0.25,
+		},
+		{
+			name:     "memory is the lowest headroom",
+			pod:      st.MakePod().Namespace("default").Name("p").Req(map[v1.ResourceName]string{v1.ResourceMemory: "8Gi"}).Obj(),
+			expected: 0.25,
+		},
+		{
+			name:     "no headroom left",
+			pod:      st.MakePod().Namespace("default").Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "10"}).Obj(),
+			expected: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := headroom(tt.pod, nodeInfo); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
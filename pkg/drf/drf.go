@@ -0,0 +1,338 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drf
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// DominantResourceFairness is a plugin implementing dominant resource fairness (DRF) across tenants:
+// the pods of the tenants with the lowest dominant share of the cluster are scheduled first,
+// and the nodes on which the tenant of a pod holds the lowest dominant share are preferred.
+type DominantResourceFairness struct {
+	sync.RWMutex
+	handle      framework.Handle
+	nodeLister  corelisters.NodeLister
+	tenantLabel string
+	// tenants holds the usage of the tenants, by tenant name
+	tenants map[string]*tenantUsage
+	// capacity is the allocatable capacity of the cluster
+	capacity *framework.Resource
+}
+
+// tenantUsage is the sum of the requests of the pods of a tenant.
+type tenantUsage struct {
+	pods sets.Set[string]
+	used *framework.Resource
+}
+
+var _ framework.QueueSortPlugin = &DominantResourceFairness{}
+var _ framework.ScorePlugin = &DominantResourceFairness{}
+var _ framework.ReservePlugin = &DominantResourceFairness{}
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "DominantResourceFairness"
+)
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (d *DominantResourceFairness) Name() string {
+	return Name
+}
+
+// New initializes a new plugin and returns it.
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.DominantResourceFairnessArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type DominantResourceFairnessArgs, got %T", obj)
+	}
+
+	d := newDominantResourceFairness(handle, args.TenantLabel)
+	d.nodeLister = handle.SharedInformerFactory().Core().V1().Nodes().Lister()
+
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+	podInformer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			switch t := obj.(type) {
+			case *v1.Pod:
+				return assignedPod(t)
+			case cache.DeletedFinalStateUnknown:
+				if pod, ok := t.Obj.(*v1.Pod); ok {
+					return assignedPod(pod)
+				}
+				return false
+			default:
+				return false
+			}
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    d.addPod,
+			UpdateFunc: d.updatePod,
+			DeleteFunc: d.deletePod,
+		},
+	})
+
+	nodeInformer := handle.SharedInformerFactory().Core().V1().Nodes().Informer()
+	nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { d.updateCapacity() },
+		UpdateFunc: func(interface{}, interface{}) { d.updateCapacity() },
+		DeleteFunc: func(interface{}) { d.updateCapacity() },
+	})
+
+	klog.FromContext(ctx).V(4).Info("DominantResourceFairness start", "tenantLabel", args.TenantLabel)
+	return d, nil
+}
+
+func newDominantResourceFairness(handle framework.Handle, tenantLabel string) *DominantResourceFairness {
+	return &DominantResourceFairness{
+		handle:      handle,
+		tenantLabel: tenantLabel,
+		tenants:     make(map[string]*tenantUsage),
+		capacity:    &framework.Resource{},
+	}
+}
+
+// Less orders the pods by priority, then by the dominant share of their tenant, lowest first,
+// then by their timestamp. The dominant shares are evaluated when the pods are compared,
+// so the order of the queue follows the usage of the tenants as pods are scheduled and re-queued.
+func (d *DominantResourceFairness) Less(podInfo1, podInfo2 *framework.QueuedPodInfo) bool {
+	prio1 := corev1helpers.PodPriority(podInfo1.Pod)
+	prio2 := corev1helpers.PodPriority(podInfo2.Pod)
+	if prio1 != prio2 {
+		return prio1 > prio2
+	}
+	share1 := d.DominantShare(d.tenantOf(podInfo1.Pod))
+	share2 := d.DominantShare(d.tenantOf(podInfo2.Pod))
+	if share1 != share2 {
+		return share1 < share2
+	}
+	return podInfo1.Timestamp.Before(podInfo2.Timestamp)
+}
+
+// Score prefers the nodes on which the tenant of the pod would hold the lowest dominant share,
+// spreading the tenants over the nodes.
+func (d *DominantResourceFairness) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	nodeInfo, err := d.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+	}
+	share := d.nodeDominantShare(pod, nodeInfo)
+	klog.FromContext(ctx).V(6).Info("Dominant share of the tenant on the node", "pod", klog.KObj(pod), "node", nodeName, "share", share)
+	return int64(math.Round((1 - share) * float64(framework.MaxNodeScore))), nil
+}
+
+// ScoreExtensions returns nil as the scores are already within the node score range.
+func (d *DominantResourceFairness) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// Reserve accounts the pod in the usage of its tenant, before its binding is observed.
+func (d *DominantResourceFairness) Reserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if err := d.addPodIfNotPresent(pod); err != nil {
+		return framework.AsStatus(err)
+	}
+	return nil
+}
+
+// Unreserve removes the pod from the usage of its tenant.
+func (d *DominantResourceFairness) Unreserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	if err := d.deletePodIfPresent(pod); err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to delete Pod from the usage of its tenant", "pod", klog.KObj(pod))
+	}
+}
+
+// DominantShare returns the dominant share of the tenant, i.e. the highest share of the
+// cluster capacity the tenant requests among all the resources.
+func (d *DominantResourceFairness) DominantShare(tenant string) float64 {
+	d.RLock()
+	defer d.RUnlock()
+	usage := d.tenants[tenant]
+	if usage == nil {
+		return 0
+	}
+	return dominantShare(usage.used, d.capacity)
+}
+
+// nodeDominantShare returns the dominant share of the node the tenant of the pod would hold with the pod.
+func (d *DominantResourceFairness) nodeDominantShare(pod *v1.Pod, nodeInfo *framework.NodeInfo) float64 {
+	tenant := d.tenantOf(pod)
+	used := podRequest(pod)
+	for _, podInfo := range nodeInfo.Pods {
+		if d.tenantOf(podInfo.Pod) == tenant {
+			addResource(used, podRequest(podInfo.Pod), 1)
+		}
+	}
+	return dominantShare(used, nodeInfo.Allocatable)
+}
+
+// tenantOf returns the tenant of the pod: the value of the tenant label, or its namespace.
+func (d *DominantResourceFairness) tenantOf(pod *v1.Pod) string {
+	if d.tenantLabel != "" {
+		if tenant, ok := pod.Labels[d.tenantLabel]; ok {
+			return tenant
+		}
+	}
+	return pod.Namespace
+}
+
+func (d *DominantResourceFairness) addPodIfNotPresent(pod *v1.Pod) error {
+	key, err := framework.GetPodKey(pod)
+	if err != nil {
+		return err
+	}
+	tenant := d.tenantOf(pod)
+
+	d.Lock()
+	defer d.Unlock()
+	usage := d.tenants[tenant]
+	if usage == nil {
+		usage = &tenantUsage{pods: sets.New[string](), used: &framework.Resource{}}
+		d.tenants[tenant] = usage
+	}
+	if usage.pods.Has(key) {
+		return nil
+	}
+	usage.pods.Insert(key)
+	addResource(usage.used, podRequest(pod), 1)
+	return nil
+}
+
+func (d *DominantResourceFairness) deletePodIfPresent(pod *v1.Pod) error {
+	key, err := framework.GetPodKey(pod)
+	if err != nil {
+		return err
+	}
+	tenant := d.tenantOf(pod)
+
+	d.Lock()
+	defer d.Unlock()
+	usage := d.tenants[tenant]
+	if usage == nil || !usage.pods.Has(key) {
+		return nil
+	}
+	usage.pods.Delete(key)
+	addResource(usage.used, podRequest(pod), -1)
+	if usage.pods.Len() == 0 {
+		delete(d.tenants, tenant)
+	}
+	return nil
+}
+
+func (d *DominantResourceFairness) addPod(obj interface{}) {
+	pod := obj.(*v1.Pod)
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return
+	}
+	if err := d.addPodIfNotPresent(pod); err != nil {
+		klog.Background().Error(err, "Failed to add Pod to the usage of its tenant", "pod", klog.KObj(pod))
+	}
+}
+
+func (d *DominantResourceFairness) updatePod(oldObj, newObj interface{}) {
+	newPod := newObj.(*v1.Pod)
+	if newPod.Status.Phase == v1.PodSucceeded || newPod.Status.Phase == v1.PodFailed {
+		d.deletePod(newPod)
+	}
+}
+
+func (d *DominantResourceFairness) deletePod(obj interface{}) {
+	var pod *v1.Pod
+	switch t := obj.(type) {
+	case *v1.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		if pod, ok = t.Obj.(*v1.Pod); !ok {
+			return
+		}
+	default:
+		return
+	}
+	if err := d.deletePodIfPresent(pod); err != nil {
+		klog.Background().Error(err, "Failed to delete Pod from the usage of its tenant", "pod", klog.KObj(pod))
+	}
+}
+
+// updateCapacity sums the allocatable resources of the nodes in the cluster capacity.
+func (d *DominantResourceFairness) updateCapacity() {
+	nodes, err := d.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.Background().Error(err, "Failed to list nodes")
+		return
+	}
+	d.setCapacity(nodes)
+}
+
+func (d *DominantResourceFairness) setCapacity(nodes []*v1.Node) {
+	capacity := &framework.Resource{}
+	for _, node := range nodes {
+		capacity.Add(node.Status.Allocatable)
+	}
+	d.Lock()
+	defer d.Unlock()
+	d.capacity = capacity
+}
+
+// dominantShare returns the highest share of the capacity requested among all the resources.
+func dominantShare(used, capacity *framework.Resource) float64 {
+	var share float64
+	ratio := func(used, capacity int64) {
+		if capacity > 0 && used > 0 {
+			share = max(share, float64(used)/float64(capacity))
+		}
+	}
+	ratio(used.MilliCPU, capacity.MilliCPU)
+	ratio(used.Memory, capacity.Memory)
+	ratio(used.EphemeralStorage, capacity.EphemeralStorage)
+	for name, quantity := range used.ScalarResources {
+		ratio(quantity, capacity.ScalarResources[name])
+	}
+	return min(share, 1)
+}
+
+func podRequest(pod *v1.Pod) *framework.Resource {
+	return framework.NewResource(resource.PodRequests(pod, resource.PodResourcesOptions{}))
+}
+
+func addResource(dst, r *framework.Resource, sign int64) {
+	dst.MilliCPU += sign * r.MilliCPU
+	dst.Memory += sign * r.Memory
+	dst.EphemeralStorage += sign * r.EphemeralStorage
+	for name, value := range r.ScalarResources {
+		dst.SetScalar(name, dst.ScalarResources[name]+sign*value)
+	}
+}
+
+func assignedPod(pod *v1.Pod) bool {
+	return len(pod.Spec.NodeName) != 0
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drf
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func TestDominantShare(t *testing.T) {
+	d := newDominantResourceFairness(nil, "")
+	d.setCapacity([]*v1.Node{
+		st.MakeNode().Name("node1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "10", v1.ResourceMemory: "20Gi"}).Obj(),
+		st.MakeNode().Name("node2").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "10", v1.ResourceMemory: "20Gi", "nvidia.com/gpu": "4"}).Obj(),
+	})
+
+	pods := []*v1.Pod{
+		// tenant a is cpu heavy: 4/20 cpu, 2/40 memory
+		st.MakePod().Namespace("a").Name("a1").UID("a1").Node("node1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "4", v1.ResourceMemory: "2Gi"}).Obj(),
+		// tenant b is memory heavy: 1/20 cpu, 20/40 memory
+		st.MakePod().Namespace("b").Name("b1").UID("b1").Node("node1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1", v1.ResourceMemory: "20Gi"}).Obj(),
+		// tenant c is gpu heavy: 1/4 gpu
+		st.MakePod().Namespace("c").Name("c1").UID("c1").Node("node2").Req(map[v1.ResourceName]string{"nvidia.com/gpu": "1"}).Obj(),
+	}
+	for _, pod := range pods {
+		d.addPod(pod)
+	}
+	// pods are only accounted once
+	d.addPod(pods[0])
+
+	for tenant, expected := range map[string]float64{"a": 0.2, "b": 0.5, "c": 0.25, "d": 0} {
+		if got := d.DominantShare(tenant); math.Abs(got-expected) > 1e-9 {
+			t.Errorf("expected dominant share %v for tenant %v, got %v", expected, tenant, got)
+		}
+	}
+
+	succeeded := pods[1].DeepCopy()
+	succeeded.Status.Phase = v1.PodSucceeded
+	d.updatePod(pods[1], succeeded)
+	if got := d.DominantShare("b"); got != 0 {
+		t.Errorf("expected no dominant share for tenant b after its pod succeeded, got %v", got)
+	}
+}
+
+func TestTenantLabel(t *testing.T) {
+	d := newDominantResourceFairness(nil, "tenant")
+	d.setCapacity([]*v1.Node{st.MakeNode().Name("node1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "10"}).Obj()})
+
+	d.addPod(st.MakePod().Namespace("ns1").Name("p1").UID("p1").Label("tenant", "team").Node("node1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj())
+	d.addPod(st.MakePod().Namespace("ns2").Name("p2").UID("p2").Label("tenant", "team").Node("node1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "3"}).Obj())
+	d.addPod(st.MakePod().Namespace("ns2").Name("p3").UID("p3").Node("node1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj())
+
+	if got := d.DominantShare("team"); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("expected dominant share 0.5 for the labeled tenant, got %v", got)
+	}
+	if got := d.DominantShare("ns2"); math.Abs(got-0.1) > 1e-9 {
+		t.Errorf("expected dominant share 0.1 for the namespace of the unlabeled pod, got %v", got)
+	}
+}
+
+func TestLess(t *testing.T) {
+	d := newDominantResourceFairness(nil, "")
+	d.setCapacity([]*v1.Node{st.MakeNode().Name("node1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "10"}).Obj()})
+	d.addPod(st.MakePod().Namespace("heavy").Name("running").UID("running").Node("node1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "5"}).Obj())
+
+	now := time.Now()
+	queued := func(namespace string, priority int32, timestamp time.Time) *framework.QueuedPodInfo {
+		return &framework.QueuedPodInfo{
+			PodInfo:   &framework.PodInfo{Pod: st.MakePod().Namespace(namespace).Name("p").Priority(priority).Obj()},
+			Timestamp: timestamp,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		p1       *framework.QueuedPodInfo
+		p2       *framework.QueuedPodInfo
+		expected bool
+	}{
+		{
+			name:     "higher priority first",
+			p1:       queued("heavy", 10, now),
+			p2:       queued("light", 0, now),
+			expected: true,
+		},
+		{
+			name:     "lower dominant share first",
+			p1:       queued("light", 0, now.Add(time.Second)),
+			p2:       queued("heavy", 0, now),
+			expected: true,
+		},
+		{
+			name:     "higher dominant share last",
+			p1:       queued("heavy", 0, now),
+			p2:       queued("light", 0, now.Add(time.Second)),
+			expected: false,
+		},
+		{
+			name:     "earlier first with equal dominant shares",
+			p1:       queued("light", 0, now),
+			p2:       queued("other", 0, now.Add(time.Second)),
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.Less(tt.p1, tt.p2); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReserve(t *testing.T) {
+	d := newDominantResourceFairness(nil, "")
+	d.setCapacity([]*v1.Node{st.MakeNode().Name("node1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "10"}).Obj()})
+	pod := st.MakePod().Namespace("ns").Name("p").UID("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj()
+
+	if status := d.Reserve(context.TODO(), nil, pod, "node1"); !status.IsSuccess() {
+		t.Fatalf("unexpected Reserve status: %v", status)
+	}
+	// the binding observed later is not accounted twice
+	bound := pod.DeepCopy()
+	bound.Spec.NodeName = "node1"
+	d.addPod(bound)
+	if got := d.DominantShare("ns"); math.Abs(got-0.2) > 1e-9 {
+		t.Errorf("expected dominant share 0.2, got %v", got)
+	}
+
+	d.Unreserve(context.TODO(), nil, pod, "node1")
+	if got := d.DominantShare("ns"); got != 0 {
+		t.Errorf("expected no dominant share after Unreserve, got %v", got)
+	}
+}
+
+func TestNodeDominantShare(t *testing.T) {
+	d := newDominantResourceFairness(nil, "")
+	node := st.MakeNode().Name("node1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8", v1.ResourceMemory: "16Gi"}).Obj()
+	nodeInfo := framework.NewNodeInfo(
+		st.MakePod().Namespace("a").Name("a1").Node("node1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2", v1.ResourceMemory: "2Gi"}).Obj(),
+		st.MakePod().Namespace("b").Name("b1").Node("node1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "4", v1.ResourceMemory: "4Gi"}).Obj(),
+	)
+	nodeInfo.SetNode(node)
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected float64
+	}{
+		{
+			name:     "tenant already on the node",
+			pod:      st.MakePod().Namespace("a").Name("a2").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2", v1.ResourceMemory: "1Gi"}).Obj(),
+			expected: 0.5,
+		},
+		{
+			name:     "tenant new to the node",
+			pod:      st.MakePod().Namespace("c").Name("c1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1", v1.ResourceMemory: "8Gi"}).Obj(),
+			expected: 0.5,
+		},
+		{
+			name:     "tenant new to the node with a small request",
+			pod:      st.MakePod().Namespace("c").Name("c1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj(),
+			expected: 0.125,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.nodeDominantShare(tt.pod, nodeInfo); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
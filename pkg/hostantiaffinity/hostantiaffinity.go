@@ -0,0 +1,298 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostantiaffinity
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/helper"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling"
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "HostAntiAffinity"
+
+	preFilterStateKey = "PreFilter" + Name
+
+	// GroupLabel is the label of the pods, e.g. the virt-launcher pods of the KubeVirt VMIs, to spread across
+	// the physical hosts: the pods of a namespace with the same value form an anti-affinity group.
+	GroupLabel = scheduling.GroupName + "/host-anti-affinity-group"
+	// RequiredLabel is the label of the pods that must not share a physical host with another pod of their
+	// group, set to "true". The other pods of a group only prefer the least used physical hosts.
+	RequiredLabel = scheduling.GroupName + "/host-anti-affinity-required"
+
+	// PhysicalHostLabel is the label of the nodes naming the physical host running them,
+	// for the nodes not listed in a HostTopology.
+	PhysicalHostLabel = scheduling.GroupName + "/physical-host"
+	// FailureDomainLabel is the label of the nodes naming the failure domain of the physical host running them,
+	// for the nodes not listed in a HostTopology.
+	FailureDomainLabel = scheduling.GroupName + "/failure-domain"
+
+	// hostWeight is the weight of a group member on the same physical host in the score,
+	// compared with a group member in the same failure domain on another physical host.
+	hostWeight = 2
+)
+
+// HostAntiAffinity is a plugin spreading the pods of an anti-affinity group, e.g. the VMs of a KubeVirt
+// VirtualMachinePool, across the physical hosts running the virtualized nodes, and then across their failure domains.
+type HostAntiAffinity struct {
+	handle framework.Handle
+	client client.Client
+}
+
+var _ framework.PreFilterPlugin = &HostAntiAffinity{}
+var _ framework.FilterPlugin = &HostAntiAffinity{}
+var _ framework.ScorePlugin = &HostAntiAffinity{}
+var _ framework.EnqueueExtensions = &HostAntiAffinity{}
+
+// location is the physical host running a node and its failure domain.
+type location struct {
+	host          string
+	failureDomain string
+}
+
+// preFilterState computed at PreFilter and used at Filter and Score.
+type preFilterState struct {
+	// required tells whether the pod must not share a physical host with another pod of its group.
+	required bool
+	// locations are the physical hosts of the nodes.
+	locations map[string]location
+	// hostMembers is the number of pods of the group per physical host.
+	hostMembers map[string]int64
+	// failureDomainMembers is the number of pods of the group per failure domain.
+	failureDomainMembers map[string]int64
+}
+
+// Clone the preFilter state. The locations are not modified after PreFilter.
+func (s *preFilterState) Clone() framework.StateData {
+	return &preFilterState{
+		required:             s.required,
+		locations:            s.locations,
+		hostMembers:          maps.Clone(s.hostMembers),
+		failureDomainMembers: maps.Clone(s.failureDomainMembers),
+	}
+}
+
+// update adds delta pods of the group on the node to the members of its physical host and failure domain.
+func (s *preFilterState) update(nodeName string, delta int64) {
+	l := s.locations[nodeName]
+	s.hostMembers[l.host] += delta
+	if l.failureDomain != "" {
+		s.failureDomainMembers[l.failureDomain] += delta
+	}
+}
+
+// New initializes a new plugin and returns it.
+func New(ctx context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	lh := klog.FromContext(ctx)
+	lh.V(5).Info("creating new host anti-affinity plugin")
+
+	scheme := runtime.NewScheme()
+	_ = clientscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	client, err := client.New(handle.KubeConfig(), client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	return &HostAntiAffinity{
+		handle: handle,
+		client: client,
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (ha *HostAntiAffinity) Name() string {
+	return Name
+}
+
+// EventsToRegister returns the possible events that may make a Pod
+// failed by this plugin schedulable.
+func (ha *HostAntiAffinity) EventsToRegister(_ context.Context) ([]framework.ClusterEventWithHint, error) {
+	hostTopologyGVK := fmt.Sprintf("hosttopologies.v1alpha1.%v", scheduling.GroupName)
+	return []framework.ClusterEventWithHint{
+		{Event: framework.ClusterEvent{Resource: framework.Pod, ActionType: framework.Delete}},
+		{Event: framework.ClusterEvent{Resource: framework.Node, ActionType: framework.Add | framework.UpdateNodeLabel}},
+		{Event: framework.ClusterEvent{Resource: framework.GVK(hostTopologyGVK), ActionType: framework.All}},
+	}, nil
+}
+
+// PreFilter locates the nodes on the physical hosts and counts the pods of the group of the pod
+// on each physical host and failure domain. It is skipped for the pods without group.
+func (ha *HostAntiAffinity) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	group := pod.Labels[GroupLabel]
+	if group == "" {
+		return nil, framework.NewStatus(framework.Skip)
+	}
+	nodeInfos, err := ha.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, framework.AsStatus(fmt.Errorf("listing nodes from Snapshot: %w", err))
+	}
+	locations, err := ha.locateNodes(ctx, nodeInfos)
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+
+	s := &preFilterState{
+		required:             pod.Labels[RequiredLabel] == "true",
+		locations:            locations,
+		hostMembers:          make(map[string]int64),
+		failureDomainMembers: make(map[string]int64),
+	}
+	for _, nodeInfo := range nodeInfos {
+		for _, p := range nodeInfo.Pods {
+			if isGroupMember(pod, p.Pod) {
+				s.update(nodeInfo.Node().Name, 1)
+			}
+		}
+	}
+	state.Write(preFilterStateKey, s)
+	return nil, nil
+}
+
+// PreFilterExtensions returns a PreFilterExtensions interface if the plugin implements one.
+func (ha *HostAntiAffinity) PreFilterExtensions() framework.PreFilterExtensions {
+	return ha
+}
+
+// AddPod from pre-computed data in cycleState.
+func (ha *HostAntiAffinity) AddPod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *v1.Pod,
+	podInfoToAdd *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	return ha.updateState(cycleState, podToSchedule, podInfoToAdd.Pod, nodeInfo, 1)
+}
+
+// RemovePod from pre-computed data in cycleState.
+func (ha *HostAntiAffinity) RemovePod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *v1.Pod,
+	podInfoToRemove *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	return ha.updateState(cycleState, podToSchedule, podInfoToRemove.Pod, nodeInfo, -1)
+}
+
+func (ha *HostAntiAffinity) updateState(cycleState *framework.CycleState, podToSchedule, pod *v1.Pod,
+	nodeInfo *framework.NodeInfo, delta int64) *framework.Status {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if isGroupMember(podToSchedule, pod) {
+		s.update(nodeInfo.Node().Name, delta)
+	}
+	return nil
+}
+
+// Filter rejects the nodes on a physical host already running a pod of the group, if required.
+func (ha *HostAntiAffinity) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if !s.required {
+		return nil
+	}
+	host := s.locations[nodeInfo.Node().Name].host
+	if s.hostMembers[host] > 0 {
+		return framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("physical host %q already runs a pod of anti-affinity group %q", host, pod.Labels[GroupLabel]))
+	}
+	return nil
+}
+
+// Score counts the pods of the group on the physical host of the node, and in its failure domain.
+// The lower the count, the higher the normalized score.
+func (ha *HostAntiAffinity) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreFilterState(state)
+	if err != nil {
+		// The pod has no group.
+		return 0, nil
+	}
+	l := s.locations[nodeName]
+	score := hostWeight * s.hostMembers[l.host]
+	if l.failureDomain != "" {
+		score += s.failureDomainMembers[l.failureDomain]
+	}
+	return score, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (ha *HostAntiAffinity) ScoreExtensions() framework.ScoreExtensions {
+	return ha
+}
+
+// NormalizeScore reverses the scores, favoring the nodes with the fewest pods of the group around.
+func (ha *HostAntiAffinity) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	return helper.DefaultNormalizeScore(framework.MaxNodeScore, true, scores)
+}
+
+// locateNodes returns the physical host and failure domain of the nodes: the ones listed in the HostTopologies,
+// else the ones of the node labels. A node without physical host is its own physical host, e.g. a bare-metal node.
+func (ha *HostAntiAffinity) locateNodes(ctx context.Context, nodeInfos []*framework.NodeInfo) (map[string]location, error) {
+	var hostTopologyList v1alpha1.HostTopologyList
+	if err := ha.client.List(ctx, &hostTopologyList); err != nil {
+		return nil, fmt.Errorf("listing host topologies: %w", err)
+	}
+	locations := make(map[string]location)
+	for _, ht := range hostTopologyList.Items {
+		for _, host := range ht.Spec.Hosts {
+			for _, node := range host.Nodes {
+				locations[node] = location{host: host.Name, failureDomain: host.FailureDomain}
+			}
+		}
+	}
+
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		if _, ok := locations[node.Name]; ok {
+			continue
+		}
+		l := location{host: node.Labels[PhysicalHostLabel], failureDomain: node.Labels[FailureDomainLabel]}
+		if l.host == "" {
+			l.host = node.Name
+		}
+		locations[node.Name] = l
+	}
+	return locations, nil
+}
+
+// isGroupMember returns whether the pod is another running pod of the anti-affinity group of the pod to schedule.
+func isGroupMember(podToSchedule, pod *v1.Pod) bool {
+	return pod.UID != podToSchedule.UID &&
+		pod.Namespace == podToSchedule.Namespace &&
+		pod.Labels[GroupLabel] == podToSchedule.Labels[GroupLabel] &&
+		pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed
+}
+
+func getPreFilterState(state *framework.CycleState) (*preFilterState, error) {
+	c, err := state.Read(preFilterStateKey)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q from cycleState: %w", preFilterStateKey, err)
+	}
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to hostantiaffinity.preFilterState error", c)
+	}
+	return s, nil
+}
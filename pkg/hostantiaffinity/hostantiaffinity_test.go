@@ -0,0 +1,242 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostantiaffinity
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	tu "github.com/amiraBenamer20/scheduler-plugins/test/util"
+)
+
+// The VM nodes vm-1 and vm-2 run on the physical host host-a and vm-3 on host-b, both in rack-1, according
+// to the HostTopology. vm-4 runs on host-c in rack-2 according to its labels, and bm-5 is a bare-metal node.
+var hostTopology = &v1alpha1.HostTopology{
+	ObjectMeta: metav1.ObjectMeta{Name: "hypervisors"},
+	Spec: v1alpha1.HostTopologySpec{
+		Hosts: []v1alpha1.PhysicalHost{
+			{Name: "host-a", FailureDomain: "rack-1", Nodes: []string{"vm-1", "vm-2"}},
+			{Name: "host-b", FailureDomain: "rack-1", Nodes: []string{"vm-3"}},
+		},
+	},
+}
+
+func makeNodes() []*v1.Node {
+	return []*v1.Node{
+		st.MakeNode().Name("vm-1").Obj(),
+		st.MakeNode().Name("vm-2").Obj(),
+		st.MakeNode().Name("vm-3").Obj(),
+		st.MakeNode().Name("vm-4").Label(PhysicalHostLabel, "host-c").Label(FailureDomainLabel, "rack-2").Obj(),
+		st.MakeNode().Name("bm-5").Obj(),
+	}
+}
+
+func makeVMIPod(name, namespace, group, nodeName string) *v1.Pod {
+	pod := st.MakePod().Name(name).UID(name).Namespace(namespace).Label("kubevirt.io", "virt-launcher").Node(nodeName).Obj()
+	if group != "" {
+		pod.Labels[GroupLabel] = group
+	}
+	return pod
+}
+
+func TestHostAntiAffinity(t *testing.T) {
+	nodes := makeNodes()
+	existingPods := []*v1.Pod{
+		makeVMIPod("db-0", "ns", "db", "vm-1"),
+		makeVMIPod("db-1", "ns", "db", "vm-4"),
+		makeVMIPod("web-0", "ns", "web", "vm-3"),
+		makeVMIPod("db-0", "other", "db", "vm-3"),
+	}
+
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		wantPreFilter framework.Code
+		wantFilter    map[string]framework.Code
+		wantScores    framework.NodeScoreList
+	}{
+		{
+			name:          "pod without group",
+			pod:           makeVMIPod("vm", "ns", "", ""),
+			wantPreFilter: framework.Skip,
+			wantScores: framework.NodeScoreList{
+				{Name: "vm-1", Score: framework.MaxNodeScore},
+				{Name: "vm-2", Score: framework.MaxNodeScore},
+				{Name: "vm-3", Score: framework.MaxNodeScore},
+				{Name: "vm-4", Score: framework.MaxNodeScore},
+				{Name: "bm-5", Score: framework.MaxNodeScore},
+			},
+		},
+		{
+			name:          "preferred spreading across physical hosts and failure domains",
+			pod:           makeVMIPod("db-2", "ns", "db", ""),
+			wantPreFilter: framework.Success,
+			wantFilter: map[string]framework.Code{
+				"vm-1": framework.Success,
+				"vm-2": framework.Success,
+				"vm-3": framework.Success,
+				"vm-4": framework.Success,
+				"bm-5": framework.Success,
+			},
+			// raw scores: host-a 2*1+1, host-b 0*2+1, host-c 2*1+1, bm-5 0.
+			wantScores: framework.NodeScoreList{
+				{Name: "vm-1", Score: 0},
+				{Name: "vm-2", Score: 0},
+				{Name: "vm-3", Score: 67},
+				{Name: "vm-4", Score: 0},
+				{Name: "bm-5", Score: framework.MaxNodeScore},
+			},
+		},
+		{
+			name: "required spreading across physical hosts",
+			pod: func() *v1.Pod {
+				pod := makeVMIPod("db-2", "ns", "db", "")
+				pod.Labels[RequiredLabel] = "true"
+				return pod
+			}(),
+			wantPreFilter: framework.Success,
+			wantFilter: map[string]framework.Code{
+				"vm-1": framework.Unschedulable,
+				"vm-2": framework.Unschedulable,
+				"vm-3": framework.Success,
+				"vm-4": framework.Unschedulable,
+				"bm-5": framework.Success,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			scheme := runtime.NewScheme()
+			if err := v1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			fwk, err := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+				frameworkruntime.WithSnapshotSharedLister(tu.NewFakeSharedLister(existingPods, nodes)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ha := &HostAntiAffinity{handle: fwk, client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(hostTopology).Build()}
+			state := framework.NewCycleState()
+			if _, status := ha.PreFilter(ctx, state, tt.pod); status.Code() != tt.wantPreFilter {
+				t.Fatalf("unexpected PreFilter status: %v, want code %v", status, tt.wantPreFilter)
+			}
+			for _, node := range nodes {
+				want, ok := tt.wantFilter[node.Name]
+				if !ok {
+					continue
+				}
+				nodeInfo, _ := ha.handle.SnapshotSharedLister().NodeInfos().Get(node.Name)
+				if status := ha.Filter(ctx, state, tt.pod, nodeInfo); status.Code() != want {
+					t.Errorf("unexpected Filter status on %v: %v, want code %v", node.Name, status, want)
+				}
+			}
+			if tt.wantScores == nil {
+				return
+			}
+			var scores framework.NodeScoreList
+			for _, node := range nodes {
+				score, status := ha.Score(ctx, state, tt.pod, node.Name)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected Score status on %v: %v", node.Name, status)
+				}
+				scores = append(scores, framework.NodeScore{Name: node.Name, Score: score})
+			}
+			if status := ha.NormalizeScore(ctx, state, tt.pod, scores); !status.IsSuccess() {
+				t.Fatalf("unexpected NormalizeScore status: %v", status)
+			}
+			if !reflect.DeepEqual(scores, tt.wantScores) {
+				t.Errorf("unexpected scores %v, want %v", scores, tt.wantScores)
+			}
+		})
+	}
+}
+
+func TestHostAntiAffinityPreFilterExtensions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodes := makeNodes()
+	victim := makeVMIPod("db-0", "ns", "db", "vm-1")
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	registeredPlugins := []tf.RegisterPluginFunc{
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}
+	fwk, err := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+		frameworkruntime.WithSnapshotSharedLister(tu.NewFakeSharedLister([]*v1.Pod{victim}, nodes)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ha := &HostAntiAffinity{handle: fwk, client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(hostTopology).Build()}
+	pod := makeVMIPod("db-1", "ns", "db", "")
+	pod.Labels[RequiredLabel] = "true"
+
+	state := framework.NewCycleState()
+	if _, status := ha.PreFilter(ctx, state, pod); !status.IsSuccess() {
+		t.Fatalf("unexpected PreFilter status: %v", status)
+	}
+	nodeInfo, _ := ha.handle.SnapshotSharedLister().NodeInfos().Get("vm-2")
+	if status := ha.Filter(ctx, state, pod, nodeInfo); status.Code() != framework.Unschedulable {
+		t.Fatalf("expected vm-2 on the physical host of db-0 to be filtered out, got %v", status)
+	}
+
+	victimNodeInfo, _ := ha.handle.SnapshotSharedLister().NodeInfos().Get("vm-1")
+	victimInfo, err := framework.NewPodInfo(victim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateWithoutVictim := state.Clone()
+	if status := ha.RemovePod(ctx, stateWithoutVictim, pod, victimInfo, victimNodeInfo); !status.IsSuccess() {
+		t.Fatalf("unexpected RemovePod status: %v", status)
+	}
+	if status := ha.Filter(ctx, stateWithoutVictim, pod, nodeInfo); !status.IsSuccess() {
+		t.Errorf("expected vm-2 to fit once db-0 is removed, got %v", status)
+	}
+	if status := ha.Filter(ctx, state, pod, nodeInfo); status.Code() != framework.Unschedulable {
+		t.Errorf("expected the original state to be left untouched, got %v", status)
+	}
+
+	if status := ha.AddPod(ctx, stateWithoutVictim, pod, victimInfo, victimNodeInfo); !status.IsSuccess() {
+		t.Fatalf("unexpected AddPod status: %v", status)
+	}
+	if status := ha.Filter(ctx, stateWithoutVictim, pod, nodeInfo); status.Code() != framework.Unschedulable {
+		t.Errorf("expected vm-2 to be filtered out once db-0 is added back, got %v", status)
+	}
+}
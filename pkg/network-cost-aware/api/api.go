@@ -0,0 +1,280 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package api exposes the cost model of the NetworkCostAware plugin as a library. Given cached
+// AppGroup and NetworkTopology state, it computes the placement costs of a hypothetical pod per
+// zone, so that cluster-autoscaler node-group selection and external planners can rank zones
+// the same way the scheduler ranks nodes.
+package api
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/multicluster"
+	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
+)
+
+const (
+	// MaxCost : cost between locations without cost defined in the NetworkTopology
+	MaxCost = 100
+
+	// SameZone : If pods belong to the same zone, then consider cost as 1
+	SameZone = 1
+)
+
+// Location : location of a node in the network topology
+type Location struct {
+	Cluster string `json:"cluster,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Zone    string `json:"zone,omitempty"`
+}
+
+// NodeLocation : return the location of the node, in the local cluster if the node has no cluster label
+func NodeLocation(node *v1.Node, clusterName string) Location {
+	cluster := networkcostawareutil.GetNodeCluster(node)
+	if cluster == "" {
+		cluster = clusterName
+	}
+	return Location{
+		Cluster: cluster,
+		Region:  networkcostawareutil.GetNodeRegion(node),
+		Zone:    networkcostawareutil.GetNodeZone(node),
+	}
+}
+
+// Zones : return the distinct locations of the given nodes, e.g. as candidates for ZoneCosts
+func Zones(nodes []*v1.Node, clusterName string) []Location {
+	seen := make(map[Location]bool)
+	var zones []Location
+	for _, node := range nodes {
+		location := NodeLocation(node, clusterName)
+		if !seen[location] {
+			seen[location] = true
+			zones = append(zones, location)
+		}
+	}
+	return zones
+}
+
+// Options : options of the cost model, matching the NetworkCostArgs of the plugin
+type Options struct {
+	// WeightsName of the NetworkTopology costs
+	WeightsName string
+
+	// ClusterName of the local cluster
+	ClusterName string
+
+	// DependencyCostMode defaults to Sum
+	DependencyCostMode pluginconfig.DependencyCostMode
+}
+
+// Model : cost model of the NetworkCostAware plugin for an AppGroup and a NetworkTopology
+type Model struct {
+	appGroup     *agv1alpha1.AppGroup
+	costs        map[networkcostawareutil.CostKey]int64
+	clusterCosts multicluster.Costs
+	options      Options
+}
+
+// NewModel : build the cost model from cached AppGroup and NetworkTopology state
+func NewModel(appGroup *agv1alpha1.AppGroup, networkTopology *ntv1alpha1.NetworkTopology, options Options) *Model {
+	if options.DependencyCostMode == "" {
+		options.DependencyCostMode = pluginconfig.DependencyCostSum
+	}
+	m := &Model{
+		appGroup:     appGroup,
+		costs:        make(map[networkcostawareutil.CostKey]int64),
+		clusterCosts: multicluster.NewCosts(networkTopology, options.WeightsName),
+		options:      options,
+	}
+	if networkTopology == nil {
+		return m
+	}
+	for _, w := range networkTopology.Spec.Weights {
+		if w.Name != options.WeightsName {
+			continue
+		}
+		for _, t := range w.TopologyList {
+			if t.TopologyKey != ntv1alpha1.NetworkTopologyRegion && t.TopologyKey != ntv1alpha1.NetworkTopologyZone {
+				continue
+			}
+			for _, o := range t.OriginList {
+				for _, c := range o.CostList {
+					m.costs[networkcostawareutil.CostKey{Origin: o.Origin, Destination: c.Destination}] = c.NetworkCost
+				}
+			}
+		}
+	}
+	return m
+}
+
+// Cost : get the cost between an origin and a destination location, false if the NetworkTopology
+// defines no cost between them or the destination has no region and zone
+func (m *Model) Cost(origin Location, destination Location) (int64, bool) {
+	if m.crossCluster(origin, destination) { // belong to different clusters
+		return m.clusterCosts.Cost(origin.Cluster, destination.Cluster)
+	}
+	if destination.Region == "" && destination.Zone == "" { // Node has no zone and region defined
+		return MaxCost, false
+	}
+	if origin.Region == destination.Region { // If Nodes belong to the same region
+		if origin.Zone == destination.Zone { // If Nodes belong to the same zone
+			return SameZone, true
+		}
+		cost, ok := m.costs[networkcostawareutil.CostKey{Origin: origin.Zone, Destination: destination.Zone}]
+		return cost, ok
+	}
+	cost, ok := m.costs[networkcostawareutil.CostKey{Origin: origin.Region, Destination: destination.Region}]
+	return cost, ok
+}
+
+// ZoneCost : placement cost of a hypothetical pod in a zone
+type ZoneCost struct {
+	Location `json:",inline"`
+
+	// Accumulated cost of the pod dependencies
+	Cost int64 `json:"cost"`
+
+	// Number of dependencies meeting their maxNetworkCost
+	Satisfied int64 `json:"satisfied"`
+
+	// Number of dependencies exceeding their maxNetworkCost
+	Violated int64 `json:"violated"`
+
+	// Normalized score, higher for lower costs
+	Score int64 `json:"score"`
+
+	// Filtered is true if the plugin would filter out the nodes of the zone, i.e. more dependencies are violated than satisfied
+	Filtered bool `json:"filtered"`
+}
+
+// ZoneCosts : compute the placement costs of a hypothetical pod for each candidate zone. Pods are the
+// pods of the AppGroup of the given pod, and nodes the nodes hosting them: the pods bound to other nodes
+// are ignored. Zones are returned best first: not filtered, then highest score.
+func (m *Model) ZoneCosts(pod *v1.Pod, pods []*v1.Pod, nodes []*v1.Node, candidates []Location) []ZoneCost {
+	var dependencyList []agv1alpha1.DependenciesInfo
+	if m.appGroup != nil {
+		dependencyList = networkcostawareutil.GetDependencyList(pod, m.appGroup)
+	}
+
+	nodeLocations := make(map[string]Location, len(nodes))
+	for _, node := range nodes {
+		nodeLocations[node.Name] = NodeLocation(node, m.options.ClusterName)
+	}
+	scheduledList := networkcostawareutil.GetScheduledList(pods)
+
+	zoneCosts := make([]ZoneCost, 0, len(candidates))
+	for _, candidate := range candidates {
+		zoneCost := ZoneCost{Location: candidate}
+		// With the NearestReplica mode, only the nearest replica of each dependency is accounted
+		nearest := make(map[string]*nearestReplica)
+		for _, podAllocated := range scheduledList {
+			location, ok := nodeLocations[podAllocated.Hostname]
+			if !ok {
+				continue
+			}
+			for _, d := range dependencyList {
+				if podAllocated.Selector != d.Workload.Selector {
+					continue
+				}
+				cost, costOK := m.Cost(candidate, location)
+				if !costOK {
+					cost = MaxCost
+				}
+				// As in the plugin, a dependency with an unknown cost is neither satisfied nor violated,
+				// unless the node hosting it has no region and zone.
+				recorded := costOK || (!m.crossCluster(candidate, location) && location.Region == "" && location.Zone == "")
+				satisfied := costOK && cost <= d.MaxNetworkCost
+
+				if m.options.DependencyCostMode != pluginconfig.DependencyCostNearestReplica {
+					zoneCost.Cost += cost
+					if satisfied {
+						zoneCost.Satisfied += 1
+					} else if recorded {
+						zoneCost.Violated += 1
+					}
+					continue
+				}
+				n, ok := nearest[d.Workload.Selector]
+				if !ok {
+					n = &nearestReplica{cost: cost}
+					nearest[d.Workload.Selector] = n
+				}
+				n.cost = min(n.cost, cost)
+				n.recorded = n.recorded || recorded
+				n.satisfied = n.satisfied || satisfied
+			}
+		}
+		for _, n := range nearest {
+			zoneCost.Cost += n.cost
+			if n.satisfied {
+				zoneCost.Satisfied += 1
+			} else if n.recorded {
+				zoneCost.Violated += 1
+			}
+		}
+		zoneCost.Filtered = zoneCost.Violated > zoneCost.Satisfied
+		zoneCosts = append(zoneCosts, zoneCost)
+	}
+
+	normalizeScores(zoneCosts)
+	sort.SliceStable(zoneCosts, func(i, j int) bool {
+		if zoneCosts[i].Filtered != zoneCosts[j].Filtered {
+			return !zoneCosts[i].Filtered
+		}
+		return zoneCosts[i].Score > zoneCosts[j].Score
+	})
+	return zoneCosts
+}
+
+// nearestReplica : cost to the nearest replica of a dependency
+type nearestReplica struct {
+	cost      int64
+	recorded  bool
+	satisfied bool
+}
+
+// crossCluster : return true if the locations belong to different clusters
+func (m *Model) crossCluster(origin Location, destination Location) bool {
+	return origin.Cluster != "" && destination.Cluster != "" && origin.Cluster != destination.Cluster
+}
+
+// normalizeScores : normalize costs between framework.MaxNodeScore and framework.MinNodeScore, lower costs first
+func normalizeScores(zoneCosts []ZoneCost) {
+	if len(zoneCosts) == 0 {
+		return
+	}
+	minCost, maxCost := zoneCosts[0].Cost, zoneCosts[0].Cost
+	for _, z := range zoneCosts {
+		minCost = min(minCost, z.Cost)
+		maxCost = max(maxCost, z.Cost)
+	}
+	for i := range zoneCosts {
+		if maxCost == minCost {
+			zoneCosts[i].Score = framework.MaxNodeScore
+			continue
+		}
+		normCost := float64(framework.MaxNodeScore) * float64(zoneCosts[i].Cost-minCost) / float64(maxCost-minCost)
+		zoneCosts[i].Score = framework.MaxNodeScore - int64(normCost)
+	}
+}
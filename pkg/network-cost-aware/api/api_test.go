@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+func getNetworkTopology() *ntv1alpha1.NetworkTopology {
+	return &ntv1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt-test", Namespace: "default"},
+		Spec: ntv1alpha1.NetworkTopologySpec{
+			Weights: ntv1alpha1.WeightList{
+				ntv1alpha1.WeightInfo{Name: "UserDefined",
+					TopologyList: ntv1alpha1.TopologyList{
+						ntv1alpha1.TopologyInfo{
+							TopologyKey: ntv1alpha1.NetworkTopologyRegion,
+							OriginList: ntv1alpha1.OriginList{
+								ntv1alpha1.OriginInfo{Origin: "R1", CostList: []ntv1alpha1.CostInfo{{Destination: "R2", NetworkCost: 50}}},
+								ntv1alpha1.OriginInfo{Origin: "R2", CostList: []ntv1alpha1.CostInfo{{Destination: "R1", NetworkCost: 50}}},
+							}},
+						ntv1alpha1.TopologyInfo{
+							TopologyKey: ntv1alpha1.NetworkTopologyZone,
+							OriginList: ntv1alpha1.OriginList{
+								ntv1alpha1.OriginInfo{Origin: "Z1", CostList: []ntv1alpha1.CostInfo{{Destination: "Z2", NetworkCost: 5}}},
+								ntv1alpha1.OriginInfo{Origin: "Z2", CostList: []ntv1alpha1.CostInfo{{Destination: "Z1", NetworkCost: 5}}},
+							}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getAppGroup() *agv1alpha1.AppGroup {
+	workload := func(name string) agv1alpha1.AppGroupWorkloadInfo {
+		return agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: name + "-deployment", Selector: name, APIVersion: "apps/v1", Namespace: "default"}
+	}
+	return &agv1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: "default"},
+		Spec: agv1alpha1.AppGroupSpec{
+			NumMembers: 2,
+			Workloads: agv1alpha1.AppGroupWorkloadList{
+				agv1alpha1.AppGroupWorkload{Workload: workload("p1"),
+					Dependencies: agv1alpha1.DependenciesList{{Workload: workload("p2"), MaxNetworkCost: 10}}},
+				agv1alpha1.AppGroupWorkload{Workload: workload("p2")},
+			},
+		},
+	}
+}
+
+func makePod(name, selector, nodeName string) *v1.Pod {
+	return st.MakePod().Name(name).Namespace("default").Node(nodeName).
+		Labels(map[string]string{agv1alpha1.AppGroupLabel: "basic", agv1alpha1.AppGroupSelectorLabel: selector}).Obj()
+}
+
+func makeNode(name, region, zone string) *v1.Node {
+	return st.MakeNode().Name(name).Label(v1.LabelTopologyRegion, region).Label(v1.LabelTopologyZone, zone).Obj()
+}
+
+func TestModelCost(t *testing.T) {
+	m := NewModel(getAppGroup(), getNetworkTopology(), Options{WeightsName: "UserDefined"})
+
+	tests := []struct {
+		name        string
+		origin      Location
+		destination Location
+		cost        int64
+		ok          bool
+	}{
+		{name: "same zone", origin: Location{Region: "R1", Zone: "Z1"}, destination: Location{Region: "R1", Zone: "Z1"}, cost: SameZone, ok: true},
+		{name: "different zone", origin: Location{Region: "R1", Zone: "Z1"}, destination: Location{Region: "R1", Zone: "Z2"}, cost: 5, ok: true},
+		{name: "different region", origin: Location{Region: "R1", Zone: "Z1"}, destination: Location{Region: "R2", Zone: "Z3"}, cost: 50, ok: true},
+		{name: "unknown zone", origin: Location{Region: "R1", Zone: "Z1"}, destination: Location{Region: "R1", Zone: "Z4"}, cost: 0, ok: false},
+		{name: "destination without region and zone", origin: Location{Region: "R1", Zone: "Z1"}, destination: Location{}, cost: MaxCost, ok: false},
+		{name: "unknown cluster", origin: Location{Cluster: "c1"}, destination: Location{Cluster: "c2"}, cost: 0, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, ok := m.Cost(tt.origin, tt.destination)
+			if cost != tt.cost || ok != tt.ok {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.cost, tt.ok, cost, ok)
+			}
+		})
+	}
+}
+
+func TestZones(t *testing.T) {
+	nodes := []*v1.Node{
+		makeNode("n-1", "R1", "Z1"),
+		makeNode("n-2", "R1", "Z1"),
+		makeNode("n-3", "R1", "Z2"),
+	}
+	expected := []Location{
+		{Cluster: "local", Region: "R1", Zone: "Z1"},
+		{Cluster: "local", Region: "R1", Zone: "Z2"},
+	}
+	if got := Zones(nodes, "local"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestZoneCosts(t *testing.T) {
+	nodes := []*v1.Node{
+		makeNode("n-1", "R1", "Z1"),
+		makeNode("n-2", "R1", "Z2"),
+		makeNode("n-3", "R2", "Z3"),
+	}
+	candidates := []Location{
+		{Region: "R2", Zone: "Z3"},
+		{Region: "R1", Zone: "Z2"},
+		{Region: "R1", Zone: "Z1"},
+	}
+
+	tests := []struct {
+		name     string
+		mode     pluginconfig.DependencyCostMode
+		pods     []*v1.Pod
+		expected []ZoneCost
+	}{
+		{
+			name: "no dependency scheduled: zones score equally",
+			pods: []*v1.Pod{makePod("p2-1", "p2", "")},
+			expected: []ZoneCost{
+				{Location: Location{Region: "R2", Zone: "Z3"}, Score: 100},
+				{Location: Location{Region: "R1", Zone: "Z2"}, Score: 100},
+				{Location: Location{Region: "R1", Zone: "Z1"}, Score: 100},
+			},
+		},
+		{
+			name: "Sum: the zone of the dependency is the best, the remote region is filtered",
+			pods: []*v1.Pod{makePod("p2-1", "p2", "n-1")},
+			expected: []ZoneCost{
+				{Location: Location{Region: "R1", Zone: "Z1"}, Cost: 1, Satisfied: 1, Score: 100},
+				{Location: Location{Region: "R1", Zone: "Z2"}, Cost: 5, Satisfied: 1, Score: 92},
+				{Location: Location{Region: "R2", Zone: "Z3"}, Cost: 50, Violated: 1, Score: 0, Filtered: true},
+			},
+		},
+		{
+			name: "Sum: the costs to all the replicas are accumulated",
+			pods: []*v1.Pod{makePod("p2-1", "p2", "n-1"), makePod("p2-2", "p2", "n-3")},
+			expected: []ZoneCost{
+				{Location: Location{Region: "R2", Zone: "Z3"}, Cost: 51, Satisfied: 1, Violated: 1, Score: 100},
+				{Location: Location{Region: "R1", Zone: "Z1"}, Cost: 51, Satisfied: 1, Violated: 1, Score: 100},
+				{Location: Location{Region: "R1", Zone: "Z2"}, Cost: 55, Satisfied: 1, Violated: 1, Score: 0},
+			},
+		},
+		{
+			name: "NearestReplica: only the nearest replica is accounted",
+			mode: pluginconfig.DependencyCostNearestReplica,
+			pods: []*v1.Pod{makePod("p2-1", "p2", "n-1"), makePod("p2-2", "p2", "n-3")},
+			expected: []ZoneCost{
+				{Location: Location{Region: "R2", Zone: "Z3"}, Cost: 1, Satisfied: 1, Score: 100},
+				{Location: Location{Region: "R1", Zone: "Z1"}, Cost: 1, Satisfied: 1, Score: 100},
+				{Location: Location{Region: "R1", Zone: "Z2"}, Cost: 5, Satisfied: 1, Score: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(getAppGroup(), getNetworkTopology(), Options{WeightsName: "UserDefined", DependencyCostMode: tt.mode})
+			got := m.ZoneCosts(makePod("p1-1", "p1", ""), tt.pods, nodes, candidates)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
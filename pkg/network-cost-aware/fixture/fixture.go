@@ -0,0 +1,231 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fixture synthesizes NetworkTopology CRs, nodes, AppGroups and their pods of a configurable
+// scale for the unit tests, benchmarks and fuzz tests of the network cost aware plugins.
+package fixture
+
+import (
+	"fmt"
+	"math/rand"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// WeightsName : name of the weights of the synthesized NetworkTopology CRs
+	WeightsName = "UserDefined"
+
+	// the network costs are drawn in [min, max)
+	minRegionCost = 50
+	maxRegionCost = 150
+	minZoneCost   = 5
+	maxZoneCost   = 50
+)
+
+// Scale : the number of regions, of zones per region and of nodes per zone of a topology
+type Scale struct {
+	Regions        int
+	ZonesPerRegion int
+	NodesPerZone   int
+}
+
+// Nodes : return the number of nodes of the topology
+func (s Scale) Nodes() int {
+	return s.Regions * s.ZonesPerRegion * s.NodesPerZone
+}
+
+func (s Scale) String() string {
+	return fmt.Sprintf("%dx%dx%d", s.Regions, s.ZonesPerRegion, s.NodesPerZone)
+}
+
+// RegionName : name of the r-th region
+func RegionName(r int) string {
+	return fmt.Sprintf("r%d", r)
+}
+
+// ZoneName : name of the z-th zone of the r-th region, unique across the regions
+func ZoneName(r, z int) string {
+	return fmt.Sprintf("r%d-z%d", r, z)
+}
+
+// NodeName : name of the n-th node of the z-th zone of the r-th region
+func NodeName(r, z, n int) string {
+	return fmt.Sprintf("n-r%d-z%d-%d", r, z, n)
+}
+
+// WorkloadSelector : AppGroup selector of the w-th workload
+func WorkloadSelector(w int) string {
+	return fmt.Sprintf("w%d", w)
+}
+
+// NewNodes : return the nodes of the topology, labeled with their region and zone
+func NewNodes(scale Scale) []*v1.Node {
+	nodes := make([]*v1.Node, 0, scale.Nodes())
+	for r := 0; r < scale.Regions; r++ {
+		for z := 0; z < scale.ZonesPerRegion; z++ {
+			for n := 0; n < scale.NodesPerZone; n++ {
+				resources := v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("8"),
+					v1.ResourceMemory: resource.MustParse("16Gi"),
+					v1.ResourcePods:   resource.MustParse("110"),
+				}
+				nodes = append(nodes, &v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: NodeName(r, z, n),
+						Labels: map[string]string{
+							v1.LabelHostname:       NodeName(r, z, n),
+							v1.LabelTopologyRegion: RegionName(r),
+							v1.LabelTopologyZone:   ZoneName(r, z),
+						},
+					},
+					Status: v1.NodeStatus{Capacity: resources, Allocatable: resources},
+				})
+			}
+		}
+	}
+	return nodes
+}
+
+// NewNetworkTopology : return a NetworkTopology CR with random costs between all the regions, and between
+// the zones of each region. The origins and destinations are shuffled, as manually defined weights may be.
+func NewNetworkTopology(name, namespace string, scale Scale, rng *rand.Rand) *ntv1alpha1.NetworkTopology {
+	var regionOrigins, zoneOrigins ntv1alpha1.OriginList
+	for r := 0; r < scale.Regions; r++ {
+		var regionCosts ntv1alpha1.CostList
+		for d := 0; d < scale.Regions; d++ {
+			if d != r {
+				regionCosts = append(regionCosts, ntv1alpha1.CostInfo{
+					Destination: RegionName(d),
+					NetworkCost: minRegionCost + rng.Int63n(maxRegionCost-minRegionCost),
+				})
+			}
+		}
+		regionOrigins = append(regionOrigins, ntv1alpha1.OriginInfo{Origin: RegionName(r), CostList: shuffle(rng, regionCosts)})
+
+		for z := 0; z < scale.ZonesPerRegion; z++ {
+			var zoneCosts ntv1alpha1.CostList
+			for d := 0; d < scale.ZonesPerRegion; d++ {
+				if d != z {
+					zoneCosts = append(zoneCosts, ntv1alpha1.CostInfo{
+						Destination: ZoneName(r, d),
+						NetworkCost: minZoneCost + rng.Int63n(maxZoneCost-minZoneCost),
+					})
+				}
+			}
+			zoneOrigins = append(zoneOrigins, ntv1alpha1.OriginInfo{Origin: ZoneName(r, z), CostList: shuffle(rng, zoneCosts)})
+		}
+	}
+	topologyList := shuffle(rng, ntv1alpha1.TopologyList{
+		{TopologyKey: ntv1alpha1.NetworkTopologyRegion, OriginList: shuffle(rng, regionOrigins)},
+		{TopologyKey: ntv1alpha1.NetworkTopologyZone, OriginList: shuffle(rng, zoneOrigins)},
+	})
+	return &ntv1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(namespace + "/" + name)},
+		Spec: ntv1alpha1.NetworkTopologySpec{
+			Weights: ntv1alpha1.WeightList{{Name: WeightsName, TopologyList: topologyList}},
+		},
+		Status: ntv1alpha1.NetworkTopologyStatus{NodeCount: int64(scale.Nodes())},
+	}
+}
+
+// NewAppGroup : return an AppGroup of the given number of workloads, whose dependencies form a random DAG:
+// each workload depends on up to maxDependencies workloads of a greater index, with a random maximum
+// network cost. Its topology order is the order of the workloads.
+func NewAppGroup(name, namespace string, workloads, maxDependencies int, rng *rand.Rand) *agv1alpha1.AppGroup {
+	workloadInfo := func(w int) agv1alpha1.AppGroupWorkloadInfo {
+		return agv1alpha1.AppGroupWorkloadInfo{
+			Kind:       "Deployment",
+			Name:       WorkloadSelector(w) + "-deployment",
+			Selector:   WorkloadSelector(w),
+			APIVersion: "apps/v1",
+			Namespace:  namespace,
+		}
+	}
+	ag := &agv1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(namespace + "/" + name)},
+		Spec: agv1alpha1.AppGroupSpec{
+			NumMembers:               int32(workloads),
+			TopologySortingAlgorithm: "KahnSort",
+		},
+		Status: agv1alpha1.AppGroupStatus{
+			RunningWorkloads:        int32(workloads),
+			ScheduleStartTime:       metav1.Now(),
+			TopologyCalculationTime: metav1.Now(),
+		},
+	}
+	for w := 0; w < workloads; w++ {
+		workload := agv1alpha1.AppGroupWorkload{Workload: workloadInfo(w)}
+		if candidates := workloads - w - 1; candidates > 0 && maxDependencies > 0 {
+			dependencies := rng.Intn(min(maxDependencies, candidates) + 1)
+			for _, d := range rng.Perm(candidates)[:dependencies] {
+				workload.Dependencies = append(workload.Dependencies, agv1alpha1.DependenciesInfo{
+					Workload:       workloadInfo(w + 1 + d),
+					MaxNetworkCost: minZoneCost + rng.Int63n(maxRegionCost),
+				})
+			}
+		}
+		ag.Spec.Workloads = append(ag.Spec.Workloads, workload)
+		ag.Status.TopologyOrder = append(ag.Status.TopologyOrder, agv1alpha1.AppGroupTopologyInfo{
+			Workload: workloadInfo(w),
+			Index:    int32(w + 1),
+		})
+	}
+	return ag
+}
+
+// NewPods : return the given number of replicas of each workload of the AppGroup, placed on random nodes
+func NewPods(ag *agv1alpha1.AppGroup, nodes []*v1.Node, replicas int, rng *rand.Rand) []*v1.Pod {
+	var pods []*v1.Pod
+	for _, w := range ag.Spec.Workloads {
+		for i := 0; i < replicas; i++ {
+			pod := NewPod(ag, w.Workload.Selector, i)
+			pod.Spec.NodeName = nodes[rng.Intn(len(nodes))].Name
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// NewPod : return the i-th replica of the workload of the AppGroup, not scheduled yet
+func NewPod(ag *agv1alpha1.AppGroup, selector string, i int) *v1.Pod {
+	name := fmt.Sprintf("%s-%d", selector, i)
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ag.Namespace,
+			UID:       types.UID(ag.Namespace + "/" + name),
+			Labels: map[string]string{
+				agv1alpha1.AppGroupLabel:         ag.Name,
+				agv1alpha1.AppGroupSelectorLabel: selector,
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: selector}},
+		},
+	}
+}
+
+// shuffle : shuffle the list in place and return it
+func shuffle[S ~[]E, E any](rng *rand.Rand, list S) S {
+	rng.Shuffle(len(list), func(i, j int) { list[i], list[j] = list[j], list[i] })
+	return list
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fixture
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestNewNetworkTopology(t *testing.T) {
+	scale := Scale{Regions: 3, ZonesPerRegion: 4, NodesPerZone: 2}
+	nodes := NewNodes(scale)
+	if len(nodes) != 24 {
+		t.Fatalf("expected 24 nodes, got %d", len(nodes))
+	}
+	zones := sets.New[string]()
+	for _, node := range nodes {
+		zones.Insert(node.Labels[v1.LabelTopologyZone])
+	}
+	if zones.Len() != 12 {
+		t.Errorf("expected the zones to be unique across the regions, got %v", sets.List(zones))
+	}
+
+	nt := NewNetworkTopology("nt", "default", scale, rand.New(rand.NewSource(1)))
+	for _, topology := range nt.Spec.Weights[0].TopologyList {
+		wantOrigins, wantDestinations := 3, 2
+		if topology.TopologyKey == ntv1alpha1.NetworkTopologyZone {
+			wantOrigins, wantDestinations = 12, 3
+		}
+		if len(topology.OriginList) != wantOrigins {
+			t.Errorf("expected %d origins for %v, got %d", wantOrigins, topology.TopologyKey, len(topology.OriginList))
+		}
+		for _, origin := range topology.OriginList {
+			if len(origin.CostList) != wantDestinations {
+				t.Errorf("expected %d destinations from %v, got %v", wantDestinations, origin.Origin, origin.CostList)
+			}
+		}
+	}
+
+	if again := NewNetworkTopology("nt", "default", scale, rand.New(rand.NewSource(1))); !reflect.DeepEqual(nt, again) {
+		t.Error("expected the same NetworkTopology for the same seed")
+	}
+}
+
+func TestNewAppGroup(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ag := NewAppGroup("ag", "default", 20, 4, rng)
+	if len(ag.Spec.Workloads) != 20 || len(ag.Status.TopologyOrder) != 20 {
+		t.Fatalf("expected 20 workloads, got %v", ag.Spec.Workloads)
+	}
+	index := make(map[string]int32)
+	for _, order := range ag.Status.TopologyOrder {
+		index[order.Workload.Selector] = order.Index
+	}
+	for _, w := range ag.Spec.Workloads {
+		if len(w.Dependencies) > 4 {
+			t.Errorf("expected at most 4 dependencies for %v, got %d", w.Workload.Selector, len(w.Dependencies))
+		}
+		for _, d := range w.Dependencies {
+			// acyclic: the dependencies come later in the topology order
+			if index[d.Workload.Selector] <= index[w.Workload.Selector] {
+				t.Errorf("expected %v to come after %v", d.Workload.Selector, w.Workload.Selector)
+			}
+		}
+	}
+
+	nodes := NewNodes(Scale{Regions: 1, ZonesPerRegion: 1, NodesPerZone: 3})
+	pods := NewPods(ag, nodes, 2, rng)
+	if len(pods) != 40 {
+		t.Fatalf("expected 40 pods, got %d", len(pods))
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			t.Errorf("expected %v to be placed on a node", pod.Name)
+		}
+	}
+}
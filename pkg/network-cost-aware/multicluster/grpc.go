@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+const (
+	// ScoringServiceName : name of the gRPC service scoring clusters
+	ScoringServiceName = "networkcost.multicluster.Scoring"
+
+	scoreClustersMethod = "/" + ScoringServiceName + "/ScoreClusters"
+)
+
+// ScoreClustersRequest : request to score candidate clusters for hosting an AppGroup
+type ScoreClustersRequest struct {
+	// Namespace of the AppGroup and NetworkTopology CRs
+	Namespace string `json:"namespace"`
+
+	// Name of the AppGroup CR
+	AppGroupName string `json:"appGroupName"`
+
+	// Name of the NetworkTopology CR
+	NetworkTopologyName string `json:"networkTopologyName"`
+
+	// Preferred weights of the NetworkTopology
+	WeightsName string `json:"weightsName"`
+
+	// Cluster of the AppGroup workloads already placed, by workload selector
+	Placements map[string]string `json:"placements,omitempty"`
+
+	// Clusters to score
+	Candidates []string `json:"candidates"`
+}
+
+// ScoreClustersResponse : candidate clusters, best first
+type ScoreClustersResponse struct {
+	Scores []ClusterScore `json:"scores"`
+}
+
+// ScoringServer : server API of the scoring service
+type ScoringServer interface {
+	ScoreClusters(context.Context, *ScoreClustersRequest) (*ScoreClustersResponse, error)
+}
+
+// Server : ScoringServer getting AppGroup and NetworkTopology CRs through a client
+type Server struct {
+	client client.Reader
+}
+
+var _ ScoringServer = &Server{}
+
+// NewServer : create a scoring server reading CRs with the given client
+func NewServer(client client.Reader) *Server {
+	return &Server{client: client}
+}
+
+// ScoreClusters : score candidate clusters for hosting the AppGroup of the request
+func (s *Server) ScoreClusters(ctx context.Context, req *ScoreClustersRequest) (*ScoreClustersResponse, error) {
+	if req.AppGroupName == "" || req.NetworkTopologyName == "" || req.WeightsName == "" {
+		return nil, status.Error(codes.InvalidArgument, "appGroupName, networkTopologyName and weightsName are required")
+	}
+
+	appGroup := &agv1alpha1.AppGroup{}
+	if err := s.client.Get(ctx, client.ObjectKey{Namespace: req.Namespace, Name: req.AppGroupName}, appGroup); err != nil {
+		return nil, toStatus(err)
+	}
+	networkTopology := &ntv1alpha1.NetworkTopology{}
+	if err := s.client.Get(ctx, client.ObjectKey{Namespace: req.Namespace, Name: req.NetworkTopologyName}, networkTopology); err != nil {
+		return nil, toStatus(err)
+	}
+
+	costs := NewCosts(networkTopology, req.WeightsName)
+	return &ScoreClustersResponse{
+		Scores: ScoreClusters(appGroup, costs, req.Placements, req.Candidates),
+	}, nil
+}
+
+func toStatus(err error) error {
+	if apierrors.IsNotFound(err) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// RegisterScoringServer : register the scoring service on a gRPC server, created with the JSON codec of the
+// service as by NewGRPCServer
+func RegisterScoringServer(s grpc.ServiceRegistrar, srv ScoringServer) {
+	s.RegisterService(&scoringServiceDesc, srv)
+}
+
+// NewGRPCServer : create a gRPC server serving the scoring service with its JSON codec, and the given options,
+// e.g. grpc.Creds(TLSCredentials(certDir))
+func NewGRPCServer(srv ScoringServer, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(append([]grpc.ServerOption{grpc.ForceServerCodec(util.JSONCodec{})}, opts...)...)
+	RegisterScoringServer(s, srv)
+	return s
+}
+
+// TLSCredentials : credentials serving the scoring service over TLS with the tls.crt and tls.key of the directory
+func TLSCredentials(certDir string) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(certDir, "tls.crt"), filepath.Join(certDir, "tls.key"))
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}), nil
+}
+
+var scoringServiceDesc = grpc.ServiceDesc{
+	ServiceName: ScoringServiceName,
+	HandlerType: (*ScoringServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ScoreClusters",
+			Handler:    scoreClustersHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+func scoreClustersHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScoreClustersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScoringServer).ScoreClusters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: scoreClustersMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScoringServer).ScoreClusters(ctx, req.(*ScoreClustersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ScoringClient : client API of the scoring service
+type ScoringClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewScoringClient : create a client of the scoring service
+func NewScoringClient(cc grpc.ClientConnInterface) *ScoringClient {
+	return &ScoringClient{cc: cc}
+}
+
+// ScoreClusters : score candidate clusters for hosting an AppGroup
+func (c *ScoringClient) ScoreClusters(ctx context.Context, in *ScoreClustersRequest, opts ...grpc.CallOption) (*ScoreClustersResponse, error) {
+	out := new(ScoreClustersResponse)
+	opts = append([]grpc.CallOption{grpc.ForceCodec(util.JSONCodec{})}, opts...)
+	if err := c.cc.Invoke(ctx, scoreClustersMethod, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Endpoint : serves the scoring service on an address until its context is done
+type Endpoint struct {
+	address string
+	server  ScoringServer
+	opts    []grpc.ServerOption
+}
+
+// NewEndpoint : create an endpoint serving the scoring service on the given address, with the given options of
+// its gRPC server, e.g. its credentials
+func NewEndpoint(address string, server ScoringServer, opts ...grpc.ServerOption) *Endpoint {
+	return &Endpoint{address: address, server: server, opts: opts}
+}
+
+// Start : serve the scoring service, implements the controller-runtime manager.Runnable interface
+func (e *Endpoint) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", e.address)
+	if err != nil {
+		return err
+	}
+	s := NewGRPCServer(e.server, e.opts...)
+	go func() {
+		<-ctx.Done()
+		s.GracefulStop()
+	}()
+	klog.FromContext(ctx).Info("Serving NetworkCostAware multi-cluster scoring", "address", e.address)
+	return s.Serve(listener)
+}
+
+// NeedLeaderElection : every replica serves the scoring service
+func (e *Endpoint) NeedLeaderElection() bool {
+	return false
+}
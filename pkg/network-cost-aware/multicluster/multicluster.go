@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package multicluster provides the costs between clusters declared in a NetworkTopology
+// (topology key networktopology.diktyo.x-k8s.io/cluster) and scores clusters for hosting
+// the workloads of an AppGroup. It is used by the NetworkCostAware plugin for nodes of
+// remote clusters, and may be reused by multi-cluster schedulers for fleet admission.
+package multicluster
+
+import (
+	"sort"
+
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
+)
+
+const (
+	// SameCluster : If workloads belong to the same cluster, then consider cost as 0
+	SameCluster = 0
+
+	// MaxCost : cost between clusters without cost defined in the NetworkTopology
+	MaxCost = 100
+)
+
+// Costs : costs between clusters (origin / destination) of a NetworkTopology
+type Costs map[networkcostawareutil.CostKey]int64
+
+// NewCosts : get the costs between clusters of a NetworkTopology for the given weights
+func NewCosts(networkTopology *ntv1alpha1.NetworkTopology, weightsName string) Costs {
+	costs := Costs{}
+	if networkTopology == nil {
+		return costs
+	}
+	for _, w := range networkTopology.Spec.Weights {
+		if w.Name != weightsName {
+			continue
+		}
+		for _, t := range w.TopologyList {
+			if t.TopologyKey != networkcostawareutil.NetworkTopologyCluster {
+				continue
+			}
+			for _, o := range t.OriginList {
+				for _, c := range o.CostList {
+					costs[networkcostawareutil.CostKey{Origin: o.Origin, Destination: c.Destination}] = c.NetworkCost
+				}
+			}
+		}
+	}
+	return costs
+}
+
+// Cost : get the cost between an origin and a destination cluster
+func (c Costs) Cost(origin string, destination string) (int64, bool) {
+	if origin == destination {
+		return SameCluster, true
+	}
+	cost, ok := c[networkcostawareutil.CostKey{Origin: origin, Destination: destination}]
+	return cost, ok
+}
+
+// ClusterScore : result of scoring a cluster for hosting the workloads of an AppGroup
+type ClusterScore struct {
+	// Name of the cluster
+	Cluster string `json:"cluster"`
+
+	// Accumulated cost of the AppGroup dependencies
+	Cost int64 `json:"cost"`
+
+	// Number of dependencies meeting their maxNetworkCost
+	Satisfied int64 `json:"satisfied"`
+
+	// Number of dependencies exceeding their maxNetworkCost
+	Violated int64 `json:"violated"`
+
+	// Normalized score, higher for lower costs
+	Score int64 `json:"score"`
+}
+
+// ScoreClusters : score candidate clusters for hosting the workloads of an AppGroup not placed yet.
+// Placements maps the selector of already placed workloads to their cluster. Each dependency with
+// at least one workload not placed yet is evaluated as if that workload was hosted by the candidate.
+// Clusters are returned best first: least violated dependencies, then highest score.
+func ScoreClusters(appGroup *agv1alpha1.AppGroup, costs Costs, placements map[string]string, candidates []string) []ClusterScore {
+	scores := make([]ClusterScore, 0, len(candidates))
+	for _, candidate := range candidates {
+		clusterOf := func(selector string) string {
+			if cluster, ok := placements[selector]; ok {
+				return cluster
+			}
+			return candidate
+		}
+
+		score := ClusterScore{Cluster: candidate}
+		for _, w := range appGroup.Spec.Workloads {
+			_, placed := placements[w.Workload.Selector]
+			for _, d := range w.Dependencies {
+				if _, dependencyPlaced := placements[d.Workload.Selector]; placed && dependencyPlaced {
+					continue
+				}
+				cost, ok := costs.Cost(clusterOf(w.Workload.Selector), clusterOf(d.Workload.Selector))
+				if !ok {
+					// Unknown links can't be told to meet the maximum network cost of the dependency.
+					score.Cost += MaxCost
+					score.Violated += 1
+					continue
+				}
+				score.Cost += cost
+				if cost <= d.MaxNetworkCost {
+					score.Satisfied += 1
+				} else {
+					score.Violated += 1
+				}
+			}
+		}
+		scores = append(scores, score)
+	}
+
+	normalizeScores(scores)
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Violated != scores[j].Violated {
+			return scores[i].Violated < scores[j].Violated
+		}
+		return scores[i].Score > scores[j].Score
+	})
+	return scores
+}
+
+// normalizeScores : normalize costs between framework.MaxNodeScore and framework.MinNodeScore, lower costs first
+func normalizeScores(scores []ClusterScore) {
+	if len(scores) == 0 {
+		return
+	}
+	minCost, maxCost := scores[0].Cost, scores[0].Cost
+	for _, s := range scores {
+		minCost = min(minCost, s.Cost)
+		maxCost = max(maxCost, s.Cost)
+	}
+	for i := range scores {
+		if maxCost == minCost {
+			scores[i].Score = framework.MaxNodeScore
+			continue
+		}
+		normCost := float64(framework.MaxNodeScore) * float64(scores[i].Cost-minCost) / float64(maxCost-minCost)
+		scores[i].Score = framework.MaxNodeScore - int64(normCost)
+	}
+}
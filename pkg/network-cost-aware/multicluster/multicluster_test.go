@@ -0,0 +1,453 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
+)
+
+func getNetworkTopologyClusters() *ntv1alpha1.NetworkTopology {
+	return &ntv1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt-fleet", Namespace: "default"},
+		Spec: ntv1alpha1.NetworkTopologySpec{
+			Weights: ntv1alpha1.WeightList{
+				ntv1alpha1.WeightInfo{Name: "UserDefined",
+					TopologyList: ntv1alpha1.TopologyList{
+						ntv1alpha1.TopologyInfo{
+							TopologyKey: networkcostawareutil.NetworkTopologyCluster,
+							OriginList: ntv1alpha1.OriginList{
+								ntv1alpha1.OriginInfo{Origin: "c1", CostList: []ntv1alpha1.CostInfo{
+									{Destination: "c2", NetworkCost: 10}, {Destination: "c3", NetworkCost: 50}}},
+								ntv1alpha1.OriginInfo{Origin: "c2", CostList: []ntv1alpha1.CostInfo{
+									{Destination: "c1", NetworkCost: 10}, {Destination: "c3", NetworkCost: 40}}},
+								ntv1alpha1.OriginInfo{Origin: "c3", CostList: []ntv1alpha1.CostInfo{
+									{Destination: "c1", NetworkCost: 50}, {Destination: "c2", NetworkCost: 40}}},
+							}},
+						ntv1alpha1.TopologyInfo{
+							TopologyKey: "topology.kubernetes.io/zone",
+							OriginList: ntv1alpha1.OriginList{
+								ntv1alpha1.OriginInfo{Origin: "Z1", CostList: []ntv1alpha1.CostInfo{{Destination: "Z2", NetworkCost: 5}}},
+							}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getAppGroupClusters() *agv1alpha1.AppGroup {
+	workload := func(name string) agv1alpha1.AppGroupWorkloadInfo {
+		return agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: name + "-deployment", Selector: name, APIVersion: "apps/v1", Namespace: "default"}
+	}
+	return &agv1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: "default"},
+		Spec: agv1alpha1.AppGroupSpec{
+			NumMembers: 3,
+			Workloads: agv1alpha1.AppGroupWorkloadList{
+				agv1alpha1.AppGroupWorkload{Workload: workload("p1"),
+					Dependencies: agv1alpha1.DependenciesList{{Workload: workload("p2"), MaxNetworkCost: 15}}},
+				agv1alpha1.AppGroupWorkload{Workload: workload("p2"),
+					Dependencies: agv1alpha1.DependenciesList{{Workload: workload("p3"), MaxNetworkCost: 45}}},
+				agv1alpha1.AppGroupWorkload{Workload: workload("p3")},
+			},
+		},
+	}
+}
+
+func TestCosts(t *testing.T) {
+	costs := NewCosts(getNetworkTopologyClusters(), "UserDefined")
+
+	tests := []struct {
+		name        string
+		origin      string
+		destination string
+		cost        int64
+		ok          bool
+	}{
+		{name: "same cluster", origin: "c1", destination: "c1", cost: SameCluster, ok: true},
+		{name: "remote cluster", origin: "c1", destination: "c3", cost: 50, ok: true},
+		{name: "unknown cluster", origin: "c1", destination: "c4", cost: 0, ok: false},
+		{name: "zones are ignored", origin: "Z1", destination: "Z2", cost: 0, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, ok := costs.Cost(tt.origin, tt.destination)
+			if cost != tt.cost || ok != tt.ok {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.cost, tt.ok, cost, ok)
+			}
+		})
+	}
+
+	if got := NewCosts(getNetworkTopologyClusters(), "Other"); len(got) != 0 {
+		t.Errorf("expected no costs for unknown weights, got %v", got)
+	}
+	if got := NewCosts(nil, "UserDefined"); len(got) != 0 {
+		t.Errorf("expected no costs without NetworkTopology, got %v", got)
+	}
+}
+
+func TestScoreClusters(t *testing.T) {
+	costs := NewCosts(getNetworkTopologyClusters(), "UserDefined")
+
+	tests := []struct {
+		name       string
+		placements map[string]string
+		candidates []string
+		expected   []ClusterScore
+	}{
+		{
+			name:       "nothing placed: every candidate hosts the whole AppGroup",
+			candidates: []string{"c1", "c2"},
+			expected: []ClusterScore{
+				{Cluster: "c1", Cost: 0, Satisfied: 2, Violated: 0, Score: 100},
+				{Cluster: "c2", Cost: 0, Satisfied: 2, Violated: 0, Score: 100},
+			},
+		},
+		{
+			name:       "p1 and p2 placed in c1: p3 is scored",
+			placements: map[string]string{"p1": "c1", "p2": "c1"},
+			candidates: []string{"c3", "c2", "c1"},
+			expected: []ClusterScore{
+				{Cluster: "c1", Cost: 0, Satisfied: 1, Violated: 0, Score: 100},
+				{Cluster: "c2", Cost: 10, Satisfied: 1, Violated: 0, Score: 80},
+				{Cluster: "c3", Cost: 50, Satisfied: 0, Violated: 1, Score: 0},
+			},
+		},
+		{
+			name:       "p1 placed in c1, p3 placed in c3: p2 is scored",
+			placements: map[string]string{"p1": "c1", "p3": "c3"},
+			candidates: []string{"c1", "c2", "c3"},
+			expected: []ClusterScore{
+				{Cluster: "c2", Cost: 50, Satisfied: 2, Violated: 0, Score: 100},
+				{Cluster: "c1", Cost: 50, Satisfied: 1, Violated: 1, Score: 100},
+				{Cluster: "c3", Cost: 50, Satisfied: 1, Violated: 1, Score: 100},
+			},
+		},
+		{
+			name:       "unknown cluster gets the maximum cost and violates the dependency",
+			placements: map[string]string{"p1": "c1", "p2": "c1"},
+			candidates: []string{"c4", "c1"},
+			expected: []ClusterScore{
+				{Cluster: "c1", Cost: 0, Satisfied: 1, Violated: 0, Score: 100},
+				{Cluster: "c4", Cost: MaxCost, Satisfied: 0, Violated: 1, Score: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreClusters(getAppGroupClusters(), costs, tt.placements, tt.candidates)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestScoringService(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(agv1alpha1.AddToScheme(s))
+	utilruntime.Must(ntv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(getAppGroupClusters(), getNetworkTopologyClusters()).
+		Build()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewGRPCServer(NewServer(c))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+	scoringClient := NewScoringClient(conn)
+
+	tests := []struct {
+		name     string
+		request  *ScoreClustersRequest
+		expected *ScoreClustersResponse
+		code     codes.Code
+	}{
+		{
+			name: "score candidates",
+			request: &ScoreClustersRequest{
+				Namespace:           "default",
+				AppGroupName:        "basic",
+				NetworkTopologyName: "nt-fleet",
+				WeightsName:         "UserDefined",
+				Placements:          map[string]string{"p1": "c1", "p2": "c1"},
+				Candidates:          []string{"c3", "c1"},
+			},
+			expected: &ScoreClustersResponse{Scores: []ClusterScore{
+				{Cluster: "c1", Cost: 0, Satisfied: 1, Violated: 0, Score: 100},
+				{Cluster: "c3", Cost: 50, Satisfied: 0, Violated: 1, Score: 0},
+			}},
+			code: codes.OK,
+		},
+		{
+			name: "missing AppGroup",
+			request: &ScoreClustersRequest{
+				Namespace:           "default",
+				AppGroupName:        "unknown",
+				NetworkTopologyName: "nt-fleet",
+				WeightsName:         "UserDefined",
+				Candidates:          []string{"c1"},
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "missing weights",
+			request: &ScoreClustersRequest{
+				Namespace:           "default",
+				AppGroupName:        "basic",
+				NetworkTopologyName: "nt-fleet",
+				Candidates:          []string{"c1"},
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scoringClient.ScoreClusters(context.Background(), tt.request)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("expected code %v, got %v: %v", tt.code, code, err)
+			}
+			if tt.code == codes.OK && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// writeServingCert writes a self-signed serving certificate for localhost to the directory, returning it.
+func writeServingCert(t *testing.T, certDir string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(certDir, "tls.crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(certDir, "tls.key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestScoringServiceOverTLS(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(agv1alpha1.AddToScheme(s))
+	utilruntime.Must(ntv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(getAppGroupClusters(), getNetworkTopologyClusters()).
+		Build()
+
+	certDir := t.TempDir()
+	cert := writeServingCert(t, certDir)
+	serverCredentials, err := TLSCredentials(certDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TLSCredentials(t.TempDir()); err == nil {
+		t.Error("expected an error without serving certificate")
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewGRPCServer(NewServer(c), grpc.Creds(serverCredentials))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	dial := func(transportCredentials credentials.TransportCredentials) *ScoringClient {
+		conn, err := grpc.NewClient("passthrough:///localhost",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(transportCredentials))
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return NewScoringClient(conn)
+	}
+	request := &ScoreClustersRequest{
+		Namespace:           "default",
+		AppGroupName:        "basic",
+		NetworkTopologyName: "nt-fleet",
+		WeightsName:         "UserDefined",
+		Placements:          map[string]string{"p1": "c1", "p2": "c1"},
+		Candidates:          []string{"c1"},
+	}
+
+	if _, err := dial(insecure.NewCredentials()).ScoreClusters(context.Background(), request); status.Code(err) != codes.Unavailable {
+		t.Errorf("expected a plaintext call to fail, got %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	got, err := dial(credentials.NewTLS(&tls.Config{RootCAs: roots})).ScoreClusters(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Scores) != 1 || got.Scores[0].Cluster != "c1" {
+		t.Errorf("expected the score of c1, got %+v", got)
+	}
+}
+
+func TestScoringServiceDesc(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(agv1alpha1.AddToScheme(s))
+	utilruntime.Must(ntv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(getAppGroupClusters(), getNetworkTopologyClusters()).
+		Build()
+
+	var (
+		fullMethod  string
+		contentType []string
+		received    *ScoreClustersRequest
+	)
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		fullMethod = info.FullMethod
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			contentType = md.Get("content-type")
+		}
+		received, _ = req.(*ScoreClustersRequest)
+		return handler(ctx, req)
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewGRPCServer(NewServer(c), grpc.UnaryInterceptor(interceptor))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	info, ok := server.GetServiceInfo()[ScoringServiceName]
+	if !ok || len(info.Methods) != 1 || info.Methods[0].Name != "ScoreClusters" || info.Methods[0].IsClientStream || info.Methods[0].IsServerStream {
+		t.Fatalf("expected the unary ScoreClusters method of %s, got %+v", ScoringServiceName, server.GetServiceInfo())
+	}
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	request := &ScoreClustersRequest{
+		Namespace:           "default",
+		AppGroupName:        "basic",
+		NetworkTopologyName: "nt-fleet",
+		WeightsName:         "UserDefined",
+		Placements:          map[string]string{"p1": "c1", "p2": "c1"},
+		Candidates:          []string{"c3", "c1"},
+	}
+	got, err := NewScoringClient(conn).ScoreClusters(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fullMethod != scoreClustersMethod {
+		t.Errorf("expected the interceptor to see %s, got %s", scoreClustersMethod, fullMethod)
+	}
+	if want := []string{"application/grpc+json"}; !reflect.DeepEqual(contentType, want) {
+		t.Errorf("expected content type %v, got %v", want, contentType)
+	}
+	if !reflect.DeepEqual(received, request) {
+		t.Errorf("expected the request %+v to round trip, got %+v", request, received)
+	}
+	expected := &ScoreClustersResponse{Scores: []ClusterScore{
+		{Cluster: "c1", Cost: 0, Satisfied: 1, Violated: 0, Score: 100},
+		{Cluster: "c3", Cost: 50, Satisfied: 0, Violated: 1, Score: 0},
+	}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the response %+v to round trip, got %+v", expected, got)
+	}
+
+	// The messages are plain Go structs: the default proto codec can't encode them.
+	if err := conn.Invoke(context.Background(), scoreClustersMethod, request, &ScoreClustersResponse{}); status.Code(err) != codes.Internal {
+		t.Errorf("expected a call without the JSON codec to fail, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkcost
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+)
+
+// OptOutAnnotation : annotation of a pod opting out of the plugin with the "true" value, the pod being scored equally
+// on all the nodes, e.g. while rolling out network-cost-aware scheduling workload by workload
+const OptOutAnnotation = agv1alpha1.AppGroupLabel + "/network-cost-opt-out"
+
+// enabledFor : whether the plugin applies to the pod, per its namespace and its opt-out annotation
+func (no *NetworkCostAware) enabledFor(pod *corev1.Pod) bool {
+	if pod.Annotations[OptOutAnnotation] == "true" {
+		return false
+	}
+	if no.deniedNamespaces.Has(pod.Namespace) {
+		return false
+	}
+	return no.allowedNamespaces.Len() == 0 || no.allowedNamespaces.Has(pod.Namespace)
+}
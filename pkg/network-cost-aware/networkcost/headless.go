@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkcost
+
+import (
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+)
+
+// DependencyOrdinalsAnnotation : annotation of a pod naming the replica it talks to for its dependencies served by a
+// headless Service, e.g. a partition of a stateful database, as a list of <workload selector>=<ordinal>, e.g. "P1=2,P3=0"
+const DependencyOrdinalsAnnotation = agv1alpha1.AppGroupLabel + "/dependency-ordinals"
+
+// getDependencyOrdinals : return the ordinals of the dependency replicas named by the pod annotation, per workload selector
+func getDependencyOrdinals(logger klog.Logger, pod *corev1.Pod) map[string]int {
+	value, ok := pod.Annotations[DependencyOrdinalsAnnotation]
+	if !ok {
+		return nil
+	}
+	ordinals := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		selector, ordinal, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			logger.V(4).Info("Ignoring invalid dependency ordinal", "pod", klog.KObj(pod), "entry", entry)
+			continue
+		}
+		i, err := strconv.Atoi(ordinal)
+		if err != nil || i < 0 {
+			logger.V(4).Info("Ignoring invalid dependency ordinal", "pod", klog.KObj(pod), "entry", entry)
+			continue
+		}
+		ordinals[selector] = i
+	}
+	return ordinals
+}
+
+// getPodOrdinal : return the ordinal of a StatefulSet pod, from its pod index label or else its name
+func getPodOrdinal(pod *corev1.Pod) (int, bool) {
+	index, ok := pod.Labels[appsv1.PodIndexLabel]
+	if !ok {
+		i := strings.LastIndex(pod.Name, "-")
+		if i < 0 {
+			return 0, false
+		}
+		index = pod.Name[i+1:]
+	}
+	ordinal, err := strconv.Atoi(index)
+	if err != nil || ordinal < 0 {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+// pinHeadlessDependencies : keep, for the dependencies served by a headless Service for which the pod names an ordinal,
+// only the replica with this ordinal in the scheduled list, since the pod talks to this replica rather than to the
+// whole workload
+func (no *NetworkCostAware) pinHeadlessDependencies(
+	logger klog.Logger,
+	pod *corev1.Pod,
+	pods []*corev1.Pod,
+	dependencyList []agv1alpha1.DependenciesInfo,
+	scheduledList networkcostawareutil.ScheduledList) networkcostawareutil.ScheduledList {
+	ordinals := getDependencyOrdinals(logger, pod)
+	if len(ordinals) == 0 || no.serviceLister == nil {
+		return scheduledList
+	}
+
+	// The Service is looked up once, from any replica of the dependency
+	pinned := make(map[string]int)
+	for _, d := range dependencyList {
+		ordinal, ok := ordinals[d.Workload.Selector]
+		if !ok {
+			continue
+		}
+		for _, p := range pods {
+			if networkcostawareutil.GetPodAppGroupSelector(p) != d.Workload.Selector {
+				continue
+			}
+			if no.hasService(logger, p, headless) {
+				pinned[d.Workload.Selector] = ordinal
+			}
+			break
+		}
+	}
+	if len(pinned) == 0 {
+		return scheduledList
+	}
+	logger.V(6).Info("Dependencies served by a headless Service", "pod", klog.KObj(pod), "ordinals", pinned)
+
+	podsByUID := make(map[string]*corev1.Pod, len(pods))
+	for _, p := range pods {
+		podsByUID[string(p.UID)] = p
+	}
+	kept := make(networkcostawareutil.ScheduledList, 0, len(scheduledList))
+	for _, s := range scheduledList {
+		if ordinal, ok := pinned[s.Selector]; ok {
+			p, found := podsByUID[s.ReplicaID]
+			if !found {
+				continue
+			}
+			if o, ok := getPodOrdinal(p); !ok || o != ordinal {
+				continue
+			}
+		}
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+// headless : whether the Service is headless, resolving to the individual pods it selects
+func headless(svc *corev1.Service) bool {
+	return svc.Spec.ClusterIP == corev1.ClusterIPNone
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkcost
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+)
+
+const (
+	// labels of the origin and destination of the samples of the measured costs
+	originLabel      = "origin"
+	destinationLabel = "destination"
+)
+
+// measuredTopologyKeys : the topology keys whose costs are measured
+var measuredTopologyKeys = []ntv1alpha1.TopologyKey{ntv1alpha1.NetworkTopologyRegion, ntv1alpha1.NetworkTopologyZone}
+
+// MeasuredCostsCollector : get the costs of the links between the regions and between the zones,
+// e.g. their latency, measured by a Prometheus server
+type MeasuredCostsCollector struct {
+	prometheus *util.PrometheusClient
+	// query per topology key
+	queries map[ntv1alpha1.TopologyKey]string
+
+	// measured costs by origin, then destination
+	costs map[string]map[string]float64
+	// for safe access to costs
+	mu sync.RWMutex
+}
+
+// NewMeasuredCostsCollector : create a collector of the costs returned by the query template, executed for each topology key
+func NewMeasuredCostsCollector(address string, queryTemplate string) (*MeasuredCostsCollector, error) {
+	if address == "" {
+		return nil, fmt.Errorf("MetricsProviderAddress is required")
+	}
+	tmpl, err := template.New("query").Parse(queryTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MetricsQuery: %w", err)
+	}
+	queries := make(map[ntv1alpha1.TopologyKey]string, len(measuredTopologyKeys))
+	for _, key := range measuredTopologyKeys {
+		var query strings.Builder
+		if err := tmpl.Execute(&query, struct{ TopologyKey string }{string(key)}); err != nil {
+			return nil, fmt.Errorf("invalid MetricsQuery: %w", err)
+		}
+		queries[key] = query.String()
+	}
+	return &MeasuredCostsCollector{
+		prometheus: util.NewPrometheusClient(address),
+		queries:    queries,
+		costs:      make(map[string]map[string]float64),
+	}, nil
+}
+
+// Run : refresh the measured costs every interval until the context is done
+func (c *MeasuredCostsCollector) Run(ctx context.Context, interval time.Duration) {
+	logger := klog.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.UpdateCosts(ctx); err != nil {
+			logger.Error(err, "Unable to update the measured network costs")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// UpdateCosts : query Prometheus for each topology key and replace the measured costs with the result.
+// The costs are kept as they are if any query fails.
+func (c *MeasuredCostsCollector) UpdateCosts(ctx context.Context) error {
+	costs := make(map[string]map[string]float64)
+	for _, key := range measuredTopologyKeys {
+		if err := c.query(ctx, c.queries[key], costs); err != nil {
+			return err
+		}
+	}
+	c.mu.Lock()
+	c.costs = costs
+	c.mu.Unlock()
+	return nil
+}
+
+// query : run the query and add the costs of its result
+func (c *MeasuredCostsCollector) query(ctx context.Context, query string, costs map[string]map[string]float64) error {
+	samples, err := c.prometheus.QueryVector(ctx, query)
+	if err != nil {
+		return err
+	}
+	for _, sample := range samples {
+		origin := sample.Labels[originLabel]
+		destination := sample.Labels[destinationLabel]
+		if origin == "" || destination == "" || sample.Value < 0 {
+			continue
+		}
+		if costs[origin] == nil {
+			costs[origin] = make(map[string]float64)
+		}
+		costs[origin][destination] = sample.Value
+	}
+	return nil
+}
+
+// Blend : blend the costs measured from the origins, i.e. the region and zone of a node, with the costs of
+// the NetworkTopology CR in the cost map. weight is the weight in percent of the measured costs.
+// The measured costs are used as they are for the destinations missing in the NetworkTopology CR.
+func (c *MeasuredCostsCollector) Blend(costMap map[networkcostawareutil.CostKey]int64, weight int64, origins ...string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, origin := range origins {
+		if origin == "" {
+			continue
+		}
+		for destination, measured := range c.costs[origin] {
+			key := networkcostawareutil.CostKey{Origin: origin, Destination: destination}
+			cost, ok := costMap[key]
+			if !ok {
+				costMap[key] = int64(math.Round(measured))
+				continue
+			}
+			costMap[key] = int64(math.Round((float64(weight)*measured + float64(100-weight)*float64(cost)) / 100))
+		}
+	}
+}
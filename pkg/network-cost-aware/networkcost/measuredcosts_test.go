@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkcost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
+)
+
+const testQueryTemplate = `link_latency{key="{{.TopologyKey}}"}`
+
+func newTestPrometheus(t *testing.T, responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			t.Errorf("unexpected path %v", r.URL.Path)
+		}
+		response, ok := responses[r.URL.Query().Get("query")]
+		if !ok {
+			t.Errorf("unexpected query %v", r.URL.Query().Get("query"))
+		}
+		fmt.Fprint(w, response)
+	}))
+}
+
+func TestMeasuredCostsCollector(t *testing.T) {
+	server := newTestPrometheus(t, map[string]string{
+		`link_latency{key="topology.kubernetes.io/region"}`: `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"origin":"us-west-1","destination":"us-east-1"},"value":[1710000000.1,"70.4"]}]}}`,
+		`link_latency{key="topology.kubernetes.io/zone"}`: `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"origin":"z1","destination":"z2"},"value":[1710000000.1,"6"]},
+			{"metric":{"origin":"z1","destination":"z3"},"value":[1710000000.1,"NaN"]},
+			{"metric":{"origin":"z2","destination":"z1"},"value":[1710000000.1,"7"]},
+			{"metric":{"destination":"z1"},"value":[1710000000.1,"1"]}]}}`,
+	})
+	defer server.Close()
+
+	c, err := NewMeasuredCostsCollector(server.URL+"/", testQueryTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateCosts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]float64{
+		"us-west-1": {"us-east-1": 70.4},
+		"z1":        {"z2": 6},
+		"z2":        {"z1": 7},
+	}
+	if !reflect.DeepEqual(c.costs, want) {
+		t.Errorf("expected the measured costs %v, got %v", want, c.costs)
+	}
+
+	tests := []struct {
+		name    string
+		weight  int64
+		costMap map[networkcostawareutil.CostKey]int64
+		want    map[networkcostawareutil.CostKey]int64
+	}{
+		{
+			name:   "measured and NetworkTopology costs weigh the same",
+			weight: 50,
+			costMap: map[networkcostawareutil.CostKey]int64{
+				{Origin: "us-west-1", Destination: "us-east-1"}: 50,
+				{Origin: "z1", Destination: "z2"}:               10,
+				{Origin: "z1", Destination: "z3"}:               20,
+			},
+			want: map[networkcostawareutil.CostKey]int64{
+				{Origin: "us-west-1", Destination: "us-east-1"}: 60,
+				{Origin: "z1", Destination: "z2"}:               8,
+				{Origin: "z1", Destination: "z3"}:               20,
+			},
+		},
+		{
+			name:   "only measured costs",
+			weight: 100,
+			costMap: map[networkcostawareutil.CostKey]int64{
+				{Origin: "z1", Destination: "z2"}: 10,
+			},
+			want: map[networkcostawareutil.CostKey]int64{
+				{Origin: "us-west-1", Destination: "us-east-1"}: 70,
+				{Origin: "z1", Destination: "z2"}:               6,
+			},
+		},
+		{
+			name:    "destinations missing in the NetworkTopology",
+			weight:  0,
+			costMap: map[networkcostawareutil.CostKey]int64{},
+			want: map[networkcostawareutil.CostKey]int64{
+				{Origin: "us-west-1", Destination: "us-east-1"}: 70,
+				{Origin: "z1", Destination: "z2"}:               6,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.Blend(tt.costMap, tt.weight, "us-west-1", "z1")
+			if !reflect.DeepEqual(tt.costMap, tt.want) {
+				t.Errorf("expected the costs %v, got %v", tt.want, tt.costMap)
+			}
+		})
+	}
+}
+
+func TestMeasuredCostsCollectorQueryError(t *testing.T) {
+	server := newTestPrometheus(t, map[string]string{
+		`link_latency{key="topology.kubernetes.io/region"}`: `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"origin":"us-west-1","destination":"us-east-1"},"value":[1710000000.1,"70"]}]}}`,
+		`link_latency{key="topology.kubernetes.io/zone"}`: `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+	})
+	defer server.Close()
+
+	c, err := NewMeasuredCostsCollector(server.URL, testQueryTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.costs = map[string]map[string]float64{"z1": {"z2": 6}}
+	if err := c.UpdateCosts(context.Background()); err == nil {
+		t.Errorf("expected an error")
+	}
+	if want := map[string]map[string]float64{"z1": {"z2": 6}}; !reflect.DeepEqual(c.costs, want) {
+		t.Errorf("expected the measured costs to be kept, got %v", c.costs)
+	}
+}
+
+func TestNewMeasuredCostsCollector(t *testing.T) {
+	if _, err := NewMeasuredCostsCollector("", testQueryTemplate); err == nil {
+		t.Errorf("expected an error without address")
+	}
+	if _, err := NewMeasuredCostsCollector("http://prometheus:9090", "{{.TopologyKey"); err == nil {
+		t.Errorf("expected an error for an invalid template")
+	}
+}
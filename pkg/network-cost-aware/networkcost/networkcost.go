@@ -19,14 +19,19 @@ package networkcost
 import (
 	"context"
 	"fmt"
+	"maps"
 	"math"
+	"slices"
 	"sort"
 	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
@@ -37,6 +42,7 @@ import (
 
 	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
 
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/multicluster"
 	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
 
 	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
@@ -46,12 +52,13 @@ import (
 var _ framework.PreFilterPlugin = &NetworkCostAware{}
 var _ framework.FilterPlugin = &NetworkCostAware{}
 var _ framework.ScorePlugin = &NetworkCostAware{}
+var _ framework.EnqueueExtensions = &NetworkCostAware{}
 
 const (
 	// Name : name of plugin used in the plugin registry and configurations.
 	Name = "NetworkCostAware"
 
-	// MaxCost : MaxCost used in the NetworkTopology for costs between origins and destinations
+	// MaxCost : MaxCost used in the NetworkTopology for costs between origins and destinations, unless configured
 	MaxCost = 100
 
 	// SameHostname : If pods belong to the same host, then consider cost as 0
@@ -60,9 +67,23 @@ const (
 	// SameZone : If pods belong to hosts in the same zone, then consider cost as 1
 	SameZone = 1
 
+	// Network hops to a replica of a dependency with the HopCount scoring mode: on the same host, in the same zone,
+	// in another zone of the same region, in another region, in another cluster or without topology labels
+	HopsSameHostname = 0
+	HopsSameZone     = 1
+	HopsOtherZone    = 2
+	HopsOtherRegion  = 3
+	HopsOtherCluster = 4
+
+	// ErrReasonUnlabeledNode : reason of the nodes without zone and region labels filtered out with the Filter policy
+	ErrReasonUnlabeledNode = "node(s) without zone and region labels"
+
 	// preFilterStateKey is the key in CycleState to NetworkCostAware pre-computed data.
 	preFilterStateKey = "PreFilter" + Name
 
+	// logCostResolution : logarithmically scaled costs keep 3 decimals before normalization
+	logCostResolution = 1000
+
 	// ResourceCostAnnotation defines the annotation key for resource usage cost
     ResourceCostAnnotation = "node.kubernetes.io/resource-cost"  
 )
@@ -79,12 +100,91 @@ func init() {
 // NetworkCostAware : Filter and Score nodes based on Pod's AppGroup requirements: MaxNetworkCosts requirements among Pods with dependencies + cost of nodes
 type NetworkCostAware struct {
 	client.Client
+	// requeue the rejected pods of an AppGroup on the changes of their AppGroup and of its placement
+	networkcostawareutil.AppGroupEnqueueExtensions
+
+	podLister     corelisters.PodLister
+	serviceLister corelisters.ServiceLister
+	handle        framework.Handle
+	namespaces    []string
+	weightsName   string
+	ntName        string
+	clusterName   string
+
+	dependencyCostMode pluginconfig.DependencyCostMode
+	costCap            int64
+	costScaling        pluginconfig.CostScalingMode
+	scoringMode        pluginconfig.NetworkCostScoringMode
+
+	// how the nodes without zone and region labels are handled
+	unlabeledNodePolicy pluginconfig.UnlabeledNodePolicy
+
+	// costs measured by the metrics provider, nil if not configured
+	measuredCosts      *MeasuredCostsCollector
+	measuredCostWeight int64
+
+	// configured costs to the replicas of the dependencies, nil for the default ones
+	costWeights *costWeights
+
+	// namespaces of the pods the plugin applies to, all if empty, and doesn't apply to
+	allowedNamespaces sets.Set[string]
+	deniedNamespaces  sets.Set[string]
+}
+
+// costWeights : the costs to the replicas of the dependencies on the same host, in the same zone or whose cost is not
+// defined, and the multipliers of the costs to the replicas in other zones, regions and clusters
+type costWeights struct {
+	maxCost           int64
+	sameZone          int64
+	sameHostname      int64
+	zoneMultiplier    int64
+	regionMultiplier  int64
+	clusterMultiplier int64
+}
+
+var defaultCostWeights = &costWeights{
+	maxCost:           MaxCost,
+	sameZone:          SameZone,
+	sameHostname:      SameHostname,
+	zoneMultiplier:    1,
+	regionMultiplier:  1,
+	clusterMultiplier: 1,
+}
+
+// newCostWeights : the cost weights of the args, the default ones for the unset costs and multipliers
+func newCostWeights(args *pluginconfig.NetworkCostArgs) (*costWeights, error) {
+	if args.MaxCost < 0 || args.SameZoneCost < 0 || args.SameHostnameCost < 0 {
+		return nil, fmt.Errorf("maxCost, sameZoneCost and sameHostnameCost should not be negative, got %d, %d and %d",
+			args.MaxCost, args.SameZoneCost, args.SameHostnameCost)
+	}
+	if args.ZoneCostMultiplier < 0 || args.RegionCostMultiplier < 0 || args.ClusterCostMultiplier < 0 {
+		return nil, fmt.Errorf("zoneCostMultiplier, regionCostMultiplier and clusterCostMultiplier should not be negative, got %d, %d and %d",
+			args.ZoneCostMultiplier, args.RegionCostMultiplier, args.ClusterCostMultiplier)
+	}
+	w := *defaultCostWeights
+	if args.MaxCost > 0 {
+		w.maxCost = args.MaxCost
+	}
+	w.sameZone = args.SameZoneCost
+	w.sameHostname = args.SameHostnameCost
+	if args.ZoneCostMultiplier > 0 {
+		w.zoneMultiplier = args.ZoneCostMultiplier
+	}
+	if args.RegionCostMultiplier > 0 {
+		w.regionMultiplier = args.RegionCostMultiplier
+	}
+	if args.ClusterCostMultiplier > 0 {
+		w.clusterMultiplier = args.ClusterCostMultiplier
+	}
+	return &w, nil
+}
 
-	podLister   corelisters.PodLister
-	handle      framework.Handle
-	namespaces  []string
-	weightsName string
-	ntName      string
+// weights : the configured cost weights, the default ones if not configured
+func (no *NetworkCostAware) weights() *costWeights {
+	if no.costWeights == nil {
+		return defaultCostWeights
+	}
+	return no.costWeights
 }
 
 // PreFilterState computed at PreFilter and used at Filter and Score.
@@ -110,6 +210,12 @@ type PreFilterState struct {
 	// node map for cost / destinations. Search for requirements faster...
 	nodeCostMap map[string]map[networkcostawareutil.CostKey]int64
 
+	// costs between clusters, for nodes of remote clusters
+	clusterCosts multicluster.Costs
+
+	// zones hosting the replicas of the dependencies served with topology-aware routing
+	hintZones map[string]sets.Set[topologyZone]
+
 	// node map for satisfied dependencies
 	satisfiedMap map[string]int64
 
@@ -122,11 +228,23 @@ type PreFilterState struct {
 
 	// Add a map to store resource costs per node
 	nodeResourceCostMap map[string]int64  //amira 
+
+	// explanation of the conflict with the topologySpreadConstraints of the pod, empty if none
+	spreadConflict string
+
+	// nodes without zone and region labels, ignored or filtered out per the unlabeledNodePolicy
+	unlabeledNodes sets.Set[string]
 }
 
-// Clone the preFilter state.
+// Clone the preFilter state. The scheduled list and the maps of the nodes are copied, since AddPod and RemovePod
+// update them, e.g. during the preemption simulations on the copies of the state.
 func (no *PreFilterState) Clone() framework.StateData {
-	return no
+	c := *no
+	c.scheduledList = slices.Clone(no.scheduledList)
+	c.satisfiedMap = maps.Clone(no.satisfiedMap)
+	c.violatedMap = maps.Clone(no.violatedMap)
+	c.finalCostMap = maps.Clone(no.finalCostMap)
+	return &c
 }
 
 // Name : returns name of the plugin.
@@ -165,21 +283,93 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if err != nil {
 		return nil, err
 	}
+	dependencyCostMode := args.DependencyCostMode
+	switch dependencyCostMode {
+	case "":
+		dependencyCostMode = pluginconfig.DependencyCostSum
+	case pluginconfig.DependencyCostSum, pluginconfig.DependencyCostNearestReplica:
+	default:
+		return nil, fmt.Errorf("unknown dependencyCostMode %q", dependencyCostMode)
+	}
+	costScaling := args.CostScaling
+	switch costScaling {
+	case "":
+		costScaling = pluginconfig.CostScalingLinear
+	case pluginconfig.CostScalingLinear, pluginconfig.CostScalingLogarithmic:
+	default:
+		return nil, fmt.Errorf("unknown costScaling %q", costScaling)
+	}
+	scoringMode := args.ScoringMode
+	switch scoringMode {
+	case "":
+		scoringMode = pluginconfig.NetworkCostScoringLatency
+	case pluginconfig.NetworkCostScoringHopCount, pluginconfig.NetworkCostScoringLatency, pluginconfig.NetworkCostScoringTrafficWeighted:
+	default:
+		return nil, fmt.Errorf("unknown scoringMode %q", scoringMode)
+	}
+	unlabeledNodePolicy := args.UnlabeledNodePolicy
+	switch unlabeledNodePolicy {
+	case "":
+		unlabeledNodePolicy = pluginconfig.UnlabeledNodePenalize
+	case pluginconfig.UnlabeledNodeIgnore, pluginconfig.UnlabeledNodePenalize, pluginconfig.UnlabeledNodeFilter:
+	default:
+		return nil, fmt.Errorf("unknown unlabeledNodePolicy %q", unlabeledNodePolicy)
+	}
+	if args.CostCap < 0 {
+		return nil, fmt.Errorf("costCap should not be negative, got %d", args.CostCap)
+	}
+	costWeights, err := newCostWeights(args)
+	if err != nil {
+		return nil, err
+	}
+	var measuredCosts *MeasuredCostsCollector
+	if args.MetricsProviderAddress != "" {
+		if args.MetricsRefreshIntervalSeconds <= 0 {
+			return nil, fmt.Errorf("invalid metricsRefreshIntervalSeconds, want a positive value, got %d", args.MetricsRefreshIntervalSeconds)
+		}
+		if args.MeasuredCostWeight < 0 || args.MeasuredCostWeight > 100 {
+			return nil, fmt.Errorf("measuredCostWeight should be within [0, 100], got %d", args.MeasuredCostWeight)
+		}
+		measuredCosts, err = NewMeasuredCostsCollector(args.MetricsProviderAddress, args.MetricsQuery)
+		if err != nil {
+			return nil, err
+		}
+	}
 	client, err := client.New(handle.KubeConfig(), client.Options{
 		Scheme: scheme,
 	})
 	if err != nil {
 		return nil, err
 	}
+	if measuredCosts != nil {
+		go measuredCosts.Run(ctx, time.Duration(args.MetricsRefreshIntervalSeconds)*time.Second)
+	}
 
 	no := &NetworkCostAware{
 		Client: client,
 
-		podLister:   handle.SharedInformerFactory().Core().V1().Pods().Lister(),
-		handle:      handle,
-		namespaces:  args.Namespaces,
-		weightsName: args.WeightsName,
-		ntName:      args.NetworkTopologyName,
+		podLister:     handle.SharedInformerFactory().Core().V1().Pods().Lister(),
+		serviceLister: handle.SharedInformerFactory().Core().V1().Services().Lister(),
+		handle:        handle,
+		namespaces:    args.Namespaces,
+		weightsName:   args.WeightsName,
+		ntName:        args.NetworkTopologyName,
+		clusterName:   args.ClusterName,
+
+		dependencyCostMode: dependencyCostMode,
+		costCap:            args.CostCap,
+		costScaling:        costScaling,
+		scoringMode:        scoringMode,
+
+		unlabeledNodePolicy: unlabeledNodePolicy,
+
+		measuredCosts:      measuredCosts,
+		measuredCostWeight: args.MeasuredCostWeight,
+
+		costWeights: costWeights,
+
+		allowedNamespaces: sets.New(args.AllowedNamespaces...),
+		deniedNamespaces:  sets.New(args.DeniedNamespaces...),
 	}
 	return no, nil
 }
@@ -201,6 +391,11 @@ func (no *NetworkCostAware) PreFilter(ctx context.Context, state *framework.Cycl
 	// Write initial status
 	state.Write(preFilterStateKey, preFilterState)
 
+	// Check if the plugin applies to the Pod
+	if !no.enabledFor(pod) {
+		return nil, framework.NewStatus(framework.Success, "Pod opted out of the plugin, return")
+	}
+
 	// Check if Pod belongs to an AppGroup
 	agName := networkcostawareutil.GetPodAppGroupLabel(pod)
 	if len(agName) == 0 { // Return
@@ -238,6 +433,8 @@ func (no *NetworkCostAware) PreFilter(ctx context.Context, state *framework.Cycl
 
 	// Pods already scheduled: Get Scheduled List (Deployment name, replicaID, hostname)
 	scheduledList := networkcostawareutil.GetScheduledList(pods)
+	// Dependencies served by a headless Service: only the replica named by the pod counts
+	scheduledList = no.pinHeadlessDependencies(logger, pod, pods, dependencyList, scheduledList)
 	// Check if scheduledList is empty...
 	if len(scheduledList) == 0 {
 		logger.Error(nil, "Scheduled list is empty, return")
@@ -251,56 +448,50 @@ func (no *NetworkCostAware) PreFilter(ctx context.Context, state *framework.Cycl
 	}
 
 	// Create variables to fill PreFilterState
+	clusterCosts := multicluster.NewCosts(networkTopology, no.weightsName)
 	nodeCostMap := make(map[string]map[networkcostawareutil.CostKey]int64)
 	satisfiedMap := make(map[string]int64)
 	violatedMap := make(map[string]int64)
 	finalCostMap := make(map[string]int64)
 	nodeResourceCostMap := make(map[string]int64)  //amira 
+	unlabeledNodes := sets.New[string]()
+
+	// Dependencies served by a Service with topology-aware routing, and the zones hosting their replicas
+	hintZones := no.getRoutingHintZones(logger, pods, dependencyList)
+
+	// PreFilter State, updated for each node
+	preFilterState = &PreFilterState{
+		scoreEqually:        false,
+		agName:              agName,
+		appGroup:            appGroup,
+		networkTopology:     networkTopology,
+		dependencyList:      dependencyList,
+		scheduledList:       scheduledList,
+		nodeCostMap:         nodeCostMap,
+		clusterCosts:        clusterCosts,
+		hintZones:           hintZones,
+		satisfiedMap:        satisfiedMap,
+		violatedMap:         violatedMap,
+		finalCostMap:        finalCostMap,
+		nodeResourceCostMap: nodeResourceCostMap, //Amira
+		unlabeledNodes:      unlabeledNodes,
+	}
 
 	// For each node:
 	// 1 - Get region and zone labels
 	// 2 - Calculate satisfied and violated number of dependencies
 	// 3 - Calculate the final cost of the node to be used by the scoring plugin
 	for _, nodeInfo := range nodeList {
-		// retrieve region and zone labels
+		// retrieve cluster, region and zone labels
+		cluster := no.getNodeCluster(nodeInfo.Node())
 		region := networkcostawareutil.GetNodeRegion(nodeInfo.Node())
 		zone := networkcostawareutil.GetNodeZone(nodeInfo.Node())
 		logger.V(6).Info("Node info",
 			"name", nodeInfo.Node().Name,
+			"cluster", cluster,
 			"region", region,
 			"zone", zone)
 
-		// Create map for cost / destinations. Search for requirements faster...
-		costMap := make(map[networkcostawareutil.CostKey]int64)
-
-		// Populate cost map for the given node
-		no.populateCostMap(costMap, networkTopology, region, zone)
-		logger.V(6).Info("Map", "costMap", costMap)
-
-		// Update nodeCostMap
-		nodeCostMap[nodeInfo.Node().Name] = costMap
-
-		// Get Satisfied and Violated number of dependencies
-		satisfied, violated, ok := checkMaxNetworkCostRequirements(logger, scheduledList, dependencyList, nodeInfo, region, zone, costMap, no)
-		if ok != nil {
-			return nil, framework.NewStatus(framework.Error, fmt.Sprintf("pod hostname not found: %v", ok))
-		}
-
-		// Update Satisfied and Violated maps
-		satisfiedMap[nodeInfo.Node().Name] = satisfied
-		violatedMap[nodeInfo.Node().Name] = violated
-		logger.V(6).Info("Number of dependencies", "satisfied", satisfied, "violated", violated)
-
-		// Get accumulated cost based on pod dependencies
-		cost, ok := no.getAccumulatedCost(logger, scheduledList, dependencyList, nodeInfo.Node().Name, region, zone, costMap)
-		if ok != nil {
-			return nil, framework.NewStatus(framework.Error, fmt.Sprintf("getting pod hostname from Snapshot: %v", ok))
-		}
-		logger.V(6).Info("Node final cost", "cost", cost)
-		finalCostMap[nodeInfo.Node().Name] = cost
-
-
-
 		//Amira
 		 // retrieve resource usage cost from annotations
 		 cpuCost, cpuFound := nodeInfo.Node().Annotations["resourceCost.cpu"]
@@ -321,23 +512,54 @@ func (no *NetworkCostAware) PreFilter(ctx context.Context, state *framework.Cycl
 				 nodeResourceCostMap[nodeInfo.Node().Name] += cost
 			 }
 		 }
+
+		// Nodes without zone and region labels are left out of the network costs, unless penalized
+		if no.unlabeledNodePolicy != pluginconfig.UnlabeledNodePenalize && region == "" && zone == "" {
+			unlabeledNodes.Insert(nodeInfo.Node().Name)
+			continue
+		}
+
+		// Create map for cost / destinations. Search for requirements faster...
+		costMap := make(map[networkcostawareutil.CostKey]int64)
+
+		// Populate cost map for the given node
+		no.populateCostMap(costMap, networkTopology, region, zone, networkcostawareutil.GetNodeLocation(nodeInfo.Node()))
+
+		// Blend the costs measured from the region and zone of the node with the NetworkTopology ones
+		if no.measuredCosts != nil {
+			no.measuredCosts.Blend(costMap, no.measuredCostWeight, region, zone)
+		}
+		logger.V(6).Info("Map", "costMap", costMap)
+
+		// Update nodeCostMap
+		nodeCostMap[nodeInfo.Node().Name] = costMap
+
+		// Update Satisfied and Violated maps and the final cost of the node
+		if err := no.updateNodeCosts(logger, preFilterState, nodeInfo); err != nil {
+			return nil, framework.NewStatus(framework.Error, err.Error())
+		}
 	}
 
-	// Update PreFilter State
-	preFilterState = &PreFilterState{
-		scoreEqually:    false,
-		agName:          agName,
-		appGroup:        appGroup,
-		networkTopology: networkTopology,
-		dependencyList:  dependencyList,
-		scheduledList:   scheduledList,
-		nodeCostMap:     nodeCostMap,
-		satisfiedMap:    satisfiedMap,
-		violatedMap:     violatedMap,
-		finalCostMap:    finalCostMap,
-		nodeResourceCostMap: nodeResourceCostMap, //Amira
+	// Ignored nodes score the average cost of the labeled nodes, neither favored nor penalized
+	if no.unlabeledNodePolicy == pluginconfig.UnlabeledNodeIgnore && unlabeledNodes.Len() > 0 {
+		average := averageCost(finalCostMap)
+		for name := range unlabeledNodes {
+			finalCostMap[name] = average
+		}
 	}
 
+	// Check if the topologySpreadConstraints of the pod rule out all the nodes meeting the requirements
+	spreadConflict := getSpreadConflict(logger, pod, nodeList, satisfiedMap, violatedMap)
+	if spreadConflict != "" {
+		logger.V(4).Info("Network cost requirements conflict with topologySpreadConstraints", "pod", klog.KObj(pod), "conflict", spreadConflict)
+		if recorder := no.handle.EventRecorder(); recorder != nil {
+			recorder.Eventf(pod, nil, corev1.EventTypeWarning, SpreadConflict, "Scheduling", "Pod can't be scheduled: %v", spreadConflict)
+		}
+	}
+
+	// Update PreFilter State
+	preFilterState.spreadConflict = spreadConflict
+
 	state.Write(preFilterStateKey, preFilterState)
 	return nil, framework.NewStatus(framework.Success, "PreFilter State updated")
 }
@@ -347,24 +569,118 @@ func (no *NetworkCostAware) PreFilterExtensions() framework.PreFilterExtensions
 	return no
 }
 
-// AddPod from pre-computed data in cycleState.
-// no current need for the NetworkCostAware plugin
+// AddPod from pre-computed data in cycleState: a dependency of the pod hypothetically added on the node, e.g. a
+// nominated pod, is added to the scheduled list and the costs of the node are updated. The framework only evaluates
+// the given node with the updated state, so the costs of the other nodes are left as they are.
 func (no *NetworkCostAware) AddPod(ctx context.Context,
 	cycleState *framework.CycleState,
 	podToSchedule *corev1.Pod,
 	podToAdd *framework.PodInfo,
 	nodeInfo *framework.NodeInfo) *framework.Status {
-	return framework.NewStatus(framework.Success, "")
+	preFilterState, err := getPreFilterState(cycleState)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if preFilterState.scoreEqually || nodeInfo.Node() == nil {
+		return nil
+	}
+	logger := klog.FromContext(ctx)
+
+	pod := podToAdd.Pod
+	if networkcostawareutil.GetPodAppGroupLabel(pod) != preFilterState.agName ||
+		slices.ContainsFunc(preFilterState.scheduledList, func(s networkcostawareutil.ScheduledInfo) bool {
+			return s.ReplicaID == string(pod.UID)
+		}) {
+		return nil
+	}
+	added := networkcostawareutil.ScheduledList{{
+		Name:      pod.Name,
+		Selector:  networkcostawareutil.GetPodAppGroupSelector(pod),
+		ReplicaID: string(pod.UID),
+		Hostname:  nodeInfo.Node().Name,
+	}}
+	// Dependencies served by a headless Service: only the replica named by the pod counts
+	added = no.pinHeadlessDependencies(logger, podToSchedule, []*corev1.Pod{pod}, preFilterState.dependencyList, added)
+	if len(added) == 0 || !isDependency(preFilterState.dependencyList, added[0].Selector) {
+		return nil
+	}
+	preFilterState.scheduledList = append(preFilterState.scheduledList, added...)
+	if err := no.updateNodeCosts(logger, preFilterState, nodeInfo); err != nil {
+		return framework.AsStatus(err)
+	}
+	return nil
 }
 
-// RemovePod from pre-computed data in cycleState.
-// no current need for the NetworkCostAware plugin
+// RemovePod from pre-computed data in cycleState: a dependency of the pod hypothetically removed from the node, e.g. a
+// victim of the preemption, is removed from the scheduled list and the costs of the node are updated. The framework only
+// evaluates the given node with the updated state, so the costs of the other nodes are left as they are.
 func (no *NetworkCostAware) RemovePod(ctx context.Context,
 	cycleState *framework.CycleState,
 	podToSchedule *corev1.Pod,
 	podToRemove *framework.PodInfo,
 	nodeInfo *framework.NodeInfo) *framework.Status {
-	return framework.NewStatus(framework.Success, "")
+	preFilterState, err := getPreFilterState(cycleState)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if preFilterState.scoreEqually || nodeInfo.Node() == nil {
+		return nil
+	}
+	logger := klog.FromContext(ctx)
+
+	uid := string(podToRemove.Pod.UID)
+	scheduledList := slices.DeleteFunc(slices.Clone(preFilterState.scheduledList), func(s networkcostawareutil.ScheduledInfo) bool {
+		return s.ReplicaID == uid
+	})
+	if len(scheduledList) == len(preFilterState.scheduledList) {
+		return nil
+	}
+	preFilterState.scheduledList = scheduledList
+	if err := no.updateNodeCosts(logger, preFilterState, nodeInfo); err != nil {
+		return framework.AsStatus(err)
+	}
+	return nil
+}
+
+// updateNodeCosts : update the number of satisfied and violated dependencies and the final cost of the node from the
+// scheduled list of the state. The nodes without zone and region labels left out of the network costs are not updated.
+func (no *NetworkCostAware) updateNodeCosts(logger klog.Logger, state *PreFilterState, nodeInfo *framework.NodeInfo) error {
+	name := nodeInfo.Node().Name
+	costMap, ok := state.nodeCostMap[name]
+	if !ok {
+		return nil
+	}
+	cluster := no.getNodeCluster(nodeInfo.Node())
+	region := networkcostawareutil.GetNodeRegion(nodeInfo.Node())
+	zone := networkcostawareutil.GetNodeZone(nodeInfo.Node())
+
+	// Dependencies whose traffic stays in the zone of the node due to routing hints
+	zoneLocal := zoneLocalDependencies(state.hintZones, cluster, zone)
+
+	// Get Satisfied and Violated number of dependencies
+	satisfied, violated, err := checkMaxNetworkCostRequirements(logger, state.scheduledList, state.dependencyList, nodeInfo, cluster, region, zone, costMap, state.clusterCosts, zoneLocal, no)
+	if err != nil {
+		return fmt.Errorf("pod hostname not found: %v", err)
+	}
+	state.satisfiedMap[name] = satisfied
+	state.violatedMap[name] = violated
+	logger.V(6).Info("Number of dependencies", "satisfied", satisfied, "violated", violated)
+
+	// Get accumulated cost based on pod dependencies
+	cost, err := no.getAccumulatedCost(logger, state.scheduledList, state.dependencyList, name, cluster, region, zone, costMap, state.clusterCosts, zoneLocal)
+	if err != nil {
+		return fmt.Errorf("getting pod hostname from Snapshot: %v", err)
+	}
+	logger.V(6).Info("Node final cost", "cost", cost)
+	state.finalCostMap[name] = cost
+	return nil
+}
+
+// isDependency : whether the workload selector is one of the dependencies
+func isDependency(dependencyList []agv1alpha1.DependenciesInfo, selector string) bool {
+	return slices.ContainsFunc(dependencyList, func(d agv1alpha1.DependenciesInfo) bool {
+		return d.Workload.Selector == selector
+	})
 }
 
 // Filter : evaluate if node can respect maxNetworkCost requirements
@@ -390,6 +706,11 @@ func (no *NetworkCostAware) Filter(ctx context.Context,
 		return nil
 	}
 
+	// Nodes without zone and region labels are filtered out with the Filter policy
+	if no.unlabeledNodePolicy == pluginconfig.UnlabeledNodeFilter && preFilterState.unlabeledNodes.Has(nodeInfo.Node().Name) {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonUnlabeledNode)
+	}
+
 	// Get satisfied and violated number of dependencies
 	satisfied := preFilterState.satisfiedMap[nodeInfo.Node().Name]
 	violated := preFilterState.violatedMap[nodeInfo.Node().Name]
@@ -397,8 +718,12 @@ func (no *NetworkCostAware) Filter(ctx context.Context,
 
 	// The pod is filtered out if the number of violated dependencies is higher than the satisfied ones
 	if violated > satisfied {
-		return framework.NewStatus(framework.Unschedulable,
-			fmt.Sprintf("Node %v does not meet several network requirements from Workload dependencies: Satisfied: %v Violated: %v", nodeInfo.Node().Name, satisfied, violated))
+		msg := fmt.Sprintf("Node %v does not meet several network requirements from Workload dependencies: Satisfied: %v Violated: %v", nodeInfo.Node().Name, satisfied, violated)
+		// Correlate with the rejections of the PodTopologySpread plugin
+		if preFilterState.spreadConflict != "" {
+			msg = fmt.Sprintf("%v; %v", msg, preFilterState.spreadConflict)
+		}
+		return framework.NewStatus(framework.Unschedulable, msg)
 	}
 	return nil
 }
@@ -432,6 +757,10 @@ func (no *NetworkCostAware) Score(ctx context.Context,
     score += resourceCost
 
 	logger.V(4).Info("Score with resource costs:", "pod", pod.GetName(), "node", nodeName, "finalScore", score)
+
+	// Cap and scale the accumulated cost, so that a few nodes with huge costs don't skew the normalization
+	score = no.scaleCost(score)
+	logger.V(4).Info("Scaled score:", "pod", pod.GetName(), "node", nodeName, "costCap", no.costCap, "costScaling", no.costScaling, "finalScore", score)
 	return score, framework.NewStatus(framework.Success, "Accumulated cost added as score, normalization ensures lower costs are favored")
 }
 
@@ -467,6 +796,29 @@ func (no *NetworkCostAware) NormalizeScore(ctx context.Context,
 	return nil
 }
 
+// scaleCost : cap the accumulated cost to costCap if set, then scale it per costScaling
+func (no *NetworkCostAware) scaleCost(cost int64) int64 {
+	if no.costCap > 0 && cost > no.costCap {
+		cost = no.costCap
+	}
+	if no.costScaling == pluginconfig.CostScalingLogarithmic {
+		return int64(math.Round(logCostResolution * math.Log1p(float64(max(cost, 0)))))
+	}
+	return cost
+}
+
+// averageCost : the average of the costs of the nodes, 0 if none
+func averageCost(costs map[string]int64) int64 {
+	if len(costs) == 0 {
+		return 0
+	}
+	var sum int64
+	for _, cost := range costs {
+		sum += cost
+	}
+	return sum / int64(len(costs))
+}
+
 // MinMax : get min and max scores from NodeScoreList
 func getMinMaxScores(scores framework.NodeScoreList) (int64, int64) {
 	var max int64 = math.MinInt64 // Set to min value
@@ -499,7 +851,8 @@ func (no *NetworkCostAware) populateCostMap(
 	costMap map[networkcostawareutil.CostKey]int64,
 	networkTopology *ntv1alpha1.NetworkTopology,
 	region string,
-	zone string) {
+	zone string,
+	location string) {
 	for _, w := range networkTopology.Spec.Weights { // Check the weights List
 		if w.Name != no.weightsName { // If it is not the Preferred algorithm, continue
 			continue
@@ -543,22 +896,85 @@ func (no *NetworkCostAware) populateCostMap(
 					Destination: c.Destination}] = c.NetworkCost
 			}
 		}
+		if location != "" { // Add Node Costs
+			// Binary search through CostList: find the Topology Key for node
+			topologyList := networkcostawareutil.FindTopologyKey(w.TopologyList, networkcostawareutil.NetworkTopologyNode)
+
+			if no.weightsName != ntv1alpha1.NetworkTopologyNetperfCosts {
+				// Sort Costs by origin, might not be sorted since were manually defined
+				sort.Sort(networkcostawareutil.ByOrigin(topologyList))
+			}
+
+			// Binary search through TopologyList: find the costs for the given node or rack
+			costs := networkcostawareutil.FindOriginCosts(topologyList, location)
+
+			// Add Node Costs
+			for _, c := range costs {
+				costMap[networkcostawareutil.CostKey{ // Add the cost to the map
+					Origin:      location,
+					Destination: c.Destination}] = c.NetworkCost
+			}
+		}
 	}
 }
 
+// nodeLevelCost : the cost between the nodes defined with the node topology key, between their names or racks, the
+// given cost for distinct nodes of the same rack, and false if the NetworkTopology defines no cost between them
+func nodeLevelCost(costMap map[networkcostawareutil.CostKey]int64, node *corev1.Node, podNode *corev1.Node, sameRack int64) (int64, bool) {
+	if node == nil || podNode == nil {
+		return 0, false
+	}
+	origin := networkcostawareutil.GetNodeLocation(node)
+	destination := networkcostawareutil.GetNodeLocation(podNode)
+	if origin == destination { // Distinct nodes of the same rack
+		return sameRack, true
+	}
+	cost, ok := costMap[networkcostawareutil.CostKey{Origin: origin, Destination: destination}]
+	return cost, ok
+}
+
+// nodeLevelHops : the network hops between nodes with a node-level cost, from their regions and zones, if any
+func nodeLevelHops(region, zone, podRegion, podZone string) int64 {
+	switch {
+	case region != podRegion:
+		return HopsOtherRegion
+	case zone != podZone:
+		return HopsOtherZone
+	}
+	return HopsSameZone
+}
+
 // checkMaxNetworkCostRequirements : verifies the number of met and unmet dependencies based on the pod being filtered
 func checkMaxNetworkCostRequirements(
 	logger klog.Logger,
 	scheduledList networkcostawareutil.ScheduledList,
 	dependencyList []agv1alpha1.DependenciesInfo,
 	nodeInfo *framework.NodeInfo,
+	cluster string,
 	region string,
 	zone string,
 	costMap map[networkcostawareutil.CostKey]int64,
+	clusterCosts multicluster.Costs,
+	zoneLocal sets.Set[string],
 	no *NetworkCostAware) (int64, int64, error) {
 	var satisfied int64 = 0
 	var violated int64 = 0
 
+	// With the NearestReplica mode, traffic only goes to the nearest replica of a dependency:
+	// the dependency is satisfied if any of its replicas meets the maxNetworkCost requirement.
+	nearestSatisfied := make(map[string]bool)
+	record := func(d agv1alpha1.DependenciesInfo, ok bool) {
+		if no.dependencyCostMode == pluginconfig.DependencyCostNearestReplica {
+			nearestSatisfied[d.Workload.Selector] = nearestSatisfied[d.Workload.Selector] || ok
+			return
+		}
+		if ok {
+			satisfied += 1
+		} else {
+			violated += 1
+		}
+	}
+
 	// check if maxNetworkCost fits
 	for _, podAllocated := range scheduledList { // For each pod already allocated
 		if podAllocated.Hostname != "" { // if hostname not empty...
@@ -568,9 +984,14 @@ func checkMaxNetworkCostRequirements(
 					continue
 				}
 
+				// Routing hints keep the traffic in the zone of the node, the replicas of other zones get none
+				if zoneLocal.Has(d.Workload.Selector) {
+					continue
+				}
+
 				// If the Pod hostname is the node being filtered, requirements are checked via extended resources
 				if podAllocated.Hostname == nodeInfo.Node().Name {
-					satisfied += 1
+					record(d, true)
 					continue
 				}
 
@@ -581,26 +1002,32 @@ func checkMaxNetworkCostRequirements(
 					return satisfied, violated, err
 				}
 
-				// Get zone and region from Pod Hostname
+				// Get cluster, zone and region from Pod Hostname
+				clusterPodNodeInfo := no.getNodeCluster(podNodeInfo.Node())
 				regionPodNodeInfo := networkcostawareutil.GetNodeRegion(podNodeInfo.Node())
 				zonePodNodeInfo := networkcostawareutil.GetNodeZone(podNodeInfo.Node())
 
-				if regionPodNodeInfo == "" && zonePodNodeInfo == "" { // Node has no zone and region defined
-					violated += 1
+				if cluster != "" && clusterPodNodeInfo != "" && cluster != clusterPodNodeInfo { // belong to different clusters
+					cost, costOK := clusterCosts.Cost(cluster, clusterPodNodeInfo)
+					if costOK {
+						record(d, cost <= d.MaxNetworkCost)
+					}
+				} else if cost, costOK := nodeLevelCost(costMap, nodeInfo.Node(), podNodeInfo.Node(), no.weights().sameZone); costOK { // Cost between the nodes or their racks
+					record(d, cost <= d.MaxNetworkCost)
+				} else if regionPodNodeInfo == "" && zonePodNodeInfo == "" { // Node has no zone and region defined
+					if no.unlabeledNodePolicy != pluginconfig.UnlabeledNodeIgnore {
+						record(d, false)
+					}
 				} else if region == regionPodNodeInfo { // If Nodes belong to the same region
 					if zone == zonePodNodeInfo { // If Nodes belong to the same zone
-						satisfied += 1
+						record(d, true)
 					} else { // belong to a different zone, check maxNetworkCost
 						cost, costOK := costMap[networkcostawareutil.CostKey{ // Retrieve the cost from the map (origin: zone, destination: pod zoneHostname)
 							Origin:      zone, // Time Complexity: O(1)
 							Destination: zonePodNodeInfo,
 						}]
 						if costOK {
-							if cost <= d.MaxNetworkCost {
-								satisfied += 1
-							} else {
-								violated += 1
-							}
+							record(d, cost <= d.MaxNetworkCost)
 						}
 					}
 				} else { // belong to a different region
@@ -609,16 +1036,21 @@ func checkMaxNetworkCostRequirements(
 						Destination: regionPodNodeInfo,
 					}]
 					if costOK {
-						if cost <= d.MaxNetworkCost {
-							satisfied += 1
-						} else {
-							violated += 1
-						}
+						record(d, cost <= d.MaxNetworkCost)
 					}
 				}
 			}
 		}
 	}
+	for _, ok := range nearestSatisfied {
+		if ok {
+			satisfied += 1
+		} else {
+			violated += 1
+		}
+	}
+	// Zone-local dependencies are satisfied, as for replicas in the same zone
+	satisfied += int64(zoneLocal.Len())
 	return satisfied, violated, nil
 }
 
@@ -628,11 +1060,37 @@ func (no *NetworkCostAware) getAccumulatedCost(
 	scheduledList networkcostawareutil.ScheduledList,
 	dependencyList []agv1alpha1.DependenciesInfo,
 	nodeName string,
+	cluster string,
 	region string,
 	zone string,
-	costMap map[networkcostawareutil.CostKey]int64) (int64, error) {
+	costMap map[networkcostawareutil.CostKey]int64,
+	clusterCosts multicluster.Costs,
+	zoneLocal sets.Set[string]) (int64, error) {
 	// keep track of the accumulated cost
 	var cost int64 = 0
+	w := no.weights()
+
+	// With the NearestReplica mode, only the cost to the nearest replica of each dependency is accumulated
+	nearestCost := make(map[string]int64)
+	add := func(d agv1alpha1.DependenciesInfo, latency, hops int64) {
+		value := no.dependencyCost(d, latency, hops)
+		if no.dependencyCostMode != pluginconfig.DependencyCostNearestReplica {
+			cost += value
+			return
+		}
+		if c, ok := nearestCost[d.Workload.Selector]; !ok || value < c {
+			nearestCost[d.Workload.Selector] = value
+		}
+	}
+
+	// Zone-local dependencies with a replica on the node being scored
+	sameHost := sets.New[string]()
+
+	// The node being scored, for the costs between nodes
+	var node *corev1.Node
+	if nodeInfo, err := no.handle.SnapshotSharedLister().NodeInfos().Get(nodeName); err == nil {
+		node = nodeInfo.Node()
+	}
 
 	// calculate accumulated shortest path
 	for _, podAllocated := range scheduledList { // For each pod already allocated
@@ -642,8 +1100,16 @@ func (no *NetworkCostAware) getAccumulatedCost(
 				continue
 			}
 
+			// Routing hints keep the traffic in the zone of the node: only the nearest zone-local replica counts
+			if zoneLocal.Has(d.Workload.Selector) {
+				if podAllocated.Hostname == nodeName {
+					sameHost.Insert(d.Workload.Selector)
+				}
+				continue
+			}
+
 			if podAllocated.Hostname == nodeName { // If the Pod hostname is the node being scored
-				cost += SameHostname
+				add(d, w.sameHostname, HopsSameHostname)
 			} else { // If Nodes are not the same
 				// Get NodeInfo from pod Hostname
 				podNodeInfo, err := no.handle.SnapshotSharedLister().NodeInfos().Get(podAllocated.Hostname)
@@ -651,24 +1117,36 @@ func (no *NetworkCostAware) getAccumulatedCost(
 					logger.Error(err, "getting pod hostname from Snapshot", "nodeInfo", podNodeInfo)
 					return cost, err
 				}
-				// Get zone and region from Pod Hostname
+				// Get cluster, zone and region from Pod Hostname
+				clusterPodNodeInfo := no.getNodeCluster(podNodeInfo.Node())
 				regionPodNodeInfo := networkcostawareutil.GetNodeRegion(podNodeInfo.Node())
 				zonePodNodeInfo := networkcostawareutil.GetNodeZone(podNodeInfo.Node())
 
-				if regionPodNodeInfo == "" && zonePodNodeInfo == "" { // Node has no zone and region defined
-					cost += MaxCost
+				if cluster != "" && clusterPodNodeInfo != "" && cluster != clusterPodNodeInfo { // belong to different clusters
+					value, ok := clusterCosts.Cost(cluster, clusterPodNodeInfo)
+					if ok {
+						add(d, value*w.clusterMultiplier, HopsOtherCluster) // Add the cost to the sum
+					} else {
+						add(d, w.maxCost*w.clusterMultiplier, HopsOtherCluster)
+					}
+				} else if value, ok := nodeLevelCost(costMap, node, podNodeInfo.Node(), w.sameZone); ok { // Cost between the nodes or their racks
+					add(d, value, nodeLevelHops(region, zone, regionPodNodeInfo, zonePodNodeInfo))
+				} else if regionPodNodeInfo == "" && zonePodNodeInfo == "" { // Node has no zone and region defined
+					if no.unlabeledNodePolicy != pluginconfig.UnlabeledNodeIgnore {
+						add(d, w.maxCost, HopsOtherCluster)
+					}
 				} else if region == regionPodNodeInfo { // If Nodes belong to the same region
 					if zone == zonePodNodeInfo { // If Nodes belong to the same zone
-						cost += SameZone
+						add(d, w.sameZone, HopsSameZone)
 					} else { // belong to a different zone
 						value, ok := costMap[networkcostawareutil.CostKey{ // Retrieve the cost from the map (origin: zone, destination: pod zoneHostname)
 							Origin:      zone, // Time Complexity: O(1)
 							Destination: zonePodNodeInfo,
 						}]
 						if ok {
-							cost += value // Add the cost to the sum
+							add(d, value*w.zoneMultiplier, HopsOtherZone) // Add the cost to the sum
 						} else {
-							cost += MaxCost
+							add(d, w.maxCost*w.zoneMultiplier, HopsOtherZone)
 						}
 					}
 				} else { // belong to a different region
@@ -677,17 +1155,57 @@ func (no *NetworkCostAware) getAccumulatedCost(
 						Destination: regionPodNodeInfo,
 					}]
 					if ok {
-						cost += value // Add the cost to the sum
+						add(d, value*w.regionMultiplier, HopsOtherRegion) // Add the cost to the sum
 					} else {
-						cost += MaxCost
+						add(d, w.maxCost*w.regionMultiplier, HopsOtherRegion)
 					}
 				}
 			}
 		}
 	}
+	for _, c := range nearestCost {
+		cost += c
+	}
+	counted := sets.New[string]()
+	for _, d := range dependencyList {
+		if !zoneLocal.Has(d.Workload.Selector) || counted.Has(d.Workload.Selector) {
+			continue
+		}
+		counted.Insert(d.Workload.Selector)
+		if sameHost.Has(d.Workload.Selector) {
+			cost += no.dependencyCost(d, w.sameHostname, HopsSameHostname)
+		} else {
+			cost += no.dependencyCost(d, w.sameZone, HopsSameZone)
+		}
+	}
 	return cost, nil
 }
 
+// dependencyCost : the cost to a replica of the dependency per the scoring mode, from the network cost and the network hops to it
+func (no *NetworkCostAware) dependencyCost(d agv1alpha1.DependenciesInfo, latency, hops int64) int64 {
+	switch no.scoringMode {
+	case pluginconfig.NetworkCostScoringHopCount:
+		return hops
+	case pluginconfig.NetworkCostScoringTrafficWeighted:
+		return latency * trafficWeight(d)
+	}
+	return latency
+}
+
+// trafficWeight : the traffic expected with the dependency, its minBandwidth in Mbit/s rounded up, 1 for the dependencies
+// without minBandwidth so that they still count as with the Latency scoring mode
+func trafficWeight(d agv1alpha1.DependenciesInfo) int64 {
+	return max(d.MinBandwidth.ScaledValue(resource.Mega), 1)
+}
+
+// getNodeCluster : return the cluster of the node, the local cluster if the node has no cluster label
+func (no *NetworkCostAware) getNodeCluster(node *corev1.Node) string {
+	if cluster := networkcostawareutil.GetNodeCluster(node); cluster != "" {
+		return cluster
+	}
+	return no.clusterName
+}
+
 func getPreFilterState(cycleState *framework.CycleState) (*PreFilterState, error) {
 	no, err := cycleState.Read(preFilterStateKey)
 	if err != nil {
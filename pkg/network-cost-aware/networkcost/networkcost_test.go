@@ -26,9 +26,11 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	testClientSet "k8s.io/client-go/kubernetes/fake"
@@ -46,6 +48,12 @@ import (
 	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
 	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/klog/v2"
+
+	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/fixture"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/multicluster"
+	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
 )
 
 var _ framework.SharedLister = &testSharedLister{}
@@ -1270,6 +1278,1130 @@ func TestNetworkCostAwareFilter(t *testing.T) {
 	}
 }
 
+func TestNetworkCostAwareMultiCluster(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-3").Label(string(networkcostawareutil.NetworkTopologyCluster), "cluster-b").
+			Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-4").Label(string(networkcostawareutil.NetworkTopologyCluster), "cluster-c").
+			Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+	}
+	clusterCosts := multicluster.Costs{
+		{Origin: "cluster-a", Destination: "cluster-b"}: 40,
+		{Origin: "cluster-b", Destination: "cluster-a"}: 40,
+	}
+	dependencyList := []agv1alpha1.DependenciesInfo{{
+		Workload:       agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "p2-deployment", Selector: "p2", APIVersion: "apps/v1", Namespace: "default"},
+		MaxNetworkCost: 30,
+	}}
+
+	tests := []struct {
+		name              string
+		hostname          string
+		nodeToFilter      *v1.Node
+		expectedSatisfied int64
+		expectedViolated  int64
+		expectedCost      int64
+	}{
+		{
+			name:              "local cluster, same zone: cluster costs are not used",
+			hostname:          "n-2",
+			nodeToFilter:      nodes[0],
+			expectedSatisfied: 1,
+			expectedViolated:  0,
+			expectedCost:      SameZone,
+		},
+		{
+			name:              "remote cluster: cluster cost exceeds maxNetworkCost",
+			hostname:          "n-3",
+			nodeToFilter:      nodes[0],
+			expectedSatisfied: 0,
+			expectedViolated:  1,
+			expectedCost:      40,
+		},
+		{
+			name:              "remote cluster without cost: maximum cost, not counted in Filter",
+			hostname:          "n-4",
+			nodeToFilter:      nodes[0],
+			expectedSatisfied: 0,
+			expectedViolated:  0,
+			expectedCost:      multicluster.MaxCost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, _ := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+				schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+
+			pl := &NetworkCostAware{
+				handle:      fh,
+				clusterName: "cluster-a",
+			}
+			logger := klog.FromContext(ctx)
+			scheduledList := networkcostawareutil.ScheduledList{{Name: "p2", Selector: "p2", Hostname: tt.hostname}}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(tt.nodeToFilter)
+			cluster := pl.getNodeCluster(tt.nodeToFilter)
+			region := networkcostawareutil.GetNodeRegion(tt.nodeToFilter)
+			zone := networkcostawareutil.GetNodeZone(tt.nodeToFilter)
+			costMap := map[networkcostawareutil.CostKey]int64{}
+
+			satisfied, violated, err := checkMaxNetworkCostRequirements(logger, scheduledList, dependencyList, nodeInfo,
+				cluster, region, zone, costMap, clusterCosts, nil, pl)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.expectedSatisfied, satisfied)
+			assert.Equal(t, tt.expectedViolated, violated)
+
+			cost, err := pl.getAccumulatedCost(logger, scheduledList, dependencyList, tt.nodeToFilter.Name,
+				cluster, region, zone, costMap, clusterCosts, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.expectedCost, cost)
+		})
+	}
+}
+
+func TestNetworkCostAwareDependencyCostMode(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-3").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z2").Obj(),
+		st.MakeNode().Name("n-4").Label(v1.LabelTopologyRegion, "R2").Label(v1.LabelTopologyZone, "Z3").Obj(),
+	}
+	costMap := map[networkcostawareutil.CostKey]int64{
+		{Origin: "Z1", Destination: "Z2"}: 20,
+		{Origin: "R1", Destination: "R2"}: 50,
+	}
+	dependencyList := []agv1alpha1.DependenciesInfo{
+		{
+			Workload:       agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "p2-deployment", Selector: "p2", APIVersion: "apps/v1", Namespace: "default"},
+			MaxNetworkCost: 30,
+		},
+		{
+			Workload:       agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "p3-deployment", Selector: "p3", APIVersion: "apps/v1", Namespace: "default"},
+			MaxNetworkCost: 30,
+		},
+	}
+	// p2 has replicas in the same zone, in another zone and in another region, p3 only in another region
+	scheduledList := networkcostawareutil.ScheduledList{
+		{Name: "p2-1", Selector: "p2", ReplicaID: "1", Hostname: "n-2"},
+		{Name: "p2-2", Selector: "p2", ReplicaID: "2", Hostname: "n-3"},
+		{Name: "p2-3", Selector: "p2", ReplicaID: "3", Hostname: "n-4"},
+		{Name: "p3-1", Selector: "p3", ReplicaID: "1", Hostname: "n-4"},
+	}
+
+	tests := []struct {
+		name              string
+		mode              pluginconfig.DependencyCostMode
+		expectedSatisfied int64
+		expectedViolated  int64
+		expectedCost      int64
+	}{
+		{
+			name:              "sum of the costs to all replicas",
+			mode:              pluginconfig.DependencyCostSum,
+			expectedSatisfied: 2,
+			expectedViolated:  2,
+			expectedCost:      SameZone + 20 + 50 + 50,
+		},
+		{
+			name:              "cost to the nearest replica",
+			mode:              pluginconfig.DependencyCostNearestReplica,
+			expectedSatisfied: 1,
+			expectedViolated:  1,
+			expectedCost:      SameZone + 50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, _ := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+				schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+
+			pl := &NetworkCostAware{
+				handle:             fh,
+				dependencyCostMode: tt.mode,
+			}
+			logger := klog.FromContext(ctx)
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(nodes[0])
+
+			satisfied, violated, err := checkMaxNetworkCostRequirements(logger, scheduledList, dependencyList, nodeInfo,
+				"", "R1", "Z1", costMap, nil, nil, pl)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.expectedSatisfied, satisfied)
+			assert.Equal(t, tt.expectedViolated, violated)
+
+			cost, err := pl.getAccumulatedCost(logger, scheduledList, dependencyList, nodes[0].Name,
+				"", "R1", "Z1", costMap, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.expectedCost, cost)
+		})
+	}
+
+	if _, err := New(context.Background(), &pluginconfig.NetworkCostArgs{DependencyCostMode: "Farthest"}, nil); err == nil {
+		t.Errorf("expected an error for an unknown dependencyCostMode")
+	}
+}
+
+func TestNetworkCostAwareScoringMode(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-3").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z2").Obj(),
+		st.MakeNode().Name("n-4").Label(v1.LabelTopologyRegion, "R2").Label(v1.LabelTopologyZone, "Z3").Obj(),
+	}
+	costMap := map[networkcostawareutil.CostKey]int64{
+		{Origin: "Z1", Destination: "Z2"}: 20,
+		{Origin: "R1", Destination: "R2"}: 50,
+	}
+	// p2 is expected to exchange 10 Mbit/s with the pod, p3 has no minBandwidth
+	dependencyList := []agv1alpha1.DependenciesInfo{
+		{
+			Workload:     agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "p2-deployment", Selector: "p2", APIVersion: "apps/v1", Namespace: "default"},
+			MinBandwidth: resource.MustParse("10M"),
+		},
+		{
+			Workload: agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "p3-deployment", Selector: "p3", APIVersion: "apps/v1", Namespace: "default"},
+		},
+	}
+	// p2 has replicas in the same zone, in another zone and in another region, p3 only in another region
+	scheduledList := networkcostawareutil.ScheduledList{
+		{Name: "p2-1", Selector: "p2", ReplicaID: "1", Hostname: "n-2"},
+		{Name: "p2-2", Selector: "p2", ReplicaID: "2", Hostname: "n-3"},
+		{Name: "p2-3", Selector: "p2", ReplicaID: "3", Hostname: "n-4"},
+		{Name: "p3-1", Selector: "p3", ReplicaID: "1", Hostname: "n-4"},
+	}
+
+	tests := []struct {
+		name         string
+		mode         pluginconfig.NetworkCostScoringMode
+		expectedCost int64
+	}{
+		{
+			name:         "network hops to all replicas",
+			mode:         pluginconfig.NetworkCostScoringHopCount,
+			expectedCost: HopsSameZone + HopsOtherZone + HopsOtherRegion + HopsOtherRegion,
+		},
+		{
+			name:         "network costs to all replicas",
+			mode:         pluginconfig.NetworkCostScoringLatency,
+			expectedCost: SameZone + 20 + 50 + 50,
+		},
+		{
+			name:         "network costs weighted by the expected traffic",
+			mode:         pluginconfig.NetworkCostScoringTrafficWeighted,
+			expectedCost: (SameZone+20+50)*10 + 50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, _ := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+				schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+
+			pl := &NetworkCostAware{
+				handle:             fh,
+				dependencyCostMode: pluginconfig.DependencyCostSum,
+				scoringMode:        tt.mode,
+			}
+			logger := klog.FromContext(ctx)
+			cost, err := pl.getAccumulatedCost(logger, scheduledList, dependencyList, nodes[0].Name,
+				"", "R1", "Z1", costMap, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.expectedCost, cost)
+		})
+	}
+
+	if _, err := New(context.Background(), &pluginconfig.NetworkCostArgs{ScoringMode: "Bandwidth"}, nil); err == nil {
+		t.Errorf("expected an error for an unknown scoringMode")
+	}
+}
+
+func TestNetworkCostAwareCostWeights(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-3").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z2").Obj(),
+		st.MakeNode().Name("n-4").Label(v1.LabelTopologyRegion, "R2").Label(v1.LabelTopologyZone, "Z3").Obj(),
+		st.MakeNode().Name("n-5").Label(v1.LabelTopologyRegion, "R3").Label(v1.LabelTopologyZone, "Z4").Obj(),
+	}
+	// the cost between R1 and R3 is not defined
+	costMap := map[networkcostawareutil.CostKey]int64{
+		{Origin: "Z1", Destination: "Z2"}: 20,
+		{Origin: "R1", Destination: "R2"}: 50,
+	}
+	dependencyList := []agv1alpha1.DependenciesInfo{
+		{Workload: agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "p2-deployment", Selector: "p2", APIVersion: "apps/v1", Namespace: "default"}},
+	}
+	// p2 has replicas on the same host, in the same zone, in another zone and in other regions
+	scheduledList := networkcostawareutil.ScheduledList{
+		{Name: "p2-1", Selector: "p2", ReplicaID: "1", Hostname: "n-1"},
+		{Name: "p2-2", Selector: "p2", ReplicaID: "2", Hostname: "n-2"},
+		{Name: "p2-3", Selector: "p2", ReplicaID: "3", Hostname: "n-3"},
+		{Name: "p2-4", Selector: "p2", ReplicaID: "4", Hostname: "n-4"},
+		{Name: "p2-5", Selector: "p2", ReplicaID: "5", Hostname: "n-5"},
+	}
+
+	tests := []struct {
+		name         string
+		args         *pluginconfig.NetworkCostArgs
+		expectedCost int64
+	}{
+		{
+			name:         "default cost weights",
+			expectedCost: SameHostname + SameZone + 20 + 50 + MaxCost,
+		},
+		{
+			name: "configured costs",
+			args: &pluginconfig.NetworkCostArgs{
+				MaxCost:          500,
+				SameZoneCost:     5,
+				SameHostnameCost: 1,
+			},
+			expectedCost: 1 + 5 + 20 + 50 + 500,
+		},
+		{
+			name: "cross-region penalty",
+			args: &pluginconfig.NetworkCostArgs{
+				SameZoneCost:         SameZone,
+				ZoneCostMultiplier:   2,
+				RegionCostMultiplier: 10,
+			},
+			expectedCost: SameHostname + SameZone + 20*2 + 50*10 + MaxCost*10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, _ := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+				schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+
+			pl := &NetworkCostAware{
+				handle:             fh,
+				dependencyCostMode: pluginconfig.DependencyCostSum,
+				scoringMode:        pluginconfig.NetworkCostScoringLatency,
+			}
+			if tt.args != nil {
+				w, err := newCostWeights(tt.args)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				pl.costWeights = w
+			}
+			logger := klog.FromContext(ctx)
+			cost, err := pl.getAccumulatedCost(logger, scheduledList, dependencyList, nodes[0].Name,
+				"", "R1", "Z1", costMap, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.expectedCost, cost)
+		})
+	}
+
+	if _, err := New(context.Background(), &pluginconfig.NetworkCostArgs{RegionCostMultiplier: -1}, nil); err == nil {
+		t.Errorf("expected an error for a negative regionCostMultiplier")
+	}
+}
+
+func TestNetworkCostAwareNodeTopology(t *testing.T) {
+	// Bare metal nodes without zone labels, n-1 to n-3 in racks
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(networkcostawareutil.NodeRackLabel, "rack-1").Obj(),
+		st.MakeNode().Name("n-2").Label(networkcostawareutil.NodeRackLabel, "rack-1").Obj(),
+		st.MakeNode().Name("n-3").Label(networkcostawareutil.NodeRackLabel, "rack-2").Obj(),
+		st.MakeNode().Name("n-4").Obj(),
+		st.MakeNode().Name("n-5").Obj(),
+	}
+	// the cost between rack-1 and n-5 is not defined
+	networkTopology := &ntv1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt-test", Namespace: "default"},
+		Spec: ntv1alpha1.NetworkTopologySpec{
+			Weights: ntv1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: ntv1alpha1.TopologyList{{
+					TopologyKey: networkcostawareutil.NetworkTopologyNode,
+					OriginList: ntv1alpha1.OriginList{
+						{Origin: "rack-2", CostList: []ntv1alpha1.CostInfo{{Destination: "rack-1", NetworkCost: 10}}},
+						{Origin: "rack-1", CostList: []ntv1alpha1.CostInfo{
+							{Destination: "rack-2", NetworkCost: 10},
+							{Destination: "n-4", NetworkCost: 30},
+						}},
+					},
+				}},
+			}},
+		},
+	}
+	dependencyList := []agv1alpha1.DependenciesInfo{{
+		Workload:       agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "p2-deployment", Selector: "p2", APIVersion: "apps/v1", Namespace: "default"},
+		MaxNetworkCost: 20,
+	}}
+	// p2 has replicas on the same host, in the same rack, in another rack, on a node out of the racks and on a node without cost
+	scheduledList := networkcostawareutil.ScheduledList{
+		{Name: "p2-1", Selector: "p2", ReplicaID: "1", Hostname: "n-1"},
+		{Name: "p2-2", Selector: "p2", ReplicaID: "2", Hostname: "n-2"},
+		{Name: "p2-3", Selector: "p2", ReplicaID: "3", Hostname: "n-3"},
+		{Name: "p2-4", Selector: "p2", ReplicaID: "4", Hostname: "n-4"},
+		{Name: "p2-5", Selector: "p2", ReplicaID: "5", Hostname: "n-5"},
+	}
+
+	ctx := context.Background()
+	registeredPlugins := []tf.RegisterPluginFunc{
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	fh, _ := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+		schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+	pl := &NetworkCostAware{
+		handle:             fh,
+		weightsName:        "UserDefined",
+		dependencyCostMode: pluginconfig.DependencyCostSum,
+		scoringMode:        pluginconfig.NetworkCostScoringLatency,
+	}
+	pl.sortNetworkTopologyCosts(networkTopology)
+
+	costMap := make(map[networkcostawareutil.CostKey]int64)
+	pl.populateCostMap(costMap, networkTopology, "", "", networkcostawareutil.GetNodeLocation(nodes[0]))
+	assert.Equal(t, map[networkcostawareutil.CostKey]int64{
+		{Origin: "rack-1", Destination: "rack-2"}: 10,
+		{Origin: "rack-1", Destination: "n-4"}:    30,
+	}, costMap)
+
+	logger := klog.FromContext(ctx)
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(nodes[0])
+	satisfied, violated, err := checkMaxNetworkCostRequirements(logger, scheduledList, dependencyList, nodeInfo,
+		"", "", "", costMap, nil, nil, pl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, int64(3), satisfied)
+	assert.Equal(t, int64(2), violated)
+
+	cost, err := pl.getAccumulatedCost(logger, scheduledList, dependencyList, nodes[0].Name,
+		"", "", "", costMap, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, int64(SameHostname+SameZone+10+30+MaxCost), cost)
+}
+
+func TestNetworkCostAwareUnlabeledNodePolicy(t *testing.T) {
+	// n-4 is an edge node without zone and region labels
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-3").Label(v1.LabelTopologyRegion, "us-east-1").Label(v1.LabelTopologyZone, "Z3").Obj(),
+		st.MakeNode().Name("n-4").Obj(),
+	}
+	// p2 has a replica in Z1 and a replica on the edge node
+	pods := []*v1.Pod{
+		makePodAllocated("p2", "p2-1", "n-2", 0, "basic", nil, nil),
+		makePodAllocated("p2", "p2-2", "n-4", 0, "basic", nil, nil),
+	}
+
+	tests := []struct {
+		name          string
+		policy        pluginconfig.UnlabeledNodePolicy
+		expectedCodes map[string]framework.Code
+		expectedCosts map[string]int64
+	}{
+		{
+			name:   "Penalize: maximum cost to and from the edge node, its replica violates the requirements",
+			policy: pluginconfig.UnlabeledNodePenalize,
+			expectedCodes: map[string]framework.Code{
+				"n-1": framework.Success,
+				"n-3": framework.Unschedulable,
+				"n-4": framework.Success,
+			},
+			expectedCosts: map[string]int64{
+				"n-1": SameZone + MaxCost,
+				"n-2": SameHostname + MaxCost,
+				"n-4": MaxCost + SameHostname,
+			},
+		},
+		{
+			name:   "Ignore: the edge node scores the average cost, its replica is not accounted",
+			policy: pluginconfig.UnlabeledNodeIgnore,
+			expectedCodes: map[string]framework.Code{
+				"n-1": framework.Success,
+				"n-3": framework.Unschedulable,
+				"n-4": framework.Success,
+			},
+			expectedCosts: map[string]int64{
+				"n-1": SameZone,
+				"n-2": SameHostname,
+				"n-3": 20,
+				"n-4": (SameZone + SameHostname + 20) / 3,
+			},
+		},
+		{
+			name:   "Filter: the edge node is filtered out, its replica violates the requirements",
+			policy: pluginconfig.UnlabeledNodeFilter,
+			expectedCodes: map[string]framework.Code{
+				"n-1": framework.Success,
+				"n-3": framework.Unschedulable,
+				"n-4": framework.UnschedulableAndUnresolvable,
+			},
+			expectedCosts: map[string]int64{
+				"n-1": SameZone + MaxCost,
+				"n-2": SameHostname + MaxCost,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			pl := newFixturePlugin(t, ctx, GetAppGroupCRBasic(), GetNetworkTopologyCRBasic(), nodes, pods)
+			pl.unlabeledNodePolicy = tt.policy
+			pod := makePod("p1", "p1-1", 0, "basic", nil, nil)
+			state := framework.NewCycleState()
+			if _, status := pl.PreFilter(ctx, state, pod); !status.IsSuccess() {
+				t.Fatalf("PreFilter: %v", status)
+			}
+			for _, n := range nodes {
+				expected, ok := tt.expectedCodes[n.Name]
+				if !ok {
+					continue
+				}
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(n)
+				if status := pl.Filter(ctx, state, pod, nodeInfo); status.Code() != expected {
+					t.Errorf("expected the code %v on %v, got %v", expected, n.Name, status)
+				}
+			}
+			for name, expected := range tt.expectedCosts {
+				cost, status := pl.Score(ctx, state, pod, name)
+				if !status.IsSuccess() {
+					t.Fatalf("Score %v: %v", name, status)
+				}
+				assert.Equal(t, expected, cost, name)
+			}
+		})
+	}
+
+	if _, err := New(context.Background(), &pluginconfig.NetworkCostArgs{UnlabeledNodePolicy: "Drop"}, nil); err == nil {
+		t.Errorf("expected an error for an unknown unlabeledNodePolicy")
+	}
+}
+
+func TestNetworkCostAwareAddRemovePod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-3").Label(v1.LabelTopologyRegion, "us-east-1").Label(v1.LabelTopologyZone, "Z3").Obj(),
+	}
+	replica := makePodAllocated("p2", "p2-1", "n-2", 0, "basic", nil, nil)
+	replica.UID = "p2-1"
+	nominated := makePod("p2", "p2-2", 0, "basic", nil, nil)
+	nominated.UID = "p2-2"
+	other := makePod("p3", "p3-1", 0, "basic", nil, nil)
+	other.UID = "p3-1"
+
+	pl := newFixturePlugin(t, ctx, GetAppGroupCRBasic(), GetNetworkTopologyCRBasic(), nodes, []*v1.Pod{replica})
+	pod := makePod("p1", "p1-1", 0, "basic", nil, nil)
+	state := framework.NewCycleState()
+	if _, status := pl.PreFilter(ctx, state, pod); !status.IsSuccess() {
+		t.Fatalf("PreFilter: %v", status)
+	}
+	nodeInfos := make(map[string]*framework.NodeInfo)
+	for _, n := range nodes {
+		nodeInfos[n.Name] = framework.NewNodeInfo()
+		nodeInfos[n.Name].SetNode(n)
+	}
+	check := func(state *framework.CycleState, node string, code framework.Code, cost int64) {
+		t.Helper()
+		if status := pl.Filter(ctx, state, pod, nodeInfos[node]); status.Code() != code {
+			t.Errorf("expected the code %v on %v, got %v", code, node, status)
+		}
+		got, status := pl.Score(ctx, state, pod, node)
+		if !status.IsSuccess() {
+			t.Fatalf("Score %v: %v", node, status)
+		}
+		assert.Equal(t, cost, got, node)
+	}
+	check(state, "n-3", framework.Unschedulable, 20)
+
+	// A replica nominated on n-3 satisfies the dependency there
+	added := state.Clone()
+	if status := pl.AddPod(ctx, added, pod, mustNewPodInfo(t, nominated), nodeInfos["n-3"]); !status.IsSuccess() {
+		t.Fatalf("AddPod: %v", status)
+	}
+	check(added, "n-3", framework.Success, 20+SameHostname)
+	// Adding the pods not being dependencies, or already accounted, changes nothing
+	for _, p := range []*v1.Pod{other, replica} {
+		if status := pl.AddPod(ctx, added, pod, mustNewPodInfo(t, p), nodeInfos["n-3"]); !status.IsSuccess() {
+			t.Fatalf("AddPod: %v", status)
+		}
+	}
+	check(added, "n-3", framework.Success, 20+SameHostname)
+
+	// Preempting the replica on n-2 leaves no dependency to satisfy there
+	removed := state.Clone()
+	check(removed, "n-2", framework.Success, SameHostname)
+	if status := pl.RemovePod(ctx, removed, pod, mustNewPodInfo(t, replica), nodeInfos["n-2"]); !status.IsSuccess() {
+		t.Fatalf("RemovePod: %v", status)
+	}
+	preFilterState, err := getPreFilterState(removed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preFilterState.scheduledList) != 0 {
+		t.Errorf("expected the replica removed from the scheduled list, got %v", preFilterState.scheduledList)
+	}
+	assert.Equal(t, int64(0), preFilterState.satisfiedMap["n-2"])
+	assert.Equal(t, int64(0), preFilterState.violatedMap["n-2"])
+
+	// The state the copies were cloned from is unchanged
+	check(state, "n-3", framework.Unschedulable, 20)
+	check(state, "n-2", framework.Success, SameHostname)
+	preFilterState, err = getPreFilterState(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(preFilterState.scheduledList))
+}
+
+func mustNewPodInfo(t *testing.T, pod *v1.Pod) *framework.PodInfo {
+	podInfo, err := framework.NewPodInfo(pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return podInfo
+}
+
+func TestNetworkCostAwareScaleCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		costCap  int64
+		scaling  pluginconfig.CostScalingMode
+		costs    []int64
+		expected []int64
+	}{
+		{
+			name:     "linear without capping, a huge cost squashes the others",
+			scaling:  pluginconfig.CostScalingLinear,
+			costs:    []int64{0, 10, 100000},
+			expected: []int64{100, 100, 0},
+		},
+		{
+			name:     "linear with capping",
+			costCap:  20,
+			scaling:  pluginconfig.CostScalingLinear,
+			costs:    []int64{0, 10, 100000},
+			expected: []int64{100, 50, 0},
+		},
+		{
+			name:     "logarithmic",
+			scaling:  pluginconfig.CostScalingLogarithmic,
+			costs:    []int64{0, 10, 100000},
+			expected: []int64{100, 80, 0},
+		},
+		{
+			name:     "capped costs score the same",
+			costCap:  100,
+			scaling:  pluginconfig.CostScalingLogarithmic,
+			costs:    []int64{10, 1000, 100000},
+			expected: []int64{100, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := &NetworkCostAware{
+				costCap:     tt.costCap,
+				costScaling: tt.scaling,
+			}
+			scores := make(framework.NodeScoreList, len(tt.costs))
+			for i, cost := range tt.costs {
+				scores[i] = framework.NodeScore{Name: fmt.Sprintf("n-%d", i), Score: pl.scaleCost(cost)}
+			}
+			if status := pl.NormalizeScore(context.Background(), nil, nil, scores); !status.IsSuccess() {
+				t.Fatalf("unexpected status: %v", status)
+			}
+			for i := range scores {
+				assert.Equal(t, tt.expected[i], scores[i].Score, "node %s", scores[i].Name)
+			}
+		})
+	}
+
+	if _, err := New(context.Background(), &pluginconfig.NetworkCostArgs{CostScaling: "Exponential"}, nil); err == nil {
+		t.Errorf("expected an error for an unknown costScaling")
+	}
+}
+
+func TestNetworkCostAwareRoutingHints(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-3").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z2").Obj(),
+		st.MakeNode().Name("n-4").Label(v1.LabelTopologyRegion, "R2").Label(v1.LabelTopologyZone, "Z3").Obj(),
+	}
+	costMap := map[networkcostawareutil.CostKey]int64{
+		{Origin: "Z1", Destination: "Z2"}: 20,
+		{Origin: "Z2", Destination: "Z1"}: 20,
+		{Origin: "R1", Destination: "R2"}: 50,
+	}
+	dependencyList := []agv1alpha1.DependenciesInfo{
+		{
+			Workload:       agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "p2-deployment", Selector: "p2", APIVersion: "apps/v1", Namespace: "default"},
+			MaxNetworkCost: 30,
+		},
+		{
+			Workload:       agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "p3-deployment", Selector: "p3", APIVersion: "apps/v1", Namespace: "default"},
+			MaxNetworkCost: 30,
+		},
+	}
+	// p2 has replicas in Z1 and in another region, p3 only in another region
+	pods := []*v1.Pod{
+		makePodAllocated("p2", "p2-1", "n-2", 0, "basic", nil, nil),
+		makePodAllocated("p2", "p2-2", "n-4", 0, "basic", nil, nil),
+		makePodAllocated("p3", "p3-1", "n-4", 0, "basic", nil, nil),
+	}
+	for _, p := range pods {
+		p.Namespace = "default"
+	}
+	preferClose := v1.ServiceTrafficDistributionPreferClose
+
+	tests := []struct {
+		name              string
+		service           *v1.Service
+		node              *v1.Node
+		expectedSatisfied int64
+		expectedViolated  int64
+		expectedCost      int64
+	}{
+		{
+			name:              "no routing hints, costs to all replicas",
+			service:           makeService("p2", "p2", nil),
+			node:              nodes[0],
+			expectedSatisfied: 1,
+			expectedViolated:  2,
+			expectedCost:      SameZone + 50 + 50,
+		},
+		{
+			name:              "topology mode auto keeps the traffic in the zone",
+			service:           makeService("p2", "p2", map[string]string{v1.AnnotationTopologyMode: "Auto"}),
+			node:              nodes[0],
+			expectedSatisfied: 1,
+			expectedViolated:  1,
+			expectedCost:      SameZone + 50,
+		},
+		{
+			name:              "deprecated topology-aware hints annotation",
+			service:           makeService("p2", "p2", map[string]string{v1.DeprecatedAnnotationTopologyAwareHints: "auto"}),
+			node:              nodes[0],
+			expectedSatisfied: 1,
+			expectedViolated:  1,
+			expectedCost:      SameZone + 50,
+		},
+		{
+			name: "prefer close traffic distribution",
+			service: func() *v1.Service {
+				svc := makeService("p2", "p2", nil)
+				svc.Spec.TrafficDistribution = &preferClose
+				return svc
+			}(),
+			node:              nodes[0],
+			expectedSatisfied: 1,
+			expectedViolated:  1,
+			expectedCost:      SameZone + 50,
+		},
+		{
+			name:              "zone-local replica on the node",
+			service:           makeService("p2", "p2", map[string]string{v1.AnnotationTopologyMode: "Auto"}),
+			node:              nodes[1],
+			expectedSatisfied: 1,
+			expectedViolated:  1,
+			expectedCost:      SameHostname + 50,
+		},
+		{
+			name:              "zone without replicas, costs to all replicas",
+			service:           makeService("p2", "p2", map[string]string{v1.AnnotationTopologyMode: "Auto"}),
+			node:              nodes[2],
+			expectedSatisfied: 1,
+			expectedViolated:  2,
+			expectedCost:      20 + 50 + 50,
+		},
+		{
+			name:              "service selecting other pods",
+			service:           makeService("p4", "p4", map[string]string{v1.AnnotationTopologyMode: "Auto"}),
+			node:              nodes[0],
+			expectedSatisfied: 1,
+			expectedViolated:  2,
+			expectedCost:      SameZone + 50 + 50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, _ := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+				schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+
+			informerFactory := informers.NewSharedInformerFactory(testClientSet.NewSimpleClientset(), 0)
+			if err := informerFactory.Core().V1().Services().Informer().GetStore().Add(tt.service); err != nil {
+				t.Fatal(err)
+			}
+
+			pl := &NetworkCostAware{
+				handle:        fh,
+				serviceLister: informerFactory.Core().V1().Services().Lister(),
+			}
+			logger := klog.FromContext(ctx)
+			hintZones := pl.getRoutingHintZones(logger, pods, dependencyList)
+			scheduledList := networkcostawareutil.GetScheduledList(pods)
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(tt.node)
+			region := networkcostawareutil.GetNodeRegion(tt.node)
+			zone := networkcostawareutil.GetNodeZone(tt.node)
+			zoneLocal := zoneLocalDependencies(hintZones, pl.getNodeCluster(tt.node), zone)
+
+			satisfied, violated, err := checkMaxNetworkCostRequirements(logger, scheduledList, dependencyList, nodeInfo,
+				"", region, zone, costMap, nil, zoneLocal, pl)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.expectedSatisfied, satisfied)
+			assert.Equal(t, tt.expectedViolated, violated)
+
+			cost, err := pl.getAccumulatedCost(logger, scheduledList, dependencyList, tt.node.Name,
+				"", region, zone, costMap, nil, zoneLocal)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.expectedCost, cost)
+		})
+	}
+}
+
+func TestNetworkCostAwareHeadlessDependencies(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-3").Label(v1.LabelTopologyRegion, "R1").Label(v1.LabelTopologyZone, "Z2").Obj(),
+		st.MakeNode().Name("n-4").Label(v1.LabelTopologyRegion, "R2").Label(v1.LabelTopologyZone, "Z3").Obj(),
+	}
+	costMap := map[networkcostawareutil.CostKey]int64{
+		{Origin: "Z1", Destination: "Z2"}: 20,
+		{Origin: "R1", Destination: "R2"}: 50,
+	}
+	dependencyList := []agv1alpha1.DependenciesInfo{
+		{
+			Workload:       agv1alpha1.AppGroupWorkloadInfo{Kind: "StatefulSet", Name: "db", Selector: "db", APIVersion: "apps/v1", Namespace: "default"},
+			MaxNetworkCost: 30,
+		},
+	}
+	// the partitions of db run in another region, in Z1 and in Z2
+	pods := []*v1.Pod{
+		makePodAllocated("db", "db-0", "n-4", 0, "basic", nil, nil),
+		makePodAllocated("db", "db-1", "n-2", 0, "basic", nil, nil),
+		makePodAllocated("db", "db-2", "n-3", 0, "basic", nil, nil),
+	}
+	for _, p := range pods {
+		p.Namespace = "default"
+		p.UID = types.UID(p.Name)
+	}
+	headlessService := makeService("db", "db", nil)
+	headlessService.Spec.ClusterIP = v1.ClusterIPNone
+
+	tests := []struct {
+		name              string
+		service           *v1.Service
+		ordinals          string
+		expectedSatisfied int64
+		expectedViolated  int64
+		expectedCost      int64
+	}{
+		{
+			name:              "no ordinal, costs to all replicas",
+			service:           headlessService,
+			expectedSatisfied: 2,
+			expectedViolated:  1,
+			expectedCost:      50 + SameZone + 20,
+		},
+		{
+			name:              "ordinal of a replica in the zone",
+			service:           headlessService,
+			ordinals:          "db=1",
+			expectedSatisfied: 1,
+			expectedViolated:  0,
+			expectedCost:      SameZone,
+		},
+		{
+			name:              "ordinal of a replica in another region",
+			service:           headlessService,
+			ordinals:          "cache=1, db=0",
+			expectedSatisfied: 0,
+			expectedViolated:  1,
+			expectedCost:      50,
+		},
+		{
+			name:              "ordinal not scheduled yet",
+			service:           headlessService,
+			ordinals:          "db=3",
+			expectedSatisfied: 0,
+			expectedViolated:  0,
+			expectedCost:      0,
+		},
+		{
+			name:              "invalid ordinal, costs to all replicas",
+			service:           headlessService,
+			ordinals:          "db=first",
+			expectedSatisfied: 2,
+			expectedViolated:  1,
+			expectedCost:      50 + SameZone + 20,
+		},
+		{
+			name:              "load-balanced service, costs to all replicas",
+			service:           makeService("db", "db", nil),
+			ordinals:          "db=1",
+			expectedSatisfied: 2,
+			expectedViolated:  1,
+			expectedCost:      50 + SameZone + 20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, _ := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+				schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+
+			informerFactory := informers.NewSharedInformerFactory(testClientSet.NewSimpleClientset(), 0)
+			if err := informerFactory.Core().V1().Services().Informer().GetStore().Add(tt.service); err != nil {
+				t.Fatal(err)
+			}
+
+			pl := &NetworkCostAware{
+				handle:        fh,
+				serviceLister: informerFactory.Core().V1().Services().Lister(),
+			}
+			pod := makePodAllocated("app", "app-0", "", 0, "basic", nil, nil)
+			if tt.ordinals != "" {
+				pod.Annotations = map[string]string{DependencyOrdinalsAnnotation: tt.ordinals}
+			}
+			logger := klog.FromContext(ctx)
+			scheduledList := pl.pinHeadlessDependencies(logger, pod, pods, dependencyList, networkcostawareutil.GetScheduledList(pods))
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(nodes[0])
+
+			satisfied, violated, err := checkMaxNetworkCostRequirements(logger, scheduledList, dependencyList, nodeInfo,
+				"", "R1", "Z1", costMap, nil, nil, pl)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.expectedSatisfied, satisfied)
+			assert.Equal(t, tt.expectedViolated, violated)
+
+			cost, err := pl.getAccumulatedCost(logger, scheduledList, dependencyList, nodes[0].Name,
+				"", "R1", "Z1", costMap, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tt.expectedCost, cost)
+		})
+	}
+}
+
+func TestNetworkCostAwareEnabledFor(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowedNamespaces []string
+		deniedNamespaces  []string
+		pod               *v1.Pod
+		expected          bool
+	}{
+		{
+			name:     "all the namespaces",
+			pod:      st.MakePod().Name("p1").Label(agv1alpha1.AppGroupLabel, "a1").Namespace("default").Obj(),
+			expected: true,
+		},
+		{
+			name:     "pod opted out",
+			pod:      st.MakePod().Name("p1").Label(agv1alpha1.AppGroupLabel, "a1").Namespace("default").Annotation(OptOutAnnotation, "true").Obj(),
+			expected: false,
+		},
+		{
+			name:              "allowed namespace",
+			allowedNamespaces: []string{"default"},
+			pod:               st.MakePod().Name("p1").Label(agv1alpha1.AppGroupLabel, "a1").Namespace("default").Obj(),
+			expected:          true,
+		},
+		{
+			name:              "namespace not allowed",
+			allowedNamespaces: []string{"default"},
+			pod:               st.MakePod().Name("p1").Label(agv1alpha1.AppGroupLabel, "a1").Namespace("batch").Obj(),
+			expected:          false,
+		},
+		{
+			name:              "denied namespace",
+			allowedNamespaces: []string{"default"},
+			deniedNamespaces:  []string{"default"},
+			pod:               st.MakePod().Name("p1").Label(agv1alpha1.AppGroupLabel, "a1").Namespace("default").Obj(),
+			expected:          false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := &NetworkCostAware{
+				allowedNamespaces: sets.New(tt.allowedNamespaces...),
+				deniedNamespaces:  sets.New(tt.deniedNamespaces...),
+			}
+			assert.Equal(t, tt.expected, pl.enabledFor(tt.pod))
+
+			if tt.expected {
+				return
+			}
+			// The pods the plugin doesn't apply to are scored equally, even in an AppGroup
+			state := framework.NewCycleState()
+			if _, status := pl.PreFilter(context.Background(), state, tt.pod); !status.IsSuccess() {
+				t.Fatalf("unexpected status: %v", status)
+			}
+			preFilterState, err := getPreFilterState(state)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.True(t, preFilterState.scoreEqually)
+		})
+	}
+}
+
+func TestGetPodOrdinal(t *testing.T) {
+	tests := []struct {
+		name            string
+		pod             *v1.Pod
+		expectedOrdinal int
+		expectedOK      bool
+	}{
+		{
+			name:            "pod index label",
+			pod:             st.MakePod().Name("db-7").Label("apps.kubernetes.io/pod-index", "3").Obj(),
+			expectedOrdinal: 3,
+			expectedOK:      true,
+		},
+		{
+			name:            "name suffix",
+			pod:             st.MakePod().Name("db-12").Obj(),
+			expectedOrdinal: 12,
+			expectedOK:      true,
+		},
+		{
+			name: "deployment pod",
+			pod:  st.MakePod().Name("db-7d9f8c-x2k4p").Obj(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordinal, ok := getPodOrdinal(tt.pod)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedOrdinal, ordinal)
+		})
+	}
+}
+
+func TestGetSpreadConflict(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyZone, "Z2").Obj(),
+		st.MakeNode().Name("n-3").Label(v1.LabelTopologyZone, "Z3").Obj(),
+	}
+	web := func(name string, node string) *v1.Pod {
+		return st.MakePod().Name(name).Namespace("default").Label("app", "web").Node(node).Obj()
+	}
+
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		pods         []*v1.Pod
+		satisfiedMap map[string]int64
+		violatedMap  map[string]int64
+		expected     string
+	}{
+		{
+			name: "low-cost zone ruled out by the spread",
+			pod: st.MakePod().Name("p").Namespace("default").Label("app", "web").
+				SpreadConstraint(1, v1.LabelTopologyZone, v1.DoNotSchedule, selector, nil, nil, nil, nil).Obj(),
+			pods:         []*v1.Pod{web("w-1", "n-1"), web("w-2", "n-1")},
+			satisfiedMap: map[string]int64{"n-1": 1},
+			violatedMap:  map[string]int64{"n-2": 1, "n-3": 1},
+			expected:     "the 1 nodes meeting the maxNetworkCost requirements of the dependencies are all ruled out by the topologySpreadConstraints of the pod on topology.kubernetes.io/zone (maxSkew 1)",
+		},
+		{
+			name: "low-cost zone within the skew",
+			pod: st.MakePod().Name("p").Namespace("default").Label("app", "web").
+				SpreadConstraint(1, v1.LabelTopologyZone, v1.DoNotSchedule, selector, nil, nil, nil, nil).Obj(),
+			pods:         []*v1.Pod{web("w-1", "n-2"), web("w-2", "n-3")},
+			satisfiedMap: map[string]int64{"n-1": 1},
+			violatedMap:  map[string]int64{"n-2": 1, "n-3": 1},
+		},
+		{
+			name: "spread not enforced",
+			pod: st.MakePod().Name("p").Namespace("default").Label("app", "web").
+				SpreadConstraint(1, v1.LabelTopologyZone, v1.ScheduleAnyway, selector, nil, nil, nil, nil).Obj(),
+			pods:         []*v1.Pod{web("w-1", "n-1"), web("w-2", "n-1")},
+			satisfiedMap: map[string]int64{"n-1": 1},
+			violatedMap:  map[string]int64{"n-2": 1, "n-3": 1},
+		},
+		{
+			name: "no node meets the requirements",
+			pod: st.MakePod().Name("p").Namespace("default").Label("app", "web").
+				SpreadConstraint(1, v1.LabelTopologyZone, v1.DoNotSchedule, selector, nil, nil, nil, nil).Obj(),
+			pods:        []*v1.Pod{web("w-1", "n-1"), web("w-2", "n-1")},
+			violatedMap: map[string]int64{"n-1": 1, "n-2": 1, "n-3": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := newTestSharedLister(tt.pods, nodes)
+			nodeList, err := snapshot.NodeInfos().List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			conflict := getSpreadConflict(klog.Background(), tt.pod, nodeList, tt.satisfiedMap, tt.violatedMap)
+			assert.Equal(t, tt.expected, conflict)
+		})
+	}
+}
+
 func BenchmarkNetworkCostAwareFilter(b *testing.B) {
 	// Get AppGroup CRD: onlineboutique
 	onlineBoutiqueAppGroup := GetAppGroupCROnlineBoutique()
@@ -1619,3 +2751,180 @@ func makePodAllocated(selector string, podName string, hostname string, priority
 		},
 	}
 }
+
+func makeService(name string, selector string, annotations map[string]string) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{agv1alpha1.AppGroupSelectorLabel: selector},
+		},
+	}
+}
+
+// newFixturePlugin : return the plugin for a synthesized topology, the pods being already scheduled
+func newFixturePlugin(tb testing.TB, ctx context.Context, ag *agv1alpha1.AppGroup, nt *ntv1alpha1.NetworkTopology, nodes []*v1.Node, pods []*v1.Pod) *NetworkCostAware {
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(ag, nt).
+		WithStatusSubresource(&agv1alpha1.AppGroup{}).
+		WithStatusSubresource(&ntv1alpha1.NetworkTopology{}).
+		Build()
+
+	cs := testClientSet.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	for _, p := range pods {
+		if err := podInformer.Informer().GetIndexer().Add(p); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	registeredPlugins := []tf.RegisterPluginFunc{
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	fh, err := tf.NewFramework(ctx, registeredPlugins, "default-scheduler", schedruntime.WithClientSet(cs),
+		schedruntime.WithInformerFactory(informerFactory), schedruntime.WithSnapshotSharedLister(newTestSharedLister(pods, nodes)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return &NetworkCostAware{
+		Client:      client,
+		podLister:   podInformer.Lister(),
+		handle:      fh,
+		namespaces:  []string{ag.Namespace},
+		weightsName: fixture.WeightsName,
+		ntName:      nt.Name,
+	}
+}
+
+func BenchmarkNetworkCostAwareLargeTopology(b *testing.B) {
+	tests := []struct {
+		scale           fixture.Scale
+		workloads       int
+		maxDependencies int
+		replicas        int
+	}{
+		{scale: fixture.Scale{Regions: 2, ZonesPerRegion: 3, NodesPerZone: 10}, workloads: 10, maxDependencies: 3, replicas: 2},
+		{scale: fixture.Scale{Regions: 5, ZonesPerRegion: 10, NodesPerZone: 20}, workloads: 20, maxDependencies: 5, replicas: 3},
+		{scale: fixture.Scale{Regions: 10, ZonesPerRegion: 10, NodesPerZone: 50}, workloads: 50, maxDependencies: 5, replicas: 3},
+		{scale: fixture.Scale{Regions: 20, ZonesPerRegion: 25, NodesPerZone: 20}, workloads: 100, maxDependencies: 10, replicas: 5},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("%v nodes, %d workloads, %d replicas", tt.scale, tt.workloads, tt.replicas)
+		b.Run(name, func(b *testing.B) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			rng := rand.New(rand.NewSource(1))
+			nodes := fixture.NewNodes(tt.scale)
+			nt := fixture.NewNetworkTopology("nt-test", "default", tt.scale, rng)
+			ag := fixture.NewAppGroup("large", "default", tt.workloads, tt.maxDependencies, rng)
+			pods := fixture.NewPods(ag, nodes, tt.replicas, rng)
+			pl := newFixturePlugin(b, ctx, ag, nt, nodes, pods)
+			// The first workload has the most candidate dependencies
+			pod := fixture.NewPod(ag, fixture.WorkloadSelector(0), tt.replicas)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				state := framework.NewCycleState()
+				if _, status := pl.PreFilter(ctx, state, pod); !status.IsSuccess() {
+					b.Fatalf("PreFilter: %v", status)
+				}
+				for _, n := range nodes {
+					if _, status := pl.Score(ctx, state, pod, n.Name); !status.IsSuccess() {
+						b.Fatalf("Score %v: %v", n.Name, status)
+					}
+				}
+			}
+		})
+	}
+}
+
+func FuzzNetworkCostAwareScore(f *testing.F) {
+	f.Add(int64(1))
+	f.Add(int64(2))
+	f.Add(int64(3))
+	f.Add(int64(42))
+	f.Fuzz(func(t *testing.T, seed int64) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		rng := rand.New(rand.NewSource(seed))
+		scale := fixture.Scale{Regions: 1 + rng.Intn(4), ZonesPerRegion: 1 + rng.Intn(4), NodesPerZone: 1 + rng.Intn(3)}
+		nodes := fixture.NewNodes(scale)
+		nt := fixture.NewNetworkTopology("nt-test", "default", scale, rng)
+		ag := fixture.NewAppGroup("fuzz", "default", 1+rng.Intn(8), 3, rng)
+		pods := fixture.NewPods(ag, nodes, 1+rng.Intn(2), rng)
+		workload := ag.Spec.Workloads[rng.Intn(len(ag.Spec.Workloads))]
+		pod := fixture.NewPod(ag, workload.Workload.Selector, len(pods))
+
+		// Expected costs, looked up linearly in the NetworkTopology as generated, before the plugin sorts it
+		costs := make(map[networkcostawareutil.CostKey]int64)
+		for _, topology := range nt.Spec.Weights[0].TopologyList {
+			for _, origin := range topology.OriginList {
+				for _, c := range origin.CostList {
+					costs[networkcostawareutil.CostKey{Origin: origin.Origin, Destination: c.Destination}] = c.NetworkCost
+				}
+			}
+		}
+		nodeByName := make(map[string]*v1.Node)
+		for _, n := range nodes {
+			nodeByName[n.Name] = n
+		}
+		expectedCost := func(node *v1.Node) int64 {
+			var cost int64
+			for _, d := range workload.Dependencies {
+				for _, p := range pods {
+					if p.Labels[agv1alpha1.AppGroupSelectorLabel] != d.Workload.Selector {
+						continue
+					}
+					other := nodeByName[p.Spec.NodeName]
+					region, zone := networkcostawareutil.GetNodeRegion(node), networkcostawareutil.GetNodeZone(node)
+					otherRegion, otherZone := networkcostawareutil.GetNodeRegion(other), networkcostawareutil.GetNodeZone(other)
+					switch {
+					case other.Name == node.Name:
+						cost += SameHostname
+					case zone == otherZone:
+						cost += SameZone
+					case region == otherRegion:
+						cost += costs[networkcostawareutil.CostKey{Origin: zone, Destination: otherZone}]
+					default:
+						cost += costs[networkcostawareutil.CostKey{Origin: region, Destination: otherRegion}]
+					}
+				}
+			}
+			return cost
+		}
+
+		pl := newFixturePlugin(t, ctx, ag, nt, nodes, pods)
+		state := framework.NewCycleState()
+		if _, status := pl.PreFilter(ctx, state, pod); !status.IsSuccess() {
+			t.Fatalf("PreFilter: %v", status)
+		}
+		var scores framework.NodeScoreList
+		for _, n := range nodes {
+			score, status := pl.Score(ctx, state, pod, n.Name)
+			if !status.IsSuccess() {
+				t.Fatalf("Score %v: %v", n.Name, status)
+			}
+			if want := expectedCost(n); len(workload.Dependencies) > 0 && score != want {
+				t.Errorf("expected the cost %d for %v on %v, got %d", want, pod.Name, n.Name, score)
+			}
+			scores = append(scores, framework.NodeScore{Name: n.Name, Score: score})
+		}
+		if status := pl.NormalizeScore(ctx, state, pod, scores); !status.IsSuccess() {
+			t.Fatalf("NormalizeScore: %v", status)
+		}
+		for _, s := range scores {
+			if s.Score < framework.MinNodeScore || s.Score > framework.MaxNodeScore {
+				t.Errorf("expected the normalized score of %v in [%d, %d], got %d", s.Name, framework.MinNodeScore, framework.MaxNodeScore, s.Score)
+			}
+		}
+	})
+}
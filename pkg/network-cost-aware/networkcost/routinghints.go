@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkcost
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	networkcostawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/util"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+)
+
+// topologyZone : a zone of a cluster, routing hints don't cross clusters
+type topologyZone struct {
+	cluster string
+	zone    string
+}
+
+// topologyAwareRouting : whether the Service keeps the traffic in the zone of the client when the zone has endpoints,
+// with topology-aware routing hints (service.kubernetes.io/topology-mode) or the PreferClose traffic distribution
+func topologyAwareRouting(svc *corev1.Service) bool {
+	mode, ok := svc.Annotations[corev1.AnnotationTopologyMode]
+	if !ok {
+		mode = svc.Annotations[corev1.DeprecatedAnnotationTopologyAwareHints]
+	}
+	if strings.EqualFold(mode, "auto") {
+		return true
+	}
+	return svc.Spec.TrafficDistribution != nil && *svc.Spec.TrafficDistribution == corev1.ServiceTrafficDistributionPreferClose
+}
+
+// getRoutingHintZones : map the dependencies served by a topology-aware Service to the zones hosting their replicas
+func (no *NetworkCostAware) getRoutingHintZones(
+	logger klog.Logger,
+	pods []*corev1.Pod,
+	dependencyList []agv1alpha1.DependenciesInfo) map[string]sets.Set[topologyZone] {
+	hintZones := make(map[string]sets.Set[topologyZone])
+	if no.serviceLister == nil {
+		return hintZones
+	}
+
+	for _, d := range dependencyList {
+		if _, ok := hintZones[d.Workload.Selector]; ok {
+			continue
+		}
+		var zones sets.Set[topologyZone]
+		for _, p := range pods {
+			if networkcostawareutil.GetPodAppGroupSelector(p) != d.Workload.Selector {
+				continue
+			}
+			// The Service is looked up once, from any replica of the dependency
+			if zones == nil {
+				if !no.hasTopologyAwareService(logger, p) {
+					break
+				}
+				zones = sets.New[topologyZone]()
+			}
+			if p.Spec.NodeName == "" {
+				continue
+			}
+			nodeInfo, err := no.handle.SnapshotSharedLister().NodeInfos().Get(p.Spec.NodeName)
+			if err != nil {
+				logger.V(6).Info("Node of the dependency not found in snapshot", "pod", klog.KObj(p), "node", p.Spec.NodeName)
+				continue
+			}
+			if zone := networkcostawareutil.GetNodeZone(nodeInfo.Node()); zone != "" {
+				zones.Insert(topologyZone{cluster: no.getNodeCluster(nodeInfo.Node()), zone: zone})
+			}
+		}
+		if zones != nil {
+			logger.V(6).Info("Dependency served with topology-aware routing", "dependency", d.Workload.Selector, "zones", zones.UnsortedList())
+			hintZones[d.Workload.Selector] = zones
+		}
+	}
+	return hintZones
+}
+
+// hasTopologyAwareService : whether the pod is an endpoint of a Service with topology-aware routing
+func (no *NetworkCostAware) hasTopologyAwareService(logger klog.Logger, pod *corev1.Pod) bool {
+	return no.hasService(logger, pod, topologyAwareRouting)
+}
+
+// hasService : whether the pod is an endpoint of a Service matching the given predicate
+func (no *NetworkCostAware) hasService(logger klog.Logger, pod *corev1.Pod, match func(*corev1.Service) bool) bool {
+	services, err := no.serviceLister.Services(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "listing services", "namespace", pod.Namespace)
+		return false
+	}
+	for _, svc := range services {
+		if len(svc.Spec.Selector) == 0 || !match(svc) {
+			continue
+		}
+		if labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// zoneLocalDependencies : the dependencies whose traffic stays in the zone of the node, since it hosts some of their replicas
+func zoneLocalDependencies(hintZones map[string]sets.Set[topologyZone], cluster string, zone string) sets.Set[string] {
+	local := sets.New[string]()
+	if zone == "" {
+		return local
+	}
+	for selector, zones := range hintZones {
+		if zones.Has(topologyZone{cluster: cluster, zone: zone}) {
+			local.Insert(selector)
+		}
+	}
+	return local
+}
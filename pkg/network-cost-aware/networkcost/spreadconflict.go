@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkcost
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// SpreadConflict : reason of the event recorded on a pod whose topologySpreadConstraints rule out all the nodes
+// meeting the maxNetworkCost requirements of its dependencies
+const SpreadConflict = "NetworkCostSpreadConflict"
+
+// spreadDomains : the topology domains allowed by a DoNotSchedule topologySpreadConstraint of the pod
+type spreadDomains struct {
+	constraint corev1.TopologySpreadConstraint
+	allowed    map[string]bool
+}
+
+// allows : whether the constraint lets the pod on the node, nodes without the topology key are ruled out
+func (s *spreadDomains) allows(node *corev1.Node) bool {
+	domain, ok := node.Labels[s.constraint.TopologyKey]
+	return ok && s.allowed[domain]
+}
+
+// getSpreadDomains : compute, for each DoNotSchedule topologySpreadConstraint of the pod, the topology domains
+// where placing the pod keeps the skew within maxSkew, as the PodTopologySpread plugin does
+func getSpreadDomains(logger klog.Logger, pod *corev1.Pod, nodeList []*framework.NodeInfo) []*spreadDomains {
+	var spreads []*spreadDomains
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if c.WhenUnsatisfiable != corev1.DoNotSchedule {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(c.LabelSelector)
+		if err != nil {
+			logger.V(4).Info("Ignoring topologySpreadConstraint with an invalid labelSelector", "pod", klog.KObj(pod), "err", err)
+			continue
+		}
+		for _, key := range c.MatchLabelKeys {
+			if value, ok := pod.Labels[key]; ok {
+				r, err := labels.NewRequirement(key, selection.Equals, []string{value})
+				if err == nil {
+					selector = selector.Add(*r)
+				}
+			}
+		}
+
+		// Count the pods matching the selector in each domain
+		counts := make(map[string]int32)
+		for _, nodeInfo := range nodeList {
+			domain, ok := nodeInfo.Node().Labels[c.TopologyKey]
+			if !ok {
+				continue
+			}
+			count := counts[domain]
+			for _, p := range nodeInfo.Pods {
+				if p.Pod.Namespace != pod.Namespace || p.Pod.DeletionTimestamp != nil {
+					continue
+				}
+				if selector.Matches(labels.Set(p.Pod.Labels)) {
+					count++
+				}
+			}
+			counts[domain] = count
+		}
+		if len(counts) == 0 {
+			continue
+		}
+		minCount := int32(math.MaxInt32)
+		for _, count := range counts {
+			minCount = min(minCount, count)
+		}
+		var selfMatch int32
+		if selector.Matches(labels.Set(pod.Labels)) {
+			selfMatch = 1
+		}
+
+		allowed := make(map[string]bool, len(counts))
+		for domain, count := range counts {
+			allowed[domain] = count+selfMatch-minCount <= c.MaxSkew
+		}
+		spreads = append(spreads, &spreadDomains{constraint: c, allowed: allowed})
+	}
+	return spreads
+}
+
+// getSpreadConflict : explain why the pod can't be scheduled when all the nodes meeting the maxNetworkCost requirements
+// of its dependencies are ruled out by its own topologySpreadConstraints, "" when some of them remain feasible
+func getSpreadConflict(logger klog.Logger, pod *corev1.Pod, nodeList []*framework.NodeInfo,
+	satisfiedMap map[string]int64, violatedMap map[string]int64) string {
+	spreads := getSpreadDomains(logger, pod, nodeList)
+	if len(spreads) == 0 {
+		return ""
+	}
+
+	lowCost := 0
+	rulingOut := make(map[int]bool)
+	for _, nodeInfo := range nodeList {
+		name := nodeInfo.Node().Name
+		if violatedMap[name] > satisfiedMap[name] {
+			continue
+		}
+		lowCost++
+		feasible := true
+		for i, s := range spreads {
+			if !s.allows(nodeInfo.Node()) {
+				rulingOut[i] = true
+				feasible = false
+			}
+		}
+		if feasible {
+			return ""
+		}
+	}
+	if lowCost == 0 {
+		return ""
+	}
+
+	var constraints []string
+	for i, s := range spreads {
+		if rulingOut[i] {
+			constraints = append(constraints, fmt.Sprintf("%v (maxSkew %v)", s.constraint.TopologyKey, s.constraint.MaxSkew))
+		}
+	}
+	return fmt.Sprintf("the %v nodes meeting the maxNetworkCost requirements of the dependencies are all ruled out by the topologySpreadConstraints of the pod on %v",
+		lowCost, strings.Join(constraints, ", "))
+}
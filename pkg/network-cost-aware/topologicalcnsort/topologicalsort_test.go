@@ -20,6 +20,7 @@ import (
 	"context"
 	"math"
 	"sort"
+	"strings"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
@@ -28,9 +29,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/rand"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
 
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	testutil "sigs.k8s.io/scheduler-plugins/test/util"
@@ -224,6 +230,46 @@ func TestTopologicalSortLess(t *testing.T) {
 			desiredTopologyOrder: onlineBoutiqueAppGroup.Status.TopologyOrder,
 			want:                 false,
 		},
+		{
+			name:                     "basic, same AppGroup, same order, p2 priority higher",
+			agName:                   "basic",
+			appGroup:                 basicAppGroup.DeepCopy(),
+			namespace:                "default",
+			numMembers:               3,
+			selectors:                []string{"p1", "p2", "p3"},
+			deploymentNames:          []string{"p1-deployment", "p2-deployment", "p3-deployment"},
+			desiredRunningWorkloads:  3,
+			podPhase:                 v1.PodRunning,
+			topologySortingAlgorithm: "KahnSort",
+			pInfo1: &framework.QueuedPodInfo{
+				PodInfo: testutil.MustNewPodInfo(t, makePod("p2", "p2-deployment-a", 0, "basic", nil, nil)),
+			},
+			pInfo2: &framework.QueuedPodInfo{
+				PodInfo: testutil.MustNewPodInfo(t, makePod("p2", "p2-deployment-b", 10, "basic", nil, nil)),
+			},
+			desiredTopologyOrder: basicAppGroup.Status.TopologyOrder,
+			want:                 false,
+		},
+		{
+			name:                     "basic, same AppGroup, same order and priority, sorted by name",
+			agName:                   "basic",
+			appGroup:                 basicAppGroup.DeepCopy(),
+			namespace:                "default",
+			numMembers:               3,
+			selectors:                []string{"p1", "p2", "p3"},
+			deploymentNames:          []string{"p1-deployment", "p2-deployment", "p3-deployment"},
+			desiredRunningWorkloads:  3,
+			podPhase:                 v1.PodRunning,
+			topologySortingAlgorithm: "KahnSort",
+			pInfo1: &framework.QueuedPodInfo{
+				PodInfo: testutil.MustNewPodInfo(t, makePod("p2", "p2-deployment-a", 0, "basic", nil, nil)),
+			},
+			pInfo2: &framework.QueuedPodInfo{
+				PodInfo: testutil.MustNewPodInfo(t, makePod("p2", "p2-deployment-b", 0, "basic", nil, nil)),
+			},
+			desiredTopologyOrder: basicAppGroup.Status.TopologyOrder,
+			want:                 true,
+		},
 		{
 			name:                     "pods from different AppGroups... ",
 			agName:                   "basic",
@@ -275,6 +321,67 @@ func TestTopologicalSortLess(t *testing.T) {
 	}
 }
 
+func TestTopologicalSortDependencyCycle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// basic AppGroup with p3 depending back on p1
+	appGroup := GetAppGroupCRBasic()
+	appGroup.Spec.Workloads[2].Dependencies = agv1alpha1.DependenciesList{agv1alpha1.DependenciesInfo{
+		Workload: appGroup.Spec.Workloads[0].Workload}}
+	sort.Sort(util.ByWorkloadSelector(appGroup.Status.TopologyOrder))
+
+	s := clientgoscheme.Scheme
+	utilruntime.Must(agv1alpha1.AddToScheme(s))
+	client := fake.NewClientBuilder().
+		WithScheme(s).
+		WithStatusSubresource(&agv1alpha1.AppGroup{}).
+		Build()
+	if err := client.Create(ctx, appGroup); err != nil {
+		t.Fatalf("failed to create AppGroup CR: %v", err)
+	}
+
+	recorder := events.NewFakeRecorder(10)
+	fwk, err := tf.NewFramework(ctx, []tf.RegisterPluginFunc{
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}, "default-scheduler", frameworkruntime.WithEventRecorder(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := &TopologicalcnSort{
+		Client:     client,
+		handle:     fwk,
+		namespaces: []string{metav1.NamespaceDefault},
+	}
+
+	p1 := &framework.QueuedPodInfo{PodInfo: testutil.MustNewPodInfo(t, makePod("p1", "p1-deployment", 0, "basic", nil, nil))}
+	p3 := &framework.QueuedPodInfo{PodInfo: testutil.MustNewPodInfo(t, makePod("p3", "p3-deployment", 10, "basic", nil, nil))}
+	// p1 is before p3 in the topology order, but the cycle makes the pods sorted by priority
+	if ts.Less(p1, p3) {
+		t.Errorf("Less(p1, p3) = true, want false")
+	}
+	if !ts.Less(p3, p1) {
+		t.Errorf("Less(p3, p1) = false, want true")
+	}
+
+	// the cycle is reported once
+	select {
+	case got := <-recorder.Events:
+		want := "Warning DependencyCycle Dependencies form a cycle p1 -> p2 -> p3 -> p1"
+		if !strings.HasPrefix(got, want) {
+			t.Errorf("unexpected event %q, want prefix %q", got, want)
+		}
+	default:
+		t.Errorf("expected a DependencyCycle event, got none")
+	}
+	select {
+	case got := <-recorder.Events:
+		t.Errorf("unexpected event %q", got)
+	default:
+	}
+}
+
 func BenchmarkTopologicalSortPlugin(b *testing.B) {
 	ctx := context.TODO()
 	agName := "onlineboutique"
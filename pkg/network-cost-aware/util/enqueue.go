@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
+
+	"github.com/diktyo-io/appgroup-api/pkg/apis/appgroup"
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	"github.com/diktyo-io/networktopology-api/pkg/apis/networktopology"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+)
+
+var (
+	// AppGroupGVK : resource of the AppGroup events, following the naming convention of the custom events
+	AppGroupGVK = framework.GVK(fmt.Sprintf("appgroups.%v.%v", agv1alpha1.SchemeGroupVersion.Version, appgroup.GroupName))
+	// NetworkTopologyGVK : resource of the NetworkTopology events
+	NetworkTopologyGVK = framework.GVK(fmt.Sprintf("networktopologies.%v.%v", ntv1alpha1.SchemeGroupVersion.Version, networktopology.GroupName))
+)
+
+// AppGroupEnqueueExtensions : requeue the unschedulable pods of an AppGroup when their AppGroup changes, or when a pod
+// of their AppGroup is placed on or removed from a node, rather than on every pod event of the cluster.
+// Pods without AppGroup are only requeued by the events of the other plugins.
+// It is embedded in the plugins, which implement framework.EnqueueExtensions with it.
+type AppGroupEnqueueExtensions struct{}
+
+// EventsToRegister : events that may make a pod of an AppGroup schedulable
+func (AppGroupEnqueueExtensions) EventsToRegister(_ context.Context) ([]framework.ClusterEventWithHint, error) {
+	return []framework.ClusterEventWithHint{
+		{Event: framework.ClusterEvent{Resource: framework.Pod, ActionType: framework.Add | framework.Update | framework.Delete}, QueueingHintFn: IsSchedulableAfterPodChange},
+		{Event: framework.ClusterEvent{Resource: AppGroupGVK, ActionType: framework.All}, QueueingHintFn: IsSchedulableAfterAppGroupChange},
+		{Event: framework.ClusterEvent{Resource: NetworkTopologyGVK, ActionType: framework.All}, QueueingHintFn: isAppGroupPod},
+		{Event: framework.ClusterEvent{Resource: framework.Node, ActionType: framework.Add | framework.UpdateNodeLabel}, QueueingHintFn: isAppGroupPod},
+	}, nil
+}
+
+// IsSchedulableAfterPodChange : requeue a pod of an AppGroup when another pod of its AppGroup, in any namespace, is
+// added to, moved to, or deleted from a node, i.e. when the placement of its dependencies changes.
+func IsSchedulableAfterPodChange(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	oldPod, newPod, err := schedutil.As[*v1.Pod](oldObj, newObj)
+	if err != nil {
+		return framework.Queue, err
+	}
+	changedPod := newPod
+	if changedPod == nil {
+		changedPod = oldPod
+	}
+	agName := GetPodAppGroupLabel(pod)
+	if agName == "" || changedPod.UID == pod.UID || GetPodAppGroupLabel(changedPod) != agName {
+		logger.V(5).Info("pod change does not affect the AppGroup of the pod", "pod", klog.KObj(pod), "changedPod", klog.KObj(changedPod))
+		return framework.QueueSkip, nil
+	}
+	if oldPod != nil && newPod != nil && oldPod.Spec.NodeName == newPod.Spec.NodeName {
+		logger.V(5).Info("pod of the AppGroup updated without changing node", "pod", klog.KObj(pod), "changedPod", klog.KObj(changedPod))
+		return framework.QueueSkip, nil
+	}
+	if changedPod.Spec.NodeName == "" && (oldPod == nil || oldPod.Spec.NodeName == "") {
+		logger.V(5).Info("unassigned pod of the AppGroup changed", "pod", klog.KObj(pod), "changedPod", klog.KObj(changedPod))
+		return framework.QueueSkip, nil
+	}
+	logger.V(5).Info("placement of the AppGroup changed, pod may be schedulable", "pod", klog.KObj(pod), "changedPod", klog.KObj(changedPod), "appGroup", agName)
+	return framework.Queue, nil
+}
+
+// IsSchedulableAfterAppGroupChange : requeue a pod when its own AppGroup changes, e.g. its dependencies.
+// AppGroups are matched by name, like the plugins look them up in their namespaces.
+func IsSchedulableAfterAppGroupChange(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	obj := newObj
+	if obj == nil {
+		obj = oldObj
+	}
+	ag, err := meta.Accessor(obj)
+	if err != nil {
+		return framework.Queue, err
+	}
+	if agName := GetPodAppGroupLabel(pod); agName == "" || ag.GetName() != agName {
+		logger.V(5).Info("AppGroup change does not affect the pod", "pod", klog.KObj(pod), "appGroup", klog.KRef(ag.GetNamespace(), ag.GetName()))
+		return framework.QueueSkip, nil
+	}
+	logger.V(5).Info("AppGroup of the pod changed, pod may be schedulable", "pod", klog.KObj(pod), "appGroup", klog.KRef(ag.GetNamespace(), ag.GetName()))
+	return framework.Queue, nil
+}
+
+// isAppGroupPod : requeue a pod on the network topology changes only if it belongs to an AppGroup.
+func isAppGroupPod(logger klog.Logger, pod *v1.Pod, _, _ interface{}) (framework.QueueingHint, error) {
+	if GetPodAppGroupLabel(pod) == "" {
+		logger.V(5).Info("pod without AppGroup, network topology change does not affect it", "pod", klog.KObj(pod))
+		return framework.QueueSkip, nil
+	}
+	return framework.Queue, nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2/ktesting"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+)
+
+func makeAppGroupPod(name, agName, nodeName string) *v1.Pod {
+	p := st.MakePod().Name(name).Namespace("default").UID(name).Node(nodeName)
+	if agName != "" {
+		p = p.Label(agv1alpha1.AppGroupLabel, agName)
+	}
+	return p.Obj()
+}
+
+func makeAppGroup(namespace, name string) *unstructured.Unstructured {
+	ag := &unstructured.Unstructured{}
+	ag.SetAPIVersion(agv1alpha1.SchemeGroupVersion.String())
+	ag.SetKind("AppGroup")
+	ag.SetNamespace(namespace)
+	ag.SetName(name)
+	return ag
+}
+
+func TestIsSchedulableAfterPodChange(t *testing.T) {
+	pod := makeAppGroupPod("p1", "basic", "")
+	tests := []struct {
+		name   string
+		pod    *v1.Pod
+		oldObj interface{}
+		newObj interface{}
+		want   framework.QueueingHint
+	}{
+		{
+			name:   "pod of the AppGroup assigned",
+			pod:    pod,
+			oldObj: makeAppGroupPod("p2", "basic", ""),
+			newObj: makeAppGroupPod("p2", "basic", "n1"),
+			want:   framework.Queue,
+		},
+		{
+			name:   "assigned pod of the AppGroup added",
+			pod:    pod,
+			newObj: makeAppGroupPod("p2", "basic", "n1"),
+			want:   framework.Queue,
+		},
+		{
+			name:   "assigned pod of the AppGroup deleted",
+			pod:    pod,
+			oldObj: makeAppGroupPod("p2", "basic", "n1"),
+			want:   framework.Queue,
+		},
+		{
+			name:   "unassigned pod of the AppGroup added",
+			pod:    pod,
+			newObj: makeAppGroupPod("p2", "basic", ""),
+			want:   framework.QueueSkip,
+		},
+		{
+			name:   "assigned pod of the AppGroup updated on its node",
+			pod:    pod,
+			oldObj: makeAppGroupPod("p2", "basic", "n1"),
+			newObj: makeAppGroupPod("p2", "basic", "n1"),
+			want:   framework.QueueSkip,
+		},
+		{
+			name:   "pod of another AppGroup assigned",
+			pod:    pod,
+			oldObj: makeAppGroupPod("p2", "other", ""),
+			newObj: makeAppGroupPod("p2", "other", "n1"),
+			want:   framework.QueueSkip,
+		},
+		{
+			name:   "pod without AppGroup assigned",
+			pod:    pod,
+			oldObj: makeAppGroupPod("p2", "", ""),
+			newObj: makeAppGroupPod("p2", "", "n1"),
+			want:   framework.QueueSkip,
+		},
+		{
+			name:   "pod itself updated",
+			pod:    pod,
+			oldObj: pod,
+			newObj: makeAppGroupPod("p1", "basic", "n1"),
+			want:   framework.QueueSkip,
+		},
+		{
+			name:   "pod without AppGroup",
+			pod:    makeAppGroupPod("p1", "", ""),
+			newObj: makeAppGroupPod("p2", "basic", "n1"),
+			want:   framework.QueueSkip,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := ktesting.NewTestContext(t)
+			got, err := IsSchedulableAfterPodChange(logger, tt.pod, tt.oldObj, tt.newObj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsSchedulableAfterAppGroupChange(t *testing.T) {
+	logger, _ := ktesting.NewTestContext(t)
+	pod := makeAppGroupPod("p1", "basic", "")
+	tests := []struct {
+		name   string
+		pod    *v1.Pod
+		oldObj interface{}
+		newObj interface{}
+		want   framework.QueueingHint
+	}{
+		{
+			name:   "AppGroup of the pod updated",
+			pod:    pod,
+			oldObj: makeAppGroup("apps", "basic"),
+			newObj: makeAppGroup("apps", "basic"),
+			want:   framework.Queue,
+		},
+		{
+			name:   "AppGroup of the pod created",
+			pod:    pod,
+			newObj: &agv1alpha1.AppGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "basic"}},
+			want:   framework.Queue,
+		},
+		{
+			name:   "AppGroup of the pod deleted",
+			pod:    pod,
+			oldObj: makeAppGroup("apps", "basic"),
+			want:   framework.Queue,
+		},
+		{
+			name:   "another AppGroup updated",
+			pod:    pod,
+			oldObj: makeAppGroup("apps", "other"),
+			newObj: makeAppGroup("apps", "other"),
+			want:   framework.QueueSkip,
+		},
+		{
+			name:   "pod without AppGroup",
+			pod:    makeAppGroupPod("p1", "", ""),
+			newObj: makeAppGroup("apps", "basic"),
+			want:   framework.QueueSkip,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsSchedulableAfterAppGroupChange(logger, tt.pod, tt.oldObj, tt.newObj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestAppGroupEnqueueExtensionsChurn checks that the pod churn of the other AppGroups, and of the pods without
+// AppGroup, requeues none of the unschedulable pods of an AppGroup, while assigning one of its pods requeues them.
+func TestAppGroupEnqueueExtensionsChurn(t *testing.T) {
+	logger, ctx := ktesting.NewTestContext(t)
+	events, err := AppGroupEnqueueExtensions{}.EventsToRegister(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var podHint framework.QueueingHintFn
+	for _, e := range events {
+		if e.Event.Resource == framework.Pod {
+			podHint = e.QueueingHintFn
+		}
+	}
+	if podHint == nil {
+		t.Fatal("expected a queueing hint for the pod events")
+	}
+
+	var unschedulable []*v1.Pod
+	for i := 0; i < 10; i++ {
+		unschedulable = append(unschedulable, makeAppGroupPod(fmt.Sprintf("basic-%d", i), "basic", ""))
+	}
+	requeued := func(oldObj, newObj interface{}) int {
+		n := 0
+		for _, pod := range unschedulable {
+			hint, err := podHint(logger, pod, oldObj, newObj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hint == framework.Queue {
+				n++
+			}
+		}
+		return n
+	}
+
+	for i := 0; i < 100; i++ {
+		agName := []string{"", "other"}[i%2]
+		name := fmt.Sprintf("churn-%d", i)
+		created := makeAppGroupPod(name, agName, "")
+		assigned := makeAppGroupPod(name, agName, fmt.Sprintf("n%d", i%3))
+		for _, change := range [][2]interface{}{{nil, created}, {created, assigned}, {assigned, assigned}, {assigned, nil}} {
+			if n := requeued(change[0], change[1]); n != 0 {
+				t.Fatalf("expected no requeue on the churn of pod %v of AppGroup %q, got %d", name, agName, n)
+			}
+		}
+	}
+
+	if n := requeued(makeAppGroupPod("basic-db", "basic", ""), makeAppGroupPod("basic-db", "basic", "n1")); n != len(unschedulable) {
+		t.Errorf("expected the %d pods of the AppGroup to be requeued, got %d", len(unschedulable), n)
+	}
+}
+
+func TestAppGroupEnqueueExtensionsEvents(t *testing.T) {
+	events, err := AppGroupEnqueueExtensions{}.EventsToRegister(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[framework.GVK]bool{
+		framework.Pod:  true,
+		framework.Node: true,
+		"appgroups.v1alpha1.appgroup.diktyo.x-k8s.io":                true,
+		"networktopologies.v1alpha1.networktopology.diktyo.x-k8s.io": true,
+	}
+	for _, e := range events {
+		if !want[e.Event.Resource] {
+			t.Errorf("unexpected event on %v", e.Event.Resource)
+		}
+		if e.QueueingHintFn == nil {
+			t.Errorf("expected a queueing hint for the events on %v", e.Event.Resource)
+		}
+		delete(want, e.Event.Resource)
+	}
+	if len(want) != 0 {
+		t.Errorf("expected events on %v", want)
+	}
+}
@@ -17,12 +17,25 @@ limitations under the License.
 package util
 
 import (
+	"sort"
+
 	v1 "k8s.io/api/core/v1"
 
 	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
 	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
 )
 
+// NetworkTopologyCluster : topology key of the costs between clusters in a NetworkTopology.
+// Nodes labeled with it belong to, or represent (e.g., virtual nodes), the given cluster.
+const NetworkTopologyCluster ntv1alpha1.TopologyKey = "networktopology.diktyo.x-k8s.io/cluster"
+
+// NetworkTopologyNode : topology key of the costs between nodes in a NetworkTopology, e.g. on bare metal clusters
+// without zone labels. Its origins and destinations are node names, or racks for the nodes labeled with NodeRackLabel.
+const NetworkTopologyNode ntv1alpha1.TopologyKey = "networktopology.diktyo.x-k8s.io/node"
+
+// NodeRackLabel : label of the nodes giving their rack, their location for the costs of NetworkTopologyNode.
+const NodeRackLabel = "networktopology.diktyo.x-k8s.io/rack"
+
 // CostKey : key for map concerning network costs (origin / destinations)
 type CostKey struct {
 	Origin      string
@@ -64,6 +77,24 @@ func GetNodeZone(node *v1.Node) string {
 	return labels[v1.LabelTopologyZone]
 }
 
+// GetNodeLocation : return the location of the node for the costs of NetworkTopologyNode, its rack if labeled with one
+// and its name otherwise
+func GetNodeLocation(node *v1.Node) string {
+	if rack := node.Labels[NodeRackLabel]; rack != "" {
+		return rack
+	}
+	return node.Name
+}
+
+// GetNodeCluster : return the cluster of the node
+func GetNodeCluster(node *v1.Node) string {
+	labels := node.Labels
+	if labels == nil {
+		return ""
+	}
+	return labels[string(NetworkTopologyCluster)]
+}
+
 // GetPodAppGroupLabel : get AppGroup from pod annotations
 func GetPodAppGroupLabel(pod *v1.Pod) string {
 	return pod.Labels[agv1alpha1.AppGroupLabel]
@@ -211,6 +242,62 @@ func GetDependencyList(pod *v1.Pod, ag *agv1alpha1.AppGroup) []agv1alpha1.Depend
 	return dependencyList
 }
 
+// FindDependencyCycle : return a cycle of the workload dependencies established in the AppGroup CR, as the selectors
+// of the workloads along the cycle with the first one repeated at the end, or nil if the dependencies form a DAG.
+// Workloads are visited by selector, so the same cycle is returned for the same AppGroup.
+func FindDependencyCycle(ag *agv1alpha1.AppGroup) []string {
+	dependencies := make(map[string][]string)
+	for _, w := range ag.Spec.Workloads {
+		for _, dependency := range w.Dependencies {
+			dependencies[w.Workload.Selector] = append(dependencies[w.Workload.Selector], dependency.Workload.Selector)
+		}
+	}
+	selectors := make([]string, 0, len(dependencies))
+	for selector := range dependencies {
+		selectors = append(selectors, selector)
+		sort.Strings(dependencies[selector])
+	}
+	sort.Strings(selectors)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(selector string) []string
+	visit = func(selector string) []string {
+		switch state[selector] {
+		case visited:
+			return nil
+		case visiting:
+			// the selector is on the path: the cycle goes from it to the end of the path
+			for i, s := range path {
+				if s == selector {
+					return append(append([]string{}, path[i:]...), selector)
+				}
+			}
+		}
+		state[selector] = visiting
+		path = append(path, selector)
+		for _, dependency := range dependencies[selector] {
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[selector] = visited
+		return nil
+	}
+	for _, selector := range selectors {
+		if cycle := visit(selector); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
 // GetScheduledList : get Pods already scheduled in the cluster for that specific AppGroup
 func GetScheduledList(pods []*v1.Pod) ScheduledList {
 	// scheduledList: Deployment name, replicaID, hostname
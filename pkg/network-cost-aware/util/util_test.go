@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/fixture"
+)
+
+func FuzzFindOriginCosts(f *testing.F) {
+	f.Add(int64(1), uint8(1), uint8(1), "r0")
+	f.Add(int64(2), uint8(5), uint8(10), "r4-z9")
+	f.Add(int64(3), uint8(3), uint8(2), "r9")
+	f.Add(int64(4), uint8(0), uint8(0), "")
+	f.Fuzz(func(t *testing.T, seed int64, regions, zones uint8, origin string) {
+		scale := fixture.Scale{Regions: int(regions % 16), ZonesPerRegion: int(zones % 16), NodesPerZone: 1}
+		nt := fixture.NewNetworkTopology("nt", "default", scale, rand.New(rand.NewSource(seed)))
+		topologyList := nt.Spec.Weights[0].TopologyList
+		sort.Sort(ByTopologyKey(topologyList))
+
+		for _, key := range []ntv1alpha1.TopologyKey{ntv1alpha1.NetworkTopologyRegion, ntv1alpha1.NetworkTopologyZone} {
+			originList := FindTopologyKey(topologyList, key)
+			// Linear search on the origins as defined, before sorting them
+			want := []ntv1alpha1.CostInfo{}
+			for _, o := range originList {
+				if o.Origin == origin {
+					want = []ntv1alpha1.CostInfo(o.CostList)
+				}
+			}
+			sort.Sort(ByOrigin(originList))
+			if got := FindOriginCosts(originList, origin); !reflect.DeepEqual(got, want) {
+				t.Errorf("%v: expected the costs %v from %q, got %v", key, want, origin, got)
+			}
+			// Every origin is found
+			for _, o := range originList {
+				if got := FindOriginCosts(originList, o.Origin); !reflect.DeepEqual(got, []ntv1alpha1.CostInfo(o.CostList)) {
+					t.Errorf("%v: expected the costs %v from %q, got %v", key, o.CostList, o.Origin, got)
+				}
+			}
+		}
+	})
+}
+
+func TestFindDependencyCycle(t *testing.T) {
+	// makeAppGroup returns an AppGroup with the dependencies given per workload selector
+	makeAppGroup := func(dependencies map[string][]string) *agv1alpha1.AppGroup {
+		ag := &agv1alpha1.AppGroup{}
+		for selector, deps := range dependencies {
+			w := agv1alpha1.AppGroupWorkload{Workload: agv1alpha1.AppGroupWorkloadInfo{Selector: selector}}
+			for _, dep := range deps {
+				w.Dependencies = append(w.Dependencies, agv1alpha1.DependenciesInfo{Workload: agv1alpha1.AppGroupWorkloadInfo{Selector: dep}})
+			}
+			ag.Spec.Workloads = append(ag.Spec.Workloads, w)
+		}
+		return ag
+	}
+	tests := []struct {
+		name         string
+		dependencies map[string][]string
+		want         []string
+	}{
+		{
+			name:         "no dependencies",
+			dependencies: map[string][]string{"p1": nil, "p2": nil},
+		},
+		{
+			name:         "DAG",
+			dependencies: map[string][]string{"p1": {"p2", "p3"}, "p2": {"p3"}, "p3": nil},
+		},
+		{
+			name:         "dependency on itself",
+			dependencies: map[string][]string{"p1": {"p2"}, "p2": {"p2"}},
+			want:         []string{"p2", "p2"},
+		},
+		{
+			name:         "cycle reached from a workload outside of it",
+			dependencies: map[string][]string{"p0": {"p2"}, "p2": {"p3"}, "p3": {"p4"}, "p4": {"p2"}},
+			want:         []string{"p2", "p3", "p4", "p2"},
+		},
+		{
+			name:         "first of several cycles by selector",
+			dependencies: map[string][]string{"p1": {"p9", "p2"}, "p2": {"p1"}, "p8": {"p9"}, "p9": {"p8"}},
+			want:         []string{"p1", "p2", "p1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindDependencyCycle(makeAppGroup(tt.dependencies)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected the cycle %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkoverhead
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+
+	networkawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/util"
+)
+
+// CenterOfMassCost : cost added in the CenterOfMass scoring mode to the nodes outside the center of mass zone
+const CenterOfMassCost = MaxCost
+
+// topologyZone : a zone of a region
+type topologyZone struct {
+	region string
+	zone   string
+}
+
+// dependencyWeight : the weight of a dependency is its minBandwidth in Mbit/s, at least 1
+func dependencyWeight(d agv1alpha1.DependenciesInfo) int64 {
+	return max(d.MinBandwidth.ScaledValue(resource.Mega), 1)
+}
+
+// getCenterOfMass : get the zone holding the largest weight of scheduled replicas of the pod's dependencies,
+// i.e. the zone where the AppGroup converges. Ties are broken by region and zone name.
+// Returns false if no replica of the dependencies is scheduled on a node with a zone.
+func (no *NetworkOverhead) getCenterOfMass(
+	logger klog.Logger,
+	scheduledList networkawareutil.ScheduledList,
+	dependencyList []agv1alpha1.DependenciesInfo) (topologyZone, bool) {
+	weights := make(map[topologyZone]int64)
+	for _, podAllocated := range scheduledList {
+		for _, d := range dependencyList {
+			if podAllocated.Selector != d.Workload.Selector {
+				continue
+			}
+			podNodeInfo, err := no.handle.SnapshotSharedLister().NodeInfos().Get(podAllocated.Hostname)
+			if err != nil || podNodeInfo == nil || podNodeInfo.Node() == nil {
+				logger.V(6).Info("Ignoring replica on a node missing from Snapshot", "pod", podAllocated.Name, "node", podAllocated.Hostname)
+				continue
+			}
+			zone := networkawareutil.GetNodeZone(podNodeInfo.Node())
+			if zone == "" {
+				continue
+			}
+			weights[topologyZone{region: networkawareutil.GetNodeRegion(podNodeInfo.Node()), zone: zone}] += dependencyWeight(d)
+		}
+	}
+
+	var center topologyZone
+	var centerWeight int64
+	for z, w := range weights {
+		if w > centerWeight || (w == centerWeight && (z.region < center.region || (z.region == center.region && z.zone < center.zone))) {
+			center, centerWeight = z, w
+		}
+	}
+	logger.V(6).Info("AppGroup center of mass", "region", center.region, "zone", center.zone, "weight", centerWeight)
+	return center, centerWeight > 0
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkoverhead
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/informers"
+	testClientSet "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	schedruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	networkawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/util"
+)
+
+func makeZonedNode(name, region, zone string) *v1.Node {
+	node := st.MakeNode().Name(name).Capacity(
+		map[v1.ResourceName]string{v1.ResourceCPU: "8000m", v1.ResourceMemory: "16Gi"}).Obj()
+	node.Labels = map[string]string{}
+	if region != "" {
+		node.Labels[v1.LabelTopologyRegion] = region
+	}
+	if zone != "" {
+		node.Labels[v1.LabelTopologyZone] = zone
+	}
+	return node
+}
+
+func makeDependency(selector, minBandwidth string) agv1alpha1.DependenciesInfo {
+	d := agv1alpha1.DependenciesInfo{
+		Workload: agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: selector + "-deployment", Selector: selector, APIVersion: "apps/v1", Namespace: "default"},
+	}
+	if minBandwidth != "" {
+		d.MinBandwidth = resource.MustParse(minBandwidth)
+	}
+	return d
+}
+
+func TestGetCenterOfMass(t *testing.T) {
+	nodes := []*v1.Node{
+		makeZonedNode("n-1", "us-west-1", "Z1"),
+		makeZonedNode("n-2", "us-west-1", "Z1"),
+		makeZonedNode("n-3", "us-west-1", "Z2"),
+		makeZonedNode("n-4", "us-east-1", "Z3"),
+		makeZonedNode("n-5", "", ""),
+	}
+
+	tests := []struct {
+		name           string
+		scheduledList  networkawareutil.ScheduledList
+		dependencyList []agv1alpha1.DependenciesInfo
+		wantCenter     topologyZone
+		wantOk         bool
+	}{
+		{
+			name: "zone with the most replicas of the dependencies",
+			scheduledList: networkawareutil.ScheduledList{
+				{Name: "p2-1", Selector: "p2", Hostname: "n-1"},
+				{Name: "p2-2", Selector: "p2", Hostname: "n-2"},
+				{Name: "p2-3", Selector: "p2", Hostname: "n-4"},
+				{Name: "p4-1", Selector: "p4", Hostname: "n-4"},
+				{Name: "p4-2", Selector: "p4", Hostname: "n-4"},
+			},
+			dependencyList: []agv1alpha1.DependenciesInfo{makeDependency("p2", "")},
+			wantCenter:     topologyZone{region: "us-west-1", zone: "Z1"},
+			wantOk:         true,
+		},
+		{
+			name: "replicas weighted by the minBandwidth of their dependency",
+			scheduledList: networkawareutil.ScheduledList{
+				{Name: "p2-1", Selector: "p2", Hostname: "n-1"},
+				{Name: "p2-2", Selector: "p2", Hostname: "n-2"},
+				{Name: "p3-1", Selector: "p3", Hostname: "n-3"},
+			},
+			dependencyList: []agv1alpha1.DependenciesInfo{makeDependency("p2", "100M"), makeDependency("p3", "250M")},
+			wantCenter:     topologyZone{region: "us-west-1", zone: "Z2"},
+			wantOk:         true,
+		},
+		{
+			name: "ties broken by region and zone name",
+			scheduledList: networkawareutil.ScheduledList{
+				{Name: "p2-1", Selector: "p2", Hostname: "n-3"},
+				{Name: "p2-2", Selector: "p2", Hostname: "n-4"},
+			},
+			dependencyList: []agv1alpha1.DependenciesInfo{makeDependency("p2", "")},
+			wantCenter:     topologyZone{region: "us-east-1", zone: "Z3"},
+			wantOk:         true,
+		},
+		{
+			name: "replicas on nodes without zone or missing from the snapshot",
+			scheduledList: networkawareutil.ScheduledList{
+				{Name: "p2-1", Selector: "p2", Hostname: "n-5"},
+				{Name: "p2-2", Selector: "p2", Hostname: "n-unknown"},
+			},
+			dependencyList: []agv1alpha1.DependenciesInfo{makeDependency("p2", "")},
+			wantOk:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, err := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+				schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			pl := &NetworkOverhead{handle: fh}
+
+			center, ok := pl.getCenterOfMass(klog.FromContext(ctx), tt.scheduledList, tt.dependencyList)
+			if ok != tt.wantOk {
+				t.Fatalf("expected ok %v, got %v", tt.wantOk, ok)
+			}
+			if ok && center != tt.wantCenter {
+				t.Errorf("expected center of mass %+v, got %+v", tt.wantCenter, center)
+			}
+		})
+	}
+}
+
+func TestNetworkOverheadPreFilterCenterOfMass(t *testing.T) {
+	basicAppGroup := GetAppGroupCRBasic()
+	networkTopology := GetNetworkTopologyCRBasic()
+	nodes := []*v1.Node{
+		makeZonedNode("n-1", "us-west-1", "Z1"),
+		makeZonedNode("n-2", "us-west-1", "Z1"),
+		makeZonedNode("n-3", "us-west-1", "Z2"),
+		makeZonedNode("n-4", "us-east-1", "Z3"),
+	}
+	pods := []*v1.Pod{
+		makePodAllocated("p2", "p2-deployment-1", "n-3", 0, "basic", nil, nil),
+		makePodAllocated("p2", "p2-deployment-2", "n-3", 0, "basic", nil, nil),
+		makePodAllocated("p2", "p2-deployment-3", "n-1", 0, "basic", nil, nil),
+	}
+	pod := makePod("p1", "p1-deployment", 0, "basic", nil, nil)
+
+	preFilter := func(t *testing.T, scoringMode pluginconfig.NetworkOverheadScoringMode) map[string]int64 {
+		s := clientgoscheme.Scheme
+		utilruntime.Must(agv1alpha1.AddToScheme(s))
+		utilruntime.Must(ntv1alpha1.AddToScheme(s))
+
+		ctx := context.Background()
+		client := fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(basicAppGroup.DeepCopy(), networkTopology.DeepCopy()).
+			Build()
+
+		cs := testClientSet.NewSimpleClientset()
+		informerFactory := informers.NewSharedInformerFactory(cs, 0)
+		podInformer := informerFactory.Core().V1().Pods()
+		for _, p := range pods {
+			if err := podInformer.Informer().GetStore().Add(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		registeredPlugins := []tf.RegisterPluginFunc{
+			tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		}
+		fh, err := tf.NewFramework(ctx, registeredPlugins, "default-scheduler", schedruntime.WithClientSet(cs),
+			schedruntime.WithInformerFactory(informerFactory), schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pl := &NetworkOverhead{
+			Client:      client,
+			podLister:   podInformer.Lister(),
+			handle:      fh,
+			namespaces:  []string{"default"},
+			weightsName: "UserDefined",
+			ntName:      "nt-test",
+
+			zoneViolationWeight:   1,
+			regionViolationWeight: 1,
+			scoringMode:           scoringMode,
+		}
+
+		state := framework.NewCycleState()
+		if _, status := pl.PreFilter(ctx, state, pod); !status.IsSuccess() {
+			t.Fatalf("unexpected PreFilter status: %v", status)
+		}
+		preFilterState, err := getPreFilterState(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return preFilterState.finalCostMap
+	}
+
+	accumulated := preFilter(t, pluginconfig.ScoringModeAccumulatedCost)
+	centerOfMass := preFilter(t, pluginconfig.ScoringModeCenterOfMass)
+
+	// Two of the three p2 replicas run in Z2: only n-3 is in the center of mass.
+	for _, n := range nodes {
+		want := accumulated[n.Name]
+		if n.Name != "n-3" {
+			want += CenterOfMassCost
+		}
+		if got := centerOfMass[n.Name]; got != want {
+			t.Errorf("node %v: expected cost %v, got %v", n.Name, want, got)
+		}
+	}
+}
@@ -105,6 +105,13 @@ type NetworkOverhead struct {
 	namespaces  []string
 	weightsName string
 	ntName      string
+
+	// weights of violated dependencies, cross-region violations may weigh more than cross-zone ones
+	zoneViolationWeight   int64
+	regionViolationWeight int64
+
+	// scoring mode: AccumulatedCost or CenterOfMass
+	scoringMode pluginconfig.NetworkOverheadScoringMode
 }
 
 // PreFilterState computed at PreFilter and used at Filter and Score.
@@ -175,6 +182,10 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if err != nil {
 		return nil, err
 	}
+	if args.ScoringMode != pluginconfig.ScoringModeAccumulatedCost && args.ScoringMode != pluginconfig.ScoringModeCenterOfMass {
+		return nil, fmt.Errorf("invalid scoringMode %q, want %q or %q",
+			args.ScoringMode, pluginconfig.ScoringModeAccumulatedCost, pluginconfig.ScoringModeCenterOfMass)
+	}
 	client, err := client.New(handle.KubeConfig(), client.Options{
 		Scheme: scheme,
 	})
@@ -190,6 +201,10 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		namespaces:  args.Namespaces,
 		weightsName: args.WeightsName,
 		ntName:      args.NetworkTopologyName,
+
+		zoneViolationWeight:   args.ZoneViolationWeight,
+		regionViolationWeight: args.RegionViolationWeight,
+		scoringMode:           args.ScoringMode,
 	}
 	return no, nil
 }
@@ -201,6 +216,8 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 // 4. Update cost map of all nodes
 // 5. Get number of satisfied and violated dependencies
 // 6. Get final cost of the given node to be used in the score plugin
+// In the CenterOfMass scoring mode, the nodes outside the zone holding most of the weight
+// of the scheduled dependencies get an extra CenterOfMassCost, so that the AppGroup converges faster.
 func (no *NetworkOverhead) PreFilter(ctx context.Context, state *framework.CycleState, pod *corev1.Pod) (*framework.PreFilterResult, *framework.Status) {
 	// Init PreFilter State
 	preFilterState := &PreFilterState{
@@ -268,6 +285,13 @@ func (no *NetworkOverhead) PreFilter(ctx context.Context, state *framework.Cycle
 	nodeInfoMap := make(map[string]*framework.NodeInfo) //Amira
 	
 
+	// Get the center of mass of the scheduled dependencies
+	var center topologyZone
+	hasCenter := false
+	if no.scoringMode == pluginconfig.ScoringModeCenterOfMass {
+		center, hasCenter = no.getCenterOfMass(logger, scheduledList, dependencyList)
+	}
+
 	// For each node:
 	// 1 - Get region and zone labels
 	// 2 - Calculate satisfied and violated number of dependencies
@@ -309,6 +333,9 @@ func (no *NetworkOverhead) PreFilter(ctx context.Context, state *framework.Cycle
 		if ok != nil {
 			return nil, framework.NewStatus(framework.Error, fmt.Sprintf("getting pod hostname from Snapshot: %v", ok))
 		}
+		if hasCenter && (region != center.region || zone != center.zone) {
+			cost += CenterOfMassCost
+		}
 		logger.V(6).Info("Node final cost", "cost", cost)
 		finalCostMap[nodeInfo.Node().Name] = cost
 	}
@@ -453,7 +480,7 @@ func (no *NetworkOverhead) Filter(ctx context.Context,
     violated := preFilterState.violatedMap[nodeInfo.Node().Name]
     klog.V(6).InfoS("Number of dependencies:", "satisfied", satisfied, "violated", violated)
 
-    // The pod is filtered out if the number of violated dependencies is higher than the satisfied ones
+    // The pod is filtered out if the weighted number of violated dependencies is higher than the satisfied ones
     if violated > satisfied {
         return framework.NewStatus(framework.Unschedulable,
             fmt.Sprintf("Node %v does not meet several network requirements from Workload dependencies: Satisfied: %v Violated: %v", nodeInfo.Node().Name, satisfied, violated))
@@ -665,6 +692,7 @@ func (no *NetworkOverhead) populateCostMap(
 }
 
 // checkMaxNetworkCostRequirements : verifies the number of met and unmet dependencies based on the pod being filtered
+// Unmet dependencies are weighted by the distance between the nodes: zoneViolationWeight across zones, regionViolationWeight across regions
 func checkMaxNetworkCostRequirements(
 	logger klog.Logger,
 	scheduledList networkawareutil.ScheduledList,
@@ -704,7 +732,7 @@ func checkMaxNetworkCostRequirements(
 				zonePodNodeInfo := networkawareutil.GetNodeZone(podNodeInfo.Node())
 
 				if regionPodNodeInfo == "" && zonePodNodeInfo == "" { // Node has no zone and region defined
-					violated += 1
+					violated += no.regionViolationWeight
 				} else if region == regionPodNodeInfo { // If Nodes belong to the same region
 					if zone == zonePodNodeInfo { // If Nodes belong to the same zone
 						satisfied += 1
@@ -717,7 +745,7 @@ func checkMaxNetworkCostRequirements(
 							if cost <= d.MaxNetworkCost {
 								satisfied += 1
 							} else {
-								violated += 1
+								violated += no.zoneViolationWeight
 							}
 						}
 					}
@@ -730,7 +758,7 @@ func checkMaxNetworkCostRequirements(
 						if cost <= d.MaxNetworkCost {
 							satisfied += 1
 						} else {
-							violated += 1
+							violated += no.regionViolationWeight
 						}
 					}
 				}
@@ -741,6 +769,7 @@ func checkMaxNetworkCostRequirements(
 }
 
 // getAccumulatedCost : calculate the accumulated cost based on the Pod's dependencies
+// The cost of a dependency violating its maxNetworkCost is weighted as in checkMaxNetworkCostRequirements
 func (no *NetworkOverhead) getAccumulatedCost(
 	logger klog.Logger,
 	scheduledList networkawareutil.ScheduledList,
@@ -774,7 +803,7 @@ func (no *NetworkOverhead) getAccumulatedCost(
 				zonePodNodeInfo := networkawareutil.GetNodeZone(podNodeInfo.Node())
 
 				if regionPodNodeInfo == "" && zonePodNodeInfo == "" { // Node has no zone and region defined
-					cost += MaxCost
+					cost += MaxCost * no.regionViolationWeight
 				} else if region == regionPodNodeInfo { // If Nodes belong to the same region
 					if zone == zonePodNodeInfo { // If Nodes belong to the same zone
 						cost += SameZone
@@ -784,7 +813,7 @@ func (no *NetworkOverhead) getAccumulatedCost(
 							Destination: zonePodNodeInfo,
 						}]
 						if ok {
-							cost += value // Add the cost to the sum
+							cost += no.weightViolation(value, d.MaxNetworkCost, no.zoneViolationWeight) // Add the cost to the sum
 						} else {
 							cost += MaxCost
 						}
@@ -795,7 +824,7 @@ func (no *NetworkOverhead) getAccumulatedCost(
 						Destination: regionPodNodeInfo,
 					}]
 					if ok {
-						cost += value // Add the cost to the sum
+						cost += no.weightViolation(value, d.MaxNetworkCost, no.regionViolationWeight) // Add the cost to the sum
 					} else {
 						cost += MaxCost
 					}
@@ -806,6 +835,14 @@ func (no *NetworkOverhead) getAccumulatedCost(
 	return cost, nil
 }
 
+// weightViolation : weight the cost of a dependency if it violates its maxNetworkCost
+func (no *NetworkOverhead) weightViolation(cost int64, maxNetworkCost int64, weight int64) int64 {
+	if cost <= maxNetworkCost {
+		return cost
+	}
+	return cost * weight
+}
+
 func getPreFilterState(cycleState *framework.CycleState) (*PreFilterState, error) {
 	no, err := cycleState.Read(preFilterStateKey)
 	if err != nil {
@@ -545,6 +545,9 @@ func BenchmarkNetworkOverheadPreFilter(b *testing.B) {
 				namespaces:  []string{"default"},
 				weightsName: "UserDefined",
 				ntName:      "nt-test",
+
+				zoneViolationWeight:   1,
+				regionViolationWeight: 1,
 			}
 
 			state := framework.NewCycleState()
@@ -765,6 +768,9 @@ func TestNetworkOverheadScore(t *testing.T) {
 				namespaces:  []string{"default"},
 				weightsName: "UserDefined",
 				ntName:      "nt-test",
+
+				zoneViolationWeight:   1,
+				regionViolationWeight: 1,
 			}
 
 			// Wait for the pods to be scheduled.
@@ -1012,6 +1018,9 @@ func BenchmarkNetworkOverheadScore(b *testing.B) {
 				namespaces:  []string{"default"},
 				weightsName: "UserDefined",
 				ntName:      "nt-test",
+
+				zoneViolationWeight:   1,
+				regionViolationWeight: 1,
 			}
 
 			state := framework.NewCycleState()
@@ -1092,6 +1101,9 @@ func TestNetworkOverheadFilter(t *testing.T) {
 		nodeToFilter    *v1.Node
 		wantStatus      *framework.Status
 		expected        framework.Code
+
+		zoneViolationWeight   int64
+		regionViolationWeight int64
 	}{
 		{
 			name:            "AppGroup: basic, p1 to allocate, n-1 to filter: n-1 does not meet network requirements",
@@ -1189,6 +1201,34 @@ func TestNetworkOverheadFilter(t *testing.T) {
 			pods:            pods,
 			expected:        framework.Success,
 		},
+		{
+			name:                  "AppGroup: basic, p1 to allocate, n-1 to filter, weighted regional violation",
+			agName:                "basic",
+			appGroup:              basicAppGroup,
+			networkTopology:       networkTopology,
+			pod:                   makePod("p1", "p1-deployment", 0, "basic", nil, nil),
+			nodes:                 nodes,
+			wantStatus:            framework.NewStatus(framework.Unschedulable, "Node n-1 does not meet several network requirements from Workload dependencies: Satisfied: 0 Violated: 3"),
+			nodeToFilter:          nodes[0],
+			pods:                  pods,
+			expected:              framework.Success,
+			zoneViolationWeight:   2,
+			regionViolationWeight: 3,
+		},
+		{
+			name:                  "AppGroup: basic, p2 to allocate, n-5 to filter, weighted zonal violation",
+			agName:                "basic",
+			appGroup:              basicAppGroup,
+			networkTopology:       networkTopology,
+			pod:                   makePod("p2", "p2-deployment", 0, "basic", nil, nil),
+			nodes:                 nodes,
+			wantStatus:            framework.NewStatus(framework.Unschedulable, "Node n-5 does not meet several network requirements from Workload dependencies: Satisfied: 0 Violated: 2"),
+			nodeToFilter:          nodes[4],
+			pods:                  pods,
+			expected:              framework.Success,
+			zoneViolationWeight:   2,
+			regionViolationWeight: 3,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1243,6 +1283,15 @@ func TestNetworkOverheadFilter(t *testing.T) {
 				namespaces:  []string{"default"},
 				weightsName: "UserDefined",
 				ntName:      "nt-test",
+
+				zoneViolationWeight:   1,
+				regionViolationWeight: 1,
+			}
+			if tt.zoneViolationWeight > 0 {
+				pl.zoneViolationWeight = tt.zoneViolationWeight
+			}
+			if tt.regionViolationWeight > 0 {
+				pl.regionViolationWeight = tt.regionViolationWeight
 			}
 
 			// Wait for the pods to be scheduled.
@@ -1469,6 +1518,9 @@ func BenchmarkNetworkOverheadFilter(b *testing.B) {
 				namespaces:  []string{"default"},
 				weightsName: "UserDefined",
 				ntName:      "nt-test",
+
+				zoneViolationWeight:   1,
+				regionViolationWeight: 1,
 			}
 
 			// Wait for the pods to be scheduled.
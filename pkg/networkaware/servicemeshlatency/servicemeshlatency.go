@@ -0,0 +1,425 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicemeshlatency
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	networkawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/util"
+)
+
+var _ framework.PreFilterPlugin = &ServiceMeshLatency{}
+var _ framework.FilterPlugin = &ServiceMeshLatency{}
+var _ framework.ScorePlugin = &ServiceMeshLatency{}
+
+const (
+	// Name : name of plugin used in the plugin registry and configurations.
+	Name = "ServiceMeshLatency"
+
+	// MaxCost : cost of a dependency whose latency is not reported by the service mesh
+	MaxCost = 100
+
+	// SameHostname : If pods belong to the same host, then consider cost as 0
+	SameHostname = 0
+
+	// SameZone : If pods belong to hosts in the same zone, then consider cost as 1
+	SameZone = 1
+
+	// preFilterStateKey is the key in CycleState to ServiceMeshLatency pre-computed data.
+	preFilterStateKey = "PreFilter" + Name
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+	utilruntime.Must(agv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(ntv1alpha1.AddToScheme(scheme))
+}
+
+// ServiceMeshLatency : Filter and Score nodes based on Pod's AppGroup requirements, using the p99 latencies between
+// workloads reported by the service mesh as network costs when the NetworkTopology CR defines no weights
+type ServiceMeshLatency struct {
+	client.Client
+
+	podLister   corelisters.PodLister
+	handle      framework.Handle
+	namespaces  []string
+	weightsName string
+	ntName      string
+	collector   *LatencyCollector
+}
+
+// PreFilterState computed at PreFilter and used at Filter and Score.
+type PreFilterState struct {
+	// boolean that tells the filter and scoring functions to pass the pod
+	scoreEqually bool
+
+	// node map for satisfied dependencies
+	satisfiedMap map[string]int64
+
+	// node map for violated dependencies
+	violatedMap map[string]int64
+
+	// node map for costs
+	finalCostMap map[string]int64
+}
+
+// Clone the preFilter state.
+func (s *PreFilterState) Clone() framework.StateData {
+	return s
+}
+
+// Name : returns name of the plugin.
+func (sml *ServiceMeshLatency) Name() string {
+	return Name
+}
+
+func getArgs(obj runtime.Object) (*pluginconfig.ServiceMeshLatencyArgs, error) {
+	args, ok := obj.(*pluginconfig.ServiceMeshLatencyArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type ServiceMeshLatencyArgs, got %T", obj)
+	}
+	return args, nil
+}
+
+// New : create an instance of a ServiceMeshLatency plugin
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Creating new instance of the ServiceMeshLatency plugin")
+
+	args, err := getArgs(obj)
+	if err != nil {
+		return nil, err
+	}
+	if args.MetricsUpdateIntervalSeconds <= 0 {
+		return nil, fmt.Errorf("invalid MetricsUpdateIntervalSeconds, want a positive value, got %v", args.MetricsUpdateIntervalSeconds)
+	}
+	collector, err := NewLatencyCollector(args.MeshType, args.PrometheusAddress, args.LatencyQuery)
+	if err != nil {
+		return nil, err
+	}
+	client, err := client.New(handle.KubeConfig(), client.Options{
+		Scheme: scheme,
+	})
+	if err != nil {
+		return nil, err
+	}
+	go collector.Run(ctx, time.Duration(args.MetricsUpdateIntervalSeconds)*time.Second)
+
+	return &ServiceMeshLatency{
+		Client: client,
+
+		podLister:   handle.SharedInformerFactory().Core().V1().Pods().Lister(),
+		handle:      handle,
+		namespaces:  args.Namespaces,
+		weightsName: args.WeightsName,
+		ntName:      args.NetworkTopologyName,
+		collector:   collector,
+	}, nil
+}
+
+// PreFilter performs the following operations:
+// 1. Get appGroup name and respective appGroup CR.
+// 2. Pass the pod if the networkTopology CR defines the preferred weights, NetworkOverhead then applies.
+// 3. Get dependency and scheduled list for the given pod
+// 4. Get number of satisfied and violated dependencies of all nodes, based on the mesh latencies
+// 5. Get final cost of the given node to be used in the score plugin
+func (sml *ServiceMeshLatency) PreFilter(ctx context.Context, state *framework.CycleState, pod *corev1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	logger := klog.FromContext(ctx)
+	state.Write(preFilterStateKey, &PreFilterState{scoreEqually: true})
+
+	agName := networkawareutil.GetPodAppGroupLabel(pod)
+	if len(agName) == 0 {
+		return nil, framework.NewStatus(framework.Success, "Pod does not belong to an AppGroup, return")
+	}
+
+	appGroup := sml.findAppGroup(ctx, logger, agName)
+	if appGroup == nil {
+		return nil, framework.NewStatus(framework.Success, "AppGroup not found, return")
+	}
+
+	if sml.hasNetworkTopologyWeights(sml.findNetworkTopology(ctx, logger)) {
+		return nil, framework.NewStatus(framework.Success, "NetworkTopology weights available, return")
+	}
+
+	dependencyList := networkawareutil.GetDependencyList(pod, appGroup)
+	if dependencyList == nil {
+		return nil, framework.NewStatus(framework.Success, "Pod has no dependencies, return")
+	}
+
+	selector := labels.Set(map[string]string{agv1alpha1.AppGroupLabel: agName}).AsSelector()
+	pods, err := sml.podLister.List(selector)
+	if err != nil {
+		return nil, framework.NewStatus(framework.Success, "Error while returning pods from appGroup, return")
+	}
+	scheduledList := networkawareutil.GetScheduledList(pods)
+	if len(scheduledList) == 0 {
+		return nil, framework.NewStatus(framework.Success, "Scheduled list is empty, return")
+	}
+
+	nodeList, err := sml.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, framework.NewStatus(framework.Error, fmt.Sprintf("Error getting the nodelist: %v", err))
+	}
+
+	// Zones of the nodes hosting the pods already scheduled
+	hostZones := make(map[string]string)
+	for _, nodeInfo := range nodeList {
+		hostZones[nodeInfo.Node().Name] = networkawareutil.GetNodeZone(nodeInfo.Node())
+	}
+
+	source := getWorkloadKey(appGroup, pod)
+	preFilterState := &PreFilterState{
+		satisfiedMap: make(map[string]int64),
+		violatedMap:  make(map[string]int64),
+		finalCostMap: make(map[string]int64),
+	}
+	for _, nodeInfo := range nodeList {
+		nodeName := nodeInfo.Node().Name
+		zone := hostZones[nodeName]
+		var satisfied, violated, cost int64
+		for _, podAllocated := range scheduledList {
+			for _, d := range dependencyList {
+				if podAllocated.Selector != d.Workload.Selector {
+					continue
+				}
+				c, known := sml.getDependencyCost(source, pod.Namespace, d, nodeName, zone, podAllocated.Hostname, hostZones)
+				cost += c
+				if !known {
+					continue
+				}
+				if c <= d.MaxNetworkCost {
+					satisfied += 1
+				} else {
+					violated += 1
+				}
+			}
+		}
+		preFilterState.satisfiedMap[nodeName] = satisfied
+		preFilterState.violatedMap[nodeName] = violated
+		preFilterState.finalCostMap[nodeName] = cost
+		logger.V(6).Info("Node final cost", "node", nodeName, "satisfied", satisfied, "violated", violated, "cost", cost)
+	}
+
+	state.Write(preFilterStateKey, preFilterState)
+	return nil, framework.NewStatus(framework.Success, "PreFilter State updated")
+}
+
+// PreFilterExtensions returns prefilter extensions, pod add and remove.
+func (sml *ServiceMeshLatency) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Filter : evaluate if node can respect maxNetworkCost requirements, taken as latency SLOs in milliseconds
+func (sml *ServiceMeshLatency) Filter(ctx context.Context,
+	cycleState *framework.CycleState,
+	pod *corev1.Pod,
+	nodeInfo *framework.NodeInfo) *framework.Status {
+	if nodeInfo.Node() == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+	logger := klog.FromContext(ctx)
+
+	preFilterState, err := getPreFilterState(cycleState)
+	if err != nil {
+		logger.Error(err, "Failed to read preFilterState from cycleState", "preFilterStateKey", preFilterStateKey)
+		return framework.NewStatus(framework.Error, "not eligible due to failed to read from cycleState")
+	}
+	if preFilterState.scoreEqually {
+		return nil
+	}
+
+	satisfied := preFilterState.satisfiedMap[nodeInfo.Node().Name]
+	violated := preFilterState.violatedMap[nodeInfo.Node().Name]
+	if violated > satisfied {
+		return framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("Node %v does not meet the latency SLOs of several Workload dependencies: Satisfied: %v Violated: %v", nodeInfo.Node().Name, satisfied, violated))
+	}
+	return nil
+}
+
+// Score : evaluate score for a node
+func (sml *ServiceMeshLatency) Score(ctx context.Context,
+	cycleState *framework.CycleState,
+	pod *corev1.Pod,
+	nodeName string) (int64, *framework.Status) {
+	score := framework.MinNodeScore
+	logger := klog.FromContext(ctx)
+
+	preFilterState, err := getPreFilterState(cycleState)
+	if err != nil {
+		logger.Error(err, "Failed to read preFilterState from cycleState", "preFilterStateKey", preFilterStateKey)
+		return score, framework.NewStatus(framework.Error, "not eligible due to failed to read from cycleState, return min score")
+	}
+	if preFilterState.scoreEqually {
+		return score, framework.NewStatus(framework.Success, "scoreEqually enabled: minimum score")
+	}
+
+	// Return Accumulated Cost as score
+	score = preFilterState.finalCostMap[nodeName]
+	logger.V(4).Info("Score:", "pod", pod.GetName(), "node", nodeName, "finalScore", score)
+	return score, framework.NewStatus(framework.Success, "Accumulated cost added as score, normalization ensures lower costs are favored")
+}
+
+// ScoreExtensions : an interface for Score extended functionality
+func (sml *ServiceMeshLatency) ScoreExtensions() framework.ScoreExtensions {
+	return sml
+}
+
+// NormalizeScore : normalize scores since lower scores correspond to lower latency
+func (sml *ServiceMeshLatency) NormalizeScore(ctx context.Context,
+	state *framework.CycleState,
+	pod *corev1.Pod,
+	scores framework.NodeScoreList) *framework.Status {
+	minCost, maxCost := getMinMaxScores(scores)
+
+	// If all nodes were given the minimum score, return
+	if minCost == 0 && maxCost == 0 {
+		return nil
+	}
+
+	for i := range scores {
+		var normCost float64
+		if maxCost != minCost {
+			normCost = float64(framework.MaxNodeScore) * float64(scores[i].Score-minCost) / float64(maxCost-minCost)
+		}
+		scores[i].Score = framework.MaxNodeScore - int64(normCost)
+	}
+	return nil
+}
+
+// getDependencyCost : cost of the dependency on a pod running on the host if the pod being scheduled is placed on
+// the node, and whether it is known. Pods on the same host or zone are considered close regardless of the mesh,
+// otherwise the cost is the p99 latency from the workload of the pod to the dependency reported by the mesh.
+func (sml *ServiceMeshLatency) getDependencyCost(source string, namespace string, d agv1alpha1.DependenciesInfo,
+	nodeName string, zone string, hostname string, hostZones map[string]string) (int64, bool) {
+	if hostname == nodeName {
+		return SameHostname, true
+	}
+	if zone != "" && zone == hostZones[hostname] {
+		return SameZone, true
+	}
+	if d.Workload.Namespace != "" {
+		namespace = d.Workload.Namespace
+	}
+	latency, ok := sml.collector.GetLatency(source, workloadKey(namespace, d.Workload.Name))
+	if !ok {
+		return MaxCost, false
+	}
+	return latency, true
+}
+
+// hasNetworkTopologyWeights : check if the NetworkTopology CR defines the preferred weights
+func (sml *ServiceMeshLatency) hasNetworkTopologyWeights(networkTopology *ntv1alpha1.NetworkTopology) bool {
+	if networkTopology == nil {
+		return false
+	}
+	for _, w := range networkTopology.Spec.Weights {
+		if w.Name == sml.weightsName && len(w.TopologyList) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getWorkloadKey : key of the workload of the pod in the mesh latencies
+func getWorkloadKey(appGroup *agv1alpha1.AppGroup, pod *corev1.Pod) string {
+	selector := networkawareutil.GetPodAppGroupSelector(pod)
+	for _, w := range appGroup.Spec.Workloads {
+		if w.Workload.Selector != selector {
+			continue
+		}
+		namespace := pod.Namespace
+		if w.Workload.Namespace != "" {
+			namespace = w.Workload.Namespace
+		}
+		return workloadKey(namespace, w.Workload.Name)
+	}
+	return workloadKey(pod.Namespace, selector)
+}
+
+// getMinMaxScores : get min and max scores from NodeScoreList
+func getMinMaxScores(scores framework.NodeScoreList) (int64, int64) {
+	var max int64 = math.MinInt64
+	var min int64 = math.MaxInt64
+	for _, nodeScore := range scores {
+		if nodeScore.Score > max {
+			max = nodeScore.Score
+		}
+		if nodeScore.Score < min {
+			min = nodeScore.Score
+		}
+	}
+	return min, max
+}
+
+func getPreFilterState(cycleState *framework.CycleState) (*PreFilterState, error) {
+	c, err := cycleState.Read(preFilterStateKey)
+	if err != nil {
+		// preFilterState doesn't exist, likely PreFilter wasn't invoked.
+		return nil, fmt.Errorf("error reading %q from cycleState: %w", preFilterStateKey, err)
+	}
+	state, ok := c.(*PreFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v  convert to ServiceMeshLatency.preFilterState error", c)
+	}
+	return state, nil
+}
+
+func (sml *ServiceMeshLatency) findAppGroup(ctx context.Context, logger klog.Logger, agName string) *agv1alpha1.AppGroup {
+	for _, namespace := range sml.namespaces {
+		appGroup := &agv1alpha1.AppGroup{}
+		if err := sml.Get(ctx, client.ObjectKey{Namespace: namespace, Name: agName}, appGroup); err != nil {
+			logger.V(4).Error(err, "Cannot get AppGroup", "namespace", namespace, "name", agName)
+			continue
+		}
+		return appGroup
+	}
+	return nil
+}
+
+func (sml *ServiceMeshLatency) findNetworkTopology(ctx context.Context, logger klog.Logger) *ntv1alpha1.NetworkTopology {
+	for _, namespace := range sml.namespaces {
+		networkTopology := &ntv1alpha1.NetworkTopology{}
+		if err := sml.Get(ctx, client.ObjectKey{Namespace: namespace, Name: sml.ntName}, networkTopology); err != nil {
+			logger.V(6).Info("Cannot get NetworkTopology", "namespace", namespace, "name", sml.ntName, "err", err)
+			continue
+		}
+		return networkTopology
+	}
+	return nil
+}
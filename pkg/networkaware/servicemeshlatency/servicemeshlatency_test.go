@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicemeshlatency
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	schedruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	networkawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/util"
+	testutil "github.com/amiraBenamer20/scheduler-plugins/test/util"
+)
+
+func makeAppGroupPod(name, selector, nodeName string) *v1.Pod {
+	pod := st.MakePod().Namespace("default").Name(name).
+		Label(agv1alpha1.AppGroupLabel, "shop").
+		Label(agv1alpha1.AppGroupSelectorLabel, selector).
+		Node(nodeName).Obj()
+	return pod
+}
+
+func TestServiceMeshLatency(t *testing.T) {
+	appGroup := &agv1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+		Spec: agv1alpha1.AppGroupSpec{
+			NumMembers: 2,
+			Workloads: agv1alpha1.AppGroupWorkloadList{
+				{
+					Workload: agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "frontend", Selector: "frontend"},
+					Dependencies: agv1alpha1.DependenciesList{{
+						Workload:       agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "cart", Selector: "cart"},
+						MaxNetworkCost: 20,
+					}},
+				},
+				{
+					Workload: agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: "cart", Selector: "cart"},
+				},
+			},
+		},
+	}
+	networkTopology := &ntv1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt-test", Namespace: "default"},
+		Spec: ntv1alpha1.NetworkTopologySpec{
+			Weights: ntv1alpha1.WeightList{{
+				Name: "UserDefined",
+				TopologyList: ntv1alpha1.TopologyList{{
+					TopologyKey: ntv1alpha1.NetworkTopologyZone,
+					OriginList: ntv1alpha1.OriginList{
+						{Origin: "Z1", CostList: []ntv1alpha1.CostInfo{{Destination: "Z2", NetworkCost: 5}}},
+					},
+				}},
+			}},
+		},
+	}
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n2").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n3").Label(v1.LabelTopologyZone, "Z2").Obj(),
+	}
+	cart := makeAppGroupPod("cart-1", "cart", "n1")
+
+	tests := []struct {
+		name            string
+		networkTopology *ntv1alpha1.NetworkTopology
+		latencies       map[networkawareutil.CostKey]float64
+		pod             *v1.Pod
+		wantFiltered    []string
+		wantScores      map[string]int64
+	}{
+		{
+			name:         "mesh latency above the SLO filters out the other zone",
+			latencies:    map[networkawareutil.CostKey]float64{{Origin: "default/frontend", Destination: "default/cart"}: 50},
+			pod:          makeAppGroupPod("frontend-1", "frontend", ""),
+			wantFiltered: []string{"n3"},
+			wantScores:   map[string]int64{"n1": 0, "n2": 1, "n3": 50},
+		},
+		{
+			name:       "mesh latency within the SLO",
+			latencies:  map[networkawareutil.CostKey]float64{{Origin: "default/frontend", Destination: "default/cart"}: 9.2},
+			pod:        makeAppGroupPod("frontend-1", "frontend", ""),
+			wantScores: map[string]int64{"n1": 0, "n2": 1, "n3": 10},
+		},
+		{
+			name:       "mesh latency not reported",
+			pod:        makeAppGroupPod("frontend-1", "frontend", ""),
+			wantScores: map[string]int64{"n1": 0, "n2": 1, "n3": MaxCost},
+		},
+		{
+			name:            "NetworkTopology weights take precedence",
+			networkTopology: networkTopology,
+			latencies:       map[networkawareutil.CostKey]float64{{Origin: "default/frontend", Destination: "default/cart"}: 50},
+			pod:             makeAppGroupPod("frontend-1", "frontend", ""),
+			wantScores:      map[string]int64{"n1": 0, "n2": 0, "n3": 0},
+		},
+		{
+			name:       "pod without dependencies",
+			latencies:  map[networkawareutil.CostKey]float64{{Origin: "default/frontend", Destination: "default/cart"}: 50},
+			pod:        makeAppGroupPod("cart-2", "cart", ""),
+			wantScores: map[string]int64{"n1": 0, "n2": 0, "n3": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(appGroup)
+			if tt.networkTopology != nil {
+				builder.WithObjects(tt.networkTopology)
+			}
+
+			cs := clientsetfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			if err := podInformer.Informer().GetStore().Add(cart); err != nil {
+				t.Fatal(err)
+			}
+
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, err := tf.NewFramework(ctx, registeredPlugins, "default-scheduler", schedruntime.WithClientSet(cs),
+				schedruntime.WithInformerFactory(informerFactory), schedruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(nil, nodes)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			collector := &LatencyCollector{latencies: tt.latencies}
+			pl := &ServiceMeshLatency{
+				Client:      builder.Build(),
+				podLister:   podInformer.Lister(),
+				handle:      fh,
+				namespaces:  []string{"default"},
+				weightsName: "UserDefined",
+				ntName:      "nt-test",
+				collector:   collector,
+			}
+
+			state := framework.NewCycleState()
+			if _, status := pl.PreFilter(ctx, state, tt.pod); !status.IsSuccess() {
+				t.Fatalf("unexpected PreFilter status: %v", status)
+			}
+			var filtered []string
+			scores := make(map[string]int64)
+			for _, node := range nodes {
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(node)
+				if status := pl.Filter(ctx, state, tt.pod, nodeInfo); !status.IsSuccess() {
+					if status.Code() != framework.Unschedulable {
+						t.Errorf("unexpected Filter status: %v", status)
+					}
+					filtered = append(filtered, node.Name)
+				}
+				score, status := pl.Score(ctx, state, tt.pod, node.Name)
+				if !status.IsSuccess() {
+					t.Errorf("unexpected Score status: %v", status)
+				}
+				scores[node.Name] = score
+			}
+			if !reflect.DeepEqual(filtered, tt.wantFiltered) {
+				t.Errorf("expected filtered nodes %v, got %v", tt.wantFiltered, filtered)
+			}
+			if !reflect.DeepEqual(scores, tt.wantScores) {
+				t.Errorf("expected scores %v, got %v", tt.wantScores, scores)
+			}
+		})
+	}
+}
+
+func TestServiceMeshLatencyNormalizeScore(t *testing.T) {
+	pl := &ServiceMeshLatency{}
+	scores := framework.NodeScoreList{{Name: "n1", Score: 0}, {Name: "n2", Score: 1}, {Name: "n3", Score: 50}}
+	if status := pl.NormalizeScore(context.Background(), framework.NewCycleState(), nil, scores); !status.IsSuccess() {
+		t.Fatalf("unexpected status: %v", status)
+	}
+	want := framework.NodeScoreList{{Name: "n1", Score: 100}, {Name: "n2", Score: 98}, {Name: "n3", Score: 0}}
+	if !reflect.DeepEqual(scores, want) {
+		t.Errorf("expected %v, got %v", want, scores)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicemeshlatency
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	networkawareutil "github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/util"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+// meshQuery : query of the p99 latency between workloads, and the labels of the workloads at both ends in its result
+type meshQuery struct {
+	query                string
+	sourceNamespace      string
+	sourceWorkload       string
+	destinationNamespace string
+	destinationWorkload  string
+}
+
+// meshQueries : queries of the standard telemetry of the supported service meshes
+var meshQueries = map[pluginconfig.ServiceMeshType]meshQuery{
+	pluginconfig.ServiceMeshIstio: {
+		query: `histogram_quantile(0.99, sum(rate(istio_request_duration_milliseconds_bucket{reporter="source"}[5m])) ` +
+			`by (le, source_workload_namespace, source_workload, destination_workload_namespace, destination_workload))`,
+		sourceNamespace:      "source_workload_namespace",
+		sourceWorkload:       "source_workload",
+		destinationNamespace: "destination_workload_namespace",
+		destinationWorkload:  "destination_workload",
+	},
+	pluginconfig.ServiceMeshLinkerd: {
+		query: `histogram_quantile(0.99, sum(rate(response_latency_ms_bucket{direction="outbound"}[5m])) ` +
+			`by (le, namespace, deployment, dst_namespace, dst_deployment))`,
+		sourceNamespace:      "namespace",
+		sourceWorkload:       "deployment",
+		destinationNamespace: "dst_namespace",
+		destinationWorkload:  "dst_deployment",
+	},
+}
+
+// LatencyCollector : get the p99 latencies between workloads reported by the service mesh from Prometheus
+type LatencyCollector struct {
+	prometheus *util.PrometheusClient
+	query      meshQuery
+
+	// latencies in milliseconds by workload key (origin: client, destination: server)
+	latencies map[networkawareutil.CostKey]float64
+	// for safe access to latencies
+	mu sync.RWMutex
+}
+
+// NewLatencyCollector : create a collector of the latencies reported by the service mesh
+func NewLatencyCollector(meshType pluginconfig.ServiceMeshType, address string, query string) (*LatencyCollector, error) {
+	mq, ok := meshQueries[meshType]
+	if !ok {
+		return nil, fmt.Errorf("invalid MeshType, got %v", meshType)
+	}
+	if address == "" {
+		return nil, fmt.Errorf("PrometheusAddress is required")
+	}
+	if query != "" {
+		mq.query = query
+	}
+	return &LatencyCollector{
+		prometheus: util.NewPrometheusClient(address),
+		query:      mq,
+		latencies:  make(map[networkawareutil.CostKey]float64),
+	}, nil
+}
+
+// Run : refresh the latencies every interval until the context is done
+func (c *LatencyCollector) Run(ctx context.Context, interval time.Duration) {
+	logger := klog.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.UpdateLatencies(ctx); err != nil {
+			logger.Error(err, "Unable to update the service mesh latencies")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// UpdateLatencies : query Prometheus and replace the latencies with the result
+func (c *LatencyCollector) UpdateLatencies(ctx context.Context) error {
+	// histogram_quantile returns NaN for the pairs of workloads without traffic in the window, skipped by QueryVector
+	samples, err := c.prometheus.QueryVector(ctx, c.query.query)
+	if err != nil {
+		return err
+	}
+
+	latencies := make(map[networkawareutil.CostKey]float64, len(samples))
+	for _, sample := range samples {
+		source := sample.Labels[c.query.sourceWorkload]
+		destination := sample.Labels[c.query.destinationWorkload]
+		if source == "" || destination == "" {
+			continue
+		}
+		latencies[networkawareutil.CostKey{
+			Origin:      workloadKey(sample.Labels[c.query.sourceNamespace], source),
+			Destination: workloadKey(sample.Labels[c.query.destinationNamespace], destination),
+		}] = sample.Value
+	}
+
+	c.mu.Lock()
+	c.latencies = latencies
+	c.mu.Unlock()
+	return nil
+}
+
+// GetLatency : get the p99 latency in milliseconds from the client workload to the server workload, rounded up
+func (c *LatencyCollector) GetLatency(source, destination string) (int64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	latency, ok := c.latencies[networkawareutil.CostKey{Origin: source, Destination: destination}]
+	if !ok {
+		return 0, false
+	}
+	return int64(math.Ceil(latency)), true
+}
+
+// workloadKey : key of a workload in the latencies
+func workloadKey(namespace, name string) string {
+	return namespace + "/" + name
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicemeshlatency
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+func newTestPrometheus(t *testing.T, wantQuery string, response string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			t.Errorf("unexpected path %v", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("query"); got != wantQuery {
+			t.Errorf("unexpected query %v", got)
+		}
+		fmt.Fprint(w, response)
+	}))
+}
+
+func TestUpdateLatencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		meshType  pluginconfig.ServiceMeshType
+		response  string
+		wantErr   bool
+		latencies map[[2]string]int64
+	}{
+		{
+			name:     "istio",
+			meshType: pluginconfig.ServiceMeshIstio,
+			response: `{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"source_workload_namespace":"default","source_workload":"frontend","destination_workload_namespace":"default","destination_workload":"cart"},"value":[1710000000.1,"12.4"]},
+				{"metric":{"source_workload_namespace":"default","source_workload":"cart","destination_workload_namespace":"db","destination_workload":"redis"},"value":[1710000000.1,"3"]},
+				{"metric":{"source_workload_namespace":"default","source_workload":"frontend","destination_workload_namespace":"default","destination_workload":"ads"},"value":[1710000000.1,"NaN"]}]}}`,
+			latencies: map[[2]string]int64{
+				{"default/frontend", "default/cart"}: 13,
+				{"default/cart", "db/redis"}:         3,
+			},
+		},
+		{
+			name:     "linkerd",
+			meshType: pluginconfig.ServiceMeshLinkerd,
+			response: `{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"namespace":"default","deployment":"frontend","dst_namespace":"default","dst_deployment":"cart"},"value":[1710000000.1,"40"]}]}}`,
+			latencies: map[[2]string]int64{
+				{"default/frontend", "default/cart"}: 40,
+			},
+		},
+		{
+			name:     "query error",
+			meshType: pluginconfig.ServiceMeshIstio,
+			response: `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestPrometheus(t, meshQueries[tt.meshType].query, tt.response)
+			defer server.Close()
+
+			c, err := NewLatencyCollector(tt.meshType, server.URL, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.UpdateLatencies(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got %v", tt.wantErr, err)
+			}
+			if len(c.latencies) != len(tt.latencies) {
+				t.Errorf("expected %v latencies, got %v", len(tt.latencies), c.latencies)
+			}
+			for k, want := range tt.latencies {
+				if got, ok := c.GetLatency(k[0], k[1]); !ok || got != want {
+					t.Errorf("expected the latency from %v to %v to be %v, got %v (found: %v)", k[0], k[1], want, got, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestNewLatencyCollector(t *testing.T) {
+	if _, err := NewLatencyCollector("Consul", "http://prometheus:9090", ""); err == nil {
+		t.Errorf("expected an error for an unsupported mesh")
+	}
+	if _, err := NewLatencyCollector(pluginconfig.ServiceMeshIstio, "", ""); err == nil {
+		t.Errorf("expected an error without Prometheus address")
+	}
+	c, err := NewLatencyCollector(pluginconfig.ServiceMeshLinkerd, "http://prometheus:9090/", "custom_p99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.query.query != "custom_p99" || c.query.sourceWorkload != "deployment" {
+		t.Errorf("unexpected collector %+v", c)
+	}
+}
@@ -19,10 +19,15 @@ package topologicalsort
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
@@ -42,6 +47,10 @@ import (
 const (
 	// Name : name of plugin used in the plugin registry and configurations.
 	Name = "TopologicalSort"
+
+	// DependencyCycle is the reason of the event recorded when the dependencies of an AppGroup form a cycle,
+	// its pods being then sorted by priority and creation time.
+	DependencyCycle = "DependencyCycle"
 )
 
 var scheme = runtime.NewScheme()
@@ -56,6 +65,16 @@ type TopologicalSort struct {
 	client.Client
 	handle     framework.Handle
 	namespaces []string
+
+	cycleLock sync.Mutex
+	// cycles are the dependency cycles of the AppGroups by UID, nil for a DAG, checked once per generation.
+	cycles map[types.UID]dependencyCycle
+}
+
+// dependencyCycle is the dependency cycle of an AppGroup at a generation, nil if its dependencies form a DAG.
+type dependencyCycle struct {
+	generation int64
+	cycle      []string
 }
 
 var _ framework.QueueSortPlugin = &TopologicalSort{}
@@ -102,7 +121,9 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 
 // Less is the function used by the activeQ heap algorithm to sort pods.
 // 1) Sort Pods based on their AppGroup and corresponding service topology graph.
-// 2) Otherwise, follow the strategy of the in-tree QueueSort Plugin (PrioritySort Plugin)
+// 2) Pods at the same order, or of an AppGroup whose dependencies form a cycle, are sorted by priority, then creation
+// time, then namespace and name.
+// 3) Otherwise, follow the strategy of the in-tree QueueSort Plugin (PrioritySort Plugin)
 func (ts *TopologicalSort) Less(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
 	p1AppGroup := networkawareutil.GetPodAppGroupLabel(pInfo1.Pod)
 	p2AppGroup := networkawareutil.GetPodAppGroupLabel(pInfo2.Pod)
@@ -120,6 +141,14 @@ func (ts *TopologicalSort) Less(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
 	logger.V(6).Info("Pods belong to the same AppGroup CR", "p1 name", pInfo1.Pod.Name, "p2 name", pInfo2.Pod.Name, "appGroup", p1AppGroup)
 	agName := p1AppGroup
 	appGroup := ts.findAppGroupTopologicalSort(ctx, logger, agName)
+	if appGroup == nil {
+		s := &queuesort.PrioritySort{}
+		return s.Less(pInfo1, pInfo2)
+	}
+	if cycle := ts.dependencyCycle(appGroup); cycle != nil {
+		logger.V(6).Info("AppGroup dependencies form a cycle, sorting by priority and creation time", "appGroup", klog.KObj(appGroup), "cycle", cycle)
+		return lessByPriorityAndCreation(pInfo1, pInfo2)
+	}
 
 	// Get labels from both pods
 	labelsP1 := pInfo1.Pod.GetLabels()
@@ -132,7 +161,55 @@ func (ts *TopologicalSort) Less(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
 	logger.V(6).Info("Pod order values", "p1 order", orderP1, "p2 order", orderP2)
 
 	// Lower is better
-	return orderP1 <= orderP2
+	if orderP1 != orderP2 {
+		return orderP1 < orderP2
+	}
+	return lessByPriorityAndCreation(pInfo1, pInfo2)
+}
+
+// lessByPriorityAndCreation sorts pods by priority, then creation time, then namespace and name, so that the order
+// of the pods is deterministic.
+func lessByPriorityAndCreation(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+	p1 := corev1helpers.PodPriority(pInfo1.Pod)
+	p2 := corev1helpers.PodPriority(pInfo2.Pod)
+	if p1 != p2 {
+		return p1 > p2
+	}
+	t1 := pInfo1.Pod.CreationTimestamp
+	t2 := pInfo2.Pod.CreationTimestamp
+	if !t1.Equal(&t2) {
+		return t1.Before(&t2)
+	}
+	if pInfo1.Pod.Namespace != pInfo2.Pod.Namespace {
+		return pInfo1.Pod.Namespace < pInfo2.Pod.Namespace
+	}
+	return pInfo1.Pod.Name < pInfo2.Pod.Name
+}
+
+// dependencyCycle returns the dependency cycle of the AppGroup, nil if its dependencies form a DAG. A Warning event
+// is recorded on the AppGroup the first time a cycle is found at its generation.
+func (ts *TopologicalSort) dependencyCycle(appGroup *agv1alpha.AppGroup) []string {
+	ts.cycleLock.Lock()
+	defer ts.cycleLock.Unlock()
+	if c, ok := ts.cycles[appGroup.UID]; ok && c.generation == appGroup.Generation {
+		return c.cycle
+	}
+	cycle := networkawareutil.FindDependencyCycle(appGroup)
+	if ts.cycles == nil {
+		ts.cycles = make(map[types.UID]dependencyCycle)
+	}
+	ts.cycles[appGroup.UID] = dependencyCycle{generation: appGroup.Generation, cycle: cycle}
+	if cycle == nil || ts.handle == nil {
+		return cycle
+	}
+	if recorder := ts.handle.EventRecorder(); recorder != nil {
+		// the AppGroup kind is unknown to the scheme of the recorder, the reference is made from the object kind
+		ag := appGroup.DeepCopy()
+		ag.SetGroupVersionKind(agv1alpha.SchemeGroupVersion.WithKind("AppGroup"))
+		recorder.Eventf(ag, nil, v1.EventTypeWarning, DependencyCycle, "Sorting",
+			"Dependencies form a cycle %v, pods are sorted by priority and creation time", strings.Join(cycle, " -> "))
+	}
+	return cycle
 }
 
 func (ts *TopologicalSort) findAppGroupTopologicalSort(ctx context.Context, logger klog.Logger, agName string) *agv1alpha.AppGroup {
@@ -17,6 +17,8 @@ limitations under the License.
 package util
 
 import (
+	"sort"
+
 	v1 "k8s.io/api/core/v1"
 
 	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
@@ -211,6 +213,62 @@ func GetDependencyList(pod *v1.Pod, ag *agv1alpha1.AppGroup) []agv1alpha1.Depend
 	return dependencyList
 }
 
+// FindDependencyCycle : return a cycle of the workload dependencies established in the AppGroup CR, as the selectors
+// of the workloads along the cycle with the first one repeated at the end, or nil if the dependencies form a DAG.
+// Workloads are visited by selector, so the same cycle is returned for the same AppGroup.
+func FindDependencyCycle(ag *agv1alpha1.AppGroup) []string {
+	dependencies := make(map[string][]string)
+	for _, w := range ag.Spec.Workloads {
+		for _, dependency := range w.Dependencies {
+			dependencies[w.Workload.Selector] = append(dependencies[w.Workload.Selector], dependency.Workload.Selector)
+		}
+	}
+	selectors := make([]string, 0, len(dependencies))
+	for selector := range dependencies {
+		selectors = append(selectors, selector)
+		sort.Strings(dependencies[selector])
+	}
+	sort.Strings(selectors)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(selector string) []string
+	visit = func(selector string) []string {
+		switch state[selector] {
+		case visited:
+			return nil
+		case visiting:
+			// the selector is on the path: the cycle goes from it to the end of the path
+			for i, s := range path {
+				if s == selector {
+					return append(append([]string{}, path[i:]...), selector)
+				}
+			}
+		}
+		state[selector] = visiting
+		path = append(path, selector)
+		for _, dependency := range dependencies[selector] {
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[selector] = visited
+		return nil
+	}
+	for _, selector := range selectors {
+		if cycle := visit(selector); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
 // GetScheduledList : get Pods already scheduled in the cluster for that specific AppGroup
 func GetScheduledList(pods []*v1.Pod) ScheduledList {
 	// scheduledList: Deployment name, replicaID, hostname
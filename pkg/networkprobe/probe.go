@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package networkprobe measures the latency from the node of a probe to the nodes of the other probes of a
+// DaemonSet, and publishes it in an annotation of the probe pod for the NetworkTopology controller.
+package networkprobe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// LatenciesAnnotation : annotation of a probe pod with the latencies in milliseconds from its node to the
+	// nodes of the other probes, encoded in JSON by destination node name
+	LatenciesAnnotation = "scheduling.x-k8s.io/network-probe-latencies"
+	// AppLabel : label selecting the probe pods
+	AppLabel = "app"
+	// AppName : value of the AppLabel of the probe pods
+	AppName = "network-probe"
+
+	dialTimeout = 2 * time.Second
+)
+
+// Latencies : latencies in milliseconds by destination node name
+type Latencies map[string]float64
+
+// Prober : measure the latency from the node of the probe pod to the nodes of the other probe pods, as the median
+// time to open a TCP connection to their listener, and publish it in the LatenciesAnnotation of the probe pod
+type Prober struct {
+	Client kubernetes.Interface
+	// Namespace and Name of the probe pod
+	Namespace string
+	Name      string
+	// NodeName : node of the probe pod
+	NodeName string
+	// Port : port the probes listen on and connect to
+	Port int
+	// Interval : period of the measurements
+	Interval time.Duration
+	// Samples : number of connections per measurement, whose median is kept
+	Samples int
+
+	// dial : open and close a connection to the address, returning the time it took
+	dial func(ctx context.Context, address string) (time.Duration, error)
+}
+
+// Run : listen for the connections of the other probes, and measure the latencies every interval until ctx is done
+func (p *Prober) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(p.Port)))
+	if err != nil {
+		return fmt.Errorf("unable to listen on port %d: %w", p.Port, err)
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	wait.UntilWithContext(ctx, p.probe, p.Interval)
+	return nil
+}
+
+// probe : measure the latencies to the other probes and publish them
+func (p *Prober) probe(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	pods, err := p.Client.CoreV1().Pods(p.Namespace).List(ctx, metav1.ListOptions{LabelSelector: AppLabel + "=" + AppName})
+	if err != nil {
+		logger.Error(err, "Unable to list the probes")
+		return
+	}
+
+	latencies := Latencies{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || pod.Spec.NodeName == p.NodeName || pod.Status.PodIP == "" || pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		latency, err := p.measure(ctx, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(p.Port)))
+		if err != nil {
+			logger.V(4).Info("Unable to measure the latency", "node", pod.Spec.NodeName, "err", err)
+			continue
+		}
+		latencies[pod.Spec.NodeName] = latency
+	}
+
+	if err := p.publish(ctx, latencies); err != nil {
+		logger.Error(err, "Unable to publish the latencies", "pod", klog.KRef(p.Namespace, p.Name))
+	}
+}
+
+// measure : median time in milliseconds to open a connection to the address, over the samples
+func (p *Prober) measure(ctx context.Context, address string) (float64, error) {
+	dial := p.dial
+	if dial == nil {
+		dial = dialTCP
+	}
+	samples := make([]float64, 0, p.Samples)
+	for i := 0; i < max(p.Samples, 1); i++ {
+		d, err := dial(ctx, address)
+		if err != nil {
+			return 0, err
+		}
+		samples = append(samples, float64(d)/float64(time.Millisecond))
+	}
+	sort.Float64s(samples)
+	// round to the microsecond
+	return math.Round(samples[len(samples)/2]*1000) / 1000, nil
+}
+
+// publish : patch the LatenciesAnnotation of the probe pod
+func (p *Prober) publish(ctx context.Context, latencies Latencies) error {
+	value, err := json.Marshal(latencies)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{LatenciesAnnotation: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.Client.CoreV1().Pods(p.Namespace).Patch(ctx, p.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// ParseLatencies : latencies of the LatenciesAnnotation of a probe pod, nil if the probe published none
+func ParseLatencies(pod *v1.Pod) (Latencies, error) {
+	value, ok := pod.Annotations[LatenciesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	latencies := Latencies{}
+	if err := json.Unmarshal([]byte(value), &latencies); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s of pod %s/%s: %w", LatenciesAnnotation, pod.Namespace, pod.Name, err)
+	}
+	return latencies, nil
+}
+
+func dialTCP(ctx context.Context, address string) (time.Duration, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, err
+	}
+	elapsed := time.Since(start)
+	conn.Close()
+	return elapsed, nil
+}
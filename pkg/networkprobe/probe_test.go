@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkprobe
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func makeProbePod(name, nodeName, podIP string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system", Labels: map[string]string{AppLabel: AppName}},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+		Status:     v1.PodStatus{Phase: v1.PodRunning, PodIP: podIP},
+	}
+}
+
+func TestProbe(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		makeProbePod("probe-a", "node-a", "10.0.0.1"),
+		makeProbePod("probe-b", "node-b", "10.0.0.2"),
+		makeProbePod("probe-c", "node-c", "10.0.0.3"),
+		makeProbePod("probe-d", "node-d", ""),
+	)
+	// node-b answers in 1, 2 and 9ms, node-c doesn't answer
+	rtts := map[string][]time.Duration{
+		"10.0.0.2:8091": {time.Millisecond, 9 * time.Millisecond, 2 * time.Millisecond},
+	}
+	p := &Prober{
+		Client:    client,
+		Namespace: "kube-system",
+		Name:      "probe-a",
+		NodeName:  "node-a",
+		Port:      8091,
+		Samples:   3,
+		dial: func(_ context.Context, address string) (time.Duration, error) {
+			if len(rtts[address]) == 0 {
+				return 0, fmt.Errorf("connection refused")
+			}
+			rtt := rtts[address][0]
+			rtts[address] = rtts[address][1:]
+			return rtt, nil
+		},
+	}
+
+	p.probe(context.Background())
+
+	pod, err := client.CoreV1().Pods("kube-system").Get(context.Background(), "probe-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseLatencies(pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Latencies{"node-b": 2}); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the median latencies %v, got %v", want, got)
+	}
+}
+
+func TestParseLatencies(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        Latencies
+		wantErr     bool
+	}{
+		{
+			name: "no latencies published",
+		},
+		{
+			name:        "latencies",
+			annotations: map[string]string{LatenciesAnnotation: `{"node-b":0.25,"node-c":12}`},
+			want:        Latencies{"node-b": 0.25, "node-c": 12},
+		},
+		{
+			name:        "invalid latencies",
+			annotations: map[string]string{LatenciesAnnotation: `node-b`},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			got, err := ParseLatencies(pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected latencies %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfvaware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/api/v1/resource"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling"
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "NFVAware"
+
+	preFilterStateKey = "PreFilter" + Name
+
+	// IsolatedCoresAnnotation is the annotation of the pods giving the number of isolated cores they pin
+	// their DPDK poll-mode threads on, e.g. "4".
+	IsolatedCoresAnnotation = scheduling.GroupName + "/isolated-cores"
+
+	// hugePages1Gi is the resource of the 1Gi hugepages.
+	hugePages1Gi = v1.ResourceName(v1.ResourceHugePagesPrefix + "1Gi")
+	// hugePageSize is the size of a 1Gi hugepage in bytes.
+	hugePageSize = 1 << 30
+)
+
+// NFVAware is a plugin placing the NFV pods, e.g. the DPDK network functions, on the nodes with enough
+// 1Gi hugepages, isolated cores and SR-IOV virtual functions according to their NodeNFVCapability,
+// and packing the virtual functions in the SR-IOV pools to keep the other pools whole.
+type NFVAware struct {
+	handle framework.Handle
+	client client.Client
+}
+
+var _ framework.PreFilterPlugin = &NFVAware{}
+var _ framework.FilterPlugin = &NFVAware{}
+var _ framework.ScorePlugin = &NFVAware{}
+var _ framework.EnqueueExtensions = &NFVAware{}
+
+// requirements are the NFV requirements of a pod.
+type requirements struct {
+	// hugePages is the number of 1Gi hugepages.
+	hugePages int64
+	// isolatedCores is the number of isolated cores.
+	isolatedCores int64
+	// vfs is the number of virtual functions by SR-IOV resource name.
+	vfs map[v1.ResourceName]int64
+}
+
+// preFilterState computed at PreFilter and used at Filter and Score.
+type preFilterState struct {
+	requirements
+	// capabilities are the NFV capabilities by node name.
+	capabilities map[string]*v1alpha1.NodeNFVCapabilitySpec
+}
+
+// Clone the preFilter state. It is not modified after PreFilter.
+func (s *preFilterState) Clone() framework.StateData {
+	return s
+}
+
+// New initializes a new plugin and returns it.
+func New(ctx context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	lh := klog.FromContext(ctx)
+	lh.V(5).Info("creating new NFV aware plugin")
+
+	scheme := runtime.NewScheme()
+	_ = clientscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	client, err := client.New(handle.KubeConfig(), client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	return &NFVAware{
+		handle: handle,
+		client: client,
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (na *NFVAware) Name() string {
+	return Name
+}
+
+// EventsToRegister returns the possible events that may make a Pod
+// failed by this plugin schedulable.
+func (na *NFVAware) EventsToRegister(_ context.Context) ([]framework.ClusterEventWithHint, error) {
+	capabilityGVK := fmt.Sprintf("nodenfvcapabilities.v1alpha1.%v", scheduling.GroupName)
+	return []framework.ClusterEventWithHint{
+		{Event: framework.ClusterEvent{Resource: framework.Pod, ActionType: framework.Delete}},
+		{Event: framework.ClusterEvent{Resource: framework.Node, ActionType: framework.Add}},
+		{Event: framework.ClusterEvent{Resource: framework.GVK(capabilityGVK), ActionType: framework.All}},
+	}, nil
+}
+
+// PreFilter computes the NFV requirements of the pod and lists the NFV capabilities of the nodes.
+// It is skipped for the pods without 1Gi hugepages, isolated cores nor SR-IOV virtual functions.
+func (na *NFVAware) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	requests := resource.PodRequests(pod, resource.PodResourcesOptions{})
+	isolatedCores, err := podIsolatedCores(pod)
+	if err != nil {
+		return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+	}
+	hugePagesRequest := requests[hugePages1Gi]
+	r := requirements{
+		hugePages:     hugePages(hugePagesRequest.Value()),
+		isolatedCores: isolatedCores,
+		vfs:           make(map[v1.ResourceName]int64),
+	}
+	hasExtendedResources := false
+	for name := range requests {
+		if v1helper.IsExtendedResourceName(name) {
+			hasExtendedResources = true
+			break
+		}
+	}
+	if r.hugePages == 0 && r.isolatedCores == 0 && !hasExtendedResources {
+		return nil, framework.NewStatus(framework.Skip)
+	}
+
+	var capabilityList v1alpha1.NodeNFVCapabilityList
+	if err := na.client.List(ctx, &capabilityList); err != nil {
+		return nil, framework.AsStatus(fmt.Errorf("listing NFV capabilities: %w", err))
+	}
+	s := &preFilterState{
+		requirements: r,
+		capabilities: make(map[string]*v1alpha1.NodeNFVCapabilitySpec, len(capabilityList.Items)),
+	}
+	for i := range capabilityList.Items {
+		capability := &capabilityList.Items[i]
+		s.capabilities[capability.Name] = &capability.Spec
+		// the extended resources of the pod are virtual functions if any node has a pool of them
+		for _, pool := range capability.Spec.SRIOVPools {
+			name := v1.ResourceName(pool.ResourceName)
+			if quantity, ok := requests[name]; ok && !quantity.IsZero() {
+				s.vfs[name] = quantity.Value()
+			}
+		}
+	}
+	if s.hugePages == 0 && s.isolatedCores == 0 && len(s.vfs) == 0 {
+		return nil, framework.NewStatus(framework.Skip)
+	}
+	state.Write(preFilterStateKey, s)
+	return nil, nil
+}
+
+// PreFilterExtensions returns nil: the usage of the node is computed from the node info at Filter.
+func (na *NFVAware) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Filter checks, in a single pass, the 1Gi hugepages, isolated cores and virtual functions left on the node
+// by its NFV capability and the pods running there against the requirements of the pod.
+func (na *NFVAware) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	node := nodeInfo.Node()
+	capability, ok := s.capabilities[node.Name]
+	if !ok {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+			fmt.Sprintf("node %q has no NFV capability", node.Name))
+	}
+
+	var reasons []string
+	if s.hugePages > 0 && s.hugePages > capability.HugePages1Gi-usedHugePages(nodeInfo) {
+		reasons = append(reasons, "Insufficient 1Gi hugepages")
+	}
+	if s.isolatedCores > 0 && s.isolatedCores > capability.IsolatedCores-usedIsolatedCores(nodeInfo) {
+		reasons = append(reasons, "Insufficient isolated cores")
+	}
+	pools := poolVFs(capability)
+	for name, vfs := range s.vfs {
+		total, ok := pools[name]
+		if !ok {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+				fmt.Sprintf("node %q has no SR-IOV pool of %v", node.Name, name))
+		}
+		if vfs > total-nodeInfo.Requested.ScalarResources[name] {
+			reasons = append(reasons, fmt.Sprintf("Insufficient virtual functions of %v", name))
+		}
+	}
+	if len(reasons) > 0 {
+		return framework.NewStatus(framework.Unschedulable, reasons...)
+	}
+	return nil
+}
+
+// Score is the mean share of the SR-IOV pools requested by the pod that would be allocated once the pod
+// runs on the node, packing the virtual functions in the fewest pools to limit their fragmentation.
+func (na *NFVAware) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreFilterState(state)
+	if err != nil || len(s.vfs) == 0 {
+		// The pod has no virtual functions.
+		return 0, nil
+	}
+	capability, ok := s.capabilities[nodeName]
+	if !ok {
+		return 0, nil
+	}
+	nodeInfo, err := na.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+	}
+	pools := poolVFs(capability)
+	var score int64
+	for name, vfs := range s.vfs {
+		total := pools[name]
+		if total <= 0 {
+			continue
+		}
+		allocated := min(nodeInfo.Requested.ScalarResources[name]+vfs, total)
+		score += allocated * framework.MaxNodeScore / total
+	}
+	return score / int64(len(s.vfs)), nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (na *NFVAware) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// poolVFs returns the number of virtual functions of the SR-IOV pools of the node by resource name,
+// summing the pools of the same resource name.
+func poolVFs(capability *v1alpha1.NodeNFVCapabilitySpec) map[v1.ResourceName]int64 {
+	pools := make(map[v1.ResourceName]int64, len(capability.SRIOVPools))
+	for _, pool := range capability.SRIOVPools {
+		pools[v1.ResourceName(pool.ResourceName)] += pool.VFs
+	}
+	return pools
+}
+
+// usedHugePages returns the number of 1Gi hugepages requested by the pods on the node.
+func usedHugePages(nodeInfo *framework.NodeInfo) int64 {
+	return hugePages(nodeInfo.Requested.ScalarResources[hugePages1Gi])
+}
+
+// usedIsolatedCores returns the number of isolated cores requested by the pods on the node.
+func usedIsolatedCores(nodeInfo *framework.NodeInfo) int64 {
+	var cores int64
+	for _, p := range nodeInfo.Pods {
+		if p.Pod.Status.Phase == v1.PodSucceeded || p.Pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		// the invalid annotations were rejected when scheduling the pods
+		n, _ := podIsolatedCores(p.Pod)
+		cores += n
+	}
+	return cores
+}
+
+// podIsolatedCores returns the number of isolated cores of the pod annotation, 0 if not annotated.
+func podIsolatedCores(pod *v1.Pod) (int64, error) {
+	value, ok := pod.Annotations[IsolatedCoresAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	cores, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || cores < 0 {
+		return 0, fmt.Errorf("invalid %v annotation %q", IsolatedCoresAnnotation, value)
+	}
+	return cores, nil
+}
+
+// hugePages returns the number of 1Gi hugepages holding the given bytes.
+func hugePages(bytes int64) int64 {
+	return (bytes + hugePageSize - 1) / hugePageSize
+}
+
+func getPreFilterState(state *framework.CycleState) (*preFilterState, error) {
+	c, err := state.Read(preFilterStateKey)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q from cycleState: %w", preFilterStateKey, err)
+	}
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to nfvaware.preFilterState error", c)
+	}
+	return s, nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfvaware
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	tu "github.com/amiraBenamer20/scheduler-plugins/test/util"
+)
+
+const sriovA = "intel.com/sriov_dpdk_a"
+
+func makeCapability(nodeName string, hugePages, isolatedCores, vfs int64) *v1alpha1.NodeNFVCapability {
+	return &v1alpha1.NodeNFVCapability{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+		Spec: v1alpha1.NodeNFVCapabilitySpec{
+			HugePages1Gi:  hugePages,
+			IsolatedCores: isolatedCores,
+			SRIOVPools:    []v1alpha1.SRIOVPool{{Name: "ens1f0", ResourceName: sriovA, VFs: vfs}},
+		},
+	}
+}
+
+func makeNFVPod(name, nodeName, hugePages, vfs, isolatedCores string) *v1.Pod {
+	requests := map[v1.ResourceName]string{v1.ResourceCPU: "1"}
+	if hugePages != "" {
+		requests[hugePages1Gi] = hugePages
+		requests[v1.ResourceMemory] = "1Gi"
+	}
+	if vfs != "" {
+		requests[sriovA] = vfs
+	}
+	pod := st.MakePod().Name(name).UID(name).Namespace("cnf").Node(nodeName).Req(requests).Obj()
+	if isolatedCores != "" {
+		pod.Annotations = map[string]string{IsolatedCoresAnnotation: isolatedCores}
+	}
+	return pod
+}
+
+func TestNFVAware(t *testing.T) {
+	// node-1 and node-2 have the same capabilities, node-1 already runs a CNF, node-3 has no NFV capability.
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node-1").Obj(),
+		st.MakeNode().Name("node-2").Obj(),
+		st.MakeNode().Name("node-3").Obj(),
+	}
+	capabilities := []runtime.Object{
+		makeCapability("node-1", 8, 4, 8),
+		makeCapability("node-2", 8, 4, 8),
+	}
+	existingPods := []*v1.Pod{
+		makeNFVPod("upf-0", "node-1", "4Gi", "6", "2"),
+	}
+
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		wantPreFilter framework.Code
+		wantFilter    map[string]framework.Code
+		wantReasons   map[string][]string
+		wantScores    framework.NodeScoreList
+	}{
+		{
+			name:          "pod without NFV requirements",
+			pod:           makeNFVPod("web", "", "", "", ""),
+			wantPreFilter: framework.Skip,
+		},
+		{
+			name: "pod with extended resources other than virtual functions",
+			pod: func() *v1.Pod {
+				pod := makeNFVPod("ml", "", "", "", "")
+				pod.Spec.Containers[0].Resources.Requests["example.com/gpu"] = *pod.Spec.Containers[0].Resources.Requests.Cpu()
+				return pod
+			}(),
+			wantPreFilter: framework.Skip,
+		},
+		{
+			name:          "invalid isolated cores annotation",
+			pod:           makeNFVPod("upf-1", "", "", "", "two"),
+			wantPreFilter: framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:          "virtual functions packed in the most allocated pool",
+			pod:           makeNFVPod("upf-1", "", "4Gi", "2", "2"),
+			wantPreFilter: framework.Success,
+			wantFilter: map[string]framework.Code{
+				"node-1": framework.Success,
+				"node-2": framework.Success,
+				"node-3": framework.UnschedulableAndUnresolvable,
+			},
+			// node-1: (6+2)/8 of its pool allocated, node-2: 2/8.
+			wantScores: framework.NodeScoreList{
+				{Name: "node-1", Score: framework.MaxNodeScore},
+				{Name: "node-2", Score: 25},
+			},
+		},
+		{
+			name:          "insufficient virtual functions",
+			pod:           makeNFVPod("upf-1", "", "", "3", ""),
+			wantPreFilter: framework.Success,
+			wantFilter: map[string]framework.Code{
+				"node-1": framework.Unschedulable,
+				"node-2": framework.Success,
+			},
+			wantReasons: map[string][]string{
+				"node-1": {"Insufficient virtual functions of " + sriovA},
+			},
+		},
+		{
+			name:          "insufficient hugepages and isolated cores in a single pass",
+			pod:           makeNFVPod("upf-1", "", "5Gi", "", "3"),
+			wantPreFilter: framework.Success,
+			wantFilter: map[string]framework.Code{
+				"node-1": framework.Unschedulable,
+				"node-2": framework.Success,
+			},
+			wantReasons: map[string][]string{
+				"node-1": {"Insufficient 1Gi hugepages", "Insufficient isolated cores"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			scheme := runtime.NewScheme()
+			if err := v1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			fwk, err := tf.NewFramework(ctx, registeredPlugins, "default-scheduler",
+				frameworkruntime.WithSnapshotSharedLister(tu.NewFakeSharedLister(existingPods, nodes)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			na := &NFVAware{handle: fwk, client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(capabilities...).Build()}
+			state := framework.NewCycleState()
+			if _, status := na.PreFilter(ctx, state, tt.pod); status.Code() != tt.wantPreFilter {
+				t.Fatalf("unexpected PreFilter status: %v, want code %v", status, tt.wantPreFilter)
+			}
+			for _, node := range nodes {
+				want, ok := tt.wantFilter[node.Name]
+				if !ok {
+					continue
+				}
+				nodeInfo, _ := na.handle.SnapshotSharedLister().NodeInfos().Get(node.Name)
+				status := na.Filter(ctx, state, tt.pod, nodeInfo)
+				if status.Code() != want {
+					t.Errorf("unexpected Filter status on %v: %v, want code %v", node.Name, status, want)
+				}
+				if reasons, ok := tt.wantReasons[node.Name]; ok && !reflect.DeepEqual(status.Reasons(), reasons) {
+					t.Errorf("unexpected Filter reasons on %v: %v, want %v", node.Name, status.Reasons(), reasons)
+				}
+			}
+			if tt.wantScores == nil {
+				return
+			}
+			var scores framework.NodeScoreList
+			for _, want := range tt.wantScores {
+				score, status := na.Score(ctx, state, tt.pod, want.Name)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected Score status on %v: %v", want.Name, status)
+				}
+				scores = append(scores, framework.NodeScore{Name: want.Name, Score: score})
+			}
+			if !reflect.DeepEqual(scores, tt.wantScores) {
+				t.Errorf("unexpected scores %v, want %v", scores, tt.wantScores)
+			}
+		})
+	}
+}
@@ -36,6 +36,7 @@ import (
 // resources.
 type Allocatable struct {
 	handle framework.Handle
+	mode   config.ModeType
 	resourceAllocationScorer
 }
 
@@ -67,6 +68,10 @@ func (alloc *Allocatable) Score(ctx context.Context, state *framework.CycleState
 		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
 
+	if alloc.mode == config.PodSlots {
+		return podSlotsScore(nodeInfo)
+	}
+
 	// alloc.score favors nodes with least allocatable or most allocatable resources.
 	// It calculates the sum of the node's weighted allocatable resources.
 	//
@@ -84,6 +89,7 @@ func NewAllocatable(ctx context.Context, allocArgs runtime.Object, h framework.H
 	logger := klog.FromContext(ctx)
 	// Start with default values.
 	mode := config.Least
+	aggregation := config.WeightedSum
 	resToWeightMap := defaultResourcesToWeightMap
 
 	// Update values from args, if specified.
@@ -94,10 +100,16 @@ func NewAllocatable(ctx context.Context, allocArgs runtime.Object, h framework.H
 		}
 		if args.Mode != "" {
 			mode = args.Mode
-			if mode != config.Least && mode != config.Most {
+			if mode != config.Least && mode != config.Most && mode != config.PodSlots && mode != config.Ratio {
 				return nil, fmt.Errorf("invalid mode, got %s", mode)
 			}
 		}
+		if args.Aggregation != "" {
+			aggregation = args.Aggregation
+			if aggregation != config.WeightedSum && aggregation != config.MinRatio && aggregation != config.Product {
+				return nil, fmt.Errorf("invalid aggregation, got %s", aggregation)
+			}
+		}
 
 		if len(args.Resources) > 0 {
 			if err := validateResources(args.Resources); err != nil {
@@ -113,15 +125,19 @@ func NewAllocatable(ctx context.Context, allocArgs runtime.Object, h framework.H
 
 	return &Allocatable{
 		handle: h,
+		mode:   mode,
 		resourceAllocationScorer: resourceAllocationScorer{
 			Name:                AllocatableName,
-			scorer:              resourceScorer(logger, resToWeightMap, mode),
+			scorer:              resourceScorer(logger, resToWeightMap, mode, aggregation),
 			resourceToWeightMap: resToWeightMap,
 		},
 	}, nil
 }
 
-func resourceScorer(logger klog.Logger, resToWeightMap resourceToWeightMap, mode config.ModeType) func(resourceToValueMap, resourceToValueMap) int64 {
+func resourceScorer(logger klog.Logger, resToWeightMap resourceToWeightMap, mode config.ModeType, aggregation config.AggregationType) func(resourceToValueMap, resourceToValueMap) int64 {
+	if mode == config.Ratio {
+		return ratioScorer(resToWeightMap, aggregation)
+	}
 	return func(requested, allocable resourceToValueMap) int64 {
 		// TODO: consider volumes in scoring.
 		var nodeScore, weightSum int64
@@ -134,6 +150,49 @@ func resourceScorer(logger klog.Logger, resToWeightMap resourceToWeightMap, mode
 	}
 }
 
+// ratioScorer favors nodes with the largest ratios of resources left once the pod is placed, from MinNodeScore to
+// MaxNodeScore, aggregated as their weighted sum, their minimum or their weighted product, i.e. weighted geometric
+// mean. The minimum and the product favor the nodes with every resource left over the nodes with a resource nearly
+// exhausted. The resources the node doesn't have, e.g. GPUs on a CPU node, are ignored.
+func ratioScorer(resToWeightMap resourceToWeightMap, aggregation config.AggregationType) func(resourceToValueMap, resourceToValueMap) int64 {
+	return func(requested, allocable resourceToValueMap) int64 {
+		ratios := make(resourceToValueMap, len(resToWeightMap))
+		var weightSum int64
+		for resource, weight := range resToWeightMap {
+			if allocable[resource] <= 0 {
+				continue
+			}
+			ratios[resource] = max(allocable[resource]-requested[resource], 0) * framework.MaxNodeScore / allocable[resource]
+			weightSum += weight
+		}
+		if len(ratios) == 0 {
+			return framework.MinNodeScore
+		}
+
+		switch aggregation {
+		case config.MinRatio:
+			nodeScore := framework.MaxNodeScore
+			for _, ratio := range ratios {
+				nodeScore = min(nodeScore, ratio)
+			}
+			return nodeScore
+		case config.Product:
+			product := 1.0
+			for resource, ratio := range ratios {
+				weight := float64(resToWeightMap[resource]) / float64(weightSum)
+				product *= math.Pow(float64(ratio)/float64(framework.MaxNodeScore), weight)
+			}
+			return int64(math.Round(product * float64(framework.MaxNodeScore)))
+		default:
+			var nodeScore int64
+			for resource, ratio := range ratios {
+				nodeScore += ratio * resToWeightMap[resource]
+			}
+			return nodeScore / weightSum
+		}
+	}
+}
+
 func score(logger klog.Logger, capacity int64, mode config.ModeType) int64 {
 	switch mode {
 	case config.Least:
@@ -146,6 +205,15 @@ func score(logger klog.Logger, capacity int64, mode config.ModeType) int64 {
 	return 0
 }
 
+// podSlotsScore favors nodes with the most pod slots remaining: the number of pods the node allows minus
+// the pods already on it. It captures nodes running out of pods before running out of CPU or memory.
+func podSlotsScore(nodeInfo *framework.NodeInfo) (int64, *framework.Status) {
+	if nodeInfo.Node() == nil {
+		return 0, framework.NewStatus(framework.Error, "node not found")
+	}
+	return int64(nodeInfo.Allocatable.AllowedPodNumber - len(nodeInfo.Pods)), nil
+}
+
 // NormalizeScore invoked after scoring all nodes.
 func (alloc *Allocatable) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
 	// Find highest and lowest scores.
@@ -18,12 +18,14 @@ package noderesources
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
 	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
@@ -99,6 +101,21 @@ func TestNodeResourcesAllocatable(t *testing.T) {
 
 	modeLeast := config.Least
 	modeMost := config.Most
+	modePodSlots := config.PodSlots
+	modeRatio := config.Ratio
+
+	// ratios of CPU and memory left weighted the same.
+	ratioResourceAllocatableSet := []schedulerconfig.ResourceSpec{
+		{Name: string(v1.ResourceCPU), Weight: 1},
+		{Name: string(v1.ResourceMemory), Weight: 1},
+	}
+	// CPU left after cpuAndMemory: 50%, 75%, 90%; memory left: 93%, 75%, 50%.
+	ratioNodeInfos := func() []*framework.NodeInfo {
+		return []*framework.NodeInfo{
+			makeNodeInfo("machine1", 2000, 16*1<<30),
+			makeNodeInfo("machine2", 4000, 4*1<<30),
+			makeNodeInfo("machine3", 10000, 2*1<<30)}
+	}
 	tests := []struct {
 		pod          *v1.Pod
 		pods         []*v1.Pod
@@ -216,6 +233,88 @@ func TestNodeResourcesAllocatable(t *testing.T) {
 				{Name: "machine3", Score: framework.MaxNodeScore}},
 			name: "nothing scheduled, resources requested, 3 differently sized machines, most mode",
 		},
+		{
+			pod: cpuAndMemory,
+			nodeInfos: []*framework.NodeInfo{
+				makePodSlotsNodeInfo("machine1", 10, 8),
+				makePodSlotsNodeInfo("machine2", 10, 2)},
+			args: config.NodeResourcesAllocatableArgs{Mode: modePodSlots},
+			expectedList: []framework.NodeScore{
+				{Name: "machine1", Score: framework.MinNodeScore},
+				{Name: "machine2", Score: framework.MaxNodeScore}},
+			name: "pods scheduled, same max pods, pod slots mode",
+		},
+		{
+			pod: cpuAndMemory,
+			nodeInfos: []*framework.NodeInfo{
+				makePodSlotsNodeInfo("machine1", 110, 100),
+				makePodSlotsNodeInfo("machine2", 20, 0),
+				makePodSlotsNodeInfo("machine3", 30, 15)},
+			args: config.NodeResourcesAllocatableArgs{Resources: defaultResourceAllocatableSet, Mode: modePodSlots},
+			expectedList: []framework.NodeScore{
+				{Name: "machine1", Score: framework.MinNodeScore},
+				{Name: "machine2", Score: framework.MaxNodeScore},
+				{Name: "machine3", Score: (framework.MinNodeScore + framework.MaxNodeScore) / 2}},
+			name: "pods scheduled, differently sized max pods, pod slots mode",
+		},
+		{
+			pod:       cpuAndMemory,
+			nodeInfos: ratioNodeInfos(),
+			args:      config.NodeResourcesAllocatableArgs{Resources: ratioResourceAllocatableSet, Mode: modeRatio},
+			// ratios of 71, 75 and 70
+			expectedList: []framework.NodeScore{
+				{Name: "machine1", Score: 20},
+				{Name: "machine2", Score: framework.MaxNodeScore},
+				{Name: "machine3", Score: framework.MinNodeScore}},
+			name: "nothing scheduled, resources requested, ratio mode, weighted sum",
+		},
+		{
+			pod:       cpuAndMemory,
+			nodeInfos: ratioNodeInfos(),
+			args:      config.NodeResourcesAllocatableArgs{Resources: ratioResourceAllocatableSet, Mode: modeRatio, Aggregation: config.MinRatio},
+			// ratios of 50, 75 and 50
+			expectedList: []framework.NodeScore{
+				{Name: "machine1", Score: framework.MinNodeScore},
+				{Name: "machine2", Score: framework.MaxNodeScore},
+				{Name: "machine3", Score: framework.MinNodeScore}},
+			name: "nothing scheduled, resources requested, ratio mode, min ratio",
+		},
+		{
+			pod:       cpuAndMemory,
+			nodeInfos: ratioNodeInfos(),
+			args:      config.NodeResourcesAllocatableArgs{Resources: ratioResourceAllocatableSet, Mode: modeRatio, Aggregation: config.Product},
+			// ratios of 68, 75 and 67
+			expectedList: []framework.NodeScore{
+				{Name: "machine1", Score: 12},
+				{Name: "machine2", Score: framework.MaxNodeScore},
+				{Name: "machine3", Score: framework.MinNodeScore}},
+			name: "nothing scheduled, resources requested, ratio mode, product",
+		},
+		{
+			pod: cpuAndMemory,
+			nodeInfos: []*framework.NodeInfo{
+				makeNodeInfo("machine1", 4000, 4*1<<30),
+				makeGPUNodeInfo("machine2", 4000, 4*1<<30, 4, 0),
+				makeGPUNodeInfo("machine3", 4000, 4*1<<30, 1, 1)},
+			args: config.NodeResourcesAllocatableArgs{Resources: []schedulerconfig.ResourceSpec{
+				{Name: string(v1.ResourceCPU), Weight: 1},
+				{Name: string(v1.ResourceMemory), Weight: 1},
+				{Name: "nvidia.com/gpu", Weight: 2},
+			}, Mode: modeRatio, Aggregation: config.MinRatio},
+			// ratios of 75, ignoring the GPUs machine1 doesn't have, 75 and 0
+			expectedList: []framework.NodeScore{
+				{Name: "machine1", Score: framework.MaxNodeScore},
+				{Name: "machine2", Score: framework.MaxNodeScore},
+				{Name: "machine3", Score: framework.MinNodeScore}},
+			name: "GPU pod scheduled, resources requested, ratio mode, min ratio",
+		},
+		{
+			pod:       cpuAndMemory,
+			nodeInfos: []*framework.NodeInfo{makeNodeInfo("machine", 4000, 10000)},
+			args:      config.NodeResourcesAllocatableArgs{Mode: modeRatio, Aggregation: "Max"},
+			wantErr:   "invalid aggregation, got Max",
+			name:      "invalid aggregation",
+		},
 		{
 			// resource with negative weight is not allowed
 			pod:       cpuAndMemory,
@@ -321,6 +420,42 @@ func makeNodeInfo(node string, milliCPU, memory int64) *framework.NodeInfo {
 	return ni
 }
 
+// makePodSlotsNodeInfo returns a node allowing maxPods pods, with the given number of pods on it.
+func makeGPUNodeInfo(node string, milliCPU, memory, gpus, usedGPUs int64) *framework.NodeInfo {
+	ni := framework.NewNodeInfo()
+	if usedGPUs > 0 {
+		ni.AddPod(makePod(node+"-gpu-pod", v1.ResourceList{
+			v1.ResourceName("nvidia.com/gpu"): *resource.NewQuantity(usedGPUs, resource.DecimalSI),
+		}))
+	}
+	resources := v1.ResourceList{
+		v1.ResourceCPU:                    *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+		v1.ResourceMemory:                 *resource.NewQuantity(memory, resource.BinarySI),
+		v1.ResourceName("nvidia.com/gpu"): *resource.NewQuantity(gpus, resource.DecimalSI),
+	}
+	ni.SetNode(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: node},
+		Status:     v1.NodeStatus{Capacity: resources, Allocatable: resources},
+	})
+	return ni
+}
+
+func makePodSlotsNodeInfo(node string, maxPods int64, pods int) *framework.NodeInfo {
+	ni := framework.NewNodeInfo()
+	for i := 0; i < pods; i++ {
+		ni.AddPod(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%v-pod%v", node, i), UID: types.UID(fmt.Sprintf("%v-pod%v", node, i))}})
+	}
+	ni.SetNode(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: node},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourcePods: *resource.NewQuantity(maxPods, resource.DecimalSI),
+			},
+		},
+	})
+	return ni
+}
+
 func makePod(name string, requests v1.ResourceList) *v1.Pod {
 	return &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/helper"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// Fragmentation is a score plugin that favors the placements leaving the nodes less fragmented, i.e. with fewer
+// resources stranded beside a slot too small for the pods, so as to preserve large slots for big pods.
+type Fragmentation struct {
+	handle    framework.Handle
+	resources resourceToWeightMap
+	shape     func(int64) int64
+}
+
+var _ = framework.ScorePlugin(&Fragmentation{})
+
+// FragmentationName is the name of the plugin used in the Registry and configurations.
+const FragmentationName = "NodeResourcesFragmentation"
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (f *Fragmentation) Name() string {
+	return FragmentationName
+}
+
+// NewFragmentation initializes a new plugin and returns it.
+func NewFragmentation(_ context.Context, obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.NodeResourcesFragmentationArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type NodeResourcesFragmentationArgs, got %T", obj)
+	}
+	if len(args.Resources) == 0 {
+		return nil, fmt.Errorf("resources must not be empty")
+	}
+	if err := validateResources(args.Resources); err != nil {
+		return nil, err
+	}
+	if err := validateShape(args.Shape); err != nil {
+		return nil, err
+	}
+
+	resources := make(resourceToWeightMap, len(args.Resources))
+	for _, resource := range args.Resources {
+		resources[v1.ResourceName(resource.Name)] = resource.Weight
+	}
+	shape := make(helper.FunctionShape, 0, len(args.Shape))
+	for _, point := range args.Shape {
+		shape = append(shape, helper.FunctionShapePoint{
+			Utilization: int64(point.Utilization),
+			// scale the fragmentation to the node scores
+			Score: int64(point.Score) * (framework.MaxNodeScore / schedulerconfig.MaxCustomPriorityScore),
+		})
+	}
+	return &Fragmentation{
+		handle:    h,
+		resources: resources,
+		shape:     helper.BuildBrokenLinearFunction(shape),
+	}, nil
+}
+
+func validateShape(shape []schedulerconfig.UtilizationShapePoint) error {
+	if len(shape) == 0 {
+		return fmt.Errorf("shape must not be empty")
+	}
+	for i, point := range shape {
+		if point.Utilization < 0 || point.Utilization > 100 {
+			return fmt.Errorf("shape utilization must be between 0 and 100, got %v", point.Utilization)
+		}
+		if point.Score < 0 || int64(point.Score) > schedulerconfig.MaxCustomPriorityScore {
+			return fmt.Errorf("shape score must be between 0 and %v, got %v", schedulerconfig.MaxCustomPriorityScore, point.Score)
+		}
+		if i > 0 && point.Utilization <= shape[i-1].Utilization {
+			return fmt.Errorf("shape utilizations must be sorted in increasing order, got %v after %v", point.Utilization, shape[i-1].Utilization)
+		}
+	}
+	return nil
+}
+
+// Score invoked at the score extension point. The score is the reduction of the fragmentation of the node by the
+// pod, mapped from [-MaxNodeScore, MaxNodeScore] to [MinNodeScore, MaxNodeScore]: placing the pod where it fills a
+// fragmented node scores above placing it on a node left intact, itself scoring above fragmenting a node.
+func (f *Fragmentation) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	logger := klog.FromContext(ctx)
+	nodeInfo, err := f.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	if nodeInfo.Node() == nil {
+		return 0, framework.NewStatus(framework.Error, "node not found")
+	}
+
+	podRequests := resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{})
+	before := f.fragmentation(nodeInfo, nil)
+	after := f.fragmentation(nodeInfo, podRequests)
+	score := (framework.MaxNodeScore + before - after) / 2
+	logger.V(10).Info("Fragmentation of the node", "pod", klog.KObj(pod), "node", nodeName,
+		"before", before, "after", after, "score", score)
+	return score, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (f *Fragmentation) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// fragmentation returns the fragmentation of the node once the requests are placed on it, from 0 to MaxNodeScore:
+// the weighted mean of the resources left on the node, in percent of its allocatable resources, stranded beside
+// its largest slot. The largest slot is the smallest part of the resources left, the shape giving how much of the
+// resources left beside it are stranded. The resources the node doesn't have are ignored.
+func (f *Fragmentation) fragmentation(nodeInfo *framework.NodeInfo, requests v1.ResourceList) int64 {
+	left := make(map[v1.ResourceName]int64, len(f.resources))
+	slot := int64(100)
+	for resource := range f.resources {
+		allocatable, requested := allocatableRequested(nodeInfo, resource)
+		if allocatable <= 0 {
+			continue
+		}
+		if quantity, ok := requests[resource]; ok {
+			if resource == v1.ResourceCPU {
+				requested += quantity.MilliValue()
+			} else {
+				requested += quantity.Value()
+			}
+		}
+		left[resource] = min(max((allocatable-requested)*100/allocatable, 0), 100)
+		slot = min(slot, left[resource])
+	}
+	if len(left) == 0 {
+		return 0
+	}
+
+	stranded := f.shape(slot)
+	var fragmentation, weightSum int64
+	for resource, percent := range left {
+		fragmentation += f.resources[resource] * percent * stranded / framework.MaxNodeScore
+		weightSum += f.resources[resource]
+	}
+	return fragmentation / weightSum
+}
+
+// allocatableRequested returns the allocatable and requested quantities of the resource on the node, in millicores
+// for the CPU.
+func allocatableRequested(nodeInfo *framework.NodeInfo, resource v1.ResourceName) (int64, int64) {
+	switch resource {
+	case v1.ResourceCPU:
+		return nodeInfo.Allocatable.MilliCPU, nodeInfo.Requested.MilliCPU
+	case v1.ResourceMemory:
+		return nodeInfo.Allocatable.Memory, nodeInfo.Requested.Memory
+	case v1.ResourceEphemeralStorage:
+		return nodeInfo.Allocatable.EphemeralStorage, nodeInfo.Requested.EphemeralStorage
+	default:
+		return nodeInfo.Allocatable.ScalarResources[resource], nodeInfo.Requested.ScalarResources[resource]
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+var defaultFragmentationArgs = config.NodeResourcesFragmentationArgs{
+	Resources: []schedulerconfig.ResourceSpec{{Name: "cpu", Weight: 1}, {Name: "memory", Weight: 1}},
+	Shape: []schedulerconfig.UtilizationShapePoint{
+		{Utilization: 0, Score: 10}, {Utilization: 10, Score: 10}, {Utilization: 30, Score: 0},
+	},
+}
+
+// makeUsedNodeInfo returns a node of 4 CPUs and 16Gi of memory, with a pod requesting the given resources on it.
+func makeUsedNodeInfo(node string, milliCPU, memoryGi int64) *framework.NodeInfo {
+	ni := makeNodeInfo(node, 4000, 16<<30)
+	ni.AddPod(makePod(node+"-pod", v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+		v1.ResourceMemory: *resource.NewQuantity(memoryGi<<30, resource.BinarySI),
+	}))
+	return ni
+}
+
+func TestNodeResourcesFragmentation(t *testing.T) {
+	pod := makePod("pod", v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("400m"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+	})
+	nodeInfos := []*framework.NodeInfo{
+		// left 90% of the CPUs and 75% of the memory: a large slot
+		makeUsedNodeInfo("empty", 0, 0),
+		// fragmented from 10% of the CPUs and 62% of the memory left, to no CPU and 37% of the memory left
+		makeUsedNodeInfo("fragmented", 3600, 6),
+		// left 40% of the CPUs and 62% of the memory: a large slot
+		makeUsedNodeInfo("half-used", 2000, 2),
+		// fragmented from 20% of the CPUs and all the memory left, to 10% of the CPUs and 75% of the memory left
+		makeUsedNodeInfo("cpu-used", 3200, 0),
+	}
+	expected := map[string]int64{
+		"empty":      50,
+		"fragmented": 59,
+		"half-used":  50,
+		"cpu-used":   44,
+	}
+
+	ctx := context.Background()
+	fh, err := tf.NewFramework(
+		ctx,
+		[]tf.RegisterPluginFunc{
+			tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		},
+		"default-scheduler",
+		frameworkruntime.WithClientSet(clientsetfake.NewSimpleClientset()),
+		frameworkruntime.WithSnapshotSharedLister(&fakeSharedLister{nodes: nodeInfos}),
+	)
+	if err != nil {
+		t.Fatalf("fail to create framework: %s", err)
+	}
+	p, err := NewFragmentation(ctx, &defaultFragmentationArgs, fh)
+	if err != nil {
+		t.Fatalf("failed to initialize plugin NodeResourcesFragmentation, got error: %v", err)
+	}
+	for _, nodeInfo := range nodeInfos {
+		name := nodeInfo.Node().Name
+		score, status := p.(framework.ScorePlugin).Score(ctx, nil, pod, name)
+		if !status.IsSuccess() {
+			t.Fatalf("unexpected error: %v", status)
+		}
+		if score != expected[name] {
+			t.Errorf("node %v: expected score %v, got %v", name, expected[name], score)
+		}
+	}
+}
+
+func TestNewFragmentationInvalidArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    config.NodeResourcesFragmentationArgs
+		wantErr string
+	}{
+		{
+			name: "resource with zero weight",
+			args: config.NodeResourcesFragmentationArgs{
+				Resources: []schedulerconfig.ResourceSpec{{Name: "cpu", Weight: 0}},
+				Shape:     defaultFragmentationArgs.Shape,
+			},
+			wantErr: "resource Weight of cpu should be a positive value, got 0",
+		},
+		{
+			name:    "empty shape",
+			args:    config.NodeResourcesFragmentationArgs{Resources: defaultFragmentationArgs.Resources},
+			wantErr: "shape must not be empty",
+		},
+		{
+			name: "unsorted shape",
+			args: config.NodeResourcesFragmentationArgs{
+				Resources: defaultFragmentationArgs.Resources,
+				Shape:     []schedulerconfig.UtilizationShapePoint{{Utilization: 30, Score: 0}, {Utilization: 10, Score: 10}},
+			},
+			wantErr: "shape utilizations must be sorted in increasing order, got 10 after 30",
+		},
+		{
+			name: "shape score above 10",
+			args: config.NodeResourcesFragmentationArgs{
+				Resources: defaultFragmentationArgs.Resources,
+				Shape:     []schedulerconfig.UtilizationShapePoint{{Utilization: 0, Score: 100}},
+			},
+			wantErr: "shape score must be between 0 and 10, got 100",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFragmentation(context.Background(), &tt.args, nil)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ package noderesources
 
 import (
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
@@ -107,13 +108,13 @@ func calculatePodResourceRequest(pod *v1.Pod, resource v1.ResourceName) int64 {
 	for i := range pod.Spec.Containers {
 		container := &pod.Spec.Containers[i]
 		qty := schedutil.GetRequestForResource(resource, &container.Resources.Requests, true)
-		podRequest += qty.Value()
+		podRequest += quantityValue(resource, qty)
 	}
 
 	for i := range pod.Spec.InitContainers {
 		initContainer := &pod.Spec.InitContainers[i]
 		qty := schedutil.GetRequestForResource(resource, &initContainer.Resources.Requests, true)
-		if value := qty.Value(); podRequest < value {
+		if value := quantityValue(resource, qty); podRequest < value {
 			podRequest = value
 		}
 	}
@@ -121,9 +122,18 @@ func calculatePodResourceRequest(pod *v1.Pod, resource v1.ResourceName) int64 {
 	// If Overhead is being utilized, add to the total requests for the pod
 	if pod.Spec.Overhead != nil {
 		if quantity, found := pod.Spec.Overhead[resource]; found {
-			podRequest += quantity.Value()
+			podRequest += quantityValue(resource, quantity)
 		}
 	}
 
 	return podRequest
 }
+
+// quantityValue returns the value of the quantity in the base unit of the resource, i.e. in millicores for CPU, as
+// the node's allocatable and requested resources.
+func quantityValue(resource v1.ResourceName, quantity resource.Quantity) int64 {
+	if resource == v1.ResourceCPU {
+		return quantity.MilliValue()
+	}
+	return quantity.Value()
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const metricsSubsystem = "noderesourcetopology_cache"
+
+// Results of the lookups of the NRT data of a node.
+const (
+	// LookupClean means the NRT data had no over-reserved resources to deduct.
+	LookupClean = "clean"
+	// LookupOverReserved means over-reserved resources were deducted from the NRT data.
+	LookupOverReserved = "overreserved"
+	// LookupDiscarded means the node was excluded until its next resync, e.g. it runs foreign pods.
+	LookupDiscarded = "discarded"
+	// LookupMissing means there is no NRT data for the node.
+	LookupMissing = "missing"
+)
+
+// Reasons for marking a node dirty, i.e. a candidate for resync.
+const (
+	// DirtyOverReserved means the node was filtered out, possibly because it was pessimistically overallocated.
+	DirtyOverReserved = "overreserved"
+	// DirtyForeignPods means the node runs pods not scheduled by this scheduler.
+	DirtyForeignPods = "foreign_pods"
+	// DirtyConfigChanged means the node received a metadata update.
+	DirtyConfigChanged = "config_changed"
+)
+
+var (
+	lookups = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "lookups_total",
+			Help:           "Number of lookups of the NRT data of a node by result: clean, overreserved, discarded or missing.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"result"})
+
+	nodesDiscarded = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "nodes_discarded_total",
+			Help:           "Number of times a node was marked as a candidate for resync by reason: overreserved or foreign_pods.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"reason"})
+
+	overReservedRejections = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "overreserved_rejections_total",
+			Help:           "Number of times a pod was filtered out of a node whose NRT data had over-reserved resources deducted, i.e. possibly rejected because of the cache conservatism.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	dirtyNodes = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "dirty_nodes",
+			Help:           "Number of nodes waiting for a resync by reason: overreserved, foreign_pods or config_changed, as of the last resync.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"reason"})
+
+	staleDuration = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "stale_duration_seconds",
+			Help:           "Time between a node being marked as a candidate for resync and its NRT data being resynced, in seconds.",
+			Buckets:        metrics.ExponentialBuckets(1, 2, 12),
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	metricsList = []metrics.Registerable{
+		lookups,
+		nodesDiscarded,
+		overReservedRejections,
+		dirtyNodes,
+		staleDuration,
+	}
+)
+
+var registerMetrics sync.Once
+
+// RegisterMetrics registers the metrics of the NRT cache in the legacy registry, exposed by the scheduler.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		for _, metric := range metricsList {
+			legacyregistry.MustRegister(metric)
+		}
+	})
+}
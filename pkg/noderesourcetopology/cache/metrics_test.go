@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/klog/v2"
+
+	tu "github.com/amiraBenamer20/scheduler-plugins/test/util"
+)
+
+func TestOverReserveMetrics(t *testing.T) {
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nrtCache := mustOverReserve(t, fakeClient, &fakePodLister{})
+	nodeTopologies := makeDefaultTestTopology()
+	node2 := nodeTopologies[0].DeepCopy()
+	node2.Name = "node2"
+	nodeTopologies = append(nodeTopologies, node2)
+	for _, obj := range nodeTopologies {
+		nrtCache.Store().Update(obj)
+	}
+
+	testPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("2"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	counter := func(m metrics.CounterMetric) float64 {
+		t.Helper()
+		val, err := testutil.GetCounterMetricValue(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return val
+	}
+	expectDelta := func(name string, m metrics.CounterMetric, before, delta float64) {
+		t.Helper()
+		if got := counter(m) - before; got != delta {
+			t.Errorf("%s: expected delta %v, got %v", name, delta, got)
+		}
+	}
+
+	clean := counter(lookups.WithLabelValues(LookupClean))
+	overReserved := counter(lookups.WithLabelValues(LookupOverReserved))
+	discarded := counter(lookups.WithLabelValues(LookupDiscarded))
+	missing := counter(lookups.WithLabelValues(LookupMissing))
+	discardedOverReserved := counter(nodesDiscarded.WithLabelValues(DirtyOverReserved))
+	discardedForeignPods := counter(nodesDiscarded.WithLabelValues(DirtyForeignPods))
+	rejections := counter(overReservedRejections)
+	stale, err := testutil.GetHistogramMetricCount(staleDuration.ObserverMetric)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	nrtCache.GetCachedNRTCopy(ctx, "node1", testPod)
+	nrtCache.GetCachedNRTCopy(ctx, "node-bogus", testPod)
+	expectDelta("clean lookups", lookups.WithLabelValues(LookupClean), clean, 1)
+	expectDelta("missing lookups", lookups.WithLabelValues(LookupMissing), missing, 1)
+
+	nrtCache.ReserveNodeResources("node1", testPod)
+	nrtCache.GetCachedNRTCopy(ctx, "node1", testPod)
+	expectDelta("overreserved lookups", lookups.WithLabelValues(LookupOverReserved), overReserved, 1)
+
+	// only the rejections on nodes with over-reserved resources are accounted to the cache conservatism
+	nrtCache.NodeMaybeOverReserved("node1", testPod)
+	nrtCache.NodeMaybeOverReserved("node2", testPod)
+	expectDelta("overreserved discarded nodes", nodesDiscarded.WithLabelValues(DirtyOverReserved), discardedOverReserved, 2)
+	expectDelta("overreserved rejections", overReservedRejections, rejections, 1)
+
+	nrtCache.NodeHasForeignPods("node2", testPod)
+	nrtCache.GetCachedNRTCopy(ctx, "node2", testPod)
+	expectDelta("foreign pods discarded nodes", nodesDiscarded.WithLabelValues(DirtyForeignPods), discardedForeignPods, 1)
+	expectDelta("discarded lookups", lookups.WithLabelValues(LookupDiscarded), discarded, 1)
+
+	lh := klog.Background()
+	nrtCache.GetDesyncedNodes(lh)
+	for reason, expected := range map[string]float64{DirtyOverReserved: 2, DirtyForeignPods: 1, DirtyConfigChanged: 0} {
+		val, err := testutil.GetGaugeMetricValue(dirtyNodes.WithLabelValues(reason))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val != expected {
+			t.Errorf("dirty nodes %s: expected %v, got %v", reason, expected, val)
+		}
+	}
+
+	nrtCache.FlushNodes(lh, nodeTopologies...)
+	count, err := testutil.GetHistogramMetricCount(staleDuration.ObserverMetric)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count-stale != 2 {
+		t.Errorf("stale duration: expected 2 observations, got %v", count-stale)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
@@ -64,6 +65,8 @@ type OverReserve struct {
 	resyncMethod           apiconfig.CacheResyncMethod
 	resyncScope            apiconfig.CacheResyncScope
 	isPodRelevant          podprovider.PodFilterFunc
+	// dirtySince tracks when a node was first marked as a candidate for resync, to measure how long its data stays stale.
+	dirtySince map[string]time.Time // nodeName -> time
 }
 
 func NewOverReserve(ctx context.Context, lh logr.Logger, cfg *apiconfig.NodeResourceTopologyCache, client ctrlclient.WithWatch, podLister podlisterv1.PodLister, isPodRelevant podprovider.PodFilterFunc) (*OverReserve, error) {
@@ -80,6 +83,7 @@ func NewOverReserve(ctx context.Context, lh logr.Logger, cfg *apiconfig.NodeReso
 	resyncScope := getCacheResyncScope(lh, cfg)
 
 	lh.V(2).Info("initializing", "noderesourcetopologies", len(nrtObjs.Items), "method", resyncMethod, "scope", resyncScope)
+	RegisterMetrics()
 	obj := &OverReserve{
 		lh:                     lh,
 		client:                 client,
@@ -88,6 +92,7 @@ func NewOverReserve(ctx context.Context, lh logr.Logger, cfg *apiconfig.NodeReso
 		nodesMaybeOverreserved: newCounter(),
 		nodesWithForeignPods:   newCounter(),
 		nodesWithAttrUpdate:    newCounter(),
+		dirtySince:             make(map[string]time.Time),
 		podLister:              podLister,
 		resyncMethod:           resyncMethod,
 		isPodRelevant:          isPodRelevant,
@@ -109,20 +114,24 @@ func (ov *OverReserve) GetCachedNRTCopy(ctx context.Context, nodeName string, po
 	ov.lock.Lock()
 	defer ov.lock.Unlock()
 	if ov.nodesWithForeignPods.IsSet(nodeName) {
+		lookups.WithLabelValues(LookupDiscarded).Inc()
 		return nil, CachedNRTInfo{}
 	}
 
 	info := CachedNRTInfo{Fresh: true}
 	nrt := ov.nrts.GetNRTCopyByNodeName(nodeName)
 	if nrt == nil {
+		lookups.WithLabelValues(LookupMissing).Inc()
 		return nil, info
 	}
 
 	info.Generation = ov.generation
 	nodeAssumedResources, ok := ov.assumedResources[nodeName]
 	if !ok {
+		lookups.WithLabelValues(LookupClean).Inc()
 		return nrt, info
 	}
+	lookups.WithLabelValues(LookupOverReserved).Inc()
 
 	logID := klog.KObj(pod)
 	lh := ov.lh.WithValues(logging.KeyPod, logID, logging.KeyPodUID, logging.PodUID(pod), logging.KeyNode, nodeName, logging.KeyGeneration, ov.generation)
@@ -139,6 +148,11 @@ func (ov *OverReserve) NodeMaybeOverReserved(nodeName string, pod *corev1.Pod) {
 	defer ov.lock.Unlock()
 	val := ov.nodesMaybeOverreserved.Incr(nodeName)
 	ov.lh.V(4).Info("mark discarded", logging.KeyNode, nodeName, "count", val)
+	nodesDiscarded.WithLabelValues(DirtyOverReserved).Inc()
+	if _, ok := ov.assumedResources[nodeName]; ok {
+		overReservedRejections.Inc()
+	}
+	ov.markDirty(nodeName)
 }
 
 func (ov *OverReserve) NodeHasForeignPods(nodeName string, pod *corev1.Pod) {
@@ -151,6 +165,8 @@ func (ov *OverReserve) NodeHasForeignPods(nodeName string, pod *corev1.Pod) {
 	}
 	val := ov.nodesWithForeignPods.Incr(nodeName)
 	lh.V(2).Info("marked with foreign pods", logging.KeyNode, nodeName, "count", val)
+	nodesDiscarded.WithLabelValues(DirtyForeignPods).Inc()
+	ov.markDirty(nodeName)
 }
 
 func (ov *OverReserve) ReserveNodeResources(nodeName string, pod *corev1.Pod) {
@@ -168,6 +184,9 @@ func (ov *OverReserve) ReserveNodeResources(nodeName string, pod *corev1.Pod) {
 
 	ov.nodesMaybeOverreserved.Delete(nodeName)
 	lh.V(6).Info("reset discard counter", logging.KeyNode, nodeName)
+	if !ov.nodesWithForeignPods.IsSet(nodeName) {
+		delete(ov.dirtySince, nodeName)
+	}
 }
 
 func (ov *OverReserve) UnreserveNodeResources(nodeName string, pod *corev1.Pod) {
@@ -241,6 +260,10 @@ func (ov *OverReserve) GetDesyncedNodes(lh logr.Logger) DesyncedNodes {
 	configChangeNodes := ov.nodesWithAttrUpdate.Clone()
 	configChangeCount := configChangeNodes.Len()
 
+	dirtyNodes.WithLabelValues(DirtyForeignPods).Set(float64(foreignCount))
+	dirtyNodes.WithLabelValues(DirtyOverReserved).Set(float64(overreservedCount))
+	dirtyNodes.WithLabelValues(DirtyConfigChanged).Set(float64(configChangeCount))
+
 	if nodes.Len() > 0 {
 		lh.V(4).Info("found dirty nodes", "foreign", foreignCount, "discarded", overreservedCount, "configChange", configChangeCount, "total", nodes.Len())
 	}
@@ -352,8 +375,13 @@ func (ov *OverReserve) FlushNodes(lh logr.Logger, nrts ...*topologyv1alpha2.Node
 	ov.lock.Lock()
 	defer ov.lock.Unlock()
 
+	now := time.Now()
 	for _, nrt := range nrts {
 		lh.V(2).Info("flushing", logging.KeyNode, nrt.Name)
+		if since, ok := ov.dirtySince[nrt.Name]; ok {
+			staleDuration.Observe(now.Sub(since).Seconds())
+			delete(ov.dirtySince, nrt.Name)
+		}
 		ov.nrts.Update(nrt)
 		delete(ov.assumedResources, nrt.Name)
 		ov.nodesMaybeOverreserved.Delete(nrt.Name)
@@ -370,6 +398,13 @@ func (ov *OverReserve) FlushNodes(lh logr.Logger, nrts ...*topologyv1alpha2.Node
 	lh.V(2).Info("generation", "new", ov.generation)
 }
 
+// markDirty records when the node was first marked as a candidate for resync. Must be called with the lock held.
+func (ov *OverReserve) markDirty(nodeName string) {
+	if _, ok := ov.dirtySince[nodeName]; !ok {
+		ov.dirtySince[nodeName] = time.Now()
+	}
+}
+
 // to be used only in tests
 func (ov *OverReserve) Store() *nrtStore {
 	return ov.nrts
@@ -0,0 +1,290 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/go-logr/logr"
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	"github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2/helper/numanode"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/logging"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/nodeconfig"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/resourcerequests"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+const (
+	// NoNUMAID is the NUMA ID of alignments which cannot be satisfied by any NUMA node.
+	NoNUMAID = -1
+
+	// FitExplainPath is the suggested path to serve the fit explanation handler on.
+	FitExplainPath = "/debug/noderesourcetopology/explain"
+)
+
+// FitExplanation details the verdict of the NodeResourceTopologyMatch filter for a pod on a node,
+// answering "why doesn't my guaranteed pod fit this NUMA node?".
+type FitExplanation struct {
+	Node   string `json:"node"`
+	Policy string `json:"policy"`
+	Scope  string `json:"scope"`
+	// Fits is true if the topology manager of the node would admit the pod.
+	Fits bool `json:"fits"`
+	// Reason is a human readable summary of the verdict.
+	Reason string `json:"reason"`
+	// Alignments holds one entry per container with the container scope,
+	// or a single entry for the whole pod with the pod scope.
+	Alignments []AlignmentExplanation `json:"alignments,omitempty"`
+}
+
+// AlignmentExplanation details the alignment of the requests of a container, or of the whole pod, on the NUMA zones.
+type AlignmentExplanation struct {
+	// Container is the name of the container, empty with the pod scope.
+	Container string `json:"container,omitempty"`
+	// ContainerKind is either logging.KindContainerInit or logging.KindContainerApp, empty with the pod scope.
+	ContainerKind string          `json:"containerKind,omitempty"`
+	Requests      v1.ResourceList `json:"requests"`
+	// Fits is true if the requests can be aligned on a single NUMA zone.
+	Fits bool `json:"fits"`
+	// NUMAID is the NUMA zone the kubelet would select, NoNUMAID if none.
+	NUMAID int `json:"numaID"`
+	// MissingResources lists the requested resources not available at all on the node.
+	MissingResources []v1.ResourceName `json:"missingResources,omitempty"`
+	Zones            []ZoneFit         `json:"zones,omitempty"`
+}
+
+// ZoneFit details the fit of an alignment on a single NUMA zone.
+type ZoneFit struct {
+	Zone   string `json:"zone"`
+	NUMAID int    `json:"numaID"`
+	Fits   bool   `json:"fits"`
+	// Insufficient lists the requested resources the zone can't provide.
+	Insufficient []InsufficientResource `json:"insufficient,omitempty"`
+}
+
+// InsufficientResource is a resource requested beyond the availability of a NUMA zone.
+type InsufficientResource struct {
+	Resource  v1.ResourceName   `json:"resource"`
+	Requested resource.Quantity `json:"requested"`
+	Available resource.Quantity `json:"available"`
+}
+
+// ExplainFit returns the detailed fit of the pod on the node described by the NodeResourceTopology object,
+// following the same rules as the Filter extension point.
+// The node is optional: when nil, the node level resources are computed from the allocatable resources of the zones.
+func ExplainFit(lh logr.Logger, pod *v1.Pod, nodeTopology *topologyv1alpha2.NodeResourceTopology, node *v1.Node) FitExplanation {
+	if node == nil {
+		node = nodeFromNodeResourceTopology(nodeTopology)
+	}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+
+	conf := nodeconfig.TopologyManagerFromNodeResourceTopology(lh, nodeTopology)
+	expl := FitExplanation{
+		Node:   node.Name,
+		Policy: conf.Policy,
+		Scope:  conf.Scope,
+		Fits:   true,
+	}
+
+	if v1qos.GetPodQOS(pod) == v1.PodQOSBestEffort && !resourcerequests.IncludeNonNative(pod) {
+		expl.Reason = "best-effort pod without extended resources, no alignment required"
+		return expl
+	}
+	if conf.Policy != kubeletconfig.SingleNumaNodeTopologyManagerPolicy {
+		expl.Reason = "topology manager policy not enforced by the scheduler"
+		return expl
+	}
+
+	qos := v1qos.GetPodQOS(pod)
+	nodes := createNUMANodeList(lh, nodeTopology.Zones)
+	zoneNames := zoneNamesByNUMAID(nodeTopology.Zones)
+
+	if conf.Scope == kubeletconfig.PodTopologyManagerScope {
+		alignment := explainAlignment(lh, nodes, zoneNames, util.GetPodEffectiveRequest(pod), qos, nodeInfo)
+		expl.Alignments = append(expl.Alignments, alignment)
+		if !alignment.Fits {
+			expl.Fits = false
+			expl.Reason = "cannot align pod"
+			return expl
+		}
+		expl.Reason = "pod aligned"
+		return expl
+	}
+
+	for _, initContainer := range pod.Spec.InitContainers {
+		alignment := explainAlignment(lh, nodes, zoneNames, initContainer.Resources.Requests, qos, nodeInfo)
+		alignment.Container = initContainer.Name
+		alignment.ContainerKind = logging.KindContainerInit
+		expl.Alignments = append(expl.Alignments, alignment)
+		if !alignment.Fits {
+			expl.Fits = false
+			expl.Reason = "cannot align init container " + initContainer.Name
+			return expl
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		alignment := explainAlignment(lh, nodes, zoneNames, container.Resources.Requests, qos, nodeInfo)
+		alignment.Container = container.Name
+		alignment.ContainerKind = logging.KindContainerApp
+		expl.Alignments = append(expl.Alignments, alignment)
+		if !alignment.Fits {
+			expl.Fits = false
+			expl.Reason = "cannot align container " + container.Name
+			return expl
+		}
+		// account the container like the filter does, so the next containers see the remaining resources
+		if err := subtractResourcesFromNUMANodeList(lh, nodes, alignment.NUMAID, qos, container.Resources.Requests); err != nil {
+			expl.Fits = false
+			expl.Reason = "inconsistent resource accounting: " + err.Error()
+			return expl
+		}
+	}
+	expl.Reason = "all containers aligned"
+	return expl
+}
+
+// explainAlignment explains the verdict of resourcesAvailableInAnyNUMANodes for the given requests.
+func explainAlignment(lh logr.Logger, numaNodes NUMANodeList, zoneNames map[int]string, requests v1.ResourceList, qos v1.PodQOSClass, nodeInfo *framework.NodeInfo) AlignmentExplanation {
+	numaID, match := resourcesAvailableInAnyNUMANodes(lh, numaNodes, requests, qos, nodeInfo)
+	alignment := AlignmentExplanation{
+		Requests: requests.DeepCopy(),
+		Fits:     match,
+		NUMAID:   NoNUMAID,
+	}
+	if match {
+		alignment.NUMAID = numaID
+	}
+
+	nodeResources := util.ResourceList(nodeInfo.Allocatable)
+	resourceNames := make([]v1.ResourceName, 0, len(requests))
+	for name, quantity := range requests {
+		if quantity.IsZero() {
+			continue
+		}
+		if _, ok := nodeResources[name]; !ok {
+			alignment.MissingResources = append(alignment.MissingResources, name)
+			continue
+		}
+		resourceNames = append(resourceNames, name)
+	}
+	sort.Slice(alignment.MissingResources, func(i, j int) bool { return alignment.MissingResources[i] < alignment.MissingResources[j] })
+	sort.Slice(resourceNames, func(i, j int) bool { return resourceNames[i] < resourceNames[j] })
+
+	for _, numaNode := range numaNodes {
+		zone := ZoneFit{
+			Zone:   zoneNames[numaNode.NUMAID],
+			NUMAID: numaNode.NUMAID,
+			Fits:   len(alignment.MissingResources) == 0,
+		}
+		for _, name := range resourceNames {
+			quantity := requests[name]
+			available, ok := numaNode.Resources[name]
+			if !ok && isHostLevelResource(name) && onlyNonNUMAResources(numaNodes, v1.ResourceList{name: quantity}) {
+				// available at node level without NUMA affinity
+				continue
+			}
+			if ok && isResourceSetSuitable(qos, name, quantity, available) {
+				continue
+			}
+			zone.Fits = false
+			zone.Insufficient = append(zone.Insufficient, InsufficientResource{
+				Resource:  name,
+				Requested: quantity.DeepCopy(),
+				Available: available.DeepCopy(),
+			})
+		}
+		alignment.Zones = append(alignment.Zones, zone)
+	}
+	return alignment
+}
+
+func zoneNamesByNUMAID(zones topologyv1alpha2.ZoneList) map[int]string {
+	names := make(map[int]string)
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		numaID, err := numanode.NameToID(zone.Name)
+		if err != nil {
+			continue
+		}
+		names[numaID] = zone.Name
+	}
+	return names
+}
+
+// nodeFromNodeResourceTopology returns a node allocating the sum of the allocatable resources of the zones,
+// or of their capacity if the allocatable resources are not reported.
+func nodeFromNodeResourceTopology(nodeTopology *topologyv1alpha2.NodeResourceTopology) *v1.Node {
+	allocatable := make(v1.ResourceList)
+	for _, zone := range nodeTopology.Zones {
+		for _, resInfo := range zone.Resources {
+			zoneAllocatable := resInfo.Allocatable
+			if zoneAllocatable.IsZero() {
+				zoneAllocatable = resInfo.Capacity
+			}
+			quantity := allocatable[v1.ResourceName(resInfo.Name)]
+			quantity.Add(zoneAllocatable)
+			allocatable[v1.ResourceName(resInfo.Name)] = quantity
+		}
+	}
+	node := &v1.Node{}
+	node.Name = nodeTopology.Name
+	node.Status.Allocatable = allocatable
+	return node
+}
+
+// FitExplainRequest is the body of the requests served by the fit explanation handler.
+type FitExplainRequest struct {
+	Pod                  *v1.Pod                                `json:"pod"`
+	NodeResourceTopology *topologyv1alpha2.NodeResourceTopology `json:"nodeResourceTopology"`
+	// Node is optional, see ExplainFit.
+	Node *v1.Node `json:"node,omitempty"`
+}
+
+// NewFitExplainHandler returns a debug HTTP handler answering a POSTed FitExplainRequest with its FitExplanation.
+func NewFitExplainHandler(lh logr.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		var req FitExplainRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "cannot decode request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Pod == nil || req.NodeResourceTopology == nil {
+			http.Error(w, "pod and nodeResourceTopology are required", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(ExplainFit(lh, req.Pod, req.NodeResourceTopology, req.Node)); err != nil {
+			lh.Error(err, "cannot encode fit explanation")
+		}
+	})
+}
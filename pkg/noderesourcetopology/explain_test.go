@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+func makeExplainTestNRT(policy topologyv1alpha2.TopologyManagerPolicy) *topologyv1alpha2.NodeResourceTopology {
+	return &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(policy)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "20", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "20", "6"),
+					MakeTopologyResInfo(memory, "8Gi", "2Gi"),
+				},
+			},
+		},
+	}
+}
+
+func TestExplainFit(t *testing.T) {
+	guaranteed := func(resources ...v1.ResourceList) *v1.Pod {
+		return makePod("pod", withMultiContainers(resources))
+	}
+	res := func(cpuQty, memQty string) v1.ResourceList {
+		return v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpuQty),
+			v1.ResourceMemory: resource.MustParse(memQty),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		policy     topologyv1alpha2.TopologyManagerPolicy
+		wantFits   bool
+		wantReason string
+		// NUMA IDs selected for each alignment
+		wantNUMAIDs []int
+		// insufficient resources of each zone of the last alignment
+		wantInsufficient map[string][]v1.ResourceName
+	}{
+		{
+			name:             "container fits the first zone with enough resources",
+			pod:              guaranteed(res("5", "1Gi")),
+			policy:           topologyv1alpha2.SingleNUMANodeContainerLevel,
+			wantFits:         true,
+			wantReason:       "all containers aligned",
+			wantNUMAIDs:      []int{1},
+			wantInsufficient: map[string][]v1.ResourceName{"node-0": {v1.ResourceCPU}, "node-1": nil},
+		},
+		{
+			name:             "no zone has enough cpu and memory",
+			pod:              guaranteed(res("5", "4Gi")),
+			policy:           topologyv1alpha2.SingleNUMANodeContainerLevel,
+			wantReason:       "cannot align container cnt-1",
+			wantNUMAIDs:      []int{NoNUMAID},
+			wantInsufficient: map[string][]v1.ResourceName{"node-0": {v1.ResourceCPU}, "node-1": {v1.ResourceMemory}},
+		},
+		{
+			name:             "second container sees the resources left by the first one",
+			pod:              guaranteed(res("4", "1Gi"), res("5", "3Gi")),
+			policy:           topologyv1alpha2.SingleNUMANodeContainerLevel,
+			wantReason:       "cannot align container cnt-2",
+			wantNUMAIDs:      []int{0, NoNUMAID},
+			wantInsufficient: map[string][]v1.ResourceName{"node-0": {v1.ResourceCPU}, "node-1": {v1.ResourceMemory}},
+		},
+		{
+			name:             "pod scope aligns the whole pod",
+			pod:              guaranteed(res("3", "1Gi"), res("3", "1Gi")),
+			policy:           topologyv1alpha2.SingleNUMANodePodLevel,
+			wantFits:         true,
+			wantReason:       "pod aligned",
+			wantNUMAIDs:      []int{1},
+			wantInsufficient: map[string][]v1.ResourceName{"node-0": {v1.ResourceCPU}, "node-1": nil},
+		},
+		{
+			name:       "policy not enforced",
+			pod:        guaranteed(res("40", "40Gi")),
+			policy:     topologyv1alpha2.BestEffortContainerLevel,
+			wantFits:   true,
+			wantReason: "topology manager policy not enforced by the scheduler",
+		},
+		{
+			name:       "best-effort pod",
+			pod:        makePod("pod"),
+			policy:     topologyv1alpha2.SingleNUMANodeContainerLevel,
+			wantFits:   true,
+			wantReason: "best-effort pod without extended resources, no alignment required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expl := ExplainFit(klog.Background(), tt.pod, makeExplainTestNRT(tt.policy), nil)
+			if expl.Fits != tt.wantFits || expl.Reason != tt.wantReason {
+				t.Fatalf("expected fits=%v reason=%q, got fits=%v reason=%q", tt.wantFits, tt.wantReason, expl.Fits, expl.Reason)
+			}
+			if len(expl.Alignments) != len(tt.wantNUMAIDs) {
+				t.Fatalf("expected %d alignments, got %+v", len(tt.wantNUMAIDs), expl.Alignments)
+			}
+			for i, alignment := range expl.Alignments {
+				if alignment.NUMAID != tt.wantNUMAIDs[i] {
+					t.Errorf("alignment %d: expected NUMA ID %d, got %d", i, tt.wantNUMAIDs[i], alignment.NUMAID)
+				}
+			}
+			if len(expl.Alignments) == 0 {
+				return
+			}
+			last := expl.Alignments[len(expl.Alignments)-1]
+			for _, zone := range last.Zones {
+				var got []v1.ResourceName
+				for _, insufficient := range zone.Insufficient {
+					got = append(got, insufficient.Resource)
+				}
+				want := tt.wantInsufficient[zone.Zone]
+				if len(got) != len(want) || (len(want) > 0 && got[0] != want[0]) {
+					t.Errorf("zone %s: expected insufficient %v, got %v", zone.Zone, want, got)
+				}
+				if zone.Fits != (len(want) == 0) {
+					t.Errorf("zone %s: unexpected fits %v", zone.Zone, zone.Fits)
+				}
+			}
+		})
+	}
+}
+
+func TestExplainFitMissingResource(t *testing.T) {
+	pod := makePod("pod", withMultiContainers([]v1.ResourceList{{
+		v1.ResourceCPU:    resource.MustParse("1"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+		v1.ResourceName(notExistingNICResourceName): resource.MustParse("1"),
+	}}))
+	expl := ExplainFit(klog.Background(), pod, makeExplainTestNRT(topologyv1alpha2.SingleNUMANodeContainerLevel), nil)
+	if expl.Fits {
+		t.Fatalf("expected the pod not to fit")
+	}
+	if missing := expl.Alignments[0].MissingResources; len(missing) != 1 || missing[0] != v1.ResourceName(notExistingNICResourceName) {
+		t.Errorf("expected %s to be missing, got %v", notExistingNICResourceName, missing)
+	}
+}
+
+func TestFitExplainHandler(t *testing.T) {
+	handler := NewFitExplainHandler(klog.Background())
+
+	body, err := json.Marshal(FitExplainRequest{
+		Pod: makePod("pod", withMultiContainers([]v1.ResourceList{{
+			v1.ResourceCPU:    resource.MustParse("2"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		}})),
+		NodeResourceTopology: makeExplainTestNRT(topologyv1alpha2.SingleNUMANodeContainerLevel),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, FitExplainPath, bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var expl FitExplanation
+	if err := json.Unmarshal(rec.Body.Bytes(), &expl); err != nil {
+		t.Fatal(err)
+	}
+	if !expl.Fits || expl.Node != "node1" || len(expl.Alignments) != 1 || expl.Alignments[0].NUMAID != 0 {
+		t.Errorf("unexpected explanation %+v", expl)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, FitExplainPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, FitExplainPath, bytes.NewReader([]byte("{}"))))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
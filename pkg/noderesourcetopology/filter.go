@@ -221,8 +221,13 @@ func (tm *TopologyMatch) Filter(ctx context.Context, cycleState *framework.Cycle
 	if handler == nil {
 		return nil
 	}
+	// during the preemption dry run, account for the NUMA resources the victims would free
+	numaState := getNUMAPreemptionState(cycleState, nodeName)
+	if numaState != nil {
+		numaState.creditZones(nodeTopology.Zones)
+	}
 	status := handler(lh, pod, nodeTopology.Zones, nodeInfo)
-	if status != nil {
+	if status != nil && numaState == nil {
 		tm.nrtCache.NodeMaybeOverReserved(nodeName, pod)
 	}
 	return status
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+// gangMember is the NUMA alignment of a gang member reserved on a node and not bound yet.
+type gangMember struct {
+	requests string
+	shape    string
+}
+
+// gangAlignments tracks the NUMA alignment of the members of the gangs, so that the members with identical
+// requests are placed with the same alignment, e.g. for the ranks of an MPI job to perform uniformly.
+// The alignment of the bound members is read from their NUMAResourcesAnnotation.
+type gangAlignments struct {
+	sync.Mutex
+	podLister corelisters.PodLister
+	// reserved are the members reserved and not bound yet, by gang then pod UID.
+	reserved map[string]map[types.UID]gangMember
+}
+
+func newGangAlignments(podLister corelisters.PodLister) *gangAlignments {
+	return &gangAlignments{
+		podLister: podLister,
+		reserved:  make(map[string]map[types.UID]gangMember),
+	}
+}
+
+// reference returns the shape of the NUMA alignment of the members of the gang of the pod with the same requests,
+// and false if the pod is not a gang member or none of them is placed yet.
+func (g *gangAlignments) reference(lh logr.Logger, pod *corev1.Pod) (string, bool) {
+	gang := util.GetPodGroupFullName(pod)
+	if g == nil || gang == "" {
+		return "", false
+	}
+	requests := resourceListKey(util.GetPodEffectiveRequest(pod))
+
+	g.Lock()
+	for uid, member := range g.reserved[gang] {
+		if uid != pod.UID && member.requests == requests {
+			g.Unlock()
+			return member.shape, true
+		}
+	}
+	g.Unlock()
+
+	members, err := util.ListPodGroupPods(g.podLister, util.GetPodGroupNamespace(pod), util.GetPodGroupLabel(pod), []string{pod.Namespace})
+	if err != nil {
+		lh.Error(err, "cannot list the gang members", "gang", gang)
+		return "", false
+	}
+	for _, member := range members {
+		annotation, ok := member.Annotations[NUMAResourcesAnnotation]
+		if member.UID == pod.UID || member.Spec.NodeName == "" || !ok {
+			continue
+		}
+		if resourceListKey(util.GetPodEffectiveRequest(member)) != requests {
+			continue
+		}
+		var resources map[string]corev1.ResourceList
+		if err := json.Unmarshal([]byte(annotation), &resources); err != nil {
+			lh.V(4).Info("invalid NUMA resources annotation", "member", member.Name, "err", err)
+			continue
+		}
+		return numaShape(resources), true
+	}
+	return "", false
+}
+
+// reserve records the NUMA alignment of the pod reserved on a node, if it is a gang member.
+func (g *gangAlignments) reserve(pod *corev1.Pod, alignment *numaAlignmentState) {
+	gang := util.GetPodGroupFullName(pod)
+	if g == nil || gang == "" || alignment == nil {
+		return
+	}
+	g.Lock()
+	defer g.Unlock()
+	if g.reserved[gang] == nil {
+		g.reserved[gang] = make(map[types.UID]gangMember)
+	}
+	g.reserved[gang][pod.UID] = gangMember{
+		requests: resourceListKey(util.GetPodEffectiveRequest(pod)),
+		shape:    numaShape(alignment.resources),
+	}
+}
+
+// forget drops the pod once unreserved, or once bound and annotated with its NUMA alignment.
+func (g *gangAlignments) forget(pod *corev1.Pod) {
+	gang := util.GetPodGroupFullName(pod)
+	if g == nil || gang == "" {
+		return
+	}
+	g.Lock()
+	defer g.Unlock()
+	delete(g.reserved[gang], pod.UID)
+	if len(g.reserved[gang]) == 0 {
+		delete(g.reserved, gang)
+	}
+}
+
+// symmetric returns whether the NUMA alignment of the pod on the node has the given shape.
+func symmetric(lh logr.Logger, pod *corev1.Pod, nodeTopology *topologyv1alpha2.NodeResourceTopology, node *corev1.Node, shape string) bool {
+	alignment := alignmentFromExplanation(pod, ExplainFit(lh, pod, nodeTopology, node), nodeTopology.Zones)
+	return alignment != nil && numaShape(alignment.resources) == shape
+}
+
+// numaShape returns the shape of a NUMA alignment: the resources taken from each NUMA zone regardless of the
+// zone names, e.g. "cpu=4000,memory=8589934592000|cpu=4000,memory=8589934592000" for a pod spanning two zones evenly.
+func numaShape(resources map[string]corev1.ResourceList) string {
+	zones := make([]string, 0, len(resources))
+	for _, zoneResources := range resources {
+		zones = append(zones, resourceListKey(zoneResources))
+	}
+	sort.Strings(zones)
+	return strings.Join(zones, "|")
+}
+
+// resourceListKey returns a canonical representation of the non-zero quantities of the resources.
+func resourceListKey(resources corev1.ResourceList) string {
+	quantities := make([]string, 0, len(resources))
+	for name, quantity := range resources {
+		if quantity.IsZero() {
+			continue
+		}
+		quantities = append(quantities, fmt.Sprintf("%v=%d", name, quantity.MilliValue()))
+	}
+	sort.Strings(quantities)
+	return strings.Join(quantities, ",")
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestNUMAShape(t *testing.T) {
+	split := map[string]v1.ResourceList{
+		"node-0": {v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("1Gi")},
+		"node-1": {v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	// the same split on other zones, with quantities written differently
+	sameSplit := map[string]v1.ResourceList{
+		"node-2": {v1.ResourceCPU: resource.MustParse("2000m"), v1.ResourceMemory: resource.MustParse("1024Mi")},
+		"node-3": {v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	singleZone := map[string]v1.ResourceList{
+		"node-0": {v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+	if numaShape(split) != numaShape(sameSplit) {
+		t.Errorf("expected the same shape, got %q and %q", numaShape(split), numaShape(sameSplit))
+	}
+	if numaShape(split) == numaShape(singleZone) {
+		t.Errorf("expected different shapes, got %q", numaShape(split))
+	}
+}
+
+func makeGangMember(name string, options ...func(*v1.Pod)) *v1.Pod {
+	containerRes := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	pod := makePod(name, withMultiContainers([]v1.ResourceList{containerRes, containerRes}))
+	pod.Namespace = metav1.NamespaceDefault
+	pod.UID = types.UID(name)
+	pod.Labels = map[string]string{v1alpha1.PodGroupLabel: "mpi"}
+	for _, o := range options {
+		o(pod)
+	}
+	return pod
+}
+
+func TestGangAlignmentScore(t *testing.T) {
+	makeNRT := func(name, cpus string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", cpus),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", cpus),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	// both containers of a member fit a zone of packed, one container fits each zone of spread
+	packed := makeNRT("packed", "4")
+	spread := makeNRT("spread", "3")
+	nrts := []*topologyv1alpha2.NodeResourceTopology{packed, spread}
+	nodes := []*v1.Node{makeNodeFromNodeResourceTopology(packed), makeNodeFromNodeResourceTopology(spread)}
+
+	// the member placed spans the two zones of its node
+	bound := makeGangMember("rank-0", func(pod *v1.Pod) {
+		pod.Spec.NodeName = "other"
+		pod.Annotations = map[string]string{
+			NUMAResourcesAnnotation: `{"node-0":{"cpu":"2","memory":"1Gi"},"node-1":{"cpu":"2","memory":"1Gi"}}`,
+		}
+	})
+
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		pods         []*v1.Pod
+		wantSymmetry map[string]bool
+	}{
+		{
+			name:         "first member of the gang",
+			pod:          makeGangMember("rank-1"),
+			wantSymmetry: map[string]bool{"packed": true, "spread": true},
+		},
+		{
+			name:         "member aligned like the bound member",
+			pod:          makeGangMember("rank-1"),
+			pods:         []*v1.Pod{bound},
+			wantSymmetry: map[string]bool{"packed": false, "spread": true},
+		},
+		{
+			name: "member with other requests",
+			pod: makeGangMember("rank-1", func(pod *v1.Pod) {
+				pod.Spec.Containers = pod.Spec.Containers[:1]
+			}),
+			pods:         []*v1.Pod{bound},
+			wantSymmetry: map[string]bool{"packed": true, "spread": true},
+		},
+		{
+			name: "pod of another gang",
+			pod: makeGangMember("rank-1", func(pod *v1.Pod) {
+				pod.Labels[v1alpha1.PodGroupLabel] = "other"
+			}),
+			pods:         []*v1.Pod{bound},
+			wantSymmetry: map[string]bool{"packed": true, "spread": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			tm := newNUMAPreemptionTestPlugin(ctx, t, nrts, nodes, tt.pods, tt.pod)
+			tm.scoreStrategyFunc = leastAllocatedScoreStrategy
+			tm.gangAlignments = newGangAlignments(tm.podLister)
+
+			for nodeName, wantSymmetric := range tt.wantSymmetry {
+				score, status := tm.Score(ctx, framework.NewCycleState(), tt.pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on %v: %v", nodeName, status)
+				}
+				if (score > 0) != wantSymmetric {
+					t.Errorf("unexpected score %v on %v, want symmetric %v", score, nodeName, wantSymmetric)
+				}
+			}
+		})
+	}
+}
+
+func TestGangAlignmentsReserved(t *testing.T) {
+	g := newGangAlignments(nil)
+	rank0 := makeGangMember("rank-0")
+	rank1 := makeGangMember("rank-1")
+	alignment := &numaAlignmentState{
+		resources: map[string]v1.ResourceList{
+			"node-0": {v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("2Gi")},
+		},
+	}
+
+	g.reserve(rank0, alignment)
+	shape, ok := g.reference(klog.Background(), rank1)
+	if !ok || shape != numaShape(alignment.resources) {
+		t.Fatalf("expected the reference of the reserved member, got %q, %v", shape, ok)
+	}
+	g.forget(rank0)
+	if len(g.reserved) != 0 {
+		t.Errorf("expected no reserved member left, got %v", g.reserved)
+	}
+}
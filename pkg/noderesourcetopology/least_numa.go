@@ -34,14 +34,38 @@ import (
 const (
 	// 255 is max value as defined by ACPI SLIT(System Locality Information Tables), which means unknown/undefined
 	maxDistanceValue = 255
+	// 10 is the distance of a NUMA node to itself as defined by ACPI SLIT
+	localDistanceValue = 10
 )
 
+// numaScoreFn computes the score of a node from the number of NUMA nodes required to run a pod,
+// whether they provide the minimal average distance among the combinations of that many NUMA nodes,
+// and their average distance.
+type numaScoreFn func(numaNodesCount int, isMinAvgDistance bool, avgDistance float32) int64
+
 func leastNUMAContainerScopeScore(lh logr.Logger, pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
+	return numaContainerScopeScore(lh, pod, zones, leastNUMANodesScore)
+}
+
+func leastNUMAPodScopeScore(lh logr.Logger, pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
+	return numaPodScopeScore(lh, pod, zones, leastNUMANodesScore)
+}
+
+func leastNUMADistanceContainerScopeScore(lh logr.Logger, pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
+	return numaContainerScopeScore(lh, pod, zones, normalizeDistanceScore)
+}
+
+func leastNUMADistancePodScopeScore(lh logr.Logger, pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
+	return numaPodScopeScore(lh, pod, zones, normalizeDistanceScore)
+}
+
+func numaContainerScopeScore(lh logr.Logger, pod *v1.Pod, zones topologyv1alpha2.ZoneList, scoreFn numaScoreFn) (int64, *framework.Status) {
 	nodes := createNUMANodeList(lh, zones)
 	qos := v1qos.GetPodQOS(pod)
 
 	maxNUMANodesCount := 0
 	allContainersMinAvgDistance := true
+	var maxAvgDistance float32
 	// the order how TopologyManager asks for hint is important so doing it in the same order
 	// https://github.com/kubernetes/kubernetes/blob/master/pkg/kubelet/cm/topologymanager/scope_container.go#L52
 	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
@@ -65,6 +89,10 @@ func leastNUMAContainerScopeScore(lh logr.Logger, pod *v1.Pod, zones topologyv1a
 			maxNUMANodesCount = numaNodes.Count()
 		}
 
+		if avgDistance := bitmaskAvgDistance(lh, nodes, numaNodes); avgDistance > maxAvgDistance {
+			maxAvgDistance = avgDistance
+		}
+
 		// subtract the resources requested by the container from the given NUMA.
 		// this is necessary, so we won't allocate the same resources for the upcoming containers
 		subtractFromNUMAs(container.Resources.Requests, nodes, numaNodes.GetBits()...)
@@ -74,10 +102,10 @@ func leastNUMAContainerScopeScore(lh logr.Logger, pod *v1.Pod, zones topologyv1a
 		return framework.MaxNodeScore, nil
 	}
 
-	return normalizeScore(maxNUMANodesCount, allContainersMinAvgDistance), nil
+	return scoreFn(maxNUMANodesCount, allContainersMinAvgDistance, maxAvgDistance), nil
 }
 
-func leastNUMAPodScopeScore(lh logr.Logger, pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
+func numaPodScopeScore(lh logr.Logger, pod *v1.Pod, zones topologyv1alpha2.ZoneList, scoreFn numaScoreFn) (int64, *framework.Status) {
 	nodes := createNUMANodeList(lh, zones)
 	qos := v1qos.GetPodQOS(pod)
 
@@ -95,7 +123,11 @@ func leastNUMAPodScopeScore(lh logr.Logger, pod *v1.Pod, zones topologyv1alpha2.
 		return framework.MinNodeScore, nil
 	}
 
-	return normalizeScore(numaNodes.Count(), isMinAvgDistance), nil
+	return scoreFn(numaNodes.Count(), isMinAvgDistance, bitmaskAvgDistance(lh, nodes, numaNodes)), nil
+}
+
+func leastNUMANodesScore(numaNodesCount int, isMinAvgDistance bool, _ float32) int64 {
+	return normalizeScore(numaNodesCount, isMinAvgDistance)
 }
 
 func normalizeScore(numaNodesCount int, isMinAvgDistance bool) int64 {
@@ -109,6 +141,30 @@ func normalizeScore(numaNodesCount int, isMinAvgDistance bool) int64 {
 	return score
 }
 
+// normalizeDistanceScore favors, among the nodes requiring the same number of NUMA nodes, the ones providing
+// the smallest average distance between them, instead of only favoring the optimal combination of each node.
+// The distance of a single NUMA node is its local distance, which gets the full bonus.
+func normalizeDistanceScore(numaNodesCount int, isMinAvgDistance bool, avgDistance float32) int64 {
+	if avgDistance >= maxDistanceValue {
+		// the distances are not exported, fall back to the LeastNUMANodes score
+		return normalizeScore(numaNodesCount, isMinAvgDistance)
+	}
+	numaNodeScore := framework.MaxNodeScore / highestNUMAID
+	score := framework.MaxNodeScore - int64(numaNodesCount)*numaNodeScore
+	return score + int64(float32(numaNodeScore)*localDistanceValue/max(avgDistance, localDistanceValue))
+}
+
+// bitmaskAvgDistance returns the average distance between the NUMA nodes of the bitmask.
+func bitmaskAvgDistance(lh logr.Logger, numaNodes NUMANodeList, numaIDs bitmask.BitMask) float32 {
+	var nodes []int
+	for i, node := range numaNodes {
+		if numaIDs.IsSet(node.NUMAID) {
+			nodes = append(nodes, i)
+		}
+	}
+	return nodesAvgDistance(lh, numaNodes, nodes...)
+}
+
 func minAvgDistanceInCombinations(lh logr.Logger, numaNodes NUMANodeList, numaNodesCombination [][]int) float32 {
 	// max distance for NUMA node
 	var minDistance float32 = maxDistanceValue
@@ -25,6 +25,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/go-logr/logr"
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
 )
 
 const (
@@ -909,3 +913,138 @@ func TestMinDistance(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeDistanceScore(t *testing.T) {
+	tcases := []struct {
+		description     string
+		score           int
+		avgDistance     float32
+		optimalDistance bool
+		expectedScore   int64
+	}{
+		{
+			description:   "1 numa node",
+			score:         1,
+			avgDistance:   10,
+			expectedScore: 100,
+		},
+		{
+			description:   "2 close numa nodes",
+			score:         2,
+			avgDistance:   11,
+			expectedScore: 86,
+		},
+		{
+			description:   "2 distant numa nodes",
+			score:         2,
+			avgDistance:   20,
+			expectedScore: 82,
+		},
+		{
+			description:   "8 numa nodes",
+			score:         8,
+			avgDistance:   20,
+			expectedScore: 10,
+		},
+		{
+			description:     "2 numa nodes, unknown distance",
+			score:           2,
+			avgDistance:     maxDistanceValue,
+			optimalDistance: true,
+			expectedScore:   82,
+		},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.description, func(t *testing.T) {
+			normalizedScore := normalizeDistanceScore(tc.score, tc.optimalDistance, tc.avgDistance)
+			if normalizedScore != tc.expectedScore {
+				t.Errorf("Expected normalizedScore to be %d not %d", tc.expectedScore, normalizedScore)
+			}
+		})
+	}
+}
+
+func TestLeastNUMADistanceScore(t *testing.T) {
+	// makeZones returns two NUMA zones of 4 CPUs at the given distance from each other.
+	makeZones := func(distance int64) topologyv1alpha2.ZoneList {
+		zones := topologyv1alpha2.ZoneList{}
+		for i := 0; i < 2; i++ {
+			zone := topologyv1alpha2.Zone{
+				Name: fmt.Sprintf("node-%d", i),
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "4Gi", "4Gi"),
+				},
+			}
+			for j := 0; j < 2; j++ {
+				cost := topologyv1alpha2.CostInfo{Name: fmt.Sprintf("node-%d", j), Value: distance}
+				if i == j {
+					cost.Value = localDistanceValue
+				}
+				zone.Costs = append(zone.Costs, cost)
+			}
+			zones = append(zones, zone)
+		}
+		return zones
+	}
+	// the guaranteed pod spans both NUMA zones of a node
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: "test",
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("6"), v1.ResourceMemory: resource.MustParse("1Gi")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("6"), v1.ResourceMemory: resource.MustParse("1Gi")},
+					},
+				},
+			},
+		},
+	}
+
+	tcases := []struct {
+		description string
+		scoreFn     func(lh logr.Logger, pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status)
+		sameScore   bool
+	}{
+		{
+			description: "LeastNUMANodes pod scope",
+			scoreFn:     leastNUMAPodScopeScore,
+			sameScore:   true,
+		},
+		{
+			description: "LeastNUMANodes container scope",
+			scoreFn:     leastNUMAContainerScopeScore,
+			sameScore:   true,
+		},
+		{
+			description: "LeastNUMADistance pod scope",
+			scoreFn:     leastNUMADistancePodScopeScore,
+		},
+		{
+			description: "LeastNUMADistance container scope",
+			scoreFn:     leastNUMADistanceContainerScopeScore,
+		},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.description, func(t *testing.T) {
+			closeScore, status := tc.scoreFn(klog.Background(), pod, makeZones(11))
+			if !status.IsSuccess() {
+				t.Fatalf("unexpected status: %v", status)
+			}
+			distantScore, status := tc.scoreFn(klog.Background(), pod, makeZones(21))
+			if !status.IsSuccess() {
+				t.Fatalf("unexpected status: %v", status)
+			}
+			if tc.sameScore && closeScore != distantScore {
+				t.Errorf("expected the same score for close and distant NUMA zones, got %d and %d", closeScore, distantScore)
+			}
+			if !tc.sameScore && closeScore <= distantScore {
+				t.Errorf("expected close NUMA zones to score higher than distant ones, got %d and %d", closeScore, distantScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeconfig
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+)
+
+const (
+	// AttributeReservedCPUs is the zone attribute giving the number of CPUs of the zone the kubelet reserves
+	// for the system, e.g. the CPUs of --reserved-cpus in the zone.
+	AttributeReservedCPUs = "reservedCPUs"
+	// AttributeReservedMemory is the zone attribute giving the memory of the zone the kubelet reserves
+	// for the system, e.g. the memory of --reserved-memory for the zone.
+	AttributeReservedMemory = "reservedMemory"
+)
+
+var reservedAttributes = map[string]corev1.ResourceName{
+	AttributeReservedCPUs:   corev1.ResourceCPU,
+	AttributeReservedMemory: corev1.ResourceMemory,
+}
+
+// ReservedResourcesFromZone returns the resources of the zone the kubelet reserves for the system, as exported
+// in the zone attributes. The attributes with an invalid quantity are ignored.
+func ReservedResourcesFromZone(zone topologyv1alpha2.Zone) corev1.ResourceList {
+	var reserved corev1.ResourceList
+	for _, attr := range zone.Attributes {
+		name, ok := reservedAttributes[attr.Name]
+		if !ok {
+			continue
+		}
+		qty, err := resource.ParseQuantity(attr.Value)
+		if err != nil || qty.Sign() <= 0 {
+			continue
+		}
+		if reserved == nil {
+			reserved = make(corev1.ResourceList)
+		}
+		reserved[name] = qty
+	}
+	return reserved
+}
+
+// AvailableExcludingReserved returns the quantity of the resource available in the zone once the reserved
+// quantity is excluded. The exporters may or may not subtract the reservations from the allocatable quantity;
+// only the part of the reserved quantity not already subtracted, i.e. exceeding capacity - allocatable,
+// is subtracted from the available quantity, which is never made negative.
+func AvailableExcludingReserved(resInfo topologyv1alpha2.ResourceInfo, reserved resource.Quantity) resource.Quantity {
+	available := resInfo.Available.DeepCopy()
+	allocatable := resInfo.Allocatable
+	if allocatable.IsZero() {
+		// not reported, the reservations can't be already subtracted
+		allocatable = resInfo.Capacity
+	}
+	missing := reserved.DeepCopy()
+	missing.Sub(resInfo.Capacity)
+	missing.Add(allocatable)
+	if missing.Sign() <= 0 {
+		return available
+	}
+	available.Sub(missing)
+	if available.Sign() < 0 {
+		return resource.Quantity{}
+	}
+	return available
+}
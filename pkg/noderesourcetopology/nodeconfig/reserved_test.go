@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeconfig
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+)
+
+func TestReservedResourcesFromZone(t *testing.T) {
+	zone := topologyv1alpha2.Zone{
+		Name: "node-0",
+		Type: "Node",
+		Attributes: topologyv1alpha2.AttributeList{
+			{Name: AttributeReservedCPUs, Value: "2"},
+			{Name: AttributeReservedMemory, Value: "not-a-quantity"},
+			{Name: AttributeScope, Value: "pod"},
+		},
+	}
+	reserved := ReservedResourcesFromZone(zone)
+	if len(reserved) != 1 {
+		t.Fatalf("expected only the reserved CPUs, got %v", reserved)
+	}
+	if qty := reserved[corev1.ResourceCPU]; qty.Cmp(resource.MustParse("2")) != 0 {
+		t.Errorf("expected 2 reserved CPUs, got %v", qty.String())
+	}
+	if reserved := ReservedResourcesFromZone(topologyv1alpha2.Zone{Name: "node-1"}); reserved != nil {
+		t.Errorf("expected no reserved resources, got %v", reserved)
+	}
+}
+
+func TestAvailableExcludingReserved(t *testing.T) {
+	tests := []struct {
+		name        string
+		capacity    string
+		allocatable string
+		available   string
+		reserved    string
+		expected    string
+	}{
+		{
+			name:        "reservation not subtracted by the exporter",
+			capacity:    "16",
+			allocatable: "16",
+			available:   "10",
+			reserved:    "4",
+			expected:    "6",
+		},
+		{
+			name:        "reservation already subtracted by the exporter",
+			capacity:    "16",
+			allocatable: "12",
+			available:   "10",
+			reserved:    "4",
+			expected:    "10",
+		},
+		{
+			name:        "reservation partially subtracted by the exporter",
+			capacity:    "16",
+			allocatable: "14",
+			available:   "10",
+			reserved:    "4",
+			expected:    "8",
+		},
+		{
+			name:      "allocatable not reported",
+			capacity:  "16",
+			available: "10",
+			reserved:  "4",
+			expected:  "6",
+		},
+		{
+			name:        "reservation exceeding the available resources",
+			capacity:    "16",
+			allocatable: "16",
+			available:   "2",
+			reserved:    "4",
+			expected:    "0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resInfo := topologyv1alpha2.ResourceInfo{
+				Name:      string(corev1.ResourceCPU),
+				Capacity:  resource.MustParse(tt.capacity),
+				Available: resource.MustParse(tt.available),
+			}
+			if tt.allocatable != "" {
+				resInfo.Allocatable = resource.MustParse(tt.allocatable)
+			}
+			got := AvailableExcludingReserved(resInfo, resource.MustParse(tt.reserved))
+			if got.Cmp(resource.MustParse(tt.expected)) != 0 {
+				t.Errorf("expected %v available, got %v", tt.expected, got.String())
+			}
+		})
+	}
+}
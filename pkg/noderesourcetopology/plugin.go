@@ -24,6 +24,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
@@ -61,9 +63,17 @@ type TopologyMatch struct {
 	nrtCache            nrtcache.Interface
 	scoreStrategyFunc   scoreStrategyFn
 	scoreStrategyType   apiconfig.ScoringStrategyType
+	numaPreemption      bool
+	handle              framework.Handle
+	podLister           corelisters.PodLister
+	pdbLister           policylisters.PodDisruptionBudgetLister
+
+	// gangAlignments tracks the NUMA alignment of the gang members, nil when the gang alignment is disabled.
+	gangAlignments *gangAlignments
 }
 
 var _ framework.FilterPlugin = &TopologyMatch{}
+var _ framework.PostFilterPlugin = &TopologyMatch{}
 var _ framework.ReservePlugin = &TopologyMatch{}
 var _ framework.ScorePlugin = &TopologyMatch{}
 var _ framework.EnqueueExtensions = &TopologyMatch{}
@@ -114,6 +124,17 @@ func New(ctx context.Context, args runtime.Object, handle framework.Handle) (fra
 		nrtCache:            nrtCache,
 		scoreStrategyFunc:   strategy,
 		scoreStrategyType:   tcfg.ScoringStrategy.Type,
+		numaPreemption:      tcfg.NUMAPreemption,
+		handle:              handle,
+	}
+	if tcfg.NUMAPreemption || tcfg.GangAlignment {
+		topologyMatch.podLister = handle.SharedInformerFactory().Core().V1().Pods().Lister()
+	}
+	if tcfg.NUMAPreemption {
+		topologyMatch.pdbLister = handle.SharedInformerFactory().Policy().V1().PodDisruptionBudgets().Lister()
+	}
+	if tcfg.GangAlignment {
+		topologyMatch.gangAlignments = newGangAlignments(topologyMatch.podLister)
 	}
 
 	return topologyMatch, nil
@@ -42,6 +42,7 @@ import (
 	apiconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
 	nrtcache "github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/cache"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/logging"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/nodeconfig"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/podprovider"
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/stringify"
 )
@@ -158,10 +159,18 @@ func extractCosts(costs topologyv1alpha2.CostList) map[int]int {
 	return nodeCosts
 }
 
+// extractResources returns the resources available in the zone, excluding the ones the kubelet reserves
+// for the system, not to place pods the kubelet would reject.
 func extractResources(zone topologyv1alpha2.Zone) corev1.ResourceList {
+	reserved := nodeconfig.ReservedResourcesFromZone(zone)
 	res := make(corev1.ResourceList)
 	for _, resInfo := range zone.Resources {
-		res[corev1.ResourceName(resInfo.Name)] = resInfo.Available.DeepCopy()
+		name := corev1.ResourceName(resInfo.Name)
+		if qty, ok := reserved[name]; ok {
+			res[name] = nodeconfig.AvailableExcludingReserved(resInfo, qty)
+			continue
+		}
+		res[name] = resInfo.Available.DeepCopy()
 	}
 	return res
 }
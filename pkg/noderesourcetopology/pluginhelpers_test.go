@@ -19,12 +19,14 @@ package noderesourcetopology
 import (
 	"testing"
 
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 	// apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
 	
 	apiconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/nodeconfig"
 )
 
 func TestOnlyNonNUMAResources(t *testing.T) {
@@ -107,6 +109,55 @@ func TestOnlyNonNUMAResources(t *testing.T) {
 	}
 }
 
+func TestCreateNUMANodeListReserved(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: nodeconfig.AttributeReservedCPUs, Value: "2"},
+				{Name: nodeconfig.AttributeReservedMemory, Value: "1Gi"},
+			},
+			Resources: topologyv1alpha2.ResourceInfoList{
+				{Name: "cpu", Capacity: resource.MustParse("8"), Allocatable: resource.MustParse("8"), Available: resource.MustParse("8")},
+				{Name: "memory", Capacity: resource.MustParse("8Gi"), Allocatable: resource.MustParse("7Gi"), Available: resource.MustParse("7Gi")},
+				{Name: "gpu", Capacity: resource.MustParse("1"), Allocatable: resource.MustParse("1"), Available: resource.MustParse("1")},
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				{Name: "cpu", Capacity: resource.MustParse("8"), Allocatable: resource.MustParse("8"), Available: resource.MustParse("8")},
+			},
+		},
+	}
+	expected := NUMANodeList{
+		{
+			NUMAID: 0,
+			Resources: corev1.ResourceList{
+				// the reserved CPUs are not subtracted from the allocatable CPUs by the exporter
+				corev1.ResourceCPU: resource.MustParse("6"),
+				// the reserved memory is already subtracted from the allocatable memory by the exporter
+				corev1.ResourceMemory: resource.MustParse("7Gi"),
+				"gpu":                 resource.MustParse("1"),
+			},
+			Costs: map[int]int{},
+		},
+		{
+			NUMAID: 1,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("8"),
+			},
+			Costs: map[int]int{},
+		},
+	}
+	nodes := createNUMANodeList(klog.Background(), zones)
+	if !nodes.Equal(expected) {
+		t.Errorf("expected the NUMA nodes %v, got %v", expected, nodes)
+	}
+}
+
 func TestGetForeignPodsDetectMode(t *testing.T) {
 	detectAll := apiconfig.ForeignPodsDetectAll
 	detectNone := apiconfig.ForeignPodsDetectNone
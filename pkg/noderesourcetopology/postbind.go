@@ -18,19 +18,174 @@ package noderesourcetopology
 
 import (
 	"context"
+	"encoding/json"
 
+	"github.com/go-logr/logr"
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	// "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/logging"
-	
+
 	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/logging"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+const (
+	// NUMAZonesAnnotation is the annotation of the bound pods giving the NUMA zone the scheduler aligned
+	// each of their containers on, as a JSON object by container name, e.g. {"app":"node-1"}.
+	NUMAZonesAnnotation = "topology.node.k8s.io/numa-zones"
+
+	// NUMAResourcesAnnotation is the annotation of the bound pods giving the resources the scheduler expects
+	// their app containers to take from each NUMA zone, as a JSON object by zone name,
+	// e.g. {"node-1":{"cpu":"4","memory":"8Gi"}}.
+	NUMAResourcesAnnotation = "topology.node.k8s.io/numa-resources"
+
+	numaAlignmentStateKey framework.StateKey = Name + "/NUMAAlignment"
 )
 
+// numaAlignmentState carries, from Reserve to PostBind, the NUMA alignment of the pod on its node.
+type numaAlignmentState struct {
+	// zone name by container name
+	zones map[string]string
+	// resources by zone name
+	resources map[string]corev1.ResourceList
+}
+
+func (s *numaAlignmentState) Clone() framework.StateData {
+	return s
+}
+
+// alignmentFromExplanation returns the NUMA alignment of the pod on the zones given by the fit explanation,
+// or nil if the pod doesn't fit or needs no alignment.
+func alignmentFromExplanation(pod *corev1.Pod, expl FitExplanation, zones topologyv1alpha2.ZoneList) *numaAlignmentState {
+	if !expl.Fits || len(expl.Alignments) == 0 {
+		return nil
+	}
+	zoneNames := zoneNamesByNUMAID(zones)
+	zoneResources := make(map[string]corev1.ResourceList)
+	for _, zone := range zones {
+		zoneResources[zone.Name] = extractResources(zone)
+	}
+	qos := v1qos.GetPodQOS(pod)
+	state := &numaAlignmentState{
+		zones:     make(map[string]string),
+		resources: make(map[string]corev1.ResourceList),
+	}
+	for _, alignment := range expl.Alignments {
+		zone, ok := zoneNames[alignment.NUMAID]
+		if !ok {
+			return nil
+		}
+		if alignment.Container == "" {
+			// the pod scope: all the containers share the zone of the pod
+			for _, container := range pod.Spec.InitContainers {
+				state.zones[container.Name] = zone
+			}
+			for _, container := range pod.Spec.Containers {
+				state.zones[container.Name] = zone
+			}
+		} else {
+			state.zones[alignment.Container] = zone
+		}
+		// the init containers run before the app containers, reusing their resources
+		if alignment.ContainerKind == logging.KindContainerInit {
+			continue
+		}
+		for name, quantity := range alignment.Requests {
+			// the resources not reported by the zone are taken at node level
+			if _, ok := zoneResources[zone][name]; !ok || quantity.IsZero() {
+				continue
+			}
+			// the kubelet pins the NUMA-affine resources of the guaranteed pods only
+			if qos != corev1.PodQOSGuaranteed && isNUMAAffineResource(name) {
+				continue
+			}
+			if state.resources[zone] == nil {
+				state.resources[zone] = make(corev1.ResourceList)
+			}
+			total := state.resources[zone][name]
+			total.Add(quantity)
+			state.resources[zone][name] = total
+		}
+	}
+	return state
+}
+
+// reserveNUMAAlignment computes the NUMA alignment of the pod on the node, before the NRT cache accounts for
+// the pod, and carries it to PostBind.
+func (tm *TopologyMatch) reserveNUMAAlignment(ctx context.Context, lh logr.Logger, state *framework.CycleState, pod *corev1.Pod, nodeName string) {
+	nodeTopology, info := tm.nrtCache.GetCachedNRTCopy(ctx, nodeName, pod)
+	if !info.Fresh || nodeTopology == nil {
+		return
+	}
+	expl := ExplainFit(lh, pod, nodeTopology, tm.snapshotNode(nodeName))
+	alignment := alignmentFromExplanation(pod, expl, nodeTopology.Zones)
+	if alignment == nil {
+		return
+	}
+	lh.V(4).Info("NUMA alignment", "zones", alignment.zones)
+	state.Write(numaAlignmentStateKey, alignment)
+	tm.gangAlignments.reserve(pod, alignment)
+}
+
+// snapshotNode returns the node from the snapshot of the scheduling cycle, nil if not found.
+func (tm *TopologyMatch) snapshotNode(nodeName string) *corev1.Node {
+	if tm.handle == nil || tm.handle.SnapshotSharedLister() == nil {
+		return nil
+	}
+	nodeInfo, err := tm.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return nil
+	}
+	return nodeInfo.Node()
+}
+
 func (tm *TopologyMatch) PostBind(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) {
 	lh := klog.FromContext(ctx).WithValues(logging.KeyPod, klog.KObj(pod), logging.KeyPodUID, logging.PodUID(pod), logging.KeyNode, nodeName)
 	lh.V(4).Info(logging.FlowBegin)
 	defer lh.V(4).Info(logging.FlowEnd)
 
 	tm.nrtCache.PostBind(nodeName, pod)
+
+	if err := tm.annotateNUMAAlignment(ctx, state, pod); err != nil {
+		lh.Error(err, "cannot annotate the NUMA alignment")
+	}
+	tm.gangAlignments.forget(pod)
+}
+
+// annotateNUMAAlignment writes the NUMA alignment of the pod, if any, on its annotations, for the node
+// agents, e.g. a topology-aware CNI or device plugin, to follow the intent of the scheduler.
+func (tm *TopologyMatch) annotateNUMAAlignment(ctx context.Context, state *framework.CycleState, pod *corev1.Pod) error {
+	c, err := state.Read(numaAlignmentStateKey)
+	if err != nil {
+		return nil
+	}
+	alignment, ok := c.(*numaAlignmentState)
+	if !ok {
+		return nil
+	}
+	zones, err := json.Marshal(alignment.zones)
+	if err != nil {
+		return err
+	}
+	resources, err := json.Marshal(alignment.resources)
+	if err != nil {
+		return err
+	}
+	podCopy := pod.DeepCopy()
+	if podCopy.Annotations == nil {
+		podCopy.Annotations = make(map[string]string)
+	}
+	podCopy.Annotations[NUMAZonesAnnotation] = string(zones)
+	podCopy.Annotations[NUMAResourcesAnnotation] = string(resources)
+	patch, err := util.CreateMergePatch(pod, podCopy)
+	if err != nil {
+		return err
+	}
+	_, err = tm.handle.ClientSet().CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
 }
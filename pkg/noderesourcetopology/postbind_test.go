@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func TestPostBindNUMAAlignmentAnnotations(t *testing.T) {
+	// node-0 has 1 CPU left, node-1 3 CPUs
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "1"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "3"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	node := makeNodeFromNodeResourceTopology(&topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Zones: topologyv1alpha2.ZoneList{{Resources: topologyv1alpha2.ResourceInfoList{
+			MakeTopologyResInfo(cpu, "8", "8"),
+			MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+		}}},
+	})
+
+	now := time.Now()
+	besteffort := makePreemptionTestPod("besteffort", 1, 0, now)
+	besteffort.Spec.Containers[0].Resources = v1.ResourceRequirements{}
+
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		wantZones     string
+		wantResources string
+	}{
+		{
+			name:          "guaranteed pod aligned on the zone with enough CPUs",
+			pod:           makePreemptionTestPod("guaranteed", 1, 2, now),
+			wantZones:     `{"` + containerName + `":"node-1"}`,
+			wantResources: `{"node-1":{"cpu":"2","memory":"1Gi"}}`,
+		},
+		{
+			name: "pod not fitting any zone",
+			pod:  makePreemptionTestPod("toobig", 1, 4, now),
+		},
+		{
+			name: "best effort pod",
+			pod:  besteffort,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			tm := newNUMAPreemptionTestPlugin(ctx, t, []*topologyv1alpha2.NodeResourceTopology{nrt}, []*v1.Node{node}, []*v1.Pod{tt.pod}, tt.pod)
+
+			state := framework.NewCycleState()
+			if status := tm.Reserve(ctx, state, tt.pod, node.Name); !status.IsSuccess() {
+				t.Fatalf("unexpected reserve status: %v", status)
+			}
+			tm.PostBind(ctx, state, tt.pod, node.Name)
+
+			got, err := tm.handle.ClientSet().CoreV1().Pods(tt.pod.Namespace).Get(ctx, tt.pod.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if zones := got.Annotations[NUMAZonesAnnotation]; zones != tt.wantZones {
+				t.Errorf("unexpected zones annotation %q, want %q", zones, tt.wantZones)
+			}
+			if resources := got.Annotations[NUMAResourcesAnnotation]; resources != tt.wantResources {
+				t.Errorf("unexpected resources annotation %q, want %q", resources, tt.wantResources)
+			}
+		})
+	}
+}
@@ -0,0 +1,461 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
+
+	"github.com/go-logr/logr"
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/logging"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/nodeconfig"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/resourcerequests"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+const numaPreemptionStateKey framework.StateKey = Name + "/NUMAPreemption"
+
+// numaPreemptionState carries, during the preemption dry run on a node, the resources
+// of its NUMA zones freed by the victims, which the NRT data doesn't reflect yet.
+type numaPreemptionState struct {
+	nodeName string
+	// freed resources by NUMA zone name
+	freed map[string]corev1.ResourceList
+}
+
+func (s *numaPreemptionState) Clone() framework.StateData {
+	freed := make(map[string]corev1.ResourceList, len(s.freed))
+	for zone, resources := range s.freed {
+		freed[zone] = resources.DeepCopy()
+	}
+	return &numaPreemptionState{nodeName: s.nodeName, freed: freed}
+}
+
+// release credits the zone with the resources of a removed pod.
+func (s *numaPreemptionState) release(req zonedRequest) {
+	freed, ok := s.freed[req.zone]
+	if !ok {
+		freed = make(corev1.ResourceList)
+		s.freed[req.zone] = freed
+	}
+	for name, qty := range req.resources {
+		total := freed[name]
+		total.Add(qty)
+		freed[name] = total
+	}
+}
+
+// reclaim takes back the resources of a pod added back to the zone.
+func (s *numaPreemptionState) reclaim(req zonedRequest) {
+	freed := s.freed[req.zone]
+	for name, qty := range req.resources {
+		total, ok := freed[name]
+		if !ok {
+			continue
+		}
+		total.Sub(qty)
+		freed[name] = total
+	}
+}
+
+// creditZones adds the freed resources to the available resources of the zones, in place.
+func (s *numaPreemptionState) creditZones(zones topologyv1alpha2.ZoneList) {
+	for i := range zones {
+		freed, ok := s.freed[zones[i].Name]
+		if !ok {
+			continue
+		}
+		for j := range zones[i].Resources {
+			resInfo := &zones[i].Resources[j]
+			qty, ok := freed[corev1.ResourceName(resInfo.Name)]
+			if !ok {
+				continue
+			}
+			resInfo.Available.Add(qty)
+			if allocatable := zoneAllocatable(*resInfo); resInfo.Available.Cmp(allocatable) > 0 {
+				resInfo.Available = allocatable
+			}
+		}
+	}
+}
+
+func getNUMAPreemptionState(cycleState *framework.CycleState, nodeName string) *numaPreemptionState {
+	c, err := cycleState.Read(numaPreemptionStateKey)
+	if err != nil {
+		return nil
+	}
+	s, ok := c.(*numaPreemptionState)
+	if !ok || s.nodeName != nodeName {
+		return nil
+	}
+	return s
+}
+
+// zonedRequest is the part of the request of a running pod the kubelet pinned to a NUMA zone.
+type zonedRequest struct {
+	zone      string
+	resources corev1.ResourceList
+}
+
+// PostFilter evaluates, when the pod doesn't fit the NUMA zones of the nodes, whether evicting
+// lower-priority pods would free a single zone able to host it.
+func (tm *TopologyMatch) PostFilter(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, m framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	if !tm.numaPreemption {
+		return nil, framework.NewStatus(framework.Unschedulable, "NUMA-aware preemption is disabled")
+	}
+	if !rejectedByNUMAMisfit(m) {
+		return nil, framework.NewStatus(framework.Unschedulable, "no node was filtered out by NUMA misfit")
+	}
+
+	pe := preemption.Evaluator{
+		PluginName: tm.Name(),
+		Handler:    tm.handle,
+		PodLister:  tm.podLister,
+		PdbLister:  tm.pdbLister,
+		State:      state,
+		Interface: &numaPreemptor{
+			tm: tm,
+		},
+	}
+
+	return pe.Preempt(ctx, pod, m)
+}
+
+func rejectedByNUMAMisfit(m framework.NodeToStatusMap) bool {
+	for _, status := range m {
+		if status.Code() == framework.Unschedulable && status.Plugin() == Name {
+			return true
+		}
+	}
+	return false
+}
+
+type numaPreemptor struct {
+	tm *TopologyMatch
+}
+
+var _ preemption.Interface = &numaPreemptor{}
+
+func (p *numaPreemptor) OrderedScoreFuncs(ctx context.Context, nodesToVictims map[string]*extenderv1.Victims) []func(node string) int64 {
+	return nil
+}
+
+func (p *numaPreemptor) GetOffsetAndNumCandidates(n int32) (int32, int32) {
+	return 0, n
+}
+
+func (p *numaPreemptor) CandidatesToVictimsMap(candidates []preemption.Candidate) map[string]*extenderv1.Victims {
+	m := make(map[string]*extenderv1.Victims)
+	for _, c := range candidates {
+		m[c.Name()] = c.Victims()
+	}
+	return m
+}
+
+// PodEligibleToPreemptOthers determines whether this pod should be considered
+// for preempting other pods or not. As long as lower-priority pods are terminating
+// on the node the pod is nominated on, it waits for their NUMA resources to be freed.
+func (p *numaPreemptor) PodEligibleToPreemptOthers(pod *corev1.Pod, nominatedNodeStatus *framework.Status) (bool, string) {
+	if pod.Spec.PreemptionPolicy != nil && *pod.Spec.PreemptionPolicy == corev1.PreemptNever {
+		return false, "not eligible due to preemptionPolicy=Never."
+	}
+
+	nomNodeName := pod.Status.NominatedNodeName
+	if len(nomNodeName) == 0 || nominatedNodeStatus.Code() == framework.UnschedulableAndUnresolvable {
+		return true, ""
+	}
+	nodeInfo, _ := p.tm.handle.SnapshotSharedLister().NodeInfos().Get(nomNodeName)
+	if nodeInfo == nil {
+		return true, ""
+	}
+	podPriority := corev1helpers.PodPriority(pod)
+	for _, pi := range nodeInfo.Pods {
+		if pi.Pod.DeletionTimestamp != nil && corev1helpers.PodPriority(pi.Pod) < podPriority {
+			return false, "not eligible due to a terminating pod on the nominated node."
+		}
+	}
+	return true, ""
+}
+
+// SelectVictimsOnNode finds the lower-priority pods whose removal frees a NUMA zone for the pod,
+// reprieving as many of them as possible. The zone each running pod is pinned to is not reported,
+// so it is estimated from the resources the zones have allocated.
+func (p *numaPreemptor) SelectVictimsOnNode(
+	ctx context.Context,
+	state *framework.CycleState,
+	pod *corev1.Pod,
+	nodeInfo *framework.NodeInfo,
+	pdbs []*policy.PodDisruptionBudget) ([]*corev1.Pod, int, *framework.Status) {
+
+	nodeName := nodeInfo.Node().Name
+	lh := klog.FromContext(ctx).WithValues(logging.KeyPod, klog.KObj(pod), logging.KeyPodUID, logging.PodUID(pod), logging.KeyNode, nodeName)
+
+	nodeTopology, info := p.tm.nrtCache.GetCachedNRTCopy(ctx, nodeName, pod)
+	if !info.Fresh || nodeTopology == nil {
+		return nil, 0, framework.NewStatus(framework.UnschedulableAndUnresolvable, "no valid node topology data")
+	}
+	conf := nodeconfig.TopologyManagerFromNodeResourceTopology(lh, nodeTopology)
+	if filterHandlerFromTopologyManager(conf) == nil {
+		return nil, 0, framework.NewStatus(framework.UnschedulableAndUnresolvable, "no NUMA alignment required")
+	}
+
+	zonedRequests := estimatePodZones(lh, nodeTopology.Zones, nodeInfo.Pods)
+	numaState := &numaPreemptionState{nodeName: nodeName, freed: make(map[string]corev1.ResourceList)}
+	state.Write(numaPreemptionStateKey, numaState)
+
+	removePod := func(rpi *framework.PodInfo) error {
+		if err := nodeInfo.RemovePod(lh, rpi.Pod); err != nil {
+			return err
+		}
+		if req, ok := zonedRequests[rpi.Pod.UID]; ok {
+			numaState.release(req)
+		}
+		status := p.tm.handle.RunPreFilterExtensionRemovePod(ctx, state, pod, rpi, nodeInfo)
+		if !status.IsSuccess() {
+			return status.AsError()
+		}
+		return nil
+	}
+	addPod := func(api *framework.PodInfo) error {
+		nodeInfo.AddPodInfo(api)
+		if req, ok := zonedRequests[api.Pod.UID]; ok {
+			numaState.reclaim(req)
+		}
+		status := p.tm.handle.RunPreFilterExtensionAddPod(ctx, state, pod, api, nodeInfo)
+		if !status.IsSuccess() {
+			return status.AsError()
+		}
+		return nil
+	}
+
+	podPriority := corev1helpers.PodPriority(pod)
+	var potentialVictims []*framework.PodInfo
+	for _, pi := range nodeInfo.Pods {
+		if corev1helpers.PodPriority(pi.Pod) < podPriority {
+			potentialVictims = append(potentialVictims, pi)
+		}
+	}
+	for _, pi := range potentialVictims {
+		if err := removePod(pi); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		}
+	}
+
+	// No potential victims are found, and so we don't need to evaluate the node again since its state didn't change.
+	if len(potentialVictims) == 0 {
+		message := fmt.Sprintf("No victims found on node %v for preemptor pod %v", nodeName, pod.Name)
+		return nil, 0, framework.NewStatus(framework.UnschedulableAndUnresolvable, message)
+	}
+
+	// If the pod doesn't fit a NUMA zone even after removing all the lower priority pods,
+	// this node is not suitable for preemption.
+	if s := p.tm.handle.RunFilterPluginsWithNominatedPods(ctx, state, pod, nodeInfo); !s.IsSuccess() {
+		return nil, 0, s
+	}
+
+	var victims []*corev1.Pod
+	numViolatingVictim := 0
+	// Sort potentialVictims by pod priority from high to low, which ensures to
+	// reprieve higher priority pods first.
+	sort.Slice(potentialVictims, func(i, j int) bool {
+		return schedutil.MoreImportantPod(potentialVictims[i].Pod, potentialVictims[j].Pod)
+	})
+	// Try to reprieve as many pods as possible. We first try to reprieve the PDB
+	// violating victims and then other non-violating ones. In both cases, we start
+	// from the highest priority victims.
+	violatingVictims, nonViolatingVictims := filterPodsWithPDBViolation(potentialVictims, pdbs)
+	reprievePod := func(pi *framework.PodInfo) (bool, error) {
+		if err := addPod(pi); err != nil {
+			return false, err
+		}
+		fits := p.tm.handle.RunFilterPluginsWithNominatedPods(ctx, state, pod, nodeInfo).IsSuccess()
+		if !fits {
+			if err := removePod(pi); err != nil {
+				return false, err
+			}
+			victims = append(victims, pi.Pod)
+			lh.V(5).Info("found a NUMA preemption victim", "victim", klog.KObj(pi.Pod), "numaZone", zonedRequests[pi.Pod.UID].zone)
+		}
+		return fits, nil
+	}
+	for _, pi := range violatingVictims {
+		if fits, err := reprievePod(pi); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		} else if !fits {
+			numViolatingVictim++
+		}
+	}
+	// Now we try to reprieve non-violating victims.
+	for _, pi := range nonViolatingVictims {
+		if _, err := reprievePod(pi); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		}
+	}
+
+	// Sort victims after reprieving pods to keep the pods in the victims sorted in order of priority from high to low.
+	if len(violatingVictims) != 0 && len(nonViolatingVictims) != 0 {
+		sort.Slice(victims, func(i, j int) bool { return schedutil.MoreImportantPod(victims[i], victims[j]) })
+	}
+	return victims, numViolatingVictim, framework.NewStatus(framework.Success)
+}
+
+// estimatePodZones assigns the pods to the first NUMA zone whose allocated resources cover
+// their exclusive requests, which is where the kubelet pins single-numa-node pods.
+// The pods which can't be assigned are left out, so their removal frees nothing.
+func estimatePodZones(lh logr.Logger, zones topologyv1alpha2.ZoneList, podInfos []*framework.PodInfo) map[types.UID]zonedRequest {
+	var zoneNames []string
+	allocated := make(map[string]corev1.ResourceList)
+	numaResources := make(map[corev1.ResourceName]bool)
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		used := make(corev1.ResourceList)
+		for _, resInfo := range zone.Resources {
+			qty := zoneAllocatable(resInfo)
+			qty.Sub(resInfo.Available)
+			if qty.Sign() > 0 {
+				used[corev1.ResourceName(resInfo.Name)] = qty
+			}
+			numaResources[corev1.ResourceName(resInfo.Name)] = true
+		}
+		zoneNames = append(zoneNames, zone.Name)
+		allocated[zone.Name] = used
+	}
+
+	zonedRequests := make(map[types.UID]zonedRequest)
+	for _, pi := range podInfos {
+		resources := exclusiveNUMARequests(pi.Pod, numaResources)
+		if len(resources) == 0 {
+			continue
+		}
+		for _, zoneName := range zoneNames {
+			used := allocated[zoneName]
+			if !allocatedCovers(used, resources) {
+				continue
+			}
+			for name, qty := range resources {
+				remaining := used[name]
+				remaining.Sub(qty)
+				used[name] = remaining
+			}
+			zonedRequests[pi.Pod.UID] = zonedRequest{zone: zoneName, resources: resources}
+			lh.V(6).Info("estimated NUMA zone", "pod", klog.KObj(pi.Pod), "numaZone", zoneName)
+			break
+		}
+	}
+	return zonedRequests
+}
+
+// exclusiveNUMARequests returns the requests of the pod the kubelet allocates from a NUMA zone.
+func exclusiveNUMARequests(pod *corev1.Pod, numaResources map[corev1.ResourceName]bool) corev1.ResourceList {
+	qos := v1qos.GetPodQOS(pod)
+	resources := make(corev1.ResourceList)
+	for name, qty := range util.GetPodEffectiveRequest(pod) {
+		if numaResources[name] && resourcerequests.IsExclusive(qos, name, qty) {
+			resources[name] = qty
+		}
+	}
+	return resources
+}
+
+func allocatedCovers(allocated, resources corev1.ResourceList) bool {
+	for name, qty := range resources {
+		used, ok := allocated[name]
+		if !ok || used.Cmp(qty) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// zoneAllocatable returns the allocatable quantity of the resource, which agents may leave unset.
+func zoneAllocatable(resInfo topologyv1alpha2.ResourceInfo) resource.Quantity {
+	if resInfo.Allocatable.IsZero() {
+		return resInfo.Capacity.DeepCopy()
+	}
+	return resInfo.Allocatable.DeepCopy()
+}
+
+// filterPodsWithPDBViolation groups the given "pods" into two groups of "violatingPods"
+// and "nonViolatingPods" based on whether their PDBs will be violated if they are
+// preempted.
+// This function is stable and does not change the order of received pods. So, if it
+// receives a sorted list, grouping will preserve the order of the input list.
+func filterPodsWithPDBViolation(podInfos []*framework.PodInfo, pdbs []*policy.PodDisruptionBudget) (violatingPods, nonViolatingPods []*framework.PodInfo) {
+	pdbsAllowed := make([]int32, len(pdbs))
+	for i, pdb := range pdbs {
+		pdbsAllowed[i] = pdb.Status.DisruptionsAllowed
+	}
+
+	for _, podInfo := range podInfos {
+		pod := podInfo.Pod
+		pdbForPodIsViolated := false
+		// A pod with no labels will not match any PDB. So, no need to check.
+		if len(pod.Labels) != 0 {
+			for i, pdb := range pdbs {
+				if pdb.Namespace != pod.Namespace {
+					continue
+				}
+				selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+				if err != nil {
+					continue
+				}
+				// A PDB with a nil or empty selector matches nothing.
+				if selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+					continue
+				}
+
+				// Existing in DisruptedPods means it has been processed in API server,
+				// we don't treat it as a violating case.
+				if _, exist := pdb.Status.DisruptedPods[pod.Name]; exist {
+					continue
+				}
+				// Only decrement the matched pdb when it's not in its <DisruptedPods>;
+				// otherwise we may over-decrement the budget number.
+				pdbsAllowed[i]--
+				// We have found a matching PDB.
+				if pdbsAllowed[i] < 0 {
+					pdbForPodIsViolated = true
+				}
+			}
+		}
+		if pdbForPodIsViolated {
+			violatingPods = append(violatingPods, podInfo)
+		} else {
+			nonViolatingPods = append(nonViolatingPods, podInfo)
+		}
+	}
+	return violatingPods, nonViolatingPods
+}
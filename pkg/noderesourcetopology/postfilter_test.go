@@ -0,0 +1,284 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	nrtcache "github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "github.com/amiraBenamer20/scheduler-plugins/test/util"
+)
+
+func makePreemptionTestPod(name string, priority int32, cpus int64, startTime time.Time) *v1.Pod {
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewQuantity(cpus, resource.DecimalSI),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+	pod.Name = name
+	pod.Namespace = metav1.NamespaceDefault
+	pod.UID = types.UID(name)
+	pod.Spec.Containers[0].Name = containerName
+	pod.Spec.Priority = &priority
+	pod.Status.StartTime = &metav1.Time{Time: startTime}
+	return pod
+}
+
+func newNUMAPreemptionTestPlugin(ctx context.Context, t *testing.T, nrts []*topologyv1alpha2.NodeResourceTopology, nodes []*v1.Node, pods []*v1.Pod, pod *v1.Pod) *TopologyMatch {
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(ctx, nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	podItems := []v1.Pod{}
+	for _, pod := range pods {
+		podItems = append(podItems, *pod)
+	}
+	cs := clientsetfake.NewSimpleClientset(&v1.PodList{Items: podItems})
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	podInformer := informerFactory.Core().V1().Pods().Informer()
+	podInformer.GetStore().Add(pod)
+	for i := range pods {
+		podInformer.GetStore().Add(pods[i])
+	}
+
+	tm := &TopologyMatch{
+		nrtCache:       nrtcache.NewPassthrough(klog.Background(), fakeClient),
+		numaPreemption: true,
+		podLister:      informerFactory.Core().V1().Pods().Lister(),
+		pdbLister:      informerFactory.Policy().V1().PodDisruptionBudgets().Lister(),
+	}
+	registeredPlugins := []tf.RegisterPluginFunc{
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		tf.RegisterFilterPlugin(Name, func(_ context.Context, _ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+			return tm, nil
+		}),
+	}
+	fwk, err := tf.NewFramework(
+		ctx,
+		registeredPlugins,
+		"default-scheduler",
+		frameworkruntime.WithClientSet(cs),
+		frameworkruntime.WithEventRecorder(&events.FakeRecorder{}),
+		frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithPodNominator(tu.NewPodNominator(informerFactory.Core().V1().Pods().Lister())),
+		frameworkruntime.WithSnapshotSharedLister(tu.NewFakeSharedLister(pods, nodes)),
+		frameworkruntime.WithWaitingPods(frameworkruntime.NewWaitingPodsMap()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm.handle = fwk
+	return tm
+}
+
+func TestNUMAPreemptionSelectVictimsOnNode(t *testing.T) {
+	// node-0 is fully allocated to the low priority pods, 3 CPUs of node-1 to the high priority one
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "0"),
+					MakeTopologyResInfo(memory, "8Gi", "6Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "1"),
+					MakeTopologyResInfo(memory, "8Gi", "7Gi"),
+				},
+			},
+		},
+	}
+	node := makeNodeFromNodeResourceTopology(&topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Zones: topologyv1alpha2.ZoneList{{Resources: topologyv1alpha2.ResourceInfoList{
+			MakeTopologyResInfo(cpu, "8", "8"),
+			MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+		}}},
+	})
+
+	now := time.Now()
+	low1 := makePreemptionTestPod("low1", 1, 2, now.Add(-time.Hour))
+	low2 := makePreemptionTestPod("low2", 1, 2, now)
+	high := makePreemptionTestPod("high", 100, 3, now)
+	existingPods := []*v1.Pod{low1, low2, high}
+
+	tests := []struct {
+		name        string
+		pod         *v1.Pod
+		wantVictims []string
+		wantCode    framework.Code
+	}{
+		{
+			name:        "one victim frees enough of its NUMA zone",
+			pod:         makePreemptionTestPod("p", 10, 2, now),
+			wantVictims: []string{"low2"},
+			wantCode:    framework.Success,
+		},
+		{
+			name:        "all the pods of the NUMA zone are evicted",
+			pod:         makePreemptionTestPod("p", 10, 4, now),
+			wantVictims: []string{"low1", "low2"},
+			wantCode:    framework.Success,
+		},
+		{
+			name:     "no NUMA zone can be freed",
+			pod:      makePreemptionTestPod("p", 10, 5, now),
+			wantCode: framework.Unschedulable,
+		},
+		{
+			name:     "no lower priority pods",
+			pod:      makePreemptionTestPod("p", 1, 2, now),
+			wantCode: framework.UnschedulableAndUnresolvable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			tm := newNUMAPreemptionTestPlugin(ctx, t, []*topologyv1alpha2.NodeResourceTopology{nrt}, []*v1.Node{node}, existingPods, tt.pod)
+
+			state := framework.NewCycleState()
+			nodeInfo := framework.NewNodeInfo(existingPods...)
+			nodeInfo.SetNode(node)
+			if status := tm.Filter(ctx, state, tt.pod, nodeInfo); status.IsSuccess() {
+				t.Fatalf("expected the pod not to fit before preemption")
+			}
+
+			p := &numaPreemptor{tm: tm}
+			victims, _, status := p.SelectVictimsOnNode(ctx, state, tt.pod, nodeInfo, nil)
+			if status.Code() != tt.wantCode {
+				t.Fatalf("unexpected status: %v, want code %v", status, tt.wantCode)
+			}
+			var gotVictims []string
+			for _, victim := range victims {
+				gotVictims = append(gotVictims, victim.Name)
+			}
+			if !reflect.DeepEqual(gotVictims, tt.wantVictims) {
+				t.Errorf("unexpected victims %v, want %v", gotVictims, tt.wantVictims)
+			}
+		})
+	}
+}
+
+func TestNUMAPreemptionPostFilter(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "0"),
+					MakeTopologyResInfo(memory, "8Gi", "7Gi"),
+				},
+			},
+		},
+	}
+	node := makeNodeFromNodeResourceTopology(&topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Zones: topologyv1alpha2.ZoneList{{Resources: topologyv1alpha2.ResourceInfoList{
+			MakeTopologyResInfo(cpu, "4", "4"),
+			MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+		}}},
+	})
+	low := makePreemptionTestPod("low", 1, 4, time.Now())
+	low.Spec.NodeName = "node1"
+	pod := makePreemptionTestPod("p", 10, 2, time.Now())
+
+	misfit := framework.NewStatus(framework.Unschedulable, "cannot align pod")
+	misfit.SetPlugin(Name)
+	otherMisfit := framework.NewStatus(framework.Unschedulable, "Insufficient cpu")
+	otherMisfit.SetPlugin("NodeResourcesFit")
+
+	tests := []struct {
+		name                  string
+		disabled              bool
+		filteredNodesStatuses framework.NodeToStatusMap
+		wantResult            *framework.PostFilterResult
+		wantCode              framework.Code
+	}{
+		{
+			name:                  "pod nominated on the node with a freed NUMA zone",
+			filteredNodesStatuses: framework.NodeToStatusMap{"node1": misfit},
+			wantResult:            framework.NewPostFilterResultWithNominatedNode("node1"),
+			wantCode:              framework.Success,
+		},
+		{
+			name:                  "NUMA-aware preemption disabled",
+			disabled:              true,
+			filteredNodesStatuses: framework.NodeToStatusMap{"node1": misfit},
+			wantCode:              framework.Unschedulable,
+		},
+		{
+			name:                  "node filtered out by another plugin",
+			filteredNodesStatuses: framework.NodeToStatusMap{"node1": otherMisfit},
+			wantCode:              framework.Unschedulable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			tm := newNUMAPreemptionTestPlugin(ctx, t, []*topologyv1alpha2.NodeResourceTopology{nrt}, []*v1.Node{node}, []*v1.Pod{low}, pod)
+			tm.numaPreemption = !tt.disabled
+
+			gotResult, gotStatus := tm.PostFilter(ctx, framework.NewCycleState(), pod, tt.filteredNodesStatuses)
+			if gotStatus.Code() != tt.wantCode {
+				t.Fatalf("unexpected status: %v, want code %v", gotStatus, tt.wantCode)
+			}
+			if !reflect.DeepEqual(gotResult, tt.wantResult) {
+				t.Errorf("unexpected result %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
@@ -33,6 +33,7 @@ func (tm *TopologyMatch) Reserve(ctx context.Context, state *framework.CycleStat
 	lh.V(4).Info(logging.FlowBegin)
 	defer lh.V(4).Info(logging.FlowEnd)
 
+	tm.reserveNUMAAlignment(ctx, lh, state, pod, nodeName)
 	tm.nrtCache.ReserveNodeResources(nodeName, pod)
 	// can't fail
 	return framework.NewStatus(framework.Success, "")
@@ -44,5 +45,6 @@ func (tm *TopologyMatch) Unreserve(ctx context.Context, state *framework.CycleSt
 	lh.V(4).Info(logging.FlowBegin)
 	defer lh.V(4).Info(logging.FlowEnd)
 
+	tm.gangAlignments.forget(pod)
 	tm.nrtCache.UnreserveNodeResources(nodeName, pod)
 }
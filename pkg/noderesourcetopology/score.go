@@ -98,7 +98,16 @@ func (tm *TopologyMatch) Score(ctx context.Context, state *framework.CycleState,
 	if handler == nil {
 		return 0, nil
 	}
-	return handler(lh, pod, nodeTopology.Zones)
+	score, status := handler(lh, pod, nodeTopology.Zones)
+	if !status.IsSuccess() {
+		return score, status
+	}
+	// the gang members with identical requests prefer the same NUMA alignment as the members already placed
+	if shape, ok := tm.gangAlignments.reference(lh, pod); ok && !symmetric(lh, pod, nodeTopology, tm.snapshotNode(nodeName), shape) {
+		lh.V(4).Info("NUMA alignment not symmetric with the gang", "score", score)
+		return 0, nil
+	}
+	return score, nil
 }
 
 func (tm *TopologyMatch) ScoreExtensions() framework.ScoreExtensions {
@@ -131,7 +140,7 @@ func getScoringStrategyFunction(strategy apiconfig.ScoringStrategyType) (scoreSt
 		return leastAllocatedScoreStrategy, nil
 	case apiconfig.BalancedAllocation:
 		return balancedAllocationScoreStrategy, nil
-	case apiconfig.LeastNUMANodes:
+	case apiconfig.LeastNUMANodes, apiconfig.LeastNUMADistance:
 		// this is a special case handled down the flow. We just need to NOT error out.
 		return nil, nil
 	default:
@@ -177,6 +186,15 @@ func (tm *TopologyMatch) scoringHandlerFromTopologyManagerConfig(conf nodeconfig
 		}
 		return nil // cannot happen
 	}
+	if tm.scoreStrategyType == apiconfig.LeastNUMADistance {
+		if conf.Scope == kubeletconfig.PodTopologyManagerScope {
+			return leastNUMADistancePodScopeScore
+		}
+		if conf.Scope == kubeletconfig.ContainerTopologyManagerScope {
+			return leastNUMADistanceContainerScopeScore
+		}
+		return nil // cannot happen
+	}
 	if conf.Policy != kubeletconfig.SingleNumaNodeTopologyManagerPolicy {
 		return nil
 	}
@@ -23,11 +23,15 @@ import (
 
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
 )
 
 type PodState struct {
 	handle framework.Handle
+	args   *config.PodStateArgs
 }
 
 var _ = framework.ScorePlugin(&PodState{})
@@ -46,8 +50,8 @@ func (ps *PodState) Score(ctx context.Context, state *framework.CycleState, pod
 		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
 
-	// pe.score favors nodes with terminating pods instead of nominated pods
-	// It calculates the sum of the node's terminating pods and nominated pods
+	// pe.score favors nodes with terminating pods instead of nominated and not-yet-ready pods
+	// It calculates the weighted sum of the node's terminating, nominated and not-yet-ready pods
 	return ps.score(nodeInfo)
 }
 
@@ -57,16 +61,29 @@ func (ps *PodState) ScoreExtensions() framework.ScoreExtensions {
 }
 
 func (ps *PodState) score(nodeInfo *framework.NodeInfo) (int64, *framework.Status) {
-	var terminatingPodNum, nominatedPodNum int64
+	var terminatingPodNum, nominatedPodNum, notReadyPodNum int64
 	// get nominated Pods for node from nominatedPodMap
 	nominatedPodNum = int64(len(ps.handle.NominatedPodsForNode(nodeInfo.Node().Name)))
 	for _, p := range nodeInfo.Pods {
 		// Pod is terminating if DeletionTimestamp has been set
 		if p.Pod.DeletionTimestamp != nil {
 			terminatingPodNum++
+		} else if ps.args.NotReadyWeight > 0 && isStarting(p.Pod) {
+			notReadyPodNum++
 		}
 	}
-	return terminatingPodNum - nominatedPodNum, nil
+	return terminatingPodNum*ps.args.TerminatingWeight -
+		nominatedPodNum*ps.args.NominatedWeight -
+		notReadyPodNum*ps.args.NotReadyWeight, nil
+}
+
+// isStarting checks if a pod is not ready yet, i.e. still starting its containers,
+// assumed pods without status included.
+func isStarting(pod *v1.Pod) bool {
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return false
+	}
+	return !podutil.IsPodReady(pod)
 }
 
 func (ps *PodState) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
@@ -97,6 +114,14 @@ func (ps *PodState) NormalizeScore(ctx context.Context, state *framework.CycleSt
 }
 
 // New initializes a new plugin and returns it.
-func New(_ context.Context, _ runtime.Object, h framework.Handle) (framework.Plugin, error) {
-	return &PodState{handle: h}, nil
+func New(_ context.Context, obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	// Without args, terminating and nominated pods are counted once and not-yet-ready pods are ignored.
+	args := &config.PodStateArgs{TerminatingWeight: 1, NominatedWeight: 1}
+	if obj != nil {
+		var ok bool
+		if args, ok = obj.(*config.PodStateArgs); !ok {
+			return nil, fmt.Errorf("want args to be of type PodStateArgs, got %T", obj)
+		}
+	}
+	return &PodState{handle: h, args: args}, nil
 }
@@ -24,6 +24,7 @@ import (
 
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
@@ -36,6 +37,8 @@ import (
 	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
 	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
 )
 
 func TestPodState(t *testing.T) {
@@ -44,6 +47,7 @@ func TestPodState(t *testing.T) {
 		wantErr      string
 		expectedList framework.NodeScoreList
 		name         string
+		args         *config.PodStateArgs
 	}{
 		{
 			nodeInfos:    []*framework.NodeInfo{makeNodeInfo("node1", 6, 0, 10), makeNodeInfo("node2", 3, 0, 10), makeNodeInfo("node3", 0, 0, 10)},
@@ -70,6 +74,23 @@ func TestPodState(t *testing.T) {
 			expectedList: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: 50}, {Name: "node3", Score: 33}, {Name: "node4", Score: framework.MinNodeScore}},
 			name:         "node has more (terminatingPodNumber - nominatedPodNumber) will be scored with higher score",
 		},
+		{
+			nodeInfos:    []*framework.NodeInfo{withStartingPods(makeNodeInfo("node1", 0, 0, 10), 4), withStartingPods(makeNodeInfo("node2", 0, 0, 10), 2), makeNodeInfo("node3", 0, 0, 10)},
+			expectedList: []framework.NodeScore{{Name: "node1", Score: framework.MinNodeScore}, {Name: "node2", Score: 50}, {Name: "node3", Score: framework.MaxNodeScore}},
+			name:         "node has more not-ready pods will be scored with lower score when weighted",
+			args:         &config.PodStateArgs{TerminatingWeight: 1, NominatedWeight: 1, NotReadyWeight: 1},
+		},
+		{
+			nodeInfos:    []*framework.NodeInfo{withStartingPods(makeNodeInfo("node1", 0, 0, 10), 4), makeNodeInfo("node2", 0, 0, 10)},
+			expectedList: []framework.NodeScore{{Name: "node1", Score: framework.MinNodeScore}, {Name: "node2", Score: framework.MinNodeScore}},
+			name:         "not-ready pods are ignored by default",
+		},
+		{
+			nodeInfos:    []*framework.NodeInfo{withStartingPods(makeNodeInfo("node1", 2, 0, 10), 1), makeNodeInfo("node2", 0, 1, 10)},
+			expectedList: []framework.NodeScore{{Name: "node1", Score: framework.MaxNodeScore}, {Name: "node2", Score: framework.MinNodeScore}},
+			name:         "weighted terminating pods outweigh weighted not-ready and nominated pods",
+			args:         &config.PodStateArgs{TerminatingWeight: 2, NominatedWeight: 3, NotReadyWeight: 1},
+		},
 	}
 
 	for _, test := range tests {
@@ -105,7 +126,14 @@ func TestPodState(t *testing.T) {
 					}
 				}
 			}
-			pe, _ := New(nil, nil, fh)
+			var args runtime.Object
+			if test.args != nil {
+				args = test.args
+			}
+			pe, err := New(nil, args, fh)
+			if err != nil {
+				t.Fatalf("fail to create plugin: %s", err)
+			}
 			var gotList framework.NodeScoreList
 			plugin := pe.(framework.ScorePlugin)
 			for i, n := range test.nodeInfos {
@@ -183,9 +211,25 @@ func makeRegularPod(name string) *v1.Pod {
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+		},
 	}
 }
 
+func withStartingPods(ni *framework.NodeInfo, startingPodNumber int) *framework.NodeInfo {
+	for i := 0; i < startingPodNumber; i++ {
+		ni.Pods = append(ni.Pods, &framework.PodInfo{
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("spod_%s_%v", ni.Node().Name, i+1)},
+				Status:     v1.PodStatus{Phase: v1.PodPending},
+			},
+		})
+	}
+	return ni
+}
+
 func addNominatedPod(logger klog.Logger, pi *framework.PodInfo, nodeName string, fh framework.Handle) *framework.PodInfo {
 	fh.AddNominatedPod(logger, pi, &framework.NominatingInfo{NominatingMode: framework.ModeOverride, NominatedNodeName: nodeName})
 	return pi
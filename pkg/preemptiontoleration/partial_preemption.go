@@ -0,0 +1,252 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptiontoleration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+const (
+	// AnnotationKeyMaxShrinkPercentage opts a pod in partial preemption: the percentage by which the cpu and
+	// memory requests and limits of its containers can be shrunk with an in-place resize, instead of evicting it.
+	AnnotationKeyMaxShrinkPercentage = AnnotationKeyPrefix + "max-shrink-percentage"
+	// AnnotationKeyResizedBy is set on the pods resized by partial preemption, to the preemptor.
+	// A pod is resized at most once: if it needs to be preempted again, it is evicted.
+	AnnotationKeyResizedBy = AnnotationKeyPrefix + "resized-by"
+)
+
+// resizableResources are the resources that can be resized in place
+var resizableResources = []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory}
+
+// podResize is a victim and its shrunk copy
+type podResize struct {
+	pod     *v1.Pod
+	resized *v1.Pod
+}
+
+// partiallyPreempt finds the node where shrinking the fewest lower priority pods opting in makes room
+// for the preemptor, resizes them and nominates the preemptor on that node.
+// A nil result means that partial preemption is not possible, and the victims must be evicted.
+func (pl *PreemptionToleration) partiallyPreempt(ctx context.Context, state *framework.CycleState, pod *v1.Pod, m framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	logger := klog.FromContext(ctx)
+	if ok, _ := pl.PodEligibleToPreemptOthers(pod, m[pod.Status.NominatedNodeName]); !ok {
+		return nil, nil
+	}
+	nodeInfos, err := pl.fh.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		logger.Error(err, "Could not list the nodes for partial preemption", "pod", klog.KObj(pod))
+		return nil, nil
+	}
+
+	var bestNode string
+	var bestResizes []podResize
+	for _, nodeInfo := range nodeInfos {
+		// the nodes where the pod is unschedulable and unresolvable can't be helped
+		if m[nodeInfo.Node().Name].Code() != framework.Unschedulable {
+			continue
+		}
+		resizes, ok := pl.selectResizesOnNode(ctx, state.Clone(), pod, nodeInfo.Snapshot())
+		if ok && (bestNode == "" || len(resizes) < len(bestResizes)) {
+			bestNode, bestResizes = nodeInfo.Node().Name, resizes
+		}
+	}
+	if bestNode == "" {
+		return nil, nil
+	}
+
+	for _, r := range bestResizes {
+		if err := pl.resizePod(ctx, r, pod); err != nil {
+			logger.Error(err, "Could not resize the victim, falling back to eviction", "pod", klog.KObj(r.pod), "preemptor", klog.KObj(pod))
+			return nil, nil
+		}
+		pl.fh.EventRecorder().Eventf(r.pod, pod, v1.EventTypeNormal, "PartiallyPreempted", "Resizing", "Resized by pod %v on node %v", pod.UID, bestNode)
+		logger.V(2).Info("Preemptor pod resized victim pod", "preemptor", klog.KObj(pod), "victim", klog.KObj(r.pod), "node", bestNode)
+	}
+	return framework.NewPostFilterResultWithNominatedNode(bestNode), framework.NewStatus(framework.Success)
+}
+
+// selectResizesOnNode shrinks the lower priority pods opting in on the node, the least important first,
+// until the cpu and memory missing for the preemptor are freed. It returns false if the preemptor
+// does not fit on the node afterwards.
+// Note that both state and nodeInfo are modified.
+func (pl *PreemptionToleration) selectResizesOnNode(ctx context.Context, state *framework.CycleState, preemptor *v1.Pod, nodeInfo *framework.NodeInfo) ([]podResize, bool) {
+	logger := klog.FromContext(ctx)
+	request := framework.NewResource(resourcehelper.PodRequests(preemptor, resourcehelper.PodResourcesOptions{}))
+	shortage := map[v1.ResourceName]int64{
+		v1.ResourceCPU:    request.MilliCPU - (nodeInfo.Allocatable.MilliCPU - nodeInfo.Requested.MilliCPU),
+		v1.ResourceMemory: request.Memory - (nodeInfo.Allocatable.Memory - nodeInfo.Requested.Memory),
+	}
+	// the preemptor doesn't fit for another reason than its cpu and memory
+	if !missing(shortage) {
+		return nil, false
+	}
+
+	podPriority := corev1helpers.PodPriority(preemptor)
+	var candidates []*framework.PodInfo
+	for _, pi := range nodeInfo.Pods {
+		if corev1helpers.PodPriority(pi.Pod) >= podPriority || !pl.resizable(pi.Pod) {
+			continue
+		}
+		exempted, err := ExemptedFromPreemption(pi.Pod, preemptor, pl.priorityClassLister, pl.curTime)
+		if err != nil || exempted {
+			continue
+		}
+		candidates = append(candidates, pi)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return schedutil.MoreImportantPod(candidates[j].Pod, candidates[i].Pod) })
+
+	var resizes []podResize
+	for _, pi := range candidates {
+		if !missing(shortage) {
+			break
+		}
+		percentage, _ := maxShrinkPercentage(pi.Pod)
+		resized, ok := shrinkPod(pi.Pod, percentage, shortage)
+		if !ok {
+			continue
+		}
+		resizedInfo, err := framework.NewPodInfo(resized)
+		if err != nil {
+			return nil, false
+		}
+		if err := nodeInfo.RemovePod(logger, pi.Pod); err != nil {
+			return nil, false
+		}
+		if status := pl.fh.RunPreFilterExtensionRemovePod(ctx, state, preemptor, pi, nodeInfo); !status.IsSuccess() {
+			return nil, false
+		}
+		nodeInfo.AddPodInfo(resizedInfo)
+		if status := pl.fh.RunPreFilterExtensionAddPod(ctx, state, preemptor, resizedInfo, nodeInfo); !status.IsSuccess() {
+			return nil, false
+		}
+		resizes = append(resizes, podResize{pod: pi.Pod, resized: resized})
+	}
+	if missing(shortage) {
+		return nil, false
+	}
+	if status := pl.fh.RunFilterPluginsWithNominatedPods(ctx, state, preemptor, nodeInfo); !status.IsSuccess() {
+		return nil, false
+	}
+	return resizes, true
+}
+
+// resizable returns whether the pod opts in partial preemption, is running and was not resized yet
+func (pl *PreemptionToleration) resizable(pod *v1.Pod) bool {
+	if _, ok := maxShrinkPercentage(pod); !ok {
+		return false
+	}
+	if _, resized := pod.Annotations[AnnotationKeyResizedBy]; resized || pod.Status.Resize != "" {
+		return false
+	}
+	return pod.DeletionTimestamp == nil && pod.Status.Phase == v1.PodRunning && pl.fh.GetWaitingPod(pod.UID) == nil
+}
+
+// maxShrinkPercentage returns the percentage of AnnotationKeyMaxShrinkPercentage, if valid
+func maxShrinkPercentage(pod *v1.Pod) (int64, bool) {
+	value, ok := pod.Annotations[AnnotationKeyMaxShrinkPercentage]
+	if !ok {
+		return 0, false
+	}
+	percentage, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || percentage <= 0 || percentage > 100 {
+		return 0, false
+	}
+	return percentage, true
+}
+
+// shrinkPod returns a copy of the pod whose containers requests and limits are shrunk by up to the given
+// percentage of their requests, and no more than the shortage, which is decreased accordingly.
+// It returns false if no container was shrunk.
+func shrinkPod(pod *v1.Pod, percentage int64, shortage map[v1.ResourceName]int64) (*v1.Pod, bool) {
+	resized := pod.DeepCopy()
+	shrunk := false
+	for i := range resized.Spec.Containers {
+		resources := &resized.Spec.Containers[i].Resources
+		for _, name := range resizableResources {
+			request, ok := resources.Requests[name]
+			if !ok || shortage[name] <= 0 {
+				continue
+			}
+			shrink := min(quantityValue(name, request)*percentage/100, shortage[name])
+			if shrink <= 0 {
+				continue
+			}
+			resources.Requests[name] = newQuantity(name, quantityValue(name, request)-shrink)
+			// the limit is shrunk by the same amount to keep the QoS class of the pod
+			if limit, ok := resources.Limits[name]; ok {
+				resources.Limits[name] = newQuantity(name, quantityValue(name, limit)-shrink)
+			}
+			shortage[name] -= shrink
+			shrunk = true
+		}
+	}
+	return resized, shrunk
+}
+
+// resizePod patches the resources of the victim in place, and records the preemptor
+func (pl *PreemptionToleration) resizePod(ctx context.Context, r podResize, preemptor *v1.Pod) error {
+	resized := r.resized.DeepCopy()
+	if resized.Annotations == nil {
+		resized.Annotations = map[string]string{}
+	}
+	resized.Annotations[AnnotationKeyResizedBy] = fmt.Sprintf("%v/%v", preemptor.Namespace, preemptor.Name)
+	patch, err := util.CreateMergePatch(r.pod, resized)
+	if err != nil {
+		return err
+	}
+	_, err = pl.fh.ClientSet().CoreV1().Pods(r.pod.Namespace).Patch(ctx, r.pod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func missing(shortage map[v1.ResourceName]int64) bool {
+	for _, value := range shortage {
+		if value > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// quantityValue returns the value of the quantity, in millicores for the cpu
+func quantityValue(name v1.ResourceName, q resource.Quantity) int64 {
+	if name == v1.ResourceCPU {
+		return q.MilliValue()
+	}
+	return q.Value()
+}
+
+func newQuantity(name v1.ResourceName, value int64) resource.Quantity {
+	if name == v1.ResourceCPU {
+		return *resource.NewMilliQuantity(value, resource.DecimalSI)
+	}
+	return *resource.NewQuantity(value, resource.BinarySI)
+}
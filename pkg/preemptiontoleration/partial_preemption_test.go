@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptiontoleration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+	schedconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	plfeature "k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/noderesources"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+type fakeSharedLister struct {
+	tf.NodeInfoLister
+}
+
+func (f fakeSharedLister) NodeInfos() framework.NodeInfoLister {
+	return f.NodeInfoLister
+}
+
+func (f fakeSharedLister) StorageInfos() framework.StorageInfoLister {
+	return nil
+}
+
+// fakePodNominator : no pod is nominated
+type fakePodNominator struct{}
+
+func (fakePodNominator) AddNominatedPod(klog.Logger, *framework.PodInfo, *framework.NominatingInfo) {}
+func (fakePodNominator) DeleteNominatedPodIfExists(*corev1.Pod)                                     {}
+func (fakePodNominator) UpdateNominatedPod(klog.Logger, *corev1.Pod, *framework.PodInfo)            {}
+func (fakePodNominator) NominatedPodsForNode(string) []*framework.PodInfo                           { return nil }
+
+func makeRunningPod(name string, priority int32, cpu, memory string) *st.PodWrapper {
+	return st.MakePod().Name(name).UID(name).Namespace("default").Node("node-a").Priority(priority).
+		Res(map[corev1.ResourceName]string{corev1.ResourceCPU: cpu, corev1.ResourceMemory: memory}).
+		Phase(corev1.PodRunning)
+}
+
+func TestPartialPreemption(t *testing.T) {
+	node := st.MakeNode().Name("node-a").Capacity(map[corev1.ResourceName]string{
+		corev1.ResourceCPU: "4", corev1.ResourceMemory: "8Gi", corev1.ResourcePods: "10",
+	}).Obj()
+	shrinkable := makeRunningPod("shrinkable", 1, "2", "4Gi").Annotation(AnnotationKeyMaxShrinkPercentage, "50").Obj()
+	notOptingIn := makeRunningPod("not-opting-in", 1, "2", "4Gi").Obj()
+
+	tests := []struct {
+		name        string
+		pods        []*corev1.Pod
+		preemptor   *corev1.Pod
+		wantNode    string
+		wantCPU     string
+		wantMemory  string
+		wantResized bool
+	}{
+		{
+			name:        "the pod opting in is shrunk of what the preemptor needs",
+			pods:        []*corev1.Pod{shrinkable, notOptingIn},
+			preemptor:   makeRunningPod("preemptor", 10, "500m", "1Gi").Node("").Obj(),
+			wantNode:    "node-a",
+			wantCPU:     "1500m",
+			wantMemory:  "3Gi",
+			wantResized: true,
+		},
+		{
+			name:      "shrinking is not enough, the pods are evicted",
+			pods:      []*corev1.Pod{shrinkable, notOptingIn},
+			preemptor: makeRunningPod("preemptor", 10, "2", "1Gi").Node("").Obj(),
+		},
+		{
+			name: "a pod is resized at most once",
+			pods: []*corev1.Pod{
+				makeRunningPod("shrinkable", 1, "2", "4Gi").Annotation(AnnotationKeyMaxShrinkPercentage, "50").
+					Annotation(AnnotationKeyResizedBy, "default/other").Obj(),
+				notOptingIn,
+			},
+			preemptor: makeRunningPod("preemptor", 10, "500m", "1Gi").Node("").Obj(),
+		},
+		{
+			name:      "the pod opting in has a higher priority than the preemptor",
+			pods:      []*corev1.Pod{shrinkable, notOptingIn},
+			preemptor: makeRunningPod("preemptor", 1, "500m", "1Gi").Node("").Obj(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			objects := []runtime.Object{}
+			for _, pod := range tt.pods {
+				objects = append(objects, pod.DeepCopy())
+			}
+			cs := fake.NewSimpleClientset(objects...)
+			nodeInfo := framework.NewNodeInfo(tt.pods...)
+			nodeInfo.SetNode(node)
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterPluginAsExtensions(noderesources.Name, func(ctx context.Context, _ runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+					args := &schedconfig.NodeResourcesFitArgs{ScoringStrategy: &schedconfig.ScoringStrategy{
+						Type:      schedconfig.LeastAllocated,
+						Resources: []schedconfig.ResourceSpec{{Name: "cpu", Weight: 1}, {Name: "memory", Weight: 1}},
+					}}
+					return noderesources.NewFit(ctx, args, fh, plfeature.Features{})
+				}, "PreFilter", "Filter"),
+			}
+			fwk, err := tf.NewFramework(
+				ctx,
+				registeredPlugins,
+				"default-scheduler",
+				frameworkruntime.WithClientSet(cs),
+				frameworkruntime.WithEventRecorder(&events.FakeRecorder{}),
+				frameworkruntime.WithInformerFactory(informerFactory),
+				frameworkruntime.WithPodNominator(fakePodNominator{}),
+				frameworkruntime.WithSnapshotSharedLister(fakeSharedLister{tf.NodeInfoLister{nodeInfo}}),
+				frameworkruntime.WithWaitingPods(frameworkruntime.NewWaitingPodsMap()),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pl := &PreemptionToleration{
+				fh:                  fwk,
+				args:                config.PreemptionTolerationArgs{PartialPreemption: true},
+				priorityClassLister: informerFactory.Scheduling().V1().PriorityClasses().Lister(),
+				clock:               testingclock.NewFakeClock(time.Now()),
+			}
+			pl.curTime = pl.clock.Now()
+
+			state := framework.NewCycleState()
+			if _, status, _ := fwk.RunPreFilterPlugins(ctx, state, tt.preemptor); !status.IsSuccess() {
+				t.Fatal(status.AsError())
+			}
+			m := framework.NodeToStatusMap{"node-a": framework.NewStatus(framework.Unschedulable)}
+			result, status := pl.partiallyPreempt(ctx, state, tt.preemptor, m)
+			if tt.wantNode == "" {
+				if result != nil {
+					t.Fatalf("expected no partial preemption, got %v", result.NominatingInfo)
+				}
+			} else if result == nil || !status.IsSuccess() || result.NominatedNodeName != tt.wantNode {
+				t.Fatalf("expected the preemptor to be nominated on %v, got %v, %v", tt.wantNode, result, status)
+			}
+
+			got, err := cs.CoreV1().Pods("default").Get(ctx, "shrinkable", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resizedBy := got.Annotations[AnnotationKeyResizedBy]; (resizedBy == "default/preemptor") != tt.wantResized {
+				t.Errorf("expected the pod to be resized by the preemptor: %v, got %v", tt.wantResized, got.Annotations)
+			}
+			if !tt.wantResized {
+				return
+			}
+			resources := got.Spec.Containers[0].Resources
+			for _, list := range []corev1.ResourceList{resources.Requests, resources.Limits} {
+				if cpu := list[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse(tt.wantCPU)) != 0 {
+					t.Errorf("expected the cpu to be shrunk to %v, got %v", tt.wantCPU, cpu.String())
+				}
+				if memory := list[corev1.ResourceMemory]; memory.Cmp(resource.MustParse(tt.wantMemory)) != 0 {
+					t.Errorf("expected the memory to be shrunk to %v, got %v", tt.wantMemory, memory.String())
+				}
+			}
+		})
+	}
+}
@@ -86,7 +86,11 @@ func New(_ context.Context, rawArgs runtime.Object, fh framework.Handle) (framew
 		return nil, fmt.Errorf("got args of type %T, want *PreemptionTolerationArgs", args)
 	}
 
-	if err := validation.ValidateDefaultPreemptionArgs(field.NewPath(""), (*schedulerapisconfig.DefaultPreemptionArgs)(args)); err != nil {
+	defaultPreemptionArgs := &schedulerapisconfig.DefaultPreemptionArgs{
+		MinCandidateNodesPercentage: args.MinCandidateNodesPercentage,
+		MinCandidateNodesAbsolute:   args.MinCandidateNodesAbsolute,
+	}
+	if err := validation.ValidateDefaultPreemptionArgs(field.NewPath(""), defaultPreemptionArgs); err != nil {
 		return nil, err
 	}
 
@@ -117,6 +121,12 @@ func (pl *PreemptionToleration) PostFilter(ctx context.Context, state *framework
 	}
 
 	pl.curTime = pl.clock.Now()
+	if pl.args.PartialPreemption {
+		// resize the victims opting in when this is enough, and evict them otherwise
+		if result, status := pl.partiallyPreempt(ctx, state, pod, m); result != nil {
+			return result, status
+		}
+	}
 	return pe.Preempt(ctx, pod, m)
 }
 
@@ -168,6 +178,11 @@ func ExemptedFromPreemption(
 		return true, nil
 	}
 
+	// check it can tolerate the preemption in terms of protected window
+	if policy.ProtectedWindow != nil && policy.ProtectedWindow.Contains(now) {
+		return true, nil
+	}
+
 	// check it can tolerate the preemption in terms of toleration seconds
 	_, scheduledCondition := podutil.GetPodCondition(&victimCandidate.Status, v1.PodScheduled)
 	if scheduledCondition == nil || scheduledCondition.Status != v1.ConditionTrue {
@@ -26,6 +26,8 @@ const (
 	AnnotationKeyPrefix                     = "preemption-toleration.scheduling.x-k8s.io/"
 	AnnotationKeyMinimumPreemptablePriority = AnnotationKeyPrefix + "minimum-preemptable-priority"
 	AnnotationKeyTolerationSeconds          = AnnotationKeyPrefix + "toleration-seconds"
+	AnnotationKeyProtectedWindow            = AnnotationKeyPrefix + "protected-window"
+	AnnotationKeyProtectedWindowTimeZone    = AnnotationKeyPrefix + "protected-window-time-zone"
 )
 
 // Policy holds preemption toleration policy configuration.  Each property values are annotated in the target PriorityClass resource.
@@ -37,6 +39,8 @@ const (
 //	  annotation:
 //	    preemption-toleration.scheduling.x-k8s.io/minimum-preemptable-priority: "10000"
 //	    preemption-toleration.scheduling.x-k8s.io/toleration-seconds: "3600"
+//	    preemption-toleration.scheduling.x-k8s.io/protected-window: "* 9-17 * * 1-5"
+//	    preemption-toleration.scheduling.x-k8s.io/protected-window-time-zone: "Europe/Paris"
 type Policy struct {
 	// MinimumPreemptablePriority specifies the minimum priority value that can preempt this priority class.
 	// It defaults to the PriorityClass's priority value + 1 if not set, which means pods that have a higher priority value can preempt it.
@@ -50,6 +54,12 @@ type Policy struct {
 	// lower than MinimumPreemptablePriority won't be able to preempt it.
 	// This value affects scheduled pods only (no effect on nominated pods).
 	TolerationSeconds int64
+
+	// ProtectedWindow specifies when this priority class tolerates preemption by priorities lower than
+	// MinimumPreemptablePriority whatever TolerationSeconds, e.g. during business hours.
+	// It is evaluated in the time zone given by the protected-window-time-zone annotation, UTC if not set.
+	// Outside the window, TolerationSeconds applies. No window if not set.
+	ProtectedWindow *TimeWindow
 }
 
 func parsePreemptionTolerationPolicy(
@@ -79,5 +89,13 @@ func parsePreemptionTolerationPolicy(
 		policy.TolerationSeconds = tolerationSeconds
 	}
 
+	if protectedWindowStr, ok := pc.Annotations[AnnotationKeyProtectedWindow]; ok {
+		protectedWindow, err := ParseTimeWindow(protectedWindowStr, pc.Annotations[AnnotationKeyProtectedWindowTimeZone])
+		if err != nil {
+			return nil, err
+		}
+		policy.ProtectedWindow = protectedWindow
+	}
+
 	return policy, nil
 }
@@ -66,6 +66,14 @@ func TestParsePreemptionTolerationPolicyToleration(t *testing.T) {
 			wantErr: true,
 			errStr:  `strconv.ParseInt: parsing "a": invalid syntax`,
 		},
+		{
+			name: "PriorityClass with invalid ProtectedWindow does raise error",
+			priorityClass: makePriorityClass(1, map[string]string{
+				AnnotationKeyProtectedWindow: "* 9-17 * *",
+			}),
+			wantErr: true,
+			errStr:  `time window "* 9-17 * *" must have 5 fields, got 4`,
+		},
 		{
 			name: "PriorityClass with negative TolerationSeconds does not raise error (But this will be able to tolerate forever)",
 			priorityClass: makePriorityClass(1, map[string]string{
@@ -152,6 +152,9 @@ func TestExemptedFromPreemptionWithPolicyWhenPreemptorPriorityLowerThanMinimumPr
 	victimCandidatePriority := int32(100)
 	minimumPreemptablePriority := int32(200)
 	preemptor := makePod().Priority(minimumPreemptablePriority - 1).Obj()
+	// on a Monday
+	businessHours := time.Date(2024, time.June, 3, 10, 0, 0, 0, time.UTC)
+	night := time.Date(2024, time.June, 3, 21, 0, 0, 0, time.UTC)
 	for _, tt := range []testCase{
 		{
 			name: "when TolerationSeconds is 0, it should return false (no toleration)",
@@ -197,6 +200,54 @@ func TestExemptedFromPreemptionWithPolicyWhenPreemptorPriorityLowerThanMinimumPr
 			now:             now,
 			want:            true,
 		},
+		{
+			name: "when within the ProtectedWindow, it should return true even if the toleration expired",
+			victimCandidatePriorityClass: makePriorityClass(victimCandidatePriority, map[string]string{
+				AnnotationKeyMinimumPreemptablePriority: fmt.Sprintf("%d", minimumPreemptablePriority),
+				AnnotationKeyTolerationSeconds:          fmt.Sprintf("%d", 100),
+				AnnotationKeyProtectedWindow:            "* 9-17 * * 1-5",
+			}),
+			victimCandidate: makePod().PriorityClassName(testPriorityClassName).ScheduledAt(businessHours.Add(-101 * time.Second)).Priority(victimCandidatePriority).Obj(),
+			preemptor:       preemptor,
+			now:             businessHours,
+			want:            true,
+		},
+		{
+			name: "when outside the ProtectedWindow, it should return false if the toleration expired",
+			victimCandidatePriorityClass: makePriorityClass(victimCandidatePriority, map[string]string{
+				AnnotationKeyMinimumPreemptablePriority: fmt.Sprintf("%d", minimumPreemptablePriority),
+				AnnotationKeyTolerationSeconds:          fmt.Sprintf("%d", 100),
+				AnnotationKeyProtectedWindow:            "* 9-17 * * 1-5",
+			}),
+			victimCandidate: makePod().PriorityClassName(testPriorityClassName).ScheduledAt(night.Add(-101 * time.Second)).Priority(victimCandidatePriority).Obj(),
+			preemptor:       preemptor,
+			now:             night,
+			want:            false,
+		},
+		{
+			name: "when within the ProtectedWindow of another time zone, it should return true",
+			victimCandidatePriorityClass: makePriorityClass(victimCandidatePriority, map[string]string{
+				AnnotationKeyMinimumPreemptablePriority: fmt.Sprintf("%d", minimumPreemptablePriority),
+				AnnotationKeyProtectedWindow:            "* 9-17 * * 1-5",
+				AnnotationKeyProtectedWindowTimeZone:    "Asia/Tokyo",
+			}),
+			victimCandidate: makePod().PriorityClassName(testPriorityClassName).ScheduledAt(businessHours.Add(-10 * time.Hour)).Priority(victimCandidatePriority).Obj(),
+			preemptor:       preemptor,
+			now:             businessHours.Add(-9 * time.Hour), // 10:00 in Tokyo
+			want:            true,
+		},
+		{
+			name: "when outside the ProtectedWindow of another time zone, it should return false",
+			victimCandidatePriorityClass: makePriorityClass(victimCandidatePriority, map[string]string{
+				AnnotationKeyMinimumPreemptablePriority: fmt.Sprintf("%d", minimumPreemptablePriority),
+				AnnotationKeyProtectedWindow:            "* 9-17 * * 1-5",
+				AnnotationKeyProtectedWindowTimeZone:    "Asia/Tokyo",
+			}),
+			victimCandidate: makePod().PriorityClassName(testPriorityClassName).ScheduledAt(businessHours.Add(-time.Hour)).Priority(victimCandidatePriority).Obj(),
+			preemptor:       preemptor,
+			now:             businessHours, // 19:00 in Tokyo
+			want:            false,
+		},
 	} {
 		t.Run(tt.name, tt.run)
 	}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptiontoleration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeWindow is the set of the minutes matching a cron-like expression of 5 space-separated fields: minute (0-59),
+// hour (0-23), day of month (1-31), month (1-12) and day of week (0-7, 0 and 7 being Sunday). Each field is "*" or
+// a comma-separated list of values "a", ranges "a-b" and steps "*/n" or "a-b/n". Like cron, when both the day of
+// month and the day of week are restricted, a day matching either is in the window.
+// Example: "* 9-17 * * 1-5" is the business hours, from 9:00 to 17:59 on weekdays.
+type TimeWindow struct {
+	// Expression is the cron-like expression of the window.
+	Expression string
+	// Location is the time zone the expression is evaluated in.
+	Location *time.Location
+
+	minutes, hours, daysOfMonth, months, daysOfWeek uint64
+	anyDayOfMonth, anyDayOfWeek                     bool
+}
+
+// cronField is the range of the values of a field of the expression.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// locations caches the time zones loaded, shared by the policies of all the PriorityClasses.
+var locations sync.Map
+
+// ParseTimeWindow parses the cron-like expression of a window evaluated in the given time zone, UTC if empty.
+func ParseTimeWindow(expression, timeZone string) (*TimeWindow, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("time window %q must have %d fields, got %d", expression, len(cronFields), len(fields))
+	}
+	location, err := loadLocation(timeZone)
+	if err != nil {
+		return nil, err
+	}
+	w := &TimeWindow{Expression: expression, Location: location}
+	sets := []*uint64{&w.minutes, &w.hours, &w.daysOfMonth, &w.months, &w.daysOfWeek}
+	for i, field := range fields {
+		set, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("time window %q: %w", expression, err)
+		}
+		*sets[i] = set
+	}
+	// Sunday is both 0 and 7.
+	if w.daysOfWeek&(1<<7) != 0 {
+		w.daysOfWeek |= 1
+	}
+	w.anyDayOfMonth = fields[2] == "*"
+	w.anyDayOfWeek = fields[4] == "*"
+	return w, nil
+}
+
+// Contains returns whether the minute of the given time is in the window.
+func (w *TimeWindow) Contains(t time.Time) bool {
+	t = t.In(w.Location)
+	if !has(w.minutes, t.Minute()) || !has(w.hours, t.Hour()) || !has(w.months, int(t.Month())) {
+		return false
+	}
+	dayOfMonth, dayOfWeek := has(w.daysOfMonth, t.Day()), has(w.daysOfWeek, int(t.Weekday()))
+	if w.anyDayOfMonth || w.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
+
+// parseCronField returns the set of the values of a field of the expression.
+func parseCronField(field string, f cronField) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q of the %v", stepExpr, f.name)
+			}
+		}
+		first, last := f.min, f.max
+		if rangeExpr != "*" {
+			firstExpr, lastExpr, isRange := strings.Cut(rangeExpr, "-")
+			var err error
+			if first, err = parseCronValue(firstExpr, f); err != nil {
+				return 0, err
+			}
+			last = first
+			if isRange {
+				if last, err = parseCronValue(lastExpr, f); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// "a/n" is every n from a
+				last = f.max
+			}
+			if first > last {
+				return 0, fmt.Errorf("invalid range %q of the %v", rangeExpr, f.name)
+			}
+		}
+		for v := first; v <= last; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func parseCronValue(value string, f cronField) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %v %q, must be between %d and %d", f.name, value, f.min, f.max)
+	}
+	return v, nil
+}
+
+func loadLocation(timeZone string) (*time.Location, error) {
+	if timeZone == "" {
+		return time.UTC, nil
+	}
+	if location, ok := locations.Load(timeZone); ok {
+		return location.(*time.Location), nil
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(timeZone, location)
+	return location, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptiontoleration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeWindow(t *testing.T) {
+	// 2024-06-03 is a Monday
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2024, time.June, 3, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name       string
+		expression string
+		timeZone   string
+		in         []time.Time
+		out        []time.Time
+	}{
+		{
+			name:       "business hours",
+			expression: "* 9-17 * * 1-5",
+			in:         []time.Time{monday(9, 0), monday(17, 59), monday(12, 30).AddDate(0, 0, 4)},
+			out:        []time.Time{monday(8, 59), monday(18, 0), monday(12, 30).AddDate(0, 0, 5), monday(12, 30).AddDate(0, 0, 6)},
+		},
+		{
+			name:       "business hours in another time zone",
+			expression: "* 9-17 * * 1-5",
+			timeZone:   "America/New_York",
+			in:         []time.Time{monday(13, 0), monday(21, 59)},
+			out:        []time.Time{monday(9, 0), monday(22, 0)},
+		},
+		{
+			name:       "lists and steps",
+			expression: "0,30 */6 * * *",
+			in:         []time.Time{monday(0, 0), monday(6, 30), monday(18, 0)},
+			out:        []time.Time{monday(0, 15), monday(7, 0)},
+		},
+		{
+			name:       "sunday as 7",
+			expression: "* * * * 7",
+			in:         []time.Time{monday(10, 0).AddDate(0, 0, -1)},
+			out:        []time.Time{monday(10, 0)},
+		},
+		{
+			name:       "day of month or day of week",
+			expression: "* * 1 * 1",
+			in:         []time.Time{monday(10, 0), time.Date(2024, time.June, 1, 10, 0, 0, 0, time.UTC)},
+			out:        []time.Time{monday(10, 0).AddDate(0, 0, 1)},
+		},
+		{
+			name:       "months",
+			expression: "* * * 1-3,12 *",
+			in:         []time.Time{time.Date(2024, time.December, 24, 10, 0, 0, 0, time.UTC)},
+			out:        []time.Time{monday(10, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseTimeWindow(tt.expression, tt.timeZone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, at := range tt.in {
+				if !w.Contains(at) {
+					t.Errorf("expected %v in the window %q", at, tt.expression)
+				}
+			}
+			for _, at := range tt.out {
+				if w.Contains(at) {
+					t.Errorf("expected %v out of the window %q", at, tt.expression)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTimeWindowErrors(t *testing.T) {
+	for _, tt := range []struct {
+		expression string
+		timeZone   string
+	}{
+		{expression: "* 9-17 * *"},
+		{expression: "* 24 * * *"},
+		{expression: "* 17-9 * * *"},
+		{expression: "*/0 * * * *"},
+		{expression: "* * 0 * *"},
+		{expression: "* * * * mon"},
+		{expression: "* * * * *", timeZone: "Nowhere/Town"},
+	} {
+		if _, err := ParseTimeWindow(tt.expression, tt.timeZone); err == nil {
+			t.Errorf("expected an error for the window %q in %q", tt.expression, tt.timeZone)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuelength
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+const (
+	// labels of the samples of the backlog query
+	namespaceLabel     = "namespace"
+	consumerGroupLabel = "consumer_group"
+)
+
+// BacklogCollector : get the backlog of the consumer groups, e.g. the lag of the Kafka consumer groups
+// or the length of the queues reported by the KEDA scalers, from a Prometheus server
+type BacklogCollector struct {
+	prometheus *util.PrometheusClient
+	query      string
+
+	// backlog by namespace and name of the consumer groups
+	backlogs map[types.NamespacedName]float64
+	// largest backlog of the consumer groups
+	maxBacklog float64
+	// for safe access to backlogs and maxBacklog
+	mu sync.RWMutex
+}
+
+// NewBacklogCollector : create a collector of the backlogs returned by the query
+func NewBacklogCollector(address string, query string) (*BacklogCollector, error) {
+	if address == "" {
+		return nil, fmt.Errorf("prometheusAddress is required")
+	}
+	if query == "" {
+		return nil, fmt.Errorf("backlogQuery is required")
+	}
+	return &BacklogCollector{
+		prometheus: util.NewPrometheusClient(address),
+		query:      query,
+		backlogs:   make(map[types.NamespacedName]float64),
+	}, nil
+}
+
+// Run : refresh the backlogs every interval until the context is done
+func (c *BacklogCollector) Run(ctx context.Context, interval time.Duration) {
+	logger := klog.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.UpdateBacklogs(ctx); err != nil {
+			logger.Error(err, "Unable to update the backlogs of the consumer groups")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// UpdateBacklogs : query Prometheus and replace the backlogs with the result.
+// The backlogs are kept as they are if the query fails.
+func (c *BacklogCollector) UpdateBacklogs(ctx context.Context) error {
+	samples, err := c.prometheus.QueryVector(ctx, c.query)
+	if err != nil {
+		return err
+	}
+
+	backlogs := make(map[types.NamespacedName]float64, len(samples))
+	maxBacklog := 0.0
+	for _, sample := range samples {
+		group := types.NamespacedName{Namespace: sample.Labels[namespaceLabel], Name: sample.Labels[consumerGroupLabel]}
+		if group.Namespace == "" || group.Name == "" || sample.Value < 0 {
+			continue
+		}
+		backlogs[group] = sample.Value
+		maxBacklog = max(maxBacklog, sample.Value)
+	}
+
+	c.mu.Lock()
+	c.backlogs = backlogs
+	c.maxBacklog = maxBacklog
+	c.mu.Unlock()
+	return nil
+}
+
+// Backlog : the backlog of the consumer group, and its share of the largest backlog, in [0, 1].
+// Both are zero for the consumer groups without backlog metrics.
+func (c *BacklogCollector) Backlog(group types.NamespacedName) (float64, float64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	backlog := c.backlogs[group]
+	if backlog == 0 || c.maxBacklog == 0 {
+		return 0, 0
+	}
+	return backlog, backlog / c.maxBacklog
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuelength
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const testQuery = `sum by (namespace, consumer_group) (kafka_consumergroup_lag)`
+
+func newTestPrometheus(t *testing.T, response string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			t.Errorf("unexpected path %v", r.URL.Path)
+		}
+		if query := r.URL.Query().Get("query"); query != testQuery {
+			t.Errorf("unexpected query %v", query)
+		}
+		fmt.Fprint(w, response)
+	}))
+}
+
+func TestBacklogCollector(t *testing.T) {
+	server := newTestPrometheus(t, `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"namespace":"default","consumer_group":"orders"},"value":[1710000000.1,"1200"]},
+		{"metric":{"namespace":"default","consumer_group":"payments"},"value":[1710000000.1,"300"]},
+		{"metric":{"namespace":"default","consumer_group":"refunds"},"value":[1710000000.1,"NaN"]},
+		{"metric":{"consumer_group":"orders"},"value":[1710000000.1,"5000"]}]}}`)
+	defer server.Close()
+
+	c, err := NewBacklogCollector(server.URL+"/", testQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateBacklogs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := map[types.NamespacedName]float64{
+		{Namespace: "default", Name: "orders"}:   1200,
+		{Namespace: "default", Name: "payments"}: 300,
+	}
+	if !reflect.DeepEqual(c.backlogs, want) {
+		t.Errorf("expected the backlogs %v, got %v", want, c.backlogs)
+	}
+
+	tests := []struct {
+		name          string
+		group         types.NamespacedName
+		expected      float64
+		expectedShare float64
+	}{
+		{
+			name:          "largest backlog",
+			group:         types.NamespacedName{Namespace: "default", Name: "orders"},
+			expected:      1200,
+			expectedShare: 1,
+		},
+		{
+			name:          "smaller backlog",
+			group:         types.NamespacedName{Namespace: "default", Name: "payments"},
+			expected:      300,
+			expectedShare: 0.25,
+		},
+		{
+			name:  "consumer group without backlog metrics",
+			group: types.NamespacedName{Namespace: "other", Name: "orders"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backlog, share := c.Backlog(tt.group)
+			if backlog != tt.expected || share != tt.expectedShare {
+				t.Errorf("expected the backlog %v and share %v, got %v and %v", tt.expected, tt.expectedShare, backlog, share)
+			}
+		})
+	}
+}
+
+func TestBacklogCollectorQueryError(t *testing.T) {
+	server := newTestPrometheus(t, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+	defer server.Close()
+
+	c, err := NewBacklogCollector(server.URL, testQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := types.NamespacedName{Namespace: "default", Name: "orders"}
+	c.backlogs = map[types.NamespacedName]float64{group: 10}
+	c.maxBacklog = 10
+	if err := c.UpdateBacklogs(context.Background()); err == nil {
+		t.Errorf("expected an error")
+	}
+	if backlog, _ := c.Backlog(group); backlog != 10 {
+		t.Errorf("expected the backlogs to be kept, got %v", c.backlogs)
+	}
+}
+
+func TestNewBacklogCollector(t *testing.T) {
+	if _, err := NewBacklogCollector("", testQuery); err == nil {
+		t.Errorf("expected an error without address")
+	}
+	if _, err := NewBacklogCollector("http://prometheus:9090", ""); err == nil {
+		t.Errorf("expected an error without query")
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuelength
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// QueueLength is a plugin favoring the consumers of the event-driven workloads with the largest backlog,
+// e.g. the Deployments scaled by KEDA: their pods are scheduled first, on the nodes with the most headroom,
+// to drain the largest queues first under fan-out load.
+type QueueLength struct {
+	handle             framework.Handle
+	backlogs           *BacklogCollector
+	consumerGroupLabel string
+}
+
+var _ framework.QueueSortPlugin = &QueueLength{}
+var _ framework.ScorePlugin = &QueueLength{}
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "QueueLength"
+)
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (q *QueueLength) Name() string {
+	return Name
+}
+
+// New initializes a new plugin and returns it.
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.QueueLengthArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type QueueLengthArgs, got %T", obj)
+	}
+	if args.ConsumerGroupLabel == "" {
+		return nil, fmt.Errorf("consumerGroupLabel is required")
+	}
+	if args.MetricsUpdateIntervalSeconds <= 0 {
+		return nil, fmt.Errorf("metricsUpdateIntervalSeconds should be positive, got %d", args.MetricsUpdateIntervalSeconds)
+	}
+	backlogs, err := NewBacklogCollector(args.PrometheusAddress, args.BacklogQuery)
+	if err != nil {
+		return nil, err
+	}
+	go backlogs.Run(ctx, time.Duration(args.MetricsUpdateIntervalSeconds)*time.Second)
+
+	klog.FromContext(ctx).V(4).Info("QueueLength start", "prometheusAddress", args.PrometheusAddress, "consumerGroupLabel", args.ConsumerGroupLabel)
+	return &QueueLength{
+		handle:             handle,
+		backlogs:           backlogs,
+		consumerGroupLabel: args.ConsumerGroupLabel,
+	}, nil
+}
+
+// Less orders the pods by priority, then the pods of the consumer groups with the largest backlog first,
+// then by their timestamp. The backlogs are read when the pods are compared, so that the pods follow the
+// backlogs as they change.
+func (q *QueueLength) Less(podInfo1, podInfo2 *framework.QueuedPodInfo) bool {
+	prio1 := corev1helpers.PodPriority(podInfo1.Pod)
+	prio2 := corev1helpers.PodPriority(podInfo2.Pod)
+	if prio1 != prio2 {
+		return prio1 > prio2
+	}
+	backlog1, _ := q.backlog(podInfo1.Pod)
+	backlog2, _ := q.backlog(podInfo2.Pod)
+	if backlog1 != backlog2 {
+		return backlog1 > backlog2
+	}
+	return podInfo1.Timestamp.Before(podInfo2.Timestamp)
+}
+
+// Score favors the nodes with the most headroom left after placing a consumer, the more the larger the
+// backlog of its consumer group is compared to the largest backlog. All the nodes score equally for the
+// pods without backlog.
+func (q *QueueLength) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	_, share := q.backlog(pod)
+	if share == 0 {
+		return 0, nil
+	}
+	nodeInfo, err := q.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+	}
+	headroom := headroom(pod, nodeInfo)
+	klog.FromContext(ctx).V(6).Info("Headroom of the node for the consumer", "pod", klog.KObj(pod), "node", nodeName, "backlogShare", share, "headroom", headroom)
+	return int64(math.Round(share * headroom * float64(framework.MaxNodeScore))), nil
+}
+
+// ScoreExtensions returns nil as the scores are already within the node score range.
+func (q *QueueLength) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// backlog returns the backlog of the consumer group of the pod and its share of the largest backlog,
+// zero for the pods not labeled with a consumer group.
+func (q *QueueLength) backlog(pod *v1.Pod) (float64, float64) {
+	group, ok := pod.Labels[q.consumerGroupLabel]
+	if !ok || group == "" {
+		return 0, 0
+	}
+	return q.backlogs.Backlog(types.NamespacedName{Namespace: pod.Namespace, Name: group})
+}
+
+// headroom returns the lowest share of the node allocatable cpu and memory left after placing the pod.
+func headroom(pod *v1.Pod, nodeInfo *framework.NodeInfo) float64 {
+	request := framework.NewResource(resource.PodRequests(pod, resource.PodResourcesOptions{}))
+	share := 1.0
+	left := func(requested, allocatable int64) {
+		if allocatable > 0 {
+			share = min(share, float64(allocatable-requested)/float64(allocatable))
+		}
+	}
+	left(nodeInfo.Requested.MilliCPU+request.MilliCPU, nodeInfo.Allocatable.MilliCPU)
+	left(nodeInfo.Requested.Memory+request.Memory, nodeInfo.Allocatable.Memory)
+	return max(share, 0)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuelength
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+)
+
+const testConsumerGroupLabel = "scheduling.x-k8s.io/consumer-group"
+
+type fakeSharedLister struct {
+	tf.NodeInfoLister
+}
+
+func (f fakeSharedLister) NodeInfos() framework.NodeInfoLister {
+	return f.NodeInfoLister
+}
+
+func (f fakeSharedLister) StorageInfos() framework.StorageInfoLister {
+	return nil
+}
+
+// fakeHandle : a framework handle only providing the snapshot
+type fakeHandle struct {
+	framework.Handle
+	lister framework.SharedLister
+}
+
+func (f fakeHandle) SnapshotSharedLister() framework.SharedLister {
+	return f.lister
+}
+
+func newTestQueueLength(handle framework.Handle) *QueueLength {
+	backlogs := &BacklogCollector{
+		backlogs: map[types.NamespacedName]float64{
+			{Namespace: "default", Name: "orders"}:   1000,
+			{Namespace: "default", Name: "payments"}: 250,
+		},
+		maxBacklog: 1000,
+	}
+	return &QueueLength{handle: handle, backlogs: backlogs, consumerGroupLabel: testConsumerGroupLabel}
+}
+
+func makeConsumerPod(name string, priority int32, group string) *st.PodWrapper {
+	pod := st.MakePod().Namespace("default").Name(name).Priority(priority)
+	if group != "" {
+		pod = pod.Label(testConsumerGroupLabel, group)
+	}
+	return pod
+}
+
+func TestLess(t *testing.T) {
+	now := time.Now()
+	q := newTestQueueLength(nil)
+	queued := func(pod *v1.Pod, timestamp time.Time) *framework.QueuedPodInfo {
+		return &framework.QueuedPodInfo{PodInfo: &framework.PodInfo{Pod: pod}, Timestamp: timestamp}
+	}
+
+	tests := []struct {
+		name     string
+		p1       *framework.QueuedPodInfo
+		p2       *framework.QueuedPodInfo
+		expected bool
+	}{
+		{
+			name:     "higher priority first",
+			p1:       queued(makeConsumerPod("p1", 10, "").Obj(), now.Add(time.Second)),
+			p2:       queued(makeConsumerPod("p2", 0, "orders").Obj(), now),
+			expected: true,
+		},
+		{
+			name:     "largest backlog first",
+			p1:       queued(makeConsumerPod("p1", 0, "orders").Obj(), now.Add(time.Second)),
+			p2:       queued(makeConsumerPod("p2", 0, "payments").Obj(), now),
+			expected: true,
+		},
+		{
+			name:     "consumer with a backlog before the other pods",
+			p1:       queued(makeConsumerPod("p1", 0, "").Obj(), now),
+			p2:       queued(makeConsumerPod("p2", 0, "payments").Obj(), now.Add(time.Second)),
+			expected: false,
+		},
+		{
+			name:     "consumer group of another namespace",
+			p1:       queued(makeConsumerPod("p1", 0, "orders").Namespace("other").Obj(), now),
+			p2:       queued(makeConsumerPod("p2", 0, "").Obj(), now.Add(time.Second)),
+			expected: true,
+		},
+		{
+			name:     "same backlog, earlier first",
+			p1:       queued(makeConsumerPod("p1", 0, "orders").Obj(), now),
+			p2:       queued(makeConsumerPod("p2", 0, "orders").Obj(), now.Add(time.Second)),
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := q.Less(tt.p1, tt.p2); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	node := st.MakeNode().Name("node1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8", v1.ResourceMemory: "16Gi"}).Obj()
+	nodeInfo := framework.NewNodeInfo(
+		st.MakePod().Namespace("default").Name("running").Node("node1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2", v1.ResourceMemory: "4Gi"}).Obj(),
+	)
+	nodeInfo.SetNode(node)
+	q := newTestQueueLength(fakeHandle{lister: fakeSharedLister{tf.NodeInfoLister{nodeInfo}}})
+	request := map[v1.ResourceName]string{v1.ResourceCPU: "2"}
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected int64
+	}{
+		{
+			name:     "pod without consumer group",
+			pod:      makeConsumerPod("p", 0, "").Req(request).Obj(),
+			expected: 0,
+		},
+		{
+			name:     "consumer of the largest backlog",
+			pod:      makeConsumerPod("p", 0, "orders").Req(request).Obj(),
+			expected: 50,
+		},
+		{
+			name:     "consumer of a smaller backlog",
+			pod:      makeConsumerPod("p", 0, "payments").Req(request).Obj(),
+			expected: 13,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, status := q.Score(context.TODO(), nil, tt.pod, "node1")
+			if !status.IsSuccess() || score != tt.expected {
+				t.Errorf("expected score %v, got %v with status %v", tt.expected, score, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shadow
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config/scheme"
+	configv1 "github.com/amiraBenamer20/scheduler-plugins/apis/config/v1"
+)
+
+// decodeArgs : get the args of the shadowed plugin. The scheduler only decodes the args of the plugins whose name
+// matches their kind, so the args of a shadow are passed undecoded, or nil when not configured. They are decoded as
+// the args of the kind they set, or of the plugin name followed by "Args", defaulted then converted like the
+// scheduler does; plugins without args get them as passed.
+func decodeArgs(plugin string, obj runtime.Object) (runtime.Object, error) {
+	var raw []byte
+	switch t := obj.(type) {
+	case nil:
+	case *runtime.Unknown:
+		raw = t.Raw
+	default:
+		// already decoded, e.g. in tests
+		return obj, nil
+	}
+
+	kind := plugin + "Args"
+	if len(raw) > 0 {
+		var typeMeta metav1.TypeMeta
+		if err := yaml.Unmarshal(raw, &typeMeta); err != nil {
+			return nil, fmt.Errorf("invalid args of %v: %w", Name(plugin), err)
+		}
+		if typeMeta.Kind != "" {
+			kind = typeMeta.Kind
+		}
+	}
+	versioned, err := scheme.Scheme.New(configv1.SchemeGroupVersion.WithKind(kind))
+	if err != nil {
+		if runtime.IsNotRegisteredError(err) && len(raw) == 0 {
+			return obj, nil
+		}
+		return nil, fmt.Errorf("unable to decode the args of %v as %v: %w", Name(plugin), kind, err)
+	}
+	if len(raw) > 0 {
+		if err := yaml.UnmarshalStrict(raw, versioned); err != nil {
+			return nil, fmt.Errorf("invalid args of %v: %w", Name(plugin), err)
+		}
+	}
+	scheme.Scheme.Default(versioned)
+	args, err := scheme.Scheme.New(config.SchemeGroupVersion.WithKind(kind))
+	if err != nil {
+		return nil, err
+	}
+	if err := scheme.Scheme.Convert(versioned, args, nil); err != nil {
+		return nil, fmt.Errorf("unable to convert the args of %v: %w", Name(plugin), err)
+	}
+	return args, nil
+}
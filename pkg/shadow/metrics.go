@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shadow
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const metricsSubsystem = "shadow"
+
+var (
+	// FilterResults is the number of results of the Filter of the shadowed plugins, per node evaluated, or per pod
+	// when the plugin decides for all the nodes at PreFilter.
+	FilterResults = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "filter_results_total",
+			Help:           "Number of results of the Filter of the shadowed plugins by plugin and result: accepted, rejected, skipped or error.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"plugin", "result"})
+
+	// PlacementResults is the number of pods placed by the scheduler by the result of the shadowed plugins for their
+	// node.
+	PlacementResults = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "placement_results_total",
+			Help:           "Number of pods placed by plugin and result of the shadowed plugin for their node: preferred, accepted, rejected, skipped or error.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"plugin", "result"})
+
+	metricsList = []metrics.Registerable{
+		FilterResults,
+		PlacementResults,
+	}
+)
+
+var registerOnce sync.Once
+
+// registerMetrics registers the metrics of the shadow plugins in the legacy registry, exposed by the scheduler.
+func registerMetrics() {
+	registerOnce.Do(func() {
+		for _, metric := range metricsList {
+			legacyregistry.MustRegister(metric)
+		}
+	})
+}
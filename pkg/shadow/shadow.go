@@ -0,0 +1,392 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package shadow runs the Filter and Score plugins of this repository in shadow mode: their decisions are logged and
+exported as metrics, but not enforced, so that a plugin can be evaluated on production traffic before enabling it.
+*/
+package shadow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+)
+
+// Suffix is appended to the name of a plugin to get the name of its shadow.
+const Suffix = "Shadow"
+
+// Results of the shadowed plugin for a node, or for the node the pod is placed on.
+const (
+	// ResultAccepted means the plugin accepted the node, without preferring it when it scores the nodes.
+	ResultAccepted = "accepted"
+	// ResultRejected means the plugin rejected the node.
+	ResultRejected = "rejected"
+	// ResultPreferred means the plugin accepted the node and scored it the highest.
+	ResultPreferred = "preferred"
+	// ResultSkipped means the plugin skipped the pod.
+	ResultSkipped = "skipped"
+	// ResultError means the plugin failed with an error.
+	ResultError = "error"
+)
+
+// Shadow : run a plugin without enforcing its decisions. It accepts every node and scores them all zero, while it
+// records the nodes the plugin rejects and the scores the plugin gives, and reports at Reserve how the node the pod
+// is placed on fares with the plugin. Reserve, Unreserve and PostBind are forwarded to the plugin, so that it
+// accounts for the placements, their status being ignored; Permit and PreBind, which enforce, are not.
+type Shadow struct {
+	plugin framework.Plugin
+	name   string
+}
+
+var _ framework.PreFilterPlugin = &Shadow{}
+var _ framework.FilterPlugin = &Shadow{}
+var _ framework.PreScorePlugin = &Shadow{}
+var _ framework.ScorePlugin = &Shadow{}
+var _ framework.ReservePlugin = &Shadow{}
+var _ framework.PostBindPlugin = &Shadow{}
+
+// Name : get the name of the shadow of the plugin.
+func Name(plugin string) string {
+	return plugin + Suffix
+}
+
+// New : get the factory of the shadow of the plugin with the given name and factory. The shadow takes the args of
+// the plugin, and can't be enabled in a profile running the plugin itself, whose state it would share.
+func New(plugin string, factory frameworkruntime.PluginFactory) frameworkruntime.PluginFactory {
+	return func(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+		logger := klog.FromContext(ctx)
+		logger.V(4).Info("Creating new instance of the shadow plugin", "plugin", plugin)
+		registerMetrics()
+
+		args, err := decodeArgs(plugin, obj)
+		if err != nil {
+			return nil, err
+		}
+		p, err := factory(ctx, args, handle)
+		if err != nil {
+			return nil, err
+		}
+		_, filters := p.(framework.FilterPlugin)
+		_, scores := p.(framework.ScorePlugin)
+		if !filters && !scores {
+			return nil, fmt.Errorf("plugin %v is neither a Filter nor a Score plugin", plugin)
+		}
+		return &Shadow{plugin: p, name: plugin}, nil
+	}
+}
+
+// Name : returns the name of the plugin.
+func (s *Shadow) Name() string {
+	return Name(s.name)
+}
+
+// stateKey is the key in CycleState to the decisions of the shadowed plugin.
+func (s *Shadow) stateKey() framework.StateKey {
+	return framework.StateKey("PreFilter" + s.Name())
+}
+
+// decisions : the decisions of the shadowed plugin in a scheduling cycle
+type decisions struct {
+	mu sync.Mutex
+	// the result of the plugin for all the nodes, when it skipped the pod, rejected it in PreFilter or failed
+	filterResult string
+	// the reasons the plugin rejected the nodes, by node name
+	rejected map[string]string
+	// the plugin skipped the scoring of the pod or failed in PreScore
+	scoreResult string
+	// the scores of the plugin, by node name
+	scores map[string]int64
+	// the nodes the plugin scored the highest, once normalized
+	preferred []string
+}
+
+// Clone the decisions.
+func (d *decisions) Clone() framework.StateData {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c := &decisions{
+		filterResult: d.filterResult,
+		rejected:     make(map[string]string, len(d.rejected)),
+		scoreResult:  d.scoreResult,
+		scores:       make(map[string]int64, len(d.scores)),
+		preferred:    append([]string(nil), d.preferred...),
+	}
+	for node, reason := range d.rejected {
+		c.rejected[node] = reason
+	}
+	for node, score := range d.scores {
+		c.scores[node] = score
+	}
+	return c
+}
+
+func (s *Shadow) decisions(state *framework.CycleState) *decisions {
+	data, err := state.Read(s.stateKey())
+	if err != nil {
+		return nil
+	}
+	d, _ := data.(*decisions)
+	return d
+}
+
+// resultOf : get the result of a status of the shadowed plugin
+func resultOf(status *framework.Status) string {
+	switch {
+	case status.IsSuccess():
+		return ResultAccepted
+	case status.IsSkip():
+		return ResultSkipped
+	case status.IsRejected():
+		return ResultRejected
+	default:
+		return ResultError
+	}
+}
+
+// PreFilter : run the PreFilter of the plugin, without restricting the nodes.
+func (s *Shadow) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	d := &decisions{rejected: make(map[string]string), scores: make(map[string]int64)}
+	state.Write(s.stateKey(), d)
+	status := framework.NewStatus(framework.Success)
+	if p, ok := s.plugin.(framework.PreFilterPlugin); ok {
+		_, status = p.PreFilter(ctx, state, pod)
+	}
+	if _, ok := s.plugin.(framework.FilterPlugin); !ok {
+		// the state of the plugin is prepared for its Score, it decides nothing at Filter
+		d.filterResult = ResultSkipped
+		return nil, framework.NewStatus(framework.Skip)
+	}
+	if result := resultOf(status); result != ResultAccepted {
+		d.filterResult = result
+		FilterResults.WithLabelValues(s.name, result).Inc()
+		klog.FromContext(ctx).V(4).Info("Shadowed plugin decision", "plugin", s.name, "extensionPoint", "PreFilter",
+			"pod", klog.KObj(pod), "result", result, "status", status)
+		return nil, framework.NewStatus(framework.Skip)
+	}
+	return nil, nil
+}
+
+// PreFilterExtensions : forward the extensions of the plugin, if any.
+func (s *Shadow) PreFilterExtensions() framework.PreFilterExtensions {
+	return s
+}
+
+func (s *Shadow) preFilterExtensions() framework.PreFilterExtensions {
+	if p, ok := s.plugin.(framework.PreFilterPlugin); ok {
+		return p.PreFilterExtensions()
+	}
+	return nil
+}
+
+// AddPod : forward AddPod to the plugin, its status ignored.
+func (s *Shadow) AddPod(ctx context.Context, state *framework.CycleState, podToSchedule *v1.Pod, podInfoToAdd *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	if e := s.preFilterExtensions(); e != nil {
+		e.AddPod(ctx, state, podToSchedule, podInfoToAdd, nodeInfo)
+	}
+	return nil
+}
+
+// RemovePod : forward RemovePod to the plugin, its status ignored.
+func (s *Shadow) RemovePod(ctx context.Context, state *framework.CycleState, podToSchedule *v1.Pod, podInfoToRemove *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	if e := s.preFilterExtensions(); e != nil {
+		e.RemovePod(ctx, state, podToSchedule, podInfoToRemove, nodeInfo)
+	}
+	return nil
+}
+
+// Filter : run the Filter of the plugin, and accept the node.
+func (s *Shadow) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	p, ok := s.plugin.(framework.FilterPlugin)
+	d := s.decisions(state)
+	if !ok || d == nil {
+		return nil
+	}
+	status := p.Filter(ctx, state, pod, nodeInfo)
+	result := resultOf(status)
+	FilterResults.WithLabelValues(s.name, result).Inc()
+	if result == ResultAccepted {
+		return nil
+	}
+	klog.FromContext(ctx).V(5).Info("Shadowed plugin decision", "plugin", s.name, "extensionPoint", "Filter",
+		"pod", klog.KObj(pod), "node", klog.KObj(nodeInfo.Node()), "result", result, "status", status)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rejected[nodeInfo.Node().Name] = status.Message()
+	return nil
+}
+
+// PreScore : run the PreScore of the plugin.
+func (s *Shadow) PreScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodes []*framework.NodeInfo) *framework.Status {
+	d := s.decisions(state)
+	if d == nil {
+		return framework.NewStatus(framework.Skip)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := s.plugin.(framework.ScorePlugin); !ok {
+		d.scoreResult = ResultSkipped
+		return framework.NewStatus(framework.Skip)
+	}
+	p, ok := s.plugin.(framework.PreScorePlugin)
+	if !ok {
+		return nil
+	}
+	status := p.PreScore(ctx, state, pod, nodes)
+	if result := resultOf(status); result != ResultAccepted {
+		d.scoreResult = result
+		klog.FromContext(ctx).V(4).Info("Shadowed plugin decision", "plugin", s.name, "extensionPoint", "PreScore",
+			"pod", klog.KObj(pod), "result", result, "status", status)
+		return framework.NewStatus(framework.Skip)
+	}
+	return nil
+}
+
+// Score : run the Score of the plugin, and score the node zero.
+func (s *Shadow) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	p, ok := s.plugin.(framework.ScorePlugin)
+	d := s.decisions(state)
+	if !ok || d == nil {
+		return 0, nil
+	}
+	score, status := p.Score(ctx, state, pod, nodeName)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !status.IsSuccess() {
+		d.scoreResult = ResultError
+		return 0, nil
+	}
+	d.scores[nodeName] = score
+	return 0, nil
+}
+
+// ScoreExtensions : normalize the scores of the plugin to find the nodes it prefers.
+func (s *Shadow) ScoreExtensions() framework.ScoreExtensions {
+	return s
+}
+
+// NormalizeScore : normalize the scores of the plugin on the nodes it accepts, and keep the nodes it scored the
+// highest.
+func (s *Shadow) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	p, ok := s.plugin.(framework.ScorePlugin)
+	d := s.decisions(state)
+	if !ok || d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.scoreResult != "" || d.filterResult == ResultRejected {
+		return nil
+	}
+	// the plugin would only score the nodes it accepts
+	pluginScores := make(framework.NodeScoreList, 0, len(scores))
+	for _, score := range scores {
+		if _, ok := d.rejected[score.Name]; !ok {
+			pluginScores = append(pluginScores, framework.NodeScore{Name: score.Name, Score: d.scores[score.Name]})
+		}
+	}
+	if e := p.ScoreExtensions(); e != nil {
+		if status := e.NormalizeScore(ctx, state, pod, pluginScores); !status.IsSuccess() {
+			d.scoreResult = ResultError
+			return nil
+		}
+	}
+	d.preferred = preferredNodes(pluginScores)
+	return nil
+}
+
+// preferredNodes : get the names of the nodes with the highest score, sorted
+func preferredNodes(scores framework.NodeScoreList) []string {
+	var preferred []string
+	var highest int64
+	for _, score := range scores {
+		switch {
+		case len(preferred) == 0 || score.Score > highest:
+			preferred, highest = []string{score.Name}, score.Score
+		case score.Score == highest:
+			preferred = append(preferred, score.Name)
+		}
+	}
+	sort.Strings(preferred)
+	return preferred
+}
+
+// placement : get the result of the plugin for the node the pod is placed on, with the reason the plugin rejects the
+// node, or the nodes it prefers
+func (d *decisions) placement(nodeName string) (string, string, []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.filterResult == ResultRejected {
+		return ResultRejected, "", nil
+	}
+	if reason, ok := d.rejected[nodeName]; ok {
+		return ResultRejected, reason, nil
+	}
+	if d.filterResult != "" && d.scoreResult != "" {
+		// the plugin decided nothing for the pod
+		if d.filterResult == ResultError || d.scoreResult == ResultError {
+			return ResultError, "", nil
+		}
+		return ResultSkipped, "", nil
+	}
+	if d.scoreResult == "" {
+		if i := sort.SearchStrings(d.preferred, nodeName); i < len(d.preferred) && d.preferred[i] == nodeName {
+			return ResultPreferred, "", d.preferred
+		}
+	}
+	return ResultAccepted, "", d.preferred
+}
+
+// Reserve : report how the node the pod is placed on fares with the plugin, and forward Reserve to the plugin.
+func (s *Shadow) Reserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if d := s.decisions(state); d != nil {
+		result, reason, preferred := d.placement(nodeName)
+		PlacementResults.WithLabelValues(s.name, result).Inc()
+		logger := klog.FromContext(ctx)
+		if result == ResultRejected {
+			logger.V(2).Info("Pod placed on a node the shadowed plugin rejects", "plugin", s.name,
+				"pod", klog.KObj(pod), "node", nodeName, "reason", reason)
+		} else {
+			logger.V(4).Info("Pod placed", "plugin", s.name, "pod", klog.KObj(pod), "node", nodeName,
+				"result", result, "preferredNodes", preferred)
+		}
+	}
+	if p, ok := s.plugin.(framework.ReservePlugin); ok {
+		p.Reserve(ctx, state, pod, nodeName)
+	}
+	return nil
+}
+
+// Unreserve : forward Unreserve to the plugin.
+func (s *Shadow) Unreserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	if p, ok := s.plugin.(framework.ReservePlugin); ok {
+		p.Unreserve(ctx, state, pod, nodeName)
+	}
+}
+
+// PostBind : forward PostBind to the plugin.
+func (s *Shadow) PostBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	if p, ok := s.plugin.(framework.PostBindPlugin); ok {
+		p.PostBind(ctx, state, pod, nodeName)
+	}
+}
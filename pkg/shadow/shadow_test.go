@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shadow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	configv1 "github.com/amiraBenamer20/scheduler-plugins/apis/config/v1"
+)
+
+// fakePlugin rejects a node and scores the nodes as configured.
+type fakePlugin struct {
+	preFilterStatus *framework.Status
+	rejected        string
+	scores          map[string]int64
+	reserved        string
+}
+
+func (f *fakePlugin) Name() string { return "Fake" }
+
+func (f *fakePlugin) PreFilter(_ context.Context, _ *framework.CycleState, _ *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	return nil, f.preFilterStatus
+}
+
+func (f *fakePlugin) PreFilterExtensions() framework.PreFilterExtensions { return nil }
+
+func (f *fakePlugin) Filter(_ context.Context, _ *framework.CycleState, _ *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	if nodeInfo.Node().Name == f.rejected {
+		return framework.NewStatus(framework.Unschedulable, "rejected by Fake")
+	}
+	return nil
+}
+
+func (f *fakePlugin) Score(_ context.Context, _ *framework.CycleState, _ *v1.Pod, nodeName string) (int64, *framework.Status) {
+	return f.scores[nodeName], nil
+}
+
+func (f *fakePlugin) ScoreExtensions() framework.ScoreExtensions { return nil }
+
+func (f *fakePlugin) Reserve(_ context.Context, _ *framework.CycleState, _ *v1.Pod, nodeName string) *framework.Status {
+	f.reserved = nodeName
+	return framework.NewStatus(framework.Unschedulable, "not enforced")
+}
+
+func (f *fakePlugin) Unreserve(_ context.Context, _ *framework.CycleState, _ *v1.Pod, _ string) {}
+
+// noopPlugin is neither a Filter nor a Score plugin.
+type noopPlugin struct{}
+
+func (noopPlugin) Name() string { return "Noop" }
+
+func TestShadow(t *testing.T) {
+	nodes := []string{"node-a", "node-b", "node-c"}
+	tests := []struct {
+		name            string
+		preFilterStatus *framework.Status
+		placedOn        string
+		wantPreferred   []string
+		wantFilter      map[string]int
+		wantPlacement   string
+	}{
+		{
+			name:          "placed on the preferred node",
+			placedOn:      "node-c",
+			wantPreferred: []string{"node-c"},
+			wantFilter:    map[string]int{ResultAccepted: 2, ResultRejected: 1},
+			wantPlacement: ResultPreferred,
+		},
+		{
+			name:          "placed on an accepted node",
+			placedOn:      "node-a",
+			wantPreferred: []string{"node-c"},
+			wantFilter:    map[string]int{ResultAccepted: 2, ResultRejected: 1},
+			wantPlacement: ResultAccepted,
+		},
+		{
+			name:          "placed on a rejected node",
+			placedOn:      "node-b",
+			wantPreferred: []string{"node-c"},
+			wantFilter:    map[string]int{ResultAccepted: 2, ResultRejected: 1},
+			wantPlacement: ResultRejected,
+		},
+		{
+			name:            "pod rejected at PreFilter",
+			preFilterStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "rejected by Fake"),
+			placedOn:        "node-a",
+			wantFilter:      map[string]int{ResultRejected: 1},
+			wantPlacement:   ResultRejected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			FilterResults.Reset()
+			PlacementResults.Reset()
+			ctx := context.Background()
+			fake := &fakePlugin{
+				preFilterStatus: tt.preFilterStatus,
+				rejected:        "node-b",
+				scores:          map[string]int64{"node-a": 10, "node-b": 90, "node-c": 50},
+			}
+			p, err := New("Fake", func(_ context.Context, _ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+				return fake, nil
+			})(ctx, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := p.(*Shadow)
+			if s.Name() != "FakeShadow" {
+				t.Errorf("unexpected name %v", s.Name())
+			}
+
+			state := framework.NewCycleState()
+			pod := st.MakePod().Name("p").Obj()
+			var nodeInfos []*framework.NodeInfo
+			feasible := framework.NodeScoreList{}
+			result, status := s.PreFilter(ctx, state, pod)
+			if result != nil {
+				t.Errorf("unexpected PreFilter result %v", result)
+			}
+			for _, name := range nodes {
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(st.MakeNode().Name(name).Obj())
+				nodeInfos = append(nodeInfos, nodeInfo)
+				if status.IsSkip() {
+					continue
+				}
+				if status := s.Filter(ctx, state, pod, nodeInfo); !status.IsSuccess() {
+					t.Errorf("unexpected Filter status %v on %v", status, name)
+				}
+			}
+			if status := s.PreScore(ctx, state, pod, nodeInfos); !status.IsSuccess() {
+				t.Fatalf("unexpected PreScore status %v", status)
+			}
+			// the scheduler scores the nodes feasible without the plugin, all of them here
+			for _, name := range nodes {
+				score, status := s.Score(ctx, state, pod, name)
+				if score != 0 || !status.IsSuccess() {
+					t.Errorf("unexpected score %v, %v on %v", score, status, name)
+				}
+				feasible = append(feasible, framework.NodeScore{Name: name})
+			}
+			if status := s.ScoreExtensions().NormalizeScore(ctx, state, pod, feasible); !status.IsSuccess() {
+				t.Fatalf("unexpected NormalizeScore status %v", status)
+			}
+			if status := s.Reserve(ctx, state, pod, tt.placedOn); !status.IsSuccess() {
+				t.Errorf("unexpected Reserve status %v", status)
+			}
+			if fake.reserved != tt.placedOn {
+				t.Errorf("expected Reserve forwarded for %v, got %q", tt.placedOn, fake.reserved)
+			}
+
+			if got := s.decisions(state).preferred; !reflect.DeepEqual(got, tt.wantPreferred) {
+				t.Errorf("expected the preferred nodes %v, got %v", tt.wantPreferred, got)
+			}
+			for _, result := range []string{ResultAccepted, ResultRejected, ResultSkipped, ResultError} {
+				got, err := testutil.GetCounterMetricValue(FilterResults.WithLabelValues("Fake", result))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if int(got) != tt.wantFilter[result] {
+					t.Errorf("expected %v filter results %v, got %v", tt.wantFilter[result], result, got)
+				}
+			}
+			got, err := testutil.GetCounterMetricValue(PlacementResults.WithLabelValues("Fake", tt.wantPlacement))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != 1 {
+				t.Errorf("expected a placement result %v, got %v", tt.wantPlacement, got)
+			}
+		})
+	}
+}
+
+func TestNewRejectsOtherPlugins(t *testing.T) {
+	_, err := New("Noop", func(_ context.Context, _ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+		return noopPlugin{}, nil
+	})(context.Background(), nil, nil)
+	if err == nil {
+		t.Errorf("expected an error for a plugin neither Filter nor Score")
+	}
+}
+
+func TestDecodeArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugin  string
+		obj     runtime.Object
+		want    runtime.Object
+		wantErr bool
+	}{
+		{
+			name:   "plugin without args",
+			plugin: "Fake",
+		},
+		{
+			name:   "args not configured are defaulted",
+			plugin: "DeadlineAware",
+			want:   &config.DeadlineAwareArgs{UrgencyThresholdSeconds: configv1.DefaultUrgencyThresholdSeconds},
+		},
+		{
+			name:   "args of the kind they set",
+			plugin: "Fake",
+			obj:    &runtime.Unknown{Raw: []byte(`{"kind":"DeadlineAwareArgs","urgencyThresholdSeconds":5}`)},
+			want:   &config.DeadlineAwareArgs{UrgencyThresholdSeconds: 5},
+		},
+		{
+			name:   "decoded args",
+			plugin: "DeadlineAware",
+			obj:    &config.DeadlineAwareArgs{UrgencyThresholdSeconds: 7},
+			want:   &config.DeadlineAwareArgs{UrgencyThresholdSeconds: 7},
+		},
+		{
+			name:    "unknown field",
+			plugin:  "DeadlineAware",
+			obj:     &runtime.Unknown{Raw: []byte(`{"urgencyTresholdSeconds":5}`)},
+			wantErr: true,
+		},
+		{
+			name:    "args of an unknown kind",
+			plugin:  "Fake",
+			obj:     &runtime.Unknown{Raw: []byte(`{"kind":"FakeArgs"}`)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeArgs(tt.plugin, tt.obj)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected the args %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
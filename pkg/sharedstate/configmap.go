@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharedstate
+
+import (
+	"context"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+)
+
+// ConfigMapStore : values shared by the replicas of the scheduler, saved by key in the data of a ConfigMap.
+// Several plugins may share the ConfigMap with different keys.
+type ConfigMapStore struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewConfigMapStore : create a store of the values saved in the given ConfigMap, created with the first value
+func NewConfigMapStore(client kubernetes.Interface, namespace, name string) *ConfigMapStore {
+	return &ConfigMapStore{client: client, namespace: namespace, name: name}
+}
+
+// Get : get the value saved with the key, and whether there is one
+func (s *ConfigMapStore) Get(ctx context.Context, key string) (string, bool, error) {
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	value, ok := cm.Data[key]
+	return value, ok, nil
+}
+
+// Set : save the value with the key, creating the ConfigMap if needed. The values of the other keys are kept,
+// even when written concurrently by other plugins.
+func (s *ConfigMapStore) Set(ctx context.Context, key, value string) error {
+	configMaps := s.client.CoreV1().ConfigMaps(s.namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, s.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: s.namespace, Name: s.name},
+				Data:       map[string]string{key: value},
+			}
+			_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// created concurrently, retry as a conflicting update
+				return apierrors.NewConflict(v1.Resource("configmaps"), s.name, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		cm = cm.DeepCopy()
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[key] = value
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// Watch : call onChange with the value saved with the key, once it is saved and every time it changes, until the
+// context is done. Watch returns immediately; a value removed from the ConfigMap, or the ConfigMap deleted, is not
+// reported, the last value being kept by the replicas.
+func (s *ConfigMapStore) Watch(ctx context.Context, key string, onChange func(value string)) {
+	factory := informers.NewSharedInformerFactoryWithOptions(s.client, 0,
+		informers.WithNamespace(s.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", s.name).String()
+		}))
+
+	var mu sync.Mutex
+	var last *string
+	changed := func(obj interface{}) {
+		cm, ok := obj.(*v1.ConfigMap)
+		if !ok || cm.Name != s.name {
+			return
+		}
+		value, ok := cm.Data[key]
+		if !ok {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if last != nil && *last == value {
+			return
+		}
+		last = &value
+		onChange(value)
+	}
+	factory.Core().V1().ConfigMaps().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    changed,
+		UpdateFunc: func(_, newObj interface{}) { changed(newObj) },
+	})
+	factory.Start(ctx.Done())
+}
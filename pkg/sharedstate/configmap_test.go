@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharedstate
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigMapStore(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	store := NewConfigMapStore(client, "kube-system", "shared-state")
+
+	if _, ok, err := store.Get(ctx, "a"); err != nil || ok {
+		t.Fatalf("expected no value before the ConfigMap is created, got %v, %v", ok, err)
+	}
+	if err := store.Set(ctx, "a", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(ctx, "b", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(ctx, "a", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok, err := store.Get(ctx, "a"); err != nil || !ok || value != "3" {
+		t.Errorf("expected the value 3, got %q, %v, %v", value, ok, err)
+	}
+	if _, ok, err := store.Get(ctx, "c"); err != nil || ok {
+		t.Errorf("expected no value for a key never set, got %v, %v", ok, err)
+	}
+
+	cm, err := client.CoreV1().ConfigMaps("kube-system").Get(ctx, "shared-state", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"a": "3", "b": "2"}; !reflect.DeepEqual(cm.Data, want) {
+		t.Errorf("expected the data %v, got %v", want, cm.Data)
+	}
+}
+
+func TestConfigMapStoreWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := fake.NewSimpleClientset()
+	leader := NewConfigMapStore(client, "kube-system", "shared-state")
+	follower := NewConfigMapStore(client, "kube-system", "shared-state")
+
+	values := make(chan string, 10)
+	follower.Watch(ctx, "a", func(value string) { values <- value })
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case got := <-values:
+			if got != want {
+				t.Errorf("expected the value %q, got %q", want, got)
+			}
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatalf("expected the value %q, got none", want)
+		}
+	}
+
+	if err := leader.Set(ctx, "a", "1"); err != nil {
+		t.Fatal(err)
+	}
+	expect("1")
+	// neither other keys nor the same value are reported
+	if err := leader.Set(ctx, "b", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := leader.Set(ctx, "a", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := leader.Set(ctx, "a", "3"); err != nil {
+		t.Fatal(err)
+	}
+	expect("3")
+	select {
+	case got := <-values:
+		t.Errorf("unexpected value %q", got)
+	default:
+	}
+}
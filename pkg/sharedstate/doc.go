@@ -0,0 +1,36 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package sharedstate keeps the internal caches of the plugins consistent across the replicas of a highly available
+scheduler.
+
+With leader election, a single replica, the leader, schedules pods while the others stand by with their informers
+running, and any of them may become the leader at any time. The plugins keep two kinds of state with different
+cross-replica semantics:
+
+  - State derived from the cluster, e.g. the pods bound to each node. Every replica rebuilds it from its informers,
+    so it must only depend on the watched objects and never on when, or by which replica, an event was observed.
+    Times in particular are taken from the objects, e.g. BoundTime, rather than from the local clock, so that a
+    replica starting, or becoming the leader, has the same cache as the replica it takes over from.
+  - State learned by the leader while scheduling, e.g. the coefficients tuned by the Trimaran plugins. It cannot be
+    rebuilt from the cluster, so the leader saves it in a ConfigMapStore and the standby replicas follow it with
+    Watch, keeping the learned values when they take over. Only the leader learns, so the last write wins.
+
+Caches of values fetched from outside of the cluster, e.g. the network costs measured by a metrics provider, are
+refreshed by every replica on its own and converge within their refresh interval.
+*/
+package sharedstate
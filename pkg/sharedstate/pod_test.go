@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharedstate
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBoundTime(t *testing.T) {
+	bound := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		conditions []v1.PodCondition
+		want       time.Time
+		wantOK     bool
+	}{
+		{
+			name: "bound pod",
+			conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(bound.Add(time.Minute))},
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(bound)},
+			},
+			want:   bound,
+			wantOK: true,
+		},
+		{
+			name: "unschedulable pod",
+			conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionFalse, LastTransitionTime: metav1.NewTime(bound)},
+			},
+		},
+		{
+			name: "pod without PodScheduled condition",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &v1.Pod{Status: v1.PodStatus{Conditions: tt.conditions}}
+			got, ok := BoundTime(pod)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("expected %v, %v, got %v, %v", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// PlacementStatus is the outcome of the scheduling of a pod.
+type PlacementStatus string
+
+const (
+	// Scheduled pods were placed on a node.
+	Scheduled PlacementStatus = "Scheduled"
+	// Unschedulable pods did not fit on any node. Preemption is not simulated.
+	Unschedulable PlacementStatus = "Unschedulable"
+	// Rejected pods fit on a node, but were rejected by a Reserve or Permit plugin.
+	Rejected PlacementStatus = "Rejected"
+	// Failed pods hit an error of a plugin.
+	Failed PlacementStatus = "Failed"
+)
+
+// NodeScore is the score of a feasible node for a pod.
+type NodeScore struct {
+	Node string `json:"node"`
+	// Total is the sum of the scores of the plugins.
+	Total int64 `json:"total"`
+	// Plugins are the normalized scores of the plugins, multiplied by their weight.
+	Plugins map[string]int64 `json:"plugins,omitempty"`
+}
+
+// FilteredNode is a node filtered out for a pod.
+type FilteredNode struct {
+	Node string `json:"node"`
+	// Plugin is the Filter plugin which rejected the node.
+	Plugin  string   `json:"plugin,omitempty"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Placement is the scheduling decision taken for a pod.
+type Placement struct {
+	// Pod is the namespaced name of the pod.
+	Pod    string          `json:"pod"`
+	Status PlacementStatus `json:"status"`
+	// Node is the node the pod was placed on, if scheduled.
+	Node string `json:"node,omitempty"`
+	// Message explains why the pod was not scheduled.
+	Message string `json:"message,omitempty"`
+	// Scores are the scores of the feasible nodes, sorted by decreasing total.
+	Scores []NodeScore `json:"scores,omitempty"`
+	// Filtered are the nodes filtered out, sorted by name.
+	Filtered []FilteredNode `json:"filtered,omitempty"`
+}
+
+// Report is the list of the placements of a replay, in the order of the pod arrivals.
+type Report struct {
+	Placements []Placement `json:"placements"`
+}
+
+// PlacementChange is a pod placed differently in two reports. The baseline or current placement
+// is nil if the pod is missing in the corresponding report.
+type PlacementChange struct {
+	Pod      string     `json:"pod"`
+	Baseline *Placement `json:"baseline,omitempty"`
+	Current  *Placement `json:"current,omitempty"`
+}
+
+// Diff returns the pods whose status or node differ from the baseline report, e.g. the report of a replay
+// before a change of the scoring. The scores alone are not compared.
+func (r *Report) Diff(baseline *Report) []PlacementChange {
+	baselinePlacements := make(map[string]*Placement, len(baseline.Placements))
+	for i := range baseline.Placements {
+		baselinePlacements[baseline.Placements[i].Pod] = &baseline.Placements[i]
+	}
+	var changes []PlacementChange
+	seen := make(map[string]bool, len(r.Placements))
+	for i := range r.Placements {
+		current := &r.Placements[i]
+		seen[current.Pod] = true
+		before := baselinePlacements[current.Pod]
+		if before != nil && before.Status == current.Status && before.Node == current.Node {
+			continue
+		}
+		changes = append(changes, PlacementChange{Pod: current.Pod, Baseline: before, Current: current})
+	}
+	for i := range baseline.Placements {
+		before := &baseline.Placements[i]
+		if !seen[before.Pod] {
+			changes = append(changes, PlacementChange{Pod: before.Pod, Baseline: before})
+		}
+	}
+	return changes
+}
+
+// WriteTo writes the report as indented JSON.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// WriteText writes the report for humans: the outcome of each pod, followed by the scores of the feasible
+// nodes per plugin and the reasons of the nodes filtered out.
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	for _, placement := range r.Placements {
+		fmt.Fprintf(&b, "%v: %v", placement.Pod, placement.Status)
+		if placement.Node != "" {
+			fmt.Fprintf(&b, " on %v", placement.Node)
+		}
+		if placement.Message != "" {
+			fmt.Fprintf(&b, ": %v", placement.Message)
+		}
+		b.WriteString("\n")
+		for _, score := range placement.Scores {
+			fmt.Fprintf(&b, "  %v: score %d", score.Node, score.Total)
+			plugins := make([]string, 0, len(score.Plugins))
+			for plugin := range score.Plugins {
+				plugins = append(plugins, plugin)
+			}
+			sort.Strings(plugins)
+			for i, plugin := range plugins {
+				sep := ", "
+				if i == 0 {
+					sep = " ("
+				}
+				fmt.Fprintf(&b, "%v%v=%d", sep, plugin, score.Plugins[plugin])
+			}
+			if len(plugins) > 0 {
+				b.WriteString(")")
+			}
+			b.WriteString("\n")
+		}
+		for _, filtered := range placement.Filtered {
+			fmt.Fprintf(&b, "  %v: filtered by %v: %v\n", filtered.Node, filtered.Plugin, strings.Join(filtered.Reasons, ", "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// LoadReport reads a report written by WriteTo.
+func LoadReport(r io.Reader) (*Report, error) {
+	report := &Report{}
+	if err := json.NewDecoder(r).Decode(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
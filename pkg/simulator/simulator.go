@@ -0,0 +1,454 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+	schedconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/config/scheme"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/cacheisolation"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/capacityscheduling"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/coscheduling"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/criticalreserve"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/dataresidency"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/deadlineaware"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/drf"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/hostantiaffinity"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/networkcost"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/network-cost-aware/topologicalcnsort"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/networkoverhead"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/servicemeshlatency"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/networkaware/topologicalsort"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/nfvaware"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesources"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/noderesourcetopology"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/podstate"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/preemptiontoleration"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/qos"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/sysched"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran/loadvariationriskbalancing"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran/lowriskovercommitment"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran/peaks"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran/targetloadpacking"
+)
+
+// bindTimeout is how long a binding is waited for to be observed by the informers.
+const bindTimeout = 10 * time.Second
+
+// Config of a replay.
+type Config struct {
+	// Profile is the scheduler profile replayed, see LoadProfile and DefaultProfile.
+	Profile *schedconfig.KubeSchedulerProfile
+	// Registry holds the plugins the profile can enable. Defaults to Registry().
+	Registry frameworkruntime.Registry
+	// KubeConfig is the API server of the plugins reading their custom resources with their own client,
+	// e.g. an envtest API server with the CRDs of this repository installed. The objects of the snapshot
+	// which are not nodes nor pods are created there before the replay. Optional if no such plugin is enabled.
+	KubeConfig *restclient.Config
+	// ObjectsInCluster tells that the objects of the snapshot already exist in the KubeConfig cluster,
+	// e.g. for a snapshot of this cluster from LoadClusterSnapshot, and are not created.
+	ObjectsInCluster bool
+}
+
+// Registry returns the in-tree plugins and the plugins of this repository,
+// as registered by the scheduler of this repository.
+func Registry() frameworkruntime.Registry {
+	registry := plugins.NewInTreeRegistry()
+	outOfTree := frameworkruntime.Registry{
+		cacheisolation.Name:             cacheisolation.New,
+		capacityscheduling.Name:         capacityscheduling.New,
+		coscheduling.Name:               coscheduling.New,
+		criticalreserve.Name:            criticalreserve.New,
+		dataresidency.Name:              dataresidency.New,
+		deadlineaware.Name:              deadlineaware.New,
+		drf.Name:                        drf.New,
+		hostantiaffinity.Name:           hostantiaffinity.New,
+		loadvariationriskbalancing.Name: loadvariationriskbalancing.New,
+		networkoverhead.Name:            networkoverhead.New,
+		servicemeshlatency.Name:         servicemeshlatency.New,
+		topologicalsort.Name:            topologicalsort.New,
+		networkcost.Name:                networkcost.New,
+		topologicalcnsort.Name:          topologicalcnsort.New,
+		nfvaware.Name:                   nfvaware.New,
+		noderesources.AllocatableName:   noderesources.NewAllocatable,
+		noderesourcetopology.Name:       noderesourcetopology.New,
+		preemptiontoleration.Name:       preemptiontoleration.New,
+		targetloadpacking.Name:          targetloadpacking.New,
+		lowriskovercommitment.Name:      lowriskovercommitment.New,
+		sysched.Name:                    sysched.New,
+		peaks.Name:                      peaks.New,
+		podstate.Name:                   podstate.New,
+		qos.Name:                        qos.New,
+	}
+	// The names are unique.
+	_ = registry.Merge(outOfTree)
+	return registry
+}
+
+// LoadProfile returns the profile of the scheduler named schedulerName, or the first profile if empty,
+// from a KubeSchedulerConfiguration, defaulted like the configuration of the scheduler.
+func LoadProfile(data []byte, schedulerName string) (*schedconfig.KubeSchedulerProfile, error) {
+	obj, gvk, err := scheme.Codecs.UniversalDecoder().Decode(data, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decoding the scheduler configuration: %w", err)
+	}
+	cfg, ok := obj.(*schedconfig.KubeSchedulerConfiguration)
+	if !ok {
+		return nil, fmt.Errorf("expected a KubeSchedulerConfiguration, got %v", gvk)
+	}
+	for i := range cfg.Profiles {
+		if schedulerName == "" || cfg.Profiles[i].SchedulerName == schedulerName {
+			return &cfg.Profiles[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no profile for scheduler %q", schedulerName)
+}
+
+// DefaultProfile returns the profile of the default scheduler, with the default plugins.
+func DefaultProfile() (*schedconfig.KubeSchedulerProfile, error) {
+	return LoadProfile([]byte("apiVersion: kubescheduler.config.k8s.io/v1\nkind: KubeSchedulerConfiguration\n"), "")
+}
+
+// simulator replays the pods through the framework of the profile.
+type simulator struct {
+	framework framework.Framework
+	clientSet clientset.Interface
+	podLister corelisters.PodLister
+
+	// lock protects the snapshot and the report, modified by the pods waiting in Permit.
+	lock     sync.Mutex
+	snapshot *clusterSnapshot
+	report   *Report
+	// waiting tracks the pods waiting in Permit.
+	waiting sync.WaitGroup
+}
+
+// Run replays the pod arrivals, in order, on the snapshot of a cluster through the plugins of the profile,
+// out-of-cluster, and reports where each pod was placed and the scores of the nodes.
+//
+// The arrivals are created in the simulated cluster up front, as pending pods, and scheduled one at a time.
+// Like the scheduler, a pod is assumed on its node before Reserve, so that the next pods see it. The pods
+// waiting in Permit stay assumed until allowed or rejected, the ones still waiting at the end of the replay
+// are rejected. Preemption, PreBind and Bind are not simulated: the scheduled pods are bound by the simulator.
+func Run(ctx context.Context, cfg Config, snapshot *Snapshot, pods []*v1.Pod) (*Report, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	logger := klog.FromContext(ctx)
+
+	if cfg.Profile == nil {
+		return nil, fmt.Errorf("missing scheduler profile")
+	}
+	registry := cfg.Registry
+	if registry == nil {
+		registry = Registry()
+	}
+	if cfg.KubeConfig != nil && !cfg.ObjectsInCluster {
+		if err := createObjects(ctx, cfg.KubeConfig, snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	arrivals := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		pod = pod.DeepCopy()
+		pod.Spec.NodeName = ""
+		if pod.Namespace == "" {
+			pod.Namespace = metav1.NamespaceDefault
+		}
+		if pod.UID == "" {
+			pod.UID = types.UID(pod.Namespace + "/" + pod.Name)
+		}
+		if pod.Spec.SchedulerName == "" {
+			pod.Spec.SchedulerName = cfg.Profile.SchedulerName
+		}
+		arrivals = append(arrivals, pod)
+	}
+	clientSet := fake.NewSimpleClientset()
+	for _, node := range snapshot.Nodes {
+		if _, err := clientSet.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{}); err != nil {
+			return nil, fmt.Errorf("creating node %q: %w", node.Name, err)
+		}
+	}
+	for _, pod := range append(append([]*v1.Pod{}, snapshot.Pods...), arrivals...) {
+		if _, err := clientSet.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			return nil, fmt.Errorf("creating pod %q: %w", klog.KObj(pod), err)
+		}
+	}
+
+	s := &simulator{
+		clientSet: clientSet,
+		snapshot:  newClusterSnapshot(snapshot.Nodes, snapshot.Pods),
+		report:    &Report{Placements: make([]Placement, len(arrivals))},
+	}
+	informerFactory := informers.NewSharedInformerFactory(clientSet, 0)
+	s.podLister = informerFactory.Core().V1().Pods().Lister()
+	fwk, err := frameworkruntime.NewFramework(ctx, registry, cfg.Profile,
+		frameworkruntime.WithClientSet(clientSet),
+		frameworkruntime.WithKubeConfig(cfg.KubeConfig),
+		frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithSnapshotSharedLister(s.snapshot),
+		frameworkruntime.WithPodNominator(&nominator{}),
+		frameworkruntime.WithEventRecorder(&events.FakeRecorder{}),
+		frameworkruntime.WithWaitingPods(frameworkruntime.NewWaitingPodsMap()),
+		frameworkruntime.WithLogger(logger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("initializing the framework of profile %q: %w", cfg.Profile.SchedulerName, err)
+	}
+	defer fwk.Close()
+	s.framework = fwk
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+
+	for i, pod := range arrivals {
+		if err := ctx.Err(); err != nil {
+			s.rejectWaitingPods()
+			return nil, err
+		}
+		s.schedule(ctx, i, pod)
+	}
+	s.rejectWaitingPods()
+	return s.report, nil
+}
+
+// schedule runs the scheduling cycle of the i-th pod and records its placement.
+func (s *simulator) schedule(ctx context.Context, i int, pod *v1.Pod) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	logger := klog.FromContext(ctx)
+
+	placement := &s.report.Placements[i]
+	placement.Pod = klog.KObj(pod).String()
+	state := framework.NewCycleState()
+	state.SetRecordPluginMetrics(false)
+
+	nodes, diagnosis, status := s.findFeasibleNodes(ctx, state, pod)
+	if !status.IsSuccess() {
+		placement.Status, placement.Message = statusOf(status), status.Message()
+		return
+	}
+	for nodeName, status := range diagnosis.NodeToStatusMap {
+		placement.Filtered = append(placement.Filtered, FilteredNode{Node: nodeName, Plugin: status.Plugin(), Reasons: status.Reasons()})
+	}
+	sort.Slice(placement.Filtered, func(i, j int) bool {
+		return placement.Filtered[i].Node < placement.Filtered[j].Node
+	})
+	if len(nodes) == 0 {
+		fitErr := &framework.FitError{Pod: pod, NumAllNodes: len(s.snapshot.nodeInfoList), Diagnosis: diagnosis}
+		placement.Status, placement.Message = Unschedulable, fitErr.Error()
+		return
+	}
+
+	// The nodes are scored even if only one is feasible, to report the scores.
+	if status := s.framework.RunPreScorePlugins(ctx, state, pod, nodes); !status.IsSuccess() {
+		placement.Status, placement.Message = statusOf(status), status.Message()
+		return
+	}
+	nodeScores, status := s.framework.RunScorePlugins(ctx, state, pod, nodes)
+	if !status.IsSuccess() {
+		placement.Status, placement.Message = statusOf(status), status.Message()
+		return
+	}
+	for _, nodeScore := range nodeScores {
+		score := NodeScore{Node: nodeScore.Name, Total: nodeScore.TotalScore, Plugins: make(map[string]int64, len(nodeScore.Scores))}
+		for _, pluginScore := range nodeScore.Scores {
+			score.Plugins[pluginScore.Name] = pluginScore.Score
+		}
+		placement.Scores = append(placement.Scores, score)
+	}
+	if len(placement.Scores) == 0 {
+		// No score plugin.
+		for _, nodeInfo := range nodes {
+			placement.Scores = append(placement.Scores, NodeScore{Node: nodeInfo.Node().Name})
+		}
+	}
+	// Deterministic tie-breaking, unlike the scheduler picking one of the best nodes at random.
+	sort.SliceStable(placement.Scores, func(i, j int) bool {
+		if placement.Scores[i].Total != placement.Scores[j].Total {
+			return placement.Scores[i].Total > placement.Scores[j].Total
+		}
+		return placement.Scores[i].Node < placement.Scores[j].Node
+	})
+	nodeName := placement.Scores[0].Node
+
+	assumedPod := pod.DeepCopy()
+	assumedPod.Spec.NodeName = nodeName
+	s.snapshot.addPod(assumedPod)
+	if status := s.framework.RunReservePluginsReserve(ctx, state, assumedPod, nodeName); !status.IsSuccess() {
+		s.forget(ctx, state, assumedPod, placement, status)
+		return
+	}
+	status = s.framework.RunPermitPlugins(ctx, state, assumedPod, nodeName)
+	if status.IsWait() {
+		logger.V(4).Info("Pod waiting in Permit", "pod", klog.KObj(pod), "node", nodeName)
+		placement.Node = nodeName
+		s.waiting.Add(1)
+		go func() {
+			defer s.waiting.Done()
+			status := s.framework.WaitOnPermit(ctx, assumedPod)
+			s.lock.Lock()
+			defer s.lock.Unlock()
+			if !status.IsSuccess() {
+				s.forget(ctx, state, assumedPod, placement, status)
+				return
+			}
+			s.bind(ctx, state, assumedPod, placement)
+		}()
+		return
+	}
+	if !status.IsSuccess() {
+		s.forget(ctx, state, assumedPod, placement, status)
+		return
+	}
+	s.bind(ctx, state, assumedPod, placement)
+}
+
+// rejectWaitingPods rejects the pods still waiting in Permit and waits for them to be forgotten.
+func (s *simulator) rejectWaitingPods() {
+	s.framework.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		waitingPod.Reject("", "simulation ended")
+	})
+	s.waiting.Wait()
+}
+
+// findFeasibleNodes runs the PreFilter and Filter plugins. The rejections are recorded in the diagnosis.
+func (s *simulator) findFeasibleNodes(ctx context.Context, state *framework.CycleState, pod *v1.Pod) ([]*framework.NodeInfo, framework.Diagnosis, *framework.Status) {
+	diagnosis := framework.Diagnosis{
+		NodeToStatusMap:      make(framework.NodeToStatusMap),
+		UnschedulablePlugins: sets.New[string](),
+	}
+	preFilterResult, status, _ := s.framework.RunPreFilterPlugins(ctx, state, pod)
+	if !status.IsSuccess() {
+		return nil, diagnosis, status
+	}
+	var feasible []*framework.NodeInfo
+	for _, nodeInfo := range s.snapshot.nodeInfoList {
+		if !preFilterResult.AllNodes() && !preFilterResult.NodeNames.Has(nodeInfo.Node().Name) {
+			continue
+		}
+		status := s.framework.RunFilterPluginsWithNominatedPods(ctx, state, pod, nodeInfo)
+		if status.IsSuccess() {
+			feasible = append(feasible, nodeInfo)
+			continue
+		}
+		if !status.IsRejected() {
+			return nil, diagnosis, status
+		}
+		diagnosis.NodeToStatusMap[nodeInfo.Node().Name] = status
+		diagnosis.UnschedulablePlugins.Insert(status.Plugin())
+	}
+	return feasible, diagnosis, nil
+}
+
+// forget unreserves the assumed pod rejected by a Reserve or Permit plugin and removes it from its node.
+func (s *simulator) forget(ctx context.Context, state *framework.CycleState, assumedPod *v1.Pod, placement *Placement, status *framework.Status) {
+	s.framework.RunReservePluginsUnreserve(ctx, state, assumedPod, assumedPod.Spec.NodeName)
+	if err := s.snapshot.removePod(klog.FromContext(ctx), assumedPod); err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to forget the assumed pod", "pod", klog.KObj(assumedPod))
+	}
+	placement.Node = ""
+	placement.Status, placement.Message = Rejected, status.Message()
+	if !status.IsRejected() {
+		placement.Status = Failed
+	}
+}
+
+// bind binds the assumed pod to its node in the simulated cluster, once observed by the informers, and runs
+// the PostBind plugins.
+func (s *simulator) bind(ctx context.Context, state *framework.CycleState, assumedPod *v1.Pod, placement *Placement) {
+	placement.Node = assumedPod.Spec.NodeName
+	if _, err := s.clientSet.CoreV1().Pods(assumedPod.Namespace).Update(ctx, assumedPod, metav1.UpdateOptions{}); err != nil {
+		placement.Status, placement.Message = Failed, fmt.Sprintf("binding pod: %v", err)
+		return
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, bindTimeout, true, func(context.Context) (bool, error) {
+		pod, err := s.podLister.Pods(assumedPod.Namespace).Get(assumedPod.Name)
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil && pod.Spec.NodeName == assumedPod.Spec.NodeName, err
+	}); err != nil {
+		placement.Status, placement.Message = Failed, fmt.Sprintf("waiting for the binding: %v", err)
+		return
+	}
+	placement.Status = Scheduled
+	s.framework.RunPostBindPlugins(ctx, state, assumedPod, assumedPod.Spec.NodeName)
+}
+
+// statusOf returns the placement status of a pod whose scheduling stopped at a non-successful status.
+func statusOf(status *framework.Status) PlacementStatus {
+	if status.IsRejected() {
+		return Unschedulable
+	}
+	return Failed
+}
+
+// createObjects creates the objects of the snapshot in the cluster of the kubeconfig.
+// The objects which already exist are left untouched.
+func createObjects(ctx context.Context, kubeConfig *restclient.Config, snapshot *Snapshot) error {
+	c, err := client.New(kubeConfig, client.Options{})
+	if err != nil {
+		return err
+	}
+	for _, obj := range snapshot.Objects {
+		obj = obj.DeepCopy()
+		obj.SetResourceVersion("")
+		obj.SetUID("")
+		if err := c.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("creating %v %q: %w", obj.GetKind(), klog.KObj(obj), err)
+		}
+	}
+	return nil
+}
+
+// nominator is a framework.PodNominator without nominated pods, preemption not being simulated.
+type nominator struct{}
+
+var _ framework.PodNominator = &nominator{}
+
+func (n *nominator) AddNominatedPod(klog.Logger, *framework.PodInfo, *framework.NominatingInfo) {}
+
+func (n *nominator) DeleteNominatedPodIfExists(*v1.Pod) {}
+
+func (n *nominator) UpdateNominatedPod(klog.Logger, *v1.Pod, *framework.PodInfo) {}
+
+func (n *nominator) NominatedPodsForNode(string) []*framework.PodInfo {
+	return nil
+}
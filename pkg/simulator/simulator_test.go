@@ -0,0 +1,393 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	schedconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+const snapshotYAML = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Node
+  metadata:
+    name: n1
+  status:
+    allocatable: {cpu: "4", memory: 8Gi, pods: "110"}
+- apiVersion: v1
+  kind: Node
+  metadata:
+    name: n2
+  status:
+    allocatable: {cpu: "4", memory: 8Gi, pods: "110"}
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: running
+  namespace: ns
+spec:
+  nodeName: n2
+  containers:
+  - name: c
+    resources:
+      requests: {cpu: "3", memory: 6Gi}
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pending
+  namespace: ns
+spec:
+  containers:
+  - name: c
+    resources:
+      requests: {cpu: "1", memory: 1Gi}
+---
+apiVersion: scheduling.x-k8s.io/v1alpha1
+kind: ElasticQuota
+metadata:
+  name: quota
+  namespace: ns
+spec:
+  max: {cpu: "8"}
+`
+
+func TestLoadSnapshot(t *testing.T) {
+	snapshot, pending, err := LoadSnapshot(strings.NewReader(snapshotYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Nodes) != 2 || snapshot.Nodes[0].Name != "n1" || snapshot.Nodes[1].Name != "n2" {
+		t.Errorf("expected the nodes n1 and n2, got %v", snapshot.Nodes)
+	}
+	if len(snapshot.Pods) != 1 || snapshot.Pods[0].Name != "running" {
+		t.Errorf("expected the bound pod running, got %v", snapshot.Pods)
+	}
+	if len(pending) != 1 || pending[0].Name != "pending" {
+		t.Errorf("expected the pending pod to be replayed, got %v", pending)
+	}
+	if len(snapshot.Objects) != 1 || snapshot.Objects[0].GetKind() != "ElasticQuota" {
+		t.Errorf("expected the ElasticQuota, got %v", snapshot.Objects)
+	}
+
+	if _, err := LoadPods(strings.NewReader(snapshotYAML)); err == nil {
+		t.Error("expected an error loading nodes as pod arrivals")
+	}
+}
+
+func TestRun(t *testing.T) {
+	snapshot, pending, err := LoadSnapshot(strings.NewReader(snapshotYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := DefaultProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pods := append(pending,
+		st.MakePod().Name("big").Namespace("ns").Req(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+		st.MakePod().Name("small").Namespace("ns").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj(),
+	)
+
+	report, err := Run(context.Background(), Config{Profile: profile}, snapshot, pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Placements) != 3 {
+		t.Fatalf("expected 3 placements, got %v", report.Placements)
+	}
+	pendingPlacement := report.Placements[0]
+	if pendingPlacement.Pod != "ns/pending" || pendingPlacement.Status != Scheduled || pendingPlacement.Node != "n1" {
+		t.Errorf("expected ns/pending scheduled on the empty node n1, got %+v", pendingPlacement)
+	}
+	if len(pendingPlacement.Scores) != 2 || pendingPlacement.Scores[0].Node != "n1" ||
+		pendingPlacement.Scores[0].Total <= pendingPlacement.Scores[1].Total {
+		t.Errorf("expected n1 to score higher than n2, got %+v", pendingPlacement.Scores)
+	}
+	if _, ok := pendingPlacement.Scores[0].Plugins["NodeResourcesFit"]; !ok {
+		t.Errorf("expected the score of NodeResourcesFit to be reported, got %v", pendingPlacement.Scores[0].Plugins)
+	}
+	if big := report.Placements[1]; big.Status != Unschedulable || !strings.Contains(big.Message, "0/2 nodes are available") {
+		t.Errorf("expected ns/big to be unschedulable, got %+v", big)
+	}
+	wantFiltered := []FilteredNode{
+		{Node: "n1", Plugin: "NodeResourcesFit", Reasons: []string{"Insufficient cpu"}},
+		{Node: "n2", Plugin: "NodeResourcesFit", Reasons: []string{"Insufficient cpu"}},
+	}
+	if big := report.Placements[1]; !reflect.DeepEqual(big.Filtered, wantFiltered) {
+		t.Errorf("expected the nodes filtered out for ns/big %+v, got %+v", wantFiltered, big.Filtered)
+	}
+	// n1 runs ns/pending, the placement of which is seen by the next pods.
+	if small := report.Placements[2]; small.Status != Scheduled || small.Node != "n1" {
+		t.Errorf("expected ns/small scheduled on n1, got %+v", small)
+	}
+}
+
+func TestRunProfile(t *testing.T) {
+	profile, err := LoadProfile([]byte(`
+apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: default-scheduler
+- schedulerName: packing
+  plugins:
+    multiPoint:
+      enabled:
+      - name: NodeResourcesAllocatable
+    score:
+      disabled:
+      - name: "*"
+      enabled:
+      - name: NodeResourcesAllocatable
+  pluginConfig:
+  - name: NodeResourcesAllocatable
+    args:
+      mode: Most
+      resources:
+      - name: cpu
+        weight: 1
+`), "packing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, pending, err := LoadSnapshot(strings.NewReader(snapshotYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Run(context.Background(), Config{Profile: profile}, snapshot, pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// n1 and n2 have the same allocatable CPU: tie broken by node name.
+	placement := report.Placements[0]
+	if placement.Status != Scheduled || placement.Node != "n1" {
+		t.Errorf("expected ns/pending scheduled on n1, got %+v", placement)
+	}
+	if len(placement.Scores) == 0 || len(placement.Scores[0].Plugins) != 1 {
+		t.Fatalf("expected only NodeResourcesAllocatable to score, got %+v", placement.Scores)
+	}
+	if _, ok := placement.Scores[0].Plugins["NodeResourcesAllocatable"]; !ok {
+		t.Errorf("expected only NodeResourcesAllocatable to score, got %+v", placement.Scores)
+	}
+
+	if _, err := LoadProfile([]byte("apiVersion: kubescheduler.config.k8s.io/v1\nkind: KubeSchedulerConfiguration\n"), "packing"); err == nil {
+		t.Error("expected an error for a missing profile")
+	}
+}
+
+const quorumName = "Quorum"
+
+// quorum is a Permit plugin letting the pods with the quorum label wait until two of them with the same value are waiting.
+type quorum struct {
+	handle framework.Handle
+}
+
+func (q *quorum) Name() string {
+	return quorumName
+}
+
+func (q *quorum) Permit(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (*framework.Status, time.Duration) {
+	group := pod.Labels["quorum"]
+	if group == "" {
+		return nil, 0
+	}
+	var waiting []framework.WaitingPod
+	q.handle.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		if waitingPod.GetPod().Labels["quorum"] == group {
+			waiting = append(waiting, waitingPod)
+		}
+	})
+	if len(waiting) == 0 {
+		return framework.NewStatus(framework.Wait), time.Minute
+	}
+	for _, waitingPod := range waiting {
+		waitingPod.Allow(quorumName)
+	}
+	return nil, 0
+}
+
+func TestRunWaitingPods(t *testing.T) {
+	snapshot, _, err := LoadSnapshot(strings.NewReader(snapshotYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := DefaultProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile.Plugins.Permit.Enabled = append(profile.Plugins.Permit.Enabled, schedconfig.Plugin{Name: quorumName})
+	registry := Registry()
+	registry[quorumName] = func(_ context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+		return &quorum{handle: handle}, nil
+	}
+	pods := []*v1.Pod{
+		st.MakePod().Name("a-1").Namespace("ns").Label("quorum", "a").Obj(),
+		st.MakePod().Name("b-1").Namespace("ns").Label("quorum", "b").Obj(),
+		st.MakePod().Name("a-2").Namespace("ns").Label("quorum", "a").Obj(),
+	}
+
+	report, err := Run(context.Background(), Config{Profile: profile, Registry: registry}, snapshot, pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []PlacementStatus{Scheduled, Rejected, Scheduled} {
+		if got := report.Placements[i]; got.Status != want {
+			t.Errorf("expected %v to be %v, got %+v", got.Pod, want, got)
+		}
+	}
+	if got := report.Placements[1]; got.Node != "" || !strings.Contains(got.Message, "simulation ended") {
+		t.Errorf("expected ns/b-1 to be rejected at the end of the simulation, got %+v", got)
+	}
+}
+
+func TestReportDiff(t *testing.T) {
+	baseline := &Report{Placements: []Placement{
+		{Pod: "ns/a", Status: Scheduled, Node: "n1"},
+		{Pod: "ns/b", Status: Scheduled, Node: "n1"},
+		{Pod: "ns/c", Status: Unschedulable},
+		{Pod: "ns/d", Status: Scheduled, Node: "n2"},
+	}}
+	current := &Report{Placements: []Placement{
+		{Pod: "ns/a", Status: Scheduled, Node: "n1", Scores: []NodeScore{{Node: "n1", Total: 10}}},
+		{Pod: "ns/b", Status: Scheduled, Node: "n2"},
+		{Pod: "ns/c", Status: Scheduled, Node: "n1"},
+		{Pod: "ns/e", Status: Unschedulable},
+	}}
+
+	var buf bytes.Buffer
+	if _, err := current.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadReport(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, current) {
+		t.Fatalf("expected the report to be loaded back, got %+v", loaded)
+	}
+
+	changes := loaded.Diff(baseline)
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.Pod)
+	}
+	want := []string{"ns/b", "ns/c", "ns/e", "ns/d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the changes of %v, got %v", want, got)
+	}
+	if changes[2].Baseline != nil || changes[3].Current != nil {
+		t.Errorf("expected the missing placements to be nil, got %+v", changes)
+	}
+}
+
+func TestReportWriteText(t *testing.T) {
+	report := &Report{Placements: []Placement{
+		{
+			Pod: "ns/a", Status: Scheduled, Node: "n1",
+			Scores: []NodeScore{
+				{Node: "n1", Total: 150, Plugins: map[string]int64{"NodeResourcesFit": 50, "NetworkCost": 100}},
+				{Node: "n2", Total: 20, Plugins: map[string]int64{"NodeResourcesFit": 20, "NetworkCost": 0}},
+			},
+			Filtered: []FilteredNode{{Node: "n3", Plugin: "NetworkCost", Reasons: []string{"Node n3 does not meet several network requirements"}}},
+		},
+		{Pod: "ns/b", Status: Unschedulable, Message: "0/3 nodes are available"},
+	}}
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `ns/a: Scheduled on n1
+  n1: score 150 (NetworkCost=100, NodeResourcesFit=50)
+  n2: score 20 (NetworkCost=0, NodeResourcesFit=20)
+  n3: filtered by NetworkCost: Node n3 does not meet several network requirements
+ns/b: Unschedulable: 0/3 nodes are available
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected text report:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestListObjects(t *testing.T) {
+	nodes := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	podGroups := schema.GroupVersionResource{Group: "scheduling.x-k8s.io", Version: "v1alpha1", Resource: "podgroups"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "nodes", Verbs: metav1.Verbs{"list"}},
+			{Name: "pods", Namespaced: true, Verbs: metav1.Verbs{"list"}},
+		}},
+		{GroupVersion: "scheduling.x-k8s.io/v1alpha1", APIResources: []metav1.APIResource{
+			{Name: "podgroups", Namespaced: true, Verbs: metav1.Verbs{"list"}},
+			{Name: "podgroups/status", Namespaced: true, Verbs: metav1.Verbs{"get"}},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Namespaced: true, Verbs: metav1.Verbs{"list"}},
+		}},
+	}}}
+	object := func(apiVersion, kind, namespace, name string, created time.Time) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetCreationTimestamp(metav1.NewTime(created))
+		return obj
+	}
+	now := time.Now().Truncate(time.Second)
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{nodes: "NodeList", pods: "PodList", podGroups: "PodGroupList", deployments: "DeploymentList"},
+		object("v1", "Node", "", "n1", now),
+		object("v1", "Pod", "ns", "late", now),
+		object("v1", "Pod", "ns", "early", now.Add(-time.Minute)),
+		object("scheduling.x-k8s.io/v1alpha1", "PodGroup", "ns", "gang", now),
+		object("apps/v1", "Deployment", "ns", "web", now),
+	)
+
+	objects, err := listObjects(context.Background(), discoveryClient, dynamicClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, obj := range objects {
+		got = append(got, obj.GetKind()+"/"+obj.GetName())
+	}
+	want := []string{"Node/n1", "Pod/early", "Pod/late", "PodGroup/gang"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the objects %v, got %v", want, got)
+	}
+}
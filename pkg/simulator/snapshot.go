@@ -0,0 +1,332 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/diktyo-io/appgroup-api/pkg/apis/appgroup"
+	"github.com/diktyo-io/networktopology-api/pkg/apis/networktopology"
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling"
+)
+
+// Snapshot is a recorded state of a cluster: its nodes, the pods bound to them,
+// and the other objects read by the plugins, e.g. their custom resources.
+type Snapshot struct {
+	Nodes   []*v1.Node
+	Pods    []*v1.Pod
+	Objects []*unstructured.Unstructured
+}
+
+// LoadSnapshot reads a snapshot from YAML or JSON documents of Kubernetes objects, e.g. the output of
+// `kubectl get nodes,pods,elasticquotas -A -o yaml`, lists being flattened. It also returns the pods not
+// bound to a node yet, in order, which are to be replayed.
+func LoadSnapshot(r io.Reader) (*Snapshot, []*v1.Pod, error) {
+	objects, err := decodeObjects(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newSnapshot(objects)
+}
+
+// LoadClusterSnapshot records a snapshot of a live cluster: its nodes, its pods, and the custom resources of
+// the API groups read by the plugins of this repository, e.g. the PodGroups, AppGroups and NetworkTopologies.
+// It also returns the pods not bound to a node yet, in the order of their creation, which are to be replayed.
+func LoadClusterSnapshot(ctx context.Context, kubeConfig *restclient.Config) (*Snapshot, []*v1.Pod, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(kubeConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	objects, err := listObjects(ctx, discoveryClient, dynamicClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshot, pending, err := newSnapshot(objects)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].CreationTimestamp.Before(&pending[j].CreationTimestamp)
+	})
+	return snapshot, pending, nil
+}
+
+// snapshotGroups are the API groups of the custom resources recorded by LoadClusterSnapshot.
+var snapshotGroups = sets.New(
+	scheduling.GroupName,
+	appgroup.GroupName,
+	networktopology.GroupName,
+	topologyv1alpha2.SchemeGroupVersion.Group,
+)
+
+// listObjects lists the nodes, the pods and the objects of the preferred version of the snapshot groups.
+func listObjects(ctx context.Context, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface) ([]*unstructured.Unstructured, error) {
+	resources := []schema.GroupVersionResource{
+		v1.SchemeGroupVersion.WithResource("nodes"),
+		v1.SchemeGroupVersion.WithResource("pods"),
+	}
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("discovering the API groups: %w", err)
+	}
+	sort.Slice(groups.Groups, func(i, j int) bool {
+		return groups.Groups[i].Name < groups.Groups[j].Name
+	})
+	for _, group := range groups.Groups {
+		if !snapshotGroups.Has(group.Name) {
+			continue
+		}
+		groupVersion := group.PreferredVersion.GroupVersion
+		resourceList, err := discoveryClient.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			return nil, fmt.Errorf("discovering the resources of %v: %w", groupVersion, err)
+		}
+		gv, err := schema.ParseGroupVersion(groupVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resourceList.APIResources {
+			// the subresources are part of their objects
+			if strings.Contains(resource.Name, "/") || !slices.Contains(resource.Verbs, "list") {
+				continue
+			}
+			resources = append(resources, gv.WithResource(resource.Name))
+		}
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, resource := range resources {
+		list, err := dynamicClient.Resource(resource).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("listing %v: %w", resource, err)
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	}
+	return objects, nil
+}
+
+// newSnapshot sorts the objects into a snapshot, and returns the pods not bound to a node yet.
+func newSnapshot(objects []*unstructured.Unstructured) (*Snapshot, []*v1.Pod, error) {
+	snapshot := &Snapshot{}
+	var pending []*v1.Pod
+	for _, obj := range objects {
+		switch obj.GroupVersionKind() {
+		case v1.SchemeGroupVersion.WithKind("Node"):
+			node := &v1.Node{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, node); err != nil {
+				return nil, nil, fmt.Errorf("decoding node %q: %w", obj.GetName(), err)
+			}
+			snapshot.Nodes = append(snapshot.Nodes, node)
+		case v1.SchemeGroupVersion.WithKind("Pod"):
+			pod := &v1.Pod{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pod); err != nil {
+				return nil, nil, fmt.Errorf("decoding pod %q: %w", klog.KObj(obj), err)
+			}
+			if pod.Spec.NodeName == "" {
+				pending = append(pending, pod)
+			} else if pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed {
+				snapshot.Pods = append(snapshot.Pods, pod)
+			}
+		default:
+			snapshot.Objects = append(snapshot.Objects, obj)
+		}
+	}
+	return snapshot, pending, nil
+}
+
+// LoadPods reads a stream of pod arrivals from YAML or JSON documents of pods, in order.
+// The node names of the pods are ignored.
+func LoadPods(r io.Reader) ([]*v1.Pod, error) {
+	objects, err := decodeObjects(r)
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]*v1.Pod, 0, len(objects))
+	for _, obj := range objects {
+		if obj.GroupVersionKind() != v1.SchemeGroupVersion.WithKind("Pod") {
+			return nil, fmt.Errorf("expected pods, got %v %q", obj.GetKind(), klog.KObj(obj))
+		}
+		pod := &v1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pod); err != nil {
+			return nil, fmt.Errorf("decoding pod %q: %w", klog.KObj(obj), err)
+		}
+		pod.Spec.NodeName = ""
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// decodeObjects returns the objects of the YAML or JSON documents, flattening the lists.
+func decodeObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	var objects []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, fmt.Errorf("decoding objects: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			// empty document
+			continue
+		}
+		if !obj.IsList() {
+			objects = append(objects, obj)
+			continue
+		}
+		if err := obj.EachListItem(func(item runtime.Object) error {
+			objects = append(objects, item.(*unstructured.Unstructured))
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("decoding %v: %w", obj.GetKind(), err)
+		}
+	}
+}
+
+// clusterSnapshot is the framework.SharedLister of the simulated cluster,
+// updated as the pods are placed on the nodes.
+type clusterSnapshot struct {
+	nodeInfoList []*framework.NodeInfo
+	nodeInfoMap  map[string]*framework.NodeInfo
+	usedPVCs     sets.Set[string]
+}
+
+var _ framework.SharedLister = &clusterSnapshot{}
+var _ framework.NodeInfoLister = &clusterSnapshot{}
+var _ framework.StorageInfoLister = &clusterSnapshot{}
+
+func newClusterSnapshot(nodes []*v1.Node, pods []*v1.Pod) *clusterSnapshot {
+	s := &clusterSnapshot{
+		nodeInfoMap: make(map[string]*framework.NodeInfo, len(nodes)),
+		usedPVCs:    sets.New[string](),
+	}
+	for _, node := range nodes {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(node)
+		s.nodeInfoList = append(s.nodeInfoList, nodeInfo)
+		s.nodeInfoMap[node.Name] = nodeInfo
+	}
+	for _, pod := range pods {
+		s.addPod(pod)
+	}
+	return s
+}
+
+// addPod accounts the pod on its node, ignored if the node is not part of the snapshot.
+func (s *clusterSnapshot) addPod(pod *v1.Pod) {
+	nodeInfo, ok := s.nodeInfoMap[pod.Spec.NodeName]
+	if !ok {
+		return
+	}
+	nodeInfo.AddPod(pod)
+	for key := range nodeInfo.PVCRefCounts {
+		s.usedPVCs.Insert(key)
+	}
+}
+
+// removePod removes the pod from its node.
+func (s *clusterSnapshot) removePod(logger klog.Logger, pod *v1.Pod) error {
+	nodeInfo, ok := s.nodeInfoMap[pod.Spec.NodeName]
+	if !ok {
+		return nil
+	}
+	if err := nodeInfo.RemovePod(logger, pod); err != nil {
+		return err
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		key := pod.Namespace + "/" + volume.PersistentVolumeClaim.ClaimName
+		if _, ok := nodeInfo.PVCRefCounts[key]; !ok {
+			s.usedPVCs.Delete(key)
+		}
+	}
+	return nil
+}
+
+func (s *clusterSnapshot) NodeInfos() framework.NodeInfoLister {
+	return s
+}
+
+func (s *clusterSnapshot) StorageInfos() framework.StorageInfoLister {
+	return s
+}
+
+func (s *clusterSnapshot) List() ([]*framework.NodeInfo, error) {
+	return s.nodeInfoList, nil
+}
+
+func (s *clusterSnapshot) HavePodsWithAffinityList() ([]*framework.NodeInfo, error) {
+	var nodeInfos []*framework.NodeInfo
+	for _, nodeInfo := range s.nodeInfoList {
+		if len(nodeInfo.PodsWithAffinity) > 0 {
+			nodeInfos = append(nodeInfos, nodeInfo)
+		}
+	}
+	return nodeInfos, nil
+}
+
+func (s *clusterSnapshot) HavePodsWithRequiredAntiAffinityList() ([]*framework.NodeInfo, error) {
+	var nodeInfos []*framework.NodeInfo
+	for _, nodeInfo := range s.nodeInfoList {
+		if len(nodeInfo.PodsWithRequiredAntiAffinity) > 0 {
+			nodeInfos = append(nodeInfos, nodeInfo)
+		}
+	}
+	return nodeInfos, nil
+}
+
+func (s *clusterSnapshot) Get(nodeName string) (*framework.NodeInfo, error) {
+	nodeInfo, ok := s.nodeInfoMap[nodeName]
+	if !ok {
+		return nil, fmt.Errorf("nodeinfo not found for node name %q", nodeName)
+	}
+	return nodeInfo, nil
+}
+
+func (s *clusterSnapshot) IsPVCUsedByPods(key string) bool {
+	return s.usedPVCs.Has(key)
+}
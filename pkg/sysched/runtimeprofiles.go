@@ -0,0 +1,113 @@
+package sysched
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// SPO annotation prefixes of the containers recorded by a ProfileRecording,
+// for the bpf, logs and hook recorders respectively
+var recordingAnnotations = []string{
+	"io.containers.trace-bpf/",
+	"io.containers.trace-logs/",
+	"io.containers.trace-syscall/",
+}
+
+// extracts the name of the seccomp profile CR recorded for a container from
+// the value of its recording annotation, without the timestamp of the recording
+// e.g., <recording>-<container>-<timestamp> with the bpf and logs recorders OR
+// e.g., of:<output path>/<recording>-<container>-<timestamp>.json with the hook recorder
+func recordedProfileName(value string) string {
+	name := strings.TrimSuffix(path.Base(strings.TrimPrefix(value, "of:")), ".json")
+	i := strings.LastIndex(name, "-")
+	if i <= 0 {
+		return ""
+	}
+	return name[:i]
+}
+
+// obtains the system calls recorded at runtime for a pod, e.g., by the eBPF
+// recorder of SPO, from the seccomp profile CRs SPO creates in the namespace
+// of the pod once the recording of its containers completes
+func (sc *SySched) getRecordedSyscalls(logger klog.Logger, pod *v1.Pod) sets.Set[string] {
+	r := sets.New[string]()
+
+	for k, v := range pod.ObjectMeta.Annotations {
+		for _, prefix := range recordingAnnotations {
+			if !strings.HasPrefix(k, prefix) {
+				continue
+			}
+			name := recordedProfileName(v)
+			if name == "" {
+				continue
+			}
+
+			syscalls, err := sc.readSPOProfileCR(name, pod.Namespace)
+			if err != nil {
+				// the profile is created once the recording completes
+				if !apierrors.IsNotFound(err) {
+					logger.Error(err, "Failed to read recorded syscall CR", "pod", klog.KObj(pod), "profile", name)
+				}
+				continue
+			}
+			r = r.Union(syscalls)
+		}
+	}
+
+	return r
+}
+
+// refreshSyscalls reads again the system calls of the cached pods, and
+// recomputes the host syscalls of the nodes whose pods' system calls changed,
+// e.g., when the recording of their profiles completed
+func (sc *SySched) refreshSyscalls(logger klog.Logger) {
+	sc.lock.RLock()
+	var pods []*v1.Pod
+	for _, p := range sc.HostToPods {
+		pods = append(pods, p...)
+	}
+	sc.lock.RUnlock()
+
+	// read the profiles without holding the lock
+	syscalls := make(map[types.NamespacedName]sets.Set[string], len(pods))
+	for _, p := range pods {
+		syscalls[podKey(p)] = sc.getSyscalls(logger, p)
+	}
+
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	for nodeName, nodePods := range sc.HostToPods {
+		changed := false
+		for _, p := range nodePods {
+			// pods added since are up-to-date
+			s, ok := syscalls[podKey(p)]
+			if !ok || s.Equal(sc.podSyscalls[podKey(p)]) {
+				continue
+			}
+			sc.podSyscalls[podKey(p)] = s
+			changed = true
+		}
+		if changed {
+			sc.HostSyscalls[nodeName] = sc.recomputeHostSyscalls(logger, nodePods)
+			logger.V(5).Info("refreshed syscalls", "node", nodeName, "syscalls", sc.HostSyscalls[nodeName].Len())
+		}
+	}
+}
+
+// runRefresh refreshes the system calls of the cached pods periodically until the context is done
+func (sc *SySched) runRefresh(ctx context.Context, period time.Duration) {
+	logger := klog.FromContext(ctx)
+	wait.UntilWithContext(ctx, func(context.Context) {
+		sc.refreshSyscalls(logger)
+	}, period)
+}
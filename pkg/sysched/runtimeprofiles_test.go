@@ -0,0 +1,75 @@
+package sysched
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	"sigs.k8s.io/security-profiles-operator/api/seccompprofile/v1beta1"
+)
+
+func TestRecordedProfileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:     "bpf recorder",
+			value:    "recording-nginx-1700000000",
+			expected: "recording-nginx",
+		},
+		{
+			name:     "hook recorder",
+			value:    "of:/var/run/seccomp/recording-nginx-1-1700000000.json",
+			expected: "recording-nginx-1",
+		},
+		{
+			name:     "malformed value",
+			value:    "recording",
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, recordedProfileName(tt.value))
+		})
+	}
+}
+
+func TestRefreshRecordedSyscalls(t *testing.T) {
+	sys, err := mockSysched()
+	assert.Nil(t, err)
+	sys.runtimeProfiles = true
+	logger := klog.FromContext(context.TODO())
+
+	pod := st.MakePod().Name("nginx").Namespace("default").Node("node-1").
+		Annotation("io.containers.trace-bpf/nginx", "recording-nginx-1700000000").Obj()
+	pod.Status.Phase = v1.PodRunning
+	sys.podAdded(pod)
+
+	// the recording is not complete yet, the pod may use all the system calls
+	_, hostSyscalls := sys.getHostSyscalls(logger, "node-1")
+	assert.EqualValues(t, len(spoResponseFull.Spec.Syscalls[0].Names), hostSyscalls.Len())
+
+	recorded := &v1beta1.SeccompProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "recording-nginx", Namespace: "default"},
+		Spec: v1beta1.SeccompProfileSpec{
+			DefaultAction: "SCMP_ACT_ERRNO",
+			Syscalls: []*v1beta1.Syscall{{
+				Action: "SCMP_ACT_ALLOW",
+				Names:  []string{"read", "write", "accept", "epoll_pwait"},
+			}},
+		},
+	}
+	assert.Nil(t, sys.client.Create(context.TODO(), recorded))
+
+	sys.refreshSyscalls(logger)
+	_, hostSyscalls = sys.getHostSyscalls(logger, "node-1")
+	assert.ElementsMatch(t, recorded.Spec.Syscalls[0].Names, hostSyscalls.UnsortedList())
+}
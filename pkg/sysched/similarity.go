@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sysched
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// ExposureMetric computes the exposure of a pod to the system calls of its node, i.e. the
+// union of the system calls of the pods running on it. The less similar the system call sets,
+// the higher the exposure; the exposures of the pods of a node are summed up into its score.
+type ExposureMetric interface {
+	Exposure(podSyscalls, hostSyscalls sets.Set[string]) float64
+}
+
+// extraneousSyscalls counts the system calls of the node the pod doesn't use (ExS).
+type extraneousSyscalls struct{}
+
+func (extraneousSyscalls) Exposure(podSyscalls, hostSyscalls sets.Set[string]) float64 {
+	return float64(hostSyscalls.Difference(podSyscalls).Len())
+}
+
+// jaccard measures the Jaccard distance between the system calls of the pod and of the node,
+// scaled to MaxNodeScore. Unlike ExS, it doesn't favor the nodes using fewer system calls
+// when the pod uses a small share of them.
+type jaccard struct{}
+
+func (jaccard) Exposure(podSyscalls, hostSyscalls sets.Set[string]) float64 {
+	union := podSyscalls.Union(hostSyscalls).Len()
+	if union == 0 {
+		return 0
+	}
+	similarity := float64(podSyscalls.Intersection(hostSyscalls).Len()) / float64(union)
+	return (1 - similarity) * float64(framework.MaxNodeScore)
+}
+
+// criticalityWeighted counts the system calls of the node the pod doesn't use, the critical ones
+// (e.g. the system calls involved in known container escapes) weighing more than the others.
+type criticalityWeighted struct {
+	critical sets.Set[string]
+	weight   float64
+}
+
+func (m criticalityWeighted) Exposure(podSyscalls, hostSyscalls sets.Set[string]) float64 {
+	extraneous := hostSyscalls.Difference(podSyscalls)
+	totCrit := extraneous.Intersection(m.critical).Len()
+	return float64(extraneous.Len()-totCrit) + m.weight*float64(totCrit)
+}
+
+// newExposureMetric returns the exposure metric selected by the plugin args.
+func newExposureMetric(args *pluginconfig.SySchedArgs) (ExposureMetric, error) {
+	switch args.SimilarityMetric {
+	case "", pluginconfig.SySchedExtraneousSyscalls:
+		return extraneousSyscalls{}, nil
+	case pluginconfig.SySchedJaccard:
+		return jaccard{}, nil
+	case pluginconfig.SySchedCriticalityWeighted:
+		if len(args.CriticalSyscalls) == 0 {
+			return nil, fmt.Errorf("CriticalSyscalls is required with the %v similarity metric", args.SimilarityMetric)
+		}
+		if args.CriticalSyscallWeight < 1 {
+			return nil, fmt.Errorf("CriticalSyscallWeight must be at least 1, got %v", args.CriticalSyscallWeight)
+		}
+		return criticalityWeighted{
+			critical: sets.New[string](args.CriticalSyscalls...),
+			weight:   float64(args.CriticalSyscallWeight),
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid SimilarityMetric, got %v", args.SimilarityMetric)
+}
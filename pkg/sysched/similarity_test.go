@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sysched
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+func TestExposureMetricsRanking(t *testing.T) {
+	podSyscalls := sets.New[string]("read", "write", "openat")
+	hosts := map[string]sets.Set[string]{
+		// the pod uses a small share of the many system calls of the node
+		"wide": sets.New[string]("read", "write", "openat", "close", "mmap", "futex", "clone", "execve"),
+		// the pod shares no system call with the node, which uses few of them
+		"disjoint": sets.New[string]("ptrace", "mount"),
+		// the pod shares most of the system calls of the node
+		"close": sets.New[string]("read", "write", "close"),
+	}
+
+	tests := []struct {
+		name     string
+		args     pluginconfig.SySchedArgs
+		expected []string
+	}{
+		{
+			name:     "extraneous system calls favor the nodes using few system calls",
+			args:     pluginconfig.SySchedArgs{SimilarityMetric: pluginconfig.SySchedExtraneousSyscalls},
+			expected: []string{"close", "disjoint", "wide"},
+		},
+		{
+			name:     "jaccard favors the nodes sharing the system calls of the pod",
+			args:     pluginconfig.SySchedArgs{SimilarityMetric: pluginconfig.SySchedJaccard},
+			expected: []string{"close", "wide", "disjoint"},
+		},
+		{
+			name: "criticality weighted disfavors the nodes exposing critical system calls",
+			args: pluginconfig.SySchedArgs{
+				SimilarityMetric:      pluginconfig.SySchedCriticalityWeighted,
+				CriticalSyscalls:      []string{"ptrace", "mount"},
+				CriticalSyscallWeight: 10,
+			},
+			expected: []string{"close", "wide", "disjoint"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, err := newExposureMetric(&tt.args)
+			assert.Nil(t, err)
+
+			exposures := make(map[string]float64)
+			var ranking []string
+			for name, hostSyscalls := range hosts {
+				exposures[name] = metric.Exposure(podSyscalls, hostSyscalls)
+				ranking = append(ranking, name)
+			}
+			sort.Slice(ranking, func(i, j int) bool { return exposures[ranking[i]] < exposures[ranking[j]] })
+			assert.EqualValues(t, tt.expected, ranking)
+		})
+	}
+}
+
+func TestExposureMetrics(t *testing.T) {
+	podSyscalls := sets.New[string]("read", "write")
+	hostSyscalls := sets.New[string]("read", "write", "close", "ptrace")
+
+	assert.EqualValues(t, 2, extraneousSyscalls{}.Exposure(podSyscalls, hostSyscalls))
+	assert.EqualValues(t, 50, jaccard{}.Exposure(podSyscalls, hostSyscalls))
+	assert.EqualValues(t, 0, jaccard{}.Exposure(sets.New[string](), sets.New[string]()))
+	weighted := criticalityWeighted{critical: sets.New[string]("ptrace"), weight: 10}
+	assert.EqualValues(t, 11, weighted.Exposure(podSyscalls, hostSyscalls))
+}
+
+func TestNewExposureMetric(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    pluginconfig.SySchedArgs
+		wantErr bool
+	}{
+		{
+			name: "default metric",
+			args: pluginconfig.SySchedArgs{},
+		},
+		{
+			name:    "unknown metric",
+			args:    pluginconfig.SySchedArgs{SimilarityMetric: "Embedding"},
+			wantErr: true,
+		},
+		{
+			name:    "criticality weighted without critical system calls",
+			args:    pluginconfig.SySchedArgs{SimilarityMetric: pluginconfig.SySchedCriticalityWeighted, CriticalSyscallWeight: 10},
+			wantErr: true,
+		},
+		{
+			name: "criticality weighted with a weight below 1",
+			args: pluginconfig.SySchedArgs{
+				SimilarityMetric: pluginconfig.SySchedCriticalityWeighted,
+				CriticalSyscalls: []string{"ptrace"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newExposureMetric(&tt.args)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
@@ -6,11 +6,15 @@ import (
 	"math"
 	"path"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/containers/common/pkg/seccomp"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	clientscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/cache"
@@ -26,6 +30,7 @@ import (
 	
 	pluginconfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
 	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
 )
 
 type SySched struct {
@@ -43,6 +48,16 @@ type SySched struct {
 	DefaultProfileNamespace string
 	DefaultProfileName      string
 	WeightedSyscallProfile  string
+	// Computes the exposure of a pod to the system calls of its node
+	metric ExposureMetric
+	// Also read the seccomp profiles recorded at runtime for the pods
+	runtimeProfiles bool
+	// Key: pod namespace/name
+	// Value: set of system call names of a cached pod, so that the host
+	// syscalls of a node are recomputed without reading the profiles again
+	podSyscalls map[types.NamespacedName]sets.Set[string]
+	// Protects HostToPods, HostSyscalls and podSyscalls
+	lock sync.RWMutex
 }
 
 var _ framework.ScorePlugin = &SySched{}
@@ -196,6 +211,11 @@ func (sc *SySched) getSyscalls(logger klog.Logger, pod *v1.Pod) sets.Set[string]
 		}
 	}
 
+	// merge the system calls recorded at runtime for the pod, if any
+	if sc.runtimeProfiles {
+		r = r.Union(sc.getRecordedSyscalls(logger, pod))
+	}
+
 	// if a pod does not have a seccomp profile specified, return the set of all syscalls
 	if len(r) == 0 {
 		syscalls, err := sc.readSPOProfileCR(sc.DefaultProfileName, sc.DefaultProfileNamespace)
@@ -216,19 +236,13 @@ func (sc *SySched) Name() string {
 	return Name
 }
 
-func (sc *SySched) calcScore(logger klog.Logger, syscalls sets.Set[string]) int {
-	// Currently, score is not adjusted based on critical/cve syscalls.
-	// NOTE: weight W is hardcoded for now
-	// TODO: add critical/cve syscalls
-	// TODO: adjust weight W for critical/cve syscalls
-	totCrit := 0
-	W := 1
-
-	score := syscalls.Len() - totCrit
-	score = score + W*totCrit
-	logger.V(10).Info("Score: ", "score", score, "tot_crit", totCrit)
-
-	return score
+// exposure computes the exposure of a pod to the system calls of its node
+// with the configured metric, counting the extraneous system calls by default
+func (sc *SySched) exposure(podSyscalls, hostSyscalls sets.Set[string]) float64 {
+	if sc.metric == nil {
+		return extraneousSyscalls{}.Exposure(podSyscalls, hostSyscalls)
+	}
+	return sc.metric.Exposure(podSyscalls, hostSyscalls)
 }
 
 // Score invoked at the score extension point.
@@ -250,33 +264,43 @@ func (sc *SySched) Score(ctx context.Context, cs *framework.CycleState, pod *v1.
 		return math.MaxInt64, nil
 	}
 
+	sc.lock.RLock()
+	defer sc.lock.RUnlock()
+
 	_, hostSyscalls := sc.getHostSyscalls(logger, node.Name)
 
+	// score the pod as if its whole gang were placed: the siblings placed
+	// on the node but not cached yet add their system calls to the node
+	siblings := sc.gangPlacements(pod)[node.Name]
+	for _, p := range siblings {
+		hostSyscalls = hostSyscalls.Union(sc.podSyscallsOf(logger, p))
+	}
+
 	// when a host or node does not have any pods
 	// running, the extraneous syscall score is zero
 	if hostSyscalls == nil {
 		return 0, nil
 	}
 
-	diffSyscalls := hostSyscalls.Difference(podSyscalls)
-	totalDiffs := sc.calcScore(logger, diffSyscalls)
+	totalDiffs := sc.exposure(podSyscalls, hostSyscalls)
 
-	// add the difference existing pods will see if new Pod is added into this host
+	// add the exposure existing pods will see if new Pod is added into this host
 	newHostSyscalls := hostSyscalls.Clone()
 	newHostSyscalls = newHostSyscalls.Union(podSyscalls)
 	for _, p := range sc.HostToPods[node.Name] {
-		podSyscalls = sc.getSyscalls(logger, p)
-		diffSyscalls = newHostSyscalls.Difference(podSyscalls)
-		totalDiffs += sc.calcScore(logger, diffSyscalls)
+		totalDiffs += sc.exposure(sc.podSyscallsOf(logger, p), newHostSyscalls)
+	}
+	for _, p := range siblings {
+		totalDiffs += sc.exposure(sc.podSyscallsOf(logger, p), newHostSyscalls)
 	}
 
-	sc.ExSAvg = sc.ExSAvg + (float64(totalDiffs)-sc.ExSAvg)/float64(sc.ExSAvgCount)
+	sc.ExSAvg = sc.ExSAvg + (totalDiffs-sc.ExSAvg)/float64(sc.ExSAvgCount)
 	sc.ExSAvgCount += 1
 
 	logger.V(10).Info("ExSAvg: ", sc.ExSAvg)
 	logger.V(10).Info("Score: ", "totalDiffs", totalDiffs, "pod", pod.Name, "node", nodeName)
 
-	return int64(totalDiffs), nil
+	return int64(math.Round(totalDiffs)), nil
 }
 
 func (sc *SySched) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
@@ -308,16 +332,94 @@ func (sc *SySched) updateHostSyscalls(logger klog.Logger, pod *v1.Pod) {
 	sc.HostSyscalls[pod.Spec.NodeName] = sc.HostSyscalls[pod.Spec.NodeName].Union(syscall)
 }
 
+// podSyscallsOf returns the cached system calls of a pod, and reads
+// them from the pod's seccomp profiles if the pod is not cached
+func (sc *SySched) podSyscallsOf(logger klog.Logger, pod *v1.Pod) sets.Set[string] {
+	if syscalls, ok := sc.podSyscalls[podKey(pod)]; ok {
+		return syscalls
+	}
+	return sc.getSyscalls(logger, pod)
+}
+
+// gangPlacements returns the siblings of a pod in its PodGroup by the node they
+// are placed on, but not cached on yet: waiting for the rest of their gang at
+// Permit, bound, or nominated by a preemption. It is empty if the pod is not
+// in a PodGroup.
+func (sc *SySched) gangPlacements(pod *v1.Pod) map[string][]*v1.Pod {
+	pgName := util.GetPodGroupLabel(pod)
+	if pgName == "" {
+		return nil
+	}
+
+	placements := make(map[string][]*v1.Pod)
+	seen := sets.New(podKey(pod))
+	place := func(p *v1.Pod, nodeName string) {
+		if nodeName == "" || seen.Has(podKey(p)) {
+			return
+		}
+		seen.Insert(podKey(p))
+		for _, cached := range sc.HostToPods[nodeName] {
+			if podKey(cached) == podKey(p) {
+				return
+			}
+		}
+		placements[nodeName] = append(placements[nodeName], p)
+	}
+
+	sc.handle.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		p := waitingPod.GetPod()
+		if p.Namespace == pod.Namespace && util.GetPodGroupLabel(p) == pgName {
+			place(p, p.Spec.NodeName)
+		}
+	})
+
+	informerFactory := sc.handle.SharedInformerFactory()
+	if informerFactory == nil {
+		return placements
+	}
+	selector := labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: pgName})
+	siblings, err := informerFactory.Core().V1().Pods().Lister().Pods(pod.Namespace).List(selector)
+	if err != nil {
+		return placements
+	}
+	for _, p := range siblings {
+		nodeName := p.Spec.NodeName
+		if nodeName == "" {
+			nodeName = p.Status.NominatedNodeName
+		}
+		place(p, nodeName)
+	}
+	return placements
+}
+
+func podKey(pod *v1.Pod) types.NamespacedName {
+	return types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+}
+
+// addHostSyscalls reads the system calls of a pod added to the cache once,
+// and adds them to the host syscalls of its node
+func (sc *SySched) addHostSyscalls(logger klog.Logger, pod *v1.Pod) {
+	if sc.podSyscalls == nil {
+		sc.podSyscalls = make(map[types.NamespacedName]sets.Set[string])
+	}
+	syscalls := sc.getSyscalls(logger, pod)
+	sc.podSyscalls[podKey(pod)] = syscalls
+	sc.HostSyscalls[pod.Spec.NodeName] = sc.HostSyscalls[pod.Spec.NodeName].Union(syscalls)
+}
+
 func (sc *SySched) addPod(logger klog.Logger, pod *v1.Pod) {
 	nodeName := pod.Spec.NodeName
 	name := pod.Name
 
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
 	_, ok := sc.HostToPods[nodeName]
 	if !ok {
 		sc.HostToPods[nodeName] = make([]*v1.Pod, 0)
 		sc.HostToPods[nodeName] = append(sc.HostToPods[nodeName], pod)
 		sc.HostSyscalls[nodeName] = sets.New[string]()
-		sc.updateHostSyscalls(logger, pod)
+		sc.addHostSyscalls(logger, pod)
 		return
 	}
 
@@ -328,7 +430,7 @@ func (sc *SySched) addPod(logger klog.Logger, pod *v1.Pod) {
 	}
 
 	sc.HostToPods[nodeName] = append(sc.HostToPods[nodeName], pod)
-	sc.updateHostSyscalls(logger, pod)
+	sc.addHostSyscalls(logger, pod)
 
 	return
 }
@@ -337,7 +439,7 @@ func (sc *SySched) recomputeHostSyscalls(logger klog.Logger, pods []*v1.Pod) set
 	syscalls := sets.New[string]()
 
 	for _, p := range pods {
-		syscall := sc.getSyscalls(logger, p)
+		syscall := sc.podSyscallsOf(logger, p)
 		syscalls = syscalls.Union(syscall)
 	}
 
@@ -347,6 +449,9 @@ func (sc *SySched) recomputeHostSyscalls(logger klog.Logger, pods []*v1.Pod) set
 func (sc *SySched) removePod(logger klog.Logger, pod *v1.Pod) {
 	nodeName := pod.Spec.NodeName
 
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
 	_, ok := sc.HostToPods[nodeName]
 	if !ok {
 		logger.V(5).Info(fmt.Sprintf("removePod: Host %s not yet cached", nodeName))
@@ -355,6 +460,8 @@ func (sc *SySched) removePod(logger klog.Logger, pod *v1.Pod) {
 	for i, p := range sc.HostToPods[nodeName] {
 		if p.Name == pod.Name {
 			sc.HostToPods[nodeName] = remove(sc.HostToPods[nodeName], i)
+			delete(sc.podSyscalls, podKey(p))
+			// only the host syscalls of the node of the removed pod change
 			sc.HostSyscalls[nodeName] = sc.recomputeHostSyscalls(logger, sc.HostToPods[nodeName])
 			c, _ := sc.getHostSyscalls(logger, nodeName)
 			logger.V(5).Info("remaining ", "syscalls", c, "node", nodeName)
@@ -406,10 +513,11 @@ func getArgs(obj runtime.Object) (*pluginconfig.SySchedArgs, error) {
 }
 
 // New initializes a new plugin and returns it.
-func New(_ context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
 	sc := SySched{handle: handle}
 	sc.HostToPods = make(map[string][]*v1.Pod)
 	sc.HostSyscalls = make(map[string]sets.Set[string])
+	sc.podSyscalls = make(map[types.NamespacedName]sets.Set[string])
 	sc.ExSAvg = 0
 	sc.ExSAvgCount = 1
 
@@ -422,6 +530,16 @@ func New(_ context.Context, obj runtime.Object, handle framework.Handle) (framew
 	sc.DefaultProfileNamespace = args.DefaultProfileNamespace
 	sc.DefaultProfileName = args.DefaultProfileName
 
+	sc.metric, err = newExposureMetric(args)
+	if err != nil {
+		return nil, err
+	}
+
+	sc.runtimeProfiles = args.RuntimeProfiles
+	if sc.runtimeProfiles && args.ProfileRefreshSeconds <= 0 {
+		return nil, fmt.Errorf("profileRefreshSeconds must be positive with runtimeProfiles, got %d", args.ProfileRefreshSeconds)
+	}
+
 	scheme := runtime.NewScheme()
 	_ = clientscheme.AddToScheme(scheme)
 	_ = v1.AddToScheme(scheme)
@@ -446,5 +564,10 @@ func New(_ context.Context, obj runtime.Object, handle framework.Handle) (framew
 		},
 	)
 
+	// the recorded profiles of the pods are created, and updated, while they run
+	if sc.runtimeProfiles {
+		go sc.runRefresh(ctx, time.Duration(args.ProfileRefreshSeconds)*time.Second)
+	}
+
 	return &sc, nil
 }
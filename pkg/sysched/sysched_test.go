@@ -326,8 +326,7 @@ func TestGetSyscalls(t *testing.T) {
 	}
 }
 
-func TestCalcScore(t *testing.T) {
-	sys, _ := mockSysched()
+func TestExtraneousSyscalls(t *testing.T) {
 	tests := []struct {
 		name     string
 		syscalls sets.Set[string]
@@ -341,8 +340,7 @@ func TestCalcScore(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			logger := klog.FromContext(context.TODO())
-			score := sys.calcScore(logger, tt.syscalls)
+			score := extraneousSyscalls{}.Exposure(sets.New[string](), tt.syscalls)
 			assert.EqualValues(t, tt.expected, score)
 		})
 	}
@@ -419,6 +417,72 @@ func TestScore(t *testing.T) {
 	}
 }
 
+func TestScoreGang(t *testing.T) {
+	node := st.MakeNode()
+	node.Name("test")
+
+	// a sibling of the scored pod nominated on the node, but not cached yet
+	sibling := st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+		"localhost/operator/default/z-seccomp.json").Name("sibling").
+		Label(v1alpha1.PodGroupLabel, "pg").NominatedNodeName("test").Obj()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cs := clientsetfake.NewSimpleClientset(node.Obj())
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	if err := informerFactory.Core().V1().Pods().Informer().GetStore().Add(sibling); err != nil {
+		t.Fatal(err)
+	}
+	fr, err := tf.NewFramework(ctx, registeredPlugins, Name,
+		frameworkruntime.WithClientSet(cs), frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithWaitingPods(frameworkruntime.NewWaitingPodsMap()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1beta1.AddToScheme(scheme))
+	client := fake.NewClientBuilder().WithScheme(scheme).
+		WithRuntimeObjects(&spoResponse, &spoResponse1, &spoResponseFull).Build()
+
+	sys := SySched{handle: fr}
+	sys.client = client
+	sys.HostToPods = make(map[string][]*v1.Pod)
+	sys.HostSyscalls = make(map[string]sets.Set[string])
+	sys.ExSAvgCount = 1
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected int
+	}{
+		{
+			name: "pod in the gang of the sibling",
+			pod: st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+				"localhost/operator/default/x-seccomp.json").Name("pod").Label(v1alpha1.PodGroupLabel, "pg").Obj(),
+			expected: 2,
+		},
+		{
+			name: "pod in another gang",
+			pod: st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+				"localhost/operator/default/x-seccomp.json").Name("pod").Label(v1alpha1.PodGroupLabel, "other").Obj(),
+			expected: 0,
+		},
+		{
+			name: "pod without gang",
+			pod: st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+				"localhost/operator/default/x-seccomp.json").Name("pod").Obj(),
+			expected: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, _ := sys.Score(context.Background(), nil, tt.pod, "test")
+			assert.EqualValues(t, tt.expected, score)
+		})
+	}
+}
+
 func TestNormalizeScore(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -680,6 +744,7 @@ func TestRemovePod(t *testing.T) {
 
 			assert.EqualValues(t, tt.expectedPodNum, len(sys.HostToPods["test"]))
 			assert.EqualValues(t, tt.expected, len(sys.HostSyscalls["test"]))
+			assert.EqualValues(t, tt.expectedPodNum, len(sys.podSyscalls))
 		})
 	}
 }
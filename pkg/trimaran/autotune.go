@@ -0,0 +1,248 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	pluginConfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/sharedstate"
+)
+
+const (
+	// Delay after binding before a placement is compared with the observed utilization,
+	// leaving time for the metrics agent to report the load of the placed pod
+	autoTuneObservationDelaySeconds = 2 * metricsAgentReportingIntervalSeconds
+	// Minimum number of observations before adjusting a coefficient
+	autoTuneMinSamples = 3
+)
+
+// TuningDirection : how a coefficient reacts to nodes being more utilized than predicted
+type TuningDirection float64
+
+const (
+	// DecreaseOnUnderPrediction lowers the coefficient when nodes are hotter than predicted (e.g. a target utilization)
+	DecreaseOnUnderPrediction TuningDirection = -1
+	// IncreaseOnUnderPrediction raises the coefficient when nodes are hotter than predicted (e.g. a safety margin)
+	IncreaseOnUnderPrediction TuningDirection = 1
+)
+
+// ObserveFunc : get the observed CPU utilization (percent) of a node
+type ObserveFunc func(logger klog.Logger, nodeName string) (float64, bool)
+
+// placement : a pod bound to a node, with the node CPU utilization (percent) predicted when scoring
+type placement struct {
+	nodeName  string
+	predicted float64
+	placedAt  time.Time
+}
+
+// CoefficientTuner : feedback loop slowly adjusting a plugin coefficient, within bounds around its
+// configured value, from the error between the node utilization predicted when scoring a pod and the
+// utilization observed after the pod is placed. Learned values are persisted in a ConfigMap, followed by the
+// standby replicas of the scheduler so that they keep the learned values when they become the leader.
+type CoefficientTuner struct {
+	// key of the coefficient in the ConfigMap data
+	key          string
+	configured   float64
+	lower        float64
+	upper        float64
+	learningRate float64
+	direction    TuningDirection
+	interval     time.Duration
+
+	// learned values shared by the replicas of the scheduler
+	store *sharedstate.ConfigMapStore
+
+	// predictions made while scoring, keyed by pod UID and node name
+	predictions *gocache.Cache
+	// placements waiting for their observation delay
+	pending []placement
+
+	// for safe access to the learned value and accumulated error
+	mu         sync.RWMutex
+	value      float64
+	errorSum   float64
+	samples    int
+	lastAdjust time.Time
+}
+
+// NewCoefficientTuner : create a tuner for a coefficient with a given configured value, whose learned values are
+// also kept within the range [lowest, highest] of the values valid for the coefficient
+func NewCoefficientTuner(key string, configured, lowest, highest float64, direction TuningDirection,
+	spec *pluginConfig.AutoTuneSpec, client kubernetes.Interface) *CoefficientTuner {
+	deviation := configured * float64(spec.MaxAdjustmentPercent) / 100
+	return &CoefficientTuner{
+		key:          key,
+		configured:   configured,
+		lower:        max(min(configured-deviation, configured+deviation), lowest),
+		upper:        min(max(configured-deviation, configured+deviation), highest),
+		learningRate: spec.LearningRate,
+		direction:    direction,
+		interval:     time.Duration(spec.IntervalSeconds) * time.Second,
+		store:        sharedstate.NewConfigMapStore(client, spec.ConfigMapNamespace, spec.ConfigMapName),
+		predictions:  gocache.New(time.Minute*cacheCleanupIntervalMinutes, time.Minute*cacheCleanupIntervalMinutes),
+		value:        configured,
+		lastAdjust:   time.Now(),
+	}
+}
+
+// Value : get the current value of the coefficient
+func (t *CoefficientTuner) Value() float64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.value
+}
+
+// RecordPrediction : record the node CPU utilization (percent) predicted when scoring a pod on a node
+func (t *CoefficientTuner) RecordPrediction(pod *v1.Pod, nodeName string, predicted float64) {
+	t.predictions.SetDefault(predictionKey(pod, nodeName), predicted)
+}
+
+// Observe : account for the error between a predicted and an observed node utilization (percent)
+func (t *CoefficientTuner) Observe(predicted, observed float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errorSum += observed - predicted
+	t.samples++
+}
+
+// Start : load the persisted coefficient, follow the coefficient persisted by the leader, and run the feedback loop
+// until the context is done
+func (t *CoefficientTuner) Start(ctx context.Context, logger klog.Logger, handler *PodAssignEventHandler, observe ObserveFunc) {
+	if err := t.load(ctx); err != nil {
+		logger.Error(err, "Failed to load learned coefficient; using configured value", "key", t.key, "value", t.configured)
+	}
+	t.store.Watch(ctx, t.key, func(data string) {
+		if err := t.set(data); err != nil {
+			logger.Error(err, "Failed to follow learned coefficient", "key", t.key)
+		}
+	})
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		t.collectPlacements(handler)
+		t.observePlacements(logger, observe)
+		if time.Since(t.lastAdjust) < t.interval {
+			return
+		}
+		t.lastAdjust = time.Now()
+		if value, changed := t.adjust(); changed {
+			logger.V(4).Info("Adjusted coefficient", "key", t.key, "value", value)
+			if err := t.persist(ctx, value); err != nil {
+				logger.Error(err, "Failed to persist learned coefficient", "key", t.key)
+			}
+		}
+	}, metricsAgentReportingIntervalSeconds*time.Second)
+}
+
+// collectPlacements : pair pods bound by the scheduler with the prediction made for their node
+func (t *CoefficientTuner) collectPlacements(handler *PodAssignEventHandler) {
+	handler.RLock()
+	defer handler.RUnlock()
+	for nodeName, infos := range handler.ScheduledPodsCache {
+		for _, info := range infos {
+			key := predictionKey(info.Pod, nodeName)
+			predicted, ok := t.predictions.Get(key)
+			if !ok {
+				continue
+			}
+			t.predictions.Delete(key)
+			t.pending = append(t.pending, placement{
+				nodeName:  nodeName,
+				predicted: predicted.(float64),
+				placedAt:  info.Timestamp,
+			})
+		}
+	}
+}
+
+// observePlacements : compare pending placements older than the observation delay with the observed utilization
+func (t *CoefficientTuner) observePlacements(logger klog.Logger, observe ObserveFunc) {
+	now := time.Now()
+	remaining := t.pending[:0]
+	for _, p := range t.pending {
+		if now.Sub(p.placedAt) < autoTuneObservationDelaySeconds*time.Second {
+			remaining = append(remaining, p)
+			continue
+		}
+		if observed, ok := observe(logger, p.nodeName); ok {
+			logger.V(6).Info("Observed node utilization", "key", t.key, "nodeName", p.nodeName,
+				"predicted", p.predicted, "observed", observed)
+			t.Observe(p.predicted, observed)
+		}
+	}
+	t.pending = remaining
+}
+
+// adjust : move the coefficient by a fraction of the mean relative prediction error, within bounds
+func (t *CoefficientTuner) adjust() (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.samples < autoTuneMinSamples {
+		return t.value, false
+	}
+	meanError := t.errorSum / float64(t.samples)
+	t.errorSum, t.samples = 0, 0
+
+	value := t.value + float64(t.direction)*t.learningRate*meanError/100*t.configured
+	value = max(min(value, t.upper), t.lower)
+	if value == t.value {
+		return value, false
+	}
+	t.value = value
+	return value, true
+}
+
+// load : get the learned coefficient from the ConfigMap, if any
+func (t *CoefficientTuner) load(ctx context.Context) error {
+	data, ok, err := t.store.Get(ctx, t.key)
+	if err != nil || !ok {
+		return err
+	}
+	return t.set(data)
+}
+
+// set : set the coefficient to a learned value, within bounds
+func (t *CoefficientTuner) set(data string) error {
+	value, err := strconv.ParseFloat(data, 64)
+	if err != nil {
+		return fmt.Errorf("unable to parse learned coefficient %q: %w", t.key, err)
+	}
+	t.mu.Lock()
+	t.value = max(min(value, t.upper), t.lower)
+	t.mu.Unlock()
+	return nil
+}
+
+// persist : save the learned coefficient in the ConfigMap, creating it if needed
+func (t *CoefficientTuner) persist(ctx context.Context, value float64) error {
+	return t.store.Set(ctx, t.key, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+func predictionKey(pod *v1.Pod, nodeName string) string {
+	return string(pod.UID) + "/" + nodeName
+}
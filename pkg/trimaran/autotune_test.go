@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+
+	pluginConfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+var autoTuneSpec = pluginConfig.AutoTuneSpec{
+	ConfigMapNamespace:   "kube-system",
+	ConfigMapName:        "trimaran-autotune",
+	IntervalSeconds:      300,
+	LearningRate:         0.5,
+	MaxAdjustmentPercent: 25,
+}
+
+func TestCoefficientTunerAdjust(t *testing.T) {
+	tests := []struct {
+		name         string
+		direction    TuningDirection
+		observations [][2]float64
+		expected     float64
+		changed      bool
+	}{
+		{
+			name:         "not enough samples",
+			direction:    DecreaseOnUnderPrediction,
+			observations: [][2]float64{{40, 60}, {40, 60}},
+			expected:     40,
+			changed:      false,
+		},
+		{
+			name:         "over-packing lowers target",
+			direction:    DecreaseOnUnderPrediction,
+			observations: [][2]float64{{40, 50}, {40, 50}, {40, 50}},
+			expected:     38,
+			changed:      true,
+		},
+		{
+			name:         "under-packing raises target",
+			direction:    DecreaseOnUnderPrediction,
+			observations: [][2]float64{{40, 30}, {40, 30}, {40, 30}},
+			expected:     42,
+			changed:      true,
+		},
+		{
+			name:         "over-packing raises margin",
+			direction:    IncreaseOnUnderPrediction,
+			observations: [][2]float64{{40, 50}, {40, 50}, {40, 50}},
+			expected:     42,
+			changed:      true,
+		},
+		{
+			name:         "adjustment bounded",
+			direction:    DecreaseOnUnderPrediction,
+			observations: [][2]float64{{10, 100}, {10, 100}, {10, 100}},
+			expected:     30,
+			changed:      true,
+		},
+		{
+			name:         "accurate predictions",
+			direction:    DecreaseOnUnderPrediction,
+			observations: [][2]float64{{40, 45}, {40, 35}, {40, 40}},
+			expected:     40,
+			changed:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tuner := NewCoefficientTuner("TargetLoadPacking.targetUtilization", 40, 0, 100, tt.direction, &autoTuneSpec, fake.NewSimpleClientset())
+			for _, o := range tt.observations {
+				tuner.Observe(o[0], o[1])
+			}
+			value, changed := tuner.adjust()
+			assert.Equal(t, tt.changed, changed)
+			assert.InDelta(t, tt.expected, value, 1e-9)
+			assert.InDelta(t, tt.expected, tuner.Value(), 1e-9)
+		})
+	}
+}
+
+func TestCoefficientTunerPersistence(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	tuner := NewCoefficientTuner("TargetLoadPacking.targetUtilization", 40, 0, 100, DecreaseOnUnderPrediction, &autoTuneSpec, client)
+
+	require.NoError(t, tuner.load(ctx))
+	assert.Equal(t, 40.0, tuner.Value())
+
+	require.NoError(t, tuner.persist(ctx, 37.5))
+	cm, err := client.CoreV1().ConfigMaps("kube-system").Get(ctx, "trimaran-autotune", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "37.5", cm.Data["TargetLoadPacking.targetUtilization"])
+
+	other := NewCoefficientTuner("LoadVariationRiskBalancing.safeVarianceMargin", 1, 0, math.Inf(1), IncreaseOnUnderPrediction, &autoTuneSpec, client)
+	require.NoError(t, other.persist(ctx, 1.1))
+	cm, err = client.CoreV1().ConfigMaps("kube-system").Get(ctx, "trimaran-autotune", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"TargetLoadPacking.targetUtilization":           "37.5",
+		"LoadVariationRiskBalancing.safeVarianceMargin": "1.1",
+	}, cm.Data)
+
+	restarted := NewCoefficientTuner("TargetLoadPacking.targetUtilization", 40, 0, 100, DecreaseOnUnderPrediction, &autoTuneSpec, client)
+	require.NoError(t, restarted.load(ctx))
+	assert.Equal(t, 37.5, restarted.Value())
+
+	// learned values outside the bounds of the configured value are clamped
+	reconfigured := NewCoefficientTuner("TargetLoadPacking.targetUtilization", 60, 0, 100, DecreaseOnUnderPrediction, &autoTuneSpec, client)
+	require.NoError(t, reconfigured.load(ctx))
+	assert.Equal(t, 45.0, reconfigured.Value())
+}
+
+func TestCoefficientTunerValidRange(t *testing.T) {
+	spec := autoTuneSpec
+	spec.LearningRate = 2
+	spec.MaxAdjustmentPercent = 150
+	observe := func(tuner *CoefficientTuner, predicted, observed float64) (float64, bool) {
+		for i := 0; i < autoTuneMinSamples; i++ {
+			tuner.Observe(predicted, observed)
+		}
+		return tuner.adjust()
+	}
+
+	// the bounds of the target utilization around its configured value, [-30, 150], are kept within [0, 100]
+	tuner := NewCoefficientTuner("TargetLoadPacking.targetUtilization", 60, 0, 100, DecreaseOnUnderPrediction, &spec, fake.NewSimpleClientset())
+	value, changed := observe(tuner, 90, 0)
+	assert.True(t, changed)
+	assert.Equal(t, 100.0, value)
+	value, changed = observe(tuner, 0, 90)
+	assert.True(t, changed)
+	assert.Equal(t, 0.0, value)
+	value, changed = observe(tuner, 0, 90)
+	assert.False(t, changed)
+	assert.Equal(t, 0.0, value)
+
+	// learned values outside [0, 100] are clamped too
+	ctx := context.Background()
+	require.NoError(t, tuner.persist(ctx, 120))
+	require.NoError(t, tuner.load(ctx))
+	assert.Equal(t, 100.0, tuner.Value())
+	require.NoError(t, tuner.persist(ctx, -10))
+	require.NoError(t, tuner.load(ctx))
+	assert.Equal(t, 0.0, tuner.Value())
+
+	// the safe variance margin is only kept non-negative, and bounded above around its configured value
+	margin := NewCoefficientTuner("LoadVariationRiskBalancing.safeVarianceMargin", 1, 0, math.Inf(1), IncreaseOnUnderPrediction, &spec, fake.NewSimpleClientset())
+	value, changed = observe(margin, 90, 0)
+	assert.True(t, changed)
+	assert.Equal(t, 0.0, value)
+	value, changed = observe(margin, 0, 90)
+	assert.True(t, changed)
+	assert.InDelta(t, 1.8, value, 1e-9)
+	value, changed = observe(margin, 0, 90)
+	assert.True(t, changed)
+	assert.Equal(t, 2.5, value)
+}
+
+func TestCoefficientTunerFollowsLeader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := fake.NewSimpleClientset()
+	leader := NewCoefficientTuner("TargetLoadPacking.targetUtilization", 40, 0, 100, DecreaseOnUnderPrediction, &autoTuneSpec, client)
+	require.NoError(t, leader.persist(ctx, 37.5))
+
+	// a standby replica loads the learned value, then follows the values persisted by the leader
+	standby := NewCoefficientTuner("TargetLoadPacking.targetUtilization", 40, 0, 100, DecreaseOnUnderPrediction, &autoTuneSpec, client)
+	standby.Start(ctx, klog.FromContext(ctx), &PodAssignEventHandler{ScheduledPodsCache: map[string][]podInfo{}},
+		func(_ klog.Logger, _ string) (float64, bool) { return 0, false })
+	assert.Equal(t, 37.5, standby.Value())
+
+	require.NoError(t, leader.persist(ctx, 42))
+	assert.Eventually(t, func() bool { return standby.Value() == 42 }, wait.ForeverTestTimeout, 10*time.Millisecond)
+}
+
+func TestCoefficientTunerPlacements(t *testing.T) {
+	logger := klog.FromContext(context.Background())
+	tuner := NewCoefficientTuner("TargetLoadPacking.targetUtilization", 40, 0, 100, DecreaseOnUnderPrediction, &autoTuneSpec, fake.NewSimpleClientset())
+
+	pod := func(uid string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: uid, UID: types.UID(uid)}}
+	}
+	placedAt := time.Now().Add(-2 * autoTuneObservationDelaySeconds * time.Second)
+	handler := &PodAssignEventHandler{ScheduledPodsCache: map[string][]podInfo{
+		"node-1": {{Timestamp: placedAt, Pod: pod("p1")}, {Timestamp: time.Now(), Pod: pod("p2")}},
+		"node-2": {{Timestamp: placedAt, Pod: pod("p3")}},
+	}}
+	tuner.RecordPrediction(pod("p1"), "node-1", 30)
+	tuner.RecordPrediction(pod("p1"), "node-2", 50)
+	tuner.RecordPrediction(pod("p2"), "node-1", 35)
+
+	tuner.collectPlacements(handler)
+	assert.Len(t, tuner.pending, 2)
+	// predictions are consumed once paired with a placement
+	tuner.collectPlacements(handler)
+	assert.Len(t, tuner.pending, 2)
+
+	observe := func(_ klog.Logger, nodeName string) (float64, bool) {
+		return map[string]float64{"node-1": 45}[nodeName], nodeName == "node-1"
+	}
+	tuner.observePlacements(logger, observe)
+	assert.Len(t, tuner.pending, 1)
+	assert.Equal(t, "node-1", tuner.pending[0].nodeName)
+	assert.Equal(t, 35.0, tuner.pending[0].predicted)
+	assert.Equal(t, 1, tuner.samples)
+	assert.Equal(t, 15.0, tuner.errorSum)
+}
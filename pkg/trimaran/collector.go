@@ -18,6 +18,8 @@ package trimaran
 
 import (
 	"fmt"
+	"math"
+	"net/http"
 	"sync"
 	"time"
 
@@ -25,6 +27,7 @@ import (
 	loadwatcherapi "github.com/paypal/load-watcher/pkg/watcher/api"
 
 	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	// pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
 
@@ -34,8 +37,20 @@ import (
 
 const (
 	metricsUpdateIntervalSeconds = 30
+	// healthCheckTimeout is the timeout of the health check of a load watcher endpoint before failing over to it
+	healthCheckTimeout = 5 * time.Second
+	// circuitBreakerThreshold is the number of consecutive metrics updates failing on all the load watcher
+	// endpoints after which the plugins fall back to the requests of the pods
+	circuitBreakerThreshold = 2
 )
 
+// endpoint : a load watcher service, or a metrics provider
+type endpoint struct {
+	// address of the load watcher service, empty for a metrics provider
+	address string
+	client  MetricsProvider
+}
+
 // Collector : get data from load watcher, encapsulating the load watcher and its operations
 //
 // Trimaran plugins have different, potentially conflicting, objectives. Thus, it is recommended not
@@ -43,11 +58,22 @@ const (
 // If a need arises in the future to enable multiple Trimaran plugins, a restructuring to have a single
 // Collector, serving the multiple plugins, may be beneficial for performance reasons.
 type Collector struct {
-	// load watcher client
-	client loadwatcherapi.Client
+	// load watcher endpoints, in order of preference
+	endpoints []endpoint
+	// index of the endpoint metrics were last collected from
+	active int
+	// number of consecutive metrics updates which failed on all the endpoints
+	failures int
+	// client checking the health of the endpoints
+	healthClient http.Client
 	// data collected by load watcher
 	metrics watcher.WatcherMetrics
-	// for safe access to metrics
+	// average CPU utilization (percent) of the nodes in metrics, and the number of nodes it is averaged over
+	clusterCPUUtil  float64
+	clusterCPUNodes int
+	// whether the circuit breaker is open, i.e. all the endpoints are down
+	breakerOpen bool
+	// for safe access to metrics, the cluster utilization and breakerOpen
 	mu sync.RWMutex
 }
 
@@ -57,23 +83,22 @@ func NewCollector(logger klog.Logger, trimaranSpec *pluginConfig.TrimaranSpec) (
 		return nil, err
 	}
 	logger.V(4).Info("Using TrimaranSpec", "type", trimaranSpec.MetricProvider.Type,
-		"address", trimaranSpec.MetricProvider.Address, "watcher", trimaranSpec.WatcherAddress)
-
-	var client loadwatcherapi.Client
-	if trimaranSpec.WatcherAddress != "" {
-		client, _ = loadwatcherapi.NewServiceClient(trimaranSpec.WatcherAddress)
-	} else {
-		opts := watcher.MetricsProviderOpts{
-			Name:               string(trimaranSpec.MetricProvider.Type),
-			Address:            trimaranSpec.MetricProvider.Address,
-			AuthToken:          trimaranSpec.MetricProvider.Token,
-			InsecureSkipVerify: trimaranSpec.MetricProvider.InsecureSkipVerify,
-		}
-		client, _ = loadwatcherapi.NewLibraryClient(opts)
-	}
+		"address", trimaranSpec.MetricProvider.Address, "watcher", trimaranSpec.WatcherAddress,
+		"watcherReplicas", trimaranSpec.WatcherAddresses)
 
 	collector := &Collector{
-		client: client,
+		healthClient: http.Client{Timeout: healthCheckTimeout},
+	}
+	for _, address := range watcherAddresses(trimaranSpec) {
+		client, _ := loadwatcherapi.NewServiceClient(address)
+		collector.endpoints = append(collector.endpoints, endpoint{address: address, client: client})
+	}
+	if len(collector.endpoints) == 0 {
+		client, err := NewMetricsProvider(trimaranSpec.MetricProvider)
+		if err != nil {
+			return nil, err
+		}
+		collector.endpoints = append(collector.endpoints, endpoint{client: client})
 	}
 
 	// populate metrics before returning
@@ -118,29 +143,177 @@ func (collector *Collector) GetNodeMetrics(logger klog.Logger, nodeName string)
 	return allMetrics.Data.NodeMetricsMap[nodeName].Metrics, allMetrics
 }
 
+// GetNodeCPUUtilization : get the measured CPU utilization (percent) of a node from watcher
+func (collector *Collector) GetNodeCPUUtilization(logger klog.Logger, nodeName string) (float64, bool) {
+	metrics, _ := collector.GetNodeMetrics(logger, nodeName)
+	if metrics == nil {
+		return 0, false
+	}
+	cpuUtil, _, ok := GetResourceData(metrics, watcher.CPU)
+	return cpuUtil, ok
+}
+
+// GetClusterCPUUtilization : get the average measured CPU utilization (percent) of the nodes reported by watcher,
+// not available while the circuit breaker is open or before any node is reported
+func (collector *Collector) GetClusterCPUUtilization() (float64, bool) {
+	collector.mu.RLock()
+	defer collector.mu.RUnlock()
+	if collector.breakerOpen || collector.clusterCPUNodes == 0 {
+		return 0, false
+	}
+	return collector.clusterCPUUtil, true
+}
+
+// GetNodeMetricsOrRequests : get metrics for a node from watcher, or, while the circuit breaker is open, i.e. all
+// the load watcher endpoints are down, the utilization of the node implied by the requests of its pods, so that
+// plugins keep scoring nodes on the best information available. The boolean tells whether the requests are used.
+func (collector *Collector) GetNodeMetricsOrRequests(logger klog.Logger, nodeInfo *framework.NodeInfo) ([]watcher.Metric, *watcher.WatcherMetrics, bool) {
+	if !collector.BreakerOpen() {
+		metrics, allMetrics := collector.GetNodeMetrics(logger, nodeInfo.Node().Name)
+		return metrics, allMetrics, false
+	}
+	logger.V(6).Info("Load watcher unavailable; using the requests of the pods", "nodeName", nodeInfo.Node().Name)
+	metrics := RequestedMetrics(nodeInfo)
+	now := time.Now().Unix()
+	allMetrics := &watcher.WatcherMetrics{
+		Window: watcher.Window{Start: now, End: now},
+		Source: "requests",
+		Data: watcher.Data{
+			NodeMetricsMap: map[string]watcher.NodeMetrics{nodeInfo.Node().Name: {Metrics: metrics}},
+		},
+	}
+	return metrics, allMetrics, true
+}
+
+// BreakerOpen : whether the circuit breaker is open, i.e. all the load watcher endpoints are down
+func (collector *Collector) BreakerOpen() bool {
+	collector.mu.RLock()
+	defer collector.mu.RUnlock()
+	return collector.breakerOpen
+}
+
+// RequestedMetrics : the CPU and memory utilization (percent) of a node implied by the requests of its pods,
+// with no variation
+func RequestedMetrics(nodeInfo *framework.NodeInfo) []watcher.Metric {
+	utilization := func(requested, allocatable int64) float64 {
+		if allocatable <= 0 {
+			return 0
+		}
+		return math.Min(float64(requested)/float64(allocatable)*100, 100)
+	}
+	cpu := utilization(nodeInfo.Requested.MilliCPU, nodeInfo.Allocatable.MilliCPU)
+	memory := utilization(nodeInfo.Requested.Memory, nodeInfo.Allocatable.Memory)
+	return []watcher.Metric{
+		{Type: watcher.CPU, Operator: watcher.Average, Value: cpu},
+		{Type: watcher.CPU, Operator: watcher.Std, Value: 0},
+		{Type: watcher.Memory, Operator: watcher.Average, Value: memory},
+		{Type: watcher.Memory, Operator: watcher.Std, Value: 0},
+	}
+}
+
+// watcherAddresses : the addresses of the load watcher service and its replicas, in order of preference
+func watcherAddresses(trimaranSpec *pluginConfig.TrimaranSpec) []string {
+	var addresses []string
+	if trimaranSpec.WatcherAddress != "" {
+		addresses = append(addresses, trimaranSpec.WatcherAddress)
+	}
+	for _, address := range trimaranSpec.WatcherAddresses {
+		if address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
 // checkSpecs : check trimaran specs
 func checkSpecs(trimaranSpec *pluginConfig.TrimaranSpec) error {
-	if trimaranSpec.WatcherAddress == "" {
-		metricProviderType := string(trimaranSpec.MetricProvider.Type)
-		validMetricProviderType := metricProviderType == string(pluginConfig.KubernetesMetricsServer) ||
-			metricProviderType == string(pluginConfig.Prometheus) ||
-			metricProviderType == string(pluginConfig.SignalFx)
-		if !validMetricProviderType {
+	if len(watcherAddresses(trimaranSpec)) == 0 {
+		if !metricsProviderRegistered(trimaranSpec.MetricProvider.Type) {
 			return fmt.Errorf("invalid MetricProvider.Type, got %v", trimaranSpec.MetricProvider.Type)
 		}
+		if trimaranSpec.MetricProvider.Type == pluginConfig.GRPC && trimaranSpec.MetricProvider.Address == "" {
+			return fmt.Errorf("MetricProvider.Address of the %v metrics provider is required", pluginConfig.GRPC)
+		}
 	}
 	return nil
 }
 
 // updateMetrics : request to load watcher to update all metrics
+//
+// The endpoints are tried in order of preference, so that the preferred endpoint is used again once it recovers.
+// The endpoints other than the active one are only used once their health check passes. When all the endpoints
+// fail circuitBreakerThreshold consecutive times, the circuit breaker opens until an endpoint responds again.
 func (collector *Collector) updateMetrics(logger klog.Logger) error {
-	metrics, err := collector.client.GetLatestWatcherMetrics()
+	var err error
+	for i, e := range collector.endpoints {
+		if i != collector.active && !collector.healthy(e) {
+			logger.V(4).Info("Load watcher endpoint unhealthy", "address", e.address)
+			continue
+		}
+		var metrics *watcher.WatcherMetrics
+		metrics, err = e.client.GetLatestWatcherMetrics()
+		if err != nil {
+			logger.Error(err, "Load watcher client failed", "address", e.address)
+			continue
+		}
+		if i != collector.active {
+			logger.Info("Failing over to load watcher endpoint", "address", e.address, "previousAddress", collector.endpoints[collector.active].address)
+			collector.active = i
+		}
+		collector.failures = 0
+		clusterCPUUtil, clusterCPUNodes := clusterCPUUtilization(metrics)
+		collector.mu.Lock()
+		if collector.breakerOpen {
+			logger.Info("Load watcher available again; closing the circuit breaker", "address", e.address)
+		}
+		collector.metrics = *metrics
+		collector.clusterCPUUtil, collector.clusterCPUNodes = clusterCPUUtil, clusterCPUNodes
+		collector.breakerOpen = false
+		collector.mu.Unlock()
+		return nil
+	}
+	if err == nil {
+		err = fmt.Errorf("no healthy load watcher endpoint")
+	}
+
+	collector.failures++
+	if collector.failures >= circuitBreakerThreshold {
+		collector.mu.Lock()
+		if !collector.breakerOpen {
+			logger.Error(err, "All load watcher endpoints are down; opening the circuit breaker, plugins fall back to the requests of the pods",
+				"failures", collector.failures)
+		}
+		collector.breakerOpen = true
+		collector.mu.Unlock()
+	}
+	return err
+}
+
+// clusterCPUUtilization : the average CPU utilization (percent) of the nodes with a CPU metric, and their number
+func clusterCPUUtilization(metrics *watcher.WatcherMetrics) (float64, int) {
+	var total float64
+	var nodes int
+	for _, nodeMetrics := range metrics.Data.NodeMetricsMap {
+		if cpuUtil, _, ok := GetResourceData(nodeMetrics.Metrics, watcher.CPU); ok {
+			total += cpuUtil
+			nodes++
+		}
+	}
+	if nodes == 0 {
+		return 0, 0
+	}
+	return total / float64(nodes), nodes
+}
+
+// healthy : whether the health check of the endpoint passes, always true for the metrics providers
+func (collector *Collector) healthy(e endpoint) bool {
+	if e.address == "" {
+		return true
+	}
+	resp, err := collector.healthClient.Get(e.address + watcher.HealthCheckUrl)
 	if err != nil {
-		logger.Error(err, "Load watcher client failed")
-		return err
+		return false
 	}
-	collector.mu.Lock()
-	collector.metrics = *metrics
-	collector.mu.Unlock()
-	return nil
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
 }
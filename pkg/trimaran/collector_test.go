@@ -25,7 +25,11 @@ import (
 
 	"github.com/paypal/load-watcher/pkg/watcher"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
 )
@@ -215,3 +219,163 @@ func TestNewCollectorLoadWatcher(t *testing.T) {
 	assert.NotNil(t, col)
 	assert.Nil(t, err)
 }
+
+func TestCollectorFailover(t *testing.T) {
+	primaryUp := true
+	primary := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !primaryUp {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		bytes, err := json.Marshal(watcherResponse)
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+	defer primary.Close()
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == watcher.HealthCheckUrl {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		t.Errorf("unexpected request to the unhealthy endpoint: %v", req.URL.Path)
+	}))
+	defer unhealthy.Close()
+	replica := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		bytes, err := json.Marshal(noWatcherResponseForNode)
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+	defer replica.Close()
+
+	trimaranSpec := pluginConfig.TrimaranSpec{
+		WatcherAddress:   primary.URL,
+		WatcherAddresses: []string{unhealthy.URL, replica.URL},
+	}
+	logger := klog.FromContext(context.TODO())
+	collector, err := NewCollector(logger, &trimaranSpec)
+	assert.Nil(t, err)
+	assert.EqualValues(t, &watcherResponse, collector.getAllMetrics())
+
+	// the primary endpoint is down: fail over to the healthy replica
+	primaryUp = false
+	assert.Nil(t, collector.updateMetrics(logger))
+	assert.Equal(t, 2, collector.active)
+	assert.EqualValues(t, &noWatcherResponseForNode, collector.getAllMetrics())
+
+	// the primary endpoint is back
+	primaryUp = true
+	assert.Nil(t, collector.updateMetrics(logger))
+	assert.Equal(t, 0, collector.active)
+	assert.EqualValues(t, &watcherResponse, collector.getAllMetrics())
+}
+
+func TestCollectorCircuitBreaker(t *testing.T) {
+	up := true
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !up {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		bytes, err := json.Marshal(watcherResponse)
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+	defer server.Close()
+
+	trimaranSpec := pluginConfig.TrimaranSpec{
+		WatcherAddress: server.URL,
+	}
+	logger := klog.FromContext(context.TODO())
+	collector, err := NewCollector(logger, &trimaranSpec)
+	assert.Nil(t, err)
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("1"),
+						v1.ResourceMemory: resource.MustParse("4Gi"),
+					},
+				},
+			}},
+		},
+	}
+	nodeInfo := framework.NewNodeInfo(pod)
+	nodeInfo.SetNode(node)
+
+	metrics, _, fromRequests := collector.GetNodeMetricsOrRequests(logger, nodeInfo)
+	assert.False(t, fromRequests)
+	assert.EqualValues(t, watcherResponse.Data.NodeMetricsMap["node-1"].Metrics, metrics)
+
+	// a single failure keeps the last metrics
+	up = false
+	assert.NotNil(t, collector.updateMetrics(logger))
+	assert.False(t, collector.BreakerOpen())
+
+	assert.NotNil(t, collector.updateMetrics(logger))
+	assert.True(t, collector.BreakerOpen())
+	metrics, _, fromRequests = collector.GetNodeMetricsOrRequests(logger, nodeInfo)
+	assert.True(t, fromRequests)
+	assert.EqualValues(t, []watcher.Metric{
+		{Type: watcher.CPU, Operator: watcher.Average, Value: 25},
+		{Type: watcher.CPU, Operator: watcher.Std, Value: 0},
+		{Type: watcher.Memory, Operator: watcher.Average, Value: 50},
+		{Type: watcher.Memory, Operator: watcher.Std, Value: 0},
+	}, metrics)
+
+	up = true
+	assert.Nil(t, collector.updateMetrics(logger))
+	assert.False(t, collector.BreakerOpen())
+}
+
+func TestGetClusterCPUUtilization(t *testing.T) {
+	up := true
+	response := watcher.WatcherMetrics{
+		Data: watcher.Data{
+			NodeMetricsMap: map[string]watcher.NodeMetrics{
+				"node-1": watcherResponse.Data.NodeMetricsMap["node-1"],
+				"node-2": {Metrics: []watcher.Metric{{Type: watcher.CPU, Operator: watcher.Latest, Value: 40}}},
+				"node-3": {Metrics: []watcher.Metric{{Type: watcher.Memory, Operator: watcher.Average, Value: 90}}},
+			},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !up {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		bytes, err := json.Marshal(response)
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+	defer server.Close()
+
+	trimaranSpec := pluginConfig.TrimaranSpec{
+		WatcherAddress: server.URL,
+	}
+	logger := klog.FromContext(context.TODO())
+	collector, err := NewCollector(logger, &trimaranSpec)
+	assert.Nil(t, err)
+
+	// the nodes without CPU metric are not averaged
+	cpuUtil, ok := collector.GetClusterCPUUtilization()
+	assert.True(t, ok)
+	assert.Equal(t, float64(60), cpuUtil)
+
+	up = false
+	for i := 0; i < circuitBreakerThreshold; i++ {
+		assert.NotNil(t, collector.updateMetrics(logger))
+	}
+	_, ok = collector.GetClusterCPUUtilization()
+	assert.False(t, ok)
+}
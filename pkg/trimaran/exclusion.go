@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	pluginConfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// NeutralScore : score given to the excluded nodes, favoring none of them over the scored nodes
+const NeutralScore = (framework.MaxNodeScore + framework.MinNodeScore) / 2
+
+// NodeExclusion : nodes which trimaran plugins give a neutral score to rather than scoring them by utilization,
+// e.g. dedicated node pools managed by other policies in multi-profile setups
+type NodeExclusion struct {
+	selector labels.Selector
+	taints   []v1.Taint
+}
+
+// NewNodeExclusion : create the node exclusion of the spec, nil when the spec is nil
+func NewNodeExclusion(spec *pluginConfig.NodeExclusionSpec) (*NodeExclusion, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	exclusion := &NodeExclusion{
+		selector: labels.Nothing(),
+		taints:   spec.Taints,
+	}
+	if spec.NodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ExcludedNodes.NodeSelector: %w", err)
+		}
+		exclusion.selector = selector
+	}
+	return exclusion, nil
+}
+
+// Excludes : check if the node matches the label selector or has one of the taints of the exclusion
+func (e *NodeExclusion) Excludes(node *v1.Node) bool {
+	if e == nil || node == nil {
+		return false
+	}
+	if e.selector.Matches(labels.Set(node.Labels)) {
+		return true
+	}
+	for i := range node.Spec.Taints {
+		for _, taint := range e.taints {
+			if matchesTaint(&node.Spec.Taints[i], &taint) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesTaint : check if the node taint has the key of the excluded taint, and its value and effect when set
+func matchesTaint(nodeTaint, excluded *v1.Taint) bool {
+	return nodeTaint.Key == excluded.Key &&
+		(excluded.Value == "" || nodeTaint.Value == excluded.Value) &&
+		(excluded.Effect == "" || nodeTaint.Effect == excluded.Effect)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	pluginConfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+func TestNewNodeExclusion(t *testing.T) {
+	exclusion, err := NewNodeExclusion(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, exclusion)
+
+	_, err = NewNodeExclusion(&pluginConfig.NodeExclusionSpec{
+		NodeSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "pool", Operator: "Unknown"}},
+		},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestNodeExclusionExcludes(t *testing.T) {
+	spec := &pluginConfig.NodeExclusionSpec{
+		NodeSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"pool": "dedicated"},
+		},
+		Taints: []v1.Taint{
+			{Key: "gpu"},
+			{Key: "team", Value: "ml", Effect: v1.TaintEffectNoSchedule},
+		},
+	}
+	tests := []struct {
+		name     string
+		spec     *pluginConfig.NodeExclusionSpec
+		node     *v1.Node
+		expected bool
+	}{
+		{
+			name:     "no exclusion",
+			node:     st.MakeNode().Name("node").Label("pool", "dedicated").Obj(),
+			expected: false,
+		},
+		{
+			name:     "node matching the selector",
+			spec:     spec,
+			node:     st.MakeNode().Name("node").Label("pool", "dedicated").Obj(),
+			expected: true,
+		},
+		{
+			name:     "node not matching the selector",
+			spec:     spec,
+			node:     st.MakeNode().Name("node").Label("pool", "shared").Obj(),
+			expected: false,
+		},
+		{
+			name:     "node with a taint matching by key",
+			spec:     spec,
+			node:     st.MakeNode().Name("node").Taints([]v1.Taint{{Key: "gpu", Value: "a100", Effect: v1.TaintEffectNoExecute}}).Obj(),
+			expected: true,
+		},
+		{
+			name:     "node with a taint matching by key, value and effect",
+			spec:     spec,
+			node:     st.MakeNode().Name("node").Taints([]v1.Taint{{Key: "team", Value: "ml", Effect: v1.TaintEffectNoSchedule}}).Obj(),
+			expected: true,
+		},
+		{
+			name:     "node with a taint of another value",
+			spec:     spec,
+			node:     st.MakeNode().Name("node").Taints([]v1.Taint{{Key: "team", Value: "web", Effect: v1.TaintEffectNoSchedule}}).Obj(),
+			expected: false,
+		},
+		{
+			name: "taints only",
+			spec: &pluginConfig.NodeExclusionSpec{
+				Taints: []v1.Taint{{Key: "gpu"}},
+			},
+			node:     st.MakeNode().Name("node").Label("pool", "dedicated").Obj(),
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exclusion, err := NewNodeExclusion(tt.spec)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, exclusion.Excludes(tt.node))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"time"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	pluginConfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/util"
+)
+
+const (
+	// MetricsServiceName : name of the gRPC service providing the node metrics
+	MetricsServiceName = "trimaran.Metrics"
+
+	getLatestWatcherMetricsMethod = "/" + MetricsServiceName + "/GetLatestWatcherMetrics"
+
+	// grpcTimeout is the timeout of a call to the gRPC metrics service
+	grpcTimeout = 10 * time.Second
+)
+
+// GetLatestWatcherMetricsRequest : request of the latest node metrics, empty
+type GetLatestWatcherMetricsRequest struct{}
+
+// MetricsServer : server API of the gRPC metrics service, the response being the node metrics
+// in the format of the load watcher
+type MetricsServer interface {
+	GetLatestWatcherMetrics(context.Context, *GetLatestWatcherMetricsRequest) (*watcher.WatcherMetrics, error)
+}
+
+// RegisterMetricsServer : register the metrics service on a gRPC server, created with the JSON codec of the
+// service as by NewGRPCServer
+func RegisterMetricsServer(s grpc.ServiceRegistrar, srv MetricsServer) {
+	s.RegisterService(&metricsServiceDesc, srv)
+}
+
+// NewGRPCServer : create a gRPC server serving the metrics service with its JSON codec, the messages being JSON
+// encoded as by the load watcher service, and the given options
+func NewGRPCServer(srv MetricsServer, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(append([]grpc.ServerOption{grpc.ForceServerCodec(util.JSONCodec{})}, opts...)...)
+	RegisterMetricsServer(s, srv)
+	return s
+}
+
+var metricsServiceDesc = grpc.ServiceDesc{
+	ServiceName: MetricsServiceName,
+	HandlerType: (*MetricsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLatestWatcherMetrics",
+			Handler:    getLatestWatcherMetricsHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+func getLatestWatcherMetricsHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestWatcherMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServer).GetLatestWatcherMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: getLatestWatcherMetricsMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServer).GetLatestWatcherMetrics(ctx, req.(*GetLatestWatcherMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GRPCMetricsProvider : MetricsProvider calling an external gRPC metrics service
+type GRPCMetricsProvider struct {
+	cc grpc.ClientConnInterface
+	// bearer token sent in the authorization metadata, if any
+	token string
+}
+
+var _ MetricsProvider = &GRPCMetricsProvider{}
+
+// NewGRPCMetricsProvider : create a provider calling the gRPC metrics service at the address of the spec.
+// The connection is in plaintext if the address starts with http://, and over TLS otherwise.
+func NewGRPCMetricsProvider(spec pluginConfig.MetricProviderSpec) (MetricsProvider, error) {
+	target, plaintext := grpcTarget(spec.Address)
+	transportCredentials := insecure.NewCredentials()
+	if !plaintext {
+		transportCredentials = credentials.NewTLS(&tls.Config{InsecureSkipVerify: spec.InsecureSkipVerify})
+	}
+	cc, err := grpc.NewClient(target, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, err
+	}
+	return NewGRPCMetricsProviderWithConn(cc, spec.Token), nil
+}
+
+// NewGRPCMetricsProviderWithConn : create a provider calling the gRPC metrics service over the connection
+func NewGRPCMetricsProviderWithConn(cc grpc.ClientConnInterface, token string) *GRPCMetricsProvider {
+	return &GRPCMetricsProvider{cc: cc, token: token}
+}
+
+// GetLatestWatcherMetrics : the latest metrics of all the nodes
+func (p *GRPCMetricsProvider) GetLatestWatcherMetrics() (*watcher.WatcherMetrics, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), grpcTimeout)
+	defer cancel()
+	if p.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+p.token)
+	}
+	out := new(watcher.WatcherMetrics)
+	if err := p.cc.Invoke(ctx, getLatestWatcherMetricsMethod, &GetLatestWatcherMetricsRequest{}, out,
+		grpc.ForceCodec(util.JSONCodec{})); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// grpcTarget : the gRPC target of the address of the metrics provider, and whether it is in plaintext
+func grpcTarget(address string) (string, bool) {
+	if target, ok := strings.CutPrefix(address, "http://"); ok {
+		return target, true
+	}
+	return strings.TrimPrefix(address, "https://"), false
+}
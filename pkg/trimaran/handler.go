@@ -23,6 +23,7 @@ package trimaran
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -32,6 +33,8 @@ import (
 	clientcache "k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/sharedstate"
 )
 
 const (
@@ -128,14 +131,23 @@ func (p *PodAssignEventHandler) OnDelete(obj interface{}) {
 	}
 }
 
+// updateCache adds the pod with the time it was bound, so that the cache is the same on every replica of the
+// scheduler, falling back to the current time for the pods without one. The pods of a node stay sorted by time.
 func (p *PodAssignEventHandler) updateCache(pod *v1.Pod) {
 	if pod.Spec.NodeName == "" {
 		return
 	}
+	timestamp, ok := sharedstate.BoundTime(pod)
+	if !ok {
+		timestamp = time.Now()
+	}
 	p.Lock()
-	p.ScheduledPodsCache[pod.Spec.NodeName] = append(p.ScheduledPodsCache[pod.Spec.NodeName],
-		podInfo{Timestamp: time.Now(), Pod: pod})
-	p.Unlock()
+	defer p.Unlock()
+	cache := p.ScheduledPodsCache[pod.Spec.NodeName]
+	idx := sort.Search(len(cache), func(i int) bool {
+		return cache[i].Timestamp.After(timestamp)
+	})
+	p.ScheduledPodsCache[pod.Spec.NodeName] = slices.Insert(cache, idx, podInfo{Timestamp: timestamp, Pod: pod})
 }
 
 // Deletes podInfo entries that are older than metricsAgentReportingIntervalSeconds. Also deletes node entry if empty
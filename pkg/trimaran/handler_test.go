@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
 )
 
@@ -75,3 +77,35 @@ func TestHandlerCacheCleanup(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerCacheBoundTime(t *testing.T) {
+	testNode := "node-1"
+	now := time.Now()
+	bound := func(name string, at time.Time) *v1.Pod {
+		pod := st.MakePod().Name(name).Node(testNode).Obj()
+		pod.Status.Conditions = []v1.PodCondition{
+			{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(at)},
+		}
+		return pod
+	}
+
+	// the pods are listed by a replica starting, in any order: they are cached with the time they were bound
+	p := New()
+	p.OnAdd(bound("Pod-1", now.Add(-10*time.Second)), true)
+	p.OnAdd(bound("Pod-2", now.Add(-5*time.Minute)), true)
+	p.OnAdd(bound("Pod-3", now.Add(-20*time.Second)), true)
+	var names []string
+	for _, v := range p.ScheduledPodsCache[testNode] {
+		names = append(names, v.Pod.Name)
+	}
+	assert.Equal(t, []string{"Pod-2", "Pod-3", "Pod-1"}, names)
+	assert.True(t, p.ScheduledPodsCache[testNode][0].Timestamp.Equal(now.Add(-5*time.Minute)))
+
+	// the pods bound before the last reporting interval are cleaned up
+	p.cleanupCache()
+	names = nil
+	for _, v := range p.ScheduledPodsCache[testNode] {
+		names = append(names, v.Pod.Name)
+	}
+	assert.Equal(t, []string{"Pod-3", "Pod-1"}, names)
+}
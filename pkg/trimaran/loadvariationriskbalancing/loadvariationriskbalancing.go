@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/paypal/load-watcher/pkg/watcher"
 
@@ -51,6 +52,12 @@ type LoadVariationRiskBalancing struct {
 	eventHandler *trimaran.PodAssignEventHandler
 	collector    *trimaran.Collector
 	args         *pluginConfig.LoadVariationRiskBalancingArgs
+	// adjusts the safe variance margin when auto-tuning is enabled
+	tuner *trimaran.CoefficientTuner
+	// nodes given a neutral score
+	exclusion *trimaran.NodeExclusion
+	// multipliers of the safe variance margin by namespace and priority class
+	tolerance *riskTolerance
 }
 
 var _ framework.ScorePlugin = &LoadVariationRiskBalancing{}
@@ -68,7 +75,18 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if err != nil {
 		return nil, err
 	}
-	logger.V(4).Info("Using LoadVariationRiskBalancingArgs", "margin", args.SafeVarianceMargin, "sensitivity", args.SafeVarianceSensitivity)
+	exclusion, err := trimaran.NewNodeExclusion(args.ExcludedNodes)
+	if err != nil {
+		return nil, err
+	}
+	tolerance, err := newRiskTolerance(args.NamespaceRiskMultipliers, args.PriorityClassRiskMultipliers,
+		handle.SharedInformerFactory().Core().V1().Namespaces().Lister())
+	if err != nil {
+		return nil, err
+	}
+	logger.V(4).Info("Using LoadVariationRiskBalancingArgs", "margin", args.SafeVarianceMargin, "sensitivity", args.SafeVarianceSensitivity,
+		"warmUpSeconds", args.WarmUpSeconds, "warmUpDiscount", args.WarmUpDiscount,
+		"namespaceRiskMultipliers", args.NamespaceRiskMultipliers, "priorityClassRiskMultipliers", args.PriorityClassRiskMultipliers)
 
 	podAssignEventHandler := trimaran.New()
 	podAssignEventHandler.AddToHandle(handle)
@@ -78,6 +96,13 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		eventHandler: podAssignEventHandler,
 		collector:    collector,
 		args:         args,
+		exclusion:    exclusion,
+		tolerance:    tolerance,
+	}
+	if args.AutoTune != nil {
+		pl.tuner = trimaran.NewCoefficientTuner(Name+".safeVarianceMargin", args.SafeVarianceMargin, 0, math.Inf(1),
+			trimaran.IncreaseOnUnderPrediction, args.AutoTune, handle.ClientSet())
+		pl.tuner.Start(ctx, logger, podAssignEventHandler, collector.GetNodeCPUUtilization)
 	}
 	return pl, nil
 }
@@ -91,8 +116,12 @@ func (pl *LoadVariationRiskBalancing) Score(ctx context.Context, cycleState *fra
 	if err != nil {
 		return score, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
+	if pl.exclusion.Excludes(nodeInfo.Node()) {
+		logger.V(6).Info("Excluded node; using neutral score", "nodeName", nodeName)
+		return trimaran.NeutralScore, nil
+	}
 	// get node metrics
-	metrics, _ := pl.collector.GetNodeMetrics(logger, nodeName)
+	metrics, _, _ := pl.collector.GetNodeMetricsOrRequests(logger, nodeInfo)
 	if metrics == nil {
 		logger.Info("Failed to get metrics for node; using minimum score", "nodeName", nodeName)
 		return score, nil
@@ -100,18 +129,33 @@ func (pl *LoadVariationRiskBalancing) Score(ctx context.Context, cycleState *fra
 	podRequest := trimaran.GetResourceRequested(pod)
 	node := nodeInfo.Node()
 
+	margin := pl.args.SafeVarianceMargin
+	if pl.tuner != nil {
+		margin = pl.tuner.Value()
+	}
+	margin *= pl.tolerance.multiplier(logger, pod)
+
+	// requests of the pods in warm-up, whose load is discounted
+	warmReq, totalReq := warmUpRequests(nodeInfo.Pods, time.Now(), time.Duration(pl.args.WarmUpSeconds)*time.Second)
+
 	// calculate CPU score
 	var cpuScore float64 = 0
 	cpuStats, cpuOK := trimaran.CreateResourceStats(logger, metrics, node, podRequest, v1.ResourceCPU, watcher.CPU)
 	if cpuOK {
-		cpuScore = computeScore(logger, cpuStats, pl.args.SafeVarianceMargin, pl.args.SafeVarianceSensitivity)
+		discountWarmUp(cpuStats, float64(warmReq.MilliCPU), float64(totalReq.MilliCPU), pl.args.WarmUpDiscount)
+		if pl.tuner != nil {
+			mu, _ := trimaran.GetMuSigma(cpuStats)
+			pl.tuner.RecordPrediction(pod, nodeName, 100*mu)
+		}
+		cpuScore = computeScore(logger, cpuStats, margin, pl.args.SafeVarianceSensitivity)
 	}
 	logger.V(6).Info("Calculating CPUScore", "pod", klog.KObj(pod), "nodeName", nodeName, "cpuScore", cpuScore)
 	// calculate Memory score
 	var memoryScore float64 = 0
 	memoryStats, memoryOK := trimaran.CreateResourceStats(logger, metrics, node, podRequest, v1.ResourceMemory, watcher.Memory)
 	if memoryOK {
-		memoryScore = computeScore(logger, memoryStats, pl.args.SafeVarianceMargin, pl.args.SafeVarianceSensitivity)
+		discountWarmUp(memoryStats, float64(warmReq.Memory)*trimaran.MegaFactor, float64(totalReq.Memory)*trimaran.MegaFactor, pl.args.WarmUpDiscount)
+		memoryScore = computeScore(logger, memoryStats, margin, pl.args.SafeVarianceSensitivity)
 	}
 	logger.V(6).Info("Calculating MemoryScore", "pod", klog.KObj(pod), "nodeName", nodeName, "memoryScore", memoryScore)
 	// calculate total score
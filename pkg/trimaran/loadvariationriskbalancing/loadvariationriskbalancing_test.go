@@ -139,11 +139,12 @@ func TestScore(t *testing.T) {
 	var mega int64 = 1024 * 1024
 
 	tests := []struct {
-		test            string
-		pod             *v1.Pod
-		nodes           []*v1.Node
-		watcherResponse watcher.WatcherMetrics
-		expected        framework.NodeScoreList
+		test                     string
+		pod                      *v1.Pod
+		nodes                    []*v1.Node
+		namespaceRiskMultipliers map[string]float64
+		watcherResponse          watcher.WatcherMetrics
+		expected                 framework.NodeScoreList
 	}{
 		{
 			test: "new node",
@@ -322,6 +323,71 @@ func TestScore(t *testing.T) {
 				{Name: "node-1", Score: framework.MinNodeScore},
 			},
 		},
+		{
+			test: "variable node",
+			pod:  st.MakePod().Name("p").Namespace("batch").Obj(),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(nodeResources).Obj(),
+			},
+			namespaceRiskMultipliers: map[string]float64{"critical": 2},
+			watcherResponse: watcher.WatcherMetrics{
+				Window: watcher.Window{},
+				Data: watcher.Data{
+					NodeMetricsMap: map[string]watcher.NodeMetrics{
+						"node-1": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Operator: watcher.Average,
+									Value:    50,
+								},
+								{
+									Type:     watcher.CPU,
+									Operator: watcher.Std,
+									Value:    10,
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 70},
+			},
+		},
+		{
+			test: "variable node for a risk averse namespace",
+			pod:  st.MakePod().Name("p").Namespace("critical").Obj(),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(nodeResources).Obj(),
+			},
+			namespaceRiskMultipliers: map[string]float64{"critical": 2},
+			watcherResponse: watcher.WatcherMetrics{
+				Window: watcher.Window{},
+				Data: watcher.Data{
+					NodeMetricsMap: map[string]watcher.NodeMetrics{
+						"node-1": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Operator: watcher.Average,
+									Value:    50,
+								},
+								{
+									Type:     watcher.CPU,
+									Operator: watcher.Std,
+									Value:    10,
+								},
+							},
+						},
+					},
+				},
+			},
+			// the standard deviation weighs twice as much in the risk
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 65},
+			},
+		},
 	}
 
 	registeredPlugins := []tf.RegisterPluginFunc{
@@ -345,9 +411,10 @@ func TestScore(t *testing.T) {
 			state := framework.NewCycleState()
 
 			loadVariationRiskBalancingArgs := pluginConfig.LoadVariationRiskBalancingArgs{
-				TrimaranSpec:            pluginConfig.TrimaranSpec{WatcherAddress: server.URL},
-				SafeVarianceMargin:      cfgv1.DefaultSafeVarianceMargin,
-				SafeVarianceSensitivity: cfgv1.DefaultSafeVarianceSensitivity,
+				TrimaranSpec:             pluginConfig.TrimaranSpec{WatcherAddress: server.URL},
+				SafeVarianceMargin:       cfgv1.DefaultSafeVarianceMargin,
+				SafeVarianceSensitivity:  cfgv1.DefaultSafeVarianceSensitivity,
+				NamespaceRiskMultipliers: tt.namespaceRiskMultipliers,
 			}
 			loadVariationRiskBalancingConfig := config.PluginConfig{
 				Name: Name,
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadvariationriskbalancing
+
+import (
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+)
+
+/*
+Risk tolerance of the pods, by namespace and priority class
+*/
+
+// RiskMultiplierAnnotation sets the multiplier of the safe variance margin for the pods of a namespace, when set
+// on the namespace. Latency-critical namespaces are placed more conservatively with a multiplier above 1, and
+// batch namespaces tolerate a higher variance with a multiplier below 1.
+const RiskMultiplierAnnotation = "trimaran.scheduling.x-k8s.io/risk-multiplier"
+
+// riskTolerance : multipliers of the safe variance margin of the pods
+type riskTolerance struct {
+	namespaces      map[string]float64
+	priorityClasses map[string]float64
+	namespaceLister listersv1.NamespaceLister
+}
+
+// newRiskTolerance : create the risk tolerance of the pods from the multipliers configured
+func newRiskTolerance(namespaces, priorityClasses map[string]float64, namespaceLister listersv1.NamespaceLister) (*riskTolerance, error) {
+	for name, multiplier := range namespaces {
+		if multiplier < 0 {
+			return nil, fmt.Errorf("invalid risk multiplier %v of namespace %q, must not be negative", multiplier, name)
+		}
+	}
+	for name, multiplier := range priorityClasses {
+		if multiplier < 0 {
+			return nil, fmt.Errorf("invalid risk multiplier %v of priority class %q, must not be negative", multiplier, name)
+		}
+	}
+	return &riskTolerance{
+		namespaces:      namespaces,
+		priorityClasses: priorityClasses,
+		namespaceLister: namespaceLister,
+	}, nil
+}
+
+// multiplier : the multiplier of the safe variance margin for a pod, by order of precedence the one annotated on
+// its namespace, configured for its namespace, configured for its priority class, or 1
+func (r *riskTolerance) multiplier(logger klog.Logger, pod *v1.Pod) float64 {
+	if namespace, err := r.namespaceLister.Get(pod.Namespace); err == nil {
+		if value, ok := namespace.Annotations[RiskMultiplierAnnotation]; ok {
+			multiplier, err := strconv.ParseFloat(value, 64)
+			if err == nil && multiplier >= 0 {
+				return multiplier
+			}
+			logger.V(4).Info("Ignoring invalid risk multiplier annotation", "namespace", pod.Namespace, "value", value)
+		}
+	}
+	if multiplier, ok := r.namespaces[pod.Namespace]; ok {
+		return multiplier
+	}
+	if multiplier, ok := r.priorityClasses[pod.Spec.PriorityClassName]; ok {
+		return multiplier
+	}
+	return 1
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadvariationriskbalancing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func TestRiskToleranceMultiplier(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, namespace := range []*v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "payments", Annotations: map[string]string{RiskMultiplierAnnotation: "3"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "search"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "broken", Annotations: map[string]string{RiskMultiplierAnnotation: "-1"}}},
+	} {
+		assert.Nil(t, indexer.Add(namespace))
+	}
+	tolerance, err := newRiskTolerance(
+		map[string]float64{"payments": 2, "search": 1.5, "broken": 1.2},
+		map[string]float64{"batch": 0.5},
+		listersv1.NewNamespaceLister(indexer))
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected float64
+	}{
+		{
+			name:     "namespace annotation",
+			pod:      withPriorityClass(st.MakePod().Namespace("payments").Obj(), "batch"),
+			expected: 3,
+		},
+		{
+			name:     "namespace multiplier",
+			pod:      withPriorityClass(st.MakePod().Namespace("search").Obj(), "batch"),
+			expected: 1.5,
+		},
+		{
+			name:     "invalid namespace annotation ignored",
+			pod:      st.MakePod().Namespace("broken").Obj(),
+			expected: 1.2,
+		},
+		{
+			name:     "priority class multiplier",
+			pod:      withPriorityClass(st.MakePod().Namespace("default").Obj(), "batch"),
+			expected: 0.5,
+		},
+		{
+			name:     "no multiplier",
+			pod:      st.MakePod().Namespace("default").Obj(),
+			expected: 1,
+		},
+	}
+	logger := klog.FromContext(context.TODO())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tolerance.multiplier(logger, tt.pod))
+		})
+	}
+}
+
+func TestNewRiskToleranceInvalid(t *testing.T) {
+	lister := listersv1.NewNamespaceLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}))
+	_, err := newRiskTolerance(map[string]float64{"payments": -1}, nil, lister)
+	assert.NotNil(t, err)
+	_, err = newRiskTolerance(nil, map[string]float64{"batch": -0.5}, lister)
+	assert.NotNil(t, err)
+}
+
+func withPriorityClass(pod *v1.Pod, priorityClassName string) *v1.Pod {
+	pod.Spec.PriorityClassName = priorityClassName
+	return pod
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadvariationriskbalancing
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran"
+)
+
+/*
+Discount of the load of pods in warm-up from the node statistics
+*/
+
+// WarmUpAnnotation flags a pod as in warm-up when set to "true", whatever its start time.
+const WarmUpAnnotation = "trimaran.scheduling.x-k8s.io/warm-up"
+
+// inWarmUp : check if a pod is in warm-up, i.e. started for less than warmUp or annotated as warming up
+func inWarmUp(pod *v1.Pod, now time.Time, warmUp time.Duration) bool {
+	if pod.Annotations[WarmUpAnnotation] == "true" {
+		return true
+	}
+	if warmUp <= 0 || pod.Status.StartTime == nil {
+		return false
+	}
+	return now.Sub(pod.Status.StartTime.Time) < warmUp
+}
+
+// warmUpRequests : requests of the pods in warm-up on a node, and of all pods on the node
+func warmUpRequests(podInfos []*framework.PodInfo, now time.Time, warmUp time.Duration) (warm, total *framework.Resource) {
+	warm = &framework.Resource{}
+	total = &framework.Resource{}
+	for _, podInfo := range podInfos {
+		requested := trimaran.GetResourceRequested(podInfo.Pod)
+		total.MilliCPU += requested.MilliCPU
+		total.Memory += requested.Memory
+		if inWarmUp(podInfo.Pod, now, warmUp) {
+			warm.MilliCPU += requested.MilliCPU
+			warm.Memory += requested.Memory
+		}
+	}
+	return warm, total
+}
+
+// discountWarmUp : discount the warm-up spikes of pods from the usage statistics of a node
+// - the share of the pods in warm-up in the node usage is estimated by their share in the node requests
+// - their usage above their requests is discounted from the average, by the fraction discount
+// - their share of the standard deviation is discounted, by the fraction discount
+func discountWarmUp(rs *trimaran.ResourceStats, warmReq, totalReq, discount float64) {
+	if warmReq <= 0 || totalReq <= 0 || discount <= 0 {
+		return
+	}
+	share := min(warmReq/totalReq, 1)
+	excess := max(share*rs.UsedAvg-warmReq, 0)
+	rs.UsedAvg -= discount * excess
+	rs.UsedStdev *= 1 - discount*share
+}
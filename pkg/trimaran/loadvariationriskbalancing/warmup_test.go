@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadvariationriskbalancing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran"
+)
+
+func TestWarmUpRequests(t *testing.T) {
+	now := time.Now()
+	started := func(pod *v1.Pod, ago time.Duration) *v1.Pod {
+		pod.Status.StartTime = &metav1.Time{Time: now.Add(-ago)}
+		return pod
+	}
+	podInfos := []*framework.PodInfo{
+		{Pod: started(st.MakePod().Name("fresh").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1", v1.ResourceMemory: "1Gi"}).Obj(), time.Minute)},
+		{Pod: started(st.MakePod().Name("old").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2", v1.ResourceMemory: "2Gi"}).Obj(), time.Hour)},
+		{Pod: started(st.MakePod().Name("flagged").Annotation(WarmUpAnnotation, "true").Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj(), time.Hour)},
+		{Pod: st.MakePod().Name("pending").Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj()},
+	}
+
+	tests := []struct {
+		name     string
+		warmUp   time.Duration
+		expected *framework.Resource
+	}{
+		{
+			name:     "annotated pods only",
+			warmUp:   0,
+			expected: &framework.Resource{MilliCPU: 500},
+		},
+		{
+			name:     "recently started and annotated pods",
+			warmUp:   5 * time.Minute,
+			expected: &framework.Resource{MilliCPU: 1500, Memory: 1 << 30},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warm, total := warmUpRequests(podInfos, now, tt.warmUp)
+			assert.Equal(t, tt.expected, warm)
+			assert.Equal(t, &framework.Resource{MilliCPU: 4000, Memory: 3 << 30}, total)
+		})
+	}
+}
+
+func TestDiscountWarmUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    trimaran.ResourceStats
+		warmReq  float64
+		totalReq float64
+		discount float64
+		expected trimaran.ResourceStats
+	}{
+		{
+			name:     "no pods in warm-up",
+			stats:    trimaran.ResourceStats{UsedAvg: 2000, UsedStdev: 800, Capacity: 4000},
+			warmReq:  0,
+			totalReq: 2000,
+			discount: 1,
+			expected: trimaran.ResourceStats{UsedAvg: 2000, UsedStdev: 800, Capacity: 4000},
+		},
+		{
+			name:     "usage within requests kept",
+			stats:    trimaran.ResourceStats{UsedAvg: 2000, UsedStdev: 800, Capacity: 4000},
+			warmReq:  500,
+			totalReq: 2000,
+			discount: 1,
+			// share 1/4: 500 used for 500 requested
+			expected: trimaran.ResourceStats{UsedAvg: 2000, UsedStdev: 600, Capacity: 4000},
+		},
+		{
+			name:     "spike above requests fully discounted",
+			stats:    trimaran.ResourceStats{UsedAvg: 3000, UsedStdev: 800, Capacity: 4000},
+			warmReq:  500,
+			totalReq: 2000,
+			discount: 1,
+			// share 1/4: 750 used for 500 requested
+			expected: trimaran.ResourceStats{UsedAvg: 2750, UsedStdev: 600, Capacity: 4000},
+		},
+		{
+			name:     "spike above requests half discounted",
+			stats:    trimaran.ResourceStats{UsedAvg: 3000, UsedStdev: 800, Capacity: 4000},
+			warmReq:  1000,
+			totalReq: 2000,
+			discount: 0.5,
+			// share 1/2: 1500 used above 1000 requested
+			expected: trimaran.ResourceStats{UsedAvg: 2750, UsedStdev: 600, Capacity: 4000},
+		},
+		{
+			name:     "discount disabled",
+			stats:    trimaran.ResourceStats{UsedAvg: 3000, UsedStdev: 800, Capacity: 4000},
+			warmReq:  1000,
+			totalReq: 2000,
+			discount: 0,
+			expected: trimaran.ResourceStats{UsedAvg: 3000, UsedStdev: 800, Capacity: 4000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := tt.stats
+			discountWarmUp(&rs, tt.warmReq, tt.totalReq, tt.discount)
+			assert.InDelta(t, tt.expected.UsedAvg, rs.UsedAvg, 1e-9)
+			assert.InDelta(t, tt.expected.UsedStdev, rs.UsedStdev, 1e-9)
+		})
+	}
+}
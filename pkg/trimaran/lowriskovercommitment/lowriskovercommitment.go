@@ -60,6 +60,10 @@ type LowRiskOverCommitment struct {
 	collector           *trimaran.Collector
 	args                *pluginConfig.LowRiskOverCommitmentArgs
 	riskLimitWeightsMap map[v1.ResourceName]float64
+	// nodes given a neutral score
+	exclusion *trimaran.NodeExclusion
+	// VPA recommendations used as priors of the pod usage, nil if disabled
+	vpa *vpaPriors
 }
 
 // New : create an instance of a LowRiskOverCommitment plugin
@@ -75,6 +79,10 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if err != nil {
 		return nil, err
 	}
+	exclusion, err := trimaran.NewNodeExclusion(args.ExcludedNodes)
+	if err != nil {
+		return nil, err
+	}
 	// create map of resource risk limit weights
 	m := make(map[v1.ResourceName]float64)
 	m[v1.ResourceCPU] = pluginv1.DefaultRiskLimitWeight
@@ -83,13 +91,21 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		m[r] = w
 	}
 	logger.V(4).Info("Using LowRiskOverCommitmentArgs", "smoothingWindowSize", args.SmoothingWindowSize,
-		"riskLimitWeights", m)
+		"riskLimitWeights", m, "useVPARecommendations", args.UseVPARecommendations)
+	var vpa *vpaPriors
+	if args.UseVPARecommendations {
+		if vpa, err = newVPAPriors(ctx, handle); err != nil {
+			return nil, err
+		}
+	}
 
 	pl := &LowRiskOverCommitment{
 		handle:              handle,
 		collector:           collector,
 		args:                args,
 		riskLimitWeightsMap: m,
+		exclusion:           exclusion,
+		vpa:                 vpa,
 	}
 	return pl, nil
 }
@@ -98,7 +114,7 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 func (pl *LowRiskOverCommitment) PreScore(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodes []*framework.NodeInfo) *framework.Status {
 	logger := klog.FromContext(ctx)
 	logger.V(6).Info("PreScore: Calculating pod resource requests and limits", "pod", klog.KObj(pod))
-	podResourcesStateData := CreatePodResourcesStateData(pod)
+	podResourcesStateData := pl.createPodResourcesStateData(logger, pod)
 	cycleState.Write(PodResourcesKey, podResourcesStateData)
 	return nil
 }
@@ -118,7 +134,7 @@ func (pl *LowRiskOverCommitment) Score(ctx context.Context, cycleState *framewor
 	if err != nil {
 		// calculate pod requests and limits, if missing
 		logger.V(6).Info(err.Error()+"; recalculating", "pod", klog.KObj(pod))
-		podResources = CreatePodResourcesStateData(pod)
+		podResources = pl.createPodResourcesStateData(logger, pod)
 	}
 	// exclude scoring for best effort pods; this plugin is not concerned about best effort pods
 	podRequests := &podResources.podRequests
@@ -133,8 +149,13 @@ func (pl *LowRiskOverCommitment) Score(ctx context.Context, cycleState *framewor
 	if err != nil {
 		return score, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
+	if pl.exclusion.Excludes(nodeInfo.Node()) {
+		logger.V(6).Info("Excluded node; using neutral score", "nodeName", nodeName)
+		score = trimaran.NeutralScore
+		return score, nil
+	}
 	// get node metrics
-	metrics, _ := pl.collector.GetNodeMetrics(logger, nodeName)
+	metrics, _, _ := pl.collector.GetNodeMetricsOrRequests(logger, nodeInfo)
 	if metrics == nil {
 		logger.Info("Failed to get metrics for node; using minimum score", "nodeName", nodeName)
 		return score, nil
@@ -261,6 +282,15 @@ func (pl *LowRiskOverCommitment) computeRisk(logger klog.Logger, metrics []watch
 	return totalRisk
 }
 
+// createPodResourcesStateData : calculate pod resource requests and limits, replaced by the recommendations
+// of the VPA of the pod controller if enabled, and store as plugin state data
+func (pl *LowRiskOverCommitment) createPodResourcesStateData(logger klog.Logger, pod *v1.Pod) *PodResourcesStateData {
+	if pl.vpa != nil {
+		pod = pl.vpa.podWithRecommendations(logger, pod)
+	}
+	return CreatePodResourcesStateData(pod)
+}
+
 // CreatePodResourcesStateData : calculate pod resource requests and limits and store as plugin state data
 func CreatePodResourcesStateData(pod *v1.Pod) *PodResourcesStateData {
 	requests := trimaran.GetResourceRequested(pod)
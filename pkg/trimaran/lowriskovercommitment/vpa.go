@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lowriskovercommitment
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// vpaGVR is the resource of the VerticalPodAutoscalers, read as unstructured objects
+// not to depend on the VerticalPodAutoscaler API.
+var vpaGVR = schema.GroupVersionResource{Group: "autoscaling.k8s.io", Version: "v1", Resource: "verticalpodautoscalers"}
+
+// verticalPodAutoscaler : the fields of a VerticalPodAutoscaler used as priors
+type verticalPodAutoscaler struct {
+	Spec struct {
+		TargetRef *autoscalingv1.CrossVersionObjectReference `json:"targetRef,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Recommendation *struct {
+			ContainerRecommendations []containerRecommendation `json:"containerRecommendations,omitempty"`
+		} `json:"recommendation,omitempty"`
+	} `json:"status"`
+}
+
+// containerRecommendation : the resources recommended for a container by a VerticalPodAutoscaler
+type containerRecommendation struct {
+	ContainerName string          `json:"containerName"`
+	Target        v1.ResourceList `json:"target"`
+	UpperBound    v1.ResourceList `json:"upperBound,omitempty"`
+}
+
+// vpaPriors : the recommendations of the VerticalPodAutoscalers of the pod controllers,
+// used as priors of the expected usage of the pods
+type vpaPriors struct {
+	vpaLister cache.GenericLister
+	rsLister  appslisters.ReplicaSetLister
+}
+
+// newVPAPriors : watch the VerticalPodAutoscalers with the kubeconfig of the scheduler
+func newVPAPriors(ctx context.Context, handle framework.Handle) (*vpaPriors, error) {
+	if handle.KubeConfig() == nil {
+		return nil, fmt.Errorf("reading the VerticalPodAutoscalers requires a kubeconfig")
+	}
+	client, err := dynamic.NewForConfig(handle.KubeConfig())
+	if err != nil {
+		return nil, err
+	}
+	informerFactory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	vpaLister := informerFactory.ForResource(vpaGVR).Lister()
+	informerFactory.Start(ctx.Done())
+	return &vpaPriors{
+		vpaLister: vpaLister,
+		rsLister:  handle.SharedInformerFactory().Apps().V1().ReplicaSets().Lister(),
+	}, nil
+}
+
+// podWithRecommendations : return a copy of the pod whose containers request the target recommended by the
+// VerticalPodAutoscaler of the pod controller and are limited to the recommended upper bound, or the pod
+// itself if there is no recommendation for the pod
+func (v *vpaPriors) podWithRecommendations(logger klog.Logger, pod *v1.Pod) *v1.Pod {
+	recommendations := v.recommendationsOf(logger, pod)
+	if len(recommendations) == 0 {
+		return pod
+	}
+	pod = pod.DeepCopy()
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		recommendation, ok := recommendations[container.Name]
+		if !ok {
+			continue
+		}
+		for _, resourceName := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			if target, ok := recommendation.Target[resourceName]; ok {
+				if container.Resources.Requests == nil {
+					container.Resources.Requests = v1.ResourceList{}
+				}
+				container.Resources.Requests[resourceName] = target
+			}
+			if upperBound, ok := recommendation.UpperBound[resourceName]; ok {
+				if container.Resources.Limits == nil {
+					container.Resources.Limits = v1.ResourceList{}
+				}
+				container.Resources.Limits[resourceName] = upperBound
+			}
+		}
+	}
+	logger.V(6).Info("Using VPA recommendations as priors", "pod", klog.KObj(pod), "recommendations", recommendations)
+	return pod
+}
+
+// recommendationsOf : the recommendations per container of the VerticalPodAutoscaler of the pod controller
+func (v *vpaPriors) recommendationsOf(logger klog.Logger, pod *v1.Pod) map[string]containerRecommendation {
+	kind, name := v.controllerOf(pod)
+	if kind == "" {
+		return nil
+	}
+	objs, err := v.vpaLister.ByNamespace(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		logger.V(6).Info("Failed to list VPAs", "namespace", pod.Namespace, "err", err)
+		return nil
+	}
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		vpa := &verticalPodAutoscaler{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, vpa); err != nil {
+			logger.V(6).Info("Failed to decode VPA", "vpa", klog.KObj(u), "err", err)
+			continue
+		}
+		targetRef := vpa.Spec.TargetRef
+		if targetRef == nil || targetRef.Kind != kind || targetRef.Name != name || vpa.Status.Recommendation == nil {
+			continue
+		}
+		recommendations := make(map[string]containerRecommendation)
+		for _, recommendation := range vpa.Status.Recommendation.ContainerRecommendations {
+			recommendations[recommendation.ContainerName] = recommendation
+		}
+		return recommendations
+	}
+	return nil
+}
+
+// controllerOf : the kind and name of the controller of the pod, targeted by its VerticalPodAutoscaler,
+// i.e. the Deployment of the pods of a ReplicaSet
+func (v *vpaPriors) controllerOf(pod *v1.Pod) (string, string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "", ""
+	}
+	if owner.Kind == "ReplicaSet" {
+		if rs, err := v.rsLister.ReplicaSets(pod.Namespace).Get(owner.Name); err == nil {
+			if deployment := metav1.GetControllerOf(rs); deployment != nil && deployment.Kind == "Deployment" {
+				return deployment.Kind, deployment.Name
+			}
+		}
+	}
+	return owner.Kind, owner.Name
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lowriskovercommitment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/informers"
+	testClientSet "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	"k8s.io/utils/ptr"
+)
+
+func makeVPA(name, targetKind, targetName string, recommendations ...interface{}) *unstructured.Unstructured {
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "autoscaling.k8s.io/v1",
+		"kind":       "VerticalPodAutoscaler",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": targetKind, "name": targetName},
+		},
+	}}
+	if len(recommendations) > 0 {
+		vpa.Object["status"] = map[string]interface{}{
+			"recommendation": map[string]interface{}{"containerRecommendations": recommendations},
+		}
+	}
+	return vpa
+}
+
+func TestVPAPriors(t *testing.T) {
+	vpaIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, vpa := range []*unstructured.Unstructured{
+		makeVPA("web", "Deployment", "web", map[string]interface{}{
+			"containerName": "app",
+			"target":        map[string]interface{}{"cpu": "200m", "memory": "256Mi"},
+			"upperBound":    map[string]interface{}{"cpu": "500m", "memory": "512Mi"},
+		}),
+		makeVPA("db", "StatefulSet", "db"),
+	} {
+		assert.Nil(t, vpaIndexer.Add(vpa))
+	}
+	informerFactory := informers.NewSharedInformerFactory(testClientSet.NewSimpleClientset(), 0)
+	rsInformer := informerFactory.Apps().V1().ReplicaSets().Informer()
+	assert.Nil(t, rsInformer.GetIndexer().Add(&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "web-1", Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Controller: ptr.To(true)}},
+	}}))
+	priors := &vpaPriors{
+		vpaLister: cache.NewGenericLister(vpaIndexer, vpaGVR.GroupResource()),
+		rsLister:  informerFactory.Apps().V1().ReplicaSets().Lister(),
+	}
+
+	makeOwnedPod := func(name, ownerKind, ownerName string) *v1.Pod {
+		pod := st.MakePod().Name(name).Namespace("default").
+			Container("app").Container("sidecar").
+			OwnerReference(ownerName, metav1.SchemeGroupVersion.WithKind(ownerKind)).Obj()
+		for i, name := range []string{"app", "sidecar"} {
+			pod.Spec.Containers[i].Name = name
+			pod.Spec.Containers[i].Resources = v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")},
+				Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("2Gi")},
+			}
+		}
+		return pod
+	}
+
+	// the sidecar container has no recommendation and keeps its requests and limits
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		wantRequests framework.Resource
+		wantLimits   framework.Resource
+	}{
+		{
+			name:         "recommendations of the deployment of the replica set",
+			pod:          makeOwnedPod("web-1-a", "ReplicaSet", "web-1"),
+			wantRequests: framework.Resource{MilliCPU: 1200, Memory: 1280 * 1024 * 1024},
+			wantLimits:   framework.Resource{MilliCPU: 2500, Memory: 2560 * 1024 * 1024},
+		},
+		{
+			name:         "no recommendation yet",
+			pod:          makeOwnedPod("db-0", "StatefulSet", "db"),
+			wantRequests: framework.Resource{MilliCPU: 2000, Memory: 2048 * 1024 * 1024},
+			wantLimits:   framework.Resource{MilliCPU: 4000, Memory: 4096 * 1024 * 1024},
+		},
+		{
+			name:         "no VPA for the controller",
+			pod:          makeOwnedPod("job-a", "Job", "job"),
+			wantRequests: framework.Resource{MilliCPU: 2000, Memory: 2048 * 1024 * 1024},
+			wantLimits:   framework.Resource{MilliCPU: 4000, Memory: 4096 * 1024 * 1024},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.pod.DeepCopy()
+			pl := &LowRiskOverCommitment{vpa: priors}
+			got := pl.createPodResourcesStateData(klog.Background(), tt.pod)
+			assert.Equal(t, original, tt.pod, "the pod must not be modified")
+			assert.Equal(t, tt.wantRequests.MilliCPU, got.podRequests.MilliCPU)
+			assert.Equal(t, tt.wantRequests.Memory, got.podRequests.Memory)
+			assert.Equal(t, tt.wantLimits.MilliCPU, got.podLimits.MilliCPU)
+			assert.Equal(t, tt.wantLimits.Memory, got.podLimits.Memory)
+		})
+	}
+}
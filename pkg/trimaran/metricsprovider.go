@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	loadwatcherapi "github.com/paypal/load-watcher/pkg/watcher/api"
+
+	pluginConfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// MetricsProvider : source of the node metrics of the trimaran plugins, when the load watcher is not used as a service
+type MetricsProvider interface {
+	// GetLatestWatcherMetrics : the latest metrics of all the nodes
+	GetLatestWatcherMetrics() (*watcher.WatcherMetrics, error)
+}
+
+// MetricsProviderFactory : create a metrics provider from the metricProvider args of a trimaran plugin
+type MetricsProviderFactory func(spec pluginConfig.MetricProviderSpec) (MetricsProvider, error)
+
+var (
+	metricsProvidersMu sync.RWMutex
+	// metricsProviders : factories of the metrics providers by type
+	metricsProviders = map[pluginConfig.MetricProviderType]MetricsProviderFactory{
+		pluginConfig.KubernetesMetricsServer: newLibraryMetricsProvider,
+		pluginConfig.Prometheus:              newLibraryMetricsProvider,
+		pluginConfig.SignalFx:                newLibraryMetricsProvider,
+		pluginConfig.GRPC:                    NewGRPCMetricsProvider,
+	}
+)
+
+// RegisterMetricsProvider : make a metrics provider selectable by its type in the metricProvider args of the
+// trimaran plugins, e.g. by a scheduler built out of tree. It replaces the provider already registered with the type.
+func RegisterMetricsProvider(providerType pluginConfig.MetricProviderType, factory MetricsProviderFactory) {
+	metricsProvidersMu.Lock()
+	defer metricsProvidersMu.Unlock()
+	metricsProviders[providerType] = factory
+}
+
+// NewMetricsProvider : create the metrics provider of the type of the spec
+func NewMetricsProvider(spec pluginConfig.MetricProviderSpec) (MetricsProvider, error) {
+	metricsProvidersMu.RLock()
+	factory, ok := metricsProviders[spec.Type]
+	metricsProvidersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("invalid MetricProvider.Type, got %v", spec.Type)
+	}
+	return factory(spec)
+}
+
+// metricsProviderRegistered : whether a metrics provider is registered with the type
+func metricsProviderRegistered(providerType pluginConfig.MetricProviderType) bool {
+	metricsProvidersMu.RLock()
+	defer metricsProvidersMu.RUnlock()
+	_, ok := metricsProviders[providerType]
+	return ok
+}
+
+// newLibraryMetricsProvider : the load watcher library, reading the Kubernetes Metrics API, Prometheus or SignalFx
+func newLibraryMetricsProvider(spec pluginConfig.MetricProviderSpec) (MetricsProvider, error) {
+	opts := watcher.MetricsProviderOpts{
+		Name:               string(spec.Type),
+		Address:            spec.Address,
+		AuthToken:          spec.Token,
+		InsecureSkipVerify: spec.InsecureSkipVerify,
+	}
+	return loadwatcherapi.NewLibraryClient(opts)
+}
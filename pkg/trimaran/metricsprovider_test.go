@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/klog/v2"
+
+	pluginConfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+)
+
+// fakeMetricsServer : metrics service answering watcherResponse to the callers presenting the token
+type fakeMetricsServer struct {
+	token string
+}
+
+func (s *fakeMetricsServer) GetLatestWatcherMetrics(ctx context.Context, _ *GetLatestWatcherMetricsRequest) (*watcher.WatcherMetrics, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if s.token != "" && (len(md.Get("authorization")) == 0 || md.Get("authorization")[0] != "Bearer "+s.token) {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	metrics := watcherResponse
+	return &metrics, nil
+}
+
+func newBufconnMetricsProvider(t *testing.T, server *fakeMetricsServer, token string) *GRPCMetricsProvider {
+	listener := bufconn.Listen(1 << 20)
+	s := NewGRPCServer(server)
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewGRPCMetricsProviderWithConn(conn, token)
+}
+
+func TestGRPCMetricsProvider(t *testing.T) {
+	provider := newBufconnMetricsProvider(t, &fakeMetricsServer{token: "secret"}, "secret")
+	metrics, err := provider.GetLatestWatcherMetrics()
+	assert.Nil(t, err)
+	assert.Equal(t, watcherResponse.Data.NodeMetricsMap["node-1"].Metrics, metrics.Data.NodeMetricsMap["node-1"].Metrics)
+
+	provider = newBufconnMetricsProvider(t, &fakeMetricsServer{token: "secret"}, "wrong")
+	_, err = provider.GetLatestWatcherMetrics()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestCollectorGRPCMetricsProvider(t *testing.T) {
+	provider := newBufconnMetricsProvider(t, &fakeMetricsServer{}, "")
+	RegisterMetricsProvider("Fake", func(pluginConfig.MetricProviderSpec) (MetricsProvider, error) {
+		return provider, nil
+	})
+	defer func() {
+		metricsProvidersMu.Lock()
+		delete(metricsProviders, "Fake")
+		metricsProvidersMu.Unlock()
+	}()
+
+	logger := klog.FromContext(context.TODO())
+	collector, err := NewCollector(logger, &pluginConfig.TrimaranSpec{
+		MetricProvider: pluginConfig.MetricProviderSpec{Type: "Fake"},
+	})
+	assert.Nil(t, err)
+	metrics, _ := collector.GetNodeMetrics(logger, "node-1")
+	assert.Equal(t, watcherResponse.Data.NodeMetricsMap["node-1"].Metrics, metrics)
+}
+
+func TestNewMetricsProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    pluginConfig.MetricProviderSpec
+		wantErr string
+	}{
+		{
+			name: "gRPC in plaintext",
+			spec: pluginConfig.MetricProviderSpec{Type: pluginConfig.GRPC, Address: "http://metrics.monitoring.svc:9090"},
+		},
+		{
+			name: "gRPC over TLS",
+			spec: pluginConfig.MetricProviderSpec{Type: pluginConfig.GRPC, Address: "metrics.monitoring.svc:9090", Token: "secret"},
+		},
+		{
+			name:    "unknown type",
+			spec:    pluginConfig.MetricProviderSpec{Type: "Graphite"},
+			wantErr: "invalid MetricProvider.Type, got Graphite",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewMetricsProvider(tt.spec)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.IsType(t, &GRPCMetricsProvider{}, provider)
+		})
+	}
+}
+
+func TestNewCollectorGRPCWithoutAddress(t *testing.T) {
+	logger := klog.FromContext(context.TODO())
+	collector, err := NewCollector(logger, &pluginConfig.TrimaranSpec{
+		MetricProvider: pluginConfig.MetricProviderSpec{Type: pluginConfig.GRPC},
+	})
+	assert.Nil(t, collector)
+	assert.EqualError(t, err, "MetricProvider.Address of the GRPC metrics provider is required")
+}
+
+func TestGRPCTarget(t *testing.T) {
+	tests := []struct {
+		address   string
+		target    string
+		plaintext bool
+	}{
+		{address: "http://metrics:9090", target: "metrics:9090", plaintext: true},
+		{address: "https://metrics:9090", target: "metrics:9090"},
+		{address: "dns:///metrics:9090", target: "dns:///metrics:9090"},
+	}
+	for _, tt := range tests {
+		target, plaintext := grpcTarget(tt.address)
+		assert.Equal(t, tt.target, target, tt.address)
+		assert.Equal(t, tt.plaintext, plaintext, tt.address)
+	}
+}
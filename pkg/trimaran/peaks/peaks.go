@@ -32,7 +32,11 @@ import (
 	"github.com/paypal/load-watcher/pkg/watcher"
 	v1 "k8s.io/api/core/v1"
 	res "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/api/v1/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -54,6 +58,9 @@ type Peaks struct {
 	handle    framework.Handle
 	collector *trimaran.Collector
 	args      *config.PeaksArgs
+	// powerProfiles lists the power profile ConfigMap, named powerProfileName, nil if disabled
+	powerProfiles    corelisters.ConfigMapNamespaceLister
+	powerProfileName string
 }
 
 var _ framework.ScorePlugin = &Peaks{}
@@ -102,9 +109,55 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		collector: collector,
 		args:      args,
 	}
+	if args.PowerProfileConfigMap != "" {
+		namespace, name, ok := strings.Cut(args.PowerProfileConfigMap, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("invalid powerProfileConfigMap %q, want <namespace>/<name>", args.PowerProfileConfigMap)
+		}
+		pl.watchPowerProfiles(ctx, namespace, name)
+	}
 	return pl, nil
 }
 
+// watchPowerProfiles watches the ConfigMap of the power profiles learned per workload by the power profile
+// controller, and only it.
+func (pl *Peaks) watchPowerProfiles(ctx context.Context, namespace, name string) {
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(pl.handle.ClientSet(), 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+	pl.powerProfiles = informerFactory.Core().V1().ConfigMaps().Lister().ConfigMaps(namespace)
+	pl.powerProfileName = name
+	informerFactory.Start(ctx.Done())
+}
+
+// podWattsPerCore returns the power drawn per CPU core by the pods of the workload of the pod on the node,
+// as learned by the power profile controller, if any.
+func (pl *Peaks) podWattsPerCore(logger klog.Logger, pod *v1.Pod, node *v1.Node) (float64, bool) {
+	if pl.powerProfiles == nil {
+		return 0, false
+	}
+	key, ok := trimaran.PowerProfileKey(pod)
+	if !ok {
+		return 0, false
+	}
+	cm, err := pl.powerProfiles.Get(pl.powerProfileName)
+	if err != nil {
+		return 0, false
+	}
+	data, ok := cm.Data[key]
+	if !ok {
+		return 0, false
+	}
+	profile, err := trimaran.DecodePowerProfile(data)
+	if err != nil {
+		logger.V(4).Info("Invalid power profile, using the power model", "workload", key, "err", err)
+		return 0, false
+	}
+	return profile.WattsPerCore(trimaran.PowerProfileNodeClass(node))
+}
+
 func (pl *Peaks) Score(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
 	logger := klog.FromContext(ctx)
 	score := framework.MinNodeScore
@@ -114,7 +167,7 @@ func (pl *Peaks) Score(ctx context.Context, cycleState *framework.CycleState, po
 		return score, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
 
-	metrics, _ := pl.collector.GetNodeMetrics(logger, nodeName)
+	metrics, _, _ := pl.collector.GetNodeMetricsOrRequests(logger, nodeInfo)
 	if metrics == nil {
 		logger.Error(nil, "Failed to get metrics for node; using minimum score", "nodeName", nodeName)
 		return score, nil
@@ -163,7 +216,14 @@ func (pl *Peaks) Score(ctx context.Context, cycleState *framework.CycleState, po
 		return score, framework.NewStatus(framework.Success, "")
 	} else {
 		logger.V(4).Info("Node :", nodeName, ", Node cpu usage current :", nodeCPUUtilPercent, ", predicted :", predictedCPUUsage)
-		jumpInPower := getPowerJumpForUtilisation(nodeCPUUtilPercent, predictedCPUUsage, getPowerModel(nodeName, pl.args.NodePowerModel))
+		var jumpInPower float64
+		if wattsPerCore, ok := pl.podWattsPerCore(logger, pod, nodeInfo.Node()); ok {
+			// The power profile of the workload on the node class replaces the power model of the node.
+			jumpInPower = wattsPerCore * float64(curPodCPUUsage) / 1000
+			logger.V(4).Info("Using the power profile of the workload", "nodeName", nodeName, "wattsPerCore", wattsPerCore)
+		} else {
+			jumpInPower = getPowerJumpForUtilisation(nodeCPUUtilPercent, predictedCPUUsage, getPowerModel(nodeName, pl.args.NodePowerModel))
+		}
 		return int64(jumpInPower * math.Pow(10, 15)), framework.NewStatus(framework.Success, "")
 	}
 }
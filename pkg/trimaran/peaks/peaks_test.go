@@ -24,11 +24,14 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/paypal/load-watcher/pkg/watcher"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	testClientSet "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/kubernetes/pkg/scheduler/apis/config"
@@ -40,6 +43,7 @@ import (
 	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
 
 	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	pluginTrimaran "sigs.k8s.io/scheduler-plugins/pkg/trimaran"
 	testutil2 "sigs.k8s.io/scheduler-plugins/test/integration"
 	testutil "sigs.k8s.io/scheduler-plugins/test/util"
 )
@@ -553,3 +557,68 @@ func newTestSharedLister(pods []*v1.Pod, nodes []*v1.Node) *testSharedLister {
 		nodeInfoMap: nodeInfoMap,
 	}
 }
+
+func TestPeaksScoreWithPowerProfile(t *testing.T) {
+	watcherResponse := watcher.WatcherMetrics{
+		Data: watcher.Data{
+			NodeMetricsMap: map[string]watcher.NodeMetrics{
+				"node-1": {Metrics: []watcher.Metric{{Type: watcher.CPU, Operator: watcher.Latest, Value: 10}}},
+				"node-2": {Metrics: []watcher.Metric{{Type: watcher.CPU, Operator: watcher.Latest, Value: 10}}},
+			},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		bytes, err := json.Marshal(watcherResponse)
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodeResources := map[v1.ResourceName]string{v1.ResourceCPU: "4"}
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node-1").Label(v1.LabelInstanceTypeStable, "m5.large").Capacity(nodeResources).Obj(),
+		st.MakeNode().Name("node-2").Capacity(nodeResources).Obj(),
+	}
+	pod := st.MakePod().Name("web-5b8c-x").Namespace("default").Label("pod-template-hash", "5b8c").
+		OwnerReference("web-5b8c", appsv1.SchemeGroupVersion.WithKind("ReplicaSet")).
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj()
+	profile, err := pluginTrimaran.PowerProfile{"m5.large": {WattsPerCore: 20, Samples: 5}}.Encode()
+	assert.Nil(t, err)
+	cs := testClientSet.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "peaks-power-profiles"},
+		Data:       map[string]string{"default_deployment_web": profile},
+	})
+	fh, err := tf.NewFramework(ctx, []tf.RegisterPluginFunc{
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}, "default-scheduler", runtime.WithClientSet(cs), runtime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+	assert.Nil(t, err)
+
+	peaksArgs := pluginConfig.PeaksArgs{
+		WatcherAddress:        server.URL,
+		NodePowerModel:        map[string]pluginConfig.PowerModel{"node-2": {}},
+		PowerProfileConfigMap: "kube-system",
+	}
+	_, err = New(ctx, &peaksArgs, fh)
+	assert.EqualError(t, err, "invalid powerProfileConfigMap \"kube-system\", want <namespace>/<name>")
+
+	peaksArgs.PowerProfileConfigMap = "kube-system/peaks-power-profiles"
+	p, err := New(ctx, &peaksArgs, fh)
+	assert.Nil(t, err)
+	pl := p.(*Peaks)
+	assert.Eventually(t, func() bool {
+		_, err := pl.powerProfiles.Get(pl.powerProfileName)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The power profile of the workload on node-1 replaces the power model of the node, missing on node-1.
+	score, status := pl.Score(ctx, framework.NewCycleState(), pod, "node-1")
+	assert.True(t, status.IsSuccess())
+	assert.Equal(t, int64(10*math.Pow(10, 15)), score)
+	score, status = pl.Score(ctx, framework.NewCycleState(), pod, "node-2")
+	assert.True(t, status.IsSuccess())
+	assert.Equal(t, int64(0), score)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// PowerProfileMinSamples : number of samples from which the power profile of a workload on a node class is used
+	PowerProfileMinSamples = 3
+	// powerProfileHistory : number of samples the power profile of a workload on a node class is averaged over,
+	// older samples weighing less and less
+	powerProfileHistory = 20
+)
+
+// PowerProfile : power drawn per CPU core by the pods of a workload, learned per node class from the
+// variations of the power of the nodes after the placements of the pods
+type PowerProfile map[string]PowerProfileEntry
+
+// PowerProfileEntry : power drawn per CPU core by the pods of a workload on a node class
+type PowerProfileEntry struct {
+	// WattsPerCore : average power drawn per requested CPU core, in watts
+	WattsPerCore float64 `json:"wattsPerCore"`
+	// Samples : number of placements the average is learned from
+	Samples int64 `json:"samples"`
+}
+
+// PowerProfileKey : key of the power profile of the workload of a pod in the power profile ConfigMap,
+// "<namespace>_<kind>_<name>" of the Deployment or Job controlling the pod, if any
+func PowerProfileKey(pod *v1.Pod) (string, bool) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "", false
+	}
+	switch owner.Kind {
+	case "Job":
+		return pod.Namespace + "_job_" + owner.Name, true
+	case "ReplicaSet":
+		// The ReplicaSets of a Deployment are named after it and the hash of their pod template.
+		hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+		if hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return pod.Namespace + "_deployment_" + strings.TrimSuffix(owner.Name, "-"+hash), true
+		}
+	}
+	return "", false
+}
+
+// PowerProfileNodeClass : class of the node the power profiles are learned for, its instance type,
+// or its name if it has none
+func PowerProfileNodeClass(node *v1.Node) string {
+	if instanceType := node.Labels[v1.LabelInstanceTypeStable]; instanceType != "" {
+		return instanceType
+	}
+	return node.Name
+}
+
+// DecodePowerProfile : decode a power profile from the power profile ConfigMap
+func DecodePowerProfile(data string) (PowerProfile, error) {
+	profile := PowerProfile{}
+	if err := json.Unmarshal([]byte(data), &profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Encode : encode the power profile for the power profile ConfigMap
+func (p PowerProfile) Encode() (string, error) {
+	data, err := json.Marshal(p)
+	return string(data), err
+}
+
+// WattsPerCore : power drawn per CPU core by the pods of the workload on the node class, if learned
+// from enough samples
+func (p PowerProfile) WattsPerCore(nodeClass string) (float64, bool) {
+	entry, ok := p[nodeClass]
+	if !ok || entry.Samples < PowerProfileMinSamples {
+		return 0, false
+	}
+	return entry.WattsPerCore, true
+}
+
+// Observe : learn the power drawn per CPU core measured after a placement on the node class
+func (p PowerProfile) Observe(nodeClass string, wattsPerCore float64) {
+	entry := p[nodeClass]
+	entry.Samples++
+	entry.WattsPerCore += (wattsPerCore - entry.WattsPerCore) / float64(min(entry.Samples, powerProfileHistory))
+	p[nodeClass] = entry
+}
+
+// NodePowerSource : the power drawn by the nodes, from the energy metrics of load watcher
+type NodePowerSource struct {
+	Collector *Collector
+	// Metric : name of the energy metric giving the power of the nodes, averaged over the load watcher window
+	Metric string
+	// Scale : watts per unit of the metric
+	Scale float64
+}
+
+// NodePower : the average power drawn by the node, in watts
+func (s *NodePowerSource) NodePower(logger klog.Logger, nodeName string) (float64, bool) {
+	metrics, _ := s.Collector.GetNodeMetrics(logger, nodeName)
+	for _, metric := range metrics {
+		if metric.Type == watcher.Energy && metric.Name == s.Metric && metric.Operator == watcher.Average {
+			return metric.Value * s.Scale, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestPowerProfileKey(t *testing.T) {
+	owned := func(kind, name, hash string) *v1.Pod {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "ns"}}
+		if kind != "" {
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: name, Controller: ptr.To(true)}}
+		}
+		if hash != "" {
+			pod.Labels = map[string]string{"pod-template-hash": hash}
+		}
+		return pod
+	}
+	tests := []struct {
+		name    string
+		pod     *v1.Pod
+		wantKey string
+		wantOk  bool
+	}{
+		{name: "deployment", pod: owned("ReplicaSet", "web-7d9f8", "7d9f8"), wantKey: "ns_deployment_web", wantOk: true},
+		{name: "job", pod: owned("Job", "train", ""), wantKey: "ns_job_train", wantOk: true},
+		{name: "replicaset without deployment", pod: owned("ReplicaSet", "web", "")},
+		{name: "statefulset", pod: owned("StatefulSet", "db", "")},
+		{name: "bare pod", pod: owned("", "", "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := PowerProfileKey(tt.pod)
+			assert.Equal(t, tt.wantKey, key)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
+
+func TestPowerProfileObserve(t *testing.T) {
+	profile := PowerProfile{}
+	profile.Observe("m5.large", 10)
+	profile.Observe("m5.large", 20)
+	_, ok := profile.WattsPerCore("m5.large")
+	assert.False(t, ok, "too few samples")
+
+	profile.Observe("m5.large", 30)
+	wattsPerCore, ok := profile.WattsPerCore("m5.large")
+	assert.True(t, ok)
+	assert.InDelta(t, 20, wattsPerCore, 1e-9)
+
+	for i := 0; i < 1000; i++ {
+		profile.Observe("m5.large", 40)
+	}
+	wattsPerCore, _ = profile.WattsPerCore("m5.large")
+	assert.InDelta(t, 40, wattsPerCore, 1e-6, "old samples forgotten")
+	_, ok = profile.WattsPerCore("c5.xlarge")
+	assert.False(t, ok)
+
+	data, err := profile.Encode()
+	assert.Nil(t, err)
+	decoded, err := DecodePowerProfile(data)
+	assert.Nil(t, err)
+	assert.Equal(t, profile, decoded)
+	_, err = DecodePowerProfile("not json")
+	assert.NotNil(t, err)
+}
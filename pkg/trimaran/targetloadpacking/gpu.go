@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targetloadpacking
+
+import (
+	"github.com/paypal/load-watcher/pkg/watcher"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	// GPU is the type of the node GPU utilization metrics reported by load-watcher, in percent of the GPUs
+	// of the node, e.g. DCGM_FI_DEV_GPU_UTIL of the DCGM exporter averaged over the GPUs of the node.
+	GPU = "GPU"
+
+	// ResourceGPU is the resource of the GPUs advertised by the NVIDIA device plugin
+	ResourceGPU v1.ResourceName = "nvidia.com/gpu"
+)
+
+// gpuRequests returns the number of GPUs requested by the pod
+func gpuRequests(pod *v1.Pod) int64 {
+	var gpus int64
+	for _, container := range pod.Spec.Containers {
+		// Extended resources can't be overcommitted: requests, when set, equal limits
+		if quantity, ok := container.Resources.Limits[ResourceGPU]; ok {
+			gpus += quantity.Value()
+		} else if quantity, ok := container.Resources.Requests[ResourceGPU]; ok {
+			gpus += quantity.Value()
+		}
+	}
+	return gpus
+}
+
+// scoreGPU scores the node by its GPU utilization predicted with the pod, as TargetLoadPacking does by CPU
+// utilization, the GPUs requested by the pods being predicted fully used. It returns false if the node
+// reports no GPU utilization or has no GPU.
+func (pl *TargetLoadPacking) scoreGPU(logger klog.Logger, nodeInfo *framework.NodeInfo, podGPUs int64,
+	metrics []watcher.Metric, allMetrics *watcher.WatcherMetrics) (int64, bool) {
+	nodeName := nodeInfo.Node().Name
+	var nodeGPUUtilPercent float64
+	var gpuMetricFound bool
+	for _, metric := range metrics {
+		if metric.Type == GPU && (metric.Operator == watcher.Average || metric.Operator == watcher.Latest) {
+			nodeGPUUtilPercent = metric.Value
+			gpuMetricFound = true
+		}
+	}
+	gpuCapacity := nodeInfo.Node().Status.Capacity[ResourceGPU]
+	nodeGPUs := gpuCapacity.Value()
+	if !gpuMetricFound || nodeGPUs == 0 {
+		return framework.MinNodeScore, false
+	}
+
+	// GPUs of the pods scheduled too recently to be accounted in the metrics
+	var missingGPUs int64
+	pl.eventHandler.RLock()
+	for _, info := range pl.eventHandler.ScheduledPodsCache[nodeName] {
+		if info.Timestamp.Unix() > allMetrics.Window.End || info.Timestamp.Unix() <= allMetrics.Window.End &&
+			(allMetrics.Window.End-info.Timestamp.Unix()) < metricsAgentReportingIntervalSeconds {
+			missingGPUs += gpuRequests(info.Pod)
+		}
+	}
+	pl.eventHandler.RUnlock()
+
+	predictedGPUUsage := nodeGPUUtilPercent + 100*float64(podGPUs+missingGPUs)/float64(nodeGPUs)
+	score := targetUtilizationScore(predictedGPUUsage, float64(pl.args.GPUTargetUtilization))
+	logger.V(6).Info("Score for host by GPU utilization", "nodeName", nodeName, "gpuUtilPercent", nodeGPUUtilPercent,
+		"missingGPUs", missingGPUs, "predictedGPUUsage", predictedGPUUsage, "score", score)
+	return score, true
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targetloadpacking
+
+import (
+	"math"
+	"sync"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// pressureMode switches between spreading and packing the pods by the cluster-wide average CPU utilization:
+// the pods are spread while the cluster is lightly loaded, keeping headroom on every node, and packed once the
+// utilization reaches the threshold, so that the nodes left idle can be scaled down. The pods are spread again
+// only once the utilization drops hysteresis points below the threshold, not to flap between the two modes.
+type pressureMode struct {
+	threshold  float64
+	hysteresis float64
+
+	mu      sync.Mutex
+	packing bool
+}
+
+func newPressureMode(threshold, hysteresis int64) *pressureMode {
+	return &pressureMode{threshold: float64(threshold), hysteresis: float64(hysteresis)}
+}
+
+// isPacking returns whether the pods are packed for the given cluster utilization, keeping the current mode
+// while it isn't available.
+func (m *pressureMode) isPacking(logger klog.Logger, clusterCPUUtil float64, ok bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !ok {
+		return m.packing
+	}
+	switch {
+	case !m.packing && clusterCPUUtil >= m.threshold:
+		logger.V(2).Info("Cluster utilization reached the pressure threshold; packing the pods",
+			"clusterCPUUtil", clusterCPUUtil, "threshold", m.threshold)
+		m.packing = true
+	case m.packing && clusterCPUUtil < m.threshold-m.hysteresis:
+		logger.V(2).Info("Cluster utilization dropped below the pressure threshold; spreading the pods",
+			"clusterCPUUtil", clusterCPUUtil, "threshold", m.threshold, "hysteresis", m.hysteresis)
+		m.packing = false
+	}
+	return m.packing
+}
+
+// spreadScore scores the predicted utilization of a node, decreasing with it
+func spreadScore(predictedUsage float64) int64 {
+	if predictedUsage > 100 {
+		return framework.MinNodeScore
+	}
+	return int64(math.Round(100 - predictedUsage))
+}
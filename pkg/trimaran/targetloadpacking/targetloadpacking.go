@@ -62,6 +62,12 @@ type TargetLoadPacking struct {
 	eventHandler *trimaran.PodAssignEventHandler
 	collector    *trimaran.Collector
 	args         *pluginConfig.TargetLoadPackingArgs
+	// adjusts the target utilization when auto-tuning is enabled
+	tuner *trimaran.CoefficientTuner
+	// nodes given a neutral score
+	exclusion *trimaran.NodeExclusion
+	// spreads the pods below the cluster pressure threshold, always packing them when nil
+	pressure *pressureMode
 }
 
 var _ framework.ScorePlugin = &TargetLoadPacking{}
@@ -78,6 +84,10 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if err != nil {
 		return nil, err
 	}
+	exclusion, err := trimaran.NewNodeExclusion(args.ExcludedNodes)
+	if err != nil {
+		return nil, err
+	}
 
 	hostTargetUtilizationPercent = args.TargetUtilization
 	requestsMilliCores = args.DefaultRequests.Cpu().MilliValue()
@@ -85,11 +95,19 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if err != nil {
 		return nil, errors.New("unable to parse DefaultRequestsMultiplier: " + err.Error())
 	}
+	if args.ClusterPressureThreshold < 0 || args.ClusterPressureThreshold > 100 {
+		return nil, fmt.Errorf("invalid ClusterPressureThreshold %d, must be between 0 and 100", args.ClusterPressureThreshold)
+	}
+	if args.ClusterPressureHysteresis < 0 {
+		return nil, fmt.Errorf("invalid ClusterPressureHysteresis %d, must not be negative", args.ClusterPressureHysteresis)
+	}
 
 	logger.V(4).Info("Using TargetLoadPackingArgs",
 		"requestsMilliCores", requestsMilliCores,
 		"requestsMultiplier", requestsMultiplier,
-		"targetUtilization", hostTargetUtilizationPercent)
+		"targetUtilization", hostTargetUtilizationPercent,
+		"clusterPressureThreshold", args.ClusterPressureThreshold,
+		"clusterPressureHysteresis", args.ClusterPressureHysteresis)
 
 	podAssignEventHandler := trimaran.New()
 	podAssignEventHandler.AddToHandle(handle)
@@ -99,6 +117,15 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		eventHandler: podAssignEventHandler,
 		collector:    collector,
 		args:         args,
+		exclusion:    exclusion,
+	}
+	if args.ClusterPressureThreshold > 0 {
+		pl.pressure = newPressureMode(args.ClusterPressureThreshold, args.ClusterPressureHysteresis)
+	}
+	if args.AutoTune != nil {
+		pl.tuner = trimaran.NewCoefficientTuner(Name+".targetUtilization", float64(hostTargetUtilizationPercent), 0, 100,
+			trimaran.DecreaseOnUnderPrediction, args.AutoTune, handle.ClientSet())
+		pl.tuner.Start(ctx, logger, podAssignEventHandler, collector.GetNodeCPUUtilization)
 	}
 	return pl, nil
 }
@@ -114,9 +141,13 @@ func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState *framework.Cy
 	if err != nil {
 		return score, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
+	if pl.exclusion.Excludes(nodeInfo.Node()) {
+		logger.V(6).Info("Excluded node; using neutral score", "nodeName", nodeName)
+		return trimaran.NeutralScore, nil
+	}
 
 	// get node metrics
-	metrics, allMetrics := pl.collector.GetNodeMetrics(logger, nodeName)
+	metrics, allMetrics, fromRequests := pl.collector.GetNodeMetricsOrRequests(logger, nodeInfo)
 	if metrics == nil {
 		klog.InfoS("Failed to get metrics for node; using minimum score", "nodeName", nodeName)
 		// Avoid the node by scoring minimum
@@ -125,6 +156,14 @@ func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState *framework.Cy
 
 	}
 
+	// Pack the pods requesting GPUs by GPU utilization, if reported for the node
+	if podGPUs := gpuRequests(pod); podGPUs > 0 {
+		if score, ok := pl.scoreGPU(logger, nodeInfo, podGPUs, metrics, allMetrics); ok {
+			return score, framework.NewStatus(framework.Success, "")
+		}
+		logger.V(6).Info("GPU metric not found in node metrics; packing by CPU utilization", "nodeName", nodeName, "podName", pod.Name)
+	}
+
 	var curPodCPUUsage int64
 	for _, container := range pod.Spec.Containers {
 		curPodCPUUsage += PredictUtilisation(&container)
@@ -157,6 +196,10 @@ func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState *framework.Cy
 	var missingCPUUtilMillis int64 = 0
 	pl.eventHandler.RLock()
 	for _, info := range pl.eventHandler.ScheduledPodsCache[nodeName] {
+		// The requests of the node already account for all its pods.
+		if fromRequests {
+			break
+		}
 		// If the time stamp of the scheduled pod is outside fetched metrics window, or it is within metrics reporting interval seconds, we predict util.
 		// Note that the second condition doesn't guarantee metrics for that pod are not reported yet as the 0 <= t <= 2*metricsAgentReportingIntervalSeconds
 		// t = metricsAgentReportingIntervalSeconds is taken as average case and it doesn't hurt us much if we are
@@ -177,21 +220,41 @@ func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState *framework.Cy
 	if nodeCPUCapMillis != 0 {
 		predictedCPUUsage = 100 * (nodeCPUUtilMillis + float64(curPodCPUUsage) + float64(missingCPUUtilMillis)) / nodeCPUCapMillis
 	}
-	if predictedCPUUsage > float64(hostTargetUtilizationPercent) {
-		if predictedCPUUsage > 100 {
-			return score, framework.NewStatus(framework.Success, "")
+	targetUtilizationPercent := float64(hostTargetUtilizationPercent)
+	if pl.tuner != nil {
+		// Only the predictions from measured utilization tune the target.
+		if !fromRequests {
+			pl.tuner.RecordPrediction(pod, nodeName, predictedCPUUsage)
 		}
-		penalisedScore := int64(math.Round(float64(hostTargetUtilizationPercent) * (100 - predictedCPUUsage) / (100 - float64(hostTargetUtilizationPercent))))
-		logger.V(6).Info("Penalised score for host", "nodeName", nodeName, "penalisedScore", penalisedScore)
-		return penalisedScore, framework.NewStatus(framework.Success, "")
+		targetUtilizationPercent = pl.tuner.Value()
+	}
+	packing := true
+	if pl.pressure != nil {
+		clusterCPUUtil, ok := pl.collector.GetClusterCPUUtilization()
+		packing = pl.pressure.isPacking(logger, clusterCPUUtil, ok)
+	}
+	if packing {
+		score = targetUtilizationScore(predictedCPUUsage, targetUtilizationPercent)
+	} else {
+		score = spreadScore(predictedCPUUsage)
 	}
-
-	score = int64(math.Round((100-float64(hostTargetUtilizationPercent))*
-		predictedCPUUsage/float64(hostTargetUtilizationPercent) + float64(hostTargetUtilizationPercent)))
 	logger.V(6).Info("Score for host", "nodeName", nodeName, "score", score)
 	return score, framework.NewStatus(framework.Success, "")
 }
 
+// targetUtilizationScore scores the predicted utilization of a node, increasing up to the target utilization
+// and penalised above it
+func targetUtilizationScore(predictedUsage float64, targetUtilizationPercent float64) int64 {
+	if predictedUsage > targetUtilizationPercent {
+		if predictedUsage > 100 {
+			return framework.MinNodeScore
+		}
+		return int64(math.Round(targetUtilizationPercent * (100 - predictedUsage) / (100 - targetUtilizationPercent)))
+	}
+	return int64(math.Round((100-targetUtilizationPercent)*
+		predictedUsage/targetUtilizationPercent + targetUtilizationPercent))
+}
+
 func (pl *TargetLoadPacking) ScoreExtensions() framework.ScoreExtensions {
 	return pl
 }
@@ -32,6 +32,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	testClientSet "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/util/workqueue"
@@ -46,6 +47,7 @@ import (
 
 	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
 	cfgv1 "sigs.k8s.io/scheduler-plugins/apis/config/v1"
+	"sigs.k8s.io/scheduler-plugins/pkg/trimaran"
 )
 
 var _ framework.SharedLister = &testSharedLister{}
@@ -133,13 +135,20 @@ func TestTargetLoadPackingScoring(t *testing.T) {
 		v1.ResourceCPU:    "1000m",
 		v1.ResourceMemory: "1Gi",
 	}
+	gpuNodeResources := map[v1.ResourceName]string{
+		v1.ResourceCPU:    "1000m",
+		v1.ResourceMemory: "1Gi",
+		ResourceGPU:       "4",
+	}
 
 	tests := []struct {
-		test            string
-		pod             *v1.Pod
-		nodes           []*v1.Node
-		watcherResponse watcher.WatcherMetrics
-		expected        framework.NodeScoreList
+		test              string
+		pod               *v1.Pod
+		nodes             []*v1.Node
+		excludedNodes     *pluginConfig.NodeExclusionSpec
+		pressureThreshold int64
+		watcherResponse   watcher.WatcherMetrics
+		expected          framework.NodeScoreList
 	}{
 		{
 			test: "new node",
@@ -220,6 +229,216 @@ func TestTargetLoadPackingScoring(t *testing.T) {
 				{Name: "node-1", Score: framework.MinNodeScore},
 			},
 		},
+		{
+			test: "excluded node returns neutral score",
+			pod:  st.MakePod().Name("p").Obj(),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(nodeResources).Obj(),
+				st.MakeNode().Name("node-2").Label("pool", "dedicated").Capacity(nodeResources).Obj(),
+			},
+			excludedNodes: &pluginConfig.NodeExclusionSpec{
+				NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "dedicated"}},
+			},
+			watcherResponse: watcher.WatcherMetrics{
+				Window: watcher.Window{},
+				Data: watcher.Data{
+					NodeMetricsMap: map[string]watcher.NodeMetrics{
+						"node-1": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Value:    0,
+									Operator: watcher.Latest,
+								},
+							},
+						},
+						"node-2": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Value:    0,
+									Operator: watcher.Latest,
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: cfgv1.DefaultTargetUtilizationPercent},
+				{Name: "node-2", Score: trimaran.NeutralScore},
+			},
+		},
+		{
+			test: "GPU pod packed by GPU utilization",
+			pod:  getPodWithGPUs(1, 100),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(gpuNodeResources).Obj(),
+			},
+			watcherResponse: watcher.WatcherMetrics{
+				Window: watcher.Window{},
+				Data: watcher.Data{
+					NodeMetricsMap: map[string]watcher.NodeMetrics{
+						"node-1": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Value:    0,
+									Operator: watcher.Latest,
+								},
+								{
+									Name:     "DCGM_FI_DEV_GPU_UTIL",
+									Type:     GPU,
+									Value:    20,
+									Operator: watcher.Average,
+								},
+							},
+						},
+					},
+				},
+			},
+			// predicted GPU utilization 20 + 100/4 = 45 below the target of 60
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 90},
+			},
+		},
+		{
+			test: "hot GPU node",
+			pod:  getPodWithGPUs(1, 100),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(gpuNodeResources).Obj(),
+			},
+			watcherResponse: watcher.WatcherMetrics{
+				Window: watcher.Window{},
+				Data: watcher.Data{
+					NodeMetricsMap: map[string]watcher.NodeMetrics{
+						"node-1": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Value:    0,
+									Operator: watcher.Latest,
+								},
+								{
+									Type:     GPU,
+									Value:    50,
+									Operator: watcher.Average,
+								},
+							},
+						},
+					},
+				},
+			},
+			// predicted GPU utilization 50 + 100/4 = 75 above the target of 60
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 38},
+			},
+		},
+		{
+			test: "GPU pod packed by CPU utilization without GPU metrics",
+			pod:  getPodWithGPUs(1, 100),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(gpuNodeResources).Obj(),
+			},
+			watcherResponse: watcher.WatcherMetrics{
+				Window: watcher.Window{},
+				Data: watcher.Data{
+					NodeMetricsMap: map[string]watcher.NodeMetrics{
+						"node-1": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Value:    0,
+									Operator: watcher.Latest,
+								},
+							},
+						},
+					},
+				},
+			},
+			// predicted CPU utilization 100m/1000m below the target of 40
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 55},
+			},
+		},
+		{
+			test: "spread below the cluster pressure threshold",
+			pod:  getPodWithContainersAndOverhead(0, 100),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(nodeResources).Obj(),
+				st.MakeNode().Name("node-2").Capacity(nodeResources).Obj(),
+			},
+			pressureThreshold: 50,
+			watcherResponse: watcher.WatcherMetrics{
+				Window: watcher.Window{},
+				Data: watcher.Data{
+					NodeMetricsMap: map[string]watcher.NodeMetrics{
+						"node-1": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Value:    10,
+									Operator: watcher.Latest,
+								},
+							},
+						},
+						"node-2": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Value:    30,
+									Operator: watcher.Latest,
+								},
+							},
+						},
+					},
+				},
+			},
+			// cluster utilization 20 below the threshold of 50, predicted utilizations 20 and 40
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 80},
+				{Name: "node-2", Score: 60},
+			},
+		},
+		{
+			test: "pack from the cluster pressure threshold",
+			pod:  getPodWithContainersAndOverhead(0, 100),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node-1").Capacity(nodeResources).Obj(),
+				st.MakeNode().Name("node-2").Capacity(nodeResources).Obj(),
+			},
+			pressureThreshold: 20,
+			watcherResponse: watcher.WatcherMetrics{
+				Window: watcher.Window{},
+				Data: watcher.Data{
+					NodeMetricsMap: map[string]watcher.NodeMetrics{
+						"node-1": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Value:    10,
+									Operator: watcher.Latest,
+								},
+							},
+						},
+						"node-2": {
+							Metrics: []watcher.Metric{
+								{
+									Type:     watcher.CPU,
+									Value:    30,
+									Operator: watcher.Latest,
+								},
+							},
+						},
+					},
+				},
+			},
+			// cluster utilization 20 at the threshold, predicted utilizations 20 and 40 up to the target of 40
+			expected: []framework.NodeScore{
+				{Name: "node-1", Score: 70},
+				{Name: "node-2", Score: 100},
+			},
+		},
 		{
 			test: "404 resp from watcher",
 			pod:  st.MakePod().Name("p").Obj(),
@@ -256,9 +475,12 @@ func TestTargetLoadPackingScoring(t *testing.T) {
 				runtime.WithInformerFactory(informerFactory), runtime.WithSnapshotSharedLister(snapshot))
 			assert.Nil(t, err)
 			targetLoadPackingArgs := pluginConfig.TargetLoadPackingArgs{
-				TrimaranSpec:              pluginConfig.TrimaranSpec{WatcherAddress: server.URL},
+				TrimaranSpec:              pluginConfig.TrimaranSpec{WatcherAddress: server.URL, ExcludedNodes: tt.excludedNodes},
 				TargetUtilization:         cfgv1.DefaultTargetUtilizationPercent,
+				GPUTargetUtilization:      cfgv1.DefaultGPUTargetUtilizationPercent,
 				DefaultRequestsMultiplier: cfgv1.DefaultRequestsMultiplier,
+				ClusterPressureThreshold:  tt.pressureThreshold,
+				ClusterPressureHysteresis: cfgv1.DefaultClusterPressureHysteresisPercent,
 			}
 			p, _ := New(ctx, &targetLoadPackingArgs, fh)
 			scorePlugin := p.(framework.ScorePlugin)
@@ -274,6 +496,46 @@ func TestTargetLoadPackingScoring(t *testing.T) {
 	}
 }
 
+func TestPressureMode(t *testing.T) {
+	logger := klog.FromContext(context.Background())
+	m := newPressureMode(60, 10)
+	steps := []struct {
+		clusterCPUUtil float64
+		ok             bool
+		packing        bool
+	}{
+		{clusterCPUUtil: 30, ok: true, packing: false},
+		{clusterCPUUtil: 60, ok: true, packing: true},
+		// within the hysteresis
+		{clusterCPUUtil: 55, ok: true, packing: true},
+		{clusterCPUUtil: 50, ok: true, packing: true},
+		// utilization unavailable
+		{ok: false, packing: true},
+		{clusterCPUUtil: 49, ok: true, packing: false},
+		{clusterCPUUtil: 59, ok: true, packing: false},
+	}
+	for i, step := range steps {
+		if got := m.isPacking(logger, step.clusterCPUUtil, step.ok); got != step.packing {
+			t.Errorf("step %d: expected packing %v at %v, got %v", i, step.packing, step.clusterCPUUtil, got)
+		}
+	}
+}
+
+func TestNewInvalidClusterPressure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, args := range []pluginConfig.TargetLoadPackingArgs{
+		{ClusterPressureThreshold: 101},
+		{ClusterPressureThreshold: 60, ClusterPressureHysteresis: -1},
+	} {
+		args.TrimaranSpec = pluginConfig.TrimaranSpec{WatcherAddress: "http://deadbeef:2020"}
+		args.TargetUtilization = cfgv1.DefaultTargetUtilizationPercent
+		args.DefaultRequestsMultiplier = cfgv1.DefaultRequestsMultiplier
+		_, err := New(ctx, &args, nil)
+		assert.NotNil(t, err)
+	}
+}
+
 func BenchmarkTargetLoadPackingPlugin(b *testing.B) {
 	tests := []struct {
 		name     string
@@ -441,6 +703,12 @@ func getPodWithContainersAndOverhead(overhead int64, requests ...int64) *v1.Pod
 	return newPod.Obj()
 }
 
+func getPodWithGPUs(gpus int64, cpuMillis int64) *v1.Pod {
+	pod := getPodWithContainersAndOverhead(0, cpuMillis)
+	pod.Spec.Containers[0].Resources.Limits[ResourceGPU] = *resource.NewQuantity(gpus, resource.DecimalSI)
+	return pod
+}
+
 func getNodes(nodesNum int64) (nodes []*v1.Node) {
 	nodeResources := map[v1.ResourceName]string{
 		v1.ResourceCPU:    "64000m",
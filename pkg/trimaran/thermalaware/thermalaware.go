@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package thermalaware plugin steers CPU-heavy pods away from hot nodes. The temperature and the
+// thermal throttling of the nodes, e.g. exported by IPMI or the node-exporter, are reported by
+// load-watcher: a node is penalized as it heats up above a safe temperature, or as its CPUs get
+// throttled, so that dense edge and on-prem deployments throttle less.
+package thermalaware
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	pluginConfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran"
+)
+
+const (
+	// Name : name of plugin
+	Name = "ThermalAware"
+
+	// Temperature is the type of the node temperature metrics reported by load-watcher, in Celsius,
+	// e.g. the hottest CPU package of node_hwmon_temp_celsius or of the IPMI sensors.
+	Temperature = "Temperature"
+	// ThermalThrottle is the type of the node thermal throttling metrics reported by load-watcher, in percent
+	// of the time the CPUs of the node were throttled, e.g. from node_cpu_core_throttles_total.
+	ThermalThrottle = "ThermalThrottle"
+)
+
+// ThermalAware : scheduler plugin
+type ThermalAware struct {
+	handle    framework.Handle
+	collector *trimaran.Collector
+	args      *pluginConfig.ThermalAwareArgs
+	// nodes given a neutral score
+	exclusion *trimaran.NodeExclusion
+}
+
+var _ framework.ScorePlugin = &ThermalAware{}
+
+// New : create an instance of a ThermalAware plugin
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Creating new instance of the ThermalAware plugin")
+	// cast object into plugin arguments object
+	args, ok := obj.(*pluginConfig.ThermalAwareArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type ThermalAwareArgs, got %T", obj)
+	}
+	if args.MaxTemperature <= args.SafeTemperature {
+		return nil, fmt.Errorf("maxTemperature %v must be above safeTemperature %v", args.MaxTemperature, args.SafeTemperature)
+	}
+	collector, err := trimaran.NewCollector(logger, &args.TrimaranSpec)
+	if err != nil {
+		return nil, err
+	}
+	exclusion, err := trimaran.NewNodeExclusion(args.ExcludedNodes)
+	if err != nil {
+		return nil, err
+	}
+	logger.V(4).Info("Using ThermalAwareArgs", "safeTemperature", args.SafeTemperature, "maxTemperature", args.MaxTemperature,
+		"cpuHeavyMilliCores", args.CPUHeavyMilliCores)
+
+	return &ThermalAware{
+		handle:    handle,
+		collector: collector,
+		args:      args,
+		exclusion: exclusion,
+	}, nil
+}
+
+// Score : evaluate score for a node
+func (pl *ThermalAware) Score(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	logger := klog.FromContext(ctx)
+	logger.V(6).Info("Calculating score", "pod", klog.KObj(pod), "nodeName", nodeName)
+	if podCPU := trimaran.GetResourceRequested(pod).MilliCPU; podCPU < pl.args.CPUHeavyMilliCores {
+		logger.V(6).Info("Pod not CPU-heavy; using neutral score", "pod", klog.KObj(pod), "podCPU", podCPU)
+		return trimaran.NeutralScore, nil
+	}
+	nodeInfo, err := pl.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return framework.MinNodeScore, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	if pl.exclusion.Excludes(nodeInfo.Node()) {
+		logger.V(6).Info("Excluded node; using neutral score", "nodeName", nodeName)
+		return trimaran.NeutralScore, nil
+	}
+	metrics, _, _ := pl.collector.GetNodeMetricsOrRequests(logger, nodeInfo)
+	heat, ok := pl.heat(metrics)
+	if !ok {
+		logger.V(6).Info("No thermal metrics for node; using neutral score", "nodeName", nodeName)
+		return trimaran.NeutralScore, nil
+	}
+	score := int64(math.Round(float64(framework.MaxNodeScore) * (1 - heat)))
+	logger.V(6).Info("Calculating thermal score", "pod", klog.KObj(pod), "nodeName", nodeName, "heat", heat, "score", score)
+	return score, nil
+}
+
+// heat : the thermal penalty [0,1] of a node, the highest of its temperature scaled between the safe and the
+// maximum temperatures, and of its fraction of throttled time. It returns false if the node reports neither.
+func (pl *ThermalAware) heat(metrics []watcher.Metric) (float64, bool) {
+	temperature, _, temperatureOK := trimaran.GetResourceData(metrics, Temperature)
+	throttle, _, throttleOK := trimaran.GetResourceData(metrics, ThermalThrottle)
+	if !temperatureOK && !throttleOK {
+		return 0, false
+	}
+	var heat float64
+	if temperatureOK {
+		heat = (temperature - pl.args.SafeTemperature) / (pl.args.MaxTemperature - pl.args.SafeTemperature)
+	}
+	if throttleOK {
+		heat = max(heat, throttle/100)
+	}
+	return max(min(heat, 1), 0), true
+}
+
+// Name : name of plugin
+func (pl *ThermalAware) Name() string {
+	return Name
+}
+
+// ScoreExtensions : an interface for Score extended functionality
+func (pl *ThermalAware) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
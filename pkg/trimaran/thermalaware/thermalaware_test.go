@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package thermalaware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	testClientSet "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	pluginConfig "github.com/amiraBenamer20/scheduler-plugins/apis/config"
+	cfgv1 "github.com/amiraBenamer20/scheduler-plugins/apis/config/v1"
+	"github.com/amiraBenamer20/scheduler-plugins/pkg/trimaran"
+	testutil "github.com/amiraBenamer20/scheduler-plugins/test/util"
+)
+
+func newWatcherServer(t *testing.T, watcherResponse watcher.WatcherMetrics) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		bytes, err := json.Marshal(watcherResponse)
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+}
+
+func newFramework(ctx context.Context, t *testing.T, nodes []*v1.Node) framework.Framework {
+	registeredPlugins := []tf.RegisterPluginFunc{
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	cs := testClientSet.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	fh, err := tf.NewFramework(ctx, registeredPlugins, "default-scheduler", runtime.WithClientSet(cs),
+		runtime.WithInformerFactory(informerFactory), runtime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(nil, nodes)))
+	assert.Nil(t, err)
+	return fh
+}
+
+func TestNew(t *testing.T) {
+	server := newWatcherServer(t, watcher.WatcherMetrics{})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fh := newFramework(ctx, t, nil)
+
+	thermalAwareArgs := pluginConfig.ThermalAwareArgs{
+		TrimaranSpec:       pluginConfig.TrimaranSpec{WatcherAddress: server.URL},
+		SafeTemperature:    cfgv1.DefaultSafeTemperature,
+		MaxTemperature:     cfgv1.DefaultMaxTemperature,
+		CPUHeavyMilliCores: cfgv1.DefaultCPUHeavyMilliCores,
+	}
+	p, err := New(ctx, &thermalAwareArgs, fh)
+	assert.NotNil(t, p)
+	assert.Nil(t, err)
+
+	badArgs := thermalAwareArgs
+	badArgs.MaxTemperature = badArgs.SafeTemperature
+	p, err = New(ctx, &badArgs, fh)
+	assert.Nil(t, p)
+	assert.NotNil(t, err)
+}
+
+func TestScore(t *testing.T) {
+	cpuHeavyPod := st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj()
+	lightPod := st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj()
+	nodeMetrics := func(metrics ...watcher.Metric) watcher.WatcherMetrics {
+		return watcher.WatcherMetrics{
+			Data: watcher.Data{
+				NodeMetricsMap: map[string]watcher.NodeMetrics{"node-1": {Metrics: metrics}},
+			},
+		}
+	}
+
+	tests := []struct {
+		test            string
+		pod             *v1.Pod
+		watcherResponse watcher.WatcherMetrics
+		excludedNodes   *pluginConfig.NodeExclusionSpec
+		expected        int64
+	}{
+		{
+			test: "cool node",
+			pod:  cpuHeavyPod,
+			watcherResponse: nodeMetrics(
+				watcher.Metric{Type: Temperature, Operator: watcher.Average, Value: 45},
+				watcher.Metric{Type: ThermalThrottle, Operator: watcher.Average, Value: 0},
+			),
+			expected: framework.MaxNodeScore,
+		},
+		{
+			test: "warm node",
+			pod:  cpuHeavyPod,
+			watcherResponse: nodeMetrics(
+				watcher.Metric{Type: Temperature, Operator: watcher.Average, Value: 72},
+			),
+			expected: 60,
+		},
+		{
+			test: "node above the max temperature",
+			pod:  cpuHeavyPod,
+			watcherResponse: nodeMetrics(
+				watcher.Metric{Type: Temperature, Operator: watcher.Latest, Value: 95},
+			),
+			expected: framework.MinNodeScore,
+		},
+		{
+			test: "throttled node",
+			pod:  cpuHeavyPod,
+			watcherResponse: nodeMetrics(
+				watcher.Metric{Type: Temperature, Operator: watcher.Average, Value: 66},
+				watcher.Metric{Type: ThermalThrottle, Operator: watcher.Average, Value: 30},
+			),
+			expected: 70,
+		},
+		{
+			test:            "node without thermal metrics",
+			pod:             cpuHeavyPod,
+			watcherResponse: nodeMetrics(watcher.Metric{Type: watcher.CPU, Operator: watcher.Average, Value: 90}),
+			expected:        trimaran.NeutralScore,
+		},
+		{
+			test:            "no metrics",
+			pod:             cpuHeavyPod,
+			watcherResponse: watcher.WatcherMetrics{},
+			expected:        trimaran.NeutralScore,
+		},
+		{
+			test: "pod not CPU-heavy",
+			pod:  lightPod,
+			watcherResponse: nodeMetrics(
+				watcher.Metric{Type: Temperature, Operator: watcher.Average, Value: 95},
+			),
+			expected: trimaran.NeutralScore,
+		},
+		{
+			test: "excluded node",
+			pod:  cpuHeavyPod,
+			watcherResponse: nodeMetrics(
+				watcher.Metric{Type: Temperature, Operator: watcher.Average, Value: 95},
+			),
+			excludedNodes: &pluginConfig.NodeExclusionSpec{
+				NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "edge-gateway"}},
+			},
+			expected: trimaran.NeutralScore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.test, func(t *testing.T) {
+			server := newWatcherServer(t, tt.watcherResponse)
+			defer server.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			node := st.MakeNode().Name("node-1").Label("role", "edge-gateway").
+				Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8"}).Obj()
+			fh := newFramework(ctx, t, []*v1.Node{node})
+			thermalAwareArgs := pluginConfig.ThermalAwareArgs{
+				TrimaranSpec:       pluginConfig.TrimaranSpec{WatcherAddress: server.URL, ExcludedNodes: tt.excludedNodes},
+				SafeTemperature:    cfgv1.DefaultSafeTemperature,
+				MaxTemperature:     cfgv1.DefaultMaxTemperature,
+				CPUHeavyMilliCores: cfgv1.DefaultCPUHeavyMilliCores,
+			}
+			p, err := New(ctx, &thermalAwareArgs, fh)
+			assert.Nil(t, err)
+			scorePlugin := p.(framework.ScorePlugin)
+
+			score, status := scorePlugin.Score(ctx, framework.NewCycleState(), tt.pod, node.Name)
+			assert.True(t, status.IsSuccess())
+			assert.Equal(t, tt.expected, score)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+)
+
+// JSONCodecName is the name of JSONCodec, the content subtype of its messages (application/grpc+json).
+const JSONCodecName = "json"
+
+// JSONCodec is the gRPC codec of the plain Go messages of the gRPC services of the plugins, JSON encoded so that
+// the services can be implemented in any language without generated code. It is not registered for the whole
+// process: servers force it with grpc.ForceServerCodec, and clients on each call with grpc.ForceCodec.
+type JSONCodec struct{}
+
+// Marshal encodes the message in JSON.
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the message from JSON.
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Name returns JSONCodecName.
+func (JSONCodec) Name() string {
+	return JSONCodecName
+}
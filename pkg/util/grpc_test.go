@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/encoding"
+)
+
+var _ encoding.Codec = JSONCodec{}
+
+func TestJSONCodec(t *testing.T) {
+	type message struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels,omitempty"`
+	}
+	in := &message{Name: "n1", Labels: map[string]string{"zone": "z1"}}
+	data, err := JSONCodec{}.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"n1","labels":{"zone":"z1"}}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	out := &message{}
+	if err := (JSONCodec{}).Unmarshal(data, out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("expected %+v after a round trip, got %+v", in, out)
+	}
+	// The codec is forced per server and per call, not registered for the whole process.
+	if codec := encoding.GetCodec(JSONCodecName); codec != nil {
+		t.Errorf("expected no codec registered for %q, got %T", JSONCodecName, codec)
+	}
+}
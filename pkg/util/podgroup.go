@@ -19,10 +19,18 @@ package util
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	listerv1 "k8s.io/client-go/listers/core/v1"
 
 	// "sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 
@@ -33,6 +41,13 @@ import (
 // DefaultWaitTime is 60s if ScheduleTimeoutSeconds is not specified.
 const DefaultWaitTime = 60 * time.Second
 
+// Defaults of the fields of the backoff policy of a PodGroup.
+const (
+	DefaultBackoffInitial    = 10 * time.Second
+	DefaultBackoffMax        = 300 * time.Second
+	DefaultBackoffMultiplier = 2
+)
+
 // CreateMergePatch return patch generated from original and new interfaces
 func CreateMergePatch(original, new interface{}) ([]byte, error) {
 	pvByte, err := json.Marshal(original)
@@ -55,13 +70,96 @@ func GetPodGroupLabel(pod *v1.Pod) string {
 	return pod.Labels[v1alpha1.PodGroupLabel]
 }
 
+// GetPodGroupNamespace get the namespace of the pod group of a pod: the one of its PodGroupNamespaceLabel
+// for the members of a pod group of another namespace, the namespace of the pod otherwise
+func GetPodGroupNamespace(pod *v1.Pod) string {
+	if namespace := pod.Labels[v1alpha1.PodGroupNamespaceLabel]; namespace != "" {
+		return namespace
+	}
+	return pod.Namespace
+}
+
 // GetPodGroupFullName get namespaced group name from pod labels
 func GetPodGroupFullName(pod *v1.Pod) string {
 	pgName := GetPodGroupLabel(pod)
 	if len(pgName) == 0 {
 		return ""
 	}
-	return fmt.Sprintf("%v/%v", pod.Namespace, pgName)
+	return fmt.Sprintf("%v/%v", GetPodGroupNamespace(pod), pgName)
+}
+
+// IsPodGroupMember returns whether the pod is labeled as a member of the pod group and lives in the
+// namespace of the pod group or in one of its spec.memberNamespaces.
+func IsPodGroupMember(pod *v1.Pod, pg *v1alpha1.PodGroup) bool {
+	if GetPodGroupLabel(pod) != pg.Name || GetPodGroupNamespace(pod) != pg.Namespace {
+		return false
+	}
+	return pod.Namespace == pg.Namespace || slices.Contains(pg.Spec.MemberNamespaces, pod.Namespace)
+}
+
+// ListPodGroupPods lists the pods labeled as members of the pod group namespace/name, in the namespace
+// of the pod group and in its member namespaces.
+func ListPodGroupPods(podLister listerv1.PodLister, namespace, name string, memberNamespaces []string) ([]*v1.Pod, error) {
+	selector := labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: name})
+	var pods []*v1.Pod
+	listed := sets.New[string]()
+	for _, ns := range append([]string{namespace}, memberNamespaces...) {
+		if listed.Has(ns) {
+			continue
+		}
+		listed.Insert(ns)
+		nsPods, err := podLister.Pods(ns).List(selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range nsPods {
+			if GetPodGroupNamespace(pod) == namespace {
+				pods = append(pods, pod)
+			}
+		}
+	}
+	return pods, nil
+}
+
+// GetPodGroupMemberKey returns the stable identity of a pod within its pod group: the UID of its controller
+// and its index, so that a pod recreated by its controller, e.g. after a transient failure, is counted as the
+// member it replaces rather than as an additional member. The index is, in this order, the one of the
+// PodGroupMemberIndexLabel, the Job completion index, the StatefulSet pod index, or the ordinal suffix of the
+// name of a StatefulSet pod. The pods without controller or index are identified by their UID.
+func GetPodGroupMemberKey(pod *v1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return string(pod.UID)
+	}
+	index := pod.Labels[v1alpha1.PodGroupMemberIndexLabel]
+	if index == "" {
+		index = pod.Labels[batchv1.JobCompletionIndexAnnotation]
+	}
+	if index == "" {
+		index = pod.Annotations[batchv1.JobCompletionIndexAnnotation]
+	}
+	if index == "" {
+		index = pod.Labels[appsv1.PodIndexLabel]
+	}
+	if index == "" && owner.Kind == "StatefulSet" {
+		if i := strings.LastIndex(pod.Name, "-"); i >= 0 {
+			index = pod.Name[i+1:]
+		}
+	}
+	if index == "" {
+		return string(pod.UID)
+	}
+	return fmt.Sprintf("%v/%v", owner.UID, index)
+}
+
+// CountPodGroupMembers returns the number of distinct members of a pod group among the pods,
+// a member and its replacement being counted once.
+func CountPodGroupMembers(pods []*v1.Pod) int {
+	members := sets.New[string]()
+	for _, pod := range pods {
+		members.Insert(GetPodGroupMemberKey(pod))
+	}
+	return members.Len()
 }
 
 // GetWaitTimeDuration returns a wait timeout based on the following precedences:
@@ -77,3 +175,42 @@ func GetWaitTimeDuration(pg *v1alpha1.PodGroup, scheduleTimeout *time.Duration)
 	}
 	return DefaultWaitTime
 }
+
+// GetBackoffDuration returns the backoff of the given pg after its n-th consecutive failure (n >= 1) based on
+// spec.backoff, and the maximal backoff of the pg. It returns 0 if the pg has no backoff policy.
+func GetBackoffDuration(pg *v1alpha1.PodGroup, n int) (time.Duration, time.Duration) {
+	if pg == nil || pg.Spec.Backoff == nil {
+		return 0, 0
+	}
+	policy := pg.Spec.Backoff
+	backoff, maxBackoff, multiplier := DefaultBackoffInitial, DefaultBackoffMax, int64(DefaultBackoffMultiplier)
+	if policy.InitialSeconds != nil && *policy.InitialSeconds > 0 {
+		backoff = time.Duration(*policy.InitialSeconds) * time.Second
+	}
+	if policy.MaxSeconds != nil && *policy.MaxSeconds > 0 {
+		maxBackoff = time.Duration(*policy.MaxSeconds) * time.Second
+	}
+	if policy.Multiplier != nil && *policy.Multiplier > 0 {
+		multiplier = int64(*policy.Multiplier)
+	}
+	for i := 1; i < n && backoff < maxBackoff; i++ {
+		backoff *= time.Duration(multiplier)
+	}
+	return min(backoff, maxBackoff), maxBackoff
+}
+
+// GetProgressDeadline returns the progress deadline of the given pg, and the number of members
+// to be assigned within it based on spec.progressMinPercentage of spec.minMember (defaults to 100%).
+// It returns 0 if the pg has no progress deadline.
+func GetProgressDeadline(pg *v1alpha1.PodGroup) (time.Duration, int32) {
+	if pg == nil || pg.Spec.ProgressDeadlineSeconds == nil || *pg.Spec.ProgressDeadlineSeconds <= 0 {
+		return 0, 0
+	}
+	percentage := int32(100)
+	if pg.Spec.ProgressMinPercentage != nil && *pg.Spec.ProgressMinPercentage > 0 && *pg.Spec.ProgressMinPercentage < 100 {
+		percentage = *pg.Spec.ProgressMinPercentage
+	}
+	// Round up so that a non-zero percentage always requires at least one member.
+	minAssigned := (pg.Spec.MinMember*percentage + 99) / 100
+	return time.Duration(*pg.Spec.ProgressDeadlineSeconds) * time.Second, minAssigned
+}
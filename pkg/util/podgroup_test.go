@@ -17,9 +17,20 @@ limitations under the License.
 package util
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/utils/ptr"
+
+	"github.com/amiraBenamer20/scheduler-plugins/apis/scheduling/v1alpha1"
 )
 
 func TestCreateMergePatch(t *testing.T) {
@@ -70,3 +81,258 @@ func TestCreateMergePatch(t *testing.T) {
 		}
 	}
 }
+
+func TestGetProgressDeadline(t *testing.T) {
+	tests := []struct {
+		name             string
+		pg               *v1alpha1.PodGroup
+		expectedDeadline time.Duration
+		expectedAssigned int32
+	}{
+		{
+			name: "no pod group",
+		},
+		{
+			name: "no progress deadline",
+			pg:   &v1alpha1.PodGroup{Spec: v1alpha1.PodGroupSpec{MinMember: 4}},
+		},
+		{
+			name: "progress deadline defaults to all members",
+			pg: &v1alpha1.PodGroup{Spec: v1alpha1.PodGroupSpec{
+				MinMember: 4, ProgressDeadlineSeconds: ptr.To[int32](30)}},
+			expectedDeadline: 30 * time.Second,
+			expectedAssigned: 4,
+		},
+		{
+			name: "progress deadline with percentage rounded up",
+			pg: &v1alpha1.PodGroup{Spec: v1alpha1.PodGroupSpec{
+				MinMember: 5, ProgressDeadlineSeconds: ptr.To[int32](30), ProgressMinPercentage: ptr.To[int32](50)}},
+			expectedDeadline: 30 * time.Second,
+			expectedAssigned: 3,
+		},
+		{
+			name: "invalid percentage falls back to all members",
+			pg: &v1alpha1.PodGroup{Spec: v1alpha1.PodGroupSpec{
+				MinMember: 5, ProgressDeadlineSeconds: ptr.To[int32](30), ProgressMinPercentage: ptr.To[int32](150)}},
+			expectedDeadline: 30 * time.Second,
+			expectedAssigned: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deadline, assigned := GetProgressDeadline(tt.pg)
+			if deadline != tt.expectedDeadline || assigned != tt.expectedAssigned {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.expectedDeadline, tt.expectedAssigned, deadline, assigned)
+			}
+		})
+	}
+}
+
+func TestGetBackoffDuration(t *testing.T) {
+	tests := []struct {
+		name            string
+		pg              *v1alpha1.PodGroup
+		n               int
+		expectedBackoff time.Duration
+		expectedMax     time.Duration
+	}{
+		{
+			name: "no backoff policy",
+			pg:   &v1alpha1.PodGroup{Spec: v1alpha1.PodGroupSpec{MinMember: 4}},
+			n:    1,
+		},
+		{
+			name:            "defaults after the first failure",
+			pg:              &v1alpha1.PodGroup{Spec: v1alpha1.PodGroupSpec{Backoff: &v1alpha1.PodGroupBackoffPolicy{}}},
+			n:               1,
+			expectedBackoff: 10 * time.Second,
+			expectedMax:     300 * time.Second,
+		},
+		{
+			name:            "defaults after the third failure",
+			pg:              &v1alpha1.PodGroup{Spec: v1alpha1.PodGroupSpec{Backoff: &v1alpha1.PodGroupBackoffPolicy{}}},
+			n:               3,
+			expectedBackoff: 40 * time.Second,
+			expectedMax:     300 * time.Second,
+		},
+		{
+			name: "backoff capped by its maximum",
+			pg: &v1alpha1.PodGroup{Spec: v1alpha1.PodGroupSpec{Backoff: &v1alpha1.PodGroupBackoffPolicy{
+				InitialSeconds: ptr.To[int32](60), MaxSeconds: ptr.To[int32](600), Multiplier: ptr.To[int32](3)}}},
+			n:               4,
+			expectedBackoff: 600 * time.Second,
+			expectedMax:     600 * time.Second,
+		},
+		{
+			name: "constant backoff",
+			pg: &v1alpha1.PodGroup{Spec: v1alpha1.PodGroupSpec{Backoff: &v1alpha1.PodGroupBackoffPolicy{
+				InitialSeconds: ptr.To[int32](30), Multiplier: ptr.To[int32](1)}}},
+			n:               100,
+			expectedBackoff: 30 * time.Second,
+			expectedMax:     300 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backoff, maxBackoff := GetBackoffDuration(tt.pg, tt.n)
+			if backoff != tt.expectedBackoff || maxBackoff != tt.expectedMax {
+				t.Errorf("expected backoff %v up to %v, got %v up to %v", tt.expectedBackoff, tt.expectedMax, backoff, maxBackoff)
+			}
+		})
+	}
+}
+
+func TestGetPodGroupMemberKey(t *testing.T) {
+	controller := func(kind string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: "owner", UID: "owner-uid", Controller: ptr.To(true)}}
+	}
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want string
+	}{
+		{
+			name: "pod without controller",
+			pod:  &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p-0", UID: "uid"}},
+			want: "uid",
+		},
+		{
+			name: "member index label",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", UID: "uid", OwnerReferences: controller("MPIJob"),
+				Labels: map[string]string{v1alpha1.PodGroupMemberIndexLabel: "3", batchv1.JobCompletionIndexAnnotation: "1"}}},
+			want: "owner-uid/3",
+		},
+		{
+			name: "job completion index annotation",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job-1-abcde", UID: "uid", OwnerReferences: controller("Job"),
+				Annotations: map[string]string{batchv1.JobCompletionIndexAnnotation: "1"}}},
+			want: "owner-uid/1",
+		},
+		{
+			name: "statefulset pod index label",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sts-2", UID: "uid", OwnerReferences: controller("StatefulSet"),
+				Labels: map[string]string{appsv1.PodIndexLabel: "2"}}},
+			want: "owner-uid/2",
+		},
+		{
+			name: "statefulset pod ordinal",
+			pod:  &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sts-2", UID: "uid", OwnerReferences: controller("StatefulSet")}},
+			want: "owner-uid/2",
+		},
+		{
+			name: "replicaset pod without index",
+			pod:  &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "rs-abcde", UID: "uid", OwnerReferences: controller("ReplicaSet")}},
+			want: "uid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetPodGroupMemberKey(tt.pod); got != tt.want {
+				t.Errorf("GetPodGroupMemberKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountPodGroupMembers(t *testing.T) {
+	controller := []metav1.OwnerReference{{Kind: "Job", Name: "job", UID: "job-uid", Controller: ptr.To(true)}}
+	pods := []*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "job-0-old", UID: "a", OwnerReferences: controller,
+			Annotations: map[string]string{batchv1.JobCompletionIndexAnnotation: "0"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "job-0-new", UID: "b", OwnerReferences: controller,
+			Annotations: map[string]string{batchv1.JobCompletionIndexAnnotation: "0"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "job-1", UID: "c", OwnerReferences: controller,
+			Annotations: map[string]string{batchv1.JobCompletionIndexAnnotation: "1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "standalone", UID: "d"}},
+	}
+	if got := CountPodGroupMembers(pods); got != 3 {
+		t.Errorf("CountPodGroupMembers() = %v, want 3", got)
+	}
+}
+
+func TestIsPodGroupMember(t *testing.T) {
+	pg := &v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pipeline", Namespace: "producer"},
+		Spec:       v1alpha1.PodGroupSpec{MinMember: 2, MemberNamespaces: []string{"consumer"}},
+	}
+	pod := func(namespace string, labels map[string]string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: namespace, Labels: labels}}
+	}
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want bool
+	}{
+		{
+			name: "pod of the namespace of the pod group",
+			pod:  pod("producer", map[string]string{v1alpha1.PodGroupLabel: "pipeline"}),
+			want: true,
+		},
+		{
+			name: "pod of a member namespace",
+			pod: pod("consumer", map[string]string{v1alpha1.PodGroupLabel: "pipeline",
+				v1alpha1.PodGroupNamespaceLabel: "producer"}),
+			want: true,
+		},
+		{
+			name: "pod of a member namespace without the namespace label",
+			pod:  pod("consumer", map[string]string{v1alpha1.PodGroupLabel: "pipeline"}),
+			want: false,
+		},
+		{
+			name: "pod of another namespace",
+			pod: pod("other", map[string]string{v1alpha1.PodGroupLabel: "pipeline",
+				v1alpha1.PodGroupNamespaceLabel: "producer"}),
+			want: false,
+		},
+		{
+			name: "pod of another pod group",
+			pod:  pod("producer", map[string]string{v1alpha1.PodGroupLabel: "other"}),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPodGroupMember(tt.pod, pg); got != tt.want {
+				t.Errorf("IsPodGroupMember() = %v, want %v", got, tt.want)
+			}
+			if tt.want && GetPodGroupFullName(tt.pod) != "producer/pipeline" {
+				t.Errorf("GetPodGroupFullName() = %v, want producer/pipeline", GetPodGroupFullName(tt.pod))
+			}
+		})
+	}
+}
+
+func TestListPodGroupPods(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range []*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "producer-0", Namespace: "producer",
+			Labels: map[string]string{v1alpha1.PodGroupLabel: "pipeline"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "consumer-0", Namespace: "consumer",
+			Labels: map[string]string{v1alpha1.PodGroupLabel: "pipeline", v1alpha1.PodGroupNamespaceLabel: "producer"}}},
+		// A member of the pod group named pipeline of the consumer namespace.
+		{ObjectMeta: metav1.ObjectMeta{Name: "consumer-1", Namespace: "consumer",
+			Labels: map[string]string{v1alpha1.PodGroupLabel: "pipeline"}}},
+		// The namespace is not a member namespace of the pod group.
+		{ObjectMeta: metav1.ObjectMeta{Name: "other-0", Namespace: "other",
+			Labels: map[string]string{v1alpha1.PodGroupLabel: "pipeline", v1alpha1.PodGroupNamespaceLabel: "producer"}}},
+	} {
+		if err := indexer.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pods, err := ListPodGroupPods(listerv1.NewPodLister(indexer), "producer", "pipeline", []string{"consumer", "producer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	if want := []string{"producer-0", "consumer-0"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ListPodGroupPods() = %v, want %v", names, want)
+	}
+}
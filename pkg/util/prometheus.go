@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrometheusQueryTimeout is the timeout of the queries of PrometheusClient.
+const PrometheusQueryTimeout = 10 * time.Second
+
+// PrometheusSample is a sample of the vector returned by a Prometheus instant query.
+type PrometheusSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// PrometheusClient runs instant queries returning a vector against the HTTP API of a Prometheus server.
+type PrometheusClient struct {
+	client  *http.Client
+	address string
+}
+
+// prometheusQueryResponse is the response of the Prometheus instant query API to a query returning a vector.
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			// Value is the evaluation timestamp and the sample value, e.g. [1710000000.123, "42.5"].
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// NewPrometheusClient returns a client of the Prometheus server at the address, e.g. http://prometheus:9090.
+func NewPrometheusClient(address string) *PrometheusClient {
+	return &PrometheusClient{
+		client:  &http.Client{Timeout: PrometheusQueryTimeout},
+		address: strings.TrimSuffix(address, "/"),
+	}
+}
+
+// QueryVector runs the instant query and returns the samples of the resulting vector. The samples whose value
+// is not a number, e.g. the NaN returned by histogram_quantile for series without data in the window, are skipped.
+func (c *PrometheusClient) QueryVector(ctx context.Context, query string) ([]PrometheusSample, error) {
+	reqURL := c.address + "/api/v1/query?" + url.Values{"query": []string{query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var qr prometheusQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&qr); err != nil {
+		return nil, fmt.Errorf("decoding the response of %v: %w", c.address, err)
+	}
+	if qr.Status != "success" {
+		return nil, fmt.Errorf("querying %v: %v", c.address, qr.Error)
+	}
+	if qr.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("querying %v: want a vector, got %v", c.address, qr.Data.ResultType)
+	}
+
+	samples := make([]PrometheusSample, 0, len(qr.Data.Result))
+	for _, result := range qr.Data.Result {
+		if len(result.Value) != 2 {
+			continue
+		}
+		s, ok := result.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		samples = append(samples, PrometheusSample{Labels: result.Metric, Value: value})
+	}
+	return samples, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPrometheusClientQueryVector(t *testing.T) {
+	const query = `sum by (zone) (up)`
+	tests := []struct {
+		name     string
+		response string
+		want     []PrometheusSample
+		wantErr  bool
+	}{
+		{
+			name: "vector",
+			response: `{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"zone":"z1"},"value":[1710000000.1,"42.5"]},
+				{"metric":{"zone":"z2"},"value":[1710000000.1,"NaN"]},
+				{"metric":{"zone":"z3"},"value":[1710000000.1,"+Inf"]},
+				{"metric":{"zone":"z4"},"value":[1710000000.1]}]}}`,
+			want: []PrometheusSample{{Labels: map[string]string{"zone": "z1"}, Value: 42.5}},
+		},
+		{
+			name:     "query error",
+			response: `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "not a vector",
+			response: `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid response",
+			response: `not json`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/query" {
+					t.Errorf("unexpected path %v", r.URL.Path)
+				}
+				if got := r.URL.Query().Get("query"); got != query {
+					t.Errorf("unexpected query %v", got)
+				}
+				fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+
+			got, err := NewPrometheusClient(server.URL+"/").QueryVector(context.Background(), query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected the samples %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -50,6 +50,12 @@ func (p *PodGroupWrapper) MinMember(i int32) *PodGroupWrapper {
 	return p
 }
 
+func (p *PodGroupWrapper) ProgressDeadline(seconds, percentage int32) *PodGroupWrapper {
+	p.Spec.ProgressDeadlineSeconds = &seconds
+	p.Spec.ProgressMinPercentage = &percentage
+	return p
+}
+
 func (p *PodGroupWrapper) Time(t time.Time) *PodGroupWrapper {
 	p.CreationTimestamp.Time = t
 	return p